@@ -0,0 +1,183 @@
+package archive
+
+import (
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Extensions lists the archive types that can be selected in place of a CSV or XLSX file.
+var Extensions = []string{".csv.gz", ".zip"}
+
+// Limits on what an archive may unpack to, so a small zip bomb can't fill the disk
+var (
+	maxExtractedBytes int64 = 4 << 30
+	maxEntries              = 10000
+)
+
+// IsArchive reports whether path is a supported archive
+func IsArchive(path string) bool {
+	lower := strings.ToLower(path)
+	for _, ext := range Extensions {
+		if strings.HasSuffix(lower, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// Extract unpacks the CSV and XLSX files in an archive into dir and returns
+// their paths. Directories inside zip archives are flattened, and files that
+// would share a name get a numbered suffix. Archives that unpack to more than
+// 4 GB or hold more than 10,000 entries are refused.
+func Extract(path, dir string) ([]string, error) {
+	lower := strings.ToLower(path)
+
+	switch {
+	case strings.HasSuffix(lower, ".csv.gz"):
+		out, err := extractGzip(path, dir)
+		if err != nil {
+			return nil, err
+		}
+		return []string{out}, nil
+	case strings.HasSuffix(lower, ".zip"):
+		return extractZip(path, dir)
+	default:
+		return nil, fmt.Errorf("unsupported archive type: %s", filepath.Base(path))
+	}
+}
+
+func extractGzip(path, dir string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	defer gz.Close()
+
+	// report.csv.gz -> report.csv
+	name := filepath.Base(path)
+	out := filepath.Join(dir, name[:len(name)-len(".gz")])
+	budget := maxExtractedBytes
+	if err := writeFile(out, gz, &budget); err != nil {
+		return "", fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	return out, nil
+}
+
+func extractZip(path, dir string) ([]string, error) {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	defer zr.Close()
+
+	if len(zr.File) > maxEntries {
+		return nil, fmt.Errorf("%s has %d entries, more than the %d allowed", filepath.Base(path), len(zr.File), maxEntries)
+	}
+
+	var files []string
+	taken := make(map[string]bool)
+	budget := maxExtractedBytes
+
+	for _, entry := range zr.File {
+		if entry.FileInfo().IsDir() || strings.HasPrefix(entry.Name, "__MACOSX/") {
+			continue
+		}
+
+		// Only the base name is used, so entries can't escape dir
+		name := filepath.Base(entry.Name)
+		ext := strings.ToLower(filepath.Ext(name))
+		if strings.HasPrefix(name, ".") || (ext != ".csv" && ext != ".xlsx") {
+			continue
+		}
+
+		// Files with the same name in different folders get a numbered
+		// suffix, skipping any suffixed name another entry already has
+		stem := strings.TrimSuffix(name, filepath.Ext(name))
+		for n := 2; taken[strings.ToLower(name)]; n++ {
+			name = fmt.Sprintf("%s_%d%s", stem, n, filepath.Ext(name))
+		}
+		taken[strings.ToLower(name)] = true
+
+		rc, err := entry.Open()
+		if err != nil {
+			return nil, err
+		}
+		out := filepath.Join(dir, name)
+		err = writeFile(out, rc, &budget)
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+		}
+		files = append(files, out)
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("%s contains no CSV or XLSX files", filepath.Base(path))
+	}
+	return files, nil
+}
+
+// writeFile copies r to path, taking what it writes from budget, and fails
+// once the budget is spent.
+func writeFile(path string, r io.Reader, budget *int64) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	n, err := io.Copy(f, io.LimitReader(r, *budget+1))
+	*budget -= n
+	if err == nil && *budget < 0 {
+		err = fmt.Errorf("unpacks to more than %d MB", maxExtractedBytes>>20)
+	}
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Zip writes files into a new zip archive at path, stored by base name
+func Zip(path string, files []string) error {
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	zw := zip.NewWriter(out)
+	for _, file := range files {
+		if err := addToZip(zw, file); err != nil {
+			return err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+func addToZip(zw *zip.Writer, file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w, err := zw.Create(filepath.Base(file))
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, f)
+	return err
+}
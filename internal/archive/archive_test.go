@@ -0,0 +1,130 @@
+package archive
+
+import (
+	"archive/zip"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExtract_Gzip(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "hours.csv.gz")
+
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gz := gzip.NewWriter(f)
+	gz.Write([]byte("Name,Hours\nAlice,1.5\n"))
+	gz.Close()
+	f.Close()
+
+	outDir := t.TempDir()
+	files, err := Extract(path, outDir)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if len(files) != 1 || files[0] != filepath.Join(outDir, "hours.csv") {
+		t.Fatalf("Unexpected files: %v", files)
+	}
+
+	data, _ := os.ReadFile(files[0])
+	if string(data) != "Name,Hours\nAlice,1.5\n" {
+		t.Errorf("Unexpected contents: %q", data)
+	}
+}
+
+func TestExtract_Zip(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "reports.zip")
+
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	for _, name := range []string{"week1/hours.csv", "week2/hours.csv", "hours_2.csv", "readme.txt", "../escape.csv", "__MACOSX/._hours.csv"} {
+		w, _ := zw.Create(name)
+		w.Write([]byte("Name,Hours\n"))
+	}
+	zw.Close()
+	f.Close()
+
+	outDir := t.TempDir()
+	files, err := Extract(path, outDir)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	expected := []string{"hours.csv", "hours_2.csv", "hours_2_2.csv", "escape.csv"}
+	if len(files) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, files)
+	}
+	for i, name := range expected {
+		if files[i] != filepath.Join(outDir, name) {
+			t.Errorf("File %d: expected %s, got %s", i, name, files[i])
+		}
+	}
+}
+
+func TestExtract_ZipLimits(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "bomb.zip")
+
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	for _, name := range []string{"a.csv", "b.csv", "c.csv"} {
+		w, _ := zw.Create(name)
+		w.Write([]byte("Name,Hours\nAlice,1.5\n"))
+	}
+	zw.Close()
+	f.Close()
+
+	defer func(bytes int64, entries int) {
+		maxExtractedBytes, maxEntries = bytes, entries
+	}(maxExtractedBytes, maxEntries)
+
+	maxExtractedBytes = 50
+	if _, err := Extract(path, t.TempDir()); err == nil {
+		t.Error("Expected an error for an archive over the size limit")
+	}
+
+	maxExtractedBytes = 1 << 20
+	maxEntries = 2
+	if _, err := Extract(path, t.TempDir()); err == nil {
+		t.Error("Expected an error for an archive over the entry limit")
+	}
+
+	maxEntries = 3
+	files, err := Extract(path, t.TempDir())
+	if err != nil || len(files) != 3 {
+		t.Errorf("Expected 3 files within the limits, got %v, %v", files, err)
+	}
+}
+
+func TestZip(t *testing.T) {
+	tmpDir := t.TempDir()
+	file := filepath.Join(tmpDir, "hours_converted.csv")
+	if err := os.WriteFile(file, []byte("Name,Hours\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(tmpDir, "out.zip")
+	if err := Zip(path, []string{file}); err != nil {
+		t.Fatalf("Zip failed: %v", err)
+	}
+
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer zr.Close()
+	if len(zr.File) != 1 || zr.File[0].Name != "hours_converted.csv" {
+		t.Errorf("Unexpected zip entries: %v", zr.File)
+	}
+}
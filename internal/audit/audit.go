@@ -0,0 +1,99 @@
+package audit
+
+import (
+	"encoding/csv"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/nconklindev/chronos/internal/types"
+)
+
+// FileName is the name of the audit log written next to converted files.
+const FileName = "chronos-audit.csv"
+
+// Header is the first row of every audit log.
+var Header = []string{"file", "sheet", "row", "column", "original", "converted", "timestamp"}
+
+// Write writes one row per changed cell in results, stamped with at.
+func Write(w io.Writer, results []*types.ConversionResult, at time.Time) error {
+	cw := csv.NewWriter(w)
+	stamp := at.Format(time.RFC3339)
+
+	for _, res := range results {
+		for _, change := range res.Changes {
+			row := []string{
+				res.InputFile,
+				change.Sheet,
+				strconv.Itoa(change.Row),
+				change.Column,
+				change.Original,
+				change.Converted,
+				stamp,
+			}
+			if err := cw.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// Append adds results to the audit log at path, creating it with a header
+// row if it doesn't exist. Earlier entries are never rewritten, so the log
+// keeps a record of every run.
+func Append(path string, results []*types.ConversionResult, at time.Time) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if info.Size() == 0 {
+		cw := csv.NewWriter(f)
+		cw.Write(Header)
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			return err
+		}
+	}
+
+	if err := Write(f, results, at); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// AppendAll appends to a chronos-audit.csv in every directory that received
+// output, listing only that directory's files. It returns the paths written.
+func AppendAll(results []*types.ConversionResult, at time.Time) ([]string, error) {
+	var dirs []string
+	byDir := make(map[string][]*types.ConversionResult)
+
+	for _, res := range results {
+		dir := filepath.Dir(res.OutputFile)
+		if _, ok := byDir[dir]; !ok {
+			dirs = append(dirs, dir)
+		}
+		byDir[dir] = append(byDir[dir], res)
+	}
+
+	var written []string
+	for _, dir := range dirs {
+		path := filepath.Join(dir, FileName)
+		if err := Append(path, byDir[dir], at); err != nil {
+			return written, err
+		}
+		written = append(written, path)
+	}
+
+	return written, nil
+}
@@ -0,0 +1,43 @@
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nconklindev/chronos/internal/types"
+)
+
+func TestAppendAll(t *testing.T) {
+	dir := t.TempDir()
+	results := []*types.ConversionResult{{
+		InputFile:  filepath.Join(dir, "hours.xlsx"),
+		OutputFile: filepath.Join(dir, "hours_converted.xlsx"),
+		Changes: []types.CellChange{
+			{Sheet: "Sheet1", Row: 2, Col: 1, Column: "Hours", Original: "1.5", Converted: "01:30"},
+		},
+	}}
+	at := time.Date(2026, 3, 2, 9, 30, 0, 0, time.UTC)
+
+	// A second run adds to the log instead of replacing it
+	for i := 0; i < 2; i++ {
+		written, err := AppendAll(results, at)
+		if err != nil {
+			t.Fatalf("AppendAll failed: %v", err)
+		}
+		if len(written) != 1 || written[0] != filepath.Join(dir, FileName) {
+			t.Fatalf("Unexpected paths written: %v", written)
+		}
+	}
+
+	got, err := os.ReadFile(filepath.Join(dir, FileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	row := filepath.Join(dir, "hours.xlsx") + ",Sheet1,2,Hours,1.5,01:30,2026-03-02T09:30:00Z\n"
+	expected := "file,sheet,row,column,original,converted,timestamp\n" + row + row
+	if string(got) != expected {
+		t.Errorf("Unexpected audit log:\n%s", got)
+	}
+}
@@ -0,0 +1,178 @@
+package bugreport
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/nconklindev/chronos/internal/config"
+	"github.com/nconklindev/chronos/internal/converter"
+)
+
+// SampleRows is how many data rows of the offending file are included in a report.
+const SampleRows = 10
+
+// Info describes the failure being reported.
+type Info struct {
+	Version   string
+	Commit    string
+	Date      string
+	Error     string // The error message shown to the user, if any
+	InputFile string // The file being processed when the error happened, if any
+	// Columns are the input's columns being converted as hours, kept as
+	// they are in the sample. Detected columns are used when it's nil.
+	Columns []int
+}
+
+// Write builds a bug report zip in dir and returns its path. The bundle contains
+// version and system info, the debug log and config file when present, and an
+// anonymized sample of the input file.
+func Write(dir string, info Info) (string, error) {
+	name := fmt.Sprintf("chronos-bug-report-%s.zip", time.Now().Format("20060102-150405"))
+	path := filepath.Join(dir, name)
+
+	out, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	defer out.Close()
+
+	zw := zip.NewWriter(out)
+
+	if err := writeEntry(zw, "info.txt", []byte(formatInfo(info))); err != nil {
+		return "", err
+	}
+
+	// Config and log files are optional; skip whichever doesn't exist
+	for _, file := range []string{config.ConfigFile, config.LogFile} {
+		if err := addConfigFile(zw, file); err != nil {
+			return "", err
+		}
+	}
+
+	if info.InputFile != "" {
+		sample, err := anonymizedSample(info.InputFile, info.Columns)
+		if err != nil {
+			sample = []byte(fmt.Sprintf("could not read sample: %v\n", err))
+		}
+		if err := writeEntry(zw, "sample.csv", sample); err != nil {
+			return "", err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
+
+func formatInfo(info Info) string {
+	var s strings.Builder
+
+	s.WriteString(fmt.Sprintf("chronos %s\ncommit: %s\nbuilt: %s\n\n", info.Version, info.Commit, info.Date))
+	s.WriteString(fmt.Sprintf("os: %s/%s\ngo: %s\nterm: %s\n\n", runtime.GOOS, runtime.GOARCH, runtime.Version(), os.Getenv("TERM")))
+
+	if info.InputFile != "" {
+		s.WriteString(fmt.Sprintf("input type: %s\n", strings.ToLower(filepath.Ext(info.InputFile))))
+		if stat, err := os.Stat(info.InputFile); err == nil {
+			s.WriteString(fmt.Sprintf("input size: %d bytes\n", stat.Size()))
+		}
+	}
+	if info.Error != "" {
+		s.WriteString(fmt.Sprintf("error: %s\n", info.Error))
+	}
+
+	return s.String()
+}
+
+func addConfigFile(zw *zip.Writer, name string) error {
+	path, err := config.Path(name)
+	if err != nil {
+		return nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		// A missing file just means the feature isn't in use
+		return nil
+	}
+
+	return writeEntry(zw, name, data)
+}
+
+func writeEntry(zw *zip.Writer, name string, data []byte) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// anonymizedSample returns the headers and first rows of a file as CSV with
+// every value masked but those of the hours columns, which drive detection.
+// Other columns are masked even when they hold numbers, since employee
+// numbers, badge IDs and SSNs do.
+func anonymizedSample(path string, hours []int) ([]byte, error) {
+	data, err := converter.ReadFileData(path)
+	if err != nil {
+		return nil, err
+	}
+	if hours == nil {
+		hours = converter.AutoDetectColumns(data)
+	}
+	keep := make(map[int]bool, len(hours))
+	for _, idx := range hours {
+		keep[idx] = true
+	}
+
+	var s strings.Builder
+	w := csv.NewWriter(&s)
+
+	if err := w.Write(data.Headers); err != nil {
+		return nil, err
+	}
+
+	for i, row := range data.Rows {
+		if i >= SampleRows {
+			break
+		}
+
+		masked := make([]string, len(row))
+		for j, cell := range row {
+			masked[j] = cell
+			if !keep[j] {
+				masked[j] = Anonymize(cell)
+			}
+		}
+		if err := w.Write(masked); err != nil {
+			return nil, err
+		}
+	}
+
+	w.Flush()
+	return []byte(s.String()), w.Error()
+}
+
+// Anonymize masks a cell value while keeping its shape: letters become x/X and
+// digits become 0, so 123-45-6789 becomes 000-00-0000.
+func Anonymize(value string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsUpper(r):
+			return 'X'
+		case unicode.IsLetter(r):
+			return 'x'
+		case unicode.IsDigit(r):
+			return '0'
+		}
+		return r
+	}, value)
+}
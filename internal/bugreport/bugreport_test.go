@@ -0,0 +1,112 @@
+package bugreport
+
+import (
+	"archive/zip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAnonymize(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"Decimal masked", "7.5", "0.0"},
+		{"Integer masked", "40", "00"},
+		{"SSN masked", "123-45-6789", "000-00-0000"},
+		{"Name masked", "Avery Johnson", "Xxxxx Xxxxxxx"},
+		{"ID masked", "E-1001", "X-0000"},
+		{"Date masked", "2025-10-13", "0000-00-00"},
+		{"Empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Anonymize(tt.input)
+			if got != tt.expected {
+				t.Errorf("Anonymize(%q) = %q; want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAnonymizedSample(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "input.csv")
+	content := "Employee ID,SSN,Hours\n48213,123-45-6789,7.5\n48214,987-65-4321,8.25\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	sample, err := anonymizedSample(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, original := range []string{"48213", "48214", "123-45-6789", "987-65-4321"} {
+		if strings.Contains(string(sample), original) {
+			t.Errorf("Expected %q to be masked in %q", original, sample)
+		}
+	}
+	// The detected hours column is kept
+	want := "Employee ID,SSN,Hours\n00000,000-00-0000,7.5\n00000,000-00-0000,8.25\n"
+	if string(sample) != want {
+		t.Errorf("Unexpected sample: %q; want %q", sample, want)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+
+	// A debug log in the config directory should be bundled
+	if err := os.MkdirAll(filepath.Join(configHome, "chronos"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(configHome, "chronos", "chronos.log"), []byte("log line\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tmpDir := t.TempDir()
+	inputFile := filepath.Join(tmpDir, "input.csv")
+	if err := os.WriteFile(inputFile, []byte("Name,Hours\nAlice,1.5\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	path, err := Write(tmpDir, Info{Version: "1.2.3", Error: "boom", InputFile: inputFile})
+	if err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer zr.Close()
+
+	entries := make(map[string]string)
+	for _, file := range zr.File {
+		rc, err := file.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		entries[file.Name] = string(data)
+	}
+
+	if !strings.Contains(entries["info.txt"], "chronos 1.2.3") || !strings.Contains(entries["info.txt"], "error: boom") {
+		t.Errorf("info.txt missing version or error: %q", entries["info.txt"])
+	}
+	if entries["chronos.log"] != "log line\n" {
+		t.Errorf("Expected debug log to be bundled, got %q", entries["chronos.log"])
+	}
+	if _, ok := entries["config.json"]; ok {
+		t.Errorf("Expected missing config file to be skipped")
+	}
+	if entries["sample.csv"] != "Name,Hours\nXxxxx,1.5\n" {
+		t.Errorf("Unexpected sample: %q", entries["sample.csv"])
+	}
+}
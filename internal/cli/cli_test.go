@@ -0,0 +1,748 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nconklindev/chronos/internal/audit"
+	"github.com/nconklindev/chronos/internal/converter"
+	"github.com/nconklindev/chronos/internal/notify"
+	"github.com/nconklindev/chronos/internal/profile"
+	"github.com/nconklindev/chronos/internal/summary"
+	"github.com/nconklindev/chronos/internal/types"
+	"github.com/xuri/excelize/v2"
+)
+
+func run(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+
+	root := NewRootCommand(BuildInfo{Version: "test"})
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&out)
+	root.SetArgs(args)
+	err := root.Execute()
+	return out.String(), err
+}
+
+func TestConvertCommand(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "hours.csv")
+	if err := os.WriteFile(input, []byte("Name,Hours\nAlice,1.5\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := run(t, "convert", input); err != nil {
+		t.Fatalf("convert failed: %v", err)
+	}
+	got, err := os.ReadFile(filepath.Join(dir, "hours_converted.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "Name,Hours\nAlice,01:30\n" {
+		t.Errorf("Unexpected output: %q", got)
+	}
+
+	out, err := run(t, "convert", "--stdout", "-k", "-c", "Hours", input)
+	if err != nil {
+		t.Fatalf("convert --stdout failed: %v", err)
+	}
+	if out != "Name,Hours,Hours (HH:MM)\nAlice,1.5,01:30\n" {
+		t.Errorf("Unexpected stdout: %q", out)
+	}
+
+	slim := filepath.Join(dir, "slim.csv")
+	if err := os.WriteFile(slim, []byte("ID,Name,Hours\n7,Alice,1.5\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	out, err = run(t, "convert", "--stdout", "--keep-columns", "ID", "-c", "Hours", slim)
+	if err != nil {
+		t.Fatalf("convert --keep-columns failed: %v", err)
+	}
+	if out != "ID,Hours\n7,01:30\n" {
+		t.Errorf("Unexpected slimmed stdout: %q", out)
+	}
+	out, err = run(t, "convert", "--stdout", "--order", "Hours,ID", "-c", "Hours", slim)
+	if err != nil {
+		t.Fatalf("convert --order failed: %v", err)
+	}
+	if out != "Hours,ID,Name\n01:30,7,Alice\n" {
+		t.Errorf("Unexpected reordered stdout: %q", out)
+	}
+
+	// Existing converted copies are only replaced with --force
+	if out, err := run(t, "convert", input); err == nil || !strings.Contains(out, "pass --force to overwrite it") {
+		t.Errorf("Expected an existing output to fail without --force, got %v: %q", err, out)
+	}
+	if _, err := run(t, "convert", "--audit", "--force", input); err != nil {
+		t.Fatalf("convert --audit failed: %v", err)
+	}
+	log, err := os.ReadFile(filepath.Join(dir, audit.FileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(log), input+",,2,Hours,1.5,01:30,") {
+		t.Errorf("Unexpected audit log: %q", log)
+	}
+
+	if _, err := run(t, "convert", "--sidecar", "--force", "-c", "Hours", input); err != nil {
+		t.Fatalf("convert --sidecar failed: %v", err)
+	}
+	sidecar, err := os.ReadFile(summary.SidecarPath(filepath.Join(dir, "hours_converted.csv")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(sidecar), `"input_sha256"`) || !strings.Contains(string(sidecar), `"Hours"`) {
+		t.Errorf("Unexpected sidecar: %s", sidecar)
+	}
+	if _, err := run(t, "convert", "--stdout", "--sidecar", input); ExitCode(err) != ExitBadArgument {
+		t.Errorf("Expected --sidecar with --stdout to be a bad argument, got %v", err)
+	}
+	if _, err := run(t, "convert", "--clipboard", "--stdout", input); ExitCode(err) != ExitBadArgument {
+		t.Errorf("Expected --clipboard with --stdout to be a bad argument, got %v", err)
+	}
+	if _, err := run(t, "convert", "--upload", "ftp://example.com/hours.csv", input); ExitCode(err) != ExitBadArgument {
+		t.Errorf("Expected an ftp --upload to be a bad argument, got %v", err)
+	}
+
+	if _, err := run(t, "convert", "--report", "pdf", "--force", input); err != nil {
+		t.Fatalf("convert --report failed: %v", err)
+	}
+	reports, _ := filepath.Glob(filepath.Join(dir, "chronos-report-*.pdf"))
+	if len(reports) != 1 {
+		t.Errorf("Expected a PDF report next to the output, got %v", reports)
+	}
+
+	out, err = run(t, "convert", "--stdout", "--format", "h:mm", "--unit", "minutes", "-c", "Hours", input)
+	if err != nil {
+		t.Fatalf("convert --format failed: %v", err)
+	}
+	if out != "Name,Hours\nAlice,0:02\n" {
+		t.Errorf("Unexpected formatted stdout: %q", out)
+	}
+	if _, err := run(t, "convert", "--format", "hhmm", input); ExitCode(err) != ExitBadArgument {
+		t.Errorf("Expected an unknown format to be a bad argument, got %v", err)
+	}
+
+	out, err = run(t, "convert", "--stdout", "--line-endings", "crlf", "--final-newline", "never", "-c", "Hours", input)
+	if err != nil {
+		t.Fatalf("convert --line-endings failed: %v", err)
+	}
+	if out != "Name,Hours\r\nAlice,01:30" {
+		t.Errorf("Unexpected CRLF stdout: %q", out)
+	}
+	if _, err := run(t, "convert", "--line-endings", "cr", input); ExitCode(err) != ExitBadArgument {
+		t.Errorf("Expected an unknown line ending to be a bad argument, got %v", err)
+	}
+
+	out, err = run(t, "convert", "--stdout", "--quoting", "all", "-c", "Hours", input)
+	if err != nil {
+		t.Fatalf("convert --quoting failed: %v", err)
+	}
+	if out != "\"Name\",\"Hours\"\n\"Alice\",\"01:30\"\n" {
+		t.Errorf("Unexpected quoted stdout: %q", out)
+	}
+
+	// 1,234 is left alone unless --decimal says which separator its comma is
+	grouped := filepath.Join(dir, "grouped.csv")
+	if err := os.WriteFile(grouped, []byte("Name,Hours\nAlice,\"1,234\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	out, err = run(t, "convert", "--stdout", "-c", "Hours", grouped)
+	if err != nil {
+		t.Fatalf("convert grouped failed: %v", err)
+	}
+	if !strings.HasPrefix(out, "Name,Hours\nAlice,\"1,234\"\nwarning: grouped.csv: 1 cell(s) such as 1,250") {
+		t.Errorf("Unexpected ambiguous stdout: %q", out)
+	}
+	out, err = run(t, "convert", "--stdout", "--decimal", "point", "-c", "Hours", grouped)
+	if err != nil {
+		t.Fatalf("convert --decimal failed: %v", err)
+	}
+	if out != "Name,Hours\nAlice,1234:00\n" {
+		t.Errorf("Unexpected grouped stdout: %q", out)
+	}
+	out, err = run(t, "convert", "--stdout", "--decimal", "comma", "-c", "Hours", grouped)
+	if err != nil {
+		t.Fatalf("convert --decimal failed: %v", err)
+	}
+	if out != "Name,Hours\nAlice,01:14\n" {
+		t.Errorf("Unexpected decimal comma stdout: %q", out)
+	}
+	if _, err := run(t, "convert", "--decimal", "dot", grouped); ExitCode(err) != ExitBadArgument {
+		t.Errorf("Expected an unknown decimal separator to be a bad argument, got %v", err)
+	}
+
+	shifts := filepath.Join(dir, "shifts.csv")
+	if err := os.WriteFile(shifts, []byte("Name,Start Time,End Time\nAlice,2024-01-02 08:00,2024-01-02 16:30\nBob,22:00,06:15\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	out, err = run(t, "convert", "--stdout", "--durations", "--keep-original", shifts)
+	if err != nil {
+		t.Fatalf("convert --durations failed: %v", err)
+	}
+	if out != "Name,Start Time,End Time,Duration,Duration (HH:MM)\nAlice,2024-01-02 08:00,2024-01-02 16:30,8.5,08:30\nBob,22:00,06:15,8.25,08:15\n" {
+		t.Errorf("Unexpected durations stdout: %q", out)
+	}
+	if out, err := run(t, "convert", "--durations", input); err == nil || !strings.Contains(out, "no start and end timestamp columns") {
+		t.Errorf("Expected a file without timestamps to fail with --durations, got %v: %q", err, out)
+	}
+
+	// Named columns that look like IDs are converted with a warning
+	badges := filepath.Join(dir, "badges.csv")
+	if err := os.WriteFile(badges, []byte("Name,Badge\nAlice,12000\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	out, err = run(t, "convert", "--stdout", "-c", "Badge", badges)
+	if err != nil {
+		t.Fatalf("convert badges failed: %v", err)
+	}
+	if !strings.Contains(out, `warning: badges.csv: column "Badge" may not be hours: values like "12000" are whole numbers that look like IDs`) {
+		t.Errorf("Expected a suspicious column warning, got %q", out)
+	}
+
+	// Totals go in a file next to the output
+	timesheet := filepath.Join(dir, "timesheet.csv")
+	if err := os.WriteFile(timesheet, []byte("Employee,Date,Hours\nAlice,2024-01-01,7.5\nAlice,2024-01-03,8.25\nBob,2024-01-31,8\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if out, err := run(t, "convert", "--totals", "Employee,Date", "--period", "month", timesheet); err != nil || !strings.Contains(out, "timesheet_converted_totals.csv (totals)") {
+		t.Fatalf("convert --totals failed: %v: %q", err, out)
+	}
+	got, err = os.ReadFile(filepath.Join(dir, "timesheet_converted_totals.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "Employee,Month,Hours,Hours (decimal)\nAlice,2024-01,15:45,15.75\nBob,2024-01,08:00,8.00\n" {
+		t.Errorf("Unexpected totals: %q", got)
+	}
+	if _, err := run(t, "convert", "--totals", "Employee,Date", "--period", "year", timesheet); ExitCode(err) != ExitBadArgument {
+		t.Errorf("Expected an unknown period to be a bad argument, got %v", err)
+	}
+	if out, err := run(t, "convert", "--totals", "Employee", timesheet); err == nil || !strings.Contains(out, "an employee column and a date column") {
+		t.Errorf("Expected --totals with one column to fail, got %v: %q", err, out)
+	}
+
+	blank := filepath.Join(dir, "blank.csv")
+	if err := os.WriteFile(blank, []byte("Name,Hours\nAlice,\nBob,0\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	out, err = run(t, "convert", "--stdout", "--blanks", "zero", "-c", "Hours", blank)
+	if err != nil {
+		t.Fatalf("convert --blanks failed: %v", err)
+	}
+	if out != "Name,Hours\nAlice,00:00\nBob,00:00\n" {
+		t.Errorf("Unexpected zero-filled stdout: %q", out)
+	}
+	out, err = run(t, "convert", "--stdout", "--blanks", "blank", "-c", "Hours", blank)
+	if err != nil {
+		t.Fatalf("convert --blanks failed: %v", err)
+	}
+	if out != "Name,Hours\nAlice,\nBob,\n" {
+		t.Errorf("Unexpected blanked stdout: %q", out)
+	}
+	if _, err := run(t, "convert", "--blanks", "none", blank); ExitCode(err) != ExitBadArgument {
+		t.Errorf("Expected an unknown blank policy to be a bad argument, got %v", err)
+	}
+	out, err = run(t, "convert", "--stdout", "--pattern", "H'h' mm'm'", "-c", "Hours", input)
+	if err != nil {
+		t.Fatalf("convert --pattern failed: %v", err)
+	}
+	if out != "Name,Hours\nAlice,1h 30m\n" {
+		t.Errorf("Unexpected patterned stdout: %q", out)
+	}
+	out, err = run(t, "convert", "--stdout", "--transformer", "minutes_to_hours", "-c", "Hours", input)
+	if err != nil {
+		t.Fatalf("convert --transformer failed: %v", err)
+	}
+	if out != "Name,Hours\nAlice,0.03\n" {
+		t.Errorf("Unexpected transformed stdout: %q", out)
+	}
+	out, err = run(t, "convert", "--stdout", "--expression", "v - 1", "-c", "Hours", input)
+	if err != nil {
+		t.Fatalf("convert --expression failed: %v", err)
+	}
+	if out != "Name,Hours\nAlice,00:30\n" {
+		t.Errorf("Unexpected adjusted stdout: %q", out)
+	}
+	if _, err := run(t, "convert", "--expression", "v +", input); ExitCode(err) != ExitBadArgument {
+		t.Errorf("Expected an invalid expression to be a bad argument, got %v", err)
+	}
+	if _, err := run(t, "convert", "--transformer", "furlongs", input); ExitCode(err) != ExitBadArgument {
+		t.Errorf("Expected an unknown transformer to be a bad argument, got %v", err)
+	}
+	if _, err := run(t, "convert", "--pattern", "hours", input); ExitCode(err) != ExitBadArgument {
+		t.Errorf("Expected an invalid pattern to be a bad argument, got %v", err)
+	}
+	if _, err := run(t, "convert", "--low-memory", "--comment-originals", input); ExitCode(err) != ExitBadArgument {
+		t.Errorf("Expected comments in low-memory mode to be a bad argument, got %v", err)
+	}
+	out, err = run(t, "convert", "--stdout", "--overtime-after", "1", "-c", "Hours", input)
+	if err != nil {
+		t.Fatalf("convert --overtime-after failed: %v", err)
+	}
+	if out != "Name,Regular (HH:MM),Overtime (HH:MM)\nAlice,01:00,00:30\n" {
+		t.Errorf("Unexpected split stdout: %q", out)
+	}
+
+	out, err = run(t, "convert", "--stdout", "--flag-above", "1", "-c", "Hours", input)
+	if err != nil {
+		t.Fatalf("convert --flag-above failed: %v", err)
+	}
+	if !strings.HasPrefix(out, "Name,Hours,Over 1 hours\nAlice,01:30,Hours\n") || !strings.Contains(out, "1 cell(s) over 1 hours were flagged") {
+		t.Errorf("Unexpected flagged stdout: %q", out)
+	}
+
+	withUnits := filepath.Join(dir, "units.csv")
+	if err := os.WriteFile(withUnits, []byte("Name,Hours\nAlice,1.5 hrs\nBob,150%\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	out, err = run(t, "convert", "--stdout", "--lenient", "-c", "Hours", withUnits)
+	if err != nil {
+		t.Fatalf("convert --lenient failed: %v", err)
+	}
+	if out != "Name,Hours\nAlice,01:30\nBob,01:30\n" {
+		t.Errorf("Unexpected lenient stdout: %q", out)
+	}
+
+	if _, err := run(t, "convert", "--encoding", "klingon", input); err == nil {
+		t.Error("Expected an error for an unknown encoding")
+	}
+	if _, err := run(t, "convert", filepath.Join(dir, "missing.csv")); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
+
+func TestConvertCommand_Preset(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "timecard.csv")
+	export := "Co Code,File #,Reg Hours,O/T Hours,Rate\nABC,101,40,2.5,18.5\nABC,102,37.75,0,21\nTotal,,77.75,2.5,\n"
+	if err := os.WriteFile(input, []byte(export), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The hour columns are converted, the totals dropped and the output named for the vendor
+	out, err := run(t, "convert", "--verbose", "--preset", "auto", input)
+	if err != nil {
+		t.Fatalf("convert --preset failed: %v", err)
+	}
+	if !strings.Contains(out, "ADP Workforce Now export recognized") {
+		t.Errorf("Expected the export recognized, got %q", out)
+	}
+	got, err := os.ReadFile(filepath.Join(dir, "timecard_adp_converted.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Co Code,File #,Reg Hours,O/T Hours,Rate\nABC,101,40:00,02:30,18.5\nABC,102,37:45,00:00,21\n"; string(got) != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
+
+func TestProfileCommand(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	dir := t.TempDir()
+	input := filepath.Join(dir, "hours.csv")
+	if err := os.WriteFile(input, []byte("Name,Hours,Badge\nAlice,1.5,2.25\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	data, err := converter.ReadFileData(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := profile.Save(profile.FromFile("weekly", data, map[int]bool{1: true}, types.ConversionOptions{})); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := run(t, "profile", "export", "-o", dir, "weekly"); err != nil {
+		t.Fatalf("profile export failed: %v", err)
+	}
+	shared := filepath.Join(dir, "weekly.chronos-preset.json")
+
+	// A shared file is used without importing it
+	out, err := run(t, "convert", "--stdout", "--profile", shared, input)
+	if err != nil {
+		t.Fatalf("convert --profile with a shared file failed: %v", err)
+	}
+	if out != "Name,Hours,Badge\nAlice,01:30,2.25\n" {
+		t.Errorf("Unexpected stdout: %q", out)
+	}
+
+	// Importing doesn't replace a profile of the same name unless forced
+	if _, err := run(t, "profile", "import", shared); ExitCode(err) != ExitBadArgument {
+		t.Errorf("Expected importing over a saved profile to be a bad argument, got %v", err)
+	}
+	if _, err := run(t, "profile", "import", "--name", "team", shared); err != nil {
+		t.Fatalf("profile import failed: %v", err)
+	}
+	out, err = run(t, "profile", "list")
+	if err != nil || !strings.Contains(out, "team\n") || !strings.Contains(out, "weekly\n") {
+		t.Errorf("Expected both profiles listed, got %q, %v", out, err)
+	}
+}
+
+func TestConvertCommand_InPlace(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "hours.xlsx")
+	f := excelize.NewFile()
+	f.SetSheetRow("Sheet1", "A1", &[]any{"Name", "Hours"})
+	f.SetSheetRow("Sheet1", "A2", &[]any{"Alice", 1.5})
+	if err := f.SaveAs(input); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	if _, err := run(t, "convert", "--in-place", input); ExitCode(err) != ExitBadArgument {
+		t.Errorf("Expected --in-place without --new-sheet to be a bad argument, got %v", err)
+	}
+	if _, err := run(t, "convert", "--new-sheet", "--in-place", input); err != nil {
+		t.Fatalf("convert --in-place failed: %v", err)
+	}
+
+	if _, err := run(t, "convert", "--table", "Weekly Hours", input); ExitCode(err) != ExitBadArgument {
+		t.Errorf("Expected an invalid table name to be a bad argument, got %v", err)
+	}
+	if _, err := run(t, "convert", "--new-sheet", "--in-place", "--table", "Hours", input); err != nil {
+		t.Fatalf("convert --in-place failed: %v", err)
+	}
+
+	out, err := excelize.OpenFile(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer out.Close()
+	if tables, _ := out.GetTables("Sheet1 (converted)"); len(tables) != 1 || tables[0].Name != "Hours" {
+		t.Errorf("Expected a table on the converted sheet, got %+v", tables)
+	}
+	rows, _ := out.GetRows("Sheet1 (converted)")
+	if len(rows) != 2 || rows[1][1] != "01:30" {
+		t.Errorf("Expected a converted sheet in the input, got %v", rows)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "hours_converted.xlsx")); !os.IsNotExist(err) {
+		t.Error("Expected no converted copy")
+	}
+}
+
+func TestVerifyCommand(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "hours.csv")
+	if err := os.WriteFile(input, []byte("Name,Hours\nAlice,1.5\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := run(t, "convert", input); err != nil {
+		t.Fatalf("convert failed: %v", err)
+	}
+
+	out, err := run(t, "verify", input)
+	if err != nil {
+		t.Fatalf("verify failed: %v", err)
+	}
+	if !strings.Contains(out, "1 converted cell(s) in Hours checked") {
+		t.Errorf("Unexpected output: %q", out)
+	}
+
+	tampered := filepath.Join(dir, "tampered.csv")
+	if err := os.WriteFile(tampered, []byte("Name,Hours\nAlice,01:29\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	out, err = run(t, "verify", input, tampered)
+	if ExitCode(err) != ExitMismatch || !strings.Contains(out, "row 2, Hours: 1.5 should be 01:30") {
+		t.Errorf("Expected a mismatch, got %v: %q", err, out)
+	}
+}
+
+func TestPlainMode(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("LANG", "")
+	dir := t.TempDir()
+	input := filepath.Join(dir, "hours.csv")
+	if err := os.WriteFile(input, []byte("Name,Hours,Overtime\nAlice,1.5,abc\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The file, a column that isn't there, the detected columns, yes to
+	// converting and no to another file
+	root := NewRootCommand(BuildInfo{Version: "test"})
+	var out bytes.Buffer
+	root.SetIn(strings.NewReader(input + "\n9\n\n\nn\n"))
+	root.SetOut(&out)
+	root.SetErr(&out)
+	root.SetArgs([]string{"--plain"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("plain mode failed: %v", err)
+	}
+
+	for _, want := range []string{
+		"Column 2: Hours, detected as decimal hours",
+		"Column 3: Overtime\n",
+		"Error: there is no column 9",
+		"Columns to convert, as numbers or names separated by commas [2]:",
+		"Saved as " + filepath.Join(dir, "hours_converted.csv"),
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected %q in the output:\n%s", want, out.String())
+		}
+	}
+	got, err := os.ReadFile(filepath.Join(dir, "hours_converted.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(got), "Alice,01:30,abc") {
+		t.Errorf("Unexpected output file: %q", got)
+	}
+
+	// A file opened with chronos skips asking for it: the detected columns,
+	// yes to converting, yes to replacing the last copy and no to another file
+	root = NewRootCommand(BuildInfo{Version: "test"})
+	out.Reset()
+	root.SetIn(strings.NewReader("\n\ny\nn\n"))
+	root.SetOut(&out)
+	root.SetErr(&out)
+	root.SetArgs([]string{"--plain", input})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("plain mode with a file failed: %v", err)
+	}
+	if strings.Contains(out.String(), "File to convert") {
+		t.Errorf("Expected the file not to be asked for:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "Saved as ") {
+		t.Errorf("Expected the file to be converted:\n%s", out.String())
+	}
+}
+
+func TestUnreadableSettings(t *testing.T) {
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	path := filepath.Join(configHome, "chronos", "config.json")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	broken := []byte(`{"theme": "dracula",}`)
+	if err := os.WriteFile(path, broken, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	root := NewRootCommand(BuildInfo{Version: "test"})
+	var out bytes.Buffer
+	root.SetIn(strings.NewReader(""))
+	root.SetOut(&out)
+	root.SetErr(&out)
+	root.SetArgs([]string{"--plain"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("chronos with unreadable settings failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "warning: reading config.json") {
+		t.Errorf("Expected the settings error to be reported:\n%s", out.String())
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, broken) {
+		t.Errorf("Expected the settings to be left alone, got %q", got)
+	}
+}
+
+func TestHeadless(t *testing.T) {
+	defer func(original func(uintptr) bool) { isTerminal = original }(isTerminal)
+	isTerminal = func(uintptr) bool { return false }
+
+	out, err := run(t)
+	if err != nil {
+		t.Fatalf("chronos without a terminal failed: %v", err)
+	}
+	for _, want := range []string{"chronos convert", "Usage:"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in the output:\n%s", want, out)
+		}
+	}
+
+	// A link is converted into the working directory, as chronos convert does
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("Name,Hours\nAlice,1.5\n"))
+	}))
+	defer server.Close()
+	dir := t.TempDir()
+	t.Chdir(dir)
+	if _, err := run(t, server.URL+"/hours.csv"); err != nil {
+		t.Fatalf("converting a link without a terminal failed: %v", err)
+	}
+	got, err := os.ReadFile(filepath.Join(dir, "hours_converted.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(got), "Alice,01:30") {
+		t.Errorf("Unexpected output file: %q", got)
+	}
+}
+
+func TestExitCodes(t *testing.T) {
+	dir := t.TempDir()
+	good := filepath.Join(dir, "hours.csv")
+	bad := filepath.Join(dir, "names.csv")
+	if err := os.WriteFile(good, []byte("Name,Hours\nAlice,1.5\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(bad, []byte("Name,Team\nAlice,Ops\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected int
+	}{
+		{"success", []string{"convert", good}, ExitOK},
+		{"partial", []string{"convert", "--force", good, bad}, ExitPartial},
+		{"failure", []string{"convert", bad}, ExitFailure},
+		{"missing file", []string{"convert", filepath.Join(dir, "missing.csv")}, ExitFailure},
+		{"no files", []string{"convert"}, ExitBadArgument},
+		{"unknown flag", []string{"convert", "--nope", good}, ExitBadArgument},
+		{"unknown encoding", []string{"convert", "--encoding", "klingon", good}, ExitBadArgument},
+		{"unknown command", []string{"nope"}, ExitBadArgument},
+		{"stdout with two files", []string{"convert", "--stdout", good, good}, ExitBadArgument},
+		{"parquet with a table", []string{"convert", "--parquet", "--table", "Hours", good}, ExitBadArgument},
+		{"unknown report format", []string{"convert", "--report", "docx", good}, ExitBadArgument},
+		{"unknown markup", []string{"convert", "--markup", "rtf", good}, ExitBadArgument},
+		{"markup with parquet", []string{"convert", "--markup", "html", "--parquet", good}, ExitBadArgument},
+		{"parquet durations without parquet", []string{"convert", "--parquet-durations", good}, ExitBadArgument},
+		{"unknown preset", []string{"convert", "--preset", "paychex", good}, ExitBadArgument},
+		{"update a development build", []string{"update"}, ExitFailure},
+		{"update with arguments", []string{"update", good}, ExitBadArgument},
+		{"preset with columns", []string{"convert", "--preset", "auto", "-c", "Hours", good}, ExitBadArgument},
+		{"preset not matching", []string{"convert", "--preset", "adp", good}, ExitFailure},
+		{"email without server", []string{"watch", "--email-to", "payroll@example.com", dir}, ExitBadArgument},
+		{"email without sender", []string{"watch", "--smtp", "mail.example.com:587", "--email-to", "payroll@example.com", dir}, ExitBadArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := run(t, tt.args...)
+			if got := ExitCode(err); got != tt.expected {
+				t.Errorf("ExitCode = %d, want %d (err: %v)", got, tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestQuietAndVerbose(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "hours.csv")
+	if err := os.WriteFile(input, []byte("Name,Hours\nAlice,1.5\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := run(t, "convert", "--quiet", input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "" {
+		t.Errorf("Expected no output with --quiet, got %q", out)
+	}
+
+	out, err = run(t, "convert", "-q", filepath.Join(dir, "missing.csv"))
+	if err == nil || !strings.Contains(out, "missing.csv") {
+		t.Errorf("Expected errors to be shown with --quiet, got %q", out)
+	}
+
+	out, err = run(t, "convert", "--verbose", "--force", input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "footer rows") || !strings.Contains(out, `column "Hours"`) {
+		t.Errorf("Expected file details with --verbose, got %q", out)
+	}
+
+	if _, err := run(t, "convert", "--quiet", "--verbose", input); ExitCode(err) != ExitBadArgument {
+		t.Errorf("Expected --quiet with --verbose to be a bad argument, got %v", err)
+	}
+}
+
+func TestParseEncoding(t *testing.T) {
+	for name, expected := range map[string]string{
+		"utf-8-bom":    "UTF-8 BOM",
+		"UTF16LE":      "UTF-16LE",
+		"windows_1252": "Windows-1252",
+	} {
+		got, err := parseEncoding(name)
+		if err != nil || got != expected {
+			t.Errorf("parseEncoding(%q) = %q, %v; want %q", name, got, err, expected)
+		}
+	}
+}
+
+func TestWatcherScan(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "hours.csv")
+	output := filepath.Join(dir, "hours_converted.csv")
+	if err := os.WriteFile(input, []byte("Name,Hours\nAlice,1.5\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	fc, err := (&conversionFlags{}).converter(&printer{out: &out, err: &out})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := newWatcher(dir, fc)
+
+	// The first sighting waits for the file to stop changing
+	w.scan()
+	if _, err := os.Stat(output); err == nil {
+		t.Fatal("Expected no conversion on the first scan")
+	}
+
+	w.scan()
+	if _, err := os.Stat(output); err != nil {
+		t.Fatalf("Expected a conversion on the second scan: %s", out.String())
+	}
+
+	// Unchanged files and our own outputs aren't converted again
+	out.Reset()
+	w.scan()
+	w.scan()
+	if strings.Contains(out.String(), "→") {
+		t.Errorf("Expected nothing converted, got %q", out.String())
+	}
+}
+
+func TestWatcherNotify(t *testing.T) {
+	var posted []notify.Batch
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var b notify.Batch
+		if err := json.NewDecoder(r.Body).Decode(&b); err != nil {
+			t.Error(err)
+		}
+		posted = append(posted, b)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "hours.csv"), []byte("Name,Hours\nAlice,1.5\nBob,2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	fc, err := (&conversionFlags{}).converter(&printer{out: &out, err: &out})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := newWatcher(dir, fc)
+	w.notifier = &notifier{webhook: server.URL, client: server.Client()}
+
+	// Only scans that convert something send a report
+	for range 4 {
+		w.scan()
+	}
+	if len(posted) != 1 {
+		t.Fatalf("Expected one report, got %d: %s", len(posted), out.String())
+	}
+	if b := posted[0]; b.Report.Totals.Files != 1 || b.Report.Totals.Rows != 2 || !strings.Contains(b.Text, "1 file(s), 2 rows") {
+		t.Errorf("Unexpected report %+v", b)
+	}
+}
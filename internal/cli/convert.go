@@ -0,0 +1,891 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/nconklindev/chronos/internal/archive"
+	"github.com/nconklindev/chronos/internal/audit"
+	"github.com/nconklindev/chronos/internal/converter"
+	"github.com/nconklindev/chronos/internal/paths"
+	"github.com/nconklindev/chronos/internal/profile"
+	"github.com/nconklindev/chronos/internal/summary"
+	"github.com/nconklindev/chronos/internal/types"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// conversionFlags are the flags shared by every command that converts files.
+type conversionFlags struct {
+	columns       string
+	keepOriginal  bool
+	dropFooter    bool
+	encoding      string
+	lineEnding    string
+	finalNewline  string
+	quoting       string
+	outputDir     string
+	profile       string
+	preset        string
+	audit         bool
+	sidecar       bool
+	headerSuffix  string
+	placement     string
+	blanks        string
+	onlySelected  bool
+	keepColumns   string
+	order         string
+	format        string
+	pattern       string
+	transformer   string
+	expression    string
+	rounding      string
+	unit          string
+	decimal       string
+	lenient       bool
+	overtimeAfter float64
+	newSheet      bool
+	comment       bool
+	lowMemory     bool
+	parquet       bool
+	parquetSecs   bool
+	csvOutput     bool
+	markup        string
+	durations     bool
+	totals        string
+	period        string
+	flagAbove     float64
+	table         string
+	tableStyle    string
+	lossThreshold float64
+	force         bool // Overwrite converted copies that already exist
+}
+
+func (f *conversionFlags) register(flags *pflag.FlagSet, cmd *cobra.Command) {
+	flags.StringVarP(&f.columns, "columns", "c", "", "comma-separated header names or 0-based indices to convert (default: auto-detect)")
+	flags.BoolVarP(&f.keepOriginal, "keep-original", "k", false, "add converted columns next to the originals")
+	flags.BoolVar(&f.dropFooter, "drop-footer", false, "leave detected totals rows out of the output")
+	flags.StringVar(&f.encoding, "encoding", "", "CSV output encoding (default: same as input)")
+	flags.StringVar(&f.lineEnding, "line-endings", "", "how CSV output lines end: lf or crlf (default: same as input)")
+	flags.StringVar(&f.finalNewline, "final-newline", "", "whether CSV output ends with a line ending: always or never (default: same as input)")
+	flags.StringVar(&f.quoting, "quoting", "", "which CSV output fields are quoted: minimal (only those that must be), all, or preserve (those quoted in the input) (default minimal)")
+	flags.StringVarP(&f.outputDir, "output-dir", "o", "", "directory for converted files (default: next to each input)")
+	flags.StringVar(&f.profile, "profile", "", "enforce a saved profile, or a shared .chronos-preset.json file or link, and use its columns and options")
+	flags.StringVar(&f.preset, "preset", "", "apply a vendor's built-in preset: auto recognizes each file's vendor from its headers, or adp, kronos, ukg or workday")
+	flags.StringVar(&f.headerSuffix, "header-suffix", "", `added to the headers of converted columns kept alongside the originals (default " (HH:MM)")`)
+	flags.StringVar(&f.placement, "placement", "", "where converted columns kept alongside the originals go: adjacent, end or grouped (default adjacent)")
+	flags.BoolVar(&f.onlySelected, "only-selected", false, "write only the converted columns and those named by --keep-columns")
+	flags.StringVar(&f.keepColumns, "keep-columns", "", "comma-separated header names or 0-based indices of unconverted columns to keep with --only-selected, such as employee IDs")
+	flags.StringVar(&f.order, "order", "", "comma-separated header names or 0-based indices of columns to write first, in that order; the rest follow in file order")
+	flags.StringVar(&f.format, "format", "", "converted value format: hh:mm, h:mm, hh:mm:ss or minutes (default hh:mm)")
+	flags.StringVar(&f.pattern, "pattern", "", `custom format pattern replacing --format, such as "H'h' mm'm'": h hours, m minutes, s seconds, doubled for two digits, text in single quotes`)
+	flags.StringVar(&f.transformer, "transformer", "", "how converted cells are transformed: duration, minutes_to_hours or cents_to_dollars (default duration, using --format, --rounding and --unit)")
+	flags.StringVar(&f.expression, "expression", "", `expression adjusting each value before it's converted, such as "if v > 12 then v - 0.5 else v", where v is the value`)
+	flags.StringVar(&f.rounding, "rounding", "", "how converted values are rounded: nearest, down or up, or to payroll increments with quarter (7-minute rule) or tenth (default nearest)")
+	flags.StringVar(&f.unit, "unit", "", "what the converted columns count: hours, minutes, seconds, days, weeks or excel_time, a fraction of a day (default hours; XLSX columns formatted as times are read as excel_time)")
+	flags.StringVar(&f.decimal, "decimal", "", "how the converted columns' numbers are written: point (1,234.5), comma (1.234,5) or auto, reading both but leaving 1,234 as is (default auto)")
+	flags.BoolVar(&f.lenient, "lenient", false, "read converted values written with a unit or percent sign, such as 7.5 hrs, 7.5h or 750%, as 7.5 hours; such values aren't detected, so name their columns with --columns")
+	flags.Float64Var(&f.overtimeAfter, "overtime-after", 0, "split converted columns into regular and overtime columns at this many hours, such as 8 or 40")
+	flags.BoolVar(&f.newSheet, "new-sheet", false, `write XLSX conversions to a new "<Sheet> (converted)" sheet, keeping the original sheet`)
+	flags.BoolVar(&f.comment, "comment-originals", false, "note the original value of each XLSX cell converted in place in a comment")
+	flags.BoolVar(&f.lowMemory, "low-memory", false, "stream XLSX files rather than loading them, keeping only number formats, for workbooks too large for memory; not with --new-sheet or --comment-originals")
+	flags.BoolVar(&f.parquet, "parquet", false, "write a .parquet file for data warehouses, Athena and Spark, with converted columns as text and originals kept with --keep-original as doubles; not with --new-sheet, --comment-originals, --table or --totals")
+	flags.BoolVar(&f.parquetSecs, "parquet-durations", false, "write converted columns of --parquet output as whole seconds rather than text")
+	flags.BoolVar(&f.csvOutput, "csv", false, "write JSON and NDJSON files as CSV rather than in their own format")
+	flags.StringVar(&f.markup, "markup", "", "write the converted data as a markdown or html table to paste into wikis, emails and pull requests, rather than a data file")
+	flags.BoolVar(&f.durations, "durations", false, `add a column of hours computed from each pair of start and end timestamp columns, such as "Start Time" and "End Time", for files without one`)
+	flags.StringVar(&f.totals, "totals", "", `employee and date columns, comma-separated header names or 0-based indices, to total converted hours by, such as "Employee,Date": XLSX output gets a Totals sheet and CSV output a _totals file`)
+	flags.StringVar(&f.period, "period", "", "how long each --totals total runs: week (from Monday), day or month (default week)")
+	flags.StringVar(&f.table, "table", "", "wrap XLSX output in an Excel table with this name, for Power Query and pivot tables")
+	flags.StringVar(&f.tableStyle, "table-style", "", "built-in style of the --table table, such as TableStyleLight9 (default TableStyleMedium2)")
+	flags.StringVar(&f.blanks, "blanks", "", "what converted columns hold for empty cells and zeros: keep, zero (empty cells written as 00:00) or blank (zeros left empty) (default keep)")
+	flags.Float64Var(&f.flagAbove, "flag-above", 0, "flag converted cells of more than this many hours, such as 16: XLSX cells are filled and CSV files get a column naming them")
+	flags.Float64Var(&f.lossThreshold, "loss-threshold", 0, "warn about cells that rounding moves by more than this many minutes (default 0.5)")
+	flags.BoolVar(&f.audit, "audit", false, "append every changed cell to a chronos-audit.csv next to the outputs")
+	flags.BoolVar(&f.sidecar, "sidecar", false, "write a <output>.chronos.json next to each output recording the input's checksum, the columns converted, the settings, the version and the time")
+
+	cmd.RegisterFlagCompletionFunc("transformer", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return converter.TransformerNames(), cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.RegisterFlagCompletionFunc("decimal", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"auto", "point", "comma"}, cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.RegisterFlagCompletionFunc("encoding", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return converter.OutputEncodings, cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.RegisterFlagCompletionFunc("line-endings", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"input", string(types.LineEndingLF), string(types.LineEndingCRLF)}, cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.RegisterFlagCompletionFunc("final-newline", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"input", string(types.FinalNewlineAlways), string(types.FinalNewlineNever)}, cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.RegisterFlagCompletionFunc("quoting", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"minimal", string(types.QuotingAll), string(types.QuotingPreserve)}, cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.RegisterFlagCompletionFunc("placement", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		var names []string
+		for _, p := range types.Placements {
+			names = append(names, string(p))
+		}
+		return names, cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.RegisterFlagCompletionFunc("period", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"week", string(types.PeriodDay), string(types.PeriodMonth)}, cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.RegisterFlagCompletionFunc("blanks", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"keep", string(types.BlankAsZero), string(types.ZeroAsBlank)}, cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.RegisterFlagCompletionFunc("markup", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{string(types.MarkupMarkdown), string(types.MarkupHTML)}, cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.RegisterFlagCompletionFunc("profile", completeProfiles)
+	cmd.RegisterFlagCompletionFunc("preset", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return append([]string{profile.PresetAuto}, profile.PresetNames()...), cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.RegisterFlagCompletionFunc("output-dir", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return nil, cobra.ShellCompDirectiveFilterDirs
+	})
+}
+
+// converter builds a fileConverter from the flags, loading the profile if
+// one is named. Invalid flag values are reported as bad arguments.
+func (f *conversionFlags) converter(p *printer) (*fileConverter, error) {
+	c := &fileConverter{flags: *f, printer: p}
+	c.flags.outputDir = paths.Normalize(f.outputDir)
+
+	if f.encoding != "" {
+		enc, err := parseEncoding(f.encoding)
+		if err != nil {
+			return nil, badArgument(err)
+		}
+		c.encoding = enc
+	}
+
+	lineEnding, err := types.ParseLineEnding(strings.ToLower(f.lineEnding))
+	if err != nil {
+		return nil, badArgument(err)
+	}
+	c.lineEnding = lineEnding
+
+	finalNewline, err := types.ParseFinalNewline(strings.ToLower(f.finalNewline))
+	if err != nil {
+		return nil, badArgument(err)
+	}
+	c.finalNewline = finalNewline
+
+	quoting, err := types.ParseQuoting(strings.ToLower(f.quoting))
+	if err != nil {
+		return nil, badArgument(err)
+	}
+	c.quoting = quoting
+
+	placement, err := types.ParsePlacement(f.placement)
+	if err != nil {
+		return nil, badArgument(err)
+	}
+	c.placement = placement
+
+	blanks, err := types.ParseBlankPolicy(f.blanks)
+	if err != nil {
+		return nil, badArgument(err)
+	}
+	c.blanks = blanks
+
+	markup, err := types.ParseMarkup(strings.ToLower(f.markup))
+	if err != nil {
+		return nil, badArgument(err)
+	}
+	if markup != types.MarkupNone && (f.parquet || f.csvOutput) {
+		return nil, badArgument(fmt.Errorf("--markup can't be used with --parquet or --csv, which write other formats"))
+	}
+	c.markup = markup
+
+	period, err := types.ParsePeriod(strings.ToLower(f.period))
+	if err != nil {
+		return nil, badArgument(err)
+	}
+	if f.period != "" && f.totals == "" {
+		return nil, badArgument(fmt.Errorf("--period works with --totals"))
+	}
+	c.period = period
+
+	settings, err := parseColumnSettings(f.format, f.rounding, f.unit, f.decimal)
+	if err != nil {
+		return nil, badArgument(err)
+	}
+	if f.transformer != "" {
+		if err := converter.CheckTransformer(f.transformer); err != nil {
+			return nil, badArgument(err)
+		}
+		settings.Transformer = f.transformer
+	}
+	if f.expression != "" {
+		if err := converter.CheckExpression(f.expression); err != nil {
+			return nil, badArgument(err)
+		}
+		settings.Expression = f.expression
+	}
+	if f.pattern != "" {
+		if err := converter.CheckPattern(f.pattern); err != nil {
+			return nil, badArgument(err)
+		}
+		settings.Pattern = f.pattern
+	}
+	if f.overtimeAfter < 0 {
+		return nil, badArgument(fmt.Errorf("--overtime-after must not be negative"))
+	}
+	settings.OvertimeAfter = f.overtimeAfter
+	settings.Lenient = f.lenient
+	c.settings = settings
+
+	if f.lossThreshold < 0 {
+		return nil, badArgument(fmt.Errorf("--loss-threshold must not be negative"))
+	}
+	if f.flagAbove < 0 {
+		return nil, badArgument(fmt.Errorf("--flag-above must not be negative"))
+	}
+	if f.lowMemory && (f.newSheet || f.comment) {
+		return nil, badArgument(fmt.Errorf("--low-memory can't be used with --new-sheet or --comment-originals, which need the whole workbook"))
+	}
+	if (f.parquet || f.markup != "") && (f.newSheet || f.comment || f.table != "" || f.totals != "") {
+		return nil, badArgument(fmt.Errorf("--parquet and --markup can't be used with --new-sheet, --comment-originals, --table or --totals, which need XLSX or CSV output"))
+	}
+	if f.parquetSecs && !f.parquet {
+		return nil, badArgument(fmt.Errorf("--parquet-durations needs --parquet"))
+	}
+	if f.table != "" {
+		if err := converter.CheckTableName(f.table); err != nil {
+			return nil, badArgument(err)
+		}
+	}
+	if f.tableStyle != "" {
+		if err := converter.CheckTableStyle(f.tableStyle); err != nil {
+			return nil, badArgument(err)
+		}
+	}
+
+	if f.profile != "" {
+		prof, err := profile.Open(f.profile)
+		if err != nil {
+			return nil, badArgument(err)
+		}
+		c.profile = prof
+	}
+	if f.preset != "" {
+		if f.profile != "" || f.columns != "" {
+			return nil, badArgument(fmt.Errorf("--preset can't be used with --profile or --columns, which choose the columns themselves"))
+		}
+		if !strings.EqualFold(f.preset, profile.PresetAuto) {
+			preset, err := profile.FindPreset(f.preset)
+			if err != nil {
+				return nil, badArgument(err)
+			}
+			c.preset = preset
+		}
+	}
+
+	return c, nil
+}
+
+// parseColumnSettings checks the --format, --rounding, --unit and --decimal flags.
+func parseColumnSettings(format, rounding, unit, decimal string) (types.ColumnSettings, error) {
+	var s types.ColumnSettings
+	switch strings.ToLower(format) {
+	case "", "hh:mm":
+	case "h:mm", "hh:mm:ss", "minutes":
+		s.Format = types.Format(strings.ToLower(format))
+	default:
+		return s, fmt.Errorf("unknown format %q (choose hh:mm, h:mm, hh:mm:ss or minutes)", format)
+	}
+	switch strings.ToLower(rounding) {
+	case "", "nearest":
+	case "down", "up", "quarter", "tenth":
+		s.Rounding = types.Rounding(strings.ToLower(rounding))
+	default:
+		return s, fmt.Errorf("unknown rounding %q (choose nearest, down, up, quarter or tenth)", rounding)
+	}
+	switch strings.ToLower(unit) {
+	case "", "hours":
+	case "minutes", "seconds", "days", "weeks", "excel_time":
+		s.Unit = types.Unit(strings.ToLower(unit))
+	default:
+		return s, fmt.Errorf("unknown unit %q (choose hours, minutes, seconds, days, weeks or excel_time)", unit)
+	}
+	switch strings.ToLower(decimal) {
+	case "", "auto":
+	case "point", "comma":
+		s.Decimal = types.Decimal(strings.ToLower(decimal))
+	default:
+		return s, fmt.Errorf("unknown decimal separator %q (choose auto, point or comma)", decimal)
+	}
+	return s, nil
+}
+
+// withSettings returns a copy of columns where the settings flags given
+// override those of every converted column, such as a profile's.
+func withSettings(columns map[int]types.ColumnSettings, converted []int, flags types.ColumnSettings) map[int]types.ColumnSettings {
+	merged := make(map[int]types.ColumnSettings, len(columns))
+	for idx, s := range columns {
+		merged[idx] = s
+	}
+	for _, idx := range converted {
+		s := merged[idx]
+		if flags.Format != "" || flags.Pattern != "" {
+			// Either flag replaces both, since a pattern overrides the format
+			s.Format = flags.Format
+			s.Pattern = flags.Pattern
+		}
+		if flags.Transformer != "" {
+			s.Transformer = flags.Transformer
+		}
+		if flags.Expression != "" {
+			s.Expression = flags.Expression
+		}
+		if flags.Rounding != "" {
+			s.Rounding = flags.Rounding
+		}
+		if flags.Unit != "" {
+			s.Unit = flags.Unit
+		}
+		if flags.Decimal != "" {
+			s.Decimal = flags.Decimal
+		}
+		if flags.Lenient {
+			s.Lenient = true
+		}
+		if flags.OvertimeAfter > 0 {
+			s.OvertimeAfter = flags.OvertimeAfter
+		}
+		merged[idx] = s
+	}
+	return merged
+}
+
+// parseEncoding matches an encoding name loosely, so "utf-8-bom" finds "UTF-8 BOM"
+func parseEncoding(name string) (string, error) {
+	squash := func(s string) string {
+		return strings.NewReplacer(" ", "", "-", "", "_", "").Replace(strings.ToLower(s))
+	}
+	for _, enc := range converter.OutputEncodings {
+		if squash(enc) == squash(name) {
+			return enc, nil
+		}
+	}
+	return "", fmt.Errorf("unknown encoding %q (choose from %s)", name, strings.Join(converter.OutputEncodings, ", "))
+}
+
+// fileConverter converts files on disk with the settings from conversionFlags.
+type fileConverter struct {
+	flags        conversionFlags
+	encoding     string
+	lineEnding   types.LineEnding
+	finalNewline types.FinalNewline
+	quoting      types.Quoting
+	placement    types.Placement
+	blanks       types.BlankPolicy
+	markup       types.Markup
+	period       types.Period
+	settings     types.ColumnSettings // From --format, --rounding, --unit and --decimal
+	profile      *profile.Profile
+	preset       *profile.Preset // Named by --preset; nil with --preset auto, which matches each file
+	printer      *printer
+	version      string // Recorded in sidecars
+}
+
+// convertFile converts one CSV or XLSX file and reports what it did. The
+// output goes to sink when it's set, otherwise to the output directory.
+func (c *fileConverter) convertFile(in input, sink converter.OutputSink) (*types.ConversionResult, types.ConversionOptions, error) {
+	path := in.path
+	read := converter.ReadFileData
+	if c.flags.lowMemory {
+		read = converter.ReadFileDataLowMemory
+	}
+	data, err := read(path)
+	if err != nil {
+		return nil, types.ConversionOptions{}, err
+	}
+
+	// Durations are computed columns, chosen and converted like the rest
+	var durations []types.DurationPair
+	switch {
+	case c.profile != nil:
+		durations = c.profile.Options.Durations
+	case c.flags.durations:
+		if durations = converter.DetectDurations(data); len(durations) == 0 {
+			return nil, types.ConversionOptions{}, fmt.Errorf("no start and end timestamp columns detected")
+		}
+	}
+	data = converter.WithDurations(data, durations)
+
+	opts := types.ConversionOptions{
+		KeepOriginal: c.flags.keepOriginal,
+		FooterRows:   data.FooterRows,
+		DropFooter:   c.flags.dropFooter,
+	}
+
+	name := filepath.Base(path)
+	rows := len(data.Rows)
+	if data.RowCount > 0 {
+		rows = data.RowCount
+	}
+	if data.Encoding != "" {
+		c.printer.Detailf("%s: %s, %d rows, %d footer rows", name, data.Encoding, rows, data.FooterRows)
+	} else {
+		c.printer.Detailf("%s: %d rows, %d footer rows", name, rows, data.FooterRows)
+	}
+
+	var columns []int
+	preset := c.preset
+	if preset == nil && c.flags.preset != "" {
+		if preset = profile.MatchPreset(data); preset != nil {
+			c.printer.Detailf("%s: %s export recognized", name, preset.Vendor)
+		} else {
+			c.printer.Detailf("%s: no vendor preset recognized", name)
+		}
+	}
+	if preset != nil {
+		if err := preset.Check(data); err != nil {
+			return nil, opts, err
+		}
+		var selected map[int]bool
+		selected, opts = preset.Apply(data)
+		for idx := range selected {
+			columns = append(columns, idx)
+		}
+	} else if c.profile != nil {
+		if err := c.profile.Validate(data); err != nil {
+			return nil, opts, err
+		}
+		var selected map[int]bool
+		selected, opts = c.profile.Apply(data)
+		for idx := range selected {
+			columns = append(columns, idx)
+		}
+	} else {
+		columns, err = converter.ResolveColumns(c.flags.columns, data.Headers)
+		if err != nil {
+			return nil, opts, err
+		}
+		if len(columns) == 0 {
+			columns = converter.WithoutTimestamps(converter.AutoDetectColumns(data), durations)
+			for _, trace := range converter.TraceDetection(data) {
+				c.printer.Detailf("%s: column %q: %s", name, trace.Header, trace.Reason)
+			}
+		} else {
+			c.warnSuspectColumns(name, data, columns)
+		}
+	}
+	if len(columns) == 0 {
+		return nil, opts, fmt.Errorf("no decimal hour columns detected; choose them with --columns")
+	}
+
+	if c.encoding != "" {
+		opts.OutputEncoding = c.encoding
+	}
+	if c.flags.lineEnding != "" {
+		opts.LineEnding = c.lineEnding
+	}
+	if c.flags.finalNewline != "" {
+		opts.FinalNewline = c.finalNewline
+	}
+	if c.flags.quoting != "" {
+		opts.Quoting = c.quoting
+	}
+	if c.flags.headerSuffix != "" {
+		opts.HeaderSuffix = c.flags.headerSuffix
+	}
+	if c.flags.placement != "" {
+		opts.Placement = c.placement
+	}
+	if c.flags.blanks != "" {
+		opts.Blanks = c.blanks
+	}
+	if c.flags.newSheet {
+		opts.NewSheet = true
+	}
+	if c.flags.comment {
+		opts.CommentOriginals = true
+	}
+	if c.flags.lowMemory {
+		opts.LowMemory = true
+	}
+	if c.flags.parquet {
+		opts.Parquet = true
+		opts.ParquetDurations = c.flags.parquetSecs
+	}
+	if c.flags.csvOutput {
+		opts.CSVOutput = true
+	}
+	if c.markup != types.MarkupNone {
+		opts.Markup = c.markup
+	}
+	opts.Durations = durations
+	if c.flags.totals != "" {
+		totals, err := converter.ResolveColumns(c.flags.totals, data.Headers)
+		if err != nil {
+			return nil, opts, err
+		}
+		if len(totals) != 2 {
+			return nil, opts, fmt.Errorf("--totals takes an employee column and a date column, got %q", c.flags.totals)
+		}
+		opts.Totals = &types.Totals{Employee: totals[0], Date: totals[1], Period: c.period}
+	}
+	if c.flags.table != "" {
+		opts.Table = c.flags.table
+	}
+	if c.flags.tableStyle != "" {
+		opts.TableStyle = c.flags.tableStyle
+	}
+	if c.flags.flagAbove > 0 {
+		opts.FlagAbove = c.flags.flagAbove
+	}
+	if c.flags.lossThreshold > 0 {
+		opts.LossThreshold = c.flags.lossThreshold
+	}
+	if c.flags.onlySelected || c.flags.keepColumns != "" {
+		keep, err := converter.ResolveColumns(c.flags.keepColumns, data.Headers)
+		if err != nil {
+			return nil, opts, err
+		}
+		opts.OnlySelected = true
+		opts.KeepColumns = keep
+	}
+	if c.settings != (types.ColumnSettings{}) {
+		opts.Columns = withSettings(opts.Columns, columns, c.settings)
+	}
+	if c.flags.order != "" {
+		order, err := converter.ResolveColumns(c.flags.order, data.Headers)
+		if err != nil {
+			return nil, opts, err
+		}
+		opts.Order = order
+	}
+
+	if sink == nil {
+		output := c.outputPath(in, preset)
+		for _, path := range []string{output, converter.TotalsOutput(output, opts)} {
+			if _, err := os.Stat(path); path != "" && err == nil && !c.flags.force {
+				return nil, opts, fmt.Errorf("%s already exists; pass --force to overwrite it", path)
+			}
+		}
+		sink = converter.LocalFile(output)
+	}
+
+	// Only audit logs list every cell changed
+	opts.RecordChanges = c.flags.audit
+	result, err := converter.Convert(path, sink, columns, opts, nil)
+	return result, opts, err
+}
+
+// warnSuspectColumns warns about columns named with --columns that
+// detection didn't pick and whose values look like IDs, money or dates.
+// They're still converted, since they were asked for.
+func (c *fileConverter) warnSuspectColumns(name string, data *types.FileData, columns []int) {
+	detected := make(map[int]bool)
+	for _, idx := range converter.AutoDetectColumns(data) {
+		detected[idx] = true
+	}
+	for _, idx := range columns {
+		if detected[idx] {
+			continue
+		}
+		if reason := converter.SuspectColumn(data, idx); reason != "" {
+			c.printer.Warnf("%s: column %q may not be hours: %s", name, data.Headers[idx], reason)
+		}
+	}
+}
+
+// writeSidecars writes a sidecar describing each result next to its
+// output when --sidecar is set
+func (c *fileConverter) writeSidecars(results []*types.ConversionResult, options []types.ConversionOptions) error {
+	if !c.flags.sidecar || len(results) == 0 {
+		return nil
+	}
+	paths, err := summary.WriteSidecars(summary.NewRun(c.version, results, options))
+	if err != nil {
+		return fmt.Errorf("could not write sidecar: %w", err)
+	}
+	for _, path := range paths {
+		c.printer.Detailf("sidecar: %s", path)
+	}
+	return nil
+}
+
+// writeAudit appends results to the audit logs when --audit is set
+func (c *fileConverter) writeAudit(results []*types.ConversionResult) error {
+	if !c.flags.audit || len(results) == 0 {
+		return nil
+	}
+	paths, err := audit.AppendAll(results, time.Now())
+	if err != nil {
+		return fmt.Errorf("could not write audit log: %w", err)
+	}
+	for _, path := range paths {
+		c.printer.Detailf("audit log: %s", path)
+	}
+	return nil
+}
+
+// outputPath returns where the converted copy of an input is written,
+// named by its preset if it has one
+func (c *fileConverter) outputPath(in input, preset *profile.Preset) string {
+	dir := c.flags.outputDir
+	if dir == "" {
+		dir = in.outputDir()
+	}
+	name := strings.TrimSuffix(filepath.Base(in.path), filepath.Ext(in.path)) + "_converted"
+	if preset != nil {
+		name = preset.OutputName(in.path)
+	}
+	ext := converter.OutputExt(in.path, types.ConversionOptions{Parquet: c.flags.parquet, CSVOutput: c.flags.csvOutput, Markup: c.markup})
+	return filepath.Join(dir, name+ext)
+}
+
+// input is a local file to convert and where it originally came from.
+type input struct {
+	path   string
+	origin string // The archive or URL the file was unpacked or downloaded from, if any
+}
+
+// outputDir is the default directory for the input's converted copy: next
+// to the file or its archive, or the working directory for downloads.
+func (in input) outputDir() string {
+	switch {
+	case in.origin == "":
+		return filepath.Dir(in.path)
+	case converter.IsURL(in.origin):
+		return "."
+	default:
+		return filepath.Dir(in.origin)
+	}
+}
+
+// uploadSink returns where --upload sends the converted file: a PUT to an
+// http(s) URL, or a file on an SFTP server.
+func uploadSink(link string) (converter.OutputSink, error) {
+	switch {
+	case converter.IsURL(link):
+		return converter.HTTPUpload{URL: link}, nil
+	case converter.IsSFTPURL(link):
+		return converter.SFTPUpload{URL: link}, nil
+	}
+	return nil, fmt.Errorf("--upload takes an http(s) or sftp:// URL, not %q", link)
+}
+
+func newConvertCommand() *cobra.Command {
+	var flags conversionFlags
+	var toStdout, toClipboard, writeSummary, inPlace bool
+	var reportFormat, upload string
+
+	cmd := &cobra.Command{
+		Use:   "convert <file|url>...",
+		Short: "Convert files without the interactive interface",
+		Long: `Convert CSV, XLSX, JSON and NDJSON files, .csv.gz and .zip archives of CSV
+and XLSX files, or download links, without the interactive interface. Decimal hour columns are detected
+automatically unless --columns or --profile is given.`,
+		Example: `  chronos convert timesheet.csv
+  chronos convert -c Regular,Overtime -k exports/*.xlsx
+  chronos convert --profile weekly -o converted/ reports.zip`,
+		Args: checkArgs(cobra.MinimumNArgs(1)),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return []string{"csv", "xlsx", "json", "ndjson", "gz", "zip"}, cobra.ShellCompDirectiveFilterFileExt
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p := newPrinter(cmd)
+			fc, err := flags.converter(p)
+			if err != nil {
+				return err
+			}
+			fc.version = cmd.Root().Version
+
+			inputs, cleanup, err := expandInputs(args)
+			defer cleanup()
+			if err != nil {
+				return err
+			}
+			if toStdout && len(inputs) > 1 {
+				return badArgument(fmt.Errorf("--stdout works with a single file"))
+			}
+			if toStdout && flags.sidecar {
+				return badArgument(fmt.Errorf("--sidecar goes next to output files, not --stdout"))
+			}
+			if toClipboard && (toStdout || len(inputs) > 1) {
+				return badArgument(fmt.Errorf("--clipboard works with a single file and not --stdout"))
+			}
+			if toClipboard && flags.sidecar {
+				return badArgument(fmt.Errorf("--sidecar goes next to output files, not --clipboard"))
+			}
+			var uploadTo converter.OutputSink
+			if upload != "" {
+				if toStdout || toClipboard || inPlace || len(inputs) > 1 {
+					return badArgument(fmt.Errorf("--upload works with a single file and not --stdout, --clipboard or --in-place"))
+				}
+				if flags.sidecar {
+					return badArgument(fmt.Errorf("--sidecar goes next to output files, not --upload"))
+				}
+				if uploadTo, err = uploadSink(upload); err != nil {
+					return badArgument(err)
+				}
+			}
+			var report summary.ReportFormat
+			if reportFormat != "" {
+				if report, err = summary.ParseReportFormat(strings.ToLower(reportFormat)); err != nil {
+					return badArgument(err)
+				}
+			}
+			if inPlace && (toStdout || toClipboard || !flags.newSheet) {
+				return badArgument(fmt.Errorf("--in-place works with --new-sheet and not --stdout or --clipboard, so the original sheet is kept"))
+			}
+
+			var results []*types.ConversionResult
+			var options []types.ConversionOptions
+			var failed int
+
+			for _, in := range inputs {
+				var sink converter.OutputSink
+				name := filepath.Base(in.path)
+				switch {
+				case toStdout:
+					sink = converter.WriterSink{W: cmd.OutOrStdout(), Label: "stdout"}
+				case toClipboard:
+					sink = converter.Clipboard{}
+				case uploadTo != nil:
+					sink = uploadTo
+				case inPlace:
+					if in.origin != "" || !strings.EqualFold(filepath.Ext(in.path), ".xlsx") {
+						failed++
+						p.Errorf("%s: only local XLSX files can be converted in place", name)
+						continue
+					}
+					sink = converter.ReplaceFile(in.path)
+				}
+
+				result, opts, err := fc.convertFile(in, sink)
+				if err != nil {
+					failed++
+					p.Errorf("%s: %v", name, err)
+					continue
+				}
+
+				results = append(results, result)
+				options = append(options, opts)
+				if !toStdout {
+					p.Infof("%s → %s (%d rows, %s)", name, result.OutputFile, result.RowsProcessed, strings.Join(result.ColumnsFound, ", "))
+				}
+				if result.TotalsFile != "" {
+					p.Infof("%s → %s (totals)", name, result.TotalsFile)
+				}
+				for _, warning := range result.Warnings {
+					p.Warnf("%s: %s", name, warning)
+				}
+			}
+
+			if err := fc.writeAudit(results); err != nil {
+				return err
+			}
+			if err := fc.writeSidecars(results, options); err != nil {
+				return err
+			}
+			if writeSummary && len(results) > 0 {
+				if _, err := summary.WriteAll(summary.NewRun(cmd.Root().Version, results, options)); err != nil {
+					return fmt.Errorf("could not write run summary: %w", err)
+				}
+			}
+			if report != "" && len(results) > 0 && !toStdout && !toClipboard && uploadTo == nil {
+				path := summary.ReportPath(filepath.Dir(results[0].OutputFile), report, time.Now())
+				if err := summary.WriteReport(path, summary.NewReport(summary.NewRun(cmd.Root().Version, results, options)), report); err != nil {
+					return fmt.Errorf("could not write report: %w", err)
+				}
+				p.Detailf("report: %s", path)
+			}
+
+			switch {
+			case failed == len(inputs):
+				return &ExitError{Code: ExitFailure, Err: fmt.Errorf("no files were converted")}
+			case failed > 0:
+				return &ExitError{Code: ExitPartial, Err: fmt.Errorf("%d of %d files failed to convert", failed, len(inputs))}
+			}
+			return nil
+		},
+	}
+
+	flags.register(cmd.Flags(), cmd)
+	cmd.Flags().BoolVar(&toStdout, "stdout", false, "write the converted file to stdout")
+	cmd.Flags().BoolVar(&toClipboard, "clipboard", false, "put the converted file on the clipboard, ready to paste into a spreadsheet; text output only, such as CSV")
+	cmd.Flags().StringVar(&upload, "upload", "", "upload the converted file instead of writing a copy: PUT it to an http(s) URL, such as an S3 presigned URL, or write it to an sftp:// URL")
+	cmd.Flags().BoolVar(&writeSummary, "summary", false, "write a chronos-run.json summary next to the outputs")
+	cmd.Flags().StringVar(&reportFormat, "report", "", "write a report of the batch next to the first output: csv, json, md or pdf, a printable summary with hours by column")
+	cmd.Flags().BoolVar(&flags.force, "force", false, "overwrite converted copies that already exist instead of failing")
+	cmd.Flags().BoolVar(&inPlace, "in-place", false, "with --new-sheet, add the converted sheet to the input workbook instead of a copy")
+	cmd.RegisterFlagCompletionFunc("report", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		var names []string
+		for _, f := range summary.ReportFormats {
+			names = append(names, string(f))
+		}
+		return names, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return cmd
+}
+
+// expandInputs downloads URLs and unpacks archives into temporary
+// directories, returning local CSV and XLSX files. cleanup removes the
+// temporary files and is safe to call even when err is set.
+func expandInputs(args []string) ([]input, func(), error) {
+	var tempDirs []string
+	cleanup := func() {
+		for _, dir := range tempDirs {
+			os.RemoveAll(dir)
+		}
+	}
+
+	tempDir := func() (string, error) {
+		dir, err := os.MkdirTemp("", "chronos-")
+		if err == nil {
+			tempDirs = append(tempDirs, dir)
+		}
+		return dir, err
+	}
+
+	var inputs []input
+	for _, arg := range args {
+		path, origin := paths.Normalize(arg), ""
+		if converter.IsURL(arg) {
+			origin = arg
+			dir, err := tempDir()
+			if err != nil {
+				return nil, cleanup, err
+			}
+			path, err = converter.HTTPDownload{URL: arg, Dir: dir}.Fetch()
+			if err != nil {
+				return nil, cleanup, err
+			}
+		}
+
+		if !archive.IsArchive(path) {
+			inputs = append(inputs, input{path: path, origin: origin})
+			continue
+		}
+		if origin == "" {
+			origin = path
+		}
+
+		dir, err := tempDir()
+		if err != nil {
+			return nil, cleanup, err
+		}
+		files, err := archive.Extract(path, dir)
+		if err != nil {
+			return nil, cleanup, err
+		}
+		for _, file := range files {
+			inputs = append(inputs, input{path: file, origin: origin})
+		}
+	}
+
+	return inputs, cleanup, nil
+}
@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+)
+
+// Exit codes for scripts and CI.
+const (
+	ExitOK          = 0
+	ExitPartial     = 1 // Some files failed to convert
+	ExitFailure     = 2 // Nothing was converted, or the command failed
+	ExitBadArgument = 3 // Unknown command or flag, wrong arguments, or invalid flag value
+	ExitMismatch    = 4 // verify found converted cells that don't match their originals
+)
+
+// ExitError carries the exit code for an error returned by a command.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// badArgument marks an error as the caller's mistake
+func badArgument(err error) error {
+	return &ExitError{Code: ExitBadArgument, Err: err}
+}
+
+// ExitCode maps an error from Execute to the process exit code.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+	return ExitFailure
+}
+
+// checkArgs marks argument validation failures as bad arguments
+func checkArgs(validate cobra.PositionalArgs) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if err := validate(cmd, args); err != nil {
+			return badArgument(err)
+		}
+		return nil
+	}
+}
+
+// Verbosity levels set by --quiet and --verbose.
+const (
+	levelQuiet = iota - 1
+	levelNormal
+	levelVerbose
+)
+
+// printer writes command output at the verbosity chosen on the command line.
+// Errors are always shown; --quiet hides progress and warnings, and
+// --verbose adds details about each file.
+type printer struct {
+	level int
+	out   io.Writer
+	err   io.Writer
+}
+
+func newPrinter(cmd *cobra.Command) *printer {
+	level := levelNormal
+	if quiet, _ := cmd.Flags().GetBool("quiet"); quiet {
+		level = levelQuiet
+	}
+	if verbose, _ := cmd.Flags().GetBool("verbose"); verbose {
+		level = levelVerbose
+	}
+	return &printer{level: level, out: cmd.OutOrStdout(), err: cmd.ErrOrStderr()}
+}
+
+// Infof reports normal progress.
+func (p *printer) Infof(format string, args ...any) {
+	if p.level >= levelNormal {
+		fmt.Fprintf(p.out, format+"\n", args...)
+	}
+}
+
+// Detailf reports extra detail for --verbose.
+func (p *printer) Detailf(format string, args ...any) {
+	if p.level >= levelVerbose {
+		fmt.Fprintf(p.out, format+"\n", args...)
+	}
+}
+
+// Warnf reports a problem that didn't stop the command.
+func (p *printer) Warnf(format string, args ...any) {
+	if p.level >= levelNormal {
+		fmt.Fprintf(p.err, "warning: "+format+"\n", args...)
+	}
+}
+
+// Errorf reports a failure. It's shown even with --quiet.
+func (p *printer) Errorf(format string, args ...any) {
+	fmt.Fprintf(p.err, format+"\n", args...)
+}
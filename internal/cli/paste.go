@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nconklindev/chronos/internal/converter"
+	"github.com/nconklindev/chronos/internal/types"
+
+	"github.com/atotto/clipboard"
+	"github.com/spf13/cobra"
+)
+
+func newPasteCommand() *cobra.Command {
+	var keepOriginal bool
+
+	cmd := &cobra.Command{
+		Use:   "paste",
+		Short: "Convert cells copied from a spreadsheet, in place on the clipboard",
+		Long: `Read tab- or comma-separated cells from the clipboard, as copied from Excel,
+convert the decimal hour columns, and put the result back on the clipboard
+ready to paste. The copied range doesn't need to include the header row.`,
+		Args: checkArgs(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			text, err := clipboard.ReadAll()
+			if err != nil {
+				return fmt.Errorf("could not read the clipboard: %w", err)
+			}
+
+			out, result, err := converter.ConvertTable(text, types.ConversionOptions{KeepOriginal: keepOriginal})
+			if err != nil {
+				return err
+			}
+			if err := clipboard.WriteAll(out); err != nil {
+				return fmt.Errorf("could not write the clipboard: %w", err)
+			}
+
+			p := newPrinter(cmd)
+			p.Infof("Converted %d rows in %s; the result is on the clipboard", result.RowsProcessed, strings.Join(result.ColumnsFound, ", "))
+			for _, warning := range result.Warnings {
+				p.Warnf("%s", warning)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&keepOriginal, "keep-original", "k", false, "add converted columns next to the originals")
+
+	return cmd
+}
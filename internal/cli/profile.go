@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/nconklindev/chronos/internal/converter"
+	"github.com/nconklindev/chronos/internal/paths"
+	"github.com/nconklindev/chronos/internal/profile"
+
+	"github.com/spf13/cobra"
+)
+
+func newProfileCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "profile",
+		Short: "List, share and import saved profiles",
+		Long: `Manage the profiles saved with p on the column screen. Export one to a
+.chronos-preset.json file to share a team's standard columns and options,
+and import files or links shared with you.
+
+A shared file or link can also be given straight to --profile, to use it
+without saving it.`,
+		Example: `  chronos profile export weekly
+  chronos profile import weekly.chronos-preset.json
+  chronos profile import https://intranet.example.com/payroll/weekly.chronos-preset.json
+  chronos convert --profile weekly.chronos-preset.json timesheet.csv`,
+		Args: checkArgs(cobra.NoArgs),
+	}
+
+	cmd.AddCommand(newProfileListCommand(), newProfileExportCommand(), newProfileImportCommand())
+	return cmd
+}
+
+func newProfileListCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List saved profiles",
+		Args:  checkArgs(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			names, err := profile.List()
+			if err != nil {
+				return err
+			}
+			for _, name := range names {
+				fmt.Fprintln(cmd.OutOrStdout(), name)
+			}
+			return nil
+		},
+	}
+}
+
+func newProfileExportCommand() *cobra.Command {
+	var outputDir string
+
+	cmd := &cobra.Command{
+		Use:   "export <name>",
+		Short: "Write a saved profile to a .chronos-preset.json file to share",
+		Args:  checkArgs(cobra.ExactArgs(1)),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) > 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			return completeProfiles(cmd, args, toComplete)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, err := profile.Load(args[0])
+			if err != nil {
+				return badArgument(err)
+			}
+			file, err := profile.ExportFile(*p, paths.Normalize(outputDir))
+			if err != nil {
+				return fmt.Errorf("could not export profile: %w", err)
+			}
+			newPrinter(cmd).Infof("Exported profile %s to %s", p.Name, file)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&outputDir, "output-dir", "o", ".", "directory to write the file to")
+	cmd.RegisterFlagCompletionFunc("output-dir", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return nil, cobra.ShellCompDirectiveFilterDirs
+	})
+	return cmd
+}
+
+func newProfileImportCommand() *cobra.Command {
+	var name string
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "import <file|url>",
+		Short: "Save a shared .chronos-preset.json file or link as a profile",
+		Long: `Save a profile shared as a .chronos-preset.json file or link, under its own
+name or the one given with --name. An existing profile of that name is only
+replaced with --force.`,
+		Args: checkArgs(cobra.ExactArgs(1)),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return []string{"json"}, cobra.ShellCompDirectiveFilterFileExt
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var p *profile.Profile
+			var err error
+			if converter.IsURL(args[0]) {
+				p, err = profile.ImportURL(nil, args[0])
+			} else {
+				p, err = profile.ImportFile(paths.Normalize(args[0]))
+			}
+			if err != nil {
+				return fmt.Errorf("%s: %w", filepath.Base(args[0]), err)
+			}
+
+			if name != "" {
+				p.Name = name
+			}
+			if _, err := profile.Load(p.Name); err == nil && !force {
+				return badArgument(fmt.Errorf("a profile named %q already exists; pass --force to replace it or --name to save it under another name", p.Name))
+			}
+			if err := profile.Save(*p); err != nil {
+				return badArgument(err)
+			}
+			newPrinter(cmd).Infof("Imported profile %s; use it with --profile %s", p.Name, p.Name)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&name, "name", "", "save the profile under this name instead of its own")
+	cmd.Flags().BoolVar(&force, "force", false, "replace a saved profile of the same name")
+	return cmd
+}
@@ -0,0 +1,32 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/nconklindev/chronos/internal/bugreport"
+
+	"github.com/spf13/cobra"
+)
+
+func newReportBugCommand(build BuildInfo) *cobra.Command {
+	return &cobra.Command{
+		Use:   "report-bug [file]",
+		Short: "Save a bug report bundle to attach to an issue",
+		Long: `Save a zip with version details, your settings, the debug log, and an
+anonymized sample of the file that caused the problem, if one is given.`,
+		Args: checkArgs(cobra.MaximumNArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			info := bugreport.Info{Version: build.Version, Commit: build.Commit, Date: build.Date}
+			if len(args) == 1 {
+				info.InputFile = args[0]
+			}
+
+			path, err := bugreport.Write(".", info)
+			if err != nil {
+				return fmt.Errorf("could not write bug report: %w", err)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Bug report saved to %s\nAttach it to an issue at https://github.com/nconklindev/chronos/issues\n", path)
+			return nil
+		},
+	}
+}
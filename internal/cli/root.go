@@ -0,0 +1,221 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/nconklindev/chronos/internal/config"
+	"github.com/nconklindev/chronos/internal/converter"
+	"github.com/nconklindev/chronos/internal/crash"
+	"github.com/nconklindev/chronos/internal/debuglog"
+	"github.com/nconklindev/chronos/internal/demo"
+	"github.com/nconklindev/chronos/internal/paths"
+	"github.com/nconklindev/chronos/internal/profile"
+	"github.com/nconklindev/chronos/internal/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/term"
+	"github.com/spf13/cobra"
+)
+
+// BuildInfo identifies the binary, set from ldflags in main.
+type BuildInfo struct {
+	Version string
+	Commit  string
+	Date    string
+}
+
+// NewRootCommand builds the chronos command tree. Without a subcommand
+// chronos opens the interactive converter.
+func NewRootCommand(build BuildInfo) *cobra.Command {
+	var demoMode bool
+	var plain bool
+	var profileName string
+
+	root := &cobra.Command{
+		Use:   "chronos [file|url]",
+		Short: "Convert decimal hours to HH:MM in CSV and XLSX files",
+		Long: `Chronos converts decimal hour values (7.5) to HH:MM (07:30) in CSV and XLSX exports.
+
+Run without a command to pick files and columns interactively. Pass a file,
+as "Open with" does, to convert its detected columns after one confirmation,
+or a URL to download a file and convert it.`,
+		Version:      build.Version,
+		Args:         checkArgs(cobra.MaximumNArgs(1)),
+		SilenceUsage: true,
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) > 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			return []string{"csv", "xlsx", "json", "ndjson", "gz", "zip"}, cobra.ShellCompDirectiveFilterFileExt
+		},
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			quiet, _ := cmd.Flags().GetBool("quiet")
+			verbose, _ := cmd.Flags().GetBool("verbose")
+			if quiet && verbose {
+				return badArgument(fmt.Errorf("--quiet and --verbose can't be used together"))
+			}
+			if debug, _ := cmd.Flags().GetBool("debug"); debug || debuglog.EnabledByEnv() {
+				// The log only helps diagnose problems, so failing to open it isn't one
+				if _, err := debuglog.Start(build.Version); err != nil {
+					newPrinter(cmd).Warnf("could not open the debug log: %v", err)
+				}
+				slog.Debug("running command", "command", cmd.CommandPath(), "args", args)
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts := ui.Options{Version: build.Version, Commit: build.Commit, Date: build.Date}
+
+			if len(args) == 1 {
+				switch path := paths.Normalize(args[0]); {
+				case converter.IsURL(args[0]):
+					opts.URL = args[0]
+				case isFile(path):
+					// The output goes next to the file, so its folder is named in full
+					abs, err := filepath.Abs(path)
+					if err != nil {
+						return err
+					}
+					opts.File = abs
+				default:
+					return badArgument(fmt.Errorf("%q is not a file, URL or command; use \"chronos convert\" to convert files without the interface", args[0]))
+				}
+			}
+
+			// Without a terminal nobody can answer the interface, so convert or explain instead
+			if !plain && !isTerminal(os.Stdin.Fd()) && !isTerminal(os.Stdout.Fd()) {
+				return headless(cmd, args, profileName)
+			}
+
+			// Open the picker on fictional sample exports for screenshots
+			if demoMode {
+				dir, err := demo.Setup()
+				if err != nil {
+					return fmt.Errorf("could not set up demo files: %w", err)
+				}
+				defer os.RemoveAll(dir)
+				opts.StartDir = dir
+			}
+
+			if profileName != "" {
+				p, err := profile.Open(profileName)
+				if err != nil {
+					return badArgument(err)
+				}
+				opts.Profile = p
+			}
+
+			// Screen readers, and output that isn't a terminal, get questions a line at a time
+			settings, err := config.LoadSettings()
+			if err != nil {
+				newPrinter(cmd).Warnf("%v; using the default settings, which won't be saved until it's fixed", err)
+			}
+			if plain || settings.Plain || !isTerminal(os.Stdout.Fd()) {
+				return ui.RunPlain(cmd.InOrStdin(), cmd.OutOrStdout(), opts)
+			}
+
+			p := tea.NewProgram(ui.InitialModel(opts), tea.WithAltScreen(), tea.WithMouseCellMotion())
+			final, err := p.Run()
+			if m, ok := final.(ui.Model); ok {
+				m.Cleanup()
+				// Settings and sessions are a convenience; failing to save them isn't worth reporting
+				_ = m.SaveSettings()
+				_ = m.SaveSession()
+			}
+			if errors.Is(err, tea.ErrProgramPanic) {
+				return crashed(build, final)
+			}
+			return err
+		},
+	}
+
+	root.SetVersionTemplate(fmt.Sprintf("chronos {{.Version}}\ncommit: %s\nbuilt: %s\n", build.Commit, build.Date))
+
+	// Flag parsing errors are the caller's mistake
+	root.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return badArgument(err)
+	})
+
+	root.PersistentFlags().BoolP("quiet", "q", false, "only print errors")
+	root.PersistentFlags().Bool("verbose", false, "print details about each file")
+	root.PersistentFlags().Bool("debug", false, "write debug logs to chronos.log in the config directory (or set CHRONOS_DEBUG=1)")
+
+	root.Flags().BoolVar(&demoMode, "demo", false, "open the file picker on fictional sample exports")
+	root.Flags().BoolVar(&plain, "plain", false, "ask questions a line at a time, without colors or layout, for screen readers")
+	root.Flags().StringVar(&profileName, "profile", "", "enforce a saved profile, or a shared .chronos-preset.json file or link, on every file")
+	root.RegisterFlagCompletionFunc("profile", completeProfiles)
+
+	root.AddCommand(
+		newConvertCommand(),
+		newWatchCommand(),
+		newServeCommand(),
+		newPasteCommand(),
+		newVerifyCommand(),
+		newProfileCommand(),
+		newReportBugCommand(build),
+		newUpdateCommand(build),
+	)
+
+	return root
+}
+
+// isTerminal reports whether a file descriptor is a terminal; tests replace it.
+var isTerminal = term.IsTerminal
+
+// headless runs chronos launched without a terminal, as from CI or a
+// double-click with redirected input and output. A file or URL is
+// converted as chronos convert would convert it, with the profile if one
+// was given; without one the usage is printed.
+func headless(cmd *cobra.Command, args []string, profileName string) error {
+	slog.Debug("no terminal, running without the interface", "args", args)
+	if len(args) == 0 {
+		newPrinter(cmd).Warnf("there's no terminal for the interface; use \"chronos convert\" to convert files, or --plain to answer questions from input")
+		return cmd.Usage()
+	}
+
+	convert, _, err := cmd.Find([]string{"convert"})
+	if err != nil {
+		return err
+	}
+	if profileName != "" {
+		if err := convert.Flags().Set("profile", profileName); err != nil {
+			return err
+		}
+	}
+	return convert.RunE(convert, args)
+}
+
+// isFile reports whether path is an existing file rather than a directory.
+func isFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// completeProfiles suggests saved profile names
+func completeProfiles(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	names, _ := profile.List()
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// crashed reports a panic Bubble Tea recovered from, once it has restored
+// the terminal. Update and View log their own panics with the model's
+// state; one in a command is logged here with the state it left.
+func crashed(build BuildInfo, final tea.Model) error {
+	path := crash.LastLog()
+	if path == "" {
+		var state string
+		if m, ok := final.(ui.Model); ok {
+			state = m.CrashState()
+		}
+		var err error
+		info := crash.Info{Version: build.Version, Commit: build.Commit, Date: build.Date}
+		if path, err = crash.Write(info, "a command panicked; its stack is printed above", nil, state); err != nil {
+			return fmt.Errorf("chronos crashed, and the crash log couldn't be saved: %w", err)
+		}
+	}
+	return fmt.Errorf("chronos crashed; a crash log was saved to %s\nPlease attach it to a report at %s", path, crash.IssuesURL)
+}
@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"net"
+	"strconv"
+
+	"github.com/nconklindev/chronos/internal/server"
+
+	"github.com/spf13/cobra"
+)
+
+func newServeCommand() *cobra.Command {
+	var host string
+	var port int
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve an upload page and conversion API over HTTP",
+		Long: `Serve a small web page where colleagues who don't use the terminal can upload
+a CSV or XLSX file, pick columns, and download the converted file. The same
+conversions are available at /api/detect and /api/convert.
+
+The server has no authentication, so it only listens on this computer unless
+--host names another address, such as 0.0.0.0 for every network interface.
+Anyone who can reach that address can upload files and download conversions.`,
+		Args: checkArgs(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			addr := net.JoinHostPort(host, strconv.Itoa(port))
+			p := newPrinter(cmd)
+			p.Infof("chronos serving on http://%s", addr)
+			if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+				p.Warnf("the server has no authentication, and anyone who can reach %s can use it", host)
+			}
+			return server.ListenAndServe(addr)
+		},
+	}
+
+	cmd.Flags().StringVar(&host, "host", "127.0.0.1", "address to listen on; the server has no authentication, so anything but this computer lets anyone who can reach it upload files")
+	cmd.Flags().IntVarP(&port, "port", "p", 8080, "port to listen on")
+
+	return cmd
+}
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/nconklindev/chronos/internal/update"
+
+	"github.com/spf13/cobra"
+)
+
+func newUpdateCommand(build BuildInfo) *cobra.Command {
+	var check bool
+
+	cmd := &cobra.Command{
+		Use:   "update",
+		Short: "Replace chronos with the latest release",
+		Long: `Download the latest release of chronos from GitHub, check it against the
+release's checksums, and replace the running binary with it. Pass --check to
+only report whether there is one.
+
+Set "check_updates": true in config.json to be told about new releases when
+the interface opens.`,
+		Args: checkArgs(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p := newPrinter(cmd)
+			if !update.IsRelease(build.Version) {
+				return fmt.Errorf("chronos %s is a development build; install a release from https://github.com/nconklindev/chronos/releases to update it", build.Version)
+			}
+			r, err := update.Latest(nil, update.LatestURL)
+			if err != nil {
+				return fmt.Errorf("could not check for updates: %w", err)
+			}
+			if !update.Newer(build.Version, r.Tag) {
+				p.Infof("chronos %s is the latest release (this is %s)", r.Tag, build.Version)
+				return nil
+			}
+			if check {
+				p.Infof("chronos %s is available (this is %s); run \"chronos update\" to install it", r.Tag, build.Version)
+				return nil
+			}
+
+			exe, err := os.Executable()
+			if err != nil {
+				return fmt.Errorf("could not find the chronos binary: %w", err)
+			}
+			if exe, err = filepath.EvalSymlinks(exe); err != nil {
+				return fmt.Errorf("could not find the chronos binary: %w", err)
+			}
+
+			p.Detailf("Downloading %s", update.ArchiveName(runtime.GOOS, runtime.GOARCH))
+			binary, err := update.Download(nil, r, runtime.GOOS, runtime.GOARCH)
+			if err != nil {
+				return fmt.Errorf("could not download chronos %s: %w", r.Tag, err)
+			}
+			if err := update.Replace(exe, binary); err != nil {
+				return fmt.Errorf("could not replace %s: %w", exe, err)
+			}
+			p.Infof("Updated chronos from %s to %s", build.Version, r.Tag)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&check, "check", false, "only report whether a newer release is available")
+	return cmd
+}
@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/nconklindev/chronos/internal/converter"
+	"github.com/nconklindev/chronos/internal/paths"
+
+	"github.com/spf13/cobra"
+)
+
+func newVerifyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify <source> [converted]",
+		Short: "Check a converted file against its source",
+		Long: `Compare a converted file with the file it was converted from, confirming
+every converted cell is its original decimal hours in HH:MM. Useful for
+auditing files converted by older versions or other tools. The converted
+file defaults to <source>_converted next to the source.
+
+Exits with 4 when any cell doesn't match.`,
+		Example: `  chronos verify timesheet.csv
+  chronos verify timesheet.xlsx converted/timesheet_converted.xlsx`,
+		Args: checkArgs(cobra.RangeArgs(1, 2)),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return []string{"csv", "xlsx"}, cobra.ShellCompDirectiveFilterFileExt
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			source := paths.Normalize(args[0])
+			converted := ""
+			if len(args) > 1 {
+				converted = paths.Normalize(args[1])
+			} else {
+				ext := filepath.Ext(source)
+				converted = strings.TrimSuffix(source, ext) + "_converted" + ext
+			}
+
+			result, err := converter.Verify(source, converted)
+			if err != nil {
+				return err
+			}
+
+			p := newPrinter(cmd)
+			for _, warning := range result.Warnings {
+				p.Warnf("%s", warning)
+			}
+			for _, m := range result.Mismatches {
+				p.Errorf("row %d, %s: %s should be %s, found %q", m.Row, m.Column, m.Original, m.Expected, m.Actual)
+			}
+			p.Detailf("%d cell(s) left unconverted, as in footer or filtered rows", result.Unconverted)
+
+			columns := strings.Join(result.Columns, ", ")
+			if len(result.Mismatches) > 0 {
+				return &ExitError{Code: ExitMismatch, Err: fmt.Errorf("%d of %d converted cell(s) in %s don't match", len(result.Mismatches), result.CellsChecked, columns)}
+			}
+			p.Infof("%s matches %s: %d converted cell(s) in %s checked", filepath.Base(converted), filepath.Base(source), result.CellsChecked, columns)
+			return nil
+		},
+	}
+
+	return cmd
+}
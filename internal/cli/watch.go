@@ -0,0 +1,286 @@
+package cli
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/nconklindev/chronos/internal/converter"
+	"github.com/nconklindev/chronos/internal/notify"
+	"github.com/nconklindev/chronos/internal/paths"
+	"github.com/nconklindev/chronos/internal/summary"
+	"github.com/nconklindev/chronos/internal/types"
+
+	"github.com/spf13/cobra"
+)
+
+func newWatchCommand() *cobra.Command {
+	var flags conversionFlags
+	var interval time.Duration
+	var n notifyFlags
+
+	cmd := &cobra.Command{
+		Use:   "watch <dir>",
+		Short: "Convert CSV and XLSX files as they appear in a folder",
+		Long: `Watch a folder and convert each CSV or XLSX file that appears or changes in
+it, such as a downloads folder that receives a weekly export. A file is
+converted once it has stopped changing between two checks, so downloads in
+progress are left alone. A file that changes again is converted again,
+replacing its converted copy. Press Ctrl+C to stop.
+
+With --webhook or --smtp, each check that converts or fails to convert
+files sends a report of them: posted as JSON to the webhook, or emailed.
+The SMTP username and password are read from CHRONOS_SMTP_USERNAME and
+CHRONOS_SMTP_PASSWORD.`,
+		Example: `  chronos watch ~/Downloads
+  chronos watch --profile weekly -o ~/Payroll ~/Downloads
+  chronos watch --webhook https://hooks.slack.com/services/... ~/Downloads
+  chronos watch --smtp mail.example.com:587 --email-from chronos@example.com --email-to payroll@example.com ~/Downloads`,
+		Args: checkArgs(cobra.ExactArgs(1)),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return nil, cobra.ShellCompDirectiveFilterDirs
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir := paths.Normalize(args[0])
+			if info, err := os.Stat(dir); err != nil {
+				return badArgument(err)
+			} else if !info.IsDir() {
+				return badArgument(fmt.Errorf("%s is not a directory", dir))
+			}
+
+			if err := n.check(); err != nil {
+				return badArgument(err)
+			}
+
+			p := newPrinter(cmd)
+			// Watched files are converted again as they change
+			flags.force = true
+			fc, err := flags.converter(p)
+			if err != nil {
+				return err
+			}
+			fc.version = cmd.Root().Version
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+			defer stop()
+
+			w := newWatcher(dir, fc)
+			w.notifier = n.notifier()
+			p.Infof("Watching %s every %s (Ctrl+C to stop)", dir, interval)
+
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			for {
+				w.scan()
+
+				select {
+				case <-ctx.Done():
+					return nil
+				case <-ticker.C:
+				}
+			}
+		},
+	}
+
+	flags.register(cmd.Flags(), cmd)
+	cmd.Flags().DurationVar(&interval, "interval", 5*time.Second, "how often to check the folder")
+	cmd.Flags().StringVar(&n.webhook, "webhook", "", "URL to post a JSON report to after each check that converts or fails to convert files")
+	cmd.Flags().StringVar(&n.smtp, "smtp", "", "SMTP server (host:port) to email the report through")
+	cmd.Flags().StringVar(&n.from, "email-from", "", "address the report is emailed from, with --smtp")
+	cmd.Flags().StringVar(&n.to, "email-to", "", "comma-separated addresses to email the report to, with --smtp")
+
+	return cmd
+}
+
+// fileState is what a watched file looked like on the last scan.
+type fileState struct {
+	size    int64
+	modTime int64 // Unix nanoseconds, so states compare with ==
+}
+
+// watcher polls a directory for files to convert.
+type watcher struct {
+	dir string
+	fc  *fileConverter
+
+	// seen holds each file's state from the previous scan, to tell when it stops changing
+	seen map[string]fileState
+	// handled holds the state each file was in when it was converted or failed
+	handled map[string]fileState
+	// notifier sends the report of each scan that handled files, if set
+	notifier *notifier
+}
+
+func newWatcher(dir string, fc *fileConverter) *watcher {
+	return &watcher{
+		dir:     dir,
+		fc:      fc,
+		seen:    make(map[string]fileState),
+		handled: make(map[string]fileState),
+	}
+}
+
+// scan converts every file that is new or changed since it was last handled
+// and hasn't changed since the previous scan.
+func (w *watcher) scan() {
+	p := w.fc.printer
+	entries, err := os.ReadDir(w.dir)
+	if err != nil {
+		p.Errorf("watch: %v", err)
+		return
+	}
+
+	current := make(map[string]fileState)
+	var results []*types.ConversionResult
+	var options []types.ConversionOptions
+	var failed []string
+	for _, entry := range entries {
+		name := entry.Name()
+		ext := strings.ToLower(filepath.Ext(name))
+		if entry.IsDir() || (ext != ".csv" && ext != ".xlsx" && !converter.IsJSON(name)) || strings.HasPrefix(name, ".") {
+			continue
+		}
+		// Skip our own outputs
+		if converter.IsConvertedName(name) {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		path := filepath.Join(w.dir, name)
+		state := fileState{size: info.Size(), modTime: info.ModTime().UnixNano()}
+		current[path] = state
+
+		if prev, ok := w.seen[path]; !ok || prev != state {
+			continue // Still being written, or first sighting
+		}
+		if done, ok := w.handled[path]; ok && done == state {
+			continue
+		}
+		w.handled[path] = state
+
+		// Files converted before chronos started don't need converting again
+		in := input{path: path}
+		if outInfo, err := os.Stat(w.fc.outputPath(in, w.fc.preset)); err == nil && outInfo.ModTime().UnixNano() >= state.modTime {
+			continue
+		}
+
+		result, opts, err := w.fc.convertFile(in, nil)
+		if err != nil {
+			p.Errorf("%s: %v", name, err)
+			failed = append(failed, fmt.Sprintf("%s: %v", name, err))
+			continue
+		}
+		results = append(results, result)
+		options = append(options, opts)
+		p.Infof("%s %s → %s (%d rows, %s)", time.Now().Format("15:04:05"), name, result.OutputFile, result.RowsProcessed, strings.Join(result.ColumnsFound, ", "))
+		for _, warning := range result.Warnings {
+			p.Warnf("%s: %s", name, warning)
+		}
+		if err := w.fc.writeAudit([]*types.ConversionResult{result}); err != nil {
+			p.Errorf("%s: %v", name, err)
+		}
+		if err := w.fc.writeSidecars([]*types.ConversionResult{result}, []types.ConversionOptions{opts}); err != nil {
+			p.Errorf("%s: %v", name, err)
+		}
+	}
+
+	w.seen = current
+	if w.notifier != nil && (len(results) > 0 || len(failed) > 0) {
+		w.notifier.send(p, summary.NewReport(summary.NewRun(w.fc.version, results, options)), failed)
+	}
+}
+
+// notifyFlags are the watch flags that send reports of converted files.
+type notifyFlags struct {
+	webhook  string
+	smtp     string
+	from, to string
+}
+
+// check reports email flags given without the others they need.
+func (f notifyFlags) check() error {
+	switch {
+	case f.smtp == "" && (f.from != "" || f.to != ""):
+		return fmt.Errorf("--email-from and --email-to need --smtp")
+	case f.smtp != "" && (f.from == "" || len(addresses(f.to)) == 0):
+		return fmt.Errorf("--smtp needs --email-from and --email-to")
+	}
+	if f.smtp != "" {
+		if _, _, err := net.SplitHostPort(f.smtp); err != nil {
+			return fmt.Errorf("--smtp: %w", err)
+		}
+	}
+	return nil
+}
+
+// addresses splits a comma-separated list of email addresses.
+func addresses(list string) []string {
+	var addrs []string
+	for _, addr := range strings.Split(list, ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs
+}
+
+// notifier returns where reports are sent, or nil when nowhere.
+func (f notifyFlags) notifier() *notifier {
+	if f.webhook == "" && f.smtp == "" {
+		return nil
+	}
+	n := &notifier{webhook: f.webhook}
+	if f.smtp != "" {
+		n.smtp = &notify.SMTP{
+			Addr:     f.smtp,
+			From:     f.from,
+			To:       addresses(f.to),
+			Username: os.Getenv("CHRONOS_SMTP_USERNAME"),
+			Password: os.Getenv("CHRONOS_SMTP_PASSWORD"),
+		}
+	}
+	return n
+}
+
+// notifier sends the reports of watched batches to a webhook, by email or
+// both.
+type notifier struct {
+	webhook string
+	smtp    *notify.SMTP
+	client  *http.Client // Defaults to a client that gives up after webhookTimeout
+}
+
+// webhookTimeout is how long a webhook has to accept a report, so a slow
+// one doesn't hold up watching.
+const webhookTimeout = 30 * time.Second
+
+// send sends a report. Failures are printed; watching goes on.
+func (n *notifier) send(p *printer, report summary.Report, failed []string) {
+	b := notify.NewBatch(report, failed)
+	if n.webhook != "" {
+		client := n.client
+		if client == nil {
+			client = &http.Client{Timeout: webhookTimeout}
+		}
+		if err := notify.Webhook(client, n.webhook, b); err != nil {
+			p.Errorf("webhook: %v", err)
+		} else {
+			p.Detailf("report posted to webhook")
+		}
+	}
+	if n.smtp != nil {
+		if err := notify.Email(*n.smtp, b); err != nil {
+			p.Errorf("email: %v", err)
+		} else {
+			p.Detailf("report emailed to %s", strings.Join(n.smtp.To, ", "))
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+)
+
+const (
+	// AppName is the name of the folder chronos uses under the user config directory.
+	AppName = "chronos"
+	// ConfigFile holds the user's saved settings.
+	ConfigFile = "config.json"
+	// LogFile receives debug logs when debug logging is enabled.
+	LogFile = "chronos.log"
+)
+
+// Dir returns the directory chronos uses for its config and state files.
+// The directory is not created; use EnsureDir before writing to it.
+func Dir() (string, error) {
+	base, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, AppName), nil
+}
+
+// EnsureDir returns the config directory, creating it if needed.
+func EnsureDir() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// Path returns the full path of a file inside the config directory.
+func Path(name string) (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name), nil
+}
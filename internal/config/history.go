@@ -0,0 +1,69 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// HistoryFile records recent conversions so they can be browsed and re-run.
+const HistoryFile = "history.json"
+
+// HistoryLimit is how many conversions the history keeps.
+const HistoryLimit = 20
+
+// HistoryEntry is one finished set of conversions.
+type HistoryEntry struct {
+	Time  time.Time `json:"time"`
+	Files []RunFile `json:"files"`
+}
+
+// LoadHistory reads the saved history, newest first. A missing history is
+// not an error.
+func LoadHistory() ([]HistoryEntry, error) {
+	path, err := Path(HistoryFile)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var history []HistoryEntry
+	if err := json.Unmarshal(data, &history); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", HistoryFile, err)
+	}
+	return history, nil
+}
+
+// AddHistory records entry as the newest conversion, dropping the oldest
+// once there are more than HistoryLimit.
+func AddHistory(entry HistoryEntry) error {
+	history, err := LoadHistory()
+	if err != nil {
+		// Start over rather than refuse to record anything
+		history = nil
+	}
+
+	history = append([]HistoryEntry{entry}, history...)
+	if len(history) > HistoryLimit {
+		history = history[:HistoryLimit]
+	}
+
+	dir, err := EnsureDir()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, HistoryFile), data, 0o644)
+}
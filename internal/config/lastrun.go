@@ -0,0 +1,177 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/nconklindev/chronos/internal/types"
+)
+
+// LastRunFile records the most recent conversion so it can be repeated.
+const LastRunFile = "last-run.json"
+
+// LastRun is the configuration of the most recent set of conversions.
+type LastRun struct {
+	Files []RunFile `json:"files"`
+}
+
+// RunFile is the configuration used for one file in a run. Columns are
+// stored by header name so they still match if a new export reorders them.
+// Headers are the file's headers as read, which the column indices in
+// Options refer to, so OptionsFor can move the options with their columns.
+type RunFile struct {
+	Path    string                  `json:"path"`
+	Output  string                  `json:"output,omitempty"`
+	Columns []string                `json:"columns"`
+	Headers []string                `json:"headers,omitempty"`
+	Options types.ConversionOptions `json:"options"`
+}
+
+// LoadLastRun reads the saved last run.
+func LoadLastRun() (*LastRun, error) {
+	path, err := Path(LastRunFile)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("no previous run to repeat")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var run LastRun
+	if err := json.Unmarshal(data, &run); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", LastRunFile, err)
+	}
+	if len(run.Files) == 0 {
+		return nil, fmt.Errorf("no previous run to repeat")
+	}
+	return &run, nil
+}
+
+// SaveLastRun records run as the last run.
+func SaveLastRun(run LastRun) error {
+	dir, err := EnsureDir()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(run, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, LastRunFile), data, 0o644)
+}
+
+var digitRun = regexp.MustCompile(`[0-9]+`)
+
+// Resolve returns the file to convert when repeating the run: the original
+// path if it still exists, otherwise the newest file in the same directory
+// whose name matches it with the numbers changed (timesheet_week43.csv for
+// timesheet_week42.csv).
+func (f RunFile) Resolve() (string, error) {
+	if _, err := os.Stat(f.Path); err == nil {
+		return f.Path, nil
+	}
+
+	pattern := digitRun.ReplaceAllString(filepath.Base(f.Path), "*")
+	matches, err := filepath.Glob(filepath.Join(filepath.Dir(f.Path), pattern))
+	if err != nil {
+		return "", err
+	}
+
+	newest := ""
+	var newestTime int64
+	for _, match := range matches {
+		info, err := os.Stat(match)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		if newest == "" || info.ModTime().UnixNano() > newestTime {
+			newest = match
+			newestTime = info.ModTime().UnixNano()
+		}
+	}
+
+	if newest == "" {
+		return "", fmt.Errorf("%s no longer exists and no similar file was found", filepath.Base(f.Path))
+	}
+	return newest, nil
+}
+
+// OptionsFor returns the run's options for a file with headers, with the
+// columns they name by index moved to where those headers are now. The
+// computed duration columns after the last header move with it. It returns
+// false when one of the run's headers is missing, or the run didn't record
+// them, since the options can't be placed then.
+func (f RunFile) OptionsFor(headers []string) (types.ConversionOptions, bool) {
+	opts := f.Options
+	if len(f.Headers) == 0 {
+		return opts, false
+	}
+
+	// The nth column with a header moves to the nth column with it now
+	found := make(map[string][]int)
+	for i, header := range headers {
+		found[header] = append(found[header], i)
+	}
+	moved := make([]int, len(f.Headers))
+	for i, header := range f.Headers {
+		if len(found[header]) == 0 {
+			return opts, false
+		}
+		moved[i] = found[header][0]
+		found[header] = found[header][1:]
+	}
+	at := func(idx int) int {
+		if idx < len(moved) {
+			return moved[idx]
+		}
+		return idx - len(f.Headers) + len(headers)
+	}
+	indices := func(old []int) []int {
+		if old == nil {
+			return nil
+		}
+		moved := make([]int, len(old))
+		for i, idx := range old {
+			moved[i] = at(idx)
+		}
+		return moved
+	}
+
+	if opts.Columns != nil {
+		opts.Columns = make(map[int]types.ColumnSettings, len(f.Options.Columns))
+		for idx, settings := range f.Options.Columns {
+			opts.Columns[at(idx)] = settings
+		}
+	}
+	if opts.Filters != nil {
+		opts.Filters = make([]types.RowFilter, len(f.Options.Filters))
+		for i, filter := range f.Options.Filters {
+			filter.Column = at(filter.Column)
+			opts.Filters[i] = filter
+		}
+	}
+	opts.Order = indices(f.Options.Order)
+	opts.KeepColumns = indices(f.Options.KeepColumns)
+	if opts.Durations != nil {
+		opts.Durations = make([]types.DurationPair, len(f.Options.Durations))
+		for i, pair := range f.Options.Durations {
+			pair.Start, pair.End = at(pair.Start), at(pair.End)
+			opts.Durations[i] = pair
+		}
+	}
+	if opts.Totals != nil {
+		totals := *opts.Totals
+		totals.Employee, totals.Date = at(totals.Employee), at(totals.Date)
+		opts.Totals = &totals
+	}
+	return opts, true
+}
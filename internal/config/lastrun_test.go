@@ -0,0 +1,147 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/nconklindev/chronos/internal/types"
+)
+
+func TestLastRun_SaveLoad(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	if _, err := LoadLastRun(); err == nil {
+		t.Error("Expected an error when there is no previous run")
+	}
+
+	run := LastRun{Files: []RunFile{{
+		Path:    "/exports/timesheet_week42.csv",
+		Columns: []string{"Hours"},
+		Options: types.ConversionOptions{KeepOriginal: true},
+	}}}
+	if err := SaveLastRun(run); err != nil {
+		t.Fatalf("SaveLastRun failed: %v", err)
+	}
+
+	got, err := LoadLastRun()
+	if err != nil {
+		t.Fatalf("LoadLastRun failed: %v", err)
+	}
+	if got.Files[0].Path != run.Files[0].Path || !got.Files[0].Options.KeepOriginal {
+		t.Errorf("Unexpected last run: %+v", got)
+	}
+}
+
+func TestRunFile_OptionsFor(t *testing.T) {
+	file := RunFile{
+		Headers: []string{"Name", "Start", "End", "Hours"},
+		Options: types.ConversionOptions{
+			Columns:     map[int]types.ColumnSettings{3: {Format: types.FormatMinutes}, 4: {Format: types.FormatHHMMSS}},
+			Filters:     []types.RowFilter{{Column: 0, Op: types.FilterEquals, Value: "Alice"}},
+			Order:       []int{3, 0},
+			KeepColumns: []int{0},
+			Durations:   []types.DurationPair{{Start: 1, End: 2, Header: "Worked"}},
+			Totals:      &types.Totals{Employee: 0, Date: 1},
+		},
+	}
+
+	// A new export with the columns moved and one more
+	got, ok := file.OptionsFor([]string{"Hours", "Site", "Name", "End", "Start"})
+	if !ok {
+		t.Fatal("Expected the options to be moved")
+	}
+	if got.Columns[0].Format != types.FormatMinutes || got.Columns[5].Format != types.FormatHHMMSS || len(got.Columns) != 2 {
+		t.Errorf("Unexpected column settings: %v", got.Columns)
+	}
+	if got.Filters[0].Column != 2 {
+		t.Errorf("Expected the filter on Name, column 2, got %d", got.Filters[0].Column)
+	}
+	if !slices.Equal(got.Order, []int{0, 2}) || !slices.Equal(got.KeepColumns, []int{2}) {
+		t.Errorf("Unexpected order %v or kept columns %v", got.Order, got.KeepColumns)
+	}
+	if got.Durations[0].Start != 4 || got.Durations[0].End != 3 {
+		t.Errorf("Unexpected durations: %+v", got.Durations)
+	}
+	if got.Totals.Employee != 2 || got.Totals.Date != 4 {
+		t.Errorf("Unexpected totals: %+v", got.Totals)
+	}
+	if file.Options.Filters[0].Column != 0 || file.Options.Totals.Employee != 0 {
+		t.Error("Expected the run's own options to be left as they were")
+	}
+
+	if _, ok := file.OptionsFor([]string{"Name", "Start", "Hours"}); ok {
+		t.Error("Expected a missing header to stop the options being moved")
+	}
+	if _, ok := (RunFile{Options: file.Options}).OptionsFor(file.Headers); ok {
+		t.Error("Expected a run without headers not to be moved")
+	}
+}
+
+func TestRunFile_Resolve(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, age time.Duration) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte("Hours\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		modTime := time.Now().Add(-age)
+		os.Chtimes(path, modTime, modTime)
+		return path
+	}
+
+	week43 := write("timesheet_week43.csv", time.Hour)
+	write("timesheet_week41.csv", 2*time.Hour)
+	write("payroll_week44.csv", 0)
+
+	// An existing file is used as is
+	if got, _ := (RunFile{Path: week43}).Resolve(); got != week43 {
+		t.Errorf("Expected %s, got %s", week43, got)
+	}
+
+	// A missing file falls back to the newest similar name
+	got, err := RunFile{Path: filepath.Join(dir, "timesheet_week42.csv")}.Resolve()
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if got != week43 {
+		t.Errorf("Expected %s, got %s", week43, got)
+	}
+
+	if _, err := (RunFile{Path: filepath.Join(dir, "summary.csv")}).Resolve(); err == nil {
+		t.Error("Expected an error when nothing matches")
+	}
+}
+
+func TestHistory(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	history, err := LoadHistory()
+	if err != nil || len(history) != 0 {
+		t.Fatalf("Expected an empty history, got %v, %v", history, err)
+	}
+
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	for i := 0; i < HistoryLimit+2; i++ {
+		entry := HistoryEntry{
+			Time:  start.Add(time.Duration(i) * time.Hour),
+			Files: []RunFile{{Path: "/exports/timesheet.csv", Columns: []string{"Hours"}}},
+		}
+		if err := AddHistory(entry); err != nil {
+			t.Fatalf("AddHistory failed: %v", err)
+		}
+	}
+
+	history, err = LoadHistory()
+	if err != nil {
+		t.Fatalf("LoadHistory failed: %v", err)
+	}
+	if len(history) != HistoryLimit {
+		t.Fatalf("Expected %d entries, got %d", HistoryLimit, len(history))
+	}
+	if newest := start.Add(time.Duration(HistoryLimit+1) * time.Hour); !history[0].Time.Equal(newest) {
+		t.Errorf("Expected the newest entry first, got %v", history[0].Time)
+	}
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// SessionFile records a batch that was quit before it was converted, so it
+// can be resumed on the next launch.
+const SessionFile = "session.json"
+
+// Session is a batch of files whose columns were still being chosen.
+type Session struct {
+	Time  time.Time     `json:"time"`
+	Files []SessionItem `json:"files"`
+}
+
+// SessionItem is one file of a saved session. Files that were configured
+// keep their columns and options; the rest are only paths still to load.
+type SessionItem struct {
+	RunFile
+	Configured bool `json:"configured,omitempty"`
+	// InPlace adds the converted sheet to the file itself rather than to a copy.
+	InPlace bool `json:"in_place,omitempty"`
+}
+
+// Configured returns how many of the session's files were configured,
+// which are the first ones.
+func (s Session) Configured() int {
+	n := 0
+	for n < len(s.Files) && s.Files[n].Configured {
+		n++
+	}
+	return n
+}
+
+// LoadSession reads the saved session. A missing session is not an error,
+// and returns nil.
+func LoadSession() (*Session, error) {
+	path, err := Path(SessionFile)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var s Session
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", SessionFile, err)
+	}
+	if s.Configured() == 0 {
+		return nil, nil
+	}
+	return &s, nil
+}
+
+// SaveSession records s as the session to resume.
+func SaveSession(s Session) error {
+	dir, err := EnsureDir()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, SessionFile), data, 0o644)
+}
+
+// ClearSession removes the saved session, if there is one.
+func ClearSession() error {
+	path, err := Path(SessionFile)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nconklindev/chronos/internal/types"
+)
+
+func TestSession(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	if s, err := LoadSession(); s != nil || err != nil {
+		t.Fatalf("Expected no session, got %v, %v", s, err)
+	}
+
+	session := Session{
+		Time: time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC),
+		Files: []SessionItem{
+			{RunFile: RunFile{Path: "/exports/a.xlsx", Columns: []string{"Hours"}, Options: types.ConversionOptions{NewSheet: true}}, Configured: true, InPlace: true},
+			{RunFile: RunFile{Path: "/exports/b.csv"}},
+		},
+	}
+	if err := SaveSession(session); err != nil {
+		t.Fatalf("SaveSession failed: %v", err)
+	}
+
+	got, err := LoadSession()
+	if err != nil {
+		t.Fatalf("LoadSession failed: %v", err)
+	}
+	if got.Configured() != 1 || got.Files[1].Path != "/exports/b.csv" || !got.Files[0].InPlace || !got.Files[0].Options.NewSheet {
+		t.Errorf("Unexpected session: %+v", got)
+	}
+
+	if err := ClearSession(); err != nil {
+		t.Fatalf("ClearSession failed: %v", err)
+	}
+	if s, err := LoadSession(); s != nil || err != nil {
+		t.Errorf("Expected the session to be cleared, got %v, %v", s, err)
+	}
+	if err := ClearSession(); err != nil {
+		t.Errorf("Clearing a missing session failed: %v", err)
+	}
+}
@@ -0,0 +1,119 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/nconklindev/chronos/internal/types"
+)
+
+// Settings are the preferences remembered between sessions, stored in ConfigFile.
+type Settings struct {
+	// LastDir is the directory the file picker was open in when chronos last exited.
+	LastDir string `json:"last_dir,omitempty"`
+	// StartDir is the directory the file picker always opens in. Empty
+	// reopens LastDir.
+	StartDir string `json:"start_dir,omitempty"`
+	// ShowHidden and SortBy are the file picker's listing options.
+	ShowHidden bool   `json:"show_hidden"`
+	SortBy     string `json:"sort_by,omitempty"`
+	// KeepOriginal, Placement, OnlySelected, DropFooter, OutputEncoding,
+	// LineEnding, FinalNewline, Quoting and Blanks are the defaults for
+	// newly loaded files.
+	KeepOriginal   bool               `json:"keep_original"`
+	Placement      types.Placement    `json:"placement,omitempty"`
+	OnlySelected   bool               `json:"only_selected,omitempty"`
+	DropFooter     bool               `json:"drop_footer"`
+	OutputEncoding string             `json:"output_encoding,omitempty"`
+	LineEnding     types.LineEnding   `json:"line_ending,omitempty"`
+	FinalNewline   types.FinalNewline `json:"final_newline,omitempty"`
+	Quoting        types.Quoting      `json:"quoting,omitempty"`
+	Blanks         types.BlankPolicy  `json:"blanks,omitempty"`
+	// HeaderSuffix names the converted copies of kept columns in newly loaded files.
+	HeaderSuffix string `json:"header_suffix,omitempty"`
+	// Rounding is how the columns of newly loaded files are rounded. Empty
+	// rounds to the nearest minute.
+	Rounding types.Rounding `json:"rounding,omitempty"`
+	// OutputSuffix is added to the names of converted copies. Empty adds
+	// "_converted".
+	OutputSuffix string `json:"output_suffix,omitempty"`
+	// LossThreshold is how many minutes rounding may lose from a cell
+	// before it's reported. Zero uses the default of half a minute.
+	LossThreshold float64 `json:"loss_threshold,omitempty"`
+	// Theme names a built-in theme, and Colors overrides its colors by
+	// name, such as "primary" or "muted".
+	Theme  string            `json:"theme,omitempty"`
+	Colors map[string]string `json:"colors,omitempty"`
+	// Notify alerts the user when a long batch finishes.
+	Notify Notify `json:"notify"`
+	// CheckUpdates looks for a newer release when the interface opens, at
+	// most once a day.
+	CheckUpdates bool `json:"check_updates,omitempty"`
+	// Plain replaces the full-screen interface with plain questions and
+	// answers, for screen readers.
+	Plain bool `json:"plain,omitempty"`
+	// Language is the interface's language, such as "es" or "de". Empty
+	// follows the locale.
+	Language string `json:"language,omitempty"`
+	// Keys remaps key bindings, from a name such as "quit" or
+	// "columns.keep_original" to the keys that trigger it.
+	Keys map[string][]string `json:"keys,omitempty"`
+}
+
+// Notify configures how chronos gets attention when a batch finishes or fails.
+type Notify struct {
+	Desktop bool `json:"desktop"` // Show a desktop notification
+	Bell    bool `json:"bell"`    // Ring the terminal bell
+	// MinSeconds skips batches that finish sooner, while the user is likely still watching.
+	MinSeconds int `json:"min_seconds,omitempty"`
+}
+
+// LoadSettings reads the saved settings. Missing settings are not an error.
+func LoadSettings() (Settings, error) {
+	var s Settings
+
+	path, err := Path(ConfigFile)
+	if err != nil {
+		return s, err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return s, err
+	}
+
+	if err := json.Unmarshal(data, &s); err != nil {
+		return Settings{}, fmt.Errorf("reading %s: %w", ConfigFile, err)
+	}
+	return s, nil
+}
+
+// HasSettings reports whether settings have been saved, which they are
+// from the end of the first session on.
+func HasSettings() bool {
+	path, err := Path(ConfigFile)
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(path)
+	return err == nil
+}
+
+// SaveSettings replaces the saved settings.
+func SaveSettings(s Settings) error {
+	dir, err := EnsureDir()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, ConfigFile), data, 0o644)
+}
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/nconklindev/chronos/internal/types"
+)
+
+func TestSettings_SaveLoad(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	s, err := LoadSettings()
+	if err != nil || !reflect.DeepEqual(s, Settings{}) {
+		t.Fatalf("Expected default settings, got %+v, %v", s, err)
+	}
+	if HasSettings() {
+		t.Error("Expected no saved settings")
+	}
+
+	want := Settings{
+		LastDir:        "/exports",
+		KeepOriginal:   true,
+		OutputEncoding: "UTF-8 BOM",
+		Rounding:       types.RoundQuarter,
+		OutputSuffix:   "_hhmm",
+		Keys:           map[string][]string{"quit": {"ctrl+q"}, "columns.toggle": {"x", " "}},
+	}
+	if err := SaveSettings(want); err != nil {
+		t.Fatalf("SaveSettings failed: %v", err)
+	}
+
+	if !HasSettings() {
+		t.Error("Expected saved settings")
+	}
+	got, err := LoadSettings()
+	if err != nil {
+		t.Fatalf("LoadSettings failed: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+}
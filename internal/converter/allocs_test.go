@@ -0,0 +1,38 @@
+//go:build !race
+
+package converter
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/nconklindev/chronos/internal/types"
+)
+
+// csvAllocsPerRow is how many allocations converting a CSV row may take.
+// Converting a row of the benchmark fixtures takes about 11. The race
+// detector allocates too, so this file is left out of its builds.
+const csvAllocsPerRow = 13
+
+// TestConvertCSV_AllocsPerRow guards against conversions allocating more
+// for each row, which the benchmarks show as slowdowns on large files.
+func TestConvertCSV_AllocsPerRow(t *testing.T) {
+	if testing.Short() {
+		t.Skip("converts thousands of rows several times")
+	}
+	allocs := func(rows int) float64 {
+		input := writeBenchCSV(t, rows)
+		output := filepath.Join(t.TempDir(), "output.csv")
+		return testing.AllocsPerRun(3, func() {
+			if _, err := ConvertCSV(input, LocalFile(output), benchColumns, types.ConversionOptions{}, nil); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+
+	// Counting the difference leaves out allocations made once per file
+	perRow := (allocs(2000) - allocs(1000)) / 1000
+	if perRow > csvAllocsPerRow {
+		t.Errorf("Expected at most %d allocations converting each row, got %.1f", csvAllocsPerRow, perRow)
+	}
+}
@@ -0,0 +1,144 @@
+package converter
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+)
+
+// arrowSchemaKey is the Parquet metadata key Arrow readers, such as
+// pyarrow and Polars, take a file's Arrow schema from. Parquet has no
+// duration type, so that's where duration columns are marked: they're
+// stored as INT64 and read back by Arrow readers as durations.
+const arrowSchemaKey = "ARROW:schema"
+
+// Arrow's flatbuffer enums, as numbered in Schema.fbs and Message.fbs.
+const (
+	arrowMetadataV5      = 4
+	arrowMessageSchema   = 1
+	arrowTypeFloat       = 3
+	arrowTypeUtf8        = 5
+	arrowTypeDuration    = 18
+	arrowPrecisionDouble = 2
+	arrowUnitSecond      = 0
+)
+
+// arrowSchema returns the Arrow schema of Parquet columns as Arrow writes
+// it in Parquet metadata: an IPC schema message, base64 encoded. Text is
+// utf8, doubles float64, and INT64 columns durations in seconds.
+func arrowSchema(columns []*parquetColumn) string {
+	fields := make([]fbTable, len(columns))
+	for i, c := range columns {
+		kind, typ := uint8(arrowTypeUtf8), fbTable{}
+		switch c.kind {
+		case parquetDouble:
+			kind, typ = arrowTypeFloat, fbTable{int16(arrowPrecisionDouble)}
+		case parquetInt64:
+			// Seconds aren't the default unit, so the unit is always written
+			kind, typ = arrowTypeDuration, fbTable{int16(arrowUnitSecond)}
+		}
+		// Name, nullable, type, dictionary and children, which readers
+		// expect even when there are none
+		fields[i] = fbTable{c.name, true, kind, typ, nil, []fbTable{}}
+	}
+	schema := fbTable{nil, fields}
+	message := fbTable{int16(arrowMetadataV5), uint8(arrowMessageSchema), schema}
+
+	var b fbBuilder
+	b.root(message)
+	b.align(8)
+
+	// An IPC message is prefixed with a continuation marker and its length
+	ipc := binary.LittleEndian.AppendUint32(nil, 0xffffffff)
+	ipc = binary.LittleEndian.AppendUint32(ipc, uint32(len(b.buf)))
+	return base64.StdEncoding.EncodeToString(append(ipc, b.buf...))
+}
+
+// fbTable is a flatbuffer table, its fields in order of their ids. A field
+// is nil when it's left out, a uint8, int16 or bool scalar, or a string,
+// table or vector of tables written after the table and referred to.
+type fbTable []any
+
+// fbBuilder writes flatbuffers front to back: each table follows its
+// vtable, and what it refers to follows it, so every offset is forward.
+type fbBuilder struct {
+	buf []byte
+}
+
+func (b *fbBuilder) align(n int) {
+	for len(b.buf)%n != 0 {
+		b.buf = append(b.buf, 0)
+	}
+}
+
+// refer fills in the offset in slot to refer to what's at pos.
+func (b *fbBuilder) refer(slot, pos int) {
+	binary.LittleEndian.PutUint32(b.buf[slot:], uint32(pos-slot))
+}
+
+// root writes t as the buffer's root table.
+func (b *fbBuilder) root(t fbTable) {
+	b.buf = make([]byte, 4)
+	b.table(0, t)
+}
+
+// table writes t, referred to from slot, with a four byte slot for each
+// field, which is more than scalars need but keeps every slot aligned.
+func (b *fbBuilder) table(from int, t fbTable) {
+	b.align(2)
+	vtable := len(b.buf)
+	b.buf = binary.LittleEndian.AppendUint16(b.buf, uint16(4+2*len(t)))
+	b.buf = binary.LittleEndian.AppendUint16(b.buf, uint16(4+4*len(t)))
+	for i, field := range t {
+		offset := 0
+		if field != nil {
+			offset = 4 + 4*i
+		}
+		b.buf = binary.LittleEndian.AppendUint16(b.buf, uint16(offset))
+	}
+	b.align(4)
+	start := len(b.buf)
+	b.refer(from, start)
+	b.buf = binary.LittleEndian.AppendUint32(b.buf, uint32(start-vtable))
+
+	for _, field := range t {
+		slot := make([]byte, 4)
+		switch v := field.(type) {
+		case uint8:
+			slot[0] = v
+		case bool:
+			if v {
+				slot[0] = 1
+			}
+		case int16:
+			binary.LittleEndian.PutUint16(slot, uint16(v))
+		}
+		b.buf = append(b.buf, slot...)
+	}
+
+	for i, field := range t {
+		slot := start + 4 + 4*i
+		switch v := field.(type) {
+		case string:
+			b.align(4)
+			b.refer(slot, len(b.buf))
+			b.buf = binary.LittleEndian.AppendUint32(b.buf, uint32(len(v)))
+			b.buf = append(append(b.buf, v...), 0)
+		case fbTable:
+			b.table(slot, v)
+		case []fbTable:
+			b.align(4)
+			b.refer(slot, len(b.buf))
+			b.vector(v)
+		}
+	}
+}
+
+// vector writes a vector of tables, each table after it.
+func (b *fbBuilder) vector(tables []fbTable) {
+	b.buf = binary.LittleEndian.AppendUint32(b.buf, uint32(len(tables)))
+	slots := len(b.buf)
+	b.buf = append(b.buf, make([]byte, 4*len(tables))...)
+	for i, t := range tables {
+		b.table(slots+4*i, t)
+	}
+}
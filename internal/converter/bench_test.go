@@ -0,0 +1,193 @@
+package converter
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/nconklindev/chronos/internal/types"
+	"github.com/xuri/excelize/v2"
+)
+
+// benchRows are the sizes of the benchmark fixtures. The largest are
+// skipped with -short, as generating and converting them takes minutes,
+// and loading them as whole workbooks takes tens of gigabytes.
+var benchRows = []int{100_000, 1_000_000}
+
+// benchShortRows is the largest fixture benchmarked with -short.
+const benchShortRows = 100_000
+
+// benchColumns are the decimal hour columns of the benchmark fixtures.
+var benchColumns = []int{3, 4}
+
+// benchRecord returns row i of a benchmark fixture: a timesheet with two
+// decimal hour columns among IDs, names, dates and departments.
+func benchRecord(i int) []string {
+	return []string{
+		fmt.Sprintf("%06d", i),
+		"Employee " + strconv.Itoa(i%500),
+		fmt.Sprintf("2024-%02d-%02d", i%12+1, i%28+1),
+		strconv.FormatFloat(float64(i%48)/4+0.1, 'f', 2, 64),
+		strconv.FormatFloat(float64(i%5)*0.25, 'f', 2, 64),
+		[]string{"Ops", "Sales", "Support", "Finance"}[i%4],
+	}
+}
+
+var benchHeader = []string{"ID", "Name", "Date", "Hours", "Break", "Dept"}
+
+// writeBenchCSV writes a fixture of rows data rows and returns its path.
+func writeBenchCSV(b testing.TB, rows int) string {
+	b.Helper()
+
+	path := filepath.Join(b.TempDir(), "bench.csv")
+	f, err := os.Create(path)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer f.Close()
+
+	buf := bufio.NewWriter(f)
+	w := csv.NewWriter(buf)
+	w.Write(benchHeader)
+	for i := range rows {
+		w.Write(benchRecord(i))
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		b.Fatal(err)
+	}
+	if err := buf.Flush(); err != nil {
+		b.Fatal(err)
+	}
+	return path
+}
+
+// writeBenchXLSX writes a fixture of rows data rows, with the hours stored
+// as numbers, and returns its path.
+func writeBenchXLSX(b testing.TB, rows int) string {
+	b.Helper()
+
+	path := filepath.Join(b.TempDir(), "bench.xlsx")
+	f := excelize.NewFile()
+	defer f.Close()
+	sw, err := f.NewStreamWriter("Sheet1")
+	if err != nil {
+		b.Fatal(err)
+	}
+	header := make([]any, len(benchHeader))
+	for i, h := range benchHeader {
+		header[i] = h
+	}
+	if err := sw.SetRow("A1", header); err != nil {
+		b.Fatal(err)
+	}
+	for i := range rows {
+		record := benchRecord(i)
+		cells := make([]any, len(record))
+		for j, value := range record {
+			cells[j] = value
+		}
+		for _, col := range benchColumns {
+			cells[col], _ = strconv.ParseFloat(record[col], 64)
+		}
+		cell, _ := excelize.CoordinatesToCellName(1, i+2)
+		if err := sw.SetRow(cell, cells); err != nil {
+			b.Fatal(err)
+		}
+	}
+	if err := sw.Flush(); err != nil {
+		b.Fatal(err)
+	}
+	if err := f.SaveAs(path); err != nil {
+		b.Fatal(err)
+	}
+	return path
+}
+
+// benchmarkConvert benchmarks converting each size of fixture written by
+// write, reporting the input's bytes per second and rows per second.
+func benchmarkConvert(b *testing.B, write func(testing.TB, int) string, convert func(string, OutputSink) error) {
+	for _, rows := range benchRows {
+		b.Run(fmt.Sprintf("rows=%d", rows), func(b *testing.B) {
+			if testing.Short() && rows > benchShortRows {
+				b.Skip("skipping the largest fixtures in short mode")
+			}
+			input := write(b, rows)
+			info, err := os.Stat(input)
+			if err != nil {
+				b.Fatal(err)
+			}
+			output := filepath.Join(b.TempDir(), "output"+filepath.Ext(input))
+
+			b.SetBytes(info.Size())
+			b.ReportAllocs()
+			for b.Loop() {
+				if err := convert(input, LocalFile(output)); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(rows)*float64(b.N)/b.Elapsed().Seconds(), "rows/s")
+		})
+	}
+}
+
+func BenchmarkConvertCSV(b *testing.B) {
+	benchmarkConvert(b, writeBenchCSV, func(input string, sink OutputSink) error {
+		_, err := ConvertCSV(input, sink, benchColumns, types.ConversionOptions{}, nil)
+		return err
+	})
+}
+
+func BenchmarkConvertCSV_KeepOriginal(b *testing.B) {
+	benchmarkConvert(b, writeBenchCSV, func(input string, sink OutputSink) error {
+		_, err := ConvertCSV(input, sink, benchColumns, types.ConversionOptions{KeepOriginal: true}, nil)
+		return err
+	})
+}
+
+func BenchmarkConvertXLSX(b *testing.B) {
+	benchmarkConvert(b, writeBenchXLSX, func(input string, sink OutputSink) error {
+		_, err := ConvertXLSX(input, sink, benchColumns, types.ConversionOptions{}, nil)
+		return err
+	})
+}
+
+func BenchmarkConvertXLSX_KeepOriginal(b *testing.B) {
+	benchmarkConvert(b, writeBenchXLSX, func(input string, sink OutputSink) error {
+		_, err := ConvertXLSX(input, sink, benchColumns, types.ConversionOptions{KeepOriginal: true}, nil)
+		return err
+	})
+}
+
+func BenchmarkConvertXLSX_LowMemory(b *testing.B) {
+	benchmarkConvert(b, writeBenchXLSX, func(input string, sink OutputSink) error {
+		_, err := ConvertXLSX(input, sink, benchColumns, types.ConversionOptions{LowMemory: true}, nil)
+		return err
+	})
+}
+
+func BenchmarkReadFileData(b *testing.B) {
+	for _, tt := range []struct {
+		name  string
+		write func(testing.TB, int) string
+		read  func(string) (*types.FileData, error)
+	}{
+		{"csv", writeBenchCSV, ReadFileData},
+		{"xlsx", writeBenchXLSX, ReadFileData},
+		{"xlsx low memory", writeBenchXLSX, ReadFileDataLowMemory},
+	} {
+		b.Run(tt.name, func(b *testing.B) {
+			input := tt.write(b, benchShortRows)
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := tt.read(input); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,307 @@
+package converter
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/nconklindev/chronos/internal/types"
+	"github.com/xuri/excelize/v2"
+)
+
+// cellKind is how a cell stores its value.
+type cellKind uint8
+
+const (
+	kindNumber cellKind = iota
+	kindText
+	kindBool
+	kindOther // Errors, dates and formula text, written as shown
+)
+
+// sourceCell is how a cell of a streamed sheet is stored, so low-memory
+// output can write the cells it doesn't convert as they were, rather than
+// guessing their types from their text.
+type sourceCell struct {
+	kind  cellKind
+	style int
+	raw   string // The stored value of numbers and booleans
+}
+
+// cellFormats reads how each cell of a workbook's first sheet is stored,
+// a row at a time from the sheet's XML, since streamed rows only have
+// values.
+type cellFormats struct {
+	zr      *zip.ReadCloser
+	sheet   io.ReadCloser
+	d       *xml.Decoder
+	next    []sourceCell // The cells of the next row read, not yet asked for
+	nextRow int          // The 1-indexed row of next, 0 once the sheet ends
+}
+
+// sheetRow is a row of a sheet's XML, with just how its cells are stored.
+type sheetRow struct {
+	R     int `xml:"r,attr"`
+	Cells []struct {
+		R string `xml:"r,attr"`
+		S int    `xml:"s,attr"`
+		T string `xml:"t,attr"`
+		V string `xml:"v"`
+	} `xml:"c"`
+}
+
+// openCellFormats opens the first sheet of a workbook to read how its
+// cells are stored.
+func openCellFormats(workbook string) (*cellFormats, error) {
+	zr, err := zip.OpenReader(workbook)
+	if err != nil {
+		return nil, err
+	}
+	sheetPath, err := firstSheetPath(&zr.Reader)
+	if err != nil {
+		zr.Close()
+		return nil, err
+	}
+	sheet, err := zr.Open(sheetPath)
+	if err != nil {
+		zr.Close()
+		return nil, err
+	}
+	formats := &cellFormats{zr: zr, sheet: sheet, d: xml.NewDecoder(sheet)}
+	if err := formats.read(); err != nil {
+		formats.Close()
+		return nil, err
+	}
+	return formats, nil
+}
+
+// row returns how the cells of row i, 0-indexed, are stored, by column.
+// Rows must be asked for in order.
+func (c *cellFormats) row(i int) ([]sourceCell, error) {
+	for c.nextRow != 0 && c.nextRow <= i {
+		if err := c.read(); err != nil {
+			return nil, err
+		}
+	}
+	if c.nextRow != i+1 {
+		return nil, nil
+	}
+	return c.next, nil
+}
+
+// read reads the next row of the sheet.
+func (c *cellFormats) read() error {
+	for {
+		token, err := c.d.Token()
+		if err == io.EOF {
+			c.next, c.nextRow = nil, 0
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "row" {
+			continue
+		}
+
+		var row sheetRow
+		if err := c.d.DecodeElement(&row, &start); err != nil {
+			return err
+		}
+		if row.R == 0 {
+			row.R = c.nextRow + 1
+		}
+		c.next, c.nextRow = nil, row.R
+		col := 0
+		for _, xc := range row.Cells {
+			col++
+			if xc.R != "" {
+				if n, _, err := excelize.CellNameToCoordinates(xc.R); err == nil {
+					col = n
+				}
+			}
+			for len(c.next) < col {
+				c.next = append(c.next, sourceCell{})
+			}
+			cell := &c.next[col-1]
+			cell.style = xc.S
+			switch xc.T {
+			case "", "n":
+				cell.kind = kindNumber
+			case "s", "inlineStr":
+				cell.kind = kindText
+			case "b":
+				cell.kind = kindBool
+			default:
+				cell.kind = kindOther
+			}
+			if cell.kind == kindNumber || cell.kind == kindBool {
+				cell.raw = xc.V
+			}
+		}
+		return nil
+	}
+}
+
+// Close closes the workbook.
+func (c *cellFormats) Close() error {
+	c.sheet.Close()
+	return c.zr.Close()
+}
+
+// firstSheetPath finds the part holding a workbook's first sheet.
+func firstSheetPath(zr *zip.Reader) (string, error) {
+	var workbook struct {
+		Sheets []struct {
+			ID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	if err := decodePart(zr, "xl/workbook.xml", &workbook); err != nil {
+		return "", err
+	}
+	if len(workbook.Sheets) == 0 {
+		return "", fmt.Errorf("workbook has no sheets")
+	}
+
+	var rels struct {
+		Relationships []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	if err := decodePart(zr, "xl/_rels/workbook.xml.rels", &rels); err != nil {
+		return "", err
+	}
+	for _, rel := range rels.Relationships {
+		if rel.ID != workbook.Sheets[0].ID {
+			continue
+		}
+		if strings.HasPrefix(rel.Target, "/") {
+			return strings.TrimPrefix(rel.Target, "/"), nil
+		}
+		return path.Join("xl", rel.Target), nil
+	}
+	return "", fmt.Errorf("workbook's first sheet not found")
+}
+
+// decodePart decodes the XML part of a workbook at name into v.
+func decodePart(zr *zip.Reader, name string, v any) error {
+	part, err := zr.Open(name)
+	if err != nil {
+		return err
+	}
+	defer part.Close()
+	return xml.NewDecoder(part).Decode(v)
+}
+
+func attr(start xml.StartElement, name string) string {
+	for _, a := range start.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// cellWriter writes the rows of low-memory output, keeping the stored
+// type and number format of cells copied unchanged from the input. Other
+// cells, such as converted ones, are written as cellOf writes them.
+type cellWriter struct {
+	from, to     *excelize.File
+	columns      map[string]int // Input columns by header
+	headerRowIdx int
+	converted    map[int]bool // Input columns of the next row converted in place
+	styles       map[int]int  // Output styles by input style
+}
+
+func newCellWriter(from, to *excelize.File, header []string, headerRowIdx int) *cellWriter {
+	columns := make(map[string]int, len(header))
+	for i, h := range header {
+		if _, ok := columns[h]; !ok {
+			columns[h] = i
+		}
+	}
+	return &cellWriter{
+		from:         from,
+		to:           to,
+		columns:      columns,
+		headerRowIdx: headerRowIdx,
+		converted:    make(map[int]bool),
+		styles:       make(map[int]int),
+	}
+}
+
+// convertedCells marks the cells of the next row changed in place, so
+// they aren't written with their input types. Kept originals are
+// unchanged, their converted copies being new columns.
+func (w *cellWriter) convertedCells(changes []types.CellChange, opts types.ConversionOptions) {
+	clear(w.converted)
+	if opts.KeepOriginal {
+		return
+	}
+	for _, c := range changes {
+		w.converted[c.Col] = true
+	}
+}
+
+// row returns the cells to write for row i of the output, whose columns
+// are found in the input by their headers, given how the input row's
+// cells were stored. Rows above the header keep their columns.
+func (w *cellWriter) row(i int, values, header []string, formats []sourceCell) []any {
+	cells := make([]any, len(values))
+	for col, value := range values {
+		from, ok := col, true
+		if i >= w.headerRowIdx {
+			from, ok = -1, col < len(header)
+			if ok {
+				from, ok = w.columns[header[col]]
+			}
+		}
+		if !ok || w.converted[from] || from >= len(formats) {
+			cells[col] = cellOf(value)
+			continue
+		}
+		cells[col] = w.unchanged(value, formats[from])
+	}
+	return cells
+}
+
+// unchanged returns the cell to write for a value copied from src.
+func (w *cellWriter) unchanged(value string, src sourceCell) any {
+	var v any = value
+	switch src.kind {
+	case kindNumber:
+		if n, err := strconv.ParseFloat(src.raw, 64); err == nil {
+			v = n
+		} else {
+			v = cellOf(value)
+		}
+	case kindBool:
+		v = src.raw == "1"
+	}
+	if style := w.style(src.style); style != 0 {
+		return excelize.Cell{StyleID: style, Value: v}
+	}
+	return v
+}
+
+// style returns the output style of an input style.
+func (w *cellWriter) style(id int) int {
+	if id == 0 {
+		return 0
+	}
+	if to, ok := w.styles[id]; ok {
+		return to
+	}
+	to := 0
+	if style, err := w.from.GetStyle(id); err == nil {
+		to, _ = w.to.NewStyle(style)
+	}
+	w.styles[id] = to
+	return to
+}
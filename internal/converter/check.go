@@ -0,0 +1,251 @@
+package converter
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/csv"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// outputSamples is how many rows of an output are compared with the rows
+// written to it, spread evenly from the first to the last.
+const outputSamples = 20
+
+// diskPath returns where output written to sink is on disk, if it is.
+// Other outputs can't be read back.
+func diskPath(sink OutputSink) (string, bool) {
+	switch s := sink.(type) {
+	case LocalFile:
+		return string(s), true
+	case ReplaceFile:
+		return string(s), true
+	}
+	return "", false
+}
+
+// checksumWriter sums and counts the bytes written through it, to compare
+// with the output once it's read back.
+type checksumWriter struct {
+	w    io.Writer
+	sum  hash.Hash
+	size int64
+}
+
+func newChecksumWriter(w io.Writer) *checksumWriter {
+	return &checksumWriter{w: w, sum: sha256.New()}
+}
+
+func (c *checksumWriter) Write(b []byte) (int, error) {
+	n, err := c.w.Write(b)
+	c.sum.Write(b[:n])
+	c.size += int64(n)
+	return n, err
+}
+
+// readBack reads an output on disk and checks it's the bytes that were
+// written to it, so a write cut short, as on a full disk, is reported
+// rather than taken for a finished conversion. It's summed as it's read,
+// so outputs of any size are checked without holding them.
+func readBack(path string, written *checksumWriter) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("could not read back %s: %w", filepath.Base(path), err)
+	}
+	defer file.Close()
+
+	sum := sha256.New()
+	size, err := io.Copy(sum, file)
+	if err != nil {
+		return fmt.Errorf("could not read back %s: %w", filepath.Base(path), err)
+	}
+	if size != written.size {
+		return fmt.Errorf("%s is %d bytes but %d were written; the disk may be full", filepath.Base(path), size, written.size)
+	}
+	if !bytes.Equal(sum.Sum(nil), written.sum.Sum(nil)) {
+		return fmt.Errorf("%s doesn't match what was written to it", filepath.Base(path))
+	}
+	return nil
+}
+
+// sampleRows returns which of n rows to compare: the first, the last and
+// evenly spaced ones between them.
+func sampleRows(n int) map[int]bool {
+	rows := make(map[int]bool, outputSamples)
+	if n == 0 {
+		return rows
+	}
+	if n <= outputSamples {
+		for i := range n {
+			rows[i] = true
+		}
+		return rows
+	}
+	for i := range outputSamples {
+		rows[i*(n-1)/(outputSamples-1)] = true
+	}
+	return rows
+}
+
+// compareRow reports a sampled row of an output that reads back differently
+// from the row written, counting rows from 0.
+func compareRow(name string, row int, want, got []string) error {
+	for col := range max(len(want), len(got)) {
+		if cellAt(want, col) != cellAt(got, col) {
+			cell, _ := excelize.CoordinatesToCellName(col+1, row+1)
+			return fmt.Errorf("%s reads back %q in %s where %q was written", name, cellAt(got, col), cell, cellAt(want, col))
+		}
+	}
+	return nil
+}
+
+// checkCSVOutput reads back a CSV output on disk and checks it has the rows
+// written to it, comparing a sample of them cell by cell. The output is
+// read a record at a time rather than parsed whole.
+func checkCSVOutput(path string, written *checksumWriter, records [][]string, encoding string) error {
+	if err := readBack(path, written); err != nil {
+		return err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("could not read back %s: %w", filepath.Base(path), err)
+	}
+	defer file.Close()
+	decoded, err := decodingReader(file, encoding)
+	if err != nil {
+		return fmt.Errorf("could not decode %s: %w", filepath.Base(path), err)
+	}
+	reader := csv.NewReader(decoded)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+
+	// A record of one empty cell is written as a blank line, which isn't
+	// read as a record
+	blank := func(record []string) bool {
+		return len(record) == 1 && record[0] == ""
+	}
+	wanted := 0
+	for _, record := range records {
+		if !blank(record) {
+			wanted++
+		}
+	}
+
+	name := filepath.Base(path)
+	samples := sampleRows(wanted)
+	next, read := 0, 0
+	for {
+		got, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("could not read back %s: %w", name, err)
+		}
+		if read == 0 {
+			trimBOMs(got)
+		}
+		for next < len(records) && blank(records[next]) {
+			next++
+		}
+		if next < len(records) && samples[read] {
+			if err := compareRow(name, read, records[next], got); err != nil {
+				return err
+			}
+		}
+		next++
+		read++
+	}
+	if read != wanted {
+		return fmt.Errorf("%s has %d rows but %d were written", name, read, wanted)
+	}
+	return nil
+}
+
+// checkXLSXOutput reads back a workbook written to disk and checks each of
+// its sheets has the rows of f, comparing a sample of them cell by cell.
+// A low-memory workbook is opened with low memory too.
+func checkXLSXOutput(path string, written *checksumWriter, f *excelize.File, lowMemory bool) error {
+	if err := readBack(path, written); err != nil {
+		return err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("could not read back %s: %w", filepath.Base(path), err)
+	}
+	defer file.Close()
+
+	var got *excelize.File
+	if lowMemory {
+		got, err = openLowMemory(file)
+	} else {
+		got, err = excelize.OpenReader(file)
+	}
+	if err != nil {
+		return fmt.Errorf("could not read back %s: %w", filepath.Base(path), err)
+	}
+	defer got.Close()
+
+	name := filepath.Base(path)
+	for _, sheet := range f.GetSheetList() {
+		if err := checkSheet(name, sheet, f, got); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkSheet compares a sheet read back with the sheet written, a row at a
+// time so low-memory workbooks aren't held twice.
+func checkSheet(name, sheet string, f, got *excelize.File) error {
+	count, err := f.Rows(sheet)
+	if err != nil {
+		return err
+	}
+	written := 0
+	for count.Next() {
+		written++
+	}
+	if err := count.Close(); err != nil {
+		return err
+	}
+
+	want, err := f.Rows(sheet)
+	if err != nil {
+		return err
+	}
+	defer want.Close()
+	rows, err := got.Rows(sheet)
+	if err != nil {
+		return fmt.Errorf("could not read back %s: %w", name, err)
+	}
+	defer rows.Close()
+
+	samples := sampleRows(written)
+	read := 0
+	for rows.Next() {
+		if read < written && want.Next() && samples[read] {
+			wantRow, err := want.Columns()
+			if err != nil {
+				return err
+			}
+			gotRow, err := rows.Columns()
+			if err != nil {
+				return fmt.Errorf("could not read back %s: %w", name, err)
+			}
+			if err := compareRow(name+" "+sheet, read, wantRow, gotRow); err != nil {
+				return err
+			}
+		}
+		read++
+	}
+	if read != written {
+		return fmt.Errorf("%s has %d rows in %s but %d were written", name, read, sheet, written)
+	}
+	return nil
+}
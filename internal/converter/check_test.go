@@ -0,0 +1,97 @@
+package converter
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestCheckCSVOutput(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hours_converted.csv")
+	records := [][]string{{"Name", "Hours"}, {"Alice", "01:30"}, {"Bob", "02:15"}}
+	content := "Name,Hours\nAlice,01:30\nBob,02:15\n"
+
+	write := func(t *testing.T, data string) *checksumWriter {
+		t.Helper()
+		file, err := os.Create(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer file.Close()
+		sum := newChecksumWriter(file)
+		if _, err := sum.Write([]byte(data)); err != nil {
+			t.Fatal(err)
+		}
+		return sum
+	}
+
+	tests := []struct {
+		name    string
+		records [][]string
+		ondisk  string // Replaces the output after it's written, if set
+		wantErr string
+	}{
+		{"Matches", records, "", ""},
+		{"Cut short", records, "Name,Hours\nAlice,01:30\n", "the disk may be full"},
+		{"Changed", records, "Name,Hours\nAlice,01:30\nBob,02:14\n", "doesn't match"},
+		{"Missing row", append(records, []string{"Carol", "00:45"}), "", "has 3 rows but 4 were written"},
+		{"Different cell", [][]string{{"Name", "Hours"}, {"Alice", "01:30"}, {"Bob", "02:14"}}, "", `"02:15" in B3 where "02:14"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sum := write(t, content)
+			if tt.ondisk != "" {
+				if err := os.WriteFile(path, []byte(tt.ondisk), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err := checkCSVOutput(path, sum, tt.records, EncodingUTF8)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected the output to check out, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected an error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestCheckXLSXOutput(t *testing.T) {
+	f := excelize.NewFile()
+	defer f.Close()
+	sheet := f.GetSheetName(0)
+	for i, row := range [][]any{{"Name", "Hours"}, {"Alice", "01:30"}, {"Bob", "02:15"}} {
+		cell, _ := excelize.CoordinatesToCellName(1, i+1)
+		if err := f.SetSheetRow(sheet, cell, &row); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "hours_converted.xlsx")
+	if err := writeWorkbook(f, LocalFile(path), 0, false, nil); err != nil {
+		t.Fatalf("writeWorkbook failed: %v", err)
+	}
+
+	// A workbook that reads back with different rows than were written
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := newChecksumWriter(file)
+	if err := f.Write(sum); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+	f.SetCellValue(sheet, "A4", "Carol")
+	if err := checkXLSXOutput(path, sum, f, false); err == nil || !strings.Contains(err.Error(), "has 3 rows in Sheet1 but 4 were written") {
+		t.Errorf("Expected a missing row to be reported, got %v", err)
+	}
+}
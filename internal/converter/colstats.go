@@ -0,0 +1,89 @@
+package converter
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/nconklindev/chronos/internal/types"
+)
+
+// ColumnStats summarizes the values of one column, to tell hours from
+// rates, IDs and other numbers at a glance.
+type ColumnStats struct {
+	Values  int // Non-empty values
+	Blank   int // Empty values, including cells missing from short rows
+	Numeric int // Values that are numbers
+	// Min and Max are the smallest and largest numbers. They're only set
+	// when Numeric is.
+	Min, Max float64
+}
+
+// ComputeStats summarizes every column of a file's data rows, leaving out
+// the footer rows.
+func ComputeStats(data *types.FileData) []ColumnStats {
+	stats := make([]ColumnStats, len(data.Headers))
+	for _, row := range data.Rows[:max(len(data.Rows)-data.FooterRows, 0)] {
+		for i := range stats {
+			s := &stats[i]
+			val := ""
+			if i < len(row) {
+				val = strings.TrimSpace(row[i])
+			}
+			if val == "" {
+				s.Blank++
+				continue
+			}
+			s.Values++
+
+			n, err := ParseNumber(val, types.DecimalAuto)
+			if err != nil || math.IsNaN(n) || math.IsInf(n, 0) {
+				continue
+			}
+			if s.Numeric == 0 || n < s.Min {
+				s.Min = n
+			}
+			if s.Numeric == 0 || n > s.Max {
+				s.Max = n
+			}
+			s.Numeric++
+		}
+	}
+	return stats
+}
+
+// NumericPercent is the share of non-empty values that are numbers, from
+// 0 to 100.
+func (s ColumnStats) NumericPercent() int {
+	if s.Values == 0 {
+		return 0
+	}
+	return s.Numeric * 100 / s.Values
+}
+
+// String summarizes the stats in a line, such as "100% numeric • 0.5–12 •
+// 3 blank", with a single number when they all match.
+func (s ColumnStats) String() string {
+	if s.Values == 0 {
+		return "empty"
+	}
+
+	parts := []string{fmt.Sprintf("%d%% numeric", s.NumericPercent())}
+	switch {
+	case s.Numeric > 0 && s.Min == s.Max:
+		parts = append(parts, formatStat(s.Min))
+	case s.Numeric > 0:
+		parts = append(parts, formatStat(s.Min)+"–"+formatStat(s.Max))
+	}
+	if s.Blank > 0 {
+		parts = append(parts, fmt.Sprintf("%d blank", s.Blank))
+	}
+	return strings.Join(parts, " • ")
+}
+
+// formatStat writes a number to at most two decimal places, without
+// trailing zeros.
+func formatStat(n float64) string {
+	return strconv.FormatFloat(math.Round(n*100)/100, 'f', -1, 64)
+}
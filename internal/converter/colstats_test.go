@@ -0,0 +1,44 @@
+package converter
+
+import (
+	"testing"
+
+	"github.com/nconklindev/chronos/internal/types"
+)
+
+func TestComputeStats(t *testing.T) {
+	data := &types.FileData{
+		Headers: []string{"Employee ID", "Hours", "Rate", "Notes"},
+		Rows: [][]string{
+			{"10042", "8.0", "$25.00", ""},
+			{"10043", "7.25", "1,250.5", "late"},
+			{"10044", ""},
+			{"Total", "15.25", "", ""},
+		},
+		FooterRows: 1,
+	}
+
+	tests := []struct {
+		header   string
+		expected string
+	}{
+		{"Employee ID", "100% numeric • 10042–10044"},
+		{"Hours", "100% numeric • 7.25–8 • 1 blank"},
+		{"Rate", "50% numeric • 1250.5 • 1 blank"},
+		{"Notes", "0% numeric • 2 blank"},
+	}
+
+	stats := ComputeStats(data)
+	if len(stats) != len(tests) {
+		t.Fatalf("Expected %d stats, got %d", len(tests), len(stats))
+	}
+	for i, tt := range tests {
+		if got := stats[i].String(); got != tt.expected {
+			t.Errorf("%s: got %q; want %q", tt.header, got, tt.expected)
+		}
+	}
+
+	if got := (ColumnStats{Blank: 3}).String(); got != "empty" {
+		t.Errorf("Blank column: got %q; want %q", got, "empty")
+	}
+}
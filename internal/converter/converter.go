@@ -3,10 +3,17 @@ package converter
 import (
 	"encoding/csv"
 	"fmt"
+	"io"
+	"log/slog"
+	"math"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
+	"unicode"
 
 	"github.com/nconklindev/chronos/internal/types"
 
@@ -17,79 +24,499 @@ const RowDetectionLimit = 10
 
 // DecimalToTime converts decimal hours to hh:mm format
 func DecimalToTime(decimal float64) string {
-	if decimal < 0 {
-		return "00:00"
+	return ConvertValue(decimal, types.ColumnSettings{})
+}
+
+// unitHours is how many hours one of each unit is.
+var unitHours = map[types.Unit]float64{
+	types.UnitHours:     1,
+	types.UnitMinutes:   1.0 / 60,
+	types.UnitSeconds:   1.0 / 3600,
+	types.UnitDays:      24,
+	types.UnitWeeks:     24 * 7,
+	types.UnitExcelTime: 24,
+}
+
+// payrollIncrements are the payroll roundings: values snap to a multiple of
+// step minutes, rounding up once they're upFrom minutes past one.
+var payrollIncrements = map[types.Rounding]struct{ step, upFrom int64 }{
+	types.RoundQuarter: {15, 8},
+	types.RoundTenth:   {6, 3},
+}
+
+// maxHours is the most hours ConvertValue counts. The seconds in many more
+// wouldn't fit in an int64.
+const maxHours = 1e15
+
+// ConvertValue converts a number counting the column's unit to its format.
+// Negative values and NaN convert to zero, and values of more than
+// maxHours hours to maxHours.
+func ConvertValue(value float64, s types.ColumnSettings) string {
+	decimal := value
+	if factor, ok := unitHours[s.Unit]; ok {
+		decimal = value * factor
+	}
+	if !(decimal > 0) {
+		decimal = 0
 	}
+	decimal = min(decimal, maxHours)
 
-	hours := int(decimal)
-	minutesDecimal := (decimal - float64(hours)) * 60
-	minutes := int(minutesDecimal + 0.5) // Round to nearest minute
+	// Count in the smallest unit shown, rounding only the part of an hour
+	// so whole hours never pick up floating point error
+	perHour := 60.0
+	if ShowsSeconds(s) {
+		perHour = 3600
+	}
+	hours := math.Floor(decimal)
+	part := (decimal - hours) * perHour
+	switch s.Rounding {
+	case types.RoundDown:
+		part = math.Floor(part + 1e-9)
+	case types.RoundUp:
+		part = math.Ceil(part - 1e-9)
+	case types.RoundQuarter, types.RoundTenth:
+		// The rules count whole minutes, so round to the minute first
+		inc := payrollIncrements[s.Rounding]
+		minutes := int64(math.Floor((decimal-hours)*60 + 0.5))
+		if past := minutes % inc.step; past >= inc.upFrom {
+			minutes += inc.step - past
+		} else {
+			minutes -= past
+		}
+		part = float64(minutes) * perHour / 60
+	default:
+		part = math.Floor(part + 0.5)
+	}
+	total := int64(hours)*int64(perHour) + int64(part)
 
-	// Handle case where rounding minutes reaches 60
-	if minutes >= 60 {
-		hours++
-		minutes -= 60
+	// Invalid patterns fall back to the format
+	if s.Pattern != "" {
+		if parts, err := parsePattern(s.Pattern); err == nil {
+			return formatPattern(total, parts)
+		}
 	}
 
-	return fmt.Sprintf("%02d:%02d", hours, minutes)
+	switch s.Format {
+	case types.FormatHMM:
+		return fmt.Sprintf("%d:%02d", total/60, total%60)
+	case types.FormatHHMMSS:
+		return fmt.Sprintf("%02d:%02d:%02d", total/3600, total/60%60, total%60)
+	case types.FormatMinutes:
+		return strconv.FormatInt(total, 10)
+	default:
+		return fmt.Sprintf("%02d:%02d", total/60, total%60)
+	}
 }
 
 // IsDecimalHour checks if a string looks like a decimal hour value
 func IsDecimalHour(s string) bool {
-	s = strings.TrimSpace(s)
-	if s == "" {
-		return false
+	ok, _ := checkDecimalHour(s)
+	return ok
+}
+
+// AutoDetectColumns identifies columns that contain decimal hour values.
+// TraceDetection explains each decision.
+func AutoDetectColumns(data *types.FileData) []int {
+	var detectedIndices []int
+	for _, trace := range TraceDetection(data) {
+		slog.Debug("column detection", "column", trace.Header, "index", trace.Index, "detected", trace.Detected, "reason", trace.Reason)
+		if trace.Detected {
+			detectedIndices = append(detectedIndices, trace.Index)
+		}
 	}
+	return detectedIndices
+}
+
+// footerKeywords are leading words that mark a summary row at the end of an export
+var footerKeywords = map[string]bool{
+	"total":     true,
+	"totals":    true,
+	"subtotal":  true,
+	"subtotals": true,
+	"grand":     true,
+	"sum":       true,
+}
 
-	val, err := strconv.ParseFloat(s, 64)
+// DetectFooterRows counts the trailing rows that look like totals or blank trailers.
+// Detection stops at the first row from the bottom that looks like regular data.
+func DetectFooterRows(rows [][]string) int {
+	count := 0
+
+	for i := len(rows) - 1; i >= 0 && count < RowDetectionLimit; i-- {
+		if !isFooterRow(rows[i]) {
+			break
+		}
+		count++
+	}
+
+	// Never treat every row as a footer
+	if count == len(rows) {
+		return 0
+	}
+
+	return count
+}
+
+// isFooterRow reports whether a row is blank or starts with a totals label
+func isFooterRow(row []string) bool {
+	for _, cell := range row {
+		trimmed := strings.ToLower(strings.TrimSpace(cell))
+		if trimmed == "" {
+			continue
+		}
+
+		// The first word of the first non-empty cell decides
+		firstWord := strings.FieldsFunc(trimmed, func(r rune) bool {
+			return !unicode.IsLetter(r)
+		})
+		return len(firstWord) > 0 && footerKeywords[firstWord[0]]
+	}
+
+	return true
+}
+
+// footerStart returns the index of the first footer row in a slice of n data rows
+func footerStart(n int, opts types.ConversionOptions) int {
+	footer := opts.FooterRows
+	if footer < 0 {
+		footer = 0
+	}
+	if footer > n {
+		footer = n
+	}
+	return n - footer
+}
+
+// ConvertCell converts a single cell with its column's transformer,
+// keeping only the regular part of split columns. ok is false for
+// non-empty values the transformer rejects, such as text in a duration
+// column, which are returned unchanged.
+func ConvertCell(cell string, s types.ColumnSettings) (string, bool) {
+	if strings.TrimSpace(cell) == "" {
+		return "", true
+	}
+
+	t, ok := LookupTransformer(s.Transformer)
+	if !ok {
+		return cell, false
+	}
+	converted, err := t.Transform(cell, s)
 	if err != nil {
-		return false
+		return cell, false
 	}
+	return converted, true
+}
 
-	return val >= 0 && val < 10000
+// outputCell converts a data cell of a column with settings s as
+// ConvertCell does, writing empty cells and zero values as the options'
+// blank policy says.
+func outputCell(cell string, s types.ColumnSettings, opts types.ConversionOptions) (string, bool) {
+	converted, ok := ConvertCell(cell, s)
+	if !ok {
+		return converted, false
+	}
+	return applyBlanks(converted, s, opts.Blanks), true
 }
 
-// AutoDetectColumns identifies columns that contain decimal hour values
-func AutoDetectColumns(data *types.FileData) []int {
-	var detectedIndices []int
+// applyBlanks writes an empty or zero value converted with s as policy says.
+func applyBlanks(converted string, s types.ColumnSettings, policy types.BlankPolicy) string {
+	switch {
+	case policy == types.BlankAsZero && converted == "":
+		return zeroCell(s)
+	case policy == types.ZeroAsBlank && converted == zeroCell(s):
+		return ""
+	}
+	return converted
+}
 
-	for i := range data.Headers {
-		hasDecimalHours := true
-		checkedRows := 0
-
-		// Check first 10 data rows
-		for j := 0; j < len(data.Rows) && j < RowDetectionLimit; j++ {
-			if i < len(data.Rows[j]) {
-				val := strings.TrimSpace(data.Rows[j][i])
-				if val != "" {
-					if !IsDecimalHour(val) {
-						hasDecimalHours = false
-						break
-					}
-					checkedRows++
-				}
-			}
+// zeroCell is what a column with settings s converts zero to, such as
+// 00:00, leaving out its expression.
+func zeroCell(s types.ColumnSettings) string {
+	s.Expression = ""
+	zero, _ := ConvertCell("0", s)
+	return zero
+}
+
+// DefaultLossThreshold is how many minutes rounding may lose from a cell
+// before it's reported, unless the options give another threshold.
+const DefaultLossThreshold = 0.5
+
+// lossThreshold returns the loss threshold the options give, or the default.
+func lossThreshold(opts types.ConversionOptions) float64 {
+	if opts.LossThreshold > 0 {
+		return opts.LossThreshold
+	}
+	return DefaultLossThreshold
+}
+
+// ParseConverted reads a value converted with s back into hours. ok is
+// false for values that aren't in the settings' format.
+func ParseConverted(value string, s types.ColumnSettings) (float64, bool) {
+	value = strings.TrimSpace(value)
+	if s.Pattern != "" && CheckPattern(s.Pattern) == nil {
+		return parsePatternValue(value, s.Pattern)
+	}
+	if s.Format == types.FormatMinutes {
+		minutes, err := strconv.ParseFloat(value, 64)
+		return minutes / 60, err == nil
+	}
+
+	parts := strings.Split(value, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, false
+	}
+	hours := 0.0
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return 0, false
 		}
+		hours += float64(n) / math.Pow(60, float64(i))
+	}
+	return hours, true
+}
+
+// markLosses works out how much rounding lost from each change, and the
+// hours each holds. The changes' columns must match the settings'
+// numbering in opts.
+func markLosses(changes []types.CellChange, opts types.ConversionOptions) {
+	for i, change := range changes {
+		s := opts.Columns[change.Col]
+		if !IsDuration(s) {
+			continue
+		}
+		// Losses are measured from the value the expression gives, since
+		// the expression changes it on purpose
+		original, err := CellValue(change.Original, s)
+		if err != nil {
+			continue
+		}
+		converted, ok := ParseConverted(change.Converted, s)
+		if !ok {
+			continue
+		}
+		// The overtime column holds the rest of a split value
+		if overtime, ok := ParseConverted(overtimeCell(change.Original, s), s); ok && splits(change.Col, opts) {
+			converted += overtime
+		}
+		changes[i].Hours, changes[i].HasHours = converted, true
+		if factor, ok := unitHours[s.Unit]; ok {
+			original *= factor
+		}
+		changes[i].LostMinutes = (original - converted) * 60
+	}
+}
 
-		if hasDecimalHours && checkedRows > 0 {
-			detectedIndices = append(detectedIndices, i)
+// lossyChanges returns the changes that lost more than the threshold to rounding.
+func lossyChanges(changes []types.CellChange, opts types.ConversionOptions) []types.CellChange {
+	threshold := lossThreshold(opts)
+	var lossy []types.CellChange
+	for _, change := range changes {
+		// Allow for floating point error right at the threshold
+		if math.Abs(change.LostMinutes) > threshold+1e-9 {
+			lossy = append(lossy, change)
 		}
 	}
+	return lossy
+}
 
-	return detectedIndices
+// changeLog gathers the cells a conversion changed. Every change is only
+// kept when the options record them, since large files change millions of
+// cells; otherwise they're counted and their hours totalled as they come.
+type changeLog struct {
+	record  bool
+	count   int
+	changes []types.CellChange
+	lossy   []types.CellChange
+	flagged []types.CellChange
+	hours   []types.ColumnHours
+	hourCol []int // The input column of each of hours
+}
+
+// add logs changes whose columns are numbered as the settings in opts
+// are, renumbering them to the input's columns from kept when columns were
+// rearranged. It returns the changes that were flagged.
+func (l *changeLog) add(changes []types.CellChange, kept []int, opts types.ConversionOptions) []types.CellChange {
+	if len(changes) == 0 {
+		return nil
+	}
+	markLosses(changes, opts)
+	flagged := flaggedChanges(changes, opts)
+	if kept != nil {
+		restoreChangeColumns(changes, kept)
+		restoreChangeColumns(flagged, kept)
+	}
+
+	l.count += len(changes)
+	l.lossy = append(l.lossy, lossyChanges(changes, opts)...)
+	l.flagged = append(l.flagged, flagged...)
+	for _, change := range changes {
+		if change.HasHours {
+			l.addHours(change)
+		}
+	}
+	if l.record {
+		l.changes = append(l.changes, changes...)
+	}
+	return flagged
+}
+
+// addHours adds the hours of a change to its column's total, keeping the
+// totals in column order.
+func (l *changeLog) addHours(change types.CellChange) {
+	i := slices.IndexFunc(l.hours, func(h types.ColumnHours) bool { return h.Column == change.Column })
+	if i < 0 {
+		i, _ = slices.BinarySearch(l.hourCol, change.Col)
+		l.hours = slices.Insert(l.hours, i, types.ColumnHours{Column: change.Column})
+		l.hourCol = slices.Insert(l.hourCol, i, change.Col)
+	}
+	l.hours[i].Cells++
+	l.hours[i].Hours += change.Hours
+}
+
+// sort orders what was logged by row, then column.
+func (l *changeLog) sort() {
+	l.changes = sortChanges(l.changes)
+	l.lossy = sortChanges(l.lossy)
+	l.flagged = sortChanges(l.flagged)
+}
+
+// unconvertedCells counts the cells of converted columns left as they were,
+// telling those written like 1,250 apart so the warning can say why.
+type unconvertedCells struct {
+	count     int
+	ambiguous int
+}
+
+// add counts a cell that couldn't be converted with settings s.
+func (u *unconvertedCells) add(cell string, s types.ColumnSettings) {
+	if ambiguousCell(cell, s) {
+		u.ambiguous++
+	} else {
+		u.count++
+	}
+}
+
+// conversionWarnings builds the warnings reported alongside a result
+func conversionWarnings(unconverted unconvertedCells, paddedRows int, lossy, flagged []types.CellChange, opts types.ConversionOptions) []string {
+	var warnings []string
+	if len(flagged) > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d cell(s) over %s hours were flagged", len(flagged), strconv.FormatFloat(opts.FlagAbove, 'f', -1, 64)))
+	}
+	if len(lossy) > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d cell(s) lost more than %s minute(s) to rounding", len(lossy), strconv.FormatFloat(lossThreshold(opts), 'f', -1, 64)))
+	}
+	if unconverted.ambiguous > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d cell(s) such as 1,250 could have a decimal comma or a thousands separator and were left unchanged; set the column's decimal separator to convert them", unconverted.ambiguous))
+	}
+	if unconverted.count > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d non-numeric cell(s) in converted columns were left unchanged", unconverted.count))
+	}
+	if paddedRows > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d row(s) had fewer fields than the header and were padded", paddedRows))
+	}
+	return warnings
+}
+
+// MatchesFilters reports whether a row passes every row filter
+func MatchesFilters(row []string, filters []types.RowFilter) bool {
+	for _, filter := range filters {
+		val := ""
+		if filter.Column >= 0 && filter.Column < len(row) {
+			val = strings.TrimSpace(row[filter.Column])
+		}
+
+		switch filter.Op {
+		case types.FilterNonEmpty:
+			if val == "" {
+				return false
+			}
+		case types.FilterEquals:
+			if !strings.EqualFold(val, strings.TrimSpace(filter.Value)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// DefaultHeaderSuffix names the converted copy of a column unless the
+// options give another suffix.
+const DefaultHeaderSuffix = " (HH:MM)"
+
+// ConvertedHeader returns the header of converted column col, whose own
+// header is header: its copy's when the original columns are kept, or its
+// own unless the column was renamed. Split columns are named for their
+// regular part.
+func ConvertedHeader(header string, col int, opts types.ConversionOptions) string {
+	if splits(col, opts) {
+		regular, _ := splitHeaders(header, col, opts, false)
+		return regular
+	}
+	if name := opts.Columns[col].Header; name != "" {
+		return name
+	}
+	if !opts.KeepOriginal {
+		return header
+	}
+	if opts.HeaderSuffix != "" {
+		return header + opts.HeaderSuffix
+	}
+	return header + DefaultHeaderSuffix
+}
+
+// Convert converts a CSV, XLSX or JSON file based on its extension, reporting
+// its progress to progress, which may be nil
+func Convert(inputFile string, sink OutputSink, columnIndices []int, opts types.ConversionOptions, progress ProgressReporter) (*types.ConversionResult, error) {
+	start := time.Now()
+	result, err := convert(inputFile, sink, columnIndices, opts, progress)
+	if err != nil {
+		slog.Debug("conversion failed", "file", inputFile, "columns", columnIndices, "elapsed", time.Since(start), "error", err)
+		return nil, err
+	}
+	slog.Debug("converted file", "file", inputFile, "output", result.OutputFile, "columns", columnIndices,
+		"rows", result.RowsProcessed, "cells", result.CellsChanged, "warnings", len(result.Warnings), "elapsed", time.Since(start))
+	return result, nil
+}
+
+func convert(inputFile string, sink OutputSink, columnIndices []int, opts types.ConversionOptions, progress ProgressReporter) (*types.ConversionResult, error) {
+	if opts.Markup != types.MarkupNone {
+		return ConvertMarkup(inputFile, sink, columnIndices, opts, progress)
+	}
+	if opts.Parquet {
+		return ConvertParquet(inputFile, sink, columnIndices, opts, progress)
+	}
+	ext := strings.ToLower(filepath.Ext(inputFile))
+
+	switch ext {
+	case ".csv":
+		return ConvertCSV(inputFile, sink, columnIndices, opts, progress)
+	case ".xlsx":
+		return ConvertXLSX(inputFile, sink, columnIndices, opts, progress)
+	case ".json", ".ndjson":
+		return ConvertJSON(inputFile, sink, columnIndices, opts, progress)
+	default:
+		return nil, fmt.Errorf("unsupported file type: %s", ext)
+	}
 }
 
 // ConvertCSV processes a CSV file and converts specified columns
-func ConvertCSV(inputFile, outputFile string, columnIndices []int, keepOriginal bool, progressChan chan<- float64) (*types.ConversionResult, error) {
-	// Read input file
-	inFile, err := os.Open(inputFile)
+func ConvertCSV(inputFile string, sink OutputSink, columnIndices []int, opts types.ConversionOptions, progress ProgressReporter) (*types.ConversionResult, error) {
+	// Read input file, transcoding it to UTF-8 if needed
+	inText, inputEncoding, err := readTextFile(inputFile)
 	if err != nil {
 		return nil, err
 	}
-	defer inFile.Close()
 
-	reader := csv.NewReader(inFile)
-	records, err := reader.ReadAll()
+	inSize := inText.Size()
+	style := outputLineStyle(detectLineStyle(inText, inSize), opts)
+	var sourceQuoted [][]bool
+	if opts.Quoting == types.QuotingPreserve {
+		text := make([]byte, inSize)
+		n, _ := inText.ReadAt(text, 0)
+		sourceQuoted = quotedCells(text[:n])
+	}
+	records, paddedRows, err := readCSVRecords(newProgressReader(inText, inSize, PhaseRead, progress))
 	if err != nil {
 		return nil, err
 	}
@@ -98,115 +525,558 @@ func ConvertCSV(inputFile, outputFile string, columnIndices []int, keepOriginal
 		return nil, fmt.Errorf("empty CSV file")
 	}
 
-	headers := records[0]
-	colMap := make(map[int]bool)
-	var convertedCols []string
+	// Converting may rename and move the header's columns
+	header := append([]string(nil), records[0]...)
+	converted := convertRecords(records, columnIndices, opts, progress)
+	quotes := quotingFor(opts, header, sourceQuoted, converted.records)
 
-	for _, idx := range columnIndices {
-		if idx >= 0 && idx < len(headers) {
-			colMap[idx] = true
-			convertedCols = append(convertedCols, headers[idx])
+	// Write output file
+	outFile, err := sink.Create()
+	if err != nil {
+		return nil, err
+	}
+	defer outFile.Close()
+
+	outputEncoding := opts.OutputEncoding
+	if outputEncoding == "" {
+		outputEncoding = inputEncoding
+	}
+	sum := newChecksumWriter(outFile)
+	written := newProgressWriter(sum, inSize, PhaseWrite, progress)
+	out, err := newEncodedWriter(written, outputEncoding)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := writeCSV(out, converted.records, style, quotes); err != nil {
+		return nil, err
+	}
+	if err := out.Close(); err != nil {
+		return nil, err
+	}
+	if err := outFile.Close(); err != nil {
+		return nil, err
+	}
+	if path, ok := diskPath(sink); ok {
+		if err := checkCSVOutput(path, sum, converted.records, outputEncoding); err != nil {
+			return nil, err
 		}
 	}
+	written.finish()
 
-	// We need to reconstruct the records with new columns if keepOriginal is true
-	var newRecords [][]string
+	var totalsFile string
+	var totalsWarning []string
+	if converted.totals != nil {
+		if totalsFile, totalsWarning, err = writeCSVTotals(sink, converted.totals, outputEncoding, style, quotes); err != nil {
+			return nil, err
+		}
+	}
 
-	totalRows := len(records)
-	// If keepOriginal, we iterate through all records.
-	// If not, we iterate from index 1.
-	if keepOriginal {
-		for i, record := range records {
-			// Report progress
-			if progressChan != nil {
-				select {
-				case progressChan <- float64(i) / float64(totalRows):
-				default:
-				}
+	return &types.ConversionResult{
+		InputFile:     inputFile,
+		OutputFile:    sink.Location(),
+		ColumnsFound:  converted.columns,
+		RowsProcessed: converted.rowsProcessed,
+		Warnings:      append(converted.warnings(paddedRows, opts), totalsWarning...),
+		Changes:       converted.changes,
+		CellsChanged:  converted.count,
+		Hours:         converted.hours,
+		Lossy:         converted.lossy,
+		Flagged:       converted.flagged,
+		TotalsFile:    totalsFile,
+	}, nil
+}
+
+// convertedRecords is the outcome of converting parsed CSV records
+type convertedRecords struct {
+	records       [][]string
+	columns       []string
+	rowsProcessed int
+	unconverted   unconvertedCells
+	changeLog
+	invalidDurations int
+	totals           [][]string // Nil without opts.Totals
+	untotalled       int
+	roles            []columnRole // What each column holds, as laid out in the header
+}
+
+// warnings builds the warnings reported alongside the converted records.
+func (c convertedRecords) warnings(paddedRows int, opts types.ConversionOptions) []string {
+	warnings := conversionWarnings(c.unconverted, paddedRows, c.lossy, c.flagged, opts)
+	warnings = append(warnings, durationWarnings(c.invalidDurations)...)
+	return append(warnings, totalsWarnings(c.untotalled)...)
+}
+
+// convertRecords converts the selected columns of records, whose first row is
+// the header. Files and pasted tables share it so they behave the same.
+func convertRecords(records [][]string, columnIndices []int, opts types.ConversionOptions, progress ProgressReporter) convertedRecords {
+	end := len(records)
+	if opts.DropFooter {
+		end = footerStart(len(records)-1, opts) + 1
+	}
+	widest := 0
+	for _, record := range records[:end] {
+		widest = max(widest, len(record))
+	}
+
+	c := newRecordConverter(records[0], len(records), widest, columnIndices, opts, progress)
+	converted := make([][]string, 0, end)
+	for i, record := range records {
+		if row, _, ok := c.convert(i, record); ok {
+			converted = append(converted, row)
+		}
+	}
+	return c.finish(converted)
+}
+
+// recordConverter converts records a row at a time, the header first, so
+// rows can be written as they're converted rather than held.
+type recordConverter struct {
+	input       types.ConversionOptions // As given, numbered as rows are read
+	opts        types.ConversionOptions // Renumbered for the columns kept
+	progress    ProgressReporter
+	width       int      // The header's width as read
+	kept        []int    // The columns written, in order, when they're rearranged
+	headers     []string // The header once rearranged, before it's renamed
+	cols        []int
+	colMap      map[int]bool
+	several     bool
+	split       bool // Whether overtime columns follow split columns in place
+	firstFooter int  // The index of the first footer row, counting the header
+	total       int  // How many records are converted or passed through
+	flagWidth   int  // How wide rows are padded before the flag column
+	totals      *totaller
+	result      convertedRecords
+	changes     []types.CellChange // Reused by each row, since most are only counted
+}
+
+// newRecordConverter prepares to convert rows records, the header among
+// them, whose widest row kept is widest cells wide.
+func newRecordConverter(header []string, rows, widest int, columnIndices []int, opts types.ConversionOptions, progress ProgressReporter) *recordConverter {
+	c := &recordConverter{
+		input:       opts,
+		progress:    progress,
+		width:       len(header),
+		firstFooter: footerStart(rows-1, opts) + 1,
+		total:       rows,
+	}
+	if opts.DropFooter {
+		c.total = c.firstFooter
+	}
+
+	// Computed durations come first, as if they'd been read
+	headers := c.withDurations(0, header)
+	if opts.Totals != nil {
+		c.totals = newTotaller(headers, columnIndices, opts)
+	}
+
+	// Filters match the rows as read, before any columns are moved or dropped
+	if rearranged(opts) {
+		c.kept = outputColumns(len(headers), columnIndices, opts)
+		columnIndices, opts = renumberOptions(c.kept, columnIndices, opts)
+	}
+	c.opts = opts
+	c.result.record = opts.RecordChanges
+	c.headers = c.arrange(headers)
+
+	c.cols = convertedColumns(columnIndices, len(c.headers))
+	c.colMap = make(map[int]bool, len(c.cols))
+	for _, idx := range c.cols {
+		c.colMap[idx] = true
+		c.result.columns = append(c.result.columns, c.headers[idx])
+		c.split = c.split || !opts.KeepOriginal && splits(idx, opts)
+	}
+	c.several = splitCount(c.colMap, opts) > 1
+
+	// Rows are padded to the widest before the flag column
+	if opts.FlagAbove > 0 {
+		blank := make([]string, max(widest, len(header)))
+		if len(opts.Durations) > 0 {
+			blank = insertCells(blank, c.width, make([]string, len(opts.Durations)))
+		}
+		blank, _ = c.convertRow(1, c.arrange(blank), true)
+		c.flagWidth = len(blank)
+	}
+	return c
+}
+
+// withDurations returns record i with its computed durations, the header
+// getting their headers and footer rows empty cells.
+func (c *recordConverter) withDurations(i int, record []string) []string {
+	durations := c.input.Durations
+	if len(durations) == 0 {
+		return record
+	}
+	cells := make([]string, len(durations))
+	switch {
+	case i == 0:
+		for j, pair := range durations {
+			cells[j] = pair.Header
+		}
+	case i < c.firstFooter:
+		cells = durationCells(record, durations, c.input, &c.result.invalidDurations)
+	}
+	return insertCells(record, c.width, cells)
+}
+
+// arrange moves and drops the columns of a record as the options say.
+func (c *recordConverter) arrange(record []string) []string {
+	if c.kept == nil {
+		return record
+	}
+	width := c.width + len(c.opts.Durations)
+	arranged := projectRow(record, c.kept)
+	// Stray cells past the header stay at the end
+	if !c.opts.OnlySelected && len(record) > width {
+		arranged = append(arranged, record[width:]...)
+	}
+	return arranged
+}
+
+// convert converts record i, the header being record 0, returning the
+// cells it changed numbered by the input's columns, which are only good
+// until the next record is converted. ok is false for footer rows that
+// are dropped.
+func (c *recordConverter) convert(i int, record []string) (row []string, changes []types.CellChange, ok bool) {
+	if i >= c.firstFooter && c.opts.DropFooter {
+		return nil, nil, false
+	}
+	report(c.progress, PhaseConvert, i, c.total)
+
+	source := c.withDurations(i, record)
+	skip := false
+	if i > 0 {
+		// Footer rows are passed through untouched, and so are rows the
+		// row filters reject
+		switch {
+		case i >= c.firstFooter:
+			skip = true
+		case !MatchesFilters(source, c.input.Filters):
+			skip = true
+		default:
+			c.result.rowsProcessed++
+			if c.totals != nil {
+				c.totals.add(source)
 			}
+		}
+	}
 
-			var newRow []string
-			for colIdx, cell := range record {
-				newRow = append(newRow, cell)
-				if colMap[colIdx] {
-					// This is a column we are converting.
-					// If it's the header row (i==0), append the new header
-					if i == 0 {
-						newRow = append(newRow, cell+" (HH:MM)")
-					} else {
-						// It's a data row. Calculate the converted value.
-						val := strings.TrimSpace(cell)
-						convertedVal := ""
-						if val != "" {
-							if decimal, err := strconv.ParseFloat(val, 64); err == nil {
-								convertedVal = DecimalToTime(decimal)
-							}
-						}
-						newRow = append(newRow, convertedVal)
-					}
+	row, changes = c.convertRow(i, c.arrange(source), skip)
+	c.changes = changes
+	flagged := c.result.add(changes, c.kept, c.opts)
+	if c.opts.FlagAbove > 0 {
+		for len(row) < c.flagWidth {
+			row = append(row, "")
+		}
+		var columns []string
+		for _, change := range flagged {
+			columns = append(columns, change.Column)
+		}
+		value := strings.Join(columns, "; ")
+		if i == 0 {
+			value = FlagHeader(c.opts)
+		}
+		row = append(row, value)
+	}
+	return row, changes, true
+}
+
+// convertRow converts the selected columns of a row laid out as the
+// header is, returning the changes numbered by the settings. Skipped rows,
+// such as footers, keep their cells, with blanks in any new columns.
+func (c *recordConverter) convertRow(i int, record []string, skip bool) ([]string, []types.CellChange) {
+	headers, opts := c.headers, c.opts
+	changes := c.changes[:0]
+	if opts.KeepOriginal {
+		// The converted copies of each converted column in this row,
+		// two for split columns
+		copies := make(map[int][]string)
+		for colIdx, cell := range record {
+			if !c.colMap[colIdx] {
+				continue
+			}
+			split := splits(colIdx, opts)
+			switch {
+			case i == 0:
+				if split {
+					regular, overtime := splitHeaders(cell, colIdx, opts, c.several)
+					copies[colIdx] = []string{regular, overtime}
+				} else {
+					copies[colIdx] = []string{ConvertedHeader(cell, colIdx, opts)}
+				}
+			case skip:
+				// Keep footer and filtered rows aligned without converting them
+				copies[colIdx] = emptyCopies(split)
+			default:
+				// Non-numeric cells in converted columns are left alone and reported
+				convertedVal, ok := outputCell(cell, opts.Columns[colIdx], opts)
+				if !ok {
+					c.result.unconverted.add(cell, opts.Columns[colIdx])
+					copies[colIdx] = emptyCopies(split)
+					continue
+				}
+				if convertedVal != "" || strings.TrimSpace(cell) != "" {
+					changes = append(changes, types.CellChange{Row: i + 1, Col: colIdx, Column: headers[colIdx], Original: cell, Converted: convertedVal})
+				}
+				copies[colIdx] = []string{convertedVal}
+				if split {
+					copies[colIdx] = append(copies[colIdx], applyBlanks(overtimeCell(cell, opts.Columns[colIdx]), opts.Columns[colIdx], opts.Blanks))
 				}
 			}
-			newRecords = append(newRecords, newRow)
 		}
-		records = newRecords
-	} else {
-		// replace in place
-		for i := 1; i < len(records); i++ {
-			// Report progress
-			if progressChan != nil {
-				select {
-				case progressChan <- float64(i) / float64(totalRows):
-				default:
-				}
+		return placeCopies(record, copies, len(headers), opts.Placement), changes
+	}
+
+	// Renamed columns are renamed in place too, and split columns get an
+	// overtime column after them
+	overtime := make(map[int][]string)
+	if i == 0 {
+		record = append([]string(nil), record...)
+		for _, colIdx := range c.cols {
+			if splits(colIdx, opts) {
+				var name string
+				record[colIdx], name = splitHeaders(headers[colIdx], colIdx, opts, c.several)
+				overtime[colIdx] = []string{name}
+			} else {
+				record[colIdx] = ConvertedHeader(headers[colIdx], colIdx, opts)
+			}
+		}
+	} else if !skip {
+		for _, colIdx := range c.cols {
+			if colIdx >= len(record) {
+				continue
+			}
+			original := record[colIdx]
+			convertedVal, ok := outputCell(original, opts.Columns[colIdx], opts)
+			if !ok {
+				c.result.unconverted.add(original, opts.Columns[colIdx])
+				continue
+			}
+			if convertedVal != original {
+				changes = append(changes, types.CellChange{Row: i + 1, Col: colIdx, Column: headers[colIdx], Original: original, Converted: convertedVal})
+			}
+			record[colIdx] = convertedVal
+			if splits(colIdx, opts) {
+				overtime[colIdx] = []string{applyBlanks(overtimeCell(original, opts.Columns[colIdx]), opts.Columns[colIdx], opts.Blanks)}
 			}
+		}
+	}
 
-			for colIdx := range colMap {
-				if colIdx < len(records[i]) {
-					val := strings.TrimSpace(records[i][colIdx])
-					if val != "" {
-						if decimal, err := strconv.ParseFloat(val, 64); err == nil {
-							records[i][colIdx] = DecimalToTime(decimal)
-						}
-					}
-				}
+	if !c.split {
+		return record, changes
+	}
+	// Rows that weren't converted still need a cell under the overtime header
+	for _, colIdx := range c.cols {
+		if _, ok := overtime[colIdx]; !ok && splits(colIdx, opts) {
+			overtime[colIdx] = []string{""}
+		}
+	}
+	return placeCopies(record, overtime, len(headers), types.PlacementAdjacent), changes
+}
+
+// finish returns the outcome of the conversion, with the converted
+// records when they were kept.
+func (c *recordConverter) finish(records [][]string) convertedRecords {
+	report(c.progress, PhaseConvert, c.total, c.total)
+	result := c.result
+	result.records = records
+	result.roles = outputRoles(len(c.headers), c.cols, c.opts)
+	if c.opts.FlagAbove > 0 {
+		result.roles = append(result.roles, columnRole{})
+	}
+	if c.totals != nil {
+		result.totals, result.untotalled = c.totals.table(), c.totals.untotalled
+	}
+	result.sort()
+	return result
+}
+
+// columnRole is what a column of converted records holds.
+type columnRole struct {
+	converted bool // Converted values, or the overtime part of them
+	original  bool // The original values of a column converted alongside them
+	settings  types.ColumnSettings
+}
+
+// outputRoles lays out what each column of the converted records holds,
+// the way the header of width columns is laid out, given the columns
+// converted.
+func outputRoles(width int, cols []int, opts types.ConversionOptions) []columnRole {
+	record := make([]columnRole, width)
+	copies := make(map[int][]columnRole)
+	for _, idx := range cols {
+		s := opts.Columns[idx]
+		converted := columnRole{converted: true, settings: s}
+		switch {
+		case opts.KeepOriginal:
+			record[idx] = columnRole{original: true, settings: s}
+			copies[idx] = []columnRole{converted}
+			if splits(idx, opts) {
+				copies[idx] = append(copies[idx], converted)
 			}
+		case splits(idx, opts):
+			record[idx] = converted
+			copies[idx] = []columnRole{converted}
+		default:
+			record[idx] = converted
 		}
 	}
 
-	// Count processed rows (excluding header)
-	rowsProcessed := len(records) - 1
+	placement := opts.Placement
+	if !opts.KeepOriginal {
+		// Overtime columns follow their split columns
+		placement = types.PlacementAdjacent
+	}
+	return placeCopies(record, copies, width, placement)
+}
 
-	// Write output file
-	outFile, err := os.Create(outputFile)
-	if err != nil {
-		return nil, err
+// convertedColumns returns the columns of a header width columns wide to
+// convert, left to right and each once, so they're processed and reported
+// in the same order however they were chosen.
+func convertedColumns(columnIndices []int, width int) []int {
+	seen := make(map[int]bool, len(columnIndices))
+	var cols []int
+	for _, idx := range columnIndices {
+		if idx >= 0 && idx < width && !seen[idx] {
+			seen[idx] = true
+			cols = append(cols, idx)
+		}
 	}
-	defer outFile.Close()
+	sort.Ints(cols)
+	return cols
+}
 
-	writer := csv.NewWriter(outFile)
-	defer writer.Flush()
+// emptyCopies returns the blank copies of a converted column, two when it's split.
+func emptyCopies(split bool) []string {
+	if split {
+		return []string{"", ""}
+	}
+	return []string{""}
+}
 
-	if err := writer.WriteAll(records); err != nil {
-		return nil, err
+// placeCopies builds a row from record and the converted copies of its
+// columns, arranged by placement. Rows are padded to width first when the
+// copies go at the end, so they line up under their headers.
+func placeCopies[T any](record []T, copies map[int][]T, width int, placement types.Placement) []T {
+	if placement == types.PlacementEnd || placement == types.PlacementGrouped {
+		var blank T
+		for len(record) < width {
+			record = append(record[:len(record):len(record)], blank)
+		}
+	}
+	cols := make([]int, 0, len(copies))
+	for colIdx := range copies {
+		cols = append(cols, colIdx)
 	}
+	sort.Ints(cols)
+
+	row := make([]T, 0, len(record)+len(copies))
+	switch placement {
+	case types.PlacementEnd:
+		row = append(row, record...)
+		for _, colIdx := range cols {
+			row = append(row, copies[colIdx]...)
+		}
+	case types.PlacementGrouped:
+		for colIdx, cell := range record {
+			if _, ok := copies[colIdx]; !ok {
+				row = append(row, cell)
+			}
+		}
+		for _, colIdx := range cols {
+			row = append(row, record[colIdx])
+			row = append(row, copies[colIdx]...)
+		}
+	default:
+		for colIdx, cell := range record {
+			row = append(row, cell)
+			row = append(row, copies[colIdx]...)
+		}
+	}
+	return row
+}
 
-	return &types.ConversionResult{
-		InputFile:     inputFile,
-		OutputFile:    outputFile,
-		ColumnsFound:  convertedCols,
-		RowsProcessed: rowsProcessed,
-	}, nil
+// sortChanges orders changes by row, then column
+func sortChanges(changes []types.CellChange) []types.CellChange {
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Row != changes[j].Row {
+			return changes[i].Row < changes[j].Row
+		}
+		return changes[i].Col < changes[j].Col
+	})
+	return changes
+}
+
+// copyColumn copies the first rows cells of column from into column to, both
+// 0-indexed, keeping their values, formulas and styles.
+func copyColumn(f *excelize.File, sheet string, from, to, rows int) error {
+	fromName, _ := excelize.ColumnNumberToName(from + 1)
+	toName, _ := excelize.ColumnNumberToName(to + 1)
+	if width, err := f.GetColWidth(sheet, fromName); err == nil {
+		f.SetColWidth(sheet, toName, toName, width)
+	}
+
+	for rowIdx := 1; rowIdx <= rows; rowIdx++ {
+		src, _ := excelize.CoordinatesToCellName(from+1, rowIdx)
+		dst, _ := excelize.CoordinatesToCellName(to+1, rowIdx)
+
+		val, err := f.GetCellValue(sheet, src, excelize.Options{RawCellValue: true})
+		if err != nil {
+			return err
+		}
+		cellType, _ := f.GetCellType(sheet, src)
+		switch {
+		case val == "":
+		case cellType == excelize.CellTypeBool:
+			f.SetCellBool(sheet, dst, val == "1")
+		case cellType == excelize.CellTypeUnset || cellType == excelize.CellTypeNumber:
+			// Numbers are stored untyped; keep them numeric
+			if n, err := strconv.ParseFloat(val, 64); err == nil {
+				f.SetCellValue(sheet, dst, n)
+			} else {
+				f.SetCellValue(sheet, dst, val)
+			}
+		default:
+			f.SetCellValue(sheet, dst, val)
+		}
+		if formula, _ := f.GetCellFormula(sheet, src); formula != "" {
+			f.SetCellFormula(sheet, dst, formula)
+		}
+		if style, err := f.GetCellStyle(sheet, src); err == nil && style != 0 {
+			f.SetCellStyle(sheet, dst, dst, style)
+		}
+	}
+	return nil
 }
 
 // ConvertXLSX processes an XLSX file and converts specified columns
-func ConvertXLSX(inputFile, outputFile string, columnIndices []int, keepOriginal bool, progressChan chan<- float64) (*types.ConversionResult, error) {
-	f, err := excelize.OpenFile(inputFile)
+func ConvertXLSX(inputFile string, sink OutputSink, columnIndices []int, opts types.ConversionOptions, progress ProgressReporter) (*types.ConversionResult, error) {
+	if opts.LowMemory {
+		return convertXLSXLowMemory(inputFile, sink, columnIndices, opts, progress)
+	}
+
+	in, err := os.Open(inputFile)
+	if err != nil {
+		return nil, err
+	}
+	defer in.Close()
+	info, err := in.Stat()
+	if err != nil {
+		return nil, err
+	}
+	f, err := excelize.OpenReader(newProgressReader(in, info.Size(), PhaseRead, progress))
 	if err != nil {
 		return nil, err
 	}
 	defer f.Close()
 
 	sheetName := f.GetSheetName(0)
+	if opts.NewSheet {
+		// Convert a copy, leaving the original sheet as it was
+		if sheetName, err = addConvertedSheet(f, sheetName); err != nil {
+			return nil, err
+		}
+	}
 	rows, err := f.GetRows(sheetName)
 	if err != nil {
 		return nil, err
@@ -220,52 +1090,215 @@ func ConvertXLSX(inputFile, outputFile string, columnIndices []int, keepOriginal
 	if headerRowIdx == -1 {
 		return nil, fmt.Errorf("could not find header row")
 	}
+	trimBOMs(rows[headerRowIdx])
 
 	headers := rows[headerRowIdx]
-	colMap := make(map[int]bool)
-	var convertedCols []string
+
+	// Cells formatted as times hold fractions of a day. The rows are read
+	// the way ReadFileData reads them, so filters match the values shown
+	// when they were chosen.
+	timeCols := timeColumns(f, sheetName, rows, headerRowIdx)
+	if err := readStoredValues(f, sheetName, rows, headerRowIdx, timeCols); err != nil {
+		return nil, err
+	}
+	opts.Columns = WithTimeColumns(opts.Columns, timeCols)
+	reader := newCellReader(f, sheetName)
+
+	// Computed durations come first, as if they'd been read
+	invalidDurations := 0
+	if len(opts.Durations) > 0 {
+		if invalidDurations, err = addDurationColumns(f, sheetName, rows, headerRowIdx, opts); err != nil {
+			return nil, err
+		}
+		headers = rows[headerRowIdx]
+	}
+	var totals [][]string
+	untotalled := 0
+	if opts.Totals != nil {
+		totals, untotalled = computeTotals(rows[headerRowIdx:], columnIndices, opts)
+	}
+
+	// Move and drop columns in the sheet. rows keeps the layout as read,
+	// so the filters still match against it.
+	var kept []int
+	if rearranged(opts) {
+		kept = outputColumns(len(headers), columnIndices, opts)
+		width := 0
+		for _, row := range rows {
+			width = max(width, len(row))
+		}
+		arrange := append([]int(nil), kept...)
+		if !opts.OnlySelected {
+			// Stray cells past the header stay at the end
+			for idx := len(headers); idx < width; idx++ {
+				arrange = append(arrange, idx)
+			}
+		}
+		if err := arrangeColumns(f, sheetName, width, len(rows), arrange); err != nil {
+			return nil, err
+		}
+		headers = projectRow(headers, kept)
+		columnIndices, opts = renumberOptions(kept, columnIndices, opts)
+	}
 
 	// Let's identify which columns to convert first.
-	for _, idx := range columnIndices {
-		if idx >= 0 && idx < len(headers) {
-			colMap[idx] = true
-			convertedCols = append(convertedCols, headers[idx])
+	cols := convertedColumns(columnIndices, len(headers))
+	colMap := make(map[int]bool, len(cols))
+	var convertedCols []string
+	for _, idx := range cols {
+		colMap[idx] = true
+		convertedCols = append(convertedCols, headers[idx])
+	}
+	several := splitCount(colMap, opts) > 1
+
+	// copyWidth is how many columns the converted copy of column colIdx
+	// takes: two when it's split into regular and overtime
+	copyWidth := func(colIdx int) int {
+		if splits(colIdx, opts) {
+			return 2
 		}
+		return 1
 	}
 
-	rowsProcessed := 0
-	totalRows := len(rows) - (headerRowIdx + 2) + 1
+	// lastDataRow is the 1-indexed sheet row of the last row before the footer
+	lastDataRow := headerRowIdx + 1 + footerStart(len(rows)-headerRowIdx-1, opts)
+	if opts.DropFooter {
+		// Remove from the bottom up so row numbers above stay valid
+		for rowIdx := len(rows); rowIdx > lastDataRow; rowIdx-- {
+			if err := f.RemoveRow(sheetName, rowIdx); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	totalRows := lastDataRow - (headerRowIdx + 2) + 1
 	if totalRows < 0 {
 		totalRows = 0
 	}
 
-	// Helper to report progress
-	reportProgress := func(current int) {
-		if progressChan != nil && totalRows > 0 {
-			select {
-			case progressChan <- float64(current) / float64(totalRows):
-			default:
-			}
+	report(progress, PhaseConvert, 0, totalRows)
+
+	// Non-numeric cells in converted columns are left alone and reported
+	var unconverted unconvertedCells
+	changes := changeLog{record: opts.RecordChanges}
+
+	// Cells of more hours than the flag threshold are filled
+	flags := newFlagger(f, sheetName)
+
+	// rowMatches applies the row filters to a 1-indexed sheet row using the
+	// values read before any columns were inserted
+	rowMatches := func(rowIdx int) bool {
+		return MatchesFilters(rows[rowIdx-1], opts.Filters)
+	}
+
+	// Rows are counted once, however many of their columns are converted
+	rowsProcessed := 0
+	for rowIdx := headerRowIdx + 2; rowIdx <= lastDataRow; rowIdx++ {
+		if rowMatches(rowIdx) {
+			rowsProcessed++
 		}
 	}
 
-	if keepOriginal {
-		// Find max col index
-		maxCol := len(headers) - 1
+	if opts.KeepOriginal {
+		processedOps := 0
 
-		// We need to iterate through all columns from right to left
-		// If a column is in colMap, we insert a column after it.
+		// convertColumn writes the converted copy of column colIdx into
+		// column destCol, both 0-indexed, and the overtime part of split
+		// columns into the column after it
+		convertColumn := func(colIdx, destCol int) error {
+			split := splits(colIdx, opts)
+
+			// Set header for new column
+			headerCell, _ := excelize.CoordinatesToCellName(destCol+1, headerRowIdx+1)
+			if split {
+				regular, overtime := splitHeaders(headers[colIdx], colIdx, opts, several)
+				overtimeHeader, _ := excelize.CoordinatesToCellName(destCol+2, headerRowIdx+1)
+				f.SetCellValue(sheetName, headerCell, regular)
+				f.SetCellValue(sheetName, overtimeHeader, overtime)
+			} else {
+				f.SetCellValue(sheetName, headerCell, ConvertedHeader(headers[colIdx], colIdx, opts))
+			}
 
-		processedOps := 0
-		totalOps := 0
-		for colIdx := maxCol; colIdx >= 0; colIdx-- {
-			if colMap[colIdx] {
-				totalOps += totalRows
+			// Process rows for this column
+			for rowIdx := headerRowIdx + 2; rowIdx <= lastDataRow; rowIdx++ {
+				// Read original value
+				origCell, _ := excelize.CoordinatesToCellName(colIdx+1, rowIdx)
+				val := reader.read(origCell, opts.Columns[colIdx].Unit == types.UnitExcelTime)
+
+				if (val != "" || opts.Blanks == types.BlankAsZero) && rowMatches(rowIdx) {
+					if convertedVal, ok := outputCell(val, opts.Columns[colIdx], opts); ok {
+						// Write to new column
+						destCell, _ := excelize.CoordinatesToCellName(destCol+1, rowIdx)
+						f.SetCellValue(sheetName, destCell, convertedVal)
+						if exceedsFlag(val, opts.Columns[colIdx], opts) {
+							if err := flags.flag(destCell); err != nil {
+								return err
+							}
+						}
+						if split {
+							destCell, _ = excelize.CoordinatesToCellName(destCol+2, rowIdx)
+							f.SetCellValue(sheetName, destCell, applyBlanks(overtimeCell(val, opts.Columns[colIdx]), opts.Columns[colIdx], opts.Blanks))
+						}
+						// Blank cells left blank aren't changes, as in CSV files
+						if convertedVal != "" || strings.TrimSpace(val) != "" {
+							changes.add([]types.CellChange{{Sheet: sheetName, Row: rowIdx, Col: colIdx, Column: headers[colIdx], Original: val, Converted: convertedVal}}, kept, opts)
+						}
+					} else {
+						unconverted.add(val, opts.Columns[colIdx])
+					}
+				}
+
+				// Each column goes through the rows once, so count its share
+				processedOps++
+				report(progress, PhaseConvert, processedOps/len(cols), totalRows)
 			}
+			return nil
 		}
 
-		for colIdx := maxCol; colIdx >= 0; colIdx-- {
-			if colMap[colIdx] {
+		switch opts.Placement {
+		case types.PlacementEnd, types.PlacementGrouped:
+			// Write past the widest row so nothing has to be inserted
+			lastCol := 0
+			sheetRows := len(rows)
+			if opts.DropFooter {
+				sheetRows = lastDataRow
+			}
+			for _, row := range rows[:sheetRows] {
+				lastCol = max(lastCol, len(row))
+			}
+			if opts.OnlySelected {
+				lastCol = len(kept)
+			}
+
+			dest := lastCol
+			for _, colIdx := range cols {
+				if opts.Placement == types.PlacementGrouped {
+					// Move the original next to its copy; it's removed below
+					if err := copyColumn(f, sheetName, colIdx, dest, sheetRows); err != nil {
+						return nil, err
+					}
+					dest++
+				}
+				if err := convertColumn(colIdx, dest); err != nil {
+					return nil, err
+				}
+				dest += copyWidth(colIdx)
+			}
+
+			if opts.Placement == types.PlacementGrouped {
+				// Remove from the right so the columns to the left stay put
+				for i := len(cols) - 1; i >= 0; i-- {
+					name, _ := excelize.ColumnNumberToName(cols[i] + 1)
+					if err := f.RemoveCol(sheetName, name); err != nil {
+						return nil, err
+					}
+				}
+			}
+		default:
+			// We need to iterate through all columns from right to left
+			// If a column is in colMap, we insert a column after it.
+			for i := len(cols) - 1; i >= 0; i-- {
+				colIdx := cols[i]
 				// Insert column after this one (at colIdx + 2 because Excel is 1-indexed and we want after)
 				// Just get the column name for the insertion point
 				insertPoint, _ := excelize.CoordinatesToCellName(colIdx+2, 1)
@@ -276,74 +1309,158 @@ func ConvertXLSX(inputFile, outputFile string, columnIndices []int, keepOriginal
 					return r
 				}, insertPoint)
 
-				if err := f.InsertCols(sheetName, insertColLetter, 1); err != nil {
+				if err := f.InsertCols(sheetName, insertColLetter, copyWidth(colIdx)); err != nil {
 					return nil, err
 				}
 
-				// Set header for new column
-				headerCell, _ := excelize.CoordinatesToCellName(colIdx+2, headerRowIdx+1)
-				f.SetCellValue(sheetName, headerCell, headers[colIdx]+" (HH:MM)")
-
-				// Process rows for this column
-				for rowIdx := headerRowIdx + 2; rowIdx <= len(rows); rowIdx++ {
-					// Read original value
-					origCell, _ := excelize.CoordinatesToCellName(colIdx+1, rowIdx)
-					val, _ := f.GetCellValue(sheetName, origCell)
-
-					if val != "" {
-						if decimal, err := strconv.ParseFloat(strings.TrimSpace(val), 64); err == nil {
-							// Write to new column
-							destCell, _ := excelize.CoordinatesToCellName(colIdx+2, rowIdx)
-							f.SetCellValue(sheetName, destCell, DecimalToTime(decimal))
-							rowsProcessed++
-						}
-					}
-
-					processedOps++
-					if progressChan != nil && totalOps > 0 {
-						select {
-						case progressChan <- float64(processedOps) / float64(totalOps):
-						default:
-						}
-					}
+				if err := convertColumn(colIdx, colIdx+1); err != nil {
+					return nil, err
 				}
 			}
 		}
 	} else {
+		// Renamed columns are renamed in place too
+		for _, colIdx := range cols {
+			name := ConvertedHeader(headers[colIdx], colIdx, opts)
+			if splits(colIdx, opts) {
+				name, _ = splitHeaders(headers[colIdx], colIdx, opts, several)
+			}
+			if name != headers[colIdx] {
+				headerCell, _ := excelize.CoordinatesToCellName(colIdx+1, headerRowIdx+1)
+				f.SetCellValue(sheetName, headerCell, name)
+			}
+		}
+
+		// Original values are noted in comments on the converted cells
+		var notes *originalNotes
+		if opts.CommentOriginals {
+			if notes, err = newOriginalNotes(f, sheetName); err != nil {
+				return nil, err
+			}
+		}
+
+		// The overtime part of split columns, by column then sheet row,
+		// written once the values are converted
+		overtime := make(map[int]map[int]string)
+		for _, colIdx := range cols {
+			if splits(colIdx, opts) {
+				overtime[colIdx] = make(map[int]string)
+			}
+		}
+
 		// Original behavior
 		current := 0
-		for rowIdx := headerRowIdx + 2; rowIdx <= len(rows); rowIdx++ {
+		for rowIdx := headerRowIdx + 2; rowIdx <= lastDataRow; rowIdx++ {
 			current++
-			reportProgress(current)
+			report(progress, PhaseConvert, current, totalRows)
 
-			for colIdx := range colMap {
-				cellName, _ := excelize.CoordinatesToCellName(colIdx+1, rowIdx)
-				cellValue, _ := f.GetCellValue(sheetName, cellName)
+			if !rowMatches(rowIdx) {
+				continue
+			}
 
-				if cellValue != "" {
-					if decimal, err := strconv.ParseFloat(strings.TrimSpace(cellValue), 64); err == nil {
-						f.SetCellValue(sheetName, cellName, DecimalToTime(decimal))
-						rowsProcessed++
+			for _, colIdx := range cols {
+				cellName, _ := excelize.CoordinatesToCellName(colIdx+1, rowIdx)
+				cellValue := reader.read(cellName, opts.Columns[colIdx].Unit == types.UnitExcelTime)
+
+				if cellValue != "" || opts.Blanks == types.BlankAsZero {
+					if convertedVal, ok := outputCell(cellValue, opts.Columns[colIdx], opts); ok {
+						f.SetCellValue(sheetName, cellName, convertedVal)
+						if exceedsFlag(cellValue, opts.Columns[colIdx], opts) {
+							if err := flags.flag(cellName); err != nil {
+								return nil, err
+							}
+						}
+						if notes != nil && cellValue != "" {
+							if err := notes.add(cellName, cellValue); err != nil {
+								return nil, err
+							}
+						}
+						if cells, ok := overtime[colIdx]; ok {
+							cells[rowIdx] = applyBlanks(overtimeCell(cellValue, opts.Columns[colIdx]), opts.Columns[colIdx], opts.Blanks)
+						}
+						// Only cells whose value changed are counted, as in CSV files
+						if convertedVal != cellValue {
+							changes.add([]types.CellChange{{Sheet: sheetName, Row: rowIdx, Col: colIdx, Column: headers[colIdx], Original: cellValue, Converted: convertedVal}}, kept, opts)
+						}
+					} else {
+						unconverted.add(cellValue, opts.Columns[colIdx])
 					}
 				}
 			}
 		}
+
+		// Insert each overtime column after its split column, from the
+		// right so the columns to the left stay put
+		var split []int
+		for colIdx := range overtime {
+			split = append(split, colIdx)
+		}
+		sort.Sort(sort.Reverse(sort.IntSlice(split)))
+		for _, colIdx := range split {
+			name, _ := excelize.ColumnNumberToName(colIdx + 2)
+			if err := f.InsertCols(sheetName, name, 1); err != nil {
+				return nil, err
+			}
+			_, header := splitHeaders(headers[colIdx], colIdx, opts, several)
+			headerCell, _ := excelize.CoordinatesToCellName(colIdx+2, headerRowIdx+1)
+			f.SetCellValue(sheetName, headerCell, header)
+			for rowIdx, value := range overtime[colIdx] {
+				cell, _ := excelize.CoordinatesToCellName(colIdx+2, rowIdx)
+				f.SetCellValue(sheetName, cell, value)
+			}
+		}
+	}
+
+	changes.sort()
+
+	if opts.Table != "" {
+		// Footer rows that weren't dropped stay below the table
+		if err := addTable(f, sheetName, opts.Table, opts.TableStyle, headerRowIdx+1, lastDataRow); err != nil {
+			return nil, err
+		}
+	}
+
+	if totals != nil {
+		if err := addTotalsSheet(f, sheetName, totals); err != nil {
+			return nil, err
+		}
 	}
 
-	if err := f.SaveAs(outputFile); err != nil {
+	if err := writeWorkbook(f, sink, info.Size(), false, progress); err != nil {
 		return nil, err
 	}
 
+	warnings := conversionWarnings(unconverted, 0, changes.lossy, changes.flagged, opts)
+	warnings = append(warnings, durationWarnings(invalidDurations)...)
+	warnings = append(warnings, totalsWarnings(untotalled)...)
 	return &types.ConversionResult{
 		InputFile:     inputFile,
-		OutputFile:    outputFile,
+		OutputFile:    sink.Location(),
 		ColumnsFound:  convertedCols,
 		RowsProcessed: rowsProcessed,
+		Warnings:      warnings,
+		Changes:       changes.changes,
+		CellsChanged:  changes.count,
+		Hours:         changes.hours,
+		Lossy:         changes.lossy,
+		Flagged:       changes.flagged,
 	}, nil
 }
 
 // ReadFileData reads headers and sample rows from a file
 func ReadFileData(filePath string) (*types.FileData, error) {
+	start := time.Now()
+	data, err := readFileData(filePath)
+	if err != nil {
+		slog.Debug("reading failed", "file", filePath, "elapsed", time.Since(start), "error", err)
+		return nil, err
+	}
+	slog.Debug("read file", "file", filePath, "columns", len(data.Headers), "rows", len(data.Rows),
+		"footer_rows", data.FooterRows, "elapsed", time.Since(start))
+	return data, nil
+}
+
+func readFileData(filePath string) (*types.FileData, error) {
 	ext := strings.ToLower(filepath.Ext(filePath))
 
 	switch ext {
@@ -351,20 +1468,20 @@ func ReadFileData(filePath string) (*types.FileData, error) {
 		return readCSVData(filePath)
 	case ".xlsx":
 		return readXLSXData(filePath)
+	case ".json", ".ndjson":
+		return readJSONData(filePath)
 	default:
 		return nil, fmt.Errorf("unsupported file type: %s", ext)
 	}
 }
 
 func readCSVData(filePath string) (*types.FileData, error) {
-	file, err := os.Open(filePath)
+	text, enc, err := readTextFile(filePath)
 	if err != nil {
 		return nil, err
 	}
-	defer file.Close()
 
-	reader := csv.NewReader(file)
-	records, err := reader.ReadAll()
+	records, paddedRows, err := readCSVRecords(text)
 	if err != nil {
 		return nil, err
 	}
@@ -374,11 +1491,42 @@ func readCSVData(filePath string) (*types.FileData, error) {
 	}
 
 	return &types.FileData{
-		Headers: records[0],
-		Rows:    records[1:],
+		Headers:    records[0],
+		Rows:       records[1:],
+		FooterRows: DetectFooterRows(records[1:]),
+		Encoding:   enc,
+		PaddedRows: paddedRows,
 	}, nil
 }
 
+// readCSVRecords reads every record, tolerating rows with a different number
+// of fields than the header. Short rows are padded with empty cells to the
+// header's width so columns stay aligned; longer rows are kept as they are.
+func readCSVRecords(r io.Reader) ([][]string, int, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(records) == 0 {
+		return records, 0, nil
+	}
+	trimBOMs(records[0])
+
+	width := len(records[0])
+	padded := 0
+	for i := 1; i < len(records); i++ {
+		if len(records[i]) < width {
+			records[i] = append(records[i], make([]string, width-len(records[i]))...)
+			padded++
+		}
+	}
+
+	return records, padded, nil
+}
+
 func readXLSXData(filePath string) (*types.FileData, error) {
 	f, err := excelize.OpenFile(filePath)
 	if err != nil {
@@ -402,11 +1550,22 @@ func readXLSXData(filePath string) (*types.FileData, error) {
 	if headerRowIdx == -1 {
 		return nil, fmt.Errorf("could not find header row")
 	}
+	trimBOMs(rows[headerRowIdx])
+
+	// Numbers are read as stored rather than as shown, and time columns
+	// as the fractions they hold
+	timeCols := timeColumns(f, sheetName, rows, headerRowIdx)
+	if err := readStoredValues(f, sheetName, rows, headerRowIdx, timeCols); err != nil {
+		return nil, err
+	}
 
 	return &types.FileData{
-		Headers:   rows[headerRowIdx],
-		Rows:      rows[headerRowIdx+1:],
-		HeaderRow: headerRowIdx,
+		Headers:     rows[headerRowIdx],
+		Rows:        rows[headerRowIdx+1:],
+		Sheet:       sheetName,
+		HeaderRow:   headerRowIdx,
+		FooterRows:  DetectFooterRows(rows[headerRowIdx+1:]),
+		TimeColumns: timeCols,
 	}, nil
 }
 
@@ -447,12 +1606,24 @@ func findHeaderRow(rows [][]string) int {
 	return headerIdx
 }
 
-// containsLetters checks if a string contains any alphabetic characters
+// containsLetters checks if a string contains any letters, in any script
 func containsLetters(s string) bool {
 	for _, r := range s {
-		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') {
+		if unicode.IsLetter(r) {
 			return true
 		}
 	}
 	return false
 }
+
+// byteOrderMark is the BOM as a character. The BOM starting a file is
+// dropped as it's decoded, but files saved twice by tools adding one can
+// start with two, and the second is read into the first header.
+const byteOrderMark = "\ufeff"
+
+// trimBOMs removes byte order marks from the start of header cells.
+func trimBOMs(headers []string) {
+	for i, header := range headers {
+		headers[i] = strings.TrimLeft(header, byteOrderMark)
+	}
+}
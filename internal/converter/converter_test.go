@@ -2,11 +2,15 @@ package converter
 
 import (
 	"encoding/csv"
+	"math"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/nconklindev/chronos/internal/types"
+	"github.com/xuri/excelize/v2"
 )
 
 func TestDecimalToTime(t *testing.T) {
@@ -38,6 +42,60 @@ func TestDecimalToTime(t *testing.T) {
 	}
 }
 
+func TestConvertValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    float64
+		settings types.ColumnSettings
+		expected string
+	}{
+		{"Default", 7.5, types.ColumnSettings{}, "07:30"},
+		{"Unpadded hours", 7.5, types.ColumnSettings{Format: types.FormatHMM}, "7:30"},
+		{"Seconds", 1.0 + 1.0/120, types.ColumnSettings{Format: types.FormatHHMMSS}, "01:00:30"},
+		{"Total minutes", 7.5, types.ColumnSettings{Format: types.FormatMinutes}, "450"},
+		{"Round down", 1.99, types.ColumnSettings{Rounding: types.RoundDown}, "01:59"},
+		{"Round up", 1.01, types.ColumnSettings{Rounding: types.RoundUp}, "01:01"},
+		{"Round up exact minute", 0.25, types.ColumnSettings{Rounding: types.RoundUp}, "00:15"},
+		{"Round up to the hour", 1.999, types.ColumnSettings{Rounding: types.RoundUp}, "02:00"},
+		{"Minutes in", 90, types.ColumnSettings{Unit: types.UnitMinutes}, "01:30"},
+		{"Seconds in", 5400, types.ColumnSettings{Unit: types.UnitSeconds, Format: types.FormatHHMMSS}, "01:30:00"},
+		{"Days in", 1.5, types.ColumnSettings{Unit: types.UnitDays}, "36:00"},
+		{"Weeks in", 0.5, types.ColumnSettings{Unit: types.UnitWeeks}, "84:00"},
+		{"Pattern", 7.5, types.ColumnSettings{Pattern: "H'h' mm'm'"}, "7h 30m"},
+		{"Pattern with seconds", 1.0 + 1.0/120, types.ColumnSettings{Pattern: "hh.mm.ss"}, "01.00.30"},
+		{"Pattern without separators", 7.5, types.ColumnSettings{Pattern: "hhmm"}, "0730"},
+		{"Pattern of minutes", 7.5, types.ColumnSettings{Pattern: "m' min'"}, "450 min"},
+		{"Pattern with a quote", 7.5, types.ColumnSettings{Pattern: "H''mm"}, "7'30"},
+		{"Pattern rounds down", 1.99, types.ColumnSettings{Pattern: "H:mm", Rounding: types.RoundDown}, "1:59"},
+		{"Invalid pattern", 7.5, types.ColumnSettings{Pattern: "hours"}, "07:30"},
+		// The 7-minute rule: 7 minutes past a quarter rounds down, 8 rounds up
+		{"Quarter on the quarter", 1.25, types.ColumnSettings{Rounding: types.RoundQuarter}, "01:15"},
+		{"Quarter 7 minutes past", 1.0 + 7.0/60, types.ColumnSettings{Rounding: types.RoundQuarter}, "01:00"},
+		{"Quarter 8 minutes past", 1.0 + 8.0/60, types.ColumnSettings{Rounding: types.RoundQuarter}, "01:15"},
+		{"Quarter 7.4 minutes past", 1.0 + 7.4/60, types.ColumnSettings{Rounding: types.RoundQuarter}, "01:00"},
+		{"Quarter 7.5 minutes past", 1.0 + 7.5/60, types.ColumnSettings{Rounding: types.RoundQuarter}, "01:15"},
+		{"Quarter 22 minutes past", 1.0 + 22.0/60, types.ColumnSettings{Rounding: types.RoundQuarter}, "01:15"},
+		{"Quarter 23 minutes past", 1.0 + 23.0/60, types.ColumnSettings{Rounding: types.RoundQuarter}, "01:30"},
+		{"Quarter up to the hour", 1.0 + 53.0/60, types.ColumnSettings{Rounding: types.RoundQuarter}, "02:00"},
+		{"Quarter with seconds", 1.0 + 8.0/60, types.ColumnSettings{Rounding: types.RoundQuarter, Format: types.FormatHHMMSS}, "01:15:00"},
+		{"Quarter from minutes", 68, types.ColumnSettings{Rounding: types.RoundQuarter, Unit: types.UnitMinutes}, "01:15"},
+		{"Tenth 2 minutes past", 1.0 + 2.0/60, types.ColumnSettings{Rounding: types.RoundTenth}, "01:00"},
+		{"Tenth 3 minutes past", 1.0 + 3.0/60, types.ColumnSettings{Rounding: types.RoundTenth}, "01:06"},
+		{"Tenth 8 minutes past", 1.0 + 8.0/60, types.ColumnSettings{Rounding: types.RoundTenth}, "01:06"},
+		{"Tenth 9 minutes past", 1.0 + 9.0/60, types.ColumnSettings{Rounding: types.RoundTenth}, "01:12"},
+		{"Tenth up to the hour", 1.0 + 57.0/60, types.ColumnSettings{Rounding: types.RoundTenth}, "02:00"},
+		{"Tenth in minutes", 7.1, types.ColumnSettings{Rounding: types.RoundTenth, Format: types.FormatMinutes}, "426"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ConvertValue(tt.input, tt.settings); got != tt.expected {
+				t.Errorf("ConvertValue(%v, %+v) = %s; want %s", tt.input, tt.settings, got, tt.expected)
+			}
+		})
+	}
+}
+
 func TestIsDecimalHour(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -53,6 +111,14 @@ func TestIsDecimalHour(t *testing.T) {
 		{"Negative", "-1", false},
 		{"Too large", "10000", false},
 		{"Mixed", "1.5h", false},
+		{"Scientific notation", "7.5e-1", true},
+		{"Overflowing exponent", "1e400", false},
+		{"NaN", "NaN", false},
+		{"Infinity", "Inf", false},
+		{"Hexadecimal", "0x1p3", false},
+		{"Thousands separator", "1,234.5", true},
+		{"Decimal comma", "7,5", true},
+		{"Misgrouped", "12,34.5", false},
 	}
 
 	for _, tt := range tests {
@@ -168,7 +234,7 @@ func TestConvertCSV_KeepOriginal(t *testing.T) {
 	f.Close()
 
 	// Test with keepOriginal = true
-	_, err = ConvertCSV(inputFile, outputFile, []int{1}, true, nil)
+	_, err = ConvertCSV(inputFile, LocalFile(outputFile), []int{1}, types.ConversionOptions{KeepOriginal: true}, nil)
 	if err != nil {
 		t.Fatalf("ConvertCSV failed: %v", err)
 	}
@@ -209,3 +275,1324 @@ func TestConvertCSV_KeepOriginal(t *testing.T) {
 		t.Errorf("Expected 02:00, got %s", records[2][2])
 	}
 }
+
+func TestDetectFooterRows(t *testing.T) {
+	tests := []struct {
+		name     string
+		rows     [][]string
+		expected int
+	}{
+		{
+			name:     "No footer",
+			rows:     [][]string{{"Alice", "8.0"}, {"Bob", "7.5"}},
+			expected: 0,
+		},
+		{
+			name:     "Totals row",
+			rows:     [][]string{{"Alice", "8.0"}, {"Bob", "7.5"}, {"Totals", "15.5"}},
+			expected: 1,
+		},
+		{
+			name:     "Grand total after blank trailer",
+			rows:     [][]string{{"Alice", "8.0"}, {"", ""}, {"Grand Total:", "8.0"}},
+			expected: 2,
+		},
+		{
+			name:     "Label in later column",
+			rows:     [][]string{{"Alice", "8.0"}, {"", "Total", "8.0"}},
+			expected: 1,
+		},
+		{
+			name:     "Name starting with keyword",
+			rows:     [][]string{{"Alice", "8.0"}, {"Summers", "7.5"}},
+			expected: 0,
+		},
+		{
+			name:     "Every row blank",
+			rows:     [][]string{{"", ""}, {"", ""}},
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DetectFooterRows(tt.rows)
+			if got != tt.expected {
+				t.Errorf("DetectFooterRows() = %d; want %d", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFindHeaderRow(t *testing.T) {
+	tests := []struct {
+		name     string
+		rows     [][]string
+		expected int
+	}{
+		{"English below a title", [][]string{{"Timesheet"}, {"Name", "Hours"}, {"Alice", "8.0"}}, 1},
+		{"Japanese", [][]string{{"1", "2"}, {"名前", "時間"}, {"山田", "7.5"}}, 1},
+		{"Czech", [][]string{{"Jméno", "Čas"}, {"Novák", "7.5"}}, 0},
+		{"Russian", [][]string{{"", ""}, {"Сотрудник", "Часы"}, {"Иванов", "8"}}, 1},
+		{"Numbers only", [][]string{{"1", "2"}, {"3", "4"}}, -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := findHeaderRow(tt.rows); got != tt.expected {
+				t.Errorf("findHeaderRow() = %d; want %d", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestConvertCSV_Footer(t *testing.T) {
+	tmpDir := t.TempDir()
+	inputFile := filepath.Join(tmpDir, "input.csv")
+	outputFile := filepath.Join(tmpDir, "output.csv")
+
+	writeTestCSV(t, inputFile, [][]string{
+		{"Name", "Hours"},
+		{"Alice", "1.5"},
+		{"Bob", "2.0"},
+		{"Totals", "3.5"},
+	})
+
+	result, err := ConvertCSV(inputFile, LocalFile(outputFile), []int{1}, types.ConversionOptions{FooterRows: 1}, nil)
+	if err != nil {
+		t.Fatalf("ConvertCSV failed: %v", err)
+	}
+	if result.RowsProcessed != 2 {
+		t.Errorf("Expected 2 rows processed, got %d", result.RowsProcessed)
+	}
+
+	records := readTestCSV(t, outputFile)
+	if records[3][1] != "3.5" {
+		t.Errorf("Expected footer to pass through as 3.5, got %s", records[3][1])
+	}
+
+	// Dropping the footer removes it from the output
+	_, err = ConvertCSV(inputFile, LocalFile(outputFile), []int{1}, types.ConversionOptions{FooterRows: 1, DropFooter: true}, nil)
+	if err != nil {
+		t.Fatalf("ConvertCSV failed: %v", err)
+	}
+	if records := readTestCSV(t, outputFile); len(records) != 3 {
+		t.Errorf("Expected 3 records with footer dropped, got %d", len(records))
+	}
+}
+
+func TestMatchesFilters(t *testing.T) {
+	row := []string{"E100", " Warehouse ", ""}
+
+	tests := []struct {
+		name     string
+		filters  []types.RowFilter
+		expected bool
+	}{
+		{"No filters", nil, true},
+		{"Non-empty passes", []types.RowFilter{{Column: 0, Op: types.FilterNonEmpty}}, true},
+		{"Non-empty fails", []types.RowFilter{{Column: 2, Op: types.FilterNonEmpty}}, false},
+		{"Out of range column is empty", []types.RowFilter{{Column: 5, Op: types.FilterNonEmpty}}, false},
+		{"Equals ignores case and spaces", []types.RowFilter{{Column: 1, Op: types.FilterEquals, Value: "warehouse"}}, true},
+		{"Equals fails", []types.RowFilter{{Column: 1, Op: types.FilterEquals, Value: "Office"}}, false},
+		{"All filters must match", []types.RowFilter{
+			{Column: 0, Op: types.FilterNonEmpty},
+			{Column: 2, Op: types.FilterNonEmpty},
+		}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MatchesFilters(row, tt.filters)
+			if got != tt.expected {
+				t.Errorf("MatchesFilters() = %v; want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestConvertCSV_Filters(t *testing.T) {
+	tmpDir := t.TempDir()
+	inputFile := filepath.Join(tmpDir, "input.csv")
+	outputFile := filepath.Join(tmpDir, "output.csv")
+
+	writeTestCSV(t, inputFile, [][]string{
+		{"Employee ID", "Hours"},
+		{"E1", "1.5"},
+		{"", "1.5"}, // Department subtotal without an ID
+		{"E2", "2.0"},
+	})
+
+	opts := types.ConversionOptions{
+		Filters: []types.RowFilter{{Column: 0, Op: types.FilterNonEmpty}},
+	}
+
+	for _, keepOriginal := range []bool{false, true} {
+		opts.KeepOriginal = keepOriginal
+
+		result, err := ConvertCSV(inputFile, LocalFile(outputFile), []int{1}, opts, nil)
+		if err != nil {
+			t.Fatalf("ConvertCSV failed: %v", err)
+		}
+		if result.RowsProcessed != 2 {
+			t.Errorf("keepOriginal=%v: expected 2 rows processed, got %d", keepOriginal, result.RowsProcessed)
+		}
+
+		records := readTestCSV(t, outputFile)
+		if records[2][1] != "1.5" {
+			t.Errorf("keepOriginal=%v: expected filtered row to be untouched, got %v", keepOriginal, records[2])
+		}
+		if got := records[3][len(records[3])-1]; got != "02:00" {
+			t.Errorf("keepOriginal=%v: expected matching row converted to 02:00, got %s", keepOriginal, got)
+		}
+	}
+}
+
+func TestConvertCSV_HeaderNames(t *testing.T) {
+	tmpDir := t.TempDir()
+	inputFile := filepath.Join(tmpDir, "input.csv")
+	outputFile := filepath.Join(tmpDir, "output.csv")
+
+	writeTestCSV(t, inputFile, [][]string{
+		{"Regular", "Overtime"},
+		{"1.5", "0.25"},
+	})
+
+	opts := types.ConversionOptions{
+		KeepOriginal: true,
+		HeaderSuffix: "_hhmm",
+		Columns:      map[int]types.ColumnSettings{1: {Header: "OT Time"}},
+	}
+	if _, err := ConvertCSV(inputFile, LocalFile(outputFile), []int{0, 1}, opts, nil); err != nil {
+		t.Fatalf("ConvertCSV failed: %v", err)
+	}
+
+	records := readTestCSV(t, outputFile)
+	expected := []string{"Regular", "Regular_hhmm", "Overtime", "OT Time"}
+	if !reflect.DeepEqual(records[0], expected) {
+		t.Errorf("Expected headers %v, got %v", expected, records[0])
+	}
+}
+
+func TestConvertCSV_ColumnSettings(t *testing.T) {
+	tmpDir := t.TempDir()
+	inputFile := filepath.Join(tmpDir, "input.csv")
+	outputFile := filepath.Join(tmpDir, "output.csv")
+
+	writeTestCSV(t, inputFile, [][]string{
+		{"Regular", "Break"},
+		{"7.5", "45"},
+	})
+
+	// Each column converts with its own settings, and renames in place
+	opts := types.ConversionOptions{Columns: map[int]types.ColumnSettings{
+		1: {Unit: types.UnitMinutes, Format: types.FormatHMM, Header: "Break Time"},
+	}}
+	if _, err := ConvertCSV(inputFile, LocalFile(outputFile), []int{0, 1}, opts, nil); err != nil {
+		t.Fatalf("ConvertCSV failed: %v", err)
+	}
+
+	records := readTestCSV(t, outputFile)
+	expected := [][]string{{"Regular", "Break Time"}, {"07:30", "0:45"}}
+	if !reflect.DeepEqual(records, expected) {
+		t.Errorf("Expected %v, got %v", expected, records)
+	}
+}
+
+func TestConvertCSV_Placement(t *testing.T) {
+	tests := []struct {
+		placement types.Placement
+		expected  [][]string
+	}{
+		{types.PlacementAdjacent, [][]string{
+			{"Name", "Regular", "Regular (HH:MM)", "Dept", "Overtime", "Overtime (HH:MM)"},
+			{"Alice", "1.5", "01:30", "Ops", "0.25", "00:15"},
+		}},
+		{types.PlacementEnd, [][]string{
+			{"Name", "Regular", "Dept", "Overtime", "Regular (HH:MM)", "Overtime (HH:MM)"},
+			{"Alice", "1.5", "Ops", "0.25", "01:30", "00:15"},
+		}},
+		{types.PlacementGrouped, [][]string{
+			{"Name", "Dept", "Regular", "Regular (HH:MM)", "Overtime", "Overtime (HH:MM)"},
+			{"Alice", "Ops", "1.5", "01:30", "0.25", "00:15"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.placement), func(t *testing.T) {
+			tmpDir := t.TempDir()
+			inputFile := filepath.Join(tmpDir, "input.csv")
+			outputFile := filepath.Join(tmpDir, "output.csv")
+
+			writeTestCSV(t, inputFile, [][]string{
+				{"Name", "Regular", "Dept", "Overtime"},
+				{"Alice", "1.5", "Ops", "0.25"},
+			})
+
+			opts := types.ConversionOptions{KeepOriginal: true, Placement: tt.placement}
+			if _, err := ConvertCSV(inputFile, LocalFile(outputFile), []int{3, 1}, opts, nil); err != nil {
+				t.Fatalf("ConvertCSV failed: %v", err)
+			}
+
+			records := readTestCSV(t, outputFile)
+			if !reflect.DeepEqual(records, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, records)
+			}
+		})
+	}
+}
+
+func TestConvertXLSX_GroupedPlacement(t *testing.T) {
+	tmpDir := t.TempDir()
+	inputFile := filepath.Join(tmpDir, "input.xlsx")
+	outputFile := filepath.Join(tmpDir, "output.xlsx")
+
+	f := excelize.NewFile()
+	f.SetSheetRow("Sheet1", "A1", &[]any{"Name", "Regular", "Dept", "Overtime"})
+	f.SetSheetRow("Sheet1", "A2", &[]any{"Alice", 1.5, "Ops", 0.25})
+	if err := f.SaveAs(inputFile); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	opts := types.ConversionOptions{KeepOriginal: true, Placement: types.PlacementGrouped}
+	if _, err := ConvertXLSX(inputFile, LocalFile(outputFile), []int{1, 3}, opts, nil); err != nil {
+		t.Fatalf("ConvertXLSX failed: %v", err)
+	}
+
+	out, err := excelize.OpenFile(outputFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer out.Close()
+	rows, _ := out.GetRows("Sheet1")
+	expected := [][]string{
+		{"Name", "Dept", "Regular", "Regular (HH:MM)", "Overtime", "Overtime (HH:MM)"},
+		{"Alice", "Ops", "1.5", "01:30", "0.25", "00:15"},
+	}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf("Expected %v, got %v", expected, rows)
+	}
+	// The moved originals stay numbers
+	if cellType, _ := out.GetCellType("Sheet1", "C2"); cellType == excelize.CellTypeSharedString || cellType == excelize.CellTypeInlineString {
+		t.Errorf("Expected Regular to stay numeric, got cell type %v", cellType)
+	}
+}
+
+func TestConvertCSV_OvertimeSplit(t *testing.T) {
+	split := map[int]types.ColumnSettings{1: {OvertimeAfter: 8}}
+	tests := []struct {
+		name     string
+		opts     types.ConversionOptions
+		expected [][]string
+	}{
+		{"Replace", types.ConversionOptions{Columns: split}, [][]string{
+			{"Name", "Regular (HH:MM)", "Overtime (HH:MM)", "Dept"},
+			{"Alice", "08:00", "01:30", "Ops"},
+			{"Bob", "07:15", "00:00", "Ops"},
+			{"Carol", "", "", "Ops"},
+		}},
+		{"Keep original", types.ConversionOptions{KeepOriginal: true, Columns: split}, [][]string{
+			{"Name", "Hours", "Regular (HH:MM)", "Overtime (HH:MM)", "Dept"},
+			{"Alice", "9.5", "08:00", "01:30", "Ops"},
+			{"Bob", "7.25", "07:15", "00:00", "Ops"},
+			{"Carol", "", "", "", "Ops"},
+		}},
+		{"At the end", types.ConversionOptions{KeepOriginal: true, Placement: types.PlacementEnd, Columns: split}, [][]string{
+			{"Name", "Hours", "Dept", "Regular (HH:MM)", "Overtime (HH:MM)"},
+			{"Alice", "9.5", "Ops", "08:00", "01:30"},
+			{"Bob", "7.25", "Ops", "07:15", "00:00"},
+			{"Carol", "", "Ops", "", ""},
+		}},
+		{"Minutes", types.ConversionOptions{Columns: map[int]types.ColumnSettings{1: {OvertimeAfter: 0.1, Unit: types.UnitMinutes}}}, [][]string{
+			{"Name", "Regular (HH:MM)", "Overtime (HH:MM)", "Dept"},
+			{"Alice", "00:06", "00:04", "Ops"},
+			{"Bob", "00:06", "00:01", "Ops"},
+			{"Carol", "", "", "Ops"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			inputFile := filepath.Join(tmpDir, "input.csv")
+			outputFile := filepath.Join(tmpDir, "output.csv")
+
+			writeTestCSV(t, inputFile, [][]string{
+				{"Name", "Hours", "Dept"},
+				{"Alice", "9.5", "Ops"},
+				{"Bob", "7.25", "Ops"},
+				{"Carol", "", "Ops"},
+			})
+
+			result, err := ConvertCSV(inputFile, LocalFile(outputFile), []int{1}, tt.opts, nil)
+			if err != nil {
+				t.Fatalf("ConvertCSV failed: %v", err)
+			}
+
+			records := readTestCSV(t, outputFile)
+			if !reflect.DeepEqual(records, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, records)
+			}
+			// Splitting isn't a rounding loss
+			if len(result.Lossy) > 0 {
+				t.Errorf("Expected no rounding losses, got %+v", result.Lossy)
+			}
+		})
+	}
+}
+
+func TestConvertCSV_FlagAbove(t *testing.T) {
+	tmpDir := t.TempDir()
+	inputFile := filepath.Join(tmpDir, "input.csv")
+	outputFile := filepath.Join(tmpDir, "output.csv")
+
+	writeTestCSV(t, inputFile, [][]string{
+		{"Name", "Mon", "Tue"},
+		{"Alice", "8", "75"},
+		{"Bob", "17.5", "18"},
+		{"Carol", "", "8"},
+	})
+
+	opts := types.ConversionOptions{FlagAbove: 16}
+	result, err := ConvertCSV(inputFile, LocalFile(outputFile), []int{1, 2}, opts, nil)
+	if err != nil {
+		t.Fatalf("ConvertCSV failed: %v", err)
+	}
+
+	expected := [][]string{
+		{"Name", "Mon", "Tue", "Over 16 hours"},
+		{"Alice", "08:00", "75:00", "Tue"},
+		{"Bob", "17:30", "18:00", "Mon; Tue"},
+		{"Carol", "", "08:00", ""},
+	}
+	if records := readTestCSV(t, outputFile); !reflect.DeepEqual(records, expected) {
+		t.Errorf("Expected %v, got %v", expected, records)
+	}
+	if len(result.Flagged) != 3 || result.Flagged[0].Original != "75" {
+		t.Errorf("Expected 3 flagged cells, got %+v", result.Flagged)
+	}
+
+	// Flagged cells keep their columns' numbering in the file when
+	// columns are moved
+	opts.Order = []int{2}
+	result, err = ConvertCSV(inputFile, LocalFile(outputFile), []int{1, 2}, opts, nil)
+	if err != nil {
+		t.Fatalf("ConvertCSV failed: %v", err)
+	}
+	if result.Flagged[0].Col != 2 || result.Flagged[0].Column != "Tue" {
+		t.Errorf("Expected the first flagged cell in column 2, got %+v", result.Flagged[0])
+	}
+	if len(result.Warnings) == 0 || result.Warnings[0] != "3 cell(s) over 16 hours were flagged" {
+		t.Errorf("Unexpected warnings %v", result.Warnings)
+	}
+}
+
+func TestConvertXLSX_FlagAbove(t *testing.T) {
+	for _, keepOriginal := range []bool{false, true} {
+		tmpDir := t.TempDir()
+		inputFile := filepath.Join(tmpDir, "input.xlsx")
+		outputFile := filepath.Join(tmpDir, "output.xlsx")
+
+		f := excelize.NewFile()
+		f.SetSheetRow("Sheet1", "A1", &[]any{"Name", "Hours"})
+		f.SetSheetRow("Sheet1", "A2", &[]any{"Alice", 8})
+		f.SetSheetRow("Sheet1", "A3", &[]any{"Bob", 80})
+		bold, _ := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+		f.SetCellStyle("Sheet1", "B3", "B3", bold)
+		if err := f.SaveAs(inputFile); err != nil {
+			t.Fatal(err)
+		}
+		f.Close()
+
+		opts := types.ConversionOptions{KeepOriginal: keepOriginal, FlagAbove: 16}
+		result, err := ConvertXLSX(inputFile, LocalFile(outputFile), []int{1}, opts, nil)
+		if err != nil {
+			t.Fatalf("ConvertXLSX failed: %v", err)
+		}
+		if len(result.Flagged) != 1 {
+			t.Errorf("Expected one flagged cell, got %+v", result.Flagged)
+		}
+
+		out, err := excelize.OpenFile(outputFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		col := "B"
+		if keepOriginal {
+			col = "C"
+		}
+		fill := func(cell string) excelize.Fill {
+			id, _ := out.GetCellStyle("Sheet1", cell)
+			style, _ := out.GetStyle(id)
+			return style.Fill
+		}
+		if got := fill(col + "3"); !reflect.DeepEqual(got.Color, flagFill.Color) {
+			t.Errorf("keepOriginal=%v: expected %s3 filled, got %+v", keepOriginal, col, got)
+		}
+		if got := fill(col + "2"); len(got.Color) > 0 {
+			t.Errorf("keepOriginal=%v: expected %s2 unfilled, got %+v", keepOriginal, col, got)
+		}
+		if !keepOriginal {
+			id, _ := out.GetCellStyle("Sheet1", "B3")
+			if style, _ := out.GetStyle(id); style.Font == nil || !style.Font.Bold {
+				t.Error("Expected the flagged cell to stay bold")
+			}
+		}
+		out.Close()
+	}
+}
+
+func TestConvertXLSX_OvertimeSplit(t *testing.T) {
+	for _, keepOriginal := range []bool{false, true} {
+		tmpDir := t.TempDir()
+		inputFile := filepath.Join(tmpDir, "input.xlsx")
+		outputFile := filepath.Join(tmpDir, "output.xlsx")
+
+		f := excelize.NewFile()
+		f.SetSheetRow("Sheet1", "A1", &[]any{"Name", "Week", "Day", "Dept"})
+		f.SetSheetRow("Sheet1", "A2", &[]any{"Alice", 42.5, 9, "Ops"})
+		if err := f.SaveAs(inputFile); err != nil {
+			t.Fatal(err)
+		}
+		f.Close()
+
+		opts := types.ConversionOptions{
+			KeepOriginal: keepOriginal,
+			Columns:      map[int]types.ColumnSettings{1: {OvertimeAfter: 40}, 2: {OvertimeAfter: 8}},
+		}
+		if _, err := ConvertXLSX(inputFile, LocalFile(outputFile), []int{1, 2}, opts, nil); err != nil {
+			t.Fatalf("ConvertXLSX failed: %v", err)
+		}
+
+		out, err := excelize.OpenFile(outputFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rows, _ := out.GetRows("Sheet1")
+		out.Close()
+
+		// With two split columns, each pair is named after its column
+		expected := [][]string{
+			{"Name", "Week Regular (HH:MM)", "Week Overtime (HH:MM)", "Day Regular (HH:MM)", "Day Overtime (HH:MM)", "Dept"},
+			{"Alice", "40:00", "02:30", "08:00", "01:00", "Ops"},
+		}
+		if keepOriginal {
+			expected = [][]string{
+				{"Name", "Week", "Week Regular (HH:MM)", "Week Overtime (HH:MM)", "Day", "Day Regular (HH:MM)", "Day Overtime (HH:MM)", "Dept"},
+				{"Alice", "42.5", "40:00", "02:30", "9", "08:00", "01:00", "Ops"},
+			}
+		}
+		if !reflect.DeepEqual(rows, expected) {
+			t.Errorf("keepOriginal=%v: expected %v, got %v", keepOriginal, expected, rows)
+		}
+	}
+}
+
+func TestConvertCSV_Blanks(t *testing.T) {
+	split := map[int]types.ColumnSettings{1: {OvertimeAfter: 8}}
+	tests := []struct {
+		name     string
+		opts     types.ConversionOptions
+		expected [][]string
+	}{
+		{"Keep", types.ConversionOptions{}, [][]string{
+			{"Name", "Hours"},
+			{"Alice", "07:30"},
+			{"Bob", ""},
+			{"Carol", "00:00"},
+			{"Dan", "00:00"},
+		}},
+		{"Zero", types.ConversionOptions{Blanks: types.BlankAsZero}, [][]string{
+			{"Name", "Hours"},
+			{"Alice", "07:30"},
+			{"Bob", "00:00"},
+			{"Carol", "00:00"},
+			{"Dan", "00:00"},
+		}},
+		{"Zero kept", types.ConversionOptions{Blanks: types.BlankAsZero, KeepOriginal: true, Columns: map[int]types.ColumnSettings{1: {Format: types.FormatMinutes}}}, [][]string{
+			{"Name", "Hours", "Hours (HH:MM)"},
+			{"Alice", "7.5", "450"},
+			{"Bob", "", "0"},
+			{"Carol", "0", "0"},
+			{"Dan", "0.001", "0"},
+		}},
+		// Values rounding to zero are zeros too
+		{"Blank", types.ConversionOptions{Blanks: types.ZeroAsBlank}, [][]string{
+			{"Name", "Hours"},
+			{"Alice", "07:30"},
+			{"Bob", ""},
+			{"Carol", ""},
+			{"Dan", ""},
+		}},
+		{"Blank split", types.ConversionOptions{Blanks: types.ZeroAsBlank, Columns: split}, [][]string{
+			{"Name", "Regular (HH:MM)", "Overtime (HH:MM)"},
+			{"Alice", "07:30", ""},
+			{"Bob", "", ""},
+			{"Carol", "", ""},
+			{"Dan", "", ""},
+		}},
+		{"Zero split", types.ConversionOptions{Blanks: types.BlankAsZero, Columns: split}, [][]string{
+			{"Name", "Regular (HH:MM)", "Overtime (HH:MM)"},
+			{"Alice", "07:30", "00:00"},
+			{"Bob", "00:00", "00:00"},
+			{"Carol", "00:00", "00:00"},
+			{"Dan", "00:00", "00:00"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			inputFile := filepath.Join(tmpDir, "input.csv")
+			outputFile := filepath.Join(tmpDir, "output.csv")
+			writeTestCSV(t, inputFile, [][]string{
+				{"Name", "Hours"},
+				{"Alice", "7.5"},
+				{"Bob", ""},
+				{"Carol", "0"},
+				{"Dan", "0.001"},
+			})
+
+			if _, err := ConvertCSV(inputFile, LocalFile(outputFile), []int{1}, tt.opts, nil); err != nil {
+				t.Fatalf("ConvertCSV failed: %v", err)
+			}
+			if records := readTestCSV(t, outputFile); !reflect.DeepEqual(records, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, records)
+			}
+		})
+	}
+}
+
+func TestConvertXLSX_Blanks(t *testing.T) {
+	for _, keepOriginal := range []bool{false, true} {
+		tmpDir := t.TempDir()
+		inputFile := filepath.Join(tmpDir, "input.xlsx")
+		outputFile := filepath.Join(tmpDir, "output.xlsx")
+
+		f := excelize.NewFile()
+		f.SetSheetRow("Sheet1", "A1", &[]any{"Name", "Hours", "Break"})
+		f.SetSheetRow("Sheet1", "A2", &[]any{"Alice", 7.5, 0})
+		f.SetSheetRow("Sheet1", "A3", &[]any{"Bob", nil, 0.5})
+		if err := f.SaveAs(inputFile); err != nil {
+			t.Fatal(err)
+		}
+		f.Close()
+
+		// Empty hours are written as zeros and zero breaks left blank
+		opts := types.ConversionOptions{KeepOriginal: keepOriginal, Blanks: types.BlankAsZero}
+		if _, err := ConvertXLSX(inputFile, LocalFile(outputFile), []int{1}, opts, nil); err != nil {
+			t.Fatalf("ConvertXLSX failed: %v", err)
+		}
+		opts.Blanks = types.ZeroAsBlank
+		breakCol := 2
+		if keepOriginal {
+			breakCol = 3
+		}
+		if _, err := ConvertXLSX(outputFile, LocalFile(outputFile), []int{breakCol}, opts, nil); err != nil {
+			t.Fatalf("ConvertXLSX failed: %v", err)
+		}
+
+		out, err := excelize.OpenFile(outputFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rows, _ := out.GetRows("Sheet1")
+		out.Close()
+
+		expected := [][]string{
+			{"Name", "Hours", "Break"},
+			{"Alice", "07:30"},
+			{"Bob", "00:00", "00:30"},
+		}
+		if keepOriginal {
+			expected = [][]string{
+				{"Name", "Hours", "Hours (HH:MM)", "Break", "Break (HH:MM)"},
+				{"Alice", "7.5", "07:30", "0"},
+				{"Bob", "", "00:00", "0.5", "00:30"},
+			}
+		}
+		if !reflect.DeepEqual(rows, expected) {
+			t.Errorf("keepOriginal=%v: expected %v, got %v", keepOriginal, expected, rows)
+		}
+	}
+}
+
+func TestConvertXLSX_ExcelTime(t *testing.T) {
+	tmpDir := t.TempDir()
+	inputFile := filepath.Join(tmpDir, "input.xlsx")
+	outputFile := filepath.Join(tmpDir, "output.xlsx")
+
+	f := excelize.NewFile()
+	f.SetSheetRow("Sheet1", "A1", &[]any{"Name", "Shift", "Total", "Hours"})
+	f.SetSheetRow("Sheet1", "A2", &[]any{"Alice", 0.3125, 1.25, 7.5})
+	builtIn, _ := f.NewStyle(&excelize.Style{NumFmt: 20}) // h:mm
+	elapsed := "[h]:mm"
+	custom, _ := f.NewStyle(&excelize.Style{CustomNumFmt: &elapsed})
+	f.SetCellStyle("Sheet1", "B2", "B2", builtIn)
+	f.SetCellStyle("Sheet1", "C2", "C2", custom)
+	if err := f.SaveAs(inputFile); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	data, err := ReadFileData(inputFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(data.TimeColumns, []int{1, 2}) {
+		t.Errorf("Expected the time formatted columns, got %v", data.TimeColumns)
+	}
+	if data.Rows[0][1] != "0.3125" {
+		t.Errorf("Expected the time fraction to be read, got %q", data.Rows[0][1])
+	}
+	if detected := AutoDetectColumns(data); !reflect.DeepEqual(detected, []int{1, 2, 3}) {
+		t.Errorf("Expected time columns to be detected, got %v", detected)
+	}
+
+	if _, err := ConvertXLSX(inputFile, LocalFile(outputFile), []int{1, 2, 3}, types.ConversionOptions{}, nil); err != nil {
+		t.Fatalf("ConvertXLSX failed: %v", err)
+	}
+	out, err := excelize.OpenFile(outputFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer out.Close()
+	rows, _ := out.GetRows("Sheet1")
+	expected := []string{"Alice", "07:30", "30:00", "07:30"}
+	if !reflect.DeepEqual(rows[1], expected) {
+		t.Errorf("Expected %v, got %v", expected, rows[1])
+	}
+}
+
+func TestConvertXLSX_StoredValues(t *testing.T) {
+	for _, date1904 := range []bool{false, true} {
+		tmpDir := t.TempDir()
+		inputFile := filepath.Join(tmpDir, "input.xlsx")
+		outputFile := filepath.Join(tmpDir, "output.xlsx")
+
+		f := excelize.NewFile()
+		f.SetWorkbookProps(&excelize.WorkbookPropsOptions{Date1904: &date1904})
+		f.SetSheetRow("Sheet1", "A1", &[]any{"Date", "Rounded", "Scientific", "Grouped", "Shift"})
+		f.SetSheetRow("Sheet1", "A2", &[]any{45000, 7.5, 1.5, 1234.5, 0.3125})
+		for cell, numFmt := range map[string]int{"A2": 14, "B2": 1, "C2": 11, "D2": 4, "E2": 20} {
+			style, _ := f.NewStyle(&excelize.Style{NumFmt: numFmt})
+			f.SetCellStyle("Sheet1", cell, cell, style)
+		}
+		if err := f.SaveAs(inputFile); err != nil {
+			t.Fatal(err)
+		}
+		shown, _ := f.GetCellValue("Sheet1", "A2")
+		f.Close()
+
+		data, err := ReadFileData(inputFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Numbers are read as stored, dates as shown
+		expected := []string{shown, "7.5", "1.5", "1234.5", "0.3125"}
+		if !reflect.DeepEqual(data.Rows[0], expected) {
+			t.Errorf("date1904=%v: expected %v, got %v", date1904, expected, data.Rows[0])
+		}
+
+		if _, err := ConvertXLSX(inputFile, LocalFile(outputFile), []int{1, 2, 3, 4}, types.ConversionOptions{}, nil); err != nil {
+			t.Fatalf("ConvertXLSX failed: %v", err)
+		}
+		out, err := excelize.OpenFile(outputFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rows, _ := out.GetRows("Sheet1")
+		out.Close()
+		if got := rows[1][1:]; !reflect.DeepEqual(got, []string{"07:30", "01:30", "1234:30", "07:30"}) {
+			t.Errorf("date1904=%v: unexpected conversion %v", date1904, got)
+		}
+	}
+}
+
+func TestConvertXLSX_NewSheet(t *testing.T) {
+	inputFile := filepath.Join(t.TempDir(), "input.xlsx")
+
+	f := excelize.NewFile()
+	f.SetSheetRow("Sheet1", "A1", &[]any{"Name", "Hours"})
+	f.SetSheetRow("Sheet1", "A2", &[]any{"Alice", 1.5})
+	if err := f.SaveAs(inputFile); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	// Converting in place twice replaces the first converted sheet
+	opts := types.ConversionOptions{NewSheet: true, RecordChanges: true}
+	for range 2 {
+		result, err := ConvertXLSX(inputFile, ReplaceFile(inputFile), []int{1}, opts, nil)
+		if err != nil {
+			t.Fatalf("ConvertXLSX failed: %v", err)
+		}
+		if result.Changes[0].Sheet != "Sheet1 (converted)" {
+			t.Errorf("Expected changes on the new sheet, got %q", result.Changes[0].Sheet)
+		}
+	}
+
+	out, err := excelize.OpenFile(inputFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer out.Close()
+	if sheets := out.GetSheetList(); !reflect.DeepEqual(sheets, []string{"Sheet1", "Sheet1 (converted)"}) {
+		t.Fatalf("Unexpected sheets %v", sheets)
+	}
+	original, _ := out.GetRows("Sheet1")
+	converted, _ := out.GetRows("Sheet1 (converted)")
+	if original[1][1] != "1.5" || converted[1][1] != "01:30" {
+		t.Errorf("Expected the original sheet untouched and the copy converted, got %v and %v", original, converted)
+	}
+}
+
+func TestConvertXLSX_CommentOriginals(t *testing.T) {
+	tmpDir := t.TempDir()
+	inputFile := filepath.Join(tmpDir, "input.xlsx")
+	outputFile := filepath.Join(tmpDir, "output.xlsx")
+
+	f := excelize.NewFile()
+	f.SetSheetRow("Sheet1", "A1", &[]any{"Name", "Hours"})
+	f.SetSheetRow("Sheet1", "A2", &[]any{"Alice", 7.5})
+	f.SetSheetRow("Sheet1", "A3", &[]any{"Bob", 2})
+	f.AddComment("Sheet1", excelize.Comment{Cell: "B3", Author: "Payroll", Text: "Approved"})
+	if err := f.SaveAs(inputFile); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	opts := types.ConversionOptions{CommentOriginals: true}
+	if _, err := ConvertXLSX(inputFile, LocalFile(outputFile), []int{1}, opts, nil); err != nil {
+		t.Fatalf("ConvertXLSX failed: %v", err)
+	}
+
+	out, err := excelize.OpenFile(outputFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer out.Close()
+	comments, err := out.GetComments("Sheet1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	notes := make(map[string]string)
+	for _, comment := range comments {
+		var text strings.Builder
+		text.WriteString(comment.Text)
+		for _, run := range comment.Paragraph {
+			text.WriteString(run.Text)
+		}
+		notes[comment.Cell] = text.String()
+	}
+	if !strings.Contains(notes["B2"], "Original value: 7.5") {
+		t.Errorf("Expected the original value in a comment on B2, got %q", notes["B2"])
+	}
+	if !strings.Contains(notes["B3"], "Approved") || !strings.Contains(notes["B3"], "Original value: 2") {
+		t.Errorf("Expected the existing comment on B3 kept, got %q", notes["B3"])
+	}
+	if got, _ := out.GetCellValue("Sheet1", "B2"); got != "07:30" {
+		t.Errorf("Expected B2 converted, got %q", got)
+	}
+}
+
+func TestConvertXLSX_Table(t *testing.T) {
+	tmpDir := t.TempDir()
+	inputFile := filepath.Join(tmpDir, "input.xlsx")
+
+	f := excelize.NewFile()
+	f.SetSheetRow("Sheet1", "A1", &[]any{"Name", "Hours"})
+	f.SetSheetRow("Sheet1", "A2", &[]any{"Alice", 1.5})
+	f.SetSheetRow("Sheet1", "A3", &[]any{"Bob", 2})
+	f.SetSheetRow("Sheet1", "A4", &[]any{"Total", 3.5})
+	if err := f.SaveAs(inputFile); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	// Converting in place twice replaces the first table
+	opts := types.ConversionOptions{KeepOriginal: true, FooterRows: 1, NewSheet: true, Table: "Hours", TableStyle: "TableStyleLight9"}
+	for range 2 {
+		if _, err := ConvertXLSX(inputFile, ReplaceFile(inputFile), []int{1}, opts, nil); err != nil {
+			t.Fatalf("ConvertXLSX failed: %v", err)
+		}
+	}
+
+	out, err := excelize.OpenFile(inputFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tables, err := out.GetTables("Sheet1 (converted)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tables) != 1 || tables[0].Name != "Hours" || tables[0].Range != "A1:C3" || tables[0].StyleName != "TableStyleLight9" {
+		t.Errorf("Expected one table over the data rows, got %+v", tables)
+	}
+	out.Close()
+
+	// Data already in another table can't be put in a second one
+	f = excelize.NewFile()
+	f.SetSheetRow("Sheet1", "A1", &[]any{"Name", "Hours"})
+	f.SetSheetRow("Sheet1", "A2", &[]any{"Alice", 1.5})
+	f.AddTable("Sheet1", &excelize.Table{Range: "A1:B2", Name: "Existing"})
+	if err := f.SaveAs(inputFile); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	opts = types.ConversionOptions{Table: "Hours"}
+	if _, err := ConvertXLSX(inputFile, LocalFile(filepath.Join(tmpDir, "output.xlsx")), []int{1}, opts, nil); err == nil || !strings.Contains(err.Error(), `table "Existing"`) {
+		t.Errorf("Expected an error for data already in a table, got %v", err)
+	}
+}
+
+func TestCheckTableName(t *testing.T) {
+	for _, name := range []string{"Hours", "_hours", "Week_1", "Payroll.Export"} {
+		if err := CheckTableName(name); err != nil {
+			t.Errorf("CheckTableName(%q) = %v; want nil", name, err)
+		}
+	}
+	for _, name := range []string{"", "1st", "Weekly Hours", "Hours!", "A1", "XFD100", "R1C1", "c"} {
+		if err := CheckTableName(name); err == nil {
+			t.Errorf("CheckTableName(%q) = nil; want an error", name)
+		}
+	}
+	if err := CheckTableStyle("TableStyleMedium28"); err != nil {
+		t.Error(err)
+	}
+	if err := CheckTableStyle("TableStyleMedium29"); err == nil {
+		t.Error("Expected an unknown style to be rejected")
+	}
+}
+
+func TestConvertedSheetName(t *testing.T) {
+	if got := ConvertedSheetName("Sheet1"); got != "Sheet1 (converted)" {
+		t.Errorf("Unexpected name %q", got)
+	}
+	if got := ConvertedSheetName("Weekly Timesheet Export 2024"); got != "Weekly Timesheet Ex (converted)" {
+		t.Errorf("Expected a long name to be shortened, got %q", got)
+	}
+}
+
+func TestIsTimeFormat(t *testing.T) {
+	tests := map[string]bool{
+		"h:mm":               true,
+		"[h]:mm:ss":          true,
+		"hh:mm AM/PM":        true,
+		"mm:ss":              true,
+		"[Red]h:mm":          true,
+		"m/d/yy h:mm":        false,
+		"yyyy-mm-dd":         false,
+		"0.00":               false,
+		`0.00 "hours"`:       false,
+		`0 "h"`:              false,
+		"#,##0.00_);(#,##0)": false,
+	}
+	for code, expected := range tests {
+		if got := isTimeFormat(code); got != expected {
+			t.Errorf("isTimeFormat(%q) = %v; want %v", code, got, expected)
+		}
+	}
+}
+
+func TestConvertCSV_OnlySelected(t *testing.T) {
+	tmpDir := t.TempDir()
+	inputFile := filepath.Join(tmpDir, "input.csv")
+	outputFile := filepath.Join(tmpDir, "output.csv")
+
+	writeTestCSV(t, inputFile, [][]string{
+		{"ID", "Name", "Regular", "Dept", "Overtime"},
+		{"7", "Alice", "1.5", "Ops", "0.25"},
+		{"8", "Bob", "2.0", "Sales", "0.5"},
+	})
+
+	// The filter's column is dropped, but rows still match against it
+	opts := types.ConversionOptions{
+		OnlySelected:  true,
+		KeepColumns:   []int{0},
+		Columns:       map[int]types.ColumnSettings{4: {Header: "OT"}},
+		KeepOriginal:  true,
+		Filters:       []types.RowFilter{{Column: 3, Op: types.FilterEquals, Value: "ops"}},
+		RecordChanges: true,
+	}
+	result, err := ConvertCSV(inputFile, LocalFile(outputFile), []int{2, 4}, opts, nil)
+	if err != nil {
+		t.Fatalf("ConvertCSV failed: %v", err)
+	}
+
+	records := readTestCSV(t, outputFile)
+	expected := [][]string{
+		{"ID", "Regular", "Regular (HH:MM)", "Overtime", "OT"},
+		{"7", "1.5", "01:30", "0.25", "00:15"},
+		{"8", "2.0", "", "0.5", ""},
+	}
+	if !reflect.DeepEqual(records, expected) {
+		t.Errorf("Expected %v, got %v", expected, records)
+	}
+	if len(result.Changes) != 2 || result.Changes[1].Col != 4 {
+		t.Errorf("Expected changes numbered by input column, got %+v", result.Changes)
+	}
+}
+
+func TestConvertXLSX_OnlySelected(t *testing.T) {
+	tmpDir := t.TempDir()
+	inputFile := filepath.Join(tmpDir, "input.xlsx")
+	outputFile := filepath.Join(tmpDir, "output.xlsx")
+
+	f := excelize.NewFile()
+	f.SetSheetRow("Sheet1", "A1", &[]any{"ID", "Name", "Regular", "Dept"})
+	f.SetSheetRow("Sheet1", "A2", &[]any{7, "Alice", 1.5, "Ops"})
+	if err := f.SaveAs(inputFile); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	opts := types.ConversionOptions{OnlySelected: true, KeepColumns: []int{0}}
+	if _, err := ConvertXLSX(inputFile, LocalFile(outputFile), []int{2}, opts, nil); err != nil {
+		t.Fatalf("ConvertXLSX failed: %v", err)
+	}
+
+	out, err := excelize.OpenFile(outputFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer out.Close()
+	rows, _ := out.GetRows("Sheet1")
+	expected := [][]string{{"ID", "Regular"}, {"7", "01:30"}}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf("Expected %v, got %v", expected, rows)
+	}
+}
+
+func TestOutputOrder(t *testing.T) {
+	got := OutputOrder(4, []int{2, 9, 0, 2})
+	expected := []int{2, 0, 1, 3}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
+
+func TestConvertCSV_Order(t *testing.T) {
+	tmpDir := t.TempDir()
+	inputFile := filepath.Join(tmpDir, "input.csv")
+	outputFile := filepath.Join(tmpDir, "output.csv")
+
+	data := "Name,Regular,ID\nAlice,1.5,7\nBob,2.0,8,extra\n"
+	if err := os.WriteFile(inputFile, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := types.ConversionOptions{KeepOriginal: true, Order: []int{2, 1}, RecordChanges: true}
+	result, err := ConvertCSV(inputFile, LocalFile(outputFile), []int{1}, opts, nil)
+	if err != nil {
+		t.Fatalf("ConvertCSV failed: %v", err)
+	}
+
+	got, _ := os.ReadFile(outputFile)
+	expected := "ID,Regular,Regular (HH:MM),Name\n7,1.5,01:30,Alice\n8,2.0,02:00,Bob,extra\n"
+	if string(got) != expected {
+		t.Errorf("Unexpected output:\n%s", got)
+	}
+	if result.Changes[0].Col != 1 {
+		t.Errorf("Expected changes numbered by input column, got %+v", result.Changes)
+	}
+}
+
+func TestConvertXLSX_Order(t *testing.T) {
+	tmpDir := t.TempDir()
+	inputFile := filepath.Join(tmpDir, "input.xlsx")
+	outputFile := filepath.Join(tmpDir, "output.xlsx")
+
+	f := excelize.NewFile()
+	f.SetSheetRow("Sheet1", "A1", &[]any{"Name", "Regular", "ID"})
+	f.SetSheetRow("Sheet1", "A2", &[]any{"Alice", 1.5, 7})
+	if err := f.SaveAs(inputFile); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	opts := types.ConversionOptions{Order: []int{2, 1}}
+	if _, err := ConvertXLSX(inputFile, LocalFile(outputFile), []int{1}, opts, nil); err != nil {
+		t.Fatalf("ConvertXLSX failed: %v", err)
+	}
+
+	out, err := excelize.OpenFile(outputFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer out.Close()
+	rows, _ := out.GetRows("Sheet1")
+	expected := [][]string{{"ID", "Regular", "Name"}, {"7", "01:30", "Alice"}}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf("Expected %v, got %v", expected, rows)
+	}
+}
+
+func TestConvertCSV_RaggedRows(t *testing.T) {
+	tmpDir := t.TempDir()
+	inputFile := filepath.Join(tmpDir, "input.csv")
+	outputFile := filepath.Join(tmpDir, "output.csv")
+
+	// Exports often drop trailing empty fields or add stray ones
+	data := "Name,Hours,Note\nAlice,1.5\nBob,2.0,,extra\n"
+	if err := os.WriteFile(inputFile, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	fileData, err := ReadFileData(inputFile)
+	if err != nil {
+		t.Fatalf("ReadFileData failed: %v", err)
+	}
+	if fileData.PaddedRows != 1 {
+		t.Errorf("Expected 1 padded row, got %d", fileData.PaddedRows)
+	}
+
+	result, err := ConvertCSV(inputFile, LocalFile(outputFile), []int{1}, types.ConversionOptions{KeepOriginal: true}, nil)
+	if err != nil {
+		t.Fatalf("ConvertCSV failed: %v", err)
+	}
+	if len(result.Warnings) != 1 {
+		t.Errorf("Expected a padding warning, got %v", result.Warnings)
+	}
+
+	got, _ := os.ReadFile(outputFile)
+	expected := "Name,Hours,Hours (HH:MM),Note\nAlice,1.5,01:30,\nBob,2.0,02:00,,extra\n"
+	if string(got) != expected {
+		t.Errorf("Unexpected output:\n%s", got)
+	}
+}
+
+func TestConvertCSV_Changes(t *testing.T) {
+	tmpDir := t.TempDir()
+	inputFile := filepath.Join(tmpDir, "input.csv")
+	outputFile := filepath.Join(tmpDir, "output.csv")
+
+	writeTestCSV(t, inputFile, [][]string{
+		{"Name", "Regular", "Overtime"},
+		{"Alice", "8", ""},
+		{"Bob", "7.5", "1.25"},
+	})
+
+	expected := []types.CellChange{
+		{Row: 2, Col: 1, Column: "Regular", Original: "8", Converted: "08:00", Hours: 8, HasHours: true},
+		{Row: 3, Col: 1, Column: "Regular", Original: "7.5", Converted: "07:30", Hours: 7.5, HasHours: true},
+		{Row: 3, Col: 2, Column: "Overtime", Original: "1.25", Converted: "01:15", Hours: 1.25, HasHours: true},
+	}
+
+	for _, keepOriginal := range []bool{false, true} {
+		result, err := ConvertCSV(inputFile, LocalFile(outputFile), []int{2, 1}, types.ConversionOptions{KeepOriginal: keepOriginal, RecordChanges: true}, nil)
+		if err != nil {
+			t.Fatalf("ConvertCSV failed: %v", err)
+		}
+		if !reflect.DeepEqual(result.Changes, expected) {
+			t.Errorf("keepOriginal=%v: unexpected changes %+v", keepOriginal, result.Changes)
+		}
+	}
+
+	// Without recording them, changes are only counted and their hours totalled
+	result, err := ConvertCSV(inputFile, LocalFile(outputFile), []int{2, 1}, types.ConversionOptions{}, nil)
+	if err != nil {
+		t.Fatalf("ConvertCSV failed: %v", err)
+	}
+	hours := []types.ColumnHours{{Column: "Regular", Cells: 2, Hours: 15.5}, {Column: "Overtime", Cells: 1, Hours: 1.25}}
+	if result.Changes != nil || result.CellsChanged != 3 || !reflect.DeepEqual(result.Hours, hours) {
+		t.Errorf("Expected 3 cells counted without their changes, got %+v", result)
+	}
+}
+
+func TestConvertXLSX_Changes(t *testing.T) {
+	tmpDir := t.TempDir()
+	inputFile := filepath.Join(tmpDir, "input.xlsx")
+	outputFile := filepath.Join(tmpDir, "output.xlsx")
+
+	// Blank and non-numeric cells in converted columns aren't changes
+	f := excelize.NewFile()
+	f.SetSheetRow("Sheet1", "A1", &[]any{"Name", "Regular", "Overtime"})
+	f.SetSheetRow("Sheet1", "A2", &[]any{"Alice", 8, nil})
+	f.SetSheetRow("Sheet1", "A3", &[]any{"Bob", 7.5, "n/a"})
+	f.SetSheetRow("Sheet1", "A4", &[]any{"Carol", nil, 1.25})
+	f.SetSheetRow("Sheet1", "A5", &[]any{"Dave", 6, 2})
+	if err := f.SaveAs(inputFile); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	expected := []types.CellChange{
+		{Sheet: "Sheet1", Row: 2, Col: 1, Column: "Regular", Original: "8", Converted: "08:00", Hours: 8, HasHours: true},
+		{Sheet: "Sheet1", Row: 3, Col: 1, Column: "Regular", Original: "7.5", Converted: "07:30", Hours: 7.5, HasHours: true},
+		{Sheet: "Sheet1", Row: 4, Col: 2, Column: "Overtime", Original: "1.25", Converted: "01:15", Hours: 1.25, HasHours: true},
+		{Sheet: "Sheet1", Row: 5, Col: 1, Column: "Regular", Original: "6", Converted: "06:00", Hours: 6, HasHours: true},
+		{Sheet: "Sheet1", Row: 5, Col: 2, Column: "Overtime", Original: "2", Converted: "02:00", Hours: 2, HasHours: true},
+	}
+
+	for _, keepOriginal := range []bool{false, true} {
+		result, err := ConvertXLSX(inputFile, LocalFile(outputFile), []int{1, 2}, types.ConversionOptions{KeepOriginal: keepOriginal, RecordChanges: true}, nil)
+		if err != nil {
+			t.Fatalf("ConvertXLSX failed: %v", err)
+		}
+		if !reflect.DeepEqual(result.Changes, expected) {
+			t.Errorf("keepOriginal=%v: unexpected changes %+v", keepOriginal, result.Changes)
+		}
+		if result.RowsProcessed != 4 || result.CellsChanged != 5 || len(result.Warnings) != 1 {
+			t.Errorf("keepOriginal=%v: expected 4 rows, 5 cells changed and a warning, got %d, %d, %v", keepOriginal, result.RowsProcessed, result.CellsChanged, result.Warnings)
+		}
+	}
+}
+
+func TestConvert_ColumnOrder(t *testing.T) {
+	tmpDir := t.TempDir()
+	csvInput := filepath.Join(tmpDir, "input.csv")
+	xlsxInput := filepath.Join(tmpDir, "input.xlsx")
+
+	records := [][]string{
+		{"Name", "Regular", "Overtime", "Break"},
+		{"Alice", "8", "0.5", "0.25"},
+	}
+	writeTestCSV(t, csvInput, records)
+	f := excelize.NewFile()
+	for i, record := range records {
+		cell, _ := excelize.CoordinatesToCellName(1, i+1)
+		f.SetSheetRow("Sheet1", cell, &record)
+	}
+	if err := f.SaveAs(xlsxInput); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	// Columns chosen out of order, or twice, are converted left to right once
+	expected := []string{"Regular", "Overtime", "Break"}
+	for _, keepOriginal := range []bool{false, true} {
+		opts := types.ConversionOptions{KeepOriginal: keepOriginal}
+		csvResult, err := ConvertCSV(csvInput, LocalFile(filepath.Join(tmpDir, "output.csv")), []int{3, 1, 2, 3}, opts, nil)
+		if err != nil {
+			t.Fatalf("ConvertCSV failed: %v", err)
+		}
+		xlsxResult, err := ConvertXLSX(xlsxInput, LocalFile(filepath.Join(tmpDir, "output.xlsx")), []int{3, 1, 2, 3}, opts, nil)
+		if err != nil {
+			t.Fatalf("ConvertXLSX failed: %v", err)
+		}
+		if !reflect.DeepEqual(csvResult.ColumnsFound, expected) {
+			t.Errorf("keepOriginal=%v: expected CSV columns %v, got %v", keepOriginal, expected, csvResult.ColumnsFound)
+		}
+		if !reflect.DeepEqual(xlsxResult.ColumnsFound, expected) {
+			t.Errorf("keepOriginal=%v: expected XLSX columns %v, got %v", keepOriginal, expected, xlsxResult.ColumnsFound)
+		}
+	}
+}
+
+func TestConvertCSV_RoundingLoss(t *testing.T) {
+	tmpDir := t.TempDir()
+	inputFile := filepath.Join(tmpDir, "input.csv")
+	outputFile := filepath.Join(tmpDir, "output.csv")
+
+	writeTestCSV(t, inputFile, [][]string{
+		{"Name", "Hours"},
+		{"Alice", "1.98"},
+		{"Bob", "2.5"},
+		{"Carol", "1.005"},
+	})
+
+	tests := []struct {
+		name     string
+		opts     types.ConversionOptions
+		expected []string // Converted values of the lossy cells
+	}{
+		{"Nearest stays within half a minute", types.ConversionOptions{}, nil},
+		{"Rounding down", types.ConversionOptions{Columns: map[int]types.ColumnSettings{1: {Rounding: types.RoundDown}}}, []string{"01:58"}},
+		{"Lower threshold", types.ConversionOptions{LossThreshold: 0.25}, []string{"01:00"}},
+		{"Rounding up", types.ConversionOptions{Columns: map[int]types.ColumnSettings{1: {Rounding: types.RoundUp}}}, []string{"01:01"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ConvertCSV(inputFile, LocalFile(outputFile), []int{1}, tt.opts, nil)
+			if err != nil {
+				t.Fatalf("ConvertCSV failed: %v", err)
+			}
+
+			var lossy []string
+			for _, change := range result.Lossy {
+				lossy = append(lossy, change.Converted)
+			}
+			if !reflect.DeepEqual(lossy, tt.expected) {
+				t.Errorf("Expected lossy cells %v, got %v", tt.expected, lossy)
+			}
+			if len(tt.expected) > 0 && len(result.Warnings) == 0 {
+				t.Error("Expected a rounding loss warning")
+			}
+		})
+	}
+}
+
+func TestCheckPattern(t *testing.T) {
+	for _, pattern := range []string{"H'h' mm'm'", "hh:mm:ss", "m", "H 'hours'"} {
+		if err := CheckPattern(pattern); err != nil {
+			t.Errorf("CheckPattern(%q) = %v; want nil", pattern, err)
+		}
+	}
+	for _, pattern := range []string{"", "'h'", "hours", "hhh:mm", "h:mm:h", "h 'hours", "HH:MM"} {
+		if err := CheckPattern(pattern); err == nil {
+			t.Errorf("CheckPattern(%q) = nil; want an error", pattern)
+		}
+	}
+}
+
+func TestParseConverted(t *testing.T) {
+	tests := []struct {
+		value    string
+		settings types.ColumnSettings
+		expected float64
+	}{
+		{"07:30", types.ColumnSettings{}, 7.5},
+		{"7:30", types.ColumnSettings{Format: types.FormatHMM}, 7.5},
+		{"01:00:36", types.ColumnSettings{Format: types.FormatHHMMSS}, 1.01},
+		{"450", types.ColumnSettings{Format: types.FormatMinutes}, 7.5},
+		{"7h 30m", types.ColumnSettings{Pattern: "H'h' mm'm'"}, 7.5},
+		{"0730", types.ColumnSettings{Pattern: "hhmm"}, 7.5},
+		{"1230", types.ColumnSettings{Pattern: "Hmm"}, 12.5},
+		{"01.00.36", types.ColumnSettings{Pattern: "hh.mm.ss"}, 1.01},
+		{"450 min", types.ColumnSettings{Pattern: "m' min'"}, 7.5},
+	}
+
+	for _, tt := range tests {
+		got, ok := ParseConverted(tt.value, tt.settings)
+		if !ok || math.Abs(got-tt.expected) > 1e-9 {
+			t.Errorf("ParseConverted(%q) = %v, %v; want %v", tt.value, got, ok, tt.expected)
+		}
+	}
+	if _, ok := ParseConverted("soon", types.ColumnSettings{}); ok {
+		t.Error("Expected an unparseable value to fail")
+	}
+}
+
+func writeTestCSV(t *testing.T, path string, records [][]string) {
+	t.Helper()
+
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	if err := w.WriteAll(records); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readTestCSV(t *testing.T, path string) [][]string {
+	t.Helper()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return records
+}
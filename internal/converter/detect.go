@@ -0,0 +1,187 @@
+package converter
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/nconklindev/chronos/internal/types"
+)
+
+// SampleCheck records how one sampled value was judged during detection.
+type SampleCheck struct {
+	Row    int    // Data row the value came from (0-index, excluding the header)
+	Value  string // The trimmed value
+	OK     bool   // Whether the value looks like a decimal hour
+	Reason string // Why the value was rejected or skipped
+}
+
+// ColumnTrace explains why a column was or wasn't auto-detected.
+type ColumnTrace struct {
+	Index    int
+	Header   string
+	Detected bool
+	Reason   string
+	Samples  []SampleCheck // Values checked, up to and including the first rejected one
+}
+
+// checkDecimalHour is IsDecimalHour with the reason a value was rejected
+func checkDecimalHour(s string) (bool, string) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return false, "empty"
+	}
+
+	val, err := ParseNumber(s, types.DecimalAuto)
+	if err != nil {
+		return false, "not a number"
+	}
+	if val < 0 {
+		return false, "negative"
+	}
+	if val >= 10000 {
+		return false, "10000 or more, too large to be hours"
+	}
+	return true, ""
+}
+
+// TraceDetection runs column auto-detection and records the reasoning for
+// every column. A column is detected when it has at least one non-empty
+// value in the first RowDetectionLimit data rows and every such value is a
+// decimal hour.
+func TraceDetection(data *types.FileData) []ColumnTrace {
+	// Footer rows hold totals, which would otherwise skew detection on small files
+	dataRows := len(data.Rows) - data.FooterRows
+	if dataRows < 0 {
+		dataRows = 0
+	}
+
+	timeCols := make(map[int]bool, len(data.TimeColumns))
+	for _, idx := range data.TimeColumns {
+		timeCols[idx] = true
+	}
+
+	traces := make([]ColumnTrace, 0, len(data.Headers))
+	for i, header := range data.Headers {
+		trace := ColumnTrace{Index: i, Header: header}
+		checkedRows := 0
+		var rejected *SampleCheck
+
+		// Check first 10 data rows
+		for j := 0; j < dataRows && j < RowDetectionLimit; j++ {
+			if i >= len(data.Rows[j]) {
+				continue
+			}
+
+			val := strings.TrimSpace(data.Rows[j][i])
+			if val == "" {
+				trace.Samples = append(trace.Samples, SampleCheck{Row: j, Reason: "empty, skipped"})
+				continue
+			}
+
+			ok, reason := checkDecimalHour(val)
+			trace.Samples = append(trace.Samples, SampleCheck{Row: j, Value: val, OK: ok, Reason: reason})
+			if !ok {
+				rejected = &trace.Samples[len(trace.Samples)-1]
+				break
+			}
+			checkedRows++
+		}
+
+		switch {
+		case rejected != nil:
+			trace.Reason = fmt.Sprintf("row %d: %q is %s", rejected.Row+1, rejected.Value, rejected.Reason)
+		case dataRows == 0:
+			trace.Reason = "no data rows to sample"
+		case checkedRows == 0:
+			trace.Reason = "every sampled value is empty"
+		case timeCols[i]:
+			trace.Detected = true
+			trace.Reason = fmt.Sprintf("formatted as times; all %d sampled values are Excel time fractions", checkedRows)
+		default:
+			trace.Detected = true
+			trace.Reason = fmt.Sprintf("all %d sampled values are decimal hours", checkedRows)
+		}
+
+		traces = append(traces, trace)
+	}
+
+	return traces
+}
+
+// datePattern matches values that start with a date such as 2024-01-31,
+// 1/31/24 or 31-01-2024.
+var datePattern = regexp.MustCompile(`^\d{1,4}[-/]\d{1,2}[-/]\d{1,4}\b`)
+
+// currencySymbols mark values that are amounts of money.
+const currencySymbols = "$€£¥"
+
+// SuspectColumn explains why a column's sampled values look like something
+// other than hours, such as IDs, money or dates, or is empty when they
+// don't. It samples the same rows as detection.
+func SuspectColumn(data *types.FileData, col int) string {
+	dataRows := max(len(data.Rows)-data.FooterRows, 0)
+
+	var whole []string
+	sampled := 0
+	for j := 0; j < dataRows && j < RowDetectionLimit; j++ {
+		if col >= len(data.Rows[j]) {
+			continue
+		}
+		val := strings.TrimSpace(data.Rows[j][col])
+		if val == "" {
+			continue
+		}
+		sampled++
+
+		switch {
+		case strings.ContainsAny(val, currencySymbols):
+			return fmt.Sprintf("%q looks like money", val)
+		case datePattern.MatchString(val):
+			return fmt.Sprintf("%q looks like a date", val)
+		}
+		if n, err := strconv.ParseInt(val, 10, 64); err == nil && (n > 1000 || len(val) > 1 && val[0] == '0') {
+			whole = append(whole, val)
+		}
+	}
+
+	// Hours are sometimes whole, but not large or zero-padded on every row
+	if sampled > 0 && len(whole) == sampled {
+		return fmt.Sprintf("values like %q are whole numbers that look like IDs", whole[0])
+	}
+	return ""
+}
+
+// ResolveColumns turns a comma-separated list of header names or 0-based
+// indices into column indices. Names are matched ignoring case and spaces.
+func ResolveColumns(value string, headers []string) ([]int, error) {
+	var columns []int
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+
+		if idx, err := strconv.Atoi(field); err == nil {
+			if idx < 0 || idx >= len(headers) {
+				return nil, fmt.Errorf("column %d is out of range", idx)
+			}
+			columns = append(columns, idx)
+			continue
+		}
+
+		found := false
+		for i, header := range headers {
+			if strings.EqualFold(strings.TrimSpace(header), field) {
+				columns = append(columns, i)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("no column named %q", field)
+		}
+	}
+	return columns, nil
+}
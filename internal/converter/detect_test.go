@@ -0,0 +1,80 @@
+package converter
+
+import (
+	"testing"
+
+	"github.com/nconklindev/chronos/internal/types"
+)
+
+func TestTraceDetection(t *testing.T) {
+	data := &types.FileData{
+		Headers: []string{"Name", "Hours", "Notes", "Badge"},
+		Rows: [][]string{
+			{"Alice", "8.0", "", "12000"},
+			{"Bob", "7.5", "", "12001"},
+		},
+	}
+
+	tests := []struct {
+		header   string
+		detected bool
+		reason   string
+	}{
+		{"Name", false, `row 1: "Alice" is not a number`},
+		{"Hours", true, "all 2 sampled values are decimal hours"},
+		{"Notes", false, "every sampled value is empty"},
+		{"Badge", false, `row 1: "12000" is 10000 or more, too large to be hours`},
+	}
+
+	traces := TraceDetection(data)
+	if len(traces) != len(tests) {
+		t.Fatalf("Expected %d traces, got %d", len(tests), len(traces))
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			trace := traces[i]
+			if trace.Header != tt.header || trace.Detected != tt.detected || trace.Reason != tt.reason {
+				t.Errorf("Got %s detected=%v %q; want %s detected=%v %q",
+					trace.Header, trace.Detected, trace.Reason, tt.header, tt.detected, tt.reason)
+			}
+		})
+	}
+
+	// Sampling stops at the first rejected value
+	if len(traces[0].Samples) != 1 {
+		t.Errorf("Expected 1 sample for Name, got %d", len(traces[0].Samples))
+	}
+}
+
+func TestSuspectColumn(t *testing.T) {
+	data := &types.FileData{
+		Headers: []string{"Hours", "Badge", "Code", "Pay", "Date", "Stamp", "Shift", "Mixed"},
+		Rows: [][]string{
+			{"8.0", "12000", "0042", "$12.50", "2024-01-31", "1/31/24 08:00", "8", "1200"},
+			{"7.5", "12001", "0043", "", "2024-02-01", "", "12", "7.5"},
+			{"", "", "", "€9", "", "", "", ""},
+			{"1250", "99", "", "Total", "", "", "", ""},
+		},
+		FooterRows: 1,
+	}
+
+	tests := []struct {
+		col    int
+		reason string
+	}{
+		{0, ""},
+		{1, `values like "12000" are whole numbers that look like IDs`},
+		{2, `values like "0042" are whole numbers that look like IDs`},
+		{3, `"$12.50" looks like money`},
+		{4, `"2024-01-31" looks like a date`},
+		{5, `"1/31/24 08:00" looks like a date`},
+		{6, ""},
+		{7, ""},
+	}
+	for _, tt := range tests {
+		if got := SuspectColumn(data, tt.col); got != tt.reason {
+			t.Errorf("SuspectColumn(%s) = %q, expected %q", data.Headers[tt.col], got, tt.reason)
+		}
+	}
+}
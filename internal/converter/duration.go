@@ -0,0 +1,345 @@
+package converter
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nconklindev/chronos/internal/types"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// DefaultDurationHeader names a computed duration column when the file has
+// a single pair of start and end columns.
+const DefaultDurationHeader = "Duration"
+
+// datedLayouts are the timestamps read with a date. Dates are month first,
+// the way US timekeeping systems export them.
+var datedLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02 3:04:05 PM",
+	"2006-01-02 3:04 PM",
+	"1/2/2006 15:04:05",
+	"1/2/2006 15:04",
+	"1/2/2006 3:04:05 PM",
+	"1/2/2006 3:04 PM",
+	"1/2/06 15:04:05",
+	"1/2/06 15:04",
+	"1/2/06 3:04 PM",
+	"01-02-06 15:04",
+}
+
+// clockLayouts are the times of day read without a date.
+var clockLayouts = []string{
+	"15:04:05",
+	"15:04",
+	"3:04:05 PM",
+	"3:04 PM",
+	"3:04PM",
+}
+
+// parseTimestamp reads a date and time, or a time of day alone, reporting
+// which it was. Dates without a time aren't timestamps.
+func parseTimestamp(s string) (t time.Time, dated bool, err error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	for _, layout := range datedLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true, nil
+		}
+	}
+	for _, layout := range clockLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, false, nil
+		}
+	}
+	return time.Time{}, false, fmt.Errorf("%q isn't a date and time or a time of day", s)
+}
+
+// Elapsed returns the hours from start to end. Excel stores timestamps as
+// days, so columns whose unit is UnitExcelTime are read as numbers of
+// days. Times of day without a date are taken to end the next day when end
+// is earlier, as in overnight shifts.
+func Elapsed(start, end string, s types.ColumnSettings) (float64, error) {
+	if s.Unit == types.UnitExcelTime {
+		from, err := strconv.ParseFloat(strings.TrimSpace(start), 64)
+		if err != nil {
+			return 0, fmt.Errorf("%q isn't an Excel time", start)
+		}
+		to, err := strconv.ParseFloat(strings.TrimSpace(end), 64)
+		if err != nil {
+			return 0, fmt.Errorf("%q isn't an Excel time", end)
+		}
+		if to < from && from < 1 && to < 1 {
+			to++
+		}
+		return checkElapsed((to - from) * 24)
+	}
+
+	from, fromDated, err := parseTimestamp(start)
+	if err != nil {
+		return 0, err
+	}
+	to, toDated, err := parseTimestamp(end)
+	if err != nil {
+		return 0, err
+	}
+	if fromDated != toDated {
+		return 0, fmt.Errorf("only one of %q and %q has a date", start, end)
+	}
+	if !fromDated && to.Before(from) {
+		to = to.Add(24 * time.Hour)
+	}
+	return checkElapsed(to.Sub(from).Hours())
+}
+
+// checkElapsed rejects durations that end before they start.
+func checkElapsed(hours float64) (float64, error) {
+	if hours < 0 {
+		return 0, fmt.Errorf("ends before it starts")
+	}
+	return hours, nil
+}
+
+// formatHours writes computed hours to the microhour, well within a second.
+func formatHours(hours float64) string {
+	return strconv.FormatFloat(math.Round(hours*1e6)/1e6, 'f', -1, 64)
+}
+
+// durationCells returns the computed cells of a row, one for each pair.
+// Cells are left empty when either timestamp is, and counted in invalid
+// when they can't be read.
+func durationCells(row []string, pairs []types.DurationPair, opts types.ConversionOptions, invalid *int) []string {
+	cells := make([]string, len(pairs))
+	for i, pair := range pairs {
+		var start, end string
+		if pair.Start < len(row) {
+			start = row[pair.Start]
+		}
+		if pair.End < len(row) {
+			end = row[pair.End]
+		}
+		if strings.TrimSpace(start) == "" || strings.TrimSpace(end) == "" {
+			continue
+		}
+		hours, err := Elapsed(start, end, opts.Columns[pair.Start])
+		if err != nil {
+			*invalid++
+			continue
+		}
+		cells[i] = formatHours(hours)
+	}
+	return cells
+}
+
+// addDurationRecords appends the computed duration columns to records
+// after the header's last column, ahead of any stray cells past it. The
+// header row gets the pairs' headers, and footer rows are left empty.
+func addDurationRecords(records [][]string, opts types.ConversionOptions) ([][]string, int) {
+	width := len(records[0])
+	firstFooter := footerStart(len(records)-1, opts) + 1
+	invalid := 0
+
+	added := make([][]string, len(records))
+	for i, record := range records {
+		cells := make([]string, len(opts.Durations))
+		switch {
+		case i == 0:
+			for j, pair := range opts.Durations {
+				cells[j] = pair.Header
+			}
+		case i < firstFooter:
+			cells = durationCells(record, opts.Durations, opts, &invalid)
+		}
+
+		added[i] = insertCells(record, width, cells)
+	}
+	return added, invalid
+}
+
+// addDurationColumns inserts the computed duration columns into sheet
+// after the header's last column, and into rows, which hold the values
+// read from it.
+func addDurationColumns(f *excelize.File, sheet string, rows [][]string, headerRowIdx int, opts types.ConversionOptions) (int, error) {
+	width := len(rows[headerRowIdx])
+	first, _ := excelize.ColumnNumberToName(width + 1)
+	if err := f.InsertCols(sheet, first, len(opts.Durations)); err != nil {
+		return 0, err
+	}
+
+	// Footers count from the bottom of the data, below the header
+	firstFooter := headerRowIdx + 1 + footerStart(len(rows)-headerRowIdx-1, opts)
+	invalid := 0
+	for i := headerRowIdx; i < len(rows); i++ {
+		var cells []string
+		switch {
+		case i == headerRowIdx:
+			for _, pair := range opts.Durations {
+				cells = append(cells, pair.Header)
+			}
+		case i < firstFooter:
+			cells = durationCells(rows[i], opts.Durations, opts, &invalid)
+		default:
+			cells = make([]string, len(opts.Durations))
+		}
+
+		for j, cell := range cells {
+			if cell == "" {
+				continue
+			}
+			name, _ := excelize.CoordinatesToCellName(width+j+1, i+1)
+			if err := setDurationCell(f, sheet, name, cell, i == headerRowIdx); err != nil {
+				return 0, err
+			}
+		}
+		rows[i] = insertCells(rows[i], width, cells)
+	}
+	return invalid, nil
+}
+
+// setDurationCell writes a computed cell, as a number unless it's the header.
+func setDurationCell(f *excelize.File, sheet, cell, value string, header bool) error {
+	if hours, err := strconv.ParseFloat(value, 64); err == nil && !header {
+		return f.SetCellFloat(sheet, cell, hours, -1, 64)
+	}
+	return f.SetCellStr(sheet, cell, value)
+}
+
+// insertCells returns row with cells inserted after its first width cells,
+// padding it to width first.
+func insertCells(row []string, width int, cells []string) []string {
+	inserted := make([]string, 0, max(len(row), width)+len(cells))
+	inserted = append(inserted, row[:min(len(row), width)]...)
+	for len(inserted) < width {
+		inserted = append(inserted, "")
+	}
+	inserted = append(inserted, cells...)
+	if len(row) > width {
+		inserted = append(inserted, row[width:]...)
+	}
+	return inserted
+}
+
+// durationWarnings reports the durations that couldn't be computed.
+func durationWarnings(invalid int) []string {
+	if invalid == 0 {
+		return nil
+	}
+	return []string{fmt.Sprintf("%d duration(s) couldn't be computed from their start and end times", invalid)}
+}
+
+// WithDurations returns a copy of data with the computed duration columns
+// added, numbered the way conversion numbers them, so they can be
+// previewed and chosen like the columns read.
+func WithDurations(data *types.FileData, pairs []types.DurationPair) *types.FileData {
+	if len(pairs) == 0 {
+		return data
+	}
+	opts := types.ConversionOptions{
+		FooterRows: data.FooterRows,
+		Columns:    WithTimeColumns(nil, data.TimeColumns),
+		Durations:  pairs,
+	}
+	records := append([][]string{data.Headers}, data.Rows...)
+	records, _ = addDurationRecords(records, opts)
+
+	added := *data
+	added.Headers = records[0]
+	added.Rows = records[1:]
+	return &added
+}
+
+// WithoutTimestamps drops the start and end columns of pairs from
+// columns. Times of day formatted as Excel times are detected as hours,
+// but it's their durations that are wanted.
+func WithoutTimestamps(columns []int, pairs []types.DurationPair) []int {
+	timestamps := make(map[int]bool)
+	for _, pair := range pairs {
+		timestamps[pair.Start], timestamps[pair.End] = true, true
+	}
+	var kept []int
+	for _, idx := range columns {
+		if !timestamps[idx] {
+			kept = append(kept, idx)
+		}
+	}
+	return kept
+}
+
+// startWords and endWords mark the headers of start and end columns, such
+// as "Start Time" and "End Time" or "Clock In" and "Clock Out".
+var (
+	startWords = map[string]bool{"start": true, "begin": true, "in": true}
+	endWords   = map[string]bool{"end": true, "finish": true, "stop": true, "out": true}
+)
+
+// timestampRole splits a header into whether it names a start or an end,
+// and the rest of it, which pairs it with its other half.
+func timestampRole(header string) (start, end bool, rest string) {
+	words := headerWords(header)
+	for i, word := range words {
+		if startWords[word] || endWords[word] {
+			others := append(append([]string(nil), words[:i]...), words[i+1:]...)
+			return startWords[word], endWords[word], strings.Join(others, " ")
+		}
+	}
+	return false, false, ""
+}
+
+// DetectDurations finds pairs of start and end columns holding timestamps,
+// such as "Start Time" and "End Time", for files without a column of
+// hours. A column pairs with the first one after it whose header differs
+// only by the start or end word, when their sampled values can be read as
+// the times of a duration.
+func DetectDurations(data *types.FileData) []types.DurationPair {
+	opts := types.ConversionOptions{Columns: WithTimeColumns(nil, data.TimeColumns)}
+	dataRows := max(len(data.Rows)-data.FooterRows, 0)
+
+	// timed reports whether the sampled values of a pair are durations
+	timed := func(start, end int) bool {
+		checked := 0
+		for j := 0; j < dataRows && j < RowDetectionLimit; j++ {
+			invalid := 0
+			cells := durationCells(data.Rows[j], []types.DurationPair{{Start: start, End: end}}, opts, &invalid)
+			if invalid > 0 {
+				return false
+			}
+			if cells[0] != "" {
+				checked++
+			}
+		}
+		return checked > 0
+	}
+
+	var pairs []types.DurationPair
+	paired := make(map[int]bool)
+	for i, header := range data.Headers {
+		isStart, _, rest := timestampRole(header)
+		if !isStart || paired[i] {
+			continue
+		}
+		for j := i + 1; j < len(data.Headers); j++ {
+			_, isEnd, endRest := timestampRole(data.Headers[j])
+			if !isEnd || paired[j] || endRest != rest || !timed(i, j) {
+				continue
+			}
+			paired[i], paired[j] = true, true
+			pairs = append(pairs, types.DurationPair{Start: i, End: j})
+			break
+		}
+	}
+
+	for i := range pairs {
+		pairs[i].Header = DefaultDurationHeader
+		if len(pairs) > 1 {
+			pairs[i].Header = strings.TrimSpace(data.Headers[pairs[i].Start]) + " " + DefaultDurationHeader
+		}
+	}
+	return pairs
+}
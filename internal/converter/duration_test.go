@@ -0,0 +1,170 @@
+package converter
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/nconklindev/chronos/internal/types"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestElapsed(t *testing.T) {
+	excelTime := types.ColumnSettings{Unit: types.UnitExcelTime}
+	tests := []struct {
+		start, end string
+		s          types.ColumnSettings
+		expected   float64
+		ok         bool
+	}{
+		{"2024-01-02 08:00", "2024-01-02 16:30", types.ColumnSettings{}, 8.5, true},
+		{"2024-01-02T22:00:00Z", "2024-01-03T06:15:00Z", types.ColumnSettings{}, 8.25, true},
+		{"1/2/2024 8:00 AM", "1/2/2024 4:30 pm", types.ColumnSettings{}, 8.5, true},
+		{"1/2/24 08:30", "1/2/24 17:00", types.ColumnSettings{}, 8.5, true},
+		{"9:00", "17:45", types.ColumnSettings{}, 8.75, true},
+		{"8:00 AM", "12:30 PM", types.ColumnSettings{}, 4.5, true},
+		// Times of day alone wrap past midnight
+		{"22:00", "06:00", types.ColumnSettings{}, 8, true},
+		{"0.375", "0.71875", excelTime, 8.25, true},
+		{"0.875", "0.25", excelTime, 9, true},
+		{"45293.25", "45293.75", excelTime, 12, true},
+		{"2024-01-02 16:00", "2024-01-02 08:00", types.ColumnSettings{}, 0, false},
+		{"2024-01-02 08:00", "16:00", types.ColumnSettings{}, 0, false},
+		{"2024-01-02", "2024-01-03", types.ColumnSettings{}, 0, false},
+		{"0.375", "0.71875", types.ColumnSettings{}, 0, false},
+		{"Alice", "17:00", types.ColumnSettings{}, 0, false},
+	}
+	for _, tt := range tests {
+		got, err := Elapsed(tt.start, tt.end, tt.s)
+		if (err == nil) != tt.ok || formatHours(got) != formatHours(tt.expected) {
+			t.Errorf("Elapsed(%q, %q) = %v, %v, expected %v", tt.start, tt.end, got, err, tt.expected)
+		}
+	}
+}
+
+func TestDetectDurations(t *testing.T) {
+	data := &types.FileData{
+		Headers: []string{"Name", "Start Time", "Break Start", "Break End", "End Time", "Login", "Logout"},
+		Rows: [][]string{
+			{"Alice", "2024-01-02 08:00", "12:00", "12:30", "2024-01-02 16:30", "alice", "yes"},
+			{"Bob", "", "", "", "", "bob", "no"},
+		},
+	}
+
+	expected := []types.DurationPair{
+		{Start: 1, End: 4, Header: "Start Time Duration"},
+		{Start: 2, End: 3, Header: "Break Start Duration"},
+	}
+	if got := DetectDurations(data); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+
+	// A single pair is just the duration, and text that isn't times doesn't pair
+	data.Headers[2], data.Headers[3] = "Clock In", "Clock Out"
+	data.Rows[0][2] = "desk"
+	expected = []types.DurationPair{{Start: 1, End: 4, Header: DefaultDurationHeader}}
+	if got := DetectDurations(data); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
+
+func TestConvertCSV_Durations(t *testing.T) {
+	tmpDir := t.TempDir()
+	inputFile := filepath.Join(tmpDir, "input.csv")
+	outputFile := filepath.Join(tmpDir, "output.csv")
+	writeTestCSV(t, inputFile, [][]string{
+		{"Name", "Start Time", "End Time"},
+		{"Alice", "2024-01-02 08:00", "2024-01-02 16:30"},
+		{"Bob", "22:00", "06:15"},
+		{"Carol", "", ""},
+		{"Dave", "17:00", "soon"},
+		{"Total", "", ""},
+	})
+
+	opts := types.ConversionOptions{
+		KeepOriginal: true,
+		FooterRows:   1,
+		Durations:    []types.DurationPair{{Start: 1, End: 2, Header: "Duration"}},
+	}
+	result, err := ConvertCSV(inputFile, LocalFile(outputFile), []int{3}, opts, nil)
+	if err != nil {
+		t.Fatalf("ConvertCSV failed: %v", err)
+	}
+
+	expected := [][]string{
+		{"Name", "Start Time", "End Time", "Duration", "Duration (HH:MM)"},
+		{"Alice", "2024-01-02 08:00", "2024-01-02 16:30", "8.5", "08:30"},
+		{"Bob", "22:00", "06:15", "8.25", "08:15"},
+		{"Carol", "", "", "", ""},
+		{"Dave", "17:00", "soon", "", ""},
+		{"Total", "", "", "", ""},
+	}
+	if records := readTestCSV(t, outputFile); !reflect.DeepEqual(records, expected) {
+		t.Errorf("Expected %v, got %v", expected, records)
+	}
+	if !reflect.DeepEqual(result.ColumnsFound, []string{"Duration"}) {
+		t.Errorf("Expected the duration to be converted, got %v", result.ColumnsFound)
+	}
+	if len(result.Warnings) != 1 || result.Warnings[0] != "1 duration(s) couldn't be computed from their start and end times" {
+		t.Errorf("Unexpected warnings: %v", result.Warnings)
+	}
+}
+
+func TestConvertXLSX_Durations(t *testing.T) {
+	tmpDir := t.TempDir()
+	inputFile := filepath.Join(tmpDir, "input.xlsx")
+
+	f := excelize.NewFile()
+	dateTime, _ := f.NewStyle(&excelize.Style{NumFmt: 22})
+	clock, _ := f.NewStyle(&excelize.Style{NumFmt: 20})
+	f.SetSheetRow("Sheet1", "A1", &[]any{"Name", "Start", "End", "Clock In", "Clock Out"})
+	f.SetSheetRow("Sheet1", "A2", &[]any{"Alice",
+		time.Date(2024, 1, 2, 8, 0, 0, 0, time.UTC), time.Date(2024, 1, 2, 16, 30, 0, 0, time.UTC),
+		0.375, 0.71875})
+	f.SetSheetRow("Sheet1", "A3", &[]any{"Bob", nil, nil, 0.875, 0.25})
+	f.SetCellStyle("Sheet1", "B2", "C3", dateTime)
+	f.SetCellStyle("Sheet1", "D2", "E3", clock)
+	if err := f.SaveAs(inputFile); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	data, err := ReadFileData(inputFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pairs := DetectDurations(data)
+	if len(pairs) != 2 || pairs[0].Header != "Start Duration" || pairs[1].Header != "Clock In Duration" {
+		t.Fatalf("Unexpected pairs: %v", pairs)
+	}
+	previewed := WithDurations(data, pairs)
+
+	for _, lowMemory := range []bool{false, true} {
+		outputFile := filepath.Join(tmpDir, "output.xlsx")
+		opts := types.ConversionOptions{Durations: pairs, LowMemory: lowMemory}
+		if _, err := ConvertXLSX(inputFile, LocalFile(outputFile), []int{5, 6}, opts, nil); err != nil {
+			t.Fatalf("ConvertXLSX failed: %v", err)
+		}
+
+		out, err := excelize.OpenFile(outputFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rows, _ := out.GetRows("Sheet1")
+		out.Close()
+
+		expected := []string{"Name", "Start", "End", "Clock In", "Clock Out", "Start Duration", "Clock In Duration"}
+		if !reflect.DeepEqual(rows[0], expected) {
+			t.Errorf("lowMemory=%v: expected headers %v, got %v", lowMemory, expected, rows[0])
+		}
+		if got := [][]string{rows[1][5:], rows[2][5:]}; !reflect.DeepEqual(got, [][]string{{"08:30", "08:15"}, {"", "09:00"}}) {
+			t.Errorf("lowMemory=%v: unexpected durations %v", lowMemory, got)
+		}
+	}
+
+	// The preview numbers the computed columns the way conversion does
+	if got := previewed.Rows[0][5:]; !reflect.DeepEqual(got, []string{"8.5", "8.25"}) {
+		t.Errorf("Unexpected previewed durations %v", got)
+	}
+}
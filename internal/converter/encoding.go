@@ -0,0 +1,204 @@
+package converter
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// Text encodings chronos can read and write for CSV files
+const (
+	EncodingUTF8        = "UTF-8"
+	EncodingUTF8BOM     = "UTF-8 BOM"
+	EncodingUTF16LE     = "UTF-16LE"
+	EncodingUTF16BE     = "UTF-16BE"
+	EncodingWindows1252 = "Windows-1252"
+)
+
+// OutputEncodings lists the encodings offered for CSV output, in display order
+var OutputEncodings = []string{EncodingUTF8, EncodingUTF8BOM, EncodingUTF16LE, EncodingWindows1252}
+
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// encodingSniffLimit is how many bytes are inspected to guess a BOM-less UTF-16 file
+const encodingSniffLimit = 1024
+
+// DetectEncoding guesses the text encoding of raw file contents.
+// BOMs are trusted first, then BOM-less UTF-16 is recognized by its zero bytes,
+// and anything that isn't valid UTF-8 is assumed to be Windows-1252.
+func DetectEncoding(data []byte) string {
+	switch {
+	case bytes.HasPrefix(data, bomUTF8):
+		return EncodingUTF8BOM
+	case bytes.HasPrefix(data, bomUTF16LE):
+		return EncodingUTF16LE
+	case bytes.HasPrefix(data, bomUTF16BE):
+		return EncodingUTF16BE
+	}
+
+	sample := data
+	if len(sample) > encodingSniffLimit {
+		sample = sample[:encodingSniffLimit]
+	}
+
+	// ASCII text in UTF-16 has a zero byte in every other position
+	evenZeros, oddZeros := 0, 0
+	for i, b := range sample {
+		if b == 0 {
+			if i%2 == 0 {
+				evenZeros++
+			} else {
+				oddZeros++
+			}
+		}
+	}
+	half := len(sample) / 2
+	if half > 0 {
+		if oddZeros > half*3/4 {
+			return EncodingUTF16LE
+		}
+		if evenZeros > half*3/4 {
+			return EncodingUTF16BE
+		}
+	}
+
+	if utf8.Valid(data) {
+		return EncodingUTF8
+	}
+	return EncodingWindows1252
+}
+
+// decodeText converts raw file contents in the given encoding to UTF-8, dropping any BOM
+func decodeText(data []byte, enc string) ([]byte, error) {
+	switch enc {
+	case EncodingUTF8, EncodingUTF8BOM:
+		return bytes.TrimPrefix(data, bomUTF8), nil
+	case EncodingUTF16LE:
+		data = bytes.TrimPrefix(data, bomUTF16LE)
+	case EncodingUTF16BE:
+		data = bytes.TrimPrefix(data, bomUTF16BE)
+	}
+
+	codec, err := textEncoding(enc)
+	if err != nil {
+		return nil, err
+	}
+
+	decoded, _, err := transform.Bytes(codec.NewDecoder(), data)
+	return decoded, err
+}
+
+// decodingReader is decodeText for a stream, converting what's read from r
+// in the given encoding to UTF-8 as it goes.
+func decodingReader(r io.Reader, enc string) (io.Reader, error) {
+	codec, err := textEncoding(enc)
+	if err != nil {
+		return nil, err
+	}
+
+	buffered := bufio.NewReader(r)
+	var bom []byte
+	switch enc {
+	case EncodingUTF8, EncodingUTF8BOM:
+		bom = bomUTF8
+	case EncodingUTF16LE:
+		bom = bomUTF16LE
+	case EncodingUTF16BE:
+		bom = bomUTF16BE
+	}
+	if start, _ := buffered.Peek(len(bom)); len(bom) > 0 && bytes.Equal(start, bom) {
+		buffered.Discard(len(bom))
+	}
+
+	if codec == unicode.UTF8 {
+		return buffered, nil
+	}
+	return transform.NewReader(buffered, codec.NewDecoder()), nil
+}
+
+// readTextFile reads a file, detects its encoding, and returns its contents as UTF-8
+func readTextFile(path string) (*bytes.Reader, string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", err
+	}
+
+	enc := DetectEncoding(data)
+	decoded, err := decodeText(data, enc)
+	if err != nil {
+		return nil, "", fmt.Errorf("could not decode %s text: %w", enc, err)
+	}
+
+	return bytes.NewReader(decoded), enc, nil
+}
+
+// textEncoding returns the x/text codec for an encoding name
+func textEncoding(enc string) (encoding.Encoding, error) {
+	switch enc {
+	case EncodingUTF8, EncodingUTF8BOM:
+		return unicode.UTF8, nil
+	case EncodingUTF16LE:
+		return unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM), nil
+	case EncodingUTF16BE:
+		return unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM), nil
+	case EncodingWindows1252:
+		return charmap.Windows1252, nil
+	}
+	return nil, fmt.Errorf("unsupported encoding: %s", enc)
+}
+
+// encodedWriter transcodes UTF-8 written to it into the target encoding
+type encodedWriter struct {
+	io.Writer
+	closer io.Closer
+}
+
+func (w encodedWriter) Close() error {
+	if w.closer != nil {
+		return w.closer.Close()
+	}
+	return nil
+}
+
+// newEncodedWriter wraps w so UTF-8 text is written in enc, starting with a BOM
+// for the encodings that expect one. Close must be called to flush the output.
+func newEncodedWriter(w io.Writer, enc string) (io.WriteCloser, error) {
+	switch enc {
+	case EncodingUTF8, "":
+		return encodedWriter{Writer: w}, nil
+	case EncodingUTF8BOM:
+		if _, err := w.Write(bomUTF8); err != nil {
+			return nil, err
+		}
+		return encodedWriter{Writer: w}, nil
+	case EncodingUTF16LE:
+		if _, err := w.Write(bomUTF16LE); err != nil {
+			return nil, err
+		}
+	case EncodingUTF16BE:
+		if _, err := w.Write(bomUTF16BE); err != nil {
+			return nil, err
+		}
+	}
+
+	codec, err := textEncoding(enc)
+	if err != nil {
+		return nil, err
+	}
+
+	// Characters the target can't represent become "?" rather than failing the whole file
+	tw := transform.NewWriter(w, encoding.ReplaceUnsupported(codec.NewEncoder()))
+	return encodedWriter{Writer: tw, closer: tw}, nil
+}
@@ -0,0 +1,113 @@
+package converter
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nconklindev/chronos/internal/types"
+)
+
+// utf16LE encodes an ASCII string as little-endian UTF-16
+func utf16LE(s string) []byte {
+	var b []byte
+	for _, r := range s {
+		b = append(b, byte(r), 0)
+	}
+	return b
+}
+
+func TestDetectEncoding(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []byte
+		expected string
+	}{
+		{"Plain ASCII", []byte("Name,Hours\n"), EncodingUTF8},
+		{"UTF-8 with accents", []byte("Nom,Heures\nZoë,1.5\n"), EncodingUTF8},
+		{"UTF-8 BOM", append([]byte{0xEF, 0xBB, 0xBF}, "Name"...), EncodingUTF8BOM},
+		{"UTF-16LE BOM", append([]byte{0xFF, 0xFE}, utf16LE("Name")...), EncodingUTF16LE},
+		{"UTF-16BE BOM", []byte{0xFE, 0xFF, 0, 'N', 0, 'a'}, EncodingUTF16BE},
+		{"UTF-16LE without BOM", utf16LE("Name,Hours\n"), EncodingUTF16LE},
+		{"Windows-1252", []byte("Name\nJos\xe9,1.5\n"), EncodingWindows1252},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DetectEncoding(tt.input)
+			if got != tt.expected {
+				t.Errorf("DetectEncoding() = %s; want %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestReadFileData_UTF16(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "input.csv")
+	data := append([]byte{0xFF, 0xFE}, utf16LE("Name,Hours\r\nAlice,1.5\r\n")...)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	fileData, err := ReadFileData(path)
+	if err != nil {
+		t.Fatalf("ReadFileData failed: %v", err)
+	}
+	if fileData.Headers[0] != "Name" || fileData.Rows[0][1] != "1.5" {
+		t.Errorf("Unexpected data: %v %v", fileData.Headers, fileData.Rows)
+	}
+	if fileData.Encoding != EncodingUTF16LE {
+		t.Errorf("Expected UTF-16LE, got %s", fileData.Encoding)
+	}
+}
+
+func TestReadFileData_BOM(t *testing.T) {
+	// Saved twice by tools adding a BOM, only the first is part of the encoding
+	path := filepath.Join(t.TempDir(), "input.csv")
+	data := []byte("\xef\xbb\xbf\xef\xbb\xbf時間,Čas\n7.5,8\n")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	fileData, err := ReadFileData(path)
+	if err != nil {
+		t.Fatalf("ReadFileData failed: %v", err)
+	}
+	if fileData.Headers[0] != "時間" || fileData.Headers[1] != "Čas" {
+		t.Errorf("Unexpected headers: %q", fileData.Headers)
+	}
+	if got := AutoDetectColumns(fileData); len(got) != 2 {
+		t.Errorf("Expected both columns detected, got %v", got)
+	}
+}
+
+func TestConvertCSV_Encoding(t *testing.T) {
+	tmpDir := t.TempDir()
+	inputFile := filepath.Join(tmpDir, "input.csv")
+	outputFile := filepath.Join(tmpDir, "output.csv")
+
+	// Windows-1252 input with an accented name
+	if err := os.WriteFile(inputFile, []byte("Name,Hours\nJos\xe9,1.5\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// By default the output keeps the input encoding
+	if _, err := ConvertCSV(inputFile, LocalFile(outputFile), []int{1}, types.ConversionOptions{}, nil); err != nil {
+		t.Fatalf("ConvertCSV failed: %v", err)
+	}
+	got, _ := os.ReadFile(outputFile)
+	if !bytes.Equal(got, []byte("Name,Hours\nJos\xe9,01:30\n")) {
+		t.Errorf("Expected Windows-1252 output, got %q", got)
+	}
+
+	// An explicit output encoding transcodes
+	opts := types.ConversionOptions{OutputEncoding: EncodingUTF8BOM}
+	if _, err := ConvertCSV(inputFile, LocalFile(outputFile), []int{1}, opts, nil); err != nil {
+		t.Fatalf("ConvertCSV failed: %v", err)
+	}
+	got, _ = os.ReadFile(outputFile)
+	if !bytes.Equal(got, []byte("\xef\xbb\xbfName,Hours\nJosé,01:30\n")) {
+		t.Errorf("Expected UTF-8 BOM output, got %q", got)
+	}
+}
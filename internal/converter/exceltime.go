@@ -0,0 +1,185 @@
+package converter
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/nconklindev/chronos/internal/types"
+	"github.com/xuri/excelize/v2"
+)
+
+// builtInTimeFormats are the built-in number formats that show a time of
+// day or a duration, such as h:mm or [h]:mm:ss. Formats with a date are left out.
+var builtInTimeFormats = map[int]bool{18: true, 19: true, 20: true, 21: true, 45: true, 46: true, 47: true}
+
+// formatCodes returns the lowercased codes of a custom number format,
+// without quoted text, escaped characters or colors.
+func formatCodes(code string) string {
+	var codes strings.Builder
+	quoted := false
+	for i := 0; i < len(code); i++ {
+		switch c := code[i]; {
+		case c == '"':
+			quoted = !quoted
+		case quoted:
+		case c == '\\' || c == '_' || c == '*':
+			i++
+		default:
+			codes.WriteByte(c)
+		}
+	}
+	lower := strings.ToLower(codes.String())
+	for _, color := range []string{"[red]", "[black]", "[blue]", "[green]", "[white]", "[yellow]", "[cyan]", "[magenta]"} {
+		lower = strings.ReplaceAll(lower, color, "")
+	}
+	return lower
+}
+
+// isTimeFormat reports whether a custom number format shows hours, minutes
+// or seconds without a date.
+func isTimeFormat(code string) bool {
+	codes := formatCodes(code)
+	if strings.ContainsAny(codes, "dy") {
+		return false
+	}
+	return strings.ContainsAny(codes, "hs")
+}
+
+// isDateFormat reports whether a custom number format shows a date or a
+// time rather than a plain number.
+func isDateFormat(code string) bool {
+	codes := formatCodes(code)
+	if strings.ContainsAny(codes, "dyhs") {
+		return true
+	}
+	// A month on its own, as long as there are no digits to show
+	return strings.Contains(codes, "m") && !strings.ContainsAny(codes, "0#?")
+}
+
+// builtInDateFormats are the built-in number formats that show a date or time.
+var builtInDateFormats = map[int]bool{14: true, 15: true, 16: true, 17: true, 22: true}
+
+// cellReader reads cells as the value they store when that's a number,
+// so number formats that round, group digits or switch to scientific
+// notation don't change what's converted. Dates and times keep the value
+// shown, except in time columns, which are read as their time fractions.
+type cellReader struct {
+	f     *excelize.File
+	sheet string
+	dated map[int]bool // Whether each style shows a date or time, by style ID
+}
+
+func newCellReader(f *excelize.File, sheet string) *cellReader {
+	return &cellReader{f: f, sheet: sheet, dated: make(map[int]bool)}
+}
+
+// read returns the value to convert from a cell.
+func (r *cellReader) read(cell string, timeCol bool) string {
+	shown, _ := r.f.GetCellValue(r.sheet, cell)
+	raw, _ := r.f.GetCellValue(r.sheet, cell, excelize.Options{RawCellValue: true})
+	return r.value(cell, shown, raw, timeCol)
+}
+
+// value chooses between a cell's shown and raw values.
+func (r *cellReader) value(cell, shown, raw string, timeCol bool) string {
+	if raw == shown || timeCol {
+		return raw
+	}
+	if _, err := strconv.ParseFloat(raw, 64); err != nil {
+		return shown
+	}
+
+	styleID, err := r.f.GetCellStyle(r.sheet, cell)
+	if err != nil {
+		return shown
+	}
+	dated, ok := r.dated[styleID]
+	if !ok {
+		if style, err := r.f.GetStyle(styleID); err == nil {
+			dated = builtInDateFormats[style.NumFmt] || builtInTimeFormats[style.NumFmt] ||
+				(style.CustomNumFmt != nil && isDateFormat(*style.CustomNumFmt))
+		}
+		r.dated[styleID] = dated
+	}
+	if dated {
+		return shown
+	}
+	return raw
+}
+
+// readStoredValues replaces the shown values of a sheet's rows below the
+// header with the values to convert, as cellReader picks them.
+func readStoredValues(f *excelize.File, sheet string, rows [][]string, headerRowIdx int, timeCols []int) error {
+	raw, err := f.GetRows(sheet, excelize.Options{RawCellValue: true})
+	if err != nil {
+		return err
+	}
+	isTime := make(map[int]bool, len(timeCols))
+	for _, col := range timeCols {
+		isTime[col] = true
+	}
+
+	r := newCellReader(f, sheet)
+	for i := headerRowIdx + 1; i < len(rows) && i < len(raw); i++ {
+		for col := range rows[i] {
+			if col >= len(raw[i]) || raw[i][col] == rows[i][col] {
+				continue
+			}
+			cell, _ := excelize.CoordinatesToCellName(col+1, i+1)
+			rows[i][col] = r.value(cell, rows[i][col], raw[i][col], isTime[col])
+		}
+	}
+	return nil
+}
+
+// timeColumns returns the columns of a sheet whose first value below the
+// header is a number formatted as a time, which Excel stores as a fraction
+// of a day.
+func timeColumns(f *excelize.File, sheet string, rows [][]string, headerRowIdx int) []int {
+	var cols []int
+	for col := range rows[headerRowIdx] {
+		for rowIdx := headerRowIdx + 1; rowIdx < len(rows); rowIdx++ {
+			if col >= len(rows[rowIdx]) || strings.TrimSpace(rows[rowIdx][col]) == "" {
+				continue
+			}
+			cell, _ := excelize.CoordinatesToCellName(col+1, rowIdx+1)
+			if cellType, _ := f.GetCellType(sheet, cell); cellType != excelize.CellTypeNumber && cellType != excelize.CellTypeUnset {
+				break
+			}
+			styleID, err := f.GetCellStyle(sheet, cell)
+			if err != nil {
+				break
+			}
+			style, err := f.GetStyle(styleID)
+			if err != nil {
+				break
+			}
+			if builtInTimeFormats[style.NumFmt] || (style.CustomNumFmt != nil && isTimeFormat(*style.CustomNumFmt)) {
+				cols = append(cols, col)
+			}
+			break
+		}
+	}
+	return cols
+}
+
+// WithTimeColumns returns a copy of columns where the given columns, found
+// holding Excel time fractions, are read as such unless they already count
+// minutes or seconds.
+func WithTimeColumns(columns map[int]types.ColumnSettings, timeCols []int) map[int]types.ColumnSettings {
+	if len(timeCols) == 0 {
+		return columns
+	}
+	merged := make(map[int]types.ColumnSettings, len(columns)+len(timeCols))
+	for idx, s := range columns {
+		merged[idx] = s
+	}
+	for _, idx := range timeCols {
+		s := merged[idx]
+		if s.Unit == types.UnitHours {
+			s.Unit = types.UnitExcelTime
+			merged[idx] = s
+		}
+	}
+	return merged
+}
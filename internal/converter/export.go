@@ -0,0 +1,119 @@
+package converter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nconklindev/chronos/internal/types"
+)
+
+// OutputExt returns the extension of a file's converted copy: the file's
+// own, unless the options write another format.
+func OutputExt(path string, opts types.ConversionOptions) string {
+	switch {
+	case opts.Markup == types.MarkupMarkdown:
+		return ".md"
+	case opts.Markup == types.MarkupHTML:
+		return ".html"
+	case opts.Parquet:
+		return ".parquet"
+	case opts.CSVOutput && IsJSON(path):
+		return ".csv"
+	}
+	return filepath.Ext(path)
+}
+
+// sourceRecords is a file read as rows for output in another format, such
+// as Parquet or a Markdown table, which only its values are written to.
+type sourceRecords struct {
+	path         string
+	records      [][]string // The header and the rows below it
+	size         int64
+	paddedRows   int
+	sheet        string
+	headerRowIdx int // Rows above an XLSX header, left out of records
+}
+
+// readSourceRecords reads a CSV, XLSX or JSON file's rows. XLSX files are
+// streamed as in low-memory mode, and their time columns added to opts.
+func readSourceRecords(inputFile string, opts *types.ConversionOptions, progress ProgressReporter) (sourceRecords, error) {
+	src := sourceRecords{path: inputFile}
+	switch ext := strings.ToLower(filepath.Ext(inputFile)); ext {
+	case ".csv":
+		inText, _, err := readTextFile(inputFile)
+		if err != nil {
+			return src, err
+		}
+		src.size = inText.Size()
+		if src.records, src.paddedRows, err = readCSVRecords(newProgressReader(inText, src.size, PhaseRead, progress)); err != nil {
+			return src, err
+		}
+		if len(src.records) == 0 {
+			return src, fmt.Errorf("empty CSV file")
+		}
+	case ".xlsx":
+		in, err := os.Open(inputFile)
+		if err != nil {
+			return src, err
+		}
+		defer in.Close()
+		info, err := in.Stat()
+		if err != nil {
+			return src, err
+		}
+		src.size = info.Size()
+		f, err := openLowMemory(newProgressReader(in, src.size, PhaseRead, progress))
+		if err != nil {
+			return src, err
+		}
+		defer f.Close()
+		sheet, err := readStreamedSheet(f, opts.FooterRows)
+		if err != nil {
+			return src, err
+		}
+		if src.records, err = sheet.records(); err != nil {
+			return src, err
+		}
+		src.headerRowIdx, src.sheet = sheet.headerRowIdx, sheet.name
+		opts.Columns = WithTimeColumns(opts.Columns, sheet.timeCols)
+	case ".json", ".ndjson":
+		inText, _, err := readTextFile(inputFile)
+		if err != nil {
+			return src, err
+		}
+		src.size = inText.Size()
+		table, err := readJSONTable(newProgressReader(inText, src.size, PhaseRead, progress), isNDJSON(inputFile))
+		if err != nil {
+			return src, err
+		}
+		src.records = table.records
+	default:
+		return src, fmt.Errorf("unsupported file type: %s", ext)
+	}
+	return src, nil
+}
+
+// result reports the conversion of the records written to sink. Changes
+// are numbered by sheet row, as in XLSX output.
+func (src sourceRecords) result(sink OutputSink, converted convertedRecords, opts types.ConversionOptions) *types.ConversionResult {
+	for _, changes := range [][]types.CellChange{converted.changes, converted.lossy, converted.flagged} {
+		for i := range changes {
+			changes[i].Sheet = src.sheet
+			changes[i].Row += src.headerRowIdx
+		}
+	}
+	return &types.ConversionResult{
+		InputFile:     src.path,
+		OutputFile:    sink.Location(),
+		ColumnsFound:  converted.columns,
+		RowsProcessed: converted.rowsProcessed,
+		Warnings:      converted.warnings(src.paddedRows, opts),
+		Changes:       converted.changes,
+		CellsChanged:  converted.count,
+		Hours:         converted.hours,
+		Lossy:         converted.lossy,
+		Flagged:       converted.flagged,
+	}
+}
@@ -0,0 +1,405 @@
+package converter
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/nconklindev/chronos/internal/types"
+)
+
+// maxExpressionLength bounds column expressions, which come from profiles
+// and config files as well as the user.
+const maxExpressionLength = 500
+
+// expression is a compiled column expression, giving a cell's new value
+// from its value as read.
+type expression func(v float64) float64
+
+// exprFuncs are the functions expressions can call, by name and number of
+// arguments. Expressions can't do anything else: they only see the cell's
+// value, and having no loops they always finish.
+var exprFuncs = map[string]struct {
+	args int
+	fn   func(args []float64) float64
+}{
+	"abs":   {1, func(a []float64) float64 { return math.Abs(a[0]) }},
+	"ceil":  {1, func(a []float64) float64 { return math.Ceil(a[0]) }},
+	"floor": {1, func(a []float64) float64 { return math.Floor(a[0]) }},
+	"round": {1, func(a []float64) float64 { return math.Round(a[0]) }},
+	"min":   {2, func(a []float64) float64 { return math.Min(a[0], a[1]) }},
+	"max":   {2, func(a []float64) float64 { return math.Max(a[0], a[1]) }},
+}
+
+// exprParser compiles an expression by recursive descent, from the
+// loosest binding (if/then/else) to the tightest (numbers and calls).
+// Conditions are numbers too: comparisons give 1 or 0, and anything but 0
+// is true.
+type exprParser struct {
+	src    string
+	tokens []string
+	pos    int
+}
+
+// compileExpression compiles an expression such as
+// "if v > 12 then v - 0.5 else v", where v is the cell's value.
+func compileExpression(src string) (expression, error) {
+	if len(src) > maxExpressionLength {
+		return nil, fmt.Errorf("expression is longer than %d characters", maxExpressionLength)
+	}
+	tokens, err := tokenizeExpression(src)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("expression is empty")
+	}
+	p := &exprParser{src: src, tokens: tokens}
+	e, err := p.conditional()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, p.errorf("unexpected %q", p.tokens[p.pos])
+	}
+	return e, nil
+}
+
+// tokenizeExpression splits an expression into numbers, words and operators.
+func tokenizeExpression(src string) ([]string, error) {
+	var tokens []string
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+		case c >= '0' && c <= '9' || c == '.':
+			j := i
+			for j < len(src) && (src[j] >= '0' && src[j] <= '9' || src[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, src[i:j])
+			i = j
+		case c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_':
+			j := i
+			for j < len(src) && (src[j] >= 'a' && src[j] <= 'z' || src[j] >= 'A' && src[j] <= 'Z' || src[j] == '_' || src[j] >= '0' && src[j] <= '9') {
+				j++
+			}
+			tokens = append(tokens, strings.ToLower(src[i:j]))
+			i = j
+		case strings.ContainsRune("<>=!", rune(c)) && i+1 < len(src) && src[i+1] == '=':
+			tokens = append(tokens, src[i:i+2])
+			i += 2
+		case strings.ContainsRune("+-*/%()<>,", rune(c)):
+			tokens = append(tokens, string(c))
+			i++
+		default:
+			return nil, fmt.Errorf("unexpected %q in expression %q", c, src)
+		}
+	}
+	return tokens, nil
+}
+
+func (p *exprParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("%s in expression %q", fmt.Sprintf(format, args...), p.src)
+}
+
+// peek returns the next token, or "" at the end.
+func (p *exprParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+// accept consumes the next token if it's one of want.
+func (p *exprParser) accept(want ...string) (string, bool) {
+	next := p.peek()
+	for _, w := range want {
+		if next == w {
+			p.pos++
+			return next, true
+		}
+	}
+	return "", false
+}
+
+func (p *exprParser) expect(want string) error {
+	if _, ok := p.accept(want); !ok {
+		if p.peek() == "" {
+			return p.errorf("missing %q", want)
+		}
+		return p.errorf("expected %q, found %q", want, p.peek())
+	}
+	return nil
+}
+
+func (p *exprParser) conditional() (expression, error) {
+	if _, ok := p.accept("if"); !ok {
+		return p.or()
+	}
+	cond, err := p.conditional()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect("then"); err != nil {
+		return nil, err
+	}
+	then, err := p.conditional()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect("else"); err != nil {
+		return nil, err
+	}
+	otherwise, err := p.conditional()
+	if err != nil {
+		return nil, err
+	}
+	return func(v float64) float64 {
+		if cond(v) != 0 {
+			return then(v)
+		}
+		return otherwise(v)
+	}, nil
+}
+
+func (p *exprParser) or() (expression, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("or"); !ok {
+			return left, nil
+		}
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(v float64) float64 { return truth(l(v) != 0 || right(v) != 0) }
+	}
+}
+
+func (p *exprParser) and() (expression, error) {
+	left, err := p.not()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("and"); !ok {
+			return left, nil
+		}
+		right, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(v float64) float64 { return truth(l(v) != 0 && right(v) != 0) }
+	}
+}
+
+func (p *exprParser) not() (expression, error) {
+	if _, ok := p.accept("not"); !ok {
+		return p.comparison()
+	}
+	operand, err := p.not()
+	if err != nil {
+		return nil, err
+	}
+	return func(v float64) float64 { return truth(operand(v) == 0) }, nil
+}
+
+func (p *exprParser) comparison() (expression, error) {
+	left, err := p.sum()
+	if err != nil {
+		return nil, err
+	}
+	op, ok := p.accept("<", "<=", ">", ">=", "==", "!=")
+	if !ok {
+		return left, nil
+	}
+	right, err := p.sum()
+	if err != nil {
+		return nil, err
+	}
+	compare := map[string]func(a, b float64) bool{
+		"<":  func(a, b float64) bool { return a < b },
+		"<=": func(a, b float64) bool { return a <= b },
+		">":  func(a, b float64) bool { return a > b },
+		">=": func(a, b float64) bool { return a >= b },
+		"==": func(a, b float64) bool { return a == b },
+		"!=": func(a, b float64) bool { return a != b },
+	}[op]
+	return func(v float64) float64 { return truth(compare(left(v), right(v))) }, nil
+}
+
+func (p *exprParser) sum() (expression, error) {
+	left, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept("+", "-")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		if op == "+" {
+			left = func(v float64) float64 { return l(v) + right(v) }
+		} else {
+			left = func(v float64) float64 { return l(v) - right(v) }
+		}
+	}
+}
+
+func (p *exprParser) term() (expression, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept("*", "/", "%")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		switch op {
+		case "*":
+			left = func(v float64) float64 { return l(v) * right(v) }
+		case "/":
+			left = func(v float64) float64 { return l(v) / right(v) }
+		default:
+			left = func(v float64) float64 { return math.Mod(l(v), right(v)) }
+		}
+	}
+}
+
+func (p *exprParser) unary() (expression, error) {
+	if _, ok := p.accept("-"); !ok {
+		return p.primary()
+	}
+	operand, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	return func(v float64) float64 { return -operand(v) }, nil
+}
+
+func (p *exprParser) primary() (expression, error) {
+	token := p.peek()
+	switch {
+	case token == "":
+		return nil, p.errorf("unexpected end")
+	case token == "(":
+		p.pos++
+		inner, err := p.conditional()
+		if err != nil {
+			return nil, err
+		}
+		return inner, p.expect(")")
+	case token == "v":
+		p.pos++
+		return func(v float64) float64 { return v }, nil
+	case token[0] >= '0' && token[0] <= '9' || token[0] == '.':
+		n, err := strconv.ParseFloat(token, 64)
+		if err != nil {
+			return nil, p.errorf("bad number %q", token)
+		}
+		p.pos++
+		return func(float64) float64 { return n }, nil
+	}
+
+	fn, ok := exprFuncs[token]
+	if !ok {
+		return nil, p.errorf("unknown name %q (use v for the value)", token)
+	}
+	p.pos++
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	var args []expression
+	for {
+		arg, err := p.conditional()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+		if _, ok := p.accept(","); !ok {
+			break
+		}
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	if len(args) != fn.args {
+		return nil, p.errorf("%s takes %d argument(s), not %d", token, fn.args, len(args))
+	}
+	return func(v float64) float64 {
+		values := make([]float64, len(args))
+		for i, arg := range args {
+			values[i] = arg(v)
+		}
+		return fn.fn(values)
+	}, nil
+}
+
+// truth is 1 for true and 0 for false.
+func truth(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// expressions caches compiled expressions by source, since each is
+// applied to every cell of its column.
+var expressions sync.Map
+
+// CheckExpression reports whether src is a valid column expression.
+func CheckExpression(src string) error {
+	_, err := lookupExpression(src)
+	return err
+}
+
+func lookupExpression(src string) (expression, error) {
+	if e, ok := expressions.Load(src); ok {
+		return e.(expression), nil
+	}
+	e, err := compileExpression(src)
+	if err != nil {
+		return nil, err
+	}
+	expressions.Store(src, e)
+	return e, nil
+}
+
+// CellValue reads the number in a duration cell, with the column's
+// expression applied. Results that aren't finite numbers, as from dividing
+// by zero, are errors and leave the cell unconverted.
+func CellValue(cell string, s types.ColumnSettings) (float64, error) {
+	value, err := parseCell(cell, s)
+	if err != nil || s.Expression == "" {
+		return value, err
+	}
+	e, err := lookupExpression(s.Expression)
+	if err != nil {
+		return 0, err
+	}
+	value = e(value)
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, fmt.Errorf("expression %q gives %v for %s", s.Expression, value, strings.TrimSpace(cell))
+	}
+	return value, nil
+}
@@ -0,0 +1,86 @@
+package converter
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/nconklindev/chronos/internal/types"
+)
+
+func TestCompileExpression(t *testing.T) {
+	tests := []struct {
+		expr     string
+		v        float64
+		expected float64
+	}{
+		{"v", 7.5, 7.5},
+		{"if v > 12 then v - 0.5 else v", 13, 12.5},
+		{"if v > 12 then v - 0.5 else v", 8, 8},
+		{"v * 1.5 + 2 * 3", 2, 9},
+		{"(v + 1) * 2", 2, 6},
+		{"-v", 2, -2},
+		{"v % 1", 7.25, 0.25},
+		{"round(v * 4) / 4", 7.4, 7.5},
+		{"min(v, 8) + max(v - 8, 0) * 1.5", 10, 11},
+		{"if v >= 6 and not (v == 8) then 1 else 0", 8, 0},
+		{"if v < 1 or v != v then 1 else 0", 0.5, 1},
+		{"IF V > 12 THEN 12 ELSE V", 14, 12},
+		{"if v > 16 then 16 else if v > 12 then v - 0.5 else v", 14, 13.5},
+	}
+	for _, tt := range tests {
+		e, err := compileExpression(tt.expr)
+		if err != nil {
+			t.Errorf("compileExpression(%q) failed: %v", tt.expr, err)
+			continue
+		}
+		if got := e(tt.v); got != tt.expected {
+			t.Errorf("%q with v = %v: expected %v, got %v", tt.expr, tt.v, tt.expected, got)
+		}
+	}
+
+	for _, bad := range []string{"", "v +", "x * 2", "if v then 1", "v = 2", "min(v)", "(v", "v v", "os.exit(1)"} {
+		if err := CheckExpression(bad); err == nil {
+			t.Errorf("Expected %q to be rejected", bad)
+		}
+	}
+}
+
+func TestConvertCSV_Expression(t *testing.T) {
+	tmpDir := t.TempDir()
+	inputFile := filepath.Join(tmpDir, "input.csv")
+	outputFile := filepath.Join(tmpDir, "output.csv")
+	writeTestCSV(t, inputFile, [][]string{
+		{"Name", "Hours", "Minutes"},
+		{"Alice", "13", "600"},
+		{"Bob", "7.5", "450"},
+		{"Carol", "0", "0"},
+	})
+
+	opts := types.ConversionOptions{
+		Columns: map[int]types.ColumnSettings{
+			1: {Expression: "if v > 12 then v - 0.5 else v", OvertimeAfter: 8},
+			// The expression sees the value in the column's unit
+			2: {Unit: types.UnitMinutes, Expression: "60 / v * v"},
+		},
+	}
+	result, err := ConvertCSV(inputFile, LocalFile(outputFile), []int{1, 2}, opts, nil)
+	if err != nil {
+		t.Fatalf("ConvertCSV failed: %v", err)
+	}
+
+	expected := [][]string{
+		{"Name", "Regular (HH:MM)", "Overtime (HH:MM)", "Minutes"},
+		{"Alice", "08:00", "04:30", "01:00"},
+		{"Bob", "07:30", "00:00", "01:00"},
+		{"Carol", "00:00", "00:00", "0"},
+	}
+	if records := readTestCSV(t, outputFile); !reflect.DeepEqual(records, expected) {
+		t.Errorf("Expected %v, got %v", expected, records)
+	}
+	// Dividing by zero leaves the cell unconverted, and the expression
+	// isn't counted as a rounding loss
+	if len(result.Warnings) != 1 || len(result.Lossy) > 0 {
+		t.Errorf("Expected one unconverted cell and no losses, got %v and %+v", result.Warnings, result.Lossy)
+	}
+}
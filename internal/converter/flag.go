@@ -0,0 +1,77 @@
+package converter
+
+import (
+	"strconv"
+
+	"github.com/nconklindev/chronos/internal/types"
+	"github.com/xuri/excelize/v2"
+)
+
+// flagFill is the light red fill of flagged XLSX cells
+var flagFill = excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"FFC7CE"}}
+
+// exceedsFlag reports whether a cell holds more hours than the flag
+// threshold. Empty and non-numeric cells never do.
+func exceedsFlag(cell string, s types.ColumnSettings, opts types.ConversionOptions) bool {
+	if opts.FlagAbove <= 0 || !IsDuration(s) {
+		return false
+	}
+	hours, err := CellValue(cell, s)
+	if err != nil {
+		return false
+	}
+	if factor, ok := unitHours[s.Unit]; ok {
+		hours *= factor
+	}
+	return hours > opts.FlagAbove
+}
+
+// flaggedChanges returns the changes whose originals exceed the flag
+// threshold. The changes' columns must match the settings' numbering in opts.
+func flaggedChanges(changes []types.CellChange, opts types.ConversionOptions) []types.CellChange {
+	var flagged []types.CellChange
+	for _, change := range changes {
+		if exceedsFlag(change.Original, opts.Columns[change.Col], opts) {
+			flagged = append(flagged, change)
+		}
+	}
+	return flagged
+}
+
+// FlagHeader is the header of the column listing the flagged columns of
+// each row in CSV output.
+func FlagHeader(opts types.ConversionOptions) string {
+	return "Over " + strconv.FormatFloat(opts.FlagAbove, 'f', -1, 64) + " hours"
+}
+
+// flagger fills flagged XLSX cells, keeping the rest of their style.
+type flagger struct {
+	f      *excelize.File
+	sheet  string
+	styles map[int]int // Flagged style IDs by the style they're based on
+}
+
+func newFlagger(f *excelize.File, sheet string) *flagger {
+	return &flagger{f: f, sheet: sheet, styles: make(map[int]int)}
+}
+
+// flag fills a cell.
+func (fl *flagger) flag(cell string) error {
+	base, err := fl.f.GetCellStyle(fl.sheet, cell)
+	if err != nil {
+		return err
+	}
+	id, ok := fl.styles[base]
+	if !ok {
+		style, err := fl.f.GetStyle(base)
+		if err != nil {
+			return err
+		}
+		style.Fill = flagFill
+		if id, err = fl.f.NewStyle(style); err != nil {
+			return err
+		}
+		fl.styles[base] = id
+	}
+	return fl.f.SetCellStyle(fl.sheet, cell, cell, id)
+}
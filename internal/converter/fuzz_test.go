@@ -0,0 +1,151 @@
+package converter
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/quick"
+
+	"github.com/nconklindev/chronos/internal/types"
+)
+
+// hhmmPattern is what DecimalToTime writes: at least two digits of hours
+// and two of minutes.
+var hhmmPattern = regexp.MustCompile(`^(\d{2,}):([0-5]\d)$`)
+
+// parseHHMM reads a time written by DecimalToTime back into minutes.
+func parseHHMM(t *testing.T, s string) int64 {
+	t.Helper()
+	m := hhmmPattern.FindStringSubmatch(s)
+	if m == nil {
+		t.Fatalf("%q isn't hh:mm", s)
+	}
+	hours, err := strconv.ParseInt(m[1], 10, 64)
+	if err != nil {
+		t.Fatalf("%q has hours out of range: %v", s, err)
+	}
+	minutes, _ := strconv.ParseInt(m[2], 10, 64)
+	return hours*60 + minutes
+}
+
+// checkRoundTrip checks that converting decimal hours and reading the time
+// back lands within 30 seconds of where it started.
+func checkRoundTrip(t *testing.T, decimal float64) {
+	t.Helper()
+	minutes := parseHHMM(t, DecimalToTime(decimal))
+	if diff := math.Abs(float64(minutes) - decimal*60); diff > 0.5+1e-6 {
+		t.Errorf("DecimalToTime(%v) = %s, %.4f minutes off", decimal, DecimalToTime(decimal), diff)
+	}
+}
+
+// hoursOf maps any float64 to decimal hours detection would accept, for
+// property tests over quick's values.
+func hoursOf(x float64) float64 {
+	if math.IsNaN(x) || math.IsInf(x, 0) {
+		return 0
+	}
+	return math.Mod(math.Abs(x), 10000)
+}
+
+func TestDecimalToTime_RoundTrip(t *testing.T) {
+	for _, decimal := range []float64{0, 0.0001, 0.5 / 60, 0.9999, 0.99999999, 1, 7.5, 7.999, 23.9917, 100.0083, 9999.9999} {
+		checkRoundTrip(t, decimal)
+	}
+
+	err := quick.Check(func(x float64) bool {
+		checkRoundTrip(t, hoursOf(x))
+		return !t.Failed()
+	}, &quick.Config{MaxCount: 10000})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDecimalToTime_Monotonic(t *testing.T) {
+	err := quick.Check(func(x, y float64) bool {
+		a, b := hoursOf(x), hoursOf(y)
+		if a > b {
+			a, b = b, a
+		}
+		if parseHHMM(t, DecimalToTime(a)) > parseHHMM(t, DecimalToTime(b)) {
+			t.Errorf("DecimalToTime(%v) = %s is after DecimalToTime(%v) = %s", a, DecimalToTime(a), b, DecimalToTime(b))
+		}
+		return !t.Failed()
+	}, &quick.Config{MaxCount: 10000})
+	if err != nil {
+		t.Error(err)
+	}
+
+	// Just below a whole hour rounds up to it rather than to 60 minutes
+	for _, decimal := range []float64{0.9999, 1.99999, 41.9999999} {
+		if got, next := DecimalToTime(decimal), DecimalToTime(math.Ceil(decimal)); got != next {
+			t.Errorf("DecimalToTime(%v) = %s, expected %s", decimal, got, next)
+		}
+	}
+}
+
+func TestDecimalToTime_OutOfRange(t *testing.T) {
+	tests := []struct {
+		decimal  float64
+		expected string
+	}{
+		{-7.5, "00:00"},
+		{math.NaN(), "00:00"},
+		{math.Inf(-1), "00:00"},
+		{math.Inf(1), DecimalToTime(maxHours)},
+		{1e300, DecimalToTime(maxHours)},
+		{maxHours, "1000000000000000:00"},
+	}
+	for _, tt := range tests {
+		if got := DecimalToTime(tt.decimal); got != tt.expected {
+			t.Errorf("DecimalToTime(%v) = %s, expected %s", tt.decimal, got, tt.expected)
+		}
+	}
+}
+
+func FuzzDecimalToTime(f *testing.F) {
+	for _, seed := range []float64{0, 0.9999, 0.5 / 60, 7.5, 9999.99, 1e-300, 1e15, 1e300, -1, math.NaN(), math.Inf(1)} {
+		f.Add(seed, 1.0)
+	}
+	f.Fuzz(func(t *testing.T, x, y float64) {
+		// Every value gives a time, however far out of range
+		a, b := parseHHMM(t, DecimalToTime(x)), parseHHMM(t, DecimalToTime(y))
+
+		if x >= 0 && x < 1e9 {
+			checkRoundTrip(t, x)
+		}
+		if x <= y && a > b {
+			t.Errorf("DecimalToTime(%v) = %s is after DecimalToTime(%v) = %s", x, DecimalToTime(x), y, DecimalToTime(y))
+		}
+	})
+}
+
+func FuzzIsDecimalHour(f *testing.F) {
+	for _, seed := range []string{"7.5", " 7.5 ", "0.9999", "9999.9999", "10000", "1e3", "7.5E-1", "1e400", "1,234.5", "1.234,5", "1,5", "1.5E+00", "-0", "+7.5", "-1", "NaN", "inf", "0x1p3", "1_0", "7,5", ""} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		ok, reason := checkDecimalHour(s)
+		if ok != IsDecimalHour(s) || ok != (reason == "") {
+			t.Fatalf("checkDecimalHour(%q) = %v, %q disagrees with IsDecimalHour", s, ok, reason)
+		}
+		if !ok {
+			return
+		}
+
+		// Accepted values are decimal numbers of hours that convert
+		if strings.ContainsAny(strings.ToLower(s), "xpnia_") {
+			t.Errorf("IsDecimalHour(%q) accepted a number that isn't written in decimal", s)
+		}
+		decimal, err := ParseNumber(s, types.DecimalAuto)
+		if err != nil || decimal < 0 || decimal >= 10000 {
+			t.Fatalf("IsDecimalHour(%q) accepted %v, %v", s, decimal, err)
+		}
+		checkRoundTrip(t, decimal)
+		if _, err := CellValue(s, types.ColumnSettings{}); err != nil {
+			t.Errorf("IsDecimalHour(%q) accepted a value conversion rejects: %v", s, err)
+		}
+	})
+}
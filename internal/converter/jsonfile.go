@@ -0,0 +1,376 @@
+package converter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/nconklindev/chronos/internal/types"
+)
+
+// IsJSON reports whether a file is a JSON array of records or NDJSON, a
+// record per line.
+func IsJSON(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json", ".ndjson":
+		return true
+	}
+	return false
+}
+
+// isNDJSON reports whether a JSON file holds a record per line rather than
+// an array.
+func isNDJSON(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".ndjson")
+}
+
+// jsonKind is the type of a column's JSON values, so they can be written
+// back as they were read.
+type jsonKind int
+
+const (
+	jsonText   jsonKind = iota // Strings, or values of mixed types
+	jsonNumber                 // Numbers, written as read
+	jsonBool
+	jsonRaw // Arrays, kept as JSON
+)
+
+// jsonTable is a file of JSON records flattened into rows, the first of
+// them the header, with the type of each column's values.
+type jsonTable struct {
+	records [][]string
+	kinds   map[string]jsonKind
+}
+
+// readJSONTable reads an array of JSON objects, or with ndjson a stream of
+// them, as rows. Each key is a column, in the order keys first appear;
+// nested objects are flattened into dotted keys such as "employee.id", and
+// arrays kept as JSON text. Nulls and missing keys are empty cells.
+func readJSONTable(r io.Reader, ndjson bool) (jsonTable, error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	if !ndjson {
+		if tok, err := dec.Token(); err != nil || tok != json.Delim('[') {
+			return jsonTable{}, fmt.Errorf("expected a JSON array of records")
+		}
+	}
+
+	var header []string
+	columns := make(map[string]int)
+	kinds := make(map[string]jsonKind)
+	seen := make(map[string]bool) // Columns with a value whose kind is recorded
+	var rows [][]string
+	for n := 1; dec.More(); n++ {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return jsonTable{}, fmt.Errorf("record %d: %w", n, err)
+		}
+
+		cells := make(map[string]string)
+		var keys []string
+		var add func(prefix string, raw json.RawMessage) error
+		add = func(prefix string, raw json.RawMessage) error {
+			fields := json.NewDecoder(bytes.NewReader(raw))
+			fields.UseNumber()
+			if tok, err := fields.Token(); err != nil || tok != json.Delim('{') {
+				return fmt.Errorf("record %d isn't an object", n)
+			}
+			for fields.More() {
+				tok, err := fields.Token()
+				if err != nil {
+					return fmt.Errorf("record %d: %w", n, err)
+				}
+				var value json.RawMessage
+				if err := fields.Decode(&value); err != nil {
+					return fmt.Errorf("record %d: %w", n, err)
+				}
+				key := prefix + tok.(string)
+				if value[0] == '{' {
+					if err := add(key+".", value); err != nil {
+						return err
+					}
+					continue
+				}
+
+				cell, kind, null := jsonCell(value)
+				if _, ok := cells[key]; !ok {
+					keys = append(keys, key)
+				}
+				cells[key] = cell
+				if null {
+					continue
+				}
+				if !seen[key] {
+					kinds[key], seen[key] = kind, true
+				} else if kinds[key] != kind {
+					kinds[key] = jsonText
+				}
+			}
+			return nil
+		}
+		if err := add("", raw); err != nil {
+			return jsonTable{}, err
+		}
+
+		for _, key := range keys {
+			if _, ok := columns[key]; !ok {
+				columns[key] = len(header)
+				header = append(header, key)
+			}
+		}
+		row := make([]string, len(header))
+		for key, cell := range cells {
+			row[columns[key]] = cell
+		}
+		rows = append(rows, row)
+	}
+	if len(header) == 0 {
+		return jsonTable{}, fmt.Errorf("no records found")
+	}
+
+	// Records without the later keys are padded to the header's width
+	records := [][]string{header}
+	for _, row := range rows {
+		records = append(records, append(row, make([]string, len(header)-len(row))...))
+	}
+	return jsonTable{records: records, kinds: kinds}, nil
+}
+
+// jsonCell reads a JSON value as a cell, reporting its kind and whether
+// it's null.
+func jsonCell(value json.RawMessage) (string, jsonKind, bool) {
+	switch value[0] {
+	case 'n':
+		return "", jsonText, true
+	case 't', 'f':
+		return string(value), jsonBool, false
+	case '"':
+		var s string
+		json.Unmarshal(value, &s)
+		return s, jsonText, false
+	case '[':
+		var compact bytes.Buffer
+		json.Compact(&compact, value)
+		return compact.String(), jsonRaw, false
+	}
+	return string(value), jsonNumber, false
+}
+
+func readJSONData(filePath string) (*types.FileData, error) {
+	text, enc, err := readTextFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	table, err := readJSONTable(text, isNDJSON(filePath))
+	if err != nil {
+		return nil, err
+	}
+
+	records := table.records
+	return &types.FileData{
+		Headers:    records[0],
+		Rows:       records[1:],
+		FooterRows: DetectFooterRows(records[1:]),
+		Encoding:   enc,
+	}, nil
+}
+
+// ConvertJSON converts a JSON or NDJSON file of records as ConvertCSV
+// converts a CSV file, writing the output in the input's format, or as CSV
+// with opts.CSVOutput. Converted cells are written as strings, and the
+// rest as the types they were read as.
+func ConvertJSON(inputFile string, sink OutputSink, columnIndices []int, opts types.ConversionOptions, progress ProgressReporter) (*types.ConversionResult, error) {
+	inText, inputEncoding, err := readTextFile(inputFile)
+	if err != nil {
+		return nil, err
+	}
+	inSize := inText.Size()
+	ndjson := isNDJSON(inputFile)
+	table, err := readJSONTable(newProgressReader(inText, inSize, PhaseRead, progress), ndjson)
+	if err != nil {
+		return nil, err
+	}
+
+	converted := convertRecords(table.records, columnIndices, opts, progress)
+
+	outFile, err := sink.Create()
+	if err != nil {
+		return nil, err
+	}
+	defer outFile.Close()
+	sum := newChecksumWriter(outFile)
+	written := newProgressWriter(sum, inSize, PhaseWrite, progress)
+
+	var totalsFile string
+	var totalsWarning []string
+	if opts.CSVOutput {
+		outputEncoding := opts.OutputEncoding
+		if outputEncoding == "" {
+			outputEncoding = inputEncoding
+		}
+		style := outputLineStyle(lineStyle{finalNewline: true}, opts)
+		q := quoting{all: opts.Quoting == types.QuotingAll}
+		out, err := newEncodedWriter(written, outputEncoding)
+		if err != nil {
+			return nil, err
+		}
+		if err := writeCSV(out, converted.records, style, q); err != nil {
+			return nil, err
+		}
+		if err := out.Close(); err != nil {
+			return nil, err
+		}
+		if err := outFile.Close(); err != nil {
+			return nil, err
+		}
+		if path, ok := diskPath(sink); ok {
+			if err := checkCSVOutput(path, sum, converted.records, outputEncoding); err != nil {
+				return nil, err
+			}
+		}
+		if converted.totals != nil {
+			if totalsFile, totalsWarning, err = writeCSVTotals(sink, converted.totals, outputEncoding, style, q); err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		kinds := make([]jsonKind, len(converted.records[0]))
+		for col, name := range converted.records[0] {
+			if col >= len(converted.roles) || !converted.roles[col].converted {
+				kinds[col] = table.kinds[name]
+			}
+		}
+		if err := writeJSONRecords(written, converted.records, kinds, ndjson); err != nil {
+			return nil, err
+		}
+		if err := outFile.Close(); err != nil {
+			return nil, err
+		}
+		if path, ok := diskPath(sink); ok {
+			if err := readBack(path, sum); err != nil {
+				return nil, err
+			}
+		}
+		if converted.totals != nil {
+			if totalsFile, totalsWarning, err = writeJSONTotals(sink, converted.totals, ndjson); err != nil {
+				return nil, err
+			}
+		}
+	}
+	written.finish()
+
+	return &types.ConversionResult{
+		InputFile:     inputFile,
+		OutputFile:    sink.Location(),
+		ColumnsFound:  converted.columns,
+		RowsProcessed: converted.rowsProcessed,
+		Warnings:      append(converted.warnings(0, opts), totalsWarning...),
+		Changes:       converted.changes,
+		CellsChanged:  converted.count,
+		Hours:         converted.hours,
+		Lossy:         converted.lossy,
+		Flagged:       converted.flagged,
+		TotalsFile:    totalsFile,
+	}, nil
+}
+
+// writeJSONRecords writes records, the first of them the header, as an
+// indented array of objects, or with ndjson an object per line. Cells are
+// written as strings unless their column's kind says otherwise, and empty
+// cells of numbers, booleans and arrays as nulls.
+func writeJSONRecords(w io.Writer, records [][]string, kinds []jsonKind, ndjson bool) error {
+	var out bytes.Buffer
+	if !ndjson {
+		out.WriteString("[")
+	}
+	header := records[0]
+	for i, record := range records[1:] {
+		var line bytes.Buffer
+		line.WriteString("{")
+		for col, name := range header {
+			if col > 0 {
+				line.WriteString(",")
+			}
+			key, _ := json.Marshal(name)
+			line.Write(key)
+			line.WriteString(":")
+			cell := ""
+			if col < len(record) {
+				cell = record[col]
+			}
+			kind := jsonText
+			if col < len(kinds) {
+				kind = kinds[col]
+			}
+			line.Write(jsonValue(cell, kind))
+		}
+		line.WriteString("}")
+
+		if ndjson {
+			out.Write(line.Bytes())
+			out.WriteString("\n")
+			continue
+		}
+		if i > 0 {
+			out.WriteString(",")
+		}
+		out.WriteString("\n  ")
+		json.Indent(&out, line.Bytes(), "  ", "  ")
+	}
+	if !ndjson {
+		out.WriteString("\n]\n")
+	}
+	_, err := w.Write(out.Bytes())
+	return err
+}
+
+// jsonValue encodes a cell as a value of the given kind, falling back to a
+// string for cells that aren't one, such as a footer's label.
+func jsonValue(cell string, kind jsonKind) []byte {
+	if kind != jsonText {
+		if cell == "" {
+			return []byte("null")
+		}
+		var v any
+		if json.Unmarshal([]byte(cell), &v) == nil {
+			switch v.(type) {
+			case float64:
+				if kind == jsonNumber {
+					return []byte(cell)
+				}
+			case bool:
+				if kind == jsonBool {
+					return []byte(cell)
+				}
+			case []any:
+				if kind == jsonRaw {
+					return []byte(cell)
+				}
+			}
+		}
+	}
+	s, _ := json.Marshal(cell)
+	return s
+}
+
+// writeJSONTotals writes totals next to the JSON output written to sink, as
+// writeCSVTotals does for CSV output.
+func writeJSONTotals(sink OutputSink, totals [][]string, ndjson bool) (string, []string, error) {
+	to, ok := totalsSink(sink)
+	if !ok {
+		return "", []string{fmt.Sprintf("totals are only written next to files on disk, not to %s", sink.Location())}, nil
+	}
+
+	file, err := to.Create()
+	if err != nil {
+		return "", nil, err
+	}
+	defer file.Close()
+	if err := writeJSONRecords(file, totals, nil, ndjson); err != nil {
+		return "", nil, err
+	}
+	return to.Location(), nil, file.Close()
+}
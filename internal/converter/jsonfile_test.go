@@ -0,0 +1,112 @@
+package converter
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nconklindev/chronos/internal/types"
+)
+
+func TestReadJSONData(t *testing.T) {
+	tmpDir := t.TempDir()
+	array := `[
+  {"id": "E12", "employee": {"name": "Alice", "dept": "Ops"}, "hours": 7.5, "approved": true},
+  {"id": "E13", "employee": {"name": "Bob"}, "hours": null, "approved": false, "tags": ["night", "weekend"]}
+]`
+	ndjson := `{"id": "E12", "employee": {"name": "Alice", "dept": "Ops"}, "hours": 7.5, "approved": true}
+{"id": "E13", "employee": {"name": "Bob"}, "hours": null, "approved": false, "tags": ["night", "weekend"]}
+`
+	wantHeaders := []string{"id", "employee.name", "employee.dept", "hours", "approved", "tags"}
+	wantRows := [][]string{
+		{"E12", "Alice", "Ops", "7.5", "true", ""},
+		{"E13", "Bob", "", "", "false", `["night","weekend"]`},
+	}
+
+	for name, text := range map[string]string{"hours.json": array, "hours.ndjson": ndjson} {
+		path := filepath.Join(tmpDir, name)
+		if err := os.WriteFile(path, []byte(text), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		data, err := ReadFileData(path)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !reflect.DeepEqual(data.Headers, wantHeaders) || !reflect.DeepEqual(data.Rows, wantRows) {
+			t.Errorf("%s: expected %v %v, got %v %v", name, wantHeaders, wantRows, data.Headers, data.Rows)
+		}
+		if got := AutoDetectColumns(data); !reflect.DeepEqual(got, []int{3}) {
+			t.Errorf("%s: expected the hours column detected, got %v", name, got)
+		}
+	}
+
+	for _, bad := range []string{`{"hours": 7.5}`, `[7.5]`, `[]`} {
+		path := filepath.Join(tmpDir, "bad.json")
+		os.WriteFile(path, []byte(bad), 0o644)
+		if _, err := ReadFileData(path); err == nil {
+			t.Errorf("Expected %s to be refused", bad)
+		}
+	}
+}
+
+func TestConvertJSON(t *testing.T) {
+	tmpDir := t.TempDir()
+	inputFile := filepath.Join(tmpDir, "input.json")
+	input := `[{"id": "0012", "hours": 7.5, "approved": true}, {"id": "0013", "hours": 1.25, "approved": null}]`
+	if err := os.WriteFile(inputFile, []byte(input), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Converted cells are strings, and the rest keep their types
+	outputFile := filepath.Join(tmpDir, "output.json")
+	result, err := Convert(inputFile, LocalFile(outputFile), []int{1}, types.ConversionOptions{KeepOriginal: true}, nil)
+	if err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+	if result.RowsProcessed != 2 || result.CellsChanged != 2 {
+		t.Errorf("Unexpected result %+v", result)
+	}
+	data, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []map[string]any
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Expected a JSON array, got %s: %v", data, err)
+	}
+	want := []map[string]any{
+		{"id": "0012", "hours": 7.5, "hours (HH:MM)": "07:30", "approved": true},
+		{"id": "0013", "hours": 1.25, "hours (HH:MM)": "01:15", "approved": nil},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if !strings.Contains(string(data), `"id": "0012",`+"\n"+`    "hours": 7.5,`) {
+		t.Errorf("Expected keys in column order, got:\n%s", data)
+	}
+
+	// NDJSON is written a record per line
+	ndjsonFile := filepath.Join(tmpDir, "input.ndjson")
+	os.WriteFile(ndjsonFile, []byte("{\"hours\": 8}\n{\"hours\": 0.5}\n"), 0o644)
+	outputFile = filepath.Join(tmpDir, "output.ndjson")
+	if _, err := Convert(ndjsonFile, LocalFile(outputFile), []int{0}, types.ConversionOptions{}, nil); err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+	data, _ = os.ReadFile(outputFile)
+	if want := "{\"hours\":\"08:00\"}\n{\"hours\":\"00:30\"}\n"; string(data) != want {
+		t.Errorf("Expected %q, got %q", want, data)
+	}
+
+	// Or as CSV
+	outputFile = filepath.Join(tmpDir, "output.csv")
+	if _, err := Convert(inputFile, LocalFile(outputFile), []int{1}, types.ConversionOptions{CSVOutput: true}, nil); err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+	data, _ = os.ReadFile(outputFile)
+	if want := "id,hours,approved\n0012,07:30,true\n0013,01:15,\n"; string(data) != want {
+		t.Errorf("Expected %q, got %q", want, data)
+	}
+}
@@ -0,0 +1,143 @@
+package converter
+
+import (
+	"sort"
+
+	"github.com/nconklindev/chronos/internal/types"
+	"github.com/xuri/excelize/v2"
+)
+
+// OutputOrder returns the first width columns in the order they're
+// written: the columns listed in order first, then the rest in file order.
+// Listed columns that are out of range or repeated are skipped.
+func OutputOrder(width int, order []int) []int {
+	placed := make(map[int]bool, width)
+	columns := make([]int, 0, width)
+	for _, idx := range order {
+		if idx >= 0 && idx < width && !placed[idx] {
+			placed[idx] = true
+			columns = append(columns, idx)
+		}
+	}
+	for idx := 0; idx < width; idx++ {
+		if !placed[idx] {
+			columns = append(columns, idx)
+		}
+	}
+	return columns
+}
+
+// rearranged reports whether opts change which columns are written or their order.
+func rearranged(opts types.ConversionOptions) bool {
+	return opts.OnlySelected || len(opts.Order) > 0
+}
+
+// outputColumns returns the columns written, in output order. With
+// opts.OnlySelected that's the converted columns and opts.KeepColumns.
+func outputColumns(width int, columnIndices []int, opts types.ConversionOptions) []int {
+	columns := OutputOrder(width, opts.Order)
+	if !opts.OnlySelected {
+		return columns
+	}
+
+	keep := make(map[int]bool)
+	for _, idx := range columnIndices {
+		keep[idx] = true
+	}
+	for _, idx := range opts.KeepColumns {
+		keep[idx] = true
+	}
+	var kept []int
+	for _, idx := range columns {
+		if keep[idx] {
+			kept = append(kept, idx)
+		}
+	}
+	return kept
+}
+
+// projectRow returns the cells of row in the kept columns, padding cells
+// the row is too short for.
+func projectRow(row []string, kept []int) []string {
+	projected := make([]string, len(kept))
+	for i, idx := range kept {
+		if idx < len(row) {
+			projected[i] = row[idx]
+		}
+	}
+	return projected
+}
+
+// renumberOptions renumbers the columns to convert and their settings to
+// their positions among the kept columns. Filters keep the
+// input's numbering, since rows are matched before columns are moved.
+func renumberOptions(kept []int, columnIndices []int, opts types.ConversionOptions) ([]int, types.ConversionOptions) {
+	position := make(map[int]int, len(kept))
+	for i, idx := range kept {
+		position[idx] = i
+	}
+
+	var indices []int
+	for _, idx := range columnIndices {
+		if pos, ok := position[idx]; ok {
+			indices = append(indices, pos)
+		}
+	}
+	if opts.Columns != nil {
+		columns := make(map[int]types.ColumnSettings, len(opts.Columns))
+		for idx, settings := range opts.Columns {
+			if pos, ok := position[idx]; ok {
+				columns[pos] = settings
+			}
+		}
+		opts.Columns = columns
+	}
+	return indices, opts
+}
+
+// restoreChangeColumns renumbers changes from positions among the kept
+// columns back to the input's columns.
+func restoreChangeColumns(changes []types.CellChange, kept []int) {
+	for i := range changes {
+		changes[i].Col = kept[changes[i].Col]
+	}
+}
+
+// arrangeColumns rearranges the first width columns of sheet into the kept
+// columns, in order, using its first rows rows.
+func arrangeColumns(f *excelize.File, sheet string, width, rows int, kept []int) error {
+	keep := make(map[int]bool, len(kept))
+	for _, idx := range kept {
+		keep[idx] = true
+	}
+
+	// Columns only being dropped are removed in place, which keeps
+	// everything about the rest intact
+	if sort.IntsAreSorted(kept) {
+		return removeColumns(f, sheet, width, func(idx int) bool { return !keep[idx] })
+	}
+
+	// Otherwise copy them past the last column in their new order, and
+	// remove the originals
+	for i, idx := range kept {
+		if err := copyColumn(f, sheet, idx, width+i, rows); err != nil {
+			return err
+		}
+	}
+	return removeColumns(f, sheet, width, func(int) bool { return true })
+}
+
+// removeColumns deletes the columns among the first width of sheet that remove picks.
+func removeColumns(f *excelize.File, sheet string, width int, remove func(idx int) bool) error {
+	// Remove from the right so the columns to the left stay put
+	for idx := width - 1; idx >= 0; idx-- {
+		if !remove(idx) {
+			continue
+		}
+		name, _ := excelize.ColumnNumberToName(idx + 1)
+		if err := f.RemoveCol(sheet, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package converter
+
+import (
+	"bytes"
+	"encoding/csv"
+	"io"
+
+	"github.com/nconklindev/chronos/internal/types"
+)
+
+// lineScan is how far into a file its first line ending is looked for.
+const lineScan = 64 * 1024
+
+// lineStyle is how the lines of CSV text end.
+type lineStyle struct {
+	crlf         bool
+	finalNewline bool
+}
+
+// detectLineStyle finds how the lines of CSV text end: as its first line
+// does, and whether its last line ends too. Text without line endings is
+// taken to use LF.
+func detectLineStyle(r io.ReaderAt, size int64) lineStyle {
+	var style lineStyle
+	head := make([]byte, min(size, lineScan))
+	n, _ := r.ReadAt(head, 0)
+	if i := bytes.IndexByte(head[:n], '\n'); i > 0 && head[i-1] == '\r' {
+		style.crlf = true
+	}
+
+	last := make([]byte, 1)
+	if size > 0 {
+		if _, err := r.ReadAt(last, size-1); err == nil {
+			style.finalNewline = last[0] == '\n' || last[0] == '\r'
+		}
+	}
+	return style
+}
+
+// outputLineStyle is how the lines of CSV output end: as the input's do,
+// unless the options say otherwise.
+func outputLineStyle(input lineStyle, opts types.ConversionOptions) lineStyle {
+	style := input
+	switch opts.LineEnding {
+	case types.LineEndingLF:
+		style.crlf = false
+	case types.LineEndingCRLF:
+		style.crlf = true
+	}
+	switch opts.FinalNewline {
+	case types.FinalNewlineAlways:
+		style.finalNewline = true
+	case types.FinalNewlineNever:
+		style.finalNewline = false
+	}
+	return style
+}
+
+// writeCSV writes records to w with lines ending in style, quoting fields
+// as q does.
+func writeCSV(w io.Writer, records [][]string, style lineStyle, q quoting) error {
+	if !style.finalNewline {
+		w = &heldNewline{w: w}
+	}
+	if !q.minimal() {
+		return writeQuoted(w, records, style.crlf, q)
+	}
+	writer := csv.NewWriter(w)
+	writer.UseCRLF = style.crlf
+	return writer.WriteAll(records)
+}
+
+// heldNewline holds back a line ending at the end of each write until more
+// is written after it, so the last one is never written.
+type heldNewline struct {
+	w    io.Writer
+	held []byte
+}
+
+func (h *heldNewline) Write(b []byte) (int, error) {
+	data := append(h.held, b...)
+	held := 0
+	switch {
+	case bytes.HasSuffix(data, []byte("\r\n")):
+		held = 2
+	case bytes.HasSuffix(data, []byte("\n")), bytes.HasSuffix(data, []byte("\r")):
+		held = 1
+	}
+
+	if _, err := h.w.Write(data[:len(data)-held]); err != nil {
+		return 0, err
+	}
+	h.held = append([]byte(nil), data[len(data)-held:]...)
+	return len(b), nil
+}
@@ -0,0 +1,65 @@
+package converter
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nconklindev/chronos/internal/types"
+)
+
+func TestConvertCSV_LineEndings(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		ending   types.LineEnding
+		final    types.FinalNewline
+		expected string
+	}{
+		{"LF as input", "Name,Hours\nAlice,1.5\n", "", "", "Name,Hours\nAlice,01:30\n"},
+		{"CRLF as input", "Name,Hours\r\nAlice,1.5\r\n", "", "", "Name,Hours\r\nAlice,01:30\r\n"},
+		{"No final newline as input", "Name,Hours\r\nAlice,1.5", "", "", "Name,Hours\r\nAlice,01:30"},
+		{"CRLF", "Name,Hours\nAlice,1.5\n", types.LineEndingCRLF, "", "Name,Hours\r\nAlice,01:30\r\n"},
+		{"LF", "Name,Hours\r\nAlice,1.5\r\n", types.LineEndingLF, "", "Name,Hours\nAlice,01:30\n"},
+		{"Always", "Name,Hours\nAlice,1.5", "", types.FinalNewlineAlways, "Name,Hours\nAlice,01:30\n"},
+		{"Never", "Name,Hours\nAlice,1.5\n", types.LineEndingCRLF, types.FinalNewlineNever, "Name,Hours\r\nAlice,01:30"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			input := filepath.Join(dir, "input.csv")
+			output := filepath.Join(dir, "output.csv")
+			if err := os.WriteFile(input, []byte(tt.input), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			opts := types.ConversionOptions{LineEnding: tt.ending, FinalNewline: tt.final}
+			if _, err := ConvertCSV(input, LocalFile(output), []int{1}, opts, nil); err != nil {
+				t.Fatalf("ConvertCSV failed: %v", err)
+			}
+			got, err := os.ReadFile(output)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.expected {
+				t.Errorf("Output = %q; want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHeldNewline(t *testing.T) {
+	// A line ending split across writes is held back whole
+	var out bytes.Buffer
+	w := &heldNewline{w: &out}
+	for _, chunk := range []string{"a,b\r", "\nc,d\r", "\n"} {
+		if _, err := w.Write([]byte(chunk)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if out.String() != "a,b\r\nc,d" {
+		t.Errorf("Output = %q; want %q", out.String(), "a,b\r\nc,d")
+	}
+}
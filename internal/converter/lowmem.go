@@ -0,0 +1,450 @@
+package converter
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/nconklindev/chronos/internal/types"
+	"github.com/xuri/excelize/v2"
+)
+
+// lowMemoryXMLLimit is how much of a worksheet or the shared strings the
+// low-memory mode unzips into memory. Larger parts are unzipped to
+// temporary files and streamed from there.
+const lowMemoryXMLLimit = 1 << 20
+
+// lowMemoryScanRows is how many rows the low-memory mode scans for the
+// header and time columns before converting.
+const lowMemoryScanRows = RowDetectionLimit * 4
+
+// shownTimePattern matches values shown by time formats, such as 7:30,
+// [h]:mm:ss totals like 31:15:00 and 7:30 AM.
+var shownTimePattern = regexp.MustCompile(`^\d+:\d{2}(:\d{2})?( ?[AaPp][Mm])?$`)
+
+// convertXLSXLowMemory converts the first sheet of an XLSX file without
+// loading its cells into the workbook. Rows are streamed from the sheet,
+// converted as CSV records are and written to a new workbook one at a
+// time. Cells that aren't converted keep their types and number formats,
+// but formulas, other formatting and the other sheets are not kept.
+func convertXLSXLowMemory(inputFile string, sink OutputSink, columnIndices []int, opts types.ConversionOptions, progress ProgressReporter) (*types.ConversionResult, error) {
+	switch {
+	case opts.NewSheet:
+		return nil, fmt.Errorf("converted sheets need the whole workbook, so they aren't available in low-memory mode")
+	case opts.CommentOriginals:
+		return nil, fmt.Errorf("original values in comments need the whole workbook, so they aren't available in low-memory mode")
+	}
+
+	in, err := os.Open(inputFile)
+	if err != nil {
+		return nil, err
+	}
+	defer in.Close()
+	info, err := in.Stat()
+	if err != nil {
+		return nil, err
+	}
+	f, err := openLowMemory(newProgressReader(in, info.Size(), PhaseRead, progress))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	sheet, err := readStreamedSheet(f, opts.FooterRows)
+	if err != nil {
+		return nil, err
+	}
+	opts.Columns = WithTimeColumns(opts.Columns, sheet.timeCols)
+	formats, err := openCellFormats(inputFile)
+	if err != nil {
+		return nil, err
+	}
+	defer formats.Close()
+
+	out := excelize.NewFile()
+	defer out.Close()
+	if err := out.SetSheetName(out.GetSheetName(0), sheet.name); err != nil {
+		return nil, err
+	}
+	sw, err := out.NewStreamWriter(sheet.name)
+	if err != nil {
+		return nil, err
+	}
+
+	// Rows above the header, such as a report title, are kept as they were.
+	// Records are converted in place, so keep the header to find where
+	// each written cell came from.
+	headerRowIdx := sheet.headerRowIdx
+	c := newRecordConverter(sheet.header, sheet.rows-headerRowIdx, sheet.widest(opts), columnIndices, opts, progress)
+	cells := newCellWriter(f, out, sheet.header, headerRowIdx)
+	var header []string
+	err = sheet.each(func(i int, row []string) error {
+		var changes []types.CellChange
+		if i >= headerRowIdx {
+			var ok bool
+			if row, changes, ok = c.convert(i-headerRowIdx, row); !ok {
+				return nil
+			}
+			if i == headerRowIdx {
+				header = row
+			}
+		}
+		from, err := formats.row(i)
+		if err != nil {
+			return err
+		}
+		cells.convertedCells(changes, opts)
+		cell, _ := excelize.CoordinatesToCellName(1, i+1)
+		return sw.SetRow(cell, cells.row(i, row, header, from))
+	})
+	if err != nil {
+		return nil, err
+	}
+	converted := c.finish(nil)
+
+	if opts.Table != "" {
+		// Footer rows that weren't dropped stay below the table
+		lastRow := headerRowIdx + 1 + footerStart(sheet.rows-headerRowIdx-1, opts)
+		if len(header) == 0 {
+			return nil, fmt.Errorf("no header row to add table %q to", opts.Table)
+		}
+		if err := addStreamTable(sw, opts.Table, opts.TableStyle, headerRowIdx+1, lastRow, len(header)); err != nil {
+			return nil, err
+		}
+	}
+	if err := sw.Flush(); err != nil {
+		return nil, err
+	}
+	if converted.totals != nil {
+		if err := addTotalsSheet(out, sheet.name, converted.totals); err != nil {
+			return nil, err
+		}
+	}
+	if err := writeWorkbook(out, sink, info.Size(), true, progress); err != nil {
+		return nil, err
+	}
+
+	// Changes count rows from the header, as in CSV files
+	for _, changes := range [][]types.CellChange{converted.changes, converted.lossy, converted.flagged} {
+		for i := range changes {
+			changes[i].Sheet = sheet.name
+			changes[i].Row += headerRowIdx
+		}
+	}
+	return &types.ConversionResult{
+		InputFile:     inputFile,
+		OutputFile:    sink.Location(),
+		ColumnsFound:  converted.columns,
+		RowsProcessed: converted.rowsProcessed,
+		Warnings:      converted.warnings(0, opts),
+		Changes:       converted.changes,
+		CellsChanged:  converted.count,
+		Hours:         converted.hours,
+		Lossy:         converted.lossy,
+		Flagged:       converted.flagged,
+	}, nil
+}
+
+// ReadFileDataLowMemory reads a file as ReadFileData does, streaming the
+// rows of XLSX files as the low-memory mode does rather than loading the
+// workbook. Only the first rows below the header and the last few are
+// kept, enough to choose columns and find the footer from, with RowCount
+// saying how many there are.
+func ReadFileDataLowMemory(filePath string) (*types.FileData, error) {
+	if strings.ToLower(filepath.Ext(filePath)) != ".xlsx" {
+		return ReadFileData(filePath)
+	}
+
+	in, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer in.Close()
+	f, err := openLowMemory(in)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	sheet, err := readStreamedSheet(f, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows, tail [][]string
+	err = sheet.each(func(i int, row []string) error {
+		switch {
+		case i <= sheet.headerRowIdx:
+		case len(rows) < lowMemoryScanRows:
+			rows = append(rows, row)
+		default:
+			tail = append(tail, row)
+			if len(tail) > RowDetectionLimit {
+				tail = tail[1:]
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	data := &types.FileData{
+		Headers:     sheet.header,
+		Rows:        append(rows, tail...),
+		Sheet:       sheet.name,
+		HeaderRow:   sheet.headerRowIdx,
+		TimeColumns: sheet.timeCols,
+	}
+	data.FooterRows = DetectFooterRows(data.Rows)
+	if n := sheet.rows - sheet.headerRowIdx - 1; n > len(data.Rows) {
+		data.RowCount = n
+	}
+	return data, nil
+}
+
+// openLowMemory opens a workbook, unzipping large parts to temporary files.
+func openLowMemory(r io.Reader) (*excelize.File, error) {
+	return excelize.OpenReader(r, excelize.Options{UnzipXMLSizeLimit: lowMemoryXMLLimit})
+}
+
+// streamedSheet is the first sheet of a workbook, scanned a row at a time
+// for what converting it needs without keeping its rows.
+type streamedSheet struct {
+	f            *excelize.File
+	name         string
+	header       []string
+	headerRowIdx int
+	timeCols     []int
+	isTime       map[int]bool
+	footerRows   int // How many of the last rows are read as shown
+	rows         int // How many rows the sheet has
+
+	// The widths of rows, for padding them: the first ones scanned, the
+	// last footerRows rows past those, and the widest in between
+	scanned []int
+	last    []int
+	middle  int
+}
+
+// readStreamedSheet scans the first sheet of a workbook a row at a time
+// for its header, time columns and size. The last footerRows rows are
+// read as shown.
+func readStreamedSheet(f *excelize.File, footerRows int) (*streamedSheet, error) {
+	s := &streamedSheet{f: f, name: f.GetSheetName(0), footerRows: max(footerRows, 0)}
+
+	// Find the header and time columns in the first rows, as readXLSXData
+	// does from the whole sheet, and count the rest
+	var shown, raw [][]string
+	err := streamRows(f, s.name, func(sh, r []string) bool {
+		if s.rows < lowMemoryScanRows {
+			shown = append(shown, sh)
+			raw = append(raw, r)
+			s.scanned = append(s.scanned, len(sh))
+		} else {
+			s.last = append(s.last, len(sh))
+			if len(s.last) > s.footerRows {
+				s.middle = max(s.middle, s.last[0])
+				s.last = s.last[1:]
+			}
+		}
+		s.rows++
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(shown) == 0 {
+		return nil, fmt.Errorf("empty file")
+	}
+	s.headerRowIdx = findHeaderRow(shown)
+	if s.headerRowIdx == -1 {
+		return nil, fmt.Errorf("could not find header row")
+	}
+	s.header = shown[s.headerRowIdx]
+	trimBOMs(s.header)
+	s.timeCols = shownTimeColumns(shown, raw, s.headerRowIdx)
+	s.isTime = make(map[int]bool, len(s.timeCols))
+	for _, col := range s.timeCols {
+		s.isTime[col] = true
+	}
+	return s, nil
+}
+
+// each streams the rows of the sheet to fn, with their index. Values below
+// the header are read as readXLSXData reads them, except for the footer
+// rows, which are passed through as shown.
+func (s *streamedSheet) each(fn func(i int, row []string) error) error {
+	firstShown := s.rows - s.footerRows
+	i := 0
+	var fnErr error
+	err := streamRows(s.f, s.name, func(sh, r []string) bool {
+		switch {
+		case i == s.headerRowIdx:
+			sh = s.header
+		case i > s.headerRowIdx && i < firstShown:
+			for col := range sh {
+				if col < len(r) {
+					sh[col] = storedValue(sh[col], r[col], s.isTime[col])
+				}
+			}
+		}
+		fnErr = fn(i, sh)
+		i++
+		return fnErr == nil
+	})
+	if err != nil {
+		return err
+	}
+	return fnErr
+}
+
+// records reads the header and every row below it.
+func (s *streamedSheet) records() ([][]string, error) {
+	var records [][]string
+	err := s.each(func(i int, row []string) error {
+		if i >= s.headerRowIdx {
+			records = append(records, row)
+		}
+		return nil
+	})
+	return records, err
+}
+
+// widest returns how many cells the widest row from the header down has,
+// leaving out footer rows the options drop.
+func (s *streamedSheet) widest(opts types.ConversionOptions) int {
+	end := s.rows
+	if opts.DropFooter {
+		end = s.headerRowIdx + 1 + footerStart(s.rows-s.headerRowIdx-1, opts)
+	}
+	widest := s.middle
+	for i, width := range s.scanned {
+		if i >= s.headerRowIdx && i < end {
+			widest = max(widest, width)
+		}
+	}
+	for j, width := range s.last {
+		if s.rows-len(s.last)+j < end {
+			widest = max(widest, width)
+		}
+	}
+	return widest
+}
+
+// streamRows streams the rows of a sheet, both as shown and as stored, to
+// each until it returns false. Rows missing from the sheet are empty.
+func streamRows(f *excelize.File, sheet string, each func(shown, raw []string) bool) error {
+	shownRows, err := f.Rows(sheet)
+	if err != nil {
+		return err
+	}
+	defer shownRows.Close()
+	rawRows, err := f.Rows(sheet)
+	if err != nil {
+		return err
+	}
+	defer rawRows.Close()
+
+	for shownRows.Next() && rawRows.Next() {
+		shown, err := shownRows.Columns()
+		if err != nil {
+			return err
+		}
+		raw, err := rawRows.Columns(excelize.Options{RawCellValue: true})
+		if err != nil {
+			return err
+		}
+		if !each(shown, raw) {
+			break
+		}
+	}
+	if err := shownRows.Error(); err != nil {
+		return err
+	}
+	return rawRows.Error()
+}
+
+// shownTimeColumns returns the columns whose first value below the header
+// is a number shown as a time, as timeColumns finds from number formats,
+// which streamed rows don't have.
+func shownTimeColumns(shown, raw [][]string, headerRowIdx int) []int {
+	var cols []int
+	for col := range shown[headerRowIdx] {
+		for rowIdx := headerRowIdx + 1; rowIdx < len(shown); rowIdx++ {
+			if col >= len(shown[rowIdx]) || strings.TrimSpace(shown[rowIdx][col]) == "" {
+				continue
+			}
+			if col < len(raw[rowIdx]) && isNumber(raw[rowIdx][col]) && shownTimePattern.MatchString(strings.TrimSpace(shown[rowIdx][col])) {
+				cols = append(cols, col)
+			}
+			break
+		}
+	}
+	return cols
+}
+
+// storedValue chooses between a streamed cell's shown and stored values
+// the way cellReader does, telling dates by how they're shown since
+// streamed rows don't have number formats.
+func storedValue(shown, raw string, timeCol bool) string {
+	switch {
+	case raw == shown || timeCol && isNumber(raw):
+		return raw
+	case !isNumber(raw) || looksDated(shown):
+		return shown
+	}
+	return raw
+}
+
+// looksDated reports whether a number is shown as a date or time, such as
+// 1/2/2024, 2024-01-02, 2-Jan or 7:30, rather than with digit grouping,
+// decimals or a currency.
+func looksDated(shown string) bool {
+	shown = strings.TrimSpace(shown)
+	for i, r := range shown {
+		switch {
+		case r == '/' || r == ':':
+			return true
+		case r == '-' && i > 0:
+			return true
+		case r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z':
+			return true
+		}
+	}
+	return false
+}
+
+func isNumber(s string) bool {
+	_, err := strconv.ParseFloat(s, 64)
+	return err == nil
+}
+
+// cellOf returns the value to write for a cell: a number when it's written
+// the way Go writes that number, so values like 0012 stay text, and
+// otherwise the text itself.
+func cellOf(value string) any {
+	if n, err := strconv.ParseFloat(value, 64); err == nil && strconv.FormatFloat(n, 'f', -1, 64) == value {
+		return n
+	}
+	return value
+}
+
+// addStreamTable wraps the rows from headerRow to lastRow, 1-indexed, of
+// width columns in an Excel table, as addTable does in whole workbooks.
+func addStreamTable(sw *excelize.StreamWriter, name, style string, headerRow, lastRow, width int) error {
+	if style == "" {
+		style = DefaultTableStyle
+	}
+	if err := CheckTableName(name); err != nil {
+		return err
+	}
+	if err := CheckTableStyle(style); err != nil {
+		return err
+	}
+	first, _ := excelize.CoordinatesToCellName(1, headerRow)
+	last, _ := excelize.CoordinatesToCellName(width, max(lastRow, headerRow+1))
+	return sw.AddTable(&excelize.Table{Range: first + ":" + last, Name: name, StyleName: style})
+}
@@ -0,0 +1,230 @@
+package converter
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/nconklindev/chronos/internal/types"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestConvertXLSX_LowMemory(t *testing.T) {
+	tmpDir := t.TempDir()
+	inputFile := filepath.Join(tmpDir, "input.xlsx")
+
+	f := excelize.NewFile()
+	f.SetSheetName("Sheet1", "Timesheet")
+	f.SetSheetRow("Timesheet", "A1", &[]any{"Weekly report"})
+	f.SetSheetRow("Timesheet", "A2", &[]any{"ID", "Name", "Shift", "Hours", "Date", "Dept"})
+	f.SetSheetRow("Timesheet", "A3", &[]any{"0012", "Alice", 0.3125, 7.5, 45292, "Ops"})
+	f.SetSheetRow("Timesheet", "A4", &[]any{"0013", "Bob", 0.5, 1234.25, 45293, "Sales"})
+	f.SetSheetRow("Timesheet", "A5", &[]any{"", "Total", 0.8125, 1241.75})
+	timeStyle, _ := f.NewStyle(&excelize.Style{NumFmt: 20})   // h:mm
+	dateStyle, _ := f.NewStyle(&excelize.Style{NumFmt: 14})   // m/d/yy
+	groupedStyle, _ := f.NewStyle(&excelize.Style{NumFmt: 4}) // #,##0.00
+	f.SetCellStyle("Timesheet", "C3", "C5", timeStyle)
+	f.SetCellStyle("Timesheet", "E3", "E4", dateStyle)
+	f.SetCellStyle("Timesheet", "D3", "D5", groupedStyle)
+	if err := f.SaveAs(inputFile); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	// Streaming reads the same data as loading the workbook
+	data, err := ReadFileData(inputFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	streamed, err := ReadFileDataLowMemory(inputFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(streamed, data) {
+		t.Errorf("Expected %+v streaming the workbook, got %+v", data, streamed)
+	}
+
+	opts := types.ConversionOptions{FooterRows: 1, Table: "Hours", Filters: []types.RowFilter{{Column: 5, Op: types.FilterNonEmpty}}, RecordChanges: true}
+	lowMemory := opts
+	lowMemory.LowMemory = true
+
+	// Low-memory mode writes the same values as converting the workbook
+	var sheets [2][][]string
+	for i, o := range []types.ConversionOptions{opts, lowMemory} {
+		outputFile := filepath.Join(tmpDir, "output.xlsx")
+		result, err := ConvertXLSX(inputFile, LocalFile(outputFile), []int{2, 3}, o, nil)
+		if err != nil {
+			t.Fatalf("ConvertXLSX failed: %v", err)
+		}
+		if len(result.Changes) != 4 || result.Changes[0].Row != 3 || result.Changes[0].Sheet != "Timesheet" {
+			t.Errorf("Expected the changes to name sheet rows, got %+v", result.Changes)
+		}
+
+		out, err := excelize.OpenFile(outputFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sheets[i], _ = out.GetRows("Timesheet")
+		tables, _ := out.GetTables("Timesheet")
+		if len(tables) != 1 || tables[0].Range != "A2:F4" {
+			t.Errorf("Expected the data in a table, got %+v", tables)
+		}
+		out.Close()
+	}
+
+	expected := [][]string{
+		{"Weekly report"},
+		{"ID", "Name", "Shift", "Hours", "Date", "Dept"},
+		{"0012", "Alice", "07:30", "07:30", "01-01-24", "Ops"},
+		{"0013", "Bob", "12:00", "1234:15", "01-02-24", "Sales"},
+		{"", "Total", "19:30", "1,241.75"},
+	}
+	if !reflect.DeepEqual(sheets[0], expected) {
+		t.Errorf("Expected %v converting the workbook, got %v", expected, sheets[0])
+	}
+	if !reflect.DeepEqual(sheets[1], expected) {
+		t.Errorf("Expected %v in low-memory mode, got %v", expected, sheets[1])
+	}
+
+	lowMemory.CommentOriginals = true
+	if _, err := ConvertXLSX(inputFile, LocalFile(filepath.Join(tmpDir, "comments.xlsx")), []int{3}, lowMemory, nil); err == nil {
+		t.Error("Expected comments to be rejected in low-memory mode")
+	}
+}
+
+func TestConvertXLSX_LowMemoryKeepsCellTypes(t *testing.T) {
+	tmpDir := t.TempDir()
+	inputFile := filepath.Join(tmpDir, "input.xlsx")
+
+	f := excelize.NewFile()
+	f.SetSheetRow("Sheet1", "A1", &[]any{"ID", "Badge", "Hours", "Pay", "Active"})
+	f.SetSheetRow("Sheet1", "A2", &[]any{"00123", "456", 1.5, 1234.5, true})
+	textStyle, _ := f.NewStyle(&excelize.Style{NumFmt: 49})   // @
+	groupedStyle, _ := f.NewStyle(&excelize.Style{NumFmt: 4}) // #,##0.00
+	f.SetCellStyle("Sheet1", "B2", "B2", textStyle)
+	f.SetCellStyle("Sheet1", "D2", "D2", groupedStyle)
+	if err := f.SaveAs(inputFile); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	// The converted copy of Hours comes right after it when originals are kept
+	for _, keepOriginal := range []bool{false, true} {
+		outputFile := filepath.Join(tmpDir, "output.xlsx")
+		opts := types.ConversionOptions{LowMemory: true, KeepOriginal: keepOriginal}
+		if _, err := ConvertXLSX(inputFile, LocalFile(outputFile), []int{2}, opts, nil); err != nil {
+			t.Fatalf("ConvertXLSX failed: %v", err)
+		}
+		pay, active := "D2", "E2"
+		if keepOriginal {
+			pay, active = "E2", "F2"
+		}
+
+		out, err := excelize.OpenFile(outputFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, cell := range []string{"A2", "B2"} {
+			if got, _ := out.GetCellType("Sheet1", cell); got != excelize.CellTypeInlineString && got != excelize.CellTypeSharedString {
+				t.Errorf("Expected %s to stay text (keep original %v), got type %v", cell, keepOriginal, got)
+			}
+		}
+		if got, _ := out.GetCellType("Sheet1", active); got != excelize.CellTypeBool {
+			t.Errorf("Expected %s to stay a boolean (keep original %v), got type %v", active, keepOriginal, got)
+		}
+		if got, _ := out.GetCellValue("Sheet1", pay); got != "1,234.50" {
+			t.Errorf("Expected %s to keep its number format (keep original %v), got %q", pay, keepOriginal, got)
+		}
+		if got, _ := out.GetCellValue("Sheet1", "A2"); got != "00123" {
+			t.Errorf("Expected the ID's leading zeros to be kept (keep original %v), got %q", keepOriginal, got)
+		}
+		out.Close()
+	}
+}
+
+func TestConvertXLSX_LowMemoryLongSheet(t *testing.T) {
+	tmpDir := t.TempDir()
+	inputFile := filepath.Join(tmpDir, "input.xlsx")
+
+	// More rows than are scanned for the header, with a wide row in the
+	// middle and a total below
+	f := excelize.NewFile()
+	f.SetSheetRow("Sheet1", "A1", &[]any{"Name", "Hours"})
+	for i := 1; i <= 100; i++ {
+		row := []any{fmt.Sprintf("E%d", i), 1.5}
+		if i == 50 {
+			row = []any{"Wide", 20, "", "note"}
+		}
+		cell, _ := excelize.CoordinatesToCellName(1, i+1)
+		f.SetSheetRow("Sheet1", cell, &row)
+	}
+	f.SetSheetRow("Sheet1", "A102", &[]any{"Total", 168.5})
+	if err := f.SaveAs(inputFile); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	// Reading keeps the first rows and the last ones
+	data, err := ReadFileDataLowMemory(inputFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.RowCount != 101 || len(data.Rows) != lowMemoryScanRows+RowDetectionLimit || data.FooterRows != 1 {
+		t.Errorf("Expected a sample of 101 rows with a footer, got %d of %d rows and %d footer rows", len(data.Rows), data.RowCount, data.FooterRows)
+	}
+
+	outputFile := filepath.Join(tmpDir, "output.xlsx")
+	opts := types.ConversionOptions{LowMemory: true, FooterRows: 1, DropFooter: true, FlagAbove: 12}
+	result, err := ConvertXLSX(inputFile, LocalFile(outputFile), []int{1}, opts, nil)
+	if err != nil {
+		t.Fatalf("ConvertXLSX failed: %v", err)
+	}
+	hours := []types.ColumnHours{{Column: "Hours", Cells: 100, Hours: 168.5}}
+	if result.Changes != nil || result.CellsChanged != 100 || !reflect.DeepEqual(result.Hours, hours) || len(result.Flagged) != 1 {
+		t.Errorf("Expected 100 cells counted and one flagged, without their changes, got %+v", result)
+	}
+
+	out, err := excelize.OpenFile(outputFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer out.Close()
+	rows, _ := out.GetRows("Sheet1")
+	if len(rows) != 101 {
+		t.Fatalf("Expected the header and 100 rows without the footer, got %d rows", len(rows))
+	}
+	// The flag column goes past the widest row
+	expected := map[int][]string{
+		0:  {"Name", "Hours", "", "", "Over 12 hours"},
+		1:  {"E1", "01:30"},
+		50: {"Wide", "20:00", "", "note", "Hours"},
+	}
+	for i, want := range expected {
+		if !reflect.DeepEqual(rows[i], want) {
+			t.Errorf("Expected row %d to be %q, got %q", i+1, want, rows[i])
+		}
+	}
+}
+
+func TestStoredValue(t *testing.T) {
+	tests := []struct {
+		shown, raw string
+		timeCol    bool
+		expected   string
+	}{
+		{"7.5", "7.5", false, "7.5"},
+		{"1,234.50", "1234.5", false, "1234.5"},
+		{"$12.00", "12", false, "12"},
+		{"01-02-24", "45293", false, "01-02-24"},
+		{"2024-01-02", "45293", false, "2024-01-02"},
+		{"7:30", "0.3125", false, "7:30"},
+		{"7:30", "0.3125", true, "0.3125"},
+		{"Alice", "Alice", false, "Alice"},
+	}
+	for _, tt := range tests {
+		if got := storedValue(tt.shown, tt.raw, tt.timeCol); got != tt.expected {
+			t.Errorf("storedValue(%q, %q, %v) = %q, expected %q", tt.shown, tt.raw, tt.timeCol, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package converter
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	"github.com/nconklindev/chronos/internal/types"
+)
+
+// ConvertMarkup converts a CSV, XLSX or JSON file as Convert does, but
+// writes the converted rows as a Markdown or HTML table, to paste into a
+// wiki, an email or a pull request. Converted columns and the originals
+// kept alongside them are right-aligned, as numbers are. Rows above an
+// XLSX header are left out, as are totals.
+func ConvertMarkup(inputFile string, sink OutputSink, columnIndices []int, opts types.ConversionOptions, progress ProgressReporter) (*types.ConversionResult, error) {
+	if opts.Totals != nil {
+		return nil, fmt.Errorf("totals aren't available in %s output", opts.Markup)
+	}
+
+	src, err := readSourceRecords(inputFile, &opts, progress)
+	if err != nil {
+		return nil, err
+	}
+	converted := convertRecords(src.records, columnIndices, opts, progress)
+
+	var text string
+	switch opts.Markup {
+	case types.MarkupMarkdown:
+		text = markdownTable(converted.records, converted.roles)
+	case types.MarkupHTML:
+		text = htmlTable(converted.records, converted.roles)
+	default:
+		return nil, fmt.Errorf("unknown markup %q", opts.Markup)
+	}
+
+	outFile, err := sink.Create()
+	if err != nil {
+		return nil, err
+	}
+	defer outFile.Close()
+	sum := newChecksumWriter(outFile)
+	written := newProgressWriter(sum, src.size, PhaseWrite, progress)
+	if _, err := io.WriteString(written, text); err != nil {
+		return nil, err
+	}
+	if err := outFile.Close(); err != nil {
+		return nil, err
+	}
+	if path, ok := diskPath(sink); ok {
+		if err := readBack(path, sum); err != nil {
+			return nil, err
+		}
+	}
+	written.finish()
+
+	return src.result(sink, converted, opts), nil
+}
+
+// markupCells returns a record's cells padded to width.
+func markupCells(record []string, width int) []string {
+	return append(record[:len(record):len(record)], make([]string, max(0, width-len(record)))...)
+}
+
+// markupWidth is the number of columns in the widest record.
+func markupWidth(records [][]string) int {
+	width := 0
+	for _, record := range records {
+		width = max(width, len(record))
+	}
+	return width
+}
+
+// rightAligned reports whether a column holds hours, converted or not.
+func rightAligned(roles []columnRole, col int) bool {
+	return col < len(roles) && (roles[col].converted || roles[col].original)
+}
+
+// markdownTable renders records, the first of them the header, as a
+// Markdown table.
+func markdownTable(records [][]string, roles []columnRole) string {
+	width := max(1, markupWidth(records))
+	var s strings.Builder
+	row := func(cells []string) {
+		s.WriteString("|")
+		for _, cell := range cells {
+			fmt.Fprintf(&s, " %s |", markdownCell(cell))
+		}
+		s.WriteString("\n")
+	}
+
+	row(markupCells(records[0], width))
+	s.WriteString("|")
+	for col := range width {
+		if rightAligned(roles, col) {
+			s.WriteString(" ---: |")
+		} else {
+			s.WriteString(" --- |")
+		}
+	}
+	s.WriteString("\n")
+	for _, record := range records[1:] {
+		row(markupCells(record, width))
+	}
+	return s.String()
+}
+
+// markdownCell escapes a value for a Markdown table cell, which can't hold
+// pipes or line breaks.
+func markdownCell(value string) string {
+	value = strings.ReplaceAll(value, "|", `\|`)
+	value = strings.ReplaceAll(value, "\r\n", "<br>")
+	return strings.ReplaceAll(value, "\n", "<br>")
+}
+
+// htmlTable renders records, the first of them the header, as an HTML page
+// holding a table. Styles are set on each element, since email clients
+// drop style sheets when a table is pasted.
+func htmlTable(records [][]string, roles []columnRole) string {
+	const (
+		tableStyle = "border-collapse: collapse; font-family: -apple-system, 'Segoe UI', Helvetica, Arial, sans-serif; font-size: 14px"
+		headStyle  = "border: 1px solid #d0d7de; padding: 6px 12px; background: #f6f8fa; font-weight: 600"
+		cellStyle  = "border: 1px solid #d0d7de; padding: 6px 12px"
+	)
+	width := markupWidth(records)
+	var s strings.Builder
+	s.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>Converted hours</title>\n</head>\n<body>\n")
+	fmt.Fprintf(&s, "<table style=\"%s\">\n", tableStyle)
+	row := func(tag, style string, cells []string) {
+		s.WriteString("<tr>")
+		for col, cell := range cells {
+			align := "left"
+			if rightAligned(roles, col) {
+				align = "right"
+			}
+			fmt.Fprintf(&s, "<%s style=\"%s; text-align: %s\">%s</%s>", tag, style, align, html.EscapeString(cell), tag)
+		}
+		s.WriteString("</tr>\n")
+	}
+
+	s.WriteString("<thead>\n")
+	row("th", headStyle, markupCells(records[0], width))
+	s.WriteString("</thead>\n<tbody>\n")
+	for _, record := range records[1:] {
+		row("td", cellStyle, markupCells(record, width))
+	}
+	s.WriteString("</tbody>\n</table>\n</body>\n</html>\n")
+	return s.String()
+}
@@ -0,0 +1,56 @@
+package converter
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nconklindev/chronos/internal/types"
+)
+
+func TestConvertMarkup(t *testing.T) {
+	tmpDir := t.TempDir()
+	inputFile := filepath.Join(tmpDir, "input.csv")
+	if err := os.WriteFile(inputFile, []byte("Name,Hours,Note\nAlice,1.5,a|b\n<Bob>,2,\"two\nlines\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	outputFile := filepath.Join(tmpDir, "output.md")
+	result, err := Convert(inputFile, LocalFile(outputFile), []int{1}, types.ConversionOptions{Markup: types.MarkupMarkdown, KeepOriginal: true}, nil)
+	if err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+	if result.RowsProcessed != 2 || result.OutputFile != outputFile {
+		t.Errorf("Unexpected result %+v", result)
+	}
+	data, _ := os.ReadFile(outputFile)
+	want := "| Name | Hours | Hours (HH:MM) | Note |\n" +
+		"| --- | ---: | ---: | --- |\n" +
+		"| Alice | 1.5 | 01:30 | a\\|b |\n" +
+		"| <Bob> | 2 | 02:00 | two<br>lines |\n"
+	if string(data) != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s", want, data)
+	}
+
+	outputFile = filepath.Join(tmpDir, "output.html")
+	if _, err := Convert(inputFile, LocalFile(outputFile), []int{1}, types.ConversionOptions{Markup: types.MarkupHTML}, nil); err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+	data, _ = os.ReadFile(outputFile)
+	for _, part := range []string{
+		"<table style=",
+		"text-align: left\">Name</th>",
+		"text-align: right\">Hours</th>",
+		"text-align: right\">01:30</td>",
+		"&lt;Bob&gt;",
+	} {
+		if !strings.Contains(string(data), part) {
+			t.Errorf("Expected the HTML to contain %q, got:\n%s", part, data)
+		}
+	}
+
+	if got := OutputExt(inputFile, types.ConversionOptions{Markup: types.MarkupMarkdown}); got != ".md" {
+		t.Errorf("Expected Markdown output to be .md, got %s", got)
+	}
+}
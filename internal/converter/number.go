@@ -0,0 +1,161 @@
+package converter
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/nconklindev/chronos/internal/types"
+)
+
+// errAmbiguousDecimal is returned for a number such as 1,250 whose comma
+// could be a decimal comma or group thousands, when nothing says which.
+var errAmbiguousDecimal = errors.New("its comma could be a decimal comma or a thousands separator")
+
+// ParseNumber reads a number written as decimal says, with separators
+// grouping thousands, a decimal comma or an exponent, such as 1,234.5,
+// 1.234,5 or 1.5E+00. Under DecimalAuto a lone comma before three digits,
+// as in 1,250, is refused rather than guessed at.
+func ParseNumber(s string, decimal types.Decimal) (float64, error) {
+	s = strings.TrimSpace(s)
+	// Most numbers have no separators to tell apart
+	if !strings.Contains(s, ",") && strings.Count(s, ".") <= 1 && (decimal != types.DecimalComma || !strings.Contains(s, ".")) {
+		return parseDecimal(s)
+	}
+
+	mantissa, exponent := s, ""
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		mantissa, exponent = s[:i], s[i:]
+	}
+
+	if decimal == types.DecimalAuto && ambiguousComma(mantissa) {
+		return 0, fmt.Errorf("%q is ambiguous: %w", s, errAmbiguousDecimal)
+	}
+
+	point, group := decimalSeparators(mantissa, decimal)
+	whole, fraction, hasFraction := strings.Cut(mantissa, point)
+	if strings.ContainsAny(fraction, ".,") {
+		return 0, fmt.Errorf("%q is not a number", s)
+	}
+	if strings.Contains(whole, group) {
+		digits, ok := ungroup(whole, group)
+		if !ok {
+			return 0, fmt.Errorf("%q is not a number: its digits aren't grouped in thousands", s)
+		}
+		whole = digits
+	}
+
+	normalized := whole
+	if hasFraction {
+		normalized += "." + fraction
+	}
+	return parseDecimal(normalized + exponent)
+}
+
+// hourSuffixes are the units exports write after hours, longest first so
+// each is stripped whole.
+var hourSuffixes = []string{"hours", "hour", "hrs", "hr", "h"}
+
+// ParseLenient reads a number as ParseNumber does, after stripping a unit
+// of hours such as in 7.5 hrs or 7.5h. A percentage of an hour, such as
+// 750%, is read as hours too.
+func ParseLenient(s string, decimal types.Decimal) (float64, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if number, ok := strings.CutSuffix(s, "%"); ok {
+		value, err := ParseNumber(number, decimal)
+		return value / 100, err
+	}
+	for _, suffix := range hourSuffixes {
+		if number, ok := strings.CutSuffix(s, suffix); ok {
+			return ParseNumber(number, decimal)
+		}
+	}
+	return ParseNumber(s, decimal)
+}
+
+// ambiguousCell reports whether a cell of a converted column was left
+// unconverted only because it's written like 1,250 and the column's
+// settings don't say which separator that is.
+func ambiguousCell(cell string, s types.ColumnSettings) bool {
+	_, err := parseCell(cell, s)
+	return errors.Is(err, errAmbiguousDecimal)
+}
+
+// parseCell reads a cell of a converted column as its settings say it's
+// written.
+func parseCell(cell string, s types.ColumnSettings) (float64, error) {
+	if s.Lenient {
+		return ParseLenient(cell, s.Decimal)
+	}
+	return ParseNumber(cell, s.Decimal)
+}
+
+// decimalSeparators returns the decimal separator and the thousands
+// separator of a number without its exponent, as decimal says they're
+// written.
+func decimalSeparators(mantissa string, decimal types.Decimal) (point, group string) {
+	switch decimal {
+	case types.DecimalPoint:
+		return ".", ","
+	case types.DecimalComma:
+		return ",", "."
+	}
+
+	lastPoint, lastComma := strings.LastIndex(mantissa, "."), strings.LastIndex(mantissa, ",")
+	switch {
+	case lastPoint >= 0 && lastComma >= 0:
+		if lastComma > lastPoint {
+			return ",", "."
+		}
+		return ".", ","
+	case lastComma >= 0:
+		// A lone comma is a decimal comma, those before three digits
+		// having been refused as ambiguous
+		if strings.Count(mantissa, ",") == 1 {
+			return ",", "."
+		}
+		return ".", ","
+	case strings.Count(mantissa, ".") > 1:
+		return ",", "."
+	}
+	return ".", ","
+}
+
+// ambiguousComma reports whether a number without its exponent has a lone
+// comma, and no point, with exactly three digits after it, as in 1,250.
+func ambiguousComma(mantissa string) bool {
+	if strings.Count(mantissa, ",") != 1 || strings.Contains(mantissa, ".") {
+		return false
+	}
+	_, fraction, _ := strings.Cut(mantissa, ",")
+	return len(fraction) == 3
+}
+
+// ungroup removes the thousands separators from the whole part of a
+// number, reporting whether they split it into groups of three digits.
+func ungroup(whole, group string) (string, bool) {
+	sign := ""
+	if strings.HasPrefix(whole, "-") || strings.HasPrefix(whole, "+") {
+		sign, whole = whole[:1], whole[1:]
+	}
+	groups := strings.Split(whole, group)
+	for i, g := range groups {
+		if len(g) > 3 || i > 0 && len(g) != 3 || g == "" {
+			return "", false
+		}
+	}
+	return sign + strings.Join(groups, ""), true
+}
+
+// parseDecimal parses a number written in decimal, such as 7.5 or 1.5e1.
+// ParseFloat also reads hexadecimal numbers, NaN and infinities, which no
+// timesheet means as hours.
+func parseDecimal(s string) (float64, error) {
+	for _, r := range s {
+		if (r < '0' || r > '9') && !strings.ContainsRune(".eE+-", r) {
+			return 0, fmt.Errorf("%q is not a decimal number", s)
+		}
+	}
+	return strconv.ParseFloat(s, 64)
+}
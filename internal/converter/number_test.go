@@ -0,0 +1,145 @@
+package converter
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/nconklindev/chronos/internal/types"
+)
+
+func TestParseNumber(t *testing.T) {
+	tests := []struct {
+		input    string
+		decimal  types.Decimal
+		expected float64
+		ok       bool
+	}{
+		{"7.5", types.DecimalAuto, 7.5, true},
+		{" 7.5 ", types.DecimalAuto, 7.5, true},
+		{"1.5E+00", types.DecimalAuto, 1.5, true},
+		{"7.5e-1", types.DecimalAuto, 0.75, true},
+		{"1,234.5", types.DecimalAuto, 1234.5, true},
+		{"1.234,5", types.DecimalAuto, 1234.5, true},
+		{"1,234,567", types.DecimalAuto, 1234567, true},
+		{"1.234.567", types.DecimalAuto, 1234567, true},
+		{"-1,234.5", types.DecimalAuto, -1234.5, true},
+		{"1,5", types.DecimalAuto, 1.5, true},
+		{"1,5E+00", types.DecimalAuto, 1.5, true},
+		// A lone comma before three digits could be either separator, so
+		// it's only read when the column says which
+		{"1,234", types.DecimalAuto, 0, false},
+		{"-1,250E+00", types.DecimalAuto, 0, false},
+		{"1,2345", types.DecimalAuto, 1.2345, true},
+		{"1,234", types.DecimalComma, 1.234, true},
+		{"1,234", types.DecimalPoint, 1234, true},
+		{"1,5", types.DecimalPoint, 0, false},
+		{"1.234", types.DecimalComma, 1234, true},
+		{"7.5", types.DecimalComma, 0, false},
+		{"7", types.DecimalComma, 7, true},
+		{"1,23.5", types.DecimalAuto, 0, false},
+		{"12,34,567.5", types.DecimalAuto, 0, false},
+		{",5", types.DecimalPoint, 0, false},
+		{"1.2.3,4,5", types.DecimalAuto, 0, false},
+		{"1,234.5.6", types.DecimalAuto, 0, false},
+		{"NaN", types.DecimalAuto, 0, false},
+		{"$12", types.DecimalAuto, 0, false},
+	}
+	for _, tt := range tests {
+		got, err := ParseNumber(tt.input, tt.decimal)
+		if (err == nil) != tt.ok || got != tt.expected {
+			t.Errorf("ParseNumber(%q, %q) = %v, %v, expected %v", tt.input, tt.decimal, got, err, tt.expected)
+		}
+	}
+}
+
+func TestParseLenient(t *testing.T) {
+	tests := []struct {
+		input    string
+		decimal  types.Decimal
+		expected float64
+		ok       bool
+	}{
+		{"7.5", types.DecimalAuto, 7.5, true},
+		{"7.5 hrs", types.DecimalAuto, 7.5, true},
+		{"7.5h", types.DecimalAuto, 7.5, true},
+		{"8 Hours", types.DecimalAuto, 8, true},
+		{"1 hour", types.DecimalAuto, 1, true},
+		{"0.5 hr", types.DecimalAuto, 0.5, true},
+		{"750%", types.DecimalAuto, 7.5, true},
+		{"7,5 h", types.DecimalComma, 7.5, true},
+		{"h", types.DecimalAuto, 0, false},
+		{"7.5 days", types.DecimalAuto, 0, false},
+		{"$12", types.DecimalAuto, 0, false},
+	}
+	for _, tt := range tests {
+		got, err := ParseLenient(tt.input, tt.decimal)
+		if (err == nil) != tt.ok || (tt.ok && got != tt.expected) {
+			t.Errorf("ParseLenient(%q, %q) = %v, %v, expected %v", tt.input, tt.decimal, got, err, tt.expected)
+		}
+	}
+}
+
+func TestConvertCSV_Separators(t *testing.T) {
+	tmpDir := t.TempDir()
+	inputFile := filepath.Join(tmpDir, "input.csv")
+	outputFile := filepath.Join(tmpDir, "output.csv")
+	writeTestCSV(t, inputFile, [][]string{
+		{"Name", "Hours", "Stunden"},
+		{"Alice", "1,234.5", "7,5"},
+		{"Bob", "7.5E+00", "1.234,25"},
+	})
+
+	opts := types.ConversionOptions{
+		Columns: map[int]types.ColumnSettings{2: {Decimal: types.DecimalComma}},
+	}
+	if _, err := ConvertCSV(inputFile, LocalFile(outputFile), []int{1, 2}, opts, nil); err != nil {
+		t.Fatalf("ConvertCSV failed: %v", err)
+	}
+
+	expected := [][]string{
+		{"Name", "Hours", "Stunden"},
+		{"Alice", "1234:30", "07:30"},
+		{"Bob", "07:30", "1234:15"},
+	}
+	if records := readTestCSV(t, outputFile); !reflect.DeepEqual(records, expected) {
+		t.Errorf("Expected %v, got %v", expected, records)
+	}
+}
+
+func TestConvertCSV_AmbiguousDecimal(t *testing.T) {
+	tmpDir := t.TempDir()
+	inputFile := filepath.Join(tmpDir, "input.csv")
+	outputFile := filepath.Join(tmpDir, "output.csv")
+	writeTestCSV(t, inputFile, [][]string{
+		{"Name", "Hours"},
+		{"Alice", "1,250"},
+		{"Bob", "n/a"},
+		{"Carol", "7,5"},
+	})
+
+	// 1,250 is left alone with its own warning, apart from non-numbers
+	result, err := ConvertCSV(inputFile, LocalFile(outputFile), []int{1}, types.ConversionOptions{}, nil)
+	if err != nil {
+		t.Fatalf("ConvertCSV failed: %v", err)
+	}
+	warnings := []string{
+		"1 cell(s) such as 1,250 could have a decimal comma or a thousands separator and were left unchanged; set the column's decimal separator to convert them",
+		"1 non-numeric cell(s) in converted columns were left unchanged",
+	}
+	if !reflect.DeepEqual(result.Warnings, warnings) {
+		t.Errorf("Expected warnings %q, got %q", warnings, result.Warnings)
+	}
+	if records := readTestCSV(t, outputFile); records[1][1] != "1,250" || records[3][1] != "07:30" {
+		t.Errorf("Unexpected output %v", records)
+	}
+
+	// Saying which separator the comma is converts it
+	opts := types.ConversionOptions{Columns: map[int]types.ColumnSettings{1: {Decimal: types.DecimalComma}}}
+	if _, err := ConvertCSV(inputFile, LocalFile(outputFile), []int{1}, opts, nil); err != nil {
+		t.Fatalf("ConvertCSV failed: %v", err)
+	}
+	if records := readTestCSV(t, outputFile); records[1][1] != "01:15" {
+		t.Errorf("Expected 1,250 read with a decimal comma, got %v", records)
+	}
+}
@@ -0,0 +1,66 @@
+package converter
+
+import "github.com/nconklindev/chronos/internal/types"
+
+// SplitValue divides a value counting the column's unit into the part up
+// to the column's overtime threshold and the part past it. Columns
+// without a threshold aren't split.
+func SplitValue(value float64, s types.ColumnSettings) (regular, overtime float64) {
+	if s.OvertimeAfter <= 0 {
+		return value, 0
+	}
+	threshold := s.OvertimeAfter
+	if factor, ok := unitHours[s.Unit]; ok {
+		threshold /= factor
+	}
+	if value <= threshold {
+		return value, 0
+	}
+	return threshold, value - threshold
+}
+
+// overtimeCell converts the overtime part of a cell in a split column.
+// Empty and non-numeric cells have no overtime.
+func overtimeCell(cell string, s types.ColumnSettings) string {
+	decimal, err := CellValue(cell, s)
+	if err != nil {
+		return ""
+	}
+	_, overtime := SplitValue(decimal, s)
+	return ConvertValue(overtime, s)
+}
+
+// splits reports whether converted column col is split into regular and overtime columns.
+func splits(col int, opts types.ConversionOptions) bool {
+	return opts.Columns[col].OvertimeAfter > 0 && IsDuration(opts.Columns[col])
+}
+
+// splitHeaders returns the headers of the regular and overtime columns
+// that converted column col is split into. With more than one split
+// column, the default names start with the column's own header so they
+// can be told apart.
+func splitHeaders(header string, col int, opts types.ConversionOptions, several bool) (regular, overtime string) {
+	suffix := opts.HeaderSuffix
+	if suffix == "" {
+		suffix = DefaultHeaderSuffix
+	}
+	regular, overtime = "Regular"+suffix, "Overtime"+suffix
+	if several {
+		regular, overtime = header+" "+regular, header+" "+overtime
+	}
+	if name := opts.Columns[col].Header; name != "" {
+		regular = name
+	}
+	return regular, overtime
+}
+
+// splitCount returns how many of the converted columns are split.
+func splitCount(colMap map[int]bool, opts types.ConversionOptions) int {
+	n := 0
+	for col := range colMap {
+		if splits(col, opts) {
+			n++
+		}
+	}
+	return n
+}
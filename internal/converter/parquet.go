@@ -0,0 +1,433 @@
+package converter
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/nconklindev/chronos/internal/types"
+)
+
+// parquetMagic starts and ends every Parquet file.
+const parquetMagic = "PAR1"
+
+// Parquet physical types, encodings and the other enums of the file
+// metadata, as numbered in parquet.thrift.
+const (
+	parquetInt64     = 2
+	parquetDouble    = 5
+	parquetByteArray = 6
+
+	parquetPlain = 0
+	parquetRLE   = 3
+
+	parquetOptional       = 1
+	parquetUTF8           = 0
+	parquetINT64          = 18 // The converted type of signed 64 bit integers
+	parquetLogicalInteger = 10 // The field of the logical type union for integers
+	parquetDataPage       = 0
+	parquetUncompressed   = 0
+)
+
+// ConvertParquet converts a CSV, XLSX or JSON file as Convert does, but
+// writes the converted rows as a Parquet file, so they can be loaded into a
+// data warehouse or queried with Athena or Spark. Converted columns are text,
+// or whole seconds with ParquetDurations; the original values kept
+// alongside them are doubles, and the other columns text. Rows above an
+// XLSX header, such as a report title, are left out, as are totals.
+func ConvertParquet(inputFile string, sink OutputSink, columnIndices []int, opts types.ConversionOptions, progress ProgressReporter) (*types.ConversionResult, error) {
+	if opts.Totals != nil {
+		return nil, fmt.Errorf("totals aren't available in Parquet output")
+	}
+
+	src, err := readSourceRecords(inputFile, &opts, progress)
+	if err != nil {
+		return nil, err
+	}
+	converted := convertRecords(src.records, columnIndices, opts, progress)
+
+	outFile, err := sink.Create()
+	if err != nil {
+		return nil, err
+	}
+	defer outFile.Close()
+	sum := newChecksumWriter(outFile)
+	written := newProgressWriter(sum, src.size, PhaseWrite, progress)
+	if err := writeParquet(written, converted.records, converted.roles, opts.ParquetDurations); err != nil {
+		return nil, err
+	}
+	if err := outFile.Close(); err != nil {
+		return nil, err
+	}
+	if path, ok := diskPath(sink); ok {
+		if err := readBack(path, sum); err != nil {
+			return nil, err
+		}
+	}
+	written.finish()
+
+	return src.result(sink, converted, opts), nil
+}
+
+// parquetColumn is a column of Parquet output, its values encoded as
+// written: each non-null value in PLAIN encoding, and whether each row has
+// one.
+type parquetColumn struct {
+	name    string
+	kind    int32 // Physical type
+	values  bytes.Buffer
+	present []bool
+}
+
+// add appends a row's value, or a null when ok is false.
+func (c *parquetColumn) add(value any, ok bool) {
+	c.present = append(c.present, ok)
+	if !ok {
+		return
+	}
+	switch v := value.(type) {
+	case string:
+		binary.Write(&c.values, binary.LittleEndian, uint32(len(v)))
+		c.values.WriteString(v)
+	case float64:
+		binary.Write(&c.values, binary.LittleEndian, math.Float64bits(v))
+	case int64:
+		binary.Write(&c.values, binary.LittleEndian, v)
+	}
+}
+
+// writeParquet writes records, the first of them the header, as a Parquet
+// file of one row group, typing each column by its role. Every column is
+// optional: empty and unreadable numbers are nulls, and so are the cells
+// rows are too short for.
+func writeParquet(w io.Writer, records [][]string, roles []columnRole, durations bool) error {
+	width := 0
+	for _, record := range records {
+		width = max(width, len(record))
+	}
+	names := parquetNames(records[0], width)
+
+	columns := make([]*parquetColumn, width)
+	for col := range columns {
+		var role columnRole
+		if col < len(roles) {
+			role = roles[col]
+		}
+		c := &parquetColumn{name: names[col], kind: parquetByteArray}
+		switch {
+		case role.original:
+			c.kind = parquetDouble
+		case role.converted && durations:
+			c.kind = parquetInt64
+		}
+
+		for _, record := range records[1:] {
+			if col >= len(record) {
+				c.add(nil, false)
+				continue
+			}
+			cell := record[col]
+			switch c.kind {
+			case parquetDouble:
+				n, err := parseCell(cell, role.settings)
+				c.add(n, err == nil && strings.TrimSpace(cell) != "")
+			case parquetInt64:
+				seconds, ok := durationSeconds(cell, role.settings)
+				c.add(seconds, ok)
+			default:
+				c.add(cell, true)
+			}
+		}
+		columns[col] = c
+	}
+
+	return writeParquetFile(w, columns, len(records)-1)
+}
+
+// parquetNames names the columns of Parquet output from the header, which
+// must name them uniquely: blank headers are named by their position and
+// repeated ones numbered.
+func parquetNames(header []string, width int) []string {
+	names := make([]string, width)
+	used := make(map[string]bool, width)
+	for col := range names {
+		name := ""
+		if col < len(header) {
+			name = strings.TrimSpace(header[col])
+		}
+		if name == "" {
+			name = fmt.Sprintf("column_%d", col+1)
+		}
+		unique := name
+		for n := 2; used[unique]; n++ {
+			unique = fmt.Sprintf("%s_%d", name, n)
+		}
+		used[unique] = true
+		names[col] = unique
+	}
+	return names
+}
+
+// durationSeconds reads a converted value back as whole seconds.
+func durationSeconds(value string, s types.ColumnSettings) (int64, bool) {
+	value = strings.TrimSpace(value)
+	sign := 1.0
+	if rest, ok := strings.CutPrefix(value, "-"); ok {
+		value, sign = rest, -1
+	}
+	hours, ok := ParseConverted(value, s)
+	if !ok || value == "" {
+		return 0, false
+	}
+	return int64(math.Round(sign * hours * 3600)), true
+}
+
+// writeParquetFile writes columns of rows values each as a Parquet file,
+// each column chunk a single uncompressed data page.
+func writeParquetFile(w io.Writer, columns []*parquetColumn, rows int) error {
+	out := &countingWriter{w: w}
+	io.WriteString(out, parquetMagic)
+
+	type chunk struct {
+		offset, size int64
+	}
+	chunks := make([]chunk, len(columns))
+	for i, c := range columns {
+		// Definition levels come first, RLE encoded with a bit width of
+		// one and prefixed with their length
+		levels := rleLevels(c.present)
+		var page bytes.Buffer
+		binary.Write(&page, binary.LittleEndian, uint32(len(levels)))
+		page.Write(levels)
+		page.Write(c.values.Bytes())
+
+		var header thriftWriter
+		header.i32(1, parquetDataPage)
+		header.i32(2, int32(page.Len()))
+		header.i32(3, int32(page.Len()))
+		header.beginStruct(5)
+		header.i32(1, int32(rows))
+		header.i32(2, parquetPlain)
+		header.i32(3, parquetRLE)
+		header.i32(4, parquetRLE)
+		header.endStruct()
+		header.stop()
+
+		chunks[i].offset = out.n
+		out.Write(header.Bytes())
+		out.Write(page.Bytes())
+		chunks[i].size = out.n - chunks[i].offset
+	}
+
+	var meta thriftWriter
+	meta.i32(1, 1)
+	meta.list(2, thriftStruct, len(columns)+1)
+	meta.beginElement()
+	meta.binary(4, "schema")
+	meta.i32(5, int32(len(columns)))
+	meta.endStruct()
+	for _, c := range columns {
+		meta.beginElement()
+		meta.i32(1, c.kind)
+		meta.i32(3, parquetOptional)
+		meta.binary(4, c.name)
+		switch c.kind {
+		case parquetByteArray:
+			meta.i32(6, parquetUTF8)
+		case parquetInt64:
+			// Durations are plain signed integers to Parquet; the Arrow
+			// schema marks them as durations
+			meta.i32(6, parquetINT64)
+			meta.beginStruct(10)
+			meta.beginStruct(parquetLogicalInteger)
+			meta.i8(1, 64)
+			meta.boolean(2, true)
+			meta.endStruct()
+			meta.endStruct()
+		}
+		meta.endStruct()
+	}
+	meta.i64(3, int64(rows))
+	meta.list(4, thriftStruct, 1)
+	meta.beginElement()
+	meta.list(1, thriftStruct, len(columns))
+	total := int64(0)
+	for i, c := range columns {
+		meta.beginElement()
+		meta.i64(2, chunks[i].offset)
+		meta.beginStruct(3)
+		meta.i32(1, c.kind)
+		meta.list(2, thriftI32, 2)
+		meta.varint(parquetPlain)
+		meta.varint(parquetRLE)
+		meta.list(3, thriftBinary, 1)
+		meta.bytes(c.name)
+		meta.i32(4, parquetUncompressed)
+		meta.i64(5, int64(rows))
+		meta.i64(6, chunks[i].size)
+		meta.i64(7, chunks[i].size)
+		meta.i64(9, chunks[i].offset)
+		meta.endStruct()
+		meta.endStruct()
+		total += chunks[i].size
+	}
+	meta.i64(2, total)
+	meta.i64(3, int64(rows))
+	meta.endStruct()
+	meta.list(5, thriftStruct, 1)
+	meta.beginElement()
+	meta.binary(1, arrowSchemaKey)
+	meta.binary(2, arrowSchema(columns))
+	meta.endStruct()
+	meta.binary(6, "chronos")
+	meta.stop()
+
+	out.Write(meta.Bytes())
+	binary.Write(out, binary.LittleEndian, uint32(meta.Len()))
+	io.WriteString(out, parquetMagic)
+	return out.err
+}
+
+// rleLevels encodes definition levels of one bit as runs of the RLE and
+// bit-packing hybrid encoding.
+func rleLevels(present []bool) []byte {
+	var b []byte
+	for i := 0; i < len(present); {
+		run := 1
+		for i+run < len(present) && present[i+run] == present[i] {
+			run++
+		}
+		b = binary.AppendUvarint(b, uint64(run)<<1)
+		if present[i] {
+			b = append(b, 1)
+		} else {
+			b = append(b, 0)
+		}
+		i += run
+	}
+	return b
+}
+
+// countingWriter counts the bytes written through it, keeping the first
+// error so a file can be written without checking each write.
+type countingWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (c *countingWriter) Write(b []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	n, err := c.w.Write(b)
+	c.n += int64(n)
+	c.err = err
+	return n, err
+}
+
+// Thrift compact protocol types, which Parquet metadata is written in.
+// Booleans are written in their field's type, as true or false.
+const (
+	thriftTrue   = 1
+	thriftFalse  = 2
+	thriftI8     = 3
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// thriftWriter writes structs in the Thrift compact protocol, each field
+// numbered relative to the last one written in its struct.
+type thriftWriter struct {
+	bytes.Buffer
+	last  int16   // The last field written in the current struct
+	outer []int16 // The last fields of the structs it's nested in
+}
+
+func (t *thriftWriter) field(id int16, kind byte) {
+	if delta := id - t.last; delta > 0 && delta <= 15 {
+		t.WriteByte(byte(delta)<<4 | kind)
+	} else {
+		t.WriteByte(kind)
+		t.varint(int64(id))
+	}
+	t.last = id
+}
+
+// varint writes an integer zigzag encoded, as i32 and i64 values are.
+func (t *thriftWriter) varint(v int64) {
+	t.Write(binary.AppendUvarint(nil, uint64(v<<1^v>>63)))
+}
+
+func (t *thriftWriter) bytes(s string) {
+	t.Write(binary.AppendUvarint(nil, uint64(len(s))))
+	t.WriteString(s)
+}
+
+func (t *thriftWriter) boolean(id int16, v bool) {
+	if v {
+		t.field(id, thriftTrue)
+	} else {
+		t.field(id, thriftFalse)
+	}
+}
+
+func (t *thriftWriter) i8(id int16, v int8) {
+	t.field(id, thriftI8)
+	t.WriteByte(byte(v))
+}
+
+func (t *thriftWriter) i32(id int16, v int32) {
+	t.field(id, thriftI32)
+	t.varint(int64(v))
+}
+
+func (t *thriftWriter) i64(id int16, v int64) {
+	t.field(id, thriftI64)
+	t.varint(v)
+}
+
+func (t *thriftWriter) binary(id int16, s string) {
+	t.field(id, thriftBinary)
+	t.bytes(s)
+}
+
+// list starts a list field of n elements of kind, which follow.
+func (t *thriftWriter) list(id int16, kind byte, n int) {
+	t.field(id, thriftList)
+	if n < 15 {
+		t.WriteByte(byte(n)<<4 | kind)
+	} else {
+		t.WriteByte(0xf0 | kind)
+		t.Write(binary.AppendUvarint(nil, uint64(n)))
+	}
+}
+
+// beginStruct starts a struct field, ended by endStruct.
+func (t *thriftWriter) beginStruct(id int16) {
+	t.field(id, thriftStruct)
+	t.beginElement()
+}
+
+// beginElement starts a struct in a list, ended by endStruct.
+func (t *thriftWriter) beginElement() {
+	t.outer = append(t.outer, t.last)
+	t.last = 0
+}
+
+func (t *thriftWriter) endStruct() {
+	t.stop()
+	t.last = t.outer[len(t.outer)-1]
+	t.outer = t.outer[:len(t.outer)-1]
+}
+
+// stop ends a struct, such as the outermost one.
+func (t *thriftWriter) stop() {
+	t.WriteByte(0)
+}
@@ -0,0 +1,327 @@
+package converter
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"flag"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nconklindev/chronos/internal/types"
+	"github.com/parquet-go/parquet-go"
+	"github.com/xuri/excelize/v2"
+)
+
+// updateGolden rewrites the golden files in testdata from what's written
+// now, for a change to the output that's meant: go test -run Golden -update
+var updateGolden = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// thriftReader decodes the Thrift compact protocol, enough to read back
+// the metadata writeParquet writes: structs decode to maps of field ids.
+type thriftReader struct {
+	b []byte
+}
+
+func (r *thriftReader) uvarint() uint64 {
+	v, n := binary.Uvarint(r.b)
+	r.b = r.b[n:]
+	return v
+}
+
+func (r *thriftReader) value(kind byte) any {
+	switch kind {
+	case thriftTrue, thriftFalse:
+		return kind == thriftTrue
+	case thriftI8:
+		v := int64(int8(r.b[0]))
+		r.b = r.b[1:]
+		return v
+	case thriftI32, thriftI64:
+		v := r.uvarint()
+		return int64(v>>1) ^ -int64(v&1)
+	case thriftBinary:
+		n := r.uvarint()
+		s := string(r.b[:n])
+		r.b = r.b[n:]
+		return s
+	case thriftList:
+		header := r.b[0]
+		r.b = r.b[1:]
+		n := int(header >> 4)
+		if n == 15 {
+			n = int(r.uvarint())
+		}
+		list := make([]any, n)
+		for i := range list {
+			list[i] = r.value(header & 0x0f)
+		}
+		return list
+	case thriftStruct:
+		fields := map[int16]any{}
+		var id int16
+		for {
+			header := r.b[0]
+			r.b = r.b[1:]
+			if header == 0 {
+				return fields
+			}
+			if delta := int16(header >> 4); delta != 0 {
+				id += delta
+			} else {
+				id = int16(r.value(thriftI32).(int64))
+			}
+			fields[id] = r.value(header & 0x0f)
+		}
+	}
+	panic("unexpected thrift type")
+}
+
+// readParquet reads back a file written by writeParquet: its column names
+// and physical types, and each column's values, nil for nulls.
+func readParquet(t *testing.T, path string) ([]string, []int64, [][]any) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(data, []byte(parquetMagic)) || !bytes.HasSuffix(data, []byte(parquetMagic)) {
+		t.Fatalf("Expected Parquet magic bytes, got %q", data)
+	}
+	size := binary.LittleEndian.Uint32(data[len(data)-8:])
+	footer := &thriftReader{b: data[len(data)-8-int(size) : len(data)-8]}
+	meta := footer.value(thriftStruct).(map[int16]any)
+
+	var names []string
+	var kinds []int64
+	for _, element := range meta[2].([]any)[1:] {
+		names = append(names, element.(map[int16]any)[4].(string))
+		kinds = append(kinds, element.(map[int16]any)[1].(int64))
+	}
+
+	rows := int(meta[3].(int64))
+	group := meta[4].([]any)[0].(map[int16]any)
+	var columns [][]any
+	for i, chunk := range group[1].([]any) {
+		offset := chunk.(map[int16]any)[3].(map[int16]any)[9].(int64)
+		page := &thriftReader{b: data[offset:]}
+		page.value(thriftStruct)
+
+		levels := binary.LittleEndian.Uint32(page.b)
+		runs := &thriftReader{b: page.b[4 : 4+levels]}
+		values := page.b[4+levels:]
+		var column []any
+		for len(runs.b) > 0 {
+			run := int(runs.uvarint() >> 1)
+			present := runs.b[0] == 1
+			runs.b = runs.b[1:]
+			for range run {
+				if !present {
+					column = append(column, nil)
+					continue
+				}
+				switch kinds[i] {
+				case parquetDouble:
+					column = append(column, math.Float64frombits(binary.LittleEndian.Uint64(values)))
+					values = values[8:]
+				case parquetInt64:
+					column = append(column, int64(binary.LittleEndian.Uint64(values)))
+					values = values[8:]
+				default:
+					n := binary.LittleEndian.Uint32(values)
+					column = append(column, string(values[4:4+n]))
+					values = values[4+n:]
+				}
+			}
+		}
+		if len(column) != rows {
+			t.Fatalf("Expected %d values in column %s, got %d", rows, names[i], len(column))
+		}
+		columns = append(columns, column)
+	}
+	return names, kinds, columns
+}
+
+func TestConvertParquet(t *testing.T) {
+	tmpDir := t.TempDir()
+	inputFile := filepath.Join(tmpDir, "input.csv")
+	if err := os.WriteFile(inputFile, []byte("Name,Hours,Hours\nAlice,1.5,2\nBob,,x\nCarol,9.25\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		opts  types.ConversionOptions
+		names []string
+		kinds []int64
+		want  [][]any
+	}{
+		{
+			name:  "in place",
+			opts:  types.ConversionOptions{Parquet: true},
+			names: []string{"Name", "Hours", "Hours_2"},
+			kinds: []int64{parquetByteArray, parquetByteArray, parquetByteArray},
+			want:  [][]any{{"Alice", "Bob", "Carol"}, {"01:30", "", "09:15"}, {"2", "x", ""}},
+		},
+		{
+			name:  "keep original",
+			opts:  types.ConversionOptions{Parquet: true, KeepOriginal: true},
+			names: []string{"Name", "Hours", "Hours (HH:MM)", "Hours_2"},
+			kinds: []int64{parquetByteArray, parquetDouble, parquetByteArray, parquetByteArray},
+			want:  [][]any{{"Alice", "Bob", "Carol"}, {1.5, nil, 9.25}, {"01:30", "", "09:15"}, {"2", "x", ""}},
+		},
+		{
+			name:  "durations",
+			opts:  types.ConversionOptions{Parquet: true, KeepOriginal: true, ParquetDurations: true},
+			names: []string{"Name", "Hours", "Hours (HH:MM)", "Hours_2"},
+			kinds: []int64{parquetByteArray, parquetDouble, parquetInt64, parquetByteArray},
+			want:  [][]any{{"Alice", "Bob", "Carol"}, {1.5, nil, 9.25}, {int64(5400), nil, int64(33300)}, {"2", "x", ""}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outputFile := filepath.Join(t.TempDir(), "output.parquet")
+			result, err := Convert(inputFile, LocalFile(outputFile), []int{1}, tt.opts, nil)
+			if err != nil {
+				t.Fatalf("Convert failed: %v", err)
+			}
+			if result.RowsProcessed != 3 || !reflect.DeepEqual(result.ColumnsFound, []string{"Hours"}) {
+				t.Errorf("Unexpected result %+v", result)
+			}
+
+			names, kinds, columns := readParquet(t, outputFile)
+			if !reflect.DeepEqual(names, tt.names) || !reflect.DeepEqual(kinds, tt.kinds) {
+				t.Errorf("Expected columns %v of types %v, got %v of types %v", tt.names, tt.kinds, names, kinds)
+			}
+			if !reflect.DeepEqual(columns, tt.want) {
+				t.Errorf("Expected values %v, got %v", tt.want, columns)
+			}
+		})
+	}
+
+	if _, err := Convert(inputFile, LocalFile(filepath.Join(tmpDir, "totals.parquet")), []int{1}, types.ConversionOptions{Parquet: true, Totals: &types.Totals{}}, nil); err == nil {
+		t.Error("Expected totals to be refused")
+	}
+}
+
+func TestConvertParquet_XLSX(t *testing.T) {
+	tmpDir := t.TempDir()
+	inputFile := filepath.Join(tmpDir, "input.xlsx")
+	outputFile := filepath.Join(tmpDir, "output.parquet")
+
+	f := excelize.NewFile()
+	f.SetSheetRow("Sheet1", "A1", &[]any{"Weekly report"})
+	f.SetSheetRow("Sheet1", "A2", &[]any{"Name", "Hours"})
+	f.SetSheetRow("Sheet1", "A3", &[]any{"Alice", 7.5})
+	f.SetSheetRow("Sheet1", "A4", &[]any{"Bob", 8.25})
+	if err := f.SaveAs(inputFile); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	result, err := Convert(inputFile, LocalFile(outputFile), []int{1}, types.ConversionOptions{Parquet: true, RecordChanges: true}, nil)
+	if err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+	if len(result.Changes) != 2 || result.Changes[0].Sheet != "Sheet1" || result.Changes[0].Row != 3 {
+		t.Errorf("Expected changes numbered by sheet row, got %+v", result.Changes)
+	}
+
+	names, _, columns := readParquet(t, outputFile)
+	want := [][]any{{"Alice", "Bob"}, {"07:30", "08:15"}}
+	if !reflect.DeepEqual(names, []string{"Name", "Hours"}) || !reflect.DeepEqual(columns, want) {
+		t.Errorf("Expected the title row left out, got %v %v", names, columns)
+	}
+}
+
+// TestConvertParquet_Golden checks Parquet output with parquet-go, a reader
+// written apart from writeParquet, rather than the decoder above. The output
+// also matches a golden file byte for byte, so changes to it show in review.
+func TestConvertParquet_Golden(t *testing.T) {
+	inputFile := filepath.Join(t.TempDir(), "input.csv")
+	input := "Name,Hours,Note\nAlice,7.5,day shift\nBob,,x\nCarol,41.25\nDan,0.25,correction\n"
+	if err := os.WriteFile(inputFile, []byte(input), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	outputFile := filepath.Join(t.TempDir(), "output.parquet")
+	opts := types.ConversionOptions{Parquet: true, KeepOriginal: true, ParquetDurations: true}
+	if _, err := Convert(inputFile, LocalFile(outputFile), []int{1}, opts, nil); err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+	got, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	golden := filepath.Join("testdata", "durations.parquet")
+	if *updateGolden {
+		if err := os.WriteFile(golden, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("Expected the output to match %s; run with -update if the change is meant", golden)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(want), int64(len(want)))
+	if err != nil {
+		t.Fatalf("parquet-go could not open %s: %v", golden, err)
+	}
+	wantSchema := []string{
+		"optional binary Name (STRING);",
+		"optional double Hours;",
+		"optional int64 Hours (HH:MM) (INT(64,true));",
+		"optional binary Note (STRING);",
+	}
+	for _, line := range wantSchema {
+		if !strings.Contains(f.Schema().String(), line) {
+			t.Errorf("Expected the schema to have %q, got\n%s", line, f.Schema())
+		}
+	}
+	if _, ok := f.Lookup(arrowSchemaKey); !ok {
+		t.Errorf("Expected an Arrow schema marking the durations")
+	}
+
+	rows := make([]parquet.Row, f.NumRows())
+	reader := f.RowGroups()[0].Rows()
+	defer reader.Close()
+	if n, err := reader.ReadRows(rows); n != len(rows) || (err != nil && !errors.Is(err, io.EOF)) {
+		t.Fatalf("Expected %d rows, read %d: %v", len(rows), n, err)
+	}
+	var values [][]any
+	for _, row := range rows {
+		var record []any
+		for _, v := range row {
+			switch {
+			case v.IsNull():
+				record = append(record, nil)
+			case v.Kind() == parquet.Double:
+				record = append(record, v.Double())
+			case v.Kind() == parquet.Int64:
+				record = append(record, v.Int64())
+			default:
+				record = append(record, v.String())
+			}
+		}
+		values = append(values, record)
+	}
+	wantValues := [][]any{
+		{"Alice", 7.5, int64(27000), "day shift"},
+		{"Bob", nil, nil, "x"},
+		{"Carol", 41.25, int64(148500), ""},
+		{"Dan", 0.25, int64(900), "correction"},
+	}
+	if !reflect.DeepEqual(values, wantValues) {
+		t.Errorf("Expected rows %v, got %v", wantValues, values)
+	}
+}
@@ -0,0 +1,231 @@
+package converter
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/nconklindev/chronos/internal/types"
+)
+
+// patternPart is one piece of a custom pattern: a field of the duration,
+// or literal text when field is 0.
+type patternPart struct {
+	field byte // 'h', 'm' or 's'
+	width int  // Digits a field is padded to
+	text  string
+}
+
+// parsePattern splits a custom pattern into its parts. h and H are hours, m
+// minutes and s seconds; doubled, they're padded to two digits. Text in
+// single quotes is written as is, and two single quotes write one. Other
+// letters are rejected so typos don't end up in the output.
+func parsePattern(pattern string) ([]patternPart, error) {
+	var parts []patternPart
+	seen := make(map[byte]bool)
+	literal := func(text string) {
+		if n := len(parts); n > 0 && parts[n-1].field == 0 {
+			parts[n-1].text += text
+			return
+		}
+		parts = append(parts, patternPart{text: text})
+	}
+
+	for i := 0; i < len(pattern); {
+		c := pattern[i]
+		switch {
+		case c == '\'':
+			if strings.HasPrefix(pattern[i:], "''") {
+				literal("'")
+				i += 2
+				continue
+			}
+			end := strings.IndexByte(pattern[i+1:], '\'')
+			if end == -1 {
+				return nil, fmt.Errorf("unterminated quote in pattern %q", pattern)
+			}
+			literal(pattern[i+1 : i+1+end])
+			i += end + 2
+		case c == 'h' || c == 'H' || c == 'm' || c == 's':
+			field := c
+			if c == 'H' {
+				field = 'h'
+			}
+			width := 0
+			for i < len(pattern) && (pattern[i] == c || field == 'h' && (pattern[i] == 'h' || pattern[i] == 'H')) {
+				width++
+				i++
+			}
+			if width > 2 {
+				return nil, fmt.Errorf("%q in pattern %q: use one or two letters", strings.Repeat(string(c), width), pattern)
+			}
+			if seen[field] {
+				return nil, fmt.Errorf("pattern %q shows %s twice", pattern, fieldName(field))
+			}
+			seen[field] = true
+			parts = append(parts, patternPart{field: field, width: width})
+		case c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+			return nil, fmt.Errorf("unknown letter %q in pattern %q: quote text, as in H'h' mm'm'", c, pattern)
+		default:
+			literal(string(c))
+			i++
+		}
+	}
+	if len(seen) == 0 {
+		return nil, fmt.Errorf("pattern %q shows no hours, minutes or seconds", pattern)
+	}
+	return parts, nil
+}
+
+// fieldName names a pattern field for errors.
+func fieldName(field byte) string {
+	switch field {
+	case 'h':
+		return "hours"
+	case 'm':
+		return "minutes"
+	}
+	return "seconds"
+}
+
+// CheckPattern reports whether a custom pattern is valid.
+func CheckPattern(pattern string) error {
+	_, err := parsePattern(pattern)
+	return err
+}
+
+// largestField returns the largest field a pattern shows, which holds the
+// whole duration: minutes without hours count every minute.
+func largestField(parts []patternPart) byte {
+	largest := byte('s')
+	for _, part := range parts {
+		switch {
+		case part.field == 'h':
+			return 'h'
+		case part.field == 'm':
+			largest = 'm'
+		}
+	}
+	return largest
+}
+
+// ShowsSeconds reports whether converted values are counted in seconds
+// rather than minutes.
+func ShowsSeconds(s types.ColumnSettings) bool {
+	if s.Pattern != "" {
+		if parts, err := parsePattern(s.Pattern); err == nil {
+			return hasField(parts, 's')
+		}
+	}
+	return s.Format == types.FormatHHMMSS
+}
+
+// formatPattern writes a duration counting total minutes, or seconds when
+// the pattern shows them, in a custom pattern.
+func formatPattern(total int64, parts []patternPart) string {
+	perMinute := int64(1)
+	if hasField(parts, 's') {
+		perMinute = 60
+	}
+
+	largest := largestField(parts)
+	var s strings.Builder
+	for _, part := range parts {
+		var n int64
+		switch part.field {
+		case 0:
+			s.WriteString(part.text)
+			continue
+		case 'h':
+			n = total / (60 * perMinute)
+		case 'm':
+			n = total / perMinute
+			if largest != 'm' {
+				n %= 60
+			}
+		case 's':
+			n = total
+			if largest != 's' {
+				n %= 60
+			}
+		}
+		s.WriteString(fmt.Sprintf("%0*d", part.width, n))
+	}
+	return s.String()
+}
+
+// hasField reports whether a pattern shows field.
+func hasField(parts []patternPart, field byte) bool {
+	for _, part := range parts {
+		if part.field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// patternRegexp matches values written in a pattern. The largest field
+// takes any number of digits, the rest two, or one or two when unpadded.
+func patternRegexp(parts []patternPart) *regexp.Regexp {
+	largest := largestField(parts)
+	var expr strings.Builder
+	expr.WriteString("^")
+	for _, part := range parts {
+		switch {
+		case part.field == 0:
+			expr.WriteString(regexp.QuoteMeta(part.text))
+		case part.field == largest:
+			expr.WriteString(`(\d+)`)
+		case part.width == 2:
+			expr.WriteString(`(\d{2})`)
+		default:
+			expr.WriteString(`(\d{1,2})`)
+		}
+	}
+	expr.WriteString("$")
+	return regexp.MustCompile(expr.String())
+}
+
+// patternRegexps caches the regular expressions of the patterns read
+// back, since a file's changes are all read with the same few.
+var patternRegexps sync.Map // pattern → *regexp.Regexp
+
+// parsePatternValue reads a value written in a custom pattern back into hours.
+func parsePatternValue(value, pattern string) (float64, bool) {
+	parts, err := parsePattern(pattern)
+	if err != nil {
+		return 0, false
+	}
+	re, ok := patternRegexps.Load(pattern)
+	if !ok {
+		re, _ = patternRegexps.LoadOrStore(pattern, patternRegexp(parts))
+	}
+	match := re.(*regexp.Regexp).FindStringSubmatch(value)
+	if match == nil {
+		return 0, false
+	}
+
+	hours := 0.0
+	group := 1
+	for _, part := range parts {
+		if part.field == 0 {
+			continue
+		}
+		n, err := strconv.Atoi(match[group])
+		if err != nil {
+			return 0, false
+		}
+		group++
+		switch part.field {
+		case 'h':
+			hours += float64(n)
+		case 'm':
+			hours += float64(n) / 60
+		case 's':
+			hours += float64(n) / 3600
+		}
+	}
+	return hours, true
+}
@@ -0,0 +1,127 @@
+package converter
+
+import "io"
+
+// Phase is the stage of a conversion a progress report comes from.
+type Phase string
+
+const (
+	PhaseRead    Phase = "read"    // Reading the input file
+	PhaseConvert Phase = "convert" // Converting its rows
+	PhaseWrite   Phase = "write"   // Writing the output
+)
+
+// Progress is one report of how far a conversion has got.
+type Progress struct {
+	Phase Phase
+	// Done counts the phase's work so far, of Total: bytes when reading
+	// and writing, and rows when converting. The output's size isn't known
+	// until it's written, so writing estimates Total from the input's size
+	// and Done can pass it.
+	Done, Total int
+}
+
+// phaseShares are how much of a whole conversion each phase makes up, by
+// where it starts and how long it is, for a single progress bar.
+var phaseShares = map[Phase][2]float64{
+	PhaseRead:    {0, 0.2},
+	PhaseConvert: {0.2, 0.6},
+	PhaseWrite:   {0.8, 0.2},
+}
+
+// Fraction is how much of the phase is done, from 0 to 1.
+func (p Progress) Fraction() float64 {
+	if p.Total <= 0 {
+		return 0
+	}
+	return min(float64(p.Done)/float64(p.Total), 1)
+}
+
+// Overall is how much of the whole conversion is done, from 0 to 1,
+// counting converting as most of the work.
+func (p Progress) Overall() float64 {
+	share := phaseShares[p.Phase]
+	return share[0] + share[1]*p.Fraction()
+}
+
+// ProgressReporter receives progress reports from a conversion. Report is
+// called on the converting goroutine, so it should return quickly.
+type ProgressReporter interface {
+	Report(p Progress)
+}
+
+// ProgressFunc reports progress to a function.
+type ProgressFunc func(p Progress)
+
+func (f ProgressFunc) Report(p Progress) { f(p) }
+
+// ProgressChan reports progress to a channel. Reports are dropped while the
+// channel is full, so a slow reader never holds up the conversion.
+type ProgressChan chan<- Progress
+
+func (c ProgressChan) Report(p Progress) {
+	select {
+	case c <- p:
+	default:
+	}
+}
+
+// report sends a progress report to r, which may be nil.
+func report(r ProgressReporter, phase Phase, done, total int) {
+	if r != nil {
+		r.Report(Progress{Phase: phase, Done: done, Total: total})
+	}
+}
+
+// progressReader reports the bytes read through it as progress of a phase.
+type progressReader struct {
+	r        io.Reader
+	progress ProgressReporter
+	phase    Phase
+	done     int
+	total    int
+}
+
+func newProgressReader(r io.Reader, total int64, phase Phase, progress ProgressReporter) *progressReader {
+	report(progress, phase, 0, int(total))
+	return &progressReader{r: r, progress: progress, phase: phase, total: int(total)}
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	if n > 0 {
+		p.done += n
+		report(p.progress, p.phase, p.done, p.total)
+	}
+	return n, err
+}
+
+// progressWriter reports the bytes written through it as progress of a
+// phase, against an estimated total.
+type progressWriter struct {
+	w        io.Writer
+	progress ProgressReporter
+	phase    Phase
+	done     int
+	total    int
+}
+
+func newProgressWriter(w io.Writer, estimate int64, phase Phase, progress ProgressReporter) *progressWriter {
+	report(progress, phase, 0, int(estimate))
+	return &progressWriter{w: w, progress: progress, phase: phase, total: int(estimate)}
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	if n > 0 {
+		p.done += n
+		report(p.progress, p.phase, p.done, p.total)
+	}
+	return n, err
+}
+
+// finish reports the phase done, however far off the estimate was.
+func (p *progressWriter) finish() {
+	done := max(p.done, 1)
+	report(p.progress, p.phase, done, done)
+}
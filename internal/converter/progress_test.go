@@ -0,0 +1,113 @@
+package converter
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/nconklindev/chronos/internal/types"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestConvert_Progress(t *testing.T) {
+	tmpDir := t.TempDir()
+	csvFile := filepath.Join(tmpDir, "input.csv")
+	writeTestCSV(t, csvFile, [][]string{
+		{"Name", "Hours", "Break"},
+		{"Alice", "7.5", "0.5"},
+		{"Bob", "8", "0.25"},
+		{"Carol", "6.25", "0"},
+	})
+
+	xlsxFile := filepath.Join(tmpDir, "input.xlsx")
+	f := excelize.NewFile()
+	f.SetSheetRow("Sheet1", "A1", &[]any{"Name", "Hours", "Break"})
+	f.SetSheetRow("Sheet1", "A2", &[]any{"Alice", 7.5, 0.5})
+	f.SetSheetRow("Sheet1", "A3", &[]any{"Bob", 8, 0.25})
+	f.SetSheetRow("Sheet1", "A4", &[]any{"Carol", 6.25, 0})
+	if err := f.SaveAs(xlsxFile); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	tests := []struct {
+		name  string
+		input string
+		opts  types.ConversionOptions
+	}{
+		{"csv", csvFile, types.ConversionOptions{}},
+		{"csv kept", csvFile, types.ConversionOptions{KeepOriginal: true}},
+		{"xlsx", xlsxFile, types.ConversionOptions{}},
+		{"xlsx kept", xlsxFile, types.ConversionOptions{KeepOriginal: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var reports []Progress
+			record := ProgressFunc(func(p Progress) { reports = append(reports, p) })
+			output := filepath.Join(t.TempDir(), "output"+filepath.Ext(tt.input))
+			if _, err := Convert(tt.input, LocalFile(output), []int{1, 2}, tt.opts, record); err != nil {
+				t.Fatalf("Convert failed: %v", err)
+			}
+
+			if len(reports) == 0 || reports[0].Phase != PhaseRead {
+				t.Fatalf("Expected reports starting with reading, got %+v", reports)
+			}
+			if last := reports[len(reports)-1]; last.Phase != PhaseWrite || last.Overall() != 1 {
+				t.Errorf("Expected reports to end with writing done, got %+v", last)
+			}
+			read, converted := false, false
+			for i, p := range reports {
+				if i > 0 && p.Overall() < reports[i-1].Overall() {
+					t.Errorf("Progress went back from %+v to %+v", reports[i-1], p)
+				}
+				if p.Phase == PhaseRead && p.Total > 0 && p.Done == p.Total {
+					read = true
+				}
+				if p.Phase == PhaseConvert && p.Total > 0 && p.Done == p.Total {
+					converted = true
+				}
+			}
+			if !read {
+				t.Errorf("Expected reading to report every byte, got %+v", reports)
+			}
+			if !converted {
+				t.Errorf("Expected converting to report every row, got %+v", reports)
+			}
+		})
+	}
+}
+
+func TestProgressChan(t *testing.T) {
+	ch := make(chan Progress, 1)
+	reporter := ProgressChan(ch)
+	reporter.Report(Progress{Phase: PhaseRead, Done: 1, Total: 1})
+	// A full channel drops reports rather than blocking
+	reporter.Report(Progress{Phase: PhaseWrite})
+
+	if p := <-ch; p.Phase != PhaseRead || p.Overall() != 0.2 {
+		t.Errorf("Expected the first report, got %+v", p)
+	}
+}
+
+func TestProgressWriter(t *testing.T) {
+	var reports []Progress
+	var buf bytes.Buffer
+	w := newProgressWriter(&buf, 4, PhaseWrite, ProgressFunc(func(p Progress) { reports = append(reports, p) }))
+	w.Write([]byte("abc"))
+	w.Write([]byte("defgh"))
+	w.finish()
+
+	// Writing more than the estimate holds at the end of the phase until it's finished
+	expected := []float64{0, 0.75, 1, 1}
+	if len(reports) != len(expected) {
+		t.Fatalf("Expected %d reports, got %+v", len(expected), reports)
+	}
+	for i, p := range reports {
+		if p.Phase != PhaseWrite || p.Fraction() != expected[i] {
+			t.Errorf("Report %d: expected %v of writing, got %+v", i, expected[i], p)
+		}
+	}
+	if buf.String() != "abcdefgh" {
+		t.Errorf("Expected the bytes to be written through, got %q", buf.String())
+	}
+}
@@ -0,0 +1,165 @@
+package converter
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/nconklindev/chronos/internal/types"
+)
+
+// quoting decides which fields of CSV output are quoted beyond those that
+// must be.
+type quoting struct {
+	all   bool
+	cells [][]bool // Output cells quoted to preserve the input's quoting
+}
+
+// minimal reports whether only the fields that must be quoted are.
+func (q quoting) minimal() bool {
+	return !q.all && q.cells == nil
+}
+
+func (q quoting) quoted(row, col int) bool {
+	if q.all {
+		return true
+	}
+	return row < len(q.cells) && col < len(q.cells[row]) && q.cells[row][col]
+}
+
+// quotedCells finds which fields of CSV text were quoted, record by record
+// as csv.Reader reads them, skipping blank lines.
+func quotedCells(text []byte) [][]bool {
+	var records [][]bool
+	var record []bool
+	atFieldStart, inQuotes, lineEmpty := true, false, true
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case inQuotes:
+			if c == '"' {
+				if i+1 < len(text) && text[i+1] == '"' {
+					i++
+				} else {
+					inQuotes = false
+				}
+			}
+		case atFieldStart && c == '"':
+			record = append(record, true)
+			inQuotes, atFieldStart, lineEmpty = true, false, false
+		case c == ',':
+			if atFieldStart {
+				record = append(record, false)
+			}
+			atFieldStart, lineEmpty = true, false
+		case c == '\n' || (c == '\r' && i+1 < len(text) && text[i+1] == '\n'):
+			if c == '\r' {
+				i++
+			}
+			if !lineEmpty {
+				if atFieldStart {
+					record = append(record, false)
+				}
+				records = append(records, record)
+			}
+			record, atFieldStart, lineEmpty = nil, true, true
+		default:
+			if atFieldStart {
+				record = append(record, false)
+			}
+			atFieldStart, lineEmpty = false, false
+		}
+	}
+	if !lineEmpty {
+		if atFieldStart {
+			record = append(record, false)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// preservedQuoting quotes the cells of output that were quoted in the
+// input, finding each cell's source by row and by header, so reordered
+// and kept columns keep their quoting. Added columns, such as converted
+// copies, are only quoted where they must be.
+func preservedQuoting(header []string, source [][]bool, output [][]string) quoting {
+	columns := make(map[string]int, len(header))
+	for i, h := range header {
+		if _, ok := columns[h]; !ok {
+			columns[h] = i
+		}
+	}
+
+	q := quoting{cells: make([][]bool, len(output))}
+	if len(output) == 0 {
+		return q
+	}
+	for row := range output {
+		if row >= len(source) {
+			break
+		}
+		q.cells[row] = make([]bool, len(output[row]))
+		for col := range output[row] {
+			from, ok := col, col < len(output[0])
+			if ok {
+				from, ok = columns[output[0][col]]
+			}
+			q.cells[row][col] = ok && from < len(source[row]) && source[row][from]
+		}
+	}
+	return q
+}
+
+// quotingFor returns how CSV output is quoted under the options: with the
+// fields quoted in the input for QuotingPreserve, given the input's header
+// and quoted cells.
+func quotingFor(opts types.ConversionOptions, header []string, source [][]bool, output [][]string) quoting {
+	switch opts.Quoting {
+	case types.QuotingAll:
+		return quoting{all: true}
+	case types.QuotingPreserve:
+		return preservedQuoting(header, source, output)
+	}
+	return quoting{}
+}
+
+// writeQuoted writes records as csv.Writer does, also quoting the fields q
+// quotes.
+func writeQuoted(w io.Writer, records [][]string, crlf bool, q quoting) error {
+	out := bufio.NewWriter(w)
+	lineEnd := "\n"
+	if crlf {
+		lineEnd = "\r\n"
+	}
+	for row, record := range records {
+		for col, field := range record {
+			if col > 0 {
+				out.WriteByte(',')
+			}
+			if !q.quoted(row, col) && !fieldNeedsQuotes(field) {
+				out.WriteString(field)
+				continue
+			}
+			out.WriteByte('"')
+			out.WriteString(strings.ReplaceAll(field, `"`, `""`))
+			out.WriteByte('"')
+		}
+		out.WriteString(lineEnd)
+	}
+	return out.Flush()
+}
+
+// fieldNeedsQuotes reports whether csv.Writer would quote field.
+func fieldNeedsQuotes(field string) bool {
+	if field == "" {
+		return false
+	}
+	if field == `\.` || strings.ContainsAny(field, ",\"\r\n") {
+		return true
+	}
+	r, _ := utf8.DecodeRuneInString(field)
+	return unicode.IsSpace(r)
+}
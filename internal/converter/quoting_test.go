@@ -0,0 +1,61 @@
+package converter
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/nconklindev/chronos/internal/types"
+)
+
+func TestQuotedCells(t *testing.T) {
+	text := "\"Name\",Hours,\"Note\"\r\n\r\n\"Alice\",1.5,\"says \"\"hi\"\"\nthen leaves\"\nBob,,\"\""
+	expected := [][]bool{
+		{true, false, true},
+		{true, false, true},
+		{false, false, true},
+	}
+	if got := quotedCells([]byte(text)); !reflect.DeepEqual(got, expected) {
+		t.Errorf("quotedCells() = %v; want %v", got, expected)
+	}
+}
+
+func TestConvertCSV_Quoting(t *testing.T) {
+	input := "\"ID\",\"Name\",Hours\n\"00123\",\"Alice\",1.5\n\"00124\",Bob,2.25\n"
+	tests := []struct {
+		name     string
+		quoting  types.Quoting
+		opts     types.ConversionOptions
+		expected string
+	}{
+		{"Minimal", types.QuotingMinimal, types.ConversionOptions{}, "ID,Name,Hours\n00123,Alice,01:30\n00124,Bob,02:15\n"},
+		{"All", types.QuotingAll, types.ConversionOptions{}, "\"ID\",\"Name\",\"Hours\"\n\"00123\",\"Alice\",\"01:30\"\n\"00124\",\"Bob\",\"02:15\"\n"},
+		{"Preserve", types.QuotingPreserve, types.ConversionOptions{}, "\"ID\",\"Name\",Hours\n\"00123\",\"Alice\",01:30\n\"00124\",Bob,02:15\n"},
+		{"Preserve reordered", types.QuotingPreserve, types.ConversionOptions{KeepOriginal: true, Order: []int{2, 0}}, "Hours,Hours (HH:MM),\"ID\",\"Name\"\n1.5,01:30,\"00123\",\"Alice\"\n2.25,02:15,\"00124\",Bob\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			in := filepath.Join(dir, "input.csv")
+			out := filepath.Join(dir, "output.csv")
+			if err := os.WriteFile(in, []byte(input), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			opts := tt.opts
+			opts.Quoting = tt.quoting
+			if _, err := ConvertCSV(in, LocalFile(out), []int{2}, opts, nil); err != nil {
+				t.Fatalf("ConvertCSV failed: %v", err)
+			}
+			got, err := os.ReadFile(out)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.expected {
+				t.Errorf("Output = %q; want %q", got, tt.expected)
+			}
+		})
+	}
+}
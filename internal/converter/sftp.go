@@ -0,0 +1,228 @@
+package converter
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"os/user"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// SFTPUpload writes output to a file on an SFTP server, named by a URL
+// such as sftp://payroll@files.example.com/exports/hours.csv. As with curl,
+// the path is absolute unless it starts with /~/, for the login folder.
+//
+// The server's key has to be in the known hosts file. The user logs in
+// with the password in the URL, if there is one, then with the keys of the
+// SSH agent and the default key files that have no passphrase.
+type SFTPUpload struct {
+	URL        string
+	KnownHosts string        // Defaults to ~/.ssh/known_hosts
+	Timeout    time.Duration // Defaults to uploadTimeout
+}
+
+// IsSFTPURL reports whether s is an sftp URL rather than a path.
+func IsSFTPURL(s string) bool {
+	u, err := url.Parse(strings.TrimSpace(s))
+	return err == nil && u.Scheme == "sftp" && u.Host != ""
+}
+
+func (u SFTPUpload) Location() string {
+	parsed, err := url.Parse(u.URL)
+	if err != nil {
+		return u.URL
+	}
+	// Passwords don't belong in results and logs
+	return parsed.Redacted()
+}
+
+// Create logs in and opens a temporary file next to the destination,
+// which is renamed over it when closed so a failed upload never leaves a
+// half written file behind.
+func (u SFTPUpload) Create() (io.WriteCloser, error) {
+	target, err := url.Parse(strings.TrimSpace(u.URL))
+	if err != nil || target.Scheme != "sftp" || target.Host == "" {
+		return nil, fmt.Errorf("%s is not an sftp:// URL", u.Location())
+	}
+	remote := target.Path
+	if rest, ok := strings.CutPrefix(remote, "/~/"); ok {
+		remote = rest
+	}
+	if remote == "" || strings.HasSuffix(remote, "/") {
+		return nil, fmt.Errorf("%s doesn't name a file", u.Location())
+	}
+
+	config, agentConn, err := u.clientConfig(target)
+	if err != nil {
+		return nil, err
+	}
+	if agentConn != nil {
+		// The agent is only needed to log in
+		defer agentConn.Close()
+	}
+	timeout := u.Timeout
+	if timeout == 0 {
+		timeout = uploadTimeout
+	}
+	addr := target.Host
+	if target.Port() == "" {
+		addr = net.JoinHostPort(target.Hostname(), "22")
+	}
+
+	conn, err := net.DialTimeout("tcp", addr, timeout)
+	if err != nil {
+		return nil, err
+	}
+	// The deadline bounds the whole upload, not just logging in
+	conn.SetDeadline(time.Now().Add(timeout))
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, addr, config)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	client, err := sftp.NewClient(ssh.NewClient(sshConn, chans, reqs), sftp.UseConcurrentWrites(true))
+	if err != nil {
+		sshConn.Close()
+		return nil, err
+	}
+
+	tmp := path.Join(path.Dir(remote), "."+path.Base(remote)+"-upload")
+	f, err := client.Create(tmp)
+	if err != nil {
+		client.Close()
+		sshConn.Close()
+		return nil, fmt.Errorf("could not create %s on %s: %w", tmp, target.Host, err)
+	}
+	return &sftpWriter{Writer: bufio.NewWriterSize(f, 256<<10), file: f, client: client, conn: sshConn, tmp: tmp, path: remote}, nil
+}
+
+// clientConfig logs in as the URL's user, or the current one, checking
+// the server's key against the known hosts. It returns the connection to
+// the SSH agent, if there is one, to close once logged in.
+func (u SFTPUpload) clientConfig(target *url.URL) (*ssh.ClientConfig, net.Conn, error) {
+	knownHosts := u.KnownHosts
+	if knownHosts == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, nil, err
+		}
+		knownHosts = filepath.Join(home, ".ssh", "known_hosts")
+	}
+	hostKey, err := knownhosts.New(knownHosts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not read known hosts: %w", err)
+	}
+
+	name := target.User.Username()
+	if name == "" {
+		current, err := user.Current()
+		if err != nil {
+			return nil, nil, err
+		}
+		name = current.Username
+	}
+
+	var auth []ssh.AuthMethod
+	if password, ok := target.User.Password(); ok {
+		auth = append(auth, ssh.Password(password))
+	}
+	var agentConn net.Conn
+	if sock := os.Getenv("SSH_AUTH_SOCK"); sock != "" {
+		if agentConn, err = net.Dial("unix", sock); err == nil {
+			auth = append(auth, ssh.PublicKeysCallback(agent.NewClient(agentConn).Signers))
+		}
+	}
+	if signers := defaultKeys(); len(signers) > 0 {
+		auth = append(auth, ssh.PublicKeys(signers...))
+	}
+	if len(auth) == 0 {
+		return nil, nil, errors.New("no password, SSH agent or key file to log in with")
+	}
+	return &ssh.ClientConfig{User: name, Auth: auth, HostKeyCallback: hostKey}, agentConn, nil
+}
+
+// defaultKeys reads the default key files that have no passphrase.
+func defaultKeys() []ssh.Signer {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+	var signers []ssh.Signer
+	for _, name := range []string{"id_ed25519", "id_ecdsa", "id_rsa"} {
+		pem, err := os.ReadFile(filepath.Join(home, ".ssh", name))
+		if err != nil {
+			continue
+		}
+		if signer, err := ssh.ParsePrivateKey(pem); err == nil {
+			signers = append(signers, signer)
+		}
+	}
+	return signers
+}
+
+type sftpWriter struct {
+	*bufio.Writer
+	file   *sftp.File
+	client *sftp.Client
+	conn   ssh.Conn
+	tmp    string
+	path   string
+	failed bool
+	closed bool
+}
+
+func (w *sftpWriter) Write(b []byte) (int, error) {
+	n, err := w.Writer.Write(b)
+	if err != nil {
+		w.failed = true
+	}
+	return n, err
+}
+
+func (w *sftpWriter) discard() {
+	w.failed = true
+}
+
+func (w *sftpWriter) Close() error {
+	// Converters close explicitly to check the error and again via defer
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	defer w.conn.Close()
+	defer w.client.Close()
+
+	err := w.Flush()
+	if closeErr := w.file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && w.failed {
+		err = fmt.Errorf("writing %s failed", w.path)
+	}
+	if err != nil {
+		w.client.Remove(w.tmp)
+		return err
+	}
+
+	// Servers without the POSIX extension can't rename over a file
+	if err := w.client.PosixRename(w.tmp, w.path); err != nil {
+		w.client.Remove(w.path)
+		if err := w.client.Rename(w.tmp, w.path); err != nil {
+			w.client.Remove(w.tmp)
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package converter
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nconklindev/chronos/internal/types"
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// startSFTPServer serves the file system over SFTP to chronos:secret on a
+// local port. It returns the port's address and a known hosts file with
+// the server's key.
+func startSFTPServer(t *testing.T) (string, string) {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := &ssh.ServerConfig{
+		PasswordCallback: func(c ssh.ConnMetadata, password []byte) (*ssh.Permissions, error) {
+			if c.User() != "chronos" || string(password) != "secret" {
+				return nil, os.ErrPermission
+			}
+			return nil, nil
+		},
+	}
+	config.AddHostKey(signer)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveSFTP(conn, config)
+		}
+	}()
+
+	addr := listener.Addr().String()
+	knownHosts := filepath.Join(t.TempDir(), "known_hosts")
+	line := knownhosts.Line([]string{knownhosts.Normalize(addr)}, signer.PublicKey())
+	if err := os.WriteFile(knownHosts, []byte(line+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return addr, knownHosts
+}
+
+// serveSFTP answers requests for the sftp subsystem on one connection.
+func serveSFTP(conn net.Conn, config *ssh.ServerConfig) {
+	sshConn, chans, reqs, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	defer sshConn.Close()
+	go ssh.DiscardRequests(reqs)
+	for newChannel := range chans {
+		if newChannel.ChannelType() != "session" {
+			newChannel.Reject(ssh.UnknownChannelType, "only sessions")
+			continue
+		}
+		channel, requests, err := newChannel.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			for req := range requests {
+				// The payload is the subsystem's name, prefixed with its length
+				ok := req.Type == "subsystem" && string(req.Payload[4:]) == "sftp"
+				req.Reply(ok, nil)
+				if ok {
+					server, err := sftp.NewServer(channel)
+					if err == nil {
+						server.Serve()
+					}
+					channel.Close()
+				}
+			}
+		}()
+	}
+}
+
+func TestSFTPUpload(t *testing.T) {
+	addr, knownHosts := startSFTPServer(t)
+	dir := t.TempDir()
+	inputFile := filepath.Join(dir, "input.csv")
+	writeTestCSV(t, inputFile, [][]string{{"Name", "Hours"}, {"Alice", "1.5"}})
+	output := filepath.Join(dir, "uploaded.csv")
+	if err := os.WriteFile(output, []byte("old"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	sink := SFTPUpload{URL: "sftp://chronos:secret@" + addr + filepath.ToSlash(output), KnownHosts: knownHosts}
+	result, err := ConvertCSV(inputFile, sink, []int{1}, types.ConversionOptions{}, nil)
+	if err != nil {
+		t.Fatalf("ConvertCSV failed: %v", err)
+	}
+
+	got, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "Name,Hours\nAlice,01:30\n" {
+		t.Errorf("Unexpected upload: %q", got)
+	}
+	if strings.Contains(result.OutputFile, "secret") {
+		t.Errorf("Expected the password left out of %s", result.OutputFile)
+	}
+	// The temporary file is renamed away
+	if entries, _ := os.ReadDir(dir); len(entries) != 2 {
+		t.Errorf("Expected only the input and the upload, got %v", entries)
+	}
+}
+
+func TestSFTPUpload_UnknownHost(t *testing.T) {
+	addr, _ := startSFTPServer(t)
+	dir := t.TempDir()
+	inputFile := filepath.Join(dir, "input.csv")
+	writeTestCSV(t, inputFile, [][]string{{"Name", "Hours"}, {"Alice", "1.5"}})
+	knownHosts := filepath.Join(dir, "known_hosts")
+	if err := os.WriteFile(knownHosts, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	output := filepath.Join(dir, "uploaded.csv")
+	sink := SFTPUpload{URL: "sftp://chronos:secret@" + addr + filepath.ToSlash(output), KnownHosts: knownHosts}
+	if _, err := ConvertCSV(inputFile, sink, []int{1}, types.ConversionOptions{}, nil); err == nil {
+		t.Error("Expected an error for a server that isn't a known host")
+	}
+	if _, err := os.Stat(output); err == nil {
+		t.Error("Expected nothing uploaded to an unknown host")
+	}
+}
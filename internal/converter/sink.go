@@ -0,0 +1,243 @@
+package converter
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+	"unicode/utf8"
+
+	"github.com/atotto/clipboard"
+)
+
+// OutputSink is a destination for converted output. Converters write the whole
+// file to the writer returned by Create and report Location in their results,
+// so every destination works with every input format.
+type OutputSink interface {
+	// Create opens the destination for writing. Closing the writer commits the output.
+	Create() (io.WriteCloser, error)
+	// Location describes where the output ends up, for display in results.
+	Location() string
+}
+
+// discard closes a writer from Create without committing what was written
+// to it, for output that failed part way through. A file on disk is
+// removed; output already sent, as to stdout, can't be taken back.
+func discard(w io.WriteCloser) {
+	if d, ok := w.(interface{ discard() }); ok {
+		d.discard()
+	}
+	w.Close()
+	if f, ok := w.(*os.File); ok {
+		os.Remove(f.Name())
+	}
+}
+
+// LocalFile writes output to a file on disk.
+type LocalFile string
+
+func (p LocalFile) Create() (io.WriteCloser, error) {
+	return os.Create(string(p))
+}
+
+func (p LocalFile) Location() string {
+	return string(p)
+}
+
+// ReplaceFile writes output to a temporary file next to a file on disk and
+// renames it over the file when closed, so the file is never left half
+// written. It's for converting a file in place.
+type ReplaceFile string
+
+func (p ReplaceFile) Create() (io.WriteCloser, error) {
+	tmp, err := os.CreateTemp(filepath.Dir(string(p)), "."+filepath.Base(string(p))+"-*")
+	if err != nil {
+		return nil, err
+	}
+	return &replacingFile{File: tmp, path: string(p)}, nil
+}
+
+func (p ReplaceFile) Location() string {
+	return string(p)
+}
+
+type replacingFile struct {
+	*os.File
+	path   string
+	failed bool
+	closed bool
+}
+
+func (r *replacingFile) Write(b []byte) (int, error) {
+	n, err := r.File.Write(b)
+	if err != nil {
+		r.failed = true
+	}
+	return n, err
+}
+
+func (r *replacingFile) discard() {
+	r.failed = true
+}
+
+func (r *replacingFile) Close() error {
+	// Converters close explicitly to check the error and again via defer
+	if r.closed {
+		return nil
+	}
+	r.closed = true
+
+	err := r.File.Close()
+	if err == nil && r.failed {
+		err = fmt.Errorf("writing %s failed", r.path)
+	}
+	if err != nil {
+		os.Remove(r.File.Name())
+		return err
+	}
+
+	// Keep the permissions of the file being replaced
+	if info, err := os.Stat(r.path); err == nil {
+		os.Chmod(r.File.Name(), info.Mode().Perm())
+	}
+	return os.Rename(r.File.Name(), r.path)
+}
+
+// WriterSink writes output to an existing writer such as stdout or an HTTP
+// response. The writer is not closed.
+type WriterSink struct {
+	W     io.Writer
+	Label string
+}
+
+// Stdout writes output to standard output.
+var Stdout = WriterSink{W: os.Stdout, Label: "stdout"}
+
+func (s WriterSink) Create() (io.WriteCloser, error) {
+	return nopWriteCloser{s.W}, nil
+}
+
+func (s WriterSink) Location() string {
+	return s.Label
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
+
+// HTTPUpload buffers output and PUTs it to a URL when closed. It works with
+// S3 presigned upload URLs and any server that accepts a plain PUT.
+type HTTPUpload struct {
+	URL         string
+	ContentType string
+	Client      *http.Client // Defaults to a client that gives up after uploadTimeout
+}
+
+// uploadTimeout is how long an upload may take. Outputs can be far larger
+// than the files downloaded, but a server that stops answering still
+// doesn't hang the conversion.
+const uploadTimeout = 5 * time.Minute
+
+func (u HTTPUpload) Create() (io.WriteCloser, error) {
+	return &uploadWriter{upload: u}, nil
+}
+
+func (u HTTPUpload) Location() string {
+	return u.URL
+}
+
+type uploadWriter struct {
+	bytes.Buffer
+	upload HTTPUpload
+	closed bool
+}
+
+func (w *uploadWriter) discard() {
+	w.closed = true
+}
+
+func (w *uploadWriter) Close() error {
+	// Converters close explicitly to check the error and again via defer
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+
+	req, err := http.NewRequest(http.MethodPut, w.upload.URL, bytes.NewReader(w.Bytes()))
+	if err != nil {
+		return err
+	}
+	if w.upload.ContentType != "" {
+		req.Header.Set("Content-Type", w.upload.ContentType)
+	}
+
+	client := w.upload.Client
+	if client == nil {
+		client = &http.Client{Timeout: uploadTimeout}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("upload failed: %s", resp.Status)
+	}
+	return nil
+}
+
+// Clipboard buffers output and puts it on the clipboard when closed, ready
+// to paste into a spreadsheet. Only text output such as CSV can be pasted,
+// so binary output such as XLSX is refused.
+type Clipboard struct {
+	Write func(text string) error // Defaults to writing the system clipboard
+}
+
+func (c Clipboard) Create() (io.WriteCloser, error) {
+	return &clipboardWriter{clipboard: c}, nil
+}
+
+func (c Clipboard) Location() string {
+	return "clipboard"
+}
+
+type clipboardWriter struct {
+	bytes.Buffer
+	clipboard Clipboard
+	closed    bool
+}
+
+func (w *clipboardWriter) discard() {
+	w.closed = true
+}
+
+func (w *clipboardWriter) Close() error {
+	// Converters close explicitly to check the error and again via defer
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+
+	if !utf8.Valid(w.Bytes()) || bytes.IndexByte(w.Bytes(), 0) >= 0 {
+		return errors.New("the output isn't text, so it can't go on the clipboard")
+	}
+
+	write := w.clipboard.Write
+	if write == nil {
+		write = clipboard.WriteAll
+	}
+	if err := write(w.String()); err != nil {
+		return fmt.Errorf("could not write the clipboard: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package converter
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nconklindev/chronos/internal/types"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestConvertCSV_WriterSink(t *testing.T) {
+	inputFile := filepath.Join(t.TempDir(), "input.csv")
+	writeTestCSV(t, inputFile, [][]string{{"Name", "Hours"}, {"Alice", "1.5"}})
+
+	var buf bytes.Buffer
+	result, err := ConvertCSV(inputFile, WriterSink{W: &buf, Label: "buffer"}, []int{1}, types.ConversionOptions{}, nil)
+	if err != nil {
+		t.Fatalf("ConvertCSV failed: %v", err)
+	}
+
+	if buf.String() != "Name,Hours\nAlice,01:30\n" {
+		t.Errorf("Unexpected output: %q", buf.String())
+	}
+	if result.OutputFile != "buffer" {
+		t.Errorf("Expected output location 'buffer', got %s", result.OutputFile)
+	}
+}
+
+func TestHTTPUpload(t *testing.T) {
+	var method string
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	inputFile := filepath.Join(t.TempDir(), "input.csv")
+	writeTestCSV(t, inputFile, [][]string{{"Name", "Hours"}, {"Alice", "1.5"}})
+
+	if _, err := ConvertCSV(inputFile, HTTPUpload{URL: server.URL}, []int{1}, types.ConversionOptions{}, nil); err != nil {
+		t.Fatalf("ConvertCSV failed: %v", err)
+	}
+
+	if method != http.MethodPut {
+		t.Errorf("Expected PUT, got %s", method)
+	}
+	if string(body) != "Name,Hours\nAlice,01:30\n" {
+		t.Errorf("Unexpected upload body: %q", body)
+	}
+}
+
+func TestHTTPUpload_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	inputFile := filepath.Join(t.TempDir(), "input.csv")
+	writeTestCSV(t, inputFile, [][]string{{"Name", "Hours"}, {"Alice", "1.5"}})
+
+	if _, err := ConvertCSV(inputFile, HTTPUpload{URL: server.URL}, []int{1}, types.ConversionOptions{}, nil); err == nil {
+		t.Error("Expected an error for a rejected upload")
+	}
+}
+
+func TestReplaceFile(t *testing.T) {
+	dir := t.TempDir()
+	inputFile := filepath.Join(dir, "input.csv")
+	writeTestCSV(t, inputFile, [][]string{{"Name", "Hours"}, {"Alice", "1.5"}})
+
+	if _, err := ConvertCSV(inputFile, ReplaceFile(inputFile), []int{1}, types.ConversionOptions{}, nil); err != nil {
+		t.Fatalf("ConvertCSV failed: %v", err)
+	}
+
+	got, err := os.ReadFile(inputFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "Name,Hours\nAlice,01:30\n" {
+		t.Errorf("Unexpected output: %q", got)
+	}
+	// The temporary file is renamed away
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("Expected only the replaced file, got %v", entries)
+	}
+}
+
+func TestClipboard(t *testing.T) {
+	dir := t.TempDir()
+	inputFile := filepath.Join(dir, "input.csv")
+	writeTestCSV(t, inputFile, [][]string{{"Name", "Hours"}, {"Alice", "1.5"}})
+
+	var pasted string
+	sink := Clipboard{Write: func(text string) error {
+		pasted = text
+		return nil
+	}}
+	result, err := ConvertCSV(inputFile, sink, []int{1}, types.ConversionOptions{}, nil)
+	if err != nil {
+		t.Fatalf("ConvertCSV failed: %v", err)
+	}
+	if pasted != "Name,Hours\nAlice,01:30\n" || result.OutputFile != "clipboard" {
+		t.Errorf("Unexpected clipboard %q at %s", pasted, result.OutputFile)
+	}
+
+	// Workbooks aren't text, so they're refused rather than pasted as garbage
+	workbook := filepath.Join(dir, "input.xlsx")
+	f := excelize.NewFile()
+	f.SetSheetRow("Sheet1", "A1", &[]any{"Name", "Hours"})
+	f.SetSheetRow("Sheet1", "A2", &[]any{"Alice", 1.5})
+	if err := f.SaveAs(workbook); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	pasted = ""
+	if _, err := ConvertXLSX(workbook, sink, []int{1}, types.ConversionOptions{}, nil); err == nil || pasted != "" {
+		t.Errorf("Expected a workbook to be refused, got %v with %q", err, pasted)
+	}
+}
@@ -0,0 +1,114 @@
+package converter
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// InputSource is where a file to convert comes from. Converters and readers
+// work on local paths, so remote sources are fetched to disk first.
+type InputSource interface {
+	// Fetch makes the file available locally and returns its path.
+	Fetch() (string, error)
+	// Location describes where the input came from, for display.
+	Location() string
+}
+
+// LocalPath is a file already on disk.
+type LocalPath string
+
+func (p LocalPath) Fetch() (string, error) {
+	return string(p), nil
+}
+
+func (p LocalPath) Location() string {
+	return string(p)
+}
+
+// HTTPDownload fetches a file with a GET request, such as a report download
+// link or an S3 presigned URL. The file keeps the name from the URL or the
+// Content-Disposition header so its type can be recognised.
+type HTTPDownload struct {
+	URL    string
+	Dir    string       // Where the download is saved
+	Client *http.Client // Defaults to a client that gives up after downloadTimeout
+}
+
+// downloadTimeout is how long a download may take, so a server that stops
+// answering doesn't hang the conversion.
+const downloadTimeout = 30 * time.Second
+
+// IsURL reports whether s is an http or https URL rather than a path
+func IsURL(s string) bool {
+	u, err := url.Parse(strings.TrimSpace(s))
+	return err == nil && (u.Scheme == "https" || u.Scheme == "http") && u.Host != ""
+}
+
+func (d HTTPDownload) Fetch() (string, error) {
+	client := d.Client
+	if client == nil {
+		client = &http.Client{Timeout: downloadTimeout}
+	}
+
+	resp, err := client.Get(strings.TrimSpace(d.URL))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return "", fmt.Errorf("download failed: %s", resp.Status)
+	}
+
+	name := downloadName(d.URL, resp.Header.Get("Content-Disposition"))
+	if name == "" {
+		return "", fmt.Errorf("can't tell the file type of %s; the link should end in .csv, .xlsx, .json or .ndjson", d.URL)
+	}
+
+	out := filepath.Join(d.Dir, name)
+	f, err := os.Create(out)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		f.Close()
+		return "", err
+	}
+	return out, f.Close()
+}
+
+func (d HTTPDownload) Location() string {
+	return d.URL
+}
+
+// downloadName picks a file name with a supported extension for a download,
+// preferring the server's Content-Disposition over the URL path.
+func downloadName(rawURL, disposition string) string {
+	var candidates []string
+	if _, params, err := mime.ParseMediaType(disposition); err == nil {
+		candidates = append(candidates, params["filename"])
+	}
+	if u, err := url.Parse(strings.TrimSpace(rawURL)); err == nil {
+		candidates = append(candidates, path.Base(u.Path))
+	}
+
+	for _, name := range candidates {
+		// Only the base name is used, so a header can't place the file elsewhere
+		name = filepath.Base(name)
+		lower := strings.ToLower(name)
+		for _, ext := range []string{".csv", ".xlsx", ".json", ".ndjson", ".csv.gz", ".zip"} {
+			if strings.HasSuffix(lower, ext) {
+				return name
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,53 @@
+package converter
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func TestHTTPDownload(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/export" {
+			w.Header().Set("Content-Disposition", `attachment; filename="../hours.csv"`)
+		}
+		io.WriteString(w, "Name,Hours\nAlice,1.5\n")
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		url      string
+		expected string
+	}{
+		{"Name from URL path", server.URL + "/reports/week42.csv?X-Amz-Signature=abc", "week42.csv"},
+		{"Name from Content-Disposition", server.URL + "/export", "hours.csv"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path, err := HTTPDownload{URL: tt.url, Dir: dir}.Fetch()
+			if err != nil {
+				t.Fatalf("Fetch failed: %v", err)
+			}
+			if path != filepath.Join(dir, tt.expected) {
+				t.Errorf("Expected %s, got %s", tt.expected, path)
+			}
+
+			data, err := ReadFileData(path)
+			if err != nil {
+				t.Fatalf("ReadFileData failed: %v", err)
+			}
+			if data.Rows[0][1] != "1.5" {
+				t.Errorf("Unexpected rows: %v", data.Rows)
+			}
+		})
+	}
+
+	if _, err := (HTTPDownload{URL: server.URL + "/report", Dir: t.TempDir()}).Fetch(); err == nil {
+		t.Error("Expected an error when the file type is unknown")
+	}
+}
@@ -0,0 +1,108 @@
+package converter
+
+import (
+	"encoding/csv"
+	"fmt"
+	"strings"
+
+	"github.com/nconklindev/chronos/internal/types"
+)
+
+// DetectDelimiter returns tab for text copied from a spreadsheet and comma otherwise
+func DetectDelimiter(text string) rune {
+	firstLine, _, _ := strings.Cut(text, "\n")
+	if strings.Contains(firstLine, "\t") {
+		return '\t'
+	}
+	return ','
+}
+
+// ConvertTable converts the auto-detected decimal hour columns in pasted
+// tabular text, such as cells copied from Excel, and returns the text in the
+// same delimiter and line endings. A copied range often starts below the
+// header, so the first row is only a header if it looks like one.
+func ConvertTable(text string, opts types.ConversionOptions) (string, *types.ConversionResult, error) {
+	delimiter := DetectDelimiter(text)
+
+	reader := csv.NewReader(strings.NewReader(text))
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return "", nil, err
+	}
+	if len(records) == 0 {
+		return "", nil, fmt.Errorf("no table to convert")
+	}
+
+	trimBOMs(records[0])
+	hasHeader := isHeaderRow(records[0])
+
+	// Without a header, give the columns names so conversion can treat them alike
+	if !hasHeader {
+		width := 0
+		for _, record := range records {
+			width = max(width, len(record))
+		}
+		headers := make([]string, width)
+		for i := range headers {
+			headers[i] = fmt.Sprintf("Column %d", i+1)
+		}
+		records = append([][]string{headers}, records...)
+	}
+
+	data := &types.FileData{
+		Headers:    records[0],
+		Rows:       records[1:],
+		FooterRows: DetectFooterRows(records[1:]),
+	}
+	columns := AutoDetectColumns(data)
+	if len(columns) == 0 {
+		return "", nil, fmt.Errorf("no decimal hour columns found in the copied cells")
+	}
+	if opts.FooterRows == 0 {
+		opts.FooterRows = data.FooterRows
+	}
+
+	converted := convertRecords(records, columns, opts, nil)
+	out := converted.records
+	if !hasHeader {
+		out = out[1:]
+	}
+
+	var s strings.Builder
+	writer := csv.NewWriter(&s)
+	writer.Comma = delimiter
+	writer.UseCRLF = strings.Contains(text, "\r\n")
+	if err := writer.WriteAll(out); err != nil {
+		return "", nil, err
+	}
+
+	return s.String(), &types.ConversionResult{
+		InputFile:     "clipboard",
+		OutputFile:    "clipboard",
+		ColumnsFound:  converted.columns,
+		RowsProcessed: converted.rowsProcessed,
+		Warnings:      converted.warnings(0, opts),
+		CellsChanged:  converted.count,
+		Hours:         converted.hours,
+		Lossy:         converted.lossy,
+		Flagged:       converted.flagged,
+	}, nil
+}
+
+// isHeaderRow reports whether a row has labels and no numbers
+func isHeaderRow(row []string) bool {
+	hasLabel := false
+	for _, cell := range row {
+		cell = strings.TrimSpace(cell)
+		if IsDecimalHour(cell) {
+			return false
+		}
+		if containsLetters(cell) {
+			hasLabel = true
+		}
+	}
+	return hasLabel
+}
@@ -0,0 +1,49 @@
+package converter
+
+import (
+	"testing"
+
+	"github.com/nconklindev/chronos/internal/types"
+)
+
+func TestConvertTable(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		opts     types.ConversionOptions
+		expected string
+	}{
+		{
+			name:     "Tab-separated with header",
+			input:    "Name\tHours\r\nAlice\t1.5\r\nBob\t2\r\n",
+			expected: "Name\tHours\r\nAlice\t01:30\r\nBob\t02:00\r\n",
+		},
+		{
+			name:     "No header row",
+			input:    "Alice\t1.5\nBob\t0.25\n",
+			expected: "Alice\t01:30\nBob\t00:15\n",
+		},
+		{
+			name:     "Comma-separated keeping originals",
+			input:    "Name,Hours\nAlice,1.5\n",
+			opts:     types.ConversionOptions{KeepOriginal: true},
+			expected: "Name,Hours,Hours (HH:MM)\nAlice,1.5,01:30\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := ConvertTable(tt.input, tt.opts)
+			if err != nil {
+				t.Fatalf("ConvertTable failed: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("ConvertTable() = %q; want %q", got, tt.expected)
+			}
+		})
+	}
+
+	if _, _, err := ConvertTable("Name\tNotes\nAlice\tlate\n", types.ConversionOptions{}); err == nil {
+		t.Error("Expected an error when no hours columns are found")
+	}
+}
@@ -0,0 +1,361 @@
+package converter
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/nconklindev/chronos/internal/types"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// TotalsSheet names the sheet of XLSX output that totals are written to.
+const TotalsSheet = "Totals"
+
+// TotalsPath returns where the totals of CSV output written to path go.
+func TotalsPath(path string) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "_totals" + ext
+}
+
+// TotalsOutput returns the separate file that converting with opts to
+// output writes totals to, or "" when there's none: without totals, and
+// for XLSX output, whose totals go on a TotalsSheet.
+func TotalsOutput(output string, opts types.ConversionOptions) string {
+	if opts.Totals == nil || opts.Markup != types.MarkupNone || opts.Parquet || strings.EqualFold(filepath.Ext(output), ".xlsx") {
+		return ""
+	}
+	return TotalsPath(output)
+}
+
+// IsConvertedName reports whether a file name is one of the outputs
+// conversion writes by default, a converted copy or its totals, so folders
+// that are watched don't convert them again.
+func IsConvertedName(name string) bool {
+	stem := strings.TrimSuffix(name, filepath.Ext(name))
+	return strings.HasSuffix(strings.TrimSuffix(stem, "_totals"), "_converted")
+}
+
+// dateLayouts are the dates totals are grouped by, besides the timestamps
+// durations are computed from. Dates are month first, as in datedLayouts.
+var dateLayouts = []string{
+	"2006-01-02",
+	"2006/01/02",
+	"1/2/2006",
+	"1/2/06",
+	"01-02-06",
+	"Jan 2, 2006",
+	"January 2, 2006",
+	"2 Jan 2006",
+}
+
+// parseDate reads the day of a date or timestamp.
+func parseDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	if t, dated, err := parseTimestamp(s); err == nil && dated {
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC), nil
+	}
+	return time.Time{}, fmt.Errorf("%q isn't a date", s)
+}
+
+// periodStart returns the first day of the period that day falls in.
+func periodStart(day time.Time, p types.Period) time.Time {
+	switch p {
+	case types.PeriodDay:
+		return day
+	case types.PeriodMonth:
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	// Weeks start on Monday
+	return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+}
+
+// periodHeader names the column of periods in totals.
+func periodHeader(p types.Period) string {
+	switch p {
+	case types.PeriodDay:
+		return "Day"
+	case types.PeriodMonth:
+		return "Month"
+	}
+	return "Week Of"
+}
+
+// periodLabel writes the period starting on start.
+func periodLabel(start time.Time, p types.Period) string {
+	if p == types.PeriodMonth {
+		return start.Format("2006-01")
+	}
+	return start.Format("2006-01-02")
+}
+
+// computeTotals sums the hours of the converted duration columns of each
+// employee over each period, from records whose first row is the header.
+// Rows that conversion skips, such as footers and filtered rows, are left
+// out, and so are rows without an employee or a readable date, which are
+// counted in untotalled. Totals are returned as a table with its header.
+func computeTotals(records [][]string, columnIndices []int, opts types.ConversionOptions) ([][]string, int) {
+	t := newTotaller(records[0], columnIndices, opts)
+	firstFooter := footerStart(len(records)-1, opts) + 1
+	for _, row := range records[1:firstFooter] {
+		if MatchesFilters(row, opts.Filters) {
+			t.add(row)
+		}
+	}
+	return t.table(), t.untotalled
+}
+
+// totalsGroup is an employee's period, whose hours are summed together.
+type totalsGroup struct {
+	employee string
+	start    time.Time
+}
+
+// totaller sums hours as computeTotals does, a row at a time.
+type totaller struct {
+	opts       types.ConversionOptions
+	headers    []string
+	columns    []int // The duration columns summed, left to right
+	sums       map[totalsGroup][]float64
+	untotalled int
+}
+
+// newTotaller prepares to total the rows below headers.
+func newTotaller(headers []string, columnIndices []int, opts types.ConversionOptions) *totaller {
+	var columns []int
+	for _, idx := range columnIndices {
+		if idx >= 0 && idx < len(headers) && IsDuration(opts.Columns[idx]) {
+			columns = append(columns, idx)
+		}
+	}
+	sort.Ints(columns)
+	return &totaller{opts: opts, headers: headers, columns: columns, sums: make(map[totalsGroup][]float64)}
+}
+
+// add adds the hours of a row that conversion doesn't skip.
+func (t *totaller) add(row []string) {
+	totals := t.opts.Totals
+	var employee, date string
+	if totals.Employee < len(row) {
+		employee = strings.TrimSpace(row[totals.Employee])
+	}
+	if totals.Date < len(row) {
+		date = row[totals.Date]
+	}
+	day, err := parseDate(date)
+	if employee == "" || err != nil {
+		t.untotalled++
+		return
+	}
+
+	g := totalsGroup{employee, periodStart(day, totals.Period)}
+	if t.sums[g] == nil {
+		t.sums[g] = make([]float64, len(t.columns))
+	}
+	for i, idx := range t.columns {
+		if idx >= len(row) {
+			continue
+		}
+		s := t.opts.Columns[idx]
+		value, err := CellValue(row[idx], s)
+		if err != nil {
+			continue
+		}
+		if factor, ok := unitHours[s.Unit]; ok {
+			value *= factor
+		}
+		t.sums[g][i] += value
+	}
+}
+
+// table returns the totals as a table with its header.
+func (t *totaller) table() [][]string {
+	groups := make([]totalsGroup, 0, len(t.sums))
+	for g := range t.sums {
+		groups = append(groups, g)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].employee != groups[j].employee {
+			return groups[i].employee < groups[j].employee
+		}
+		return groups[i].start.Before(groups[j].start)
+	})
+
+	period := t.opts.Totals.Period
+	header := []string{headerAt(t.headers, t.opts.Totals.Employee), periodHeader(period)}
+	for _, idx := range t.columns {
+		header = append(header, t.headers[idx], t.headers[idx]+" (decimal)")
+	}
+	totals := [][]string{header}
+	for _, g := range groups {
+		row := []string{g.employee, periodLabel(g.start, period)}
+		for i, idx := range t.columns {
+			// Totals are formatted like the column, but not rounded the way
+			// its cells are, so they add up
+			s := t.opts.Columns[idx]
+			hours := t.sums[g][i]
+			row = append(row, ConvertValue(hours, types.ColumnSettings{Format: s.Format, Pattern: s.Pattern}), strconv.FormatFloat(hours, 'f', 2, 64))
+		}
+		totals = append(totals, row)
+	}
+	return totals
+}
+
+// headerAt returns the header of column idx, or a name for it when it has none.
+func headerAt(headers []string, idx int) string {
+	if idx < len(headers) && strings.TrimSpace(headers[idx]) != "" {
+		return headers[idx]
+	}
+	return fmt.Sprintf("Column %d", idx+1)
+}
+
+// totalsWarnings reports the rows left out of the totals.
+func totalsWarnings(untotalled int) []string {
+	if untotalled == 0 {
+		return nil
+	}
+	return []string{fmt.Sprintf("%d row(s) without an employee or a readable date were left out of the totals", untotalled)}
+}
+
+// totalsSink returns where the totals of CSV output written to sink go.
+// They go in a file next to the output, so only outputs on disk have them.
+func totalsSink(sink OutputSink) (OutputSink, bool) {
+	switch s := sink.(type) {
+	case LocalFile:
+		return LocalFile(TotalsPath(string(s))), true
+	case ReplaceFile:
+		return ReplaceFile(TotalsPath(string(s))), true
+	}
+	return nil, false
+}
+
+// writeCSVTotals writes totals next to the CSV output written to sink, in
+// the output's encoding and line style, quoting every field if the
+// output's are. It returns where they went, or a warning when the
+// output isn't on disk.
+func writeCSVTotals(sink OutputSink, totals [][]string, encoding string, style lineStyle, q quoting) (string, []string, error) {
+	to, ok := totalsSink(sink)
+	if !ok {
+		return "", []string{fmt.Sprintf("totals are only written next to files on disk, not to %s", sink.Location())}, nil
+	}
+
+	file, err := to.Create()
+	if err != nil {
+		return "", nil, err
+	}
+	defer file.Close()
+	out, err := newEncodedWriter(file, encoding)
+	if err != nil {
+		return "", nil, err
+	}
+	if err := writeCSV(out, totals, style, quoting{all: q.all}); err != nil {
+		return "", nil, err
+	}
+	if err := out.Close(); err != nil {
+		return "", nil, err
+	}
+	return to.Location(), nil, file.Close()
+}
+
+// addTotalsSheet writes totals to a TotalsSheet in f, replacing one left
+// by an earlier conversion, unless that's the sheet converted. Decimal
+// hours are written as numbers.
+func addTotalsSheet(f *excelize.File, sheet string, totals [][]string) error {
+	name := TotalsSheet
+	if strings.EqualFold(sheet, name) {
+		name = "Hours " + TotalsSheet
+	}
+	if err := f.DeleteSheet(name); err != nil {
+		return err
+	}
+	if _, err := f.NewSheet(name); err != nil {
+		return err
+	}
+	for i, row := range totals {
+		cells := make([]any, len(row))
+		for j, value := range row {
+			cells[j] = value
+			// The decimal columns follow each converted column
+			if n, err := strconv.ParseFloat(value, 64); err == nil && i > 0 && j > 1 && j%2 == 1 {
+				cells[j] = n
+			}
+		}
+		cell, _ := excelize.CoordinatesToCellName(1, i+1)
+		if err := f.SetSheetRow(name, cell, &cells); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// employeeWords and dateWords mark the headers of the columns totals are
+// grouped by, in order of preference.
+var (
+	employeeWords = []string{"employee", "name", "badge", "worker", "staff", "person", "id"}
+	dateWords     = []string{"date", "day", "worked"}
+)
+
+// DetectTotals finds an employee column and a date column to group totals
+// by, from their headers and, for the date, its sampled values.
+func DetectTotals(data *types.FileData) (types.Totals, bool) {
+	dataRows := max(len(data.Rows)-data.FooterRows, 0)
+	dated := func(col int) bool {
+		checked := 0
+		for j := 0; j < dataRows && j < RowDetectionLimit; j++ {
+			if col >= len(data.Rows[j]) || strings.TrimSpace(data.Rows[j][col]) == "" {
+				continue
+			}
+			if _, err := parseDate(data.Rows[j][col]); err != nil {
+				return false
+			}
+			checked++
+		}
+		return checked > 0
+	}
+
+	find := func(words []string, fits func(col int) bool) int {
+		for _, word := range words {
+			for i, header := range data.Headers {
+				if headerHasWord(header, word) && fits(i) {
+					return i
+				}
+			}
+		}
+		return -1
+	}
+
+	date := find(dateWords, dated)
+	employee := find(employeeWords, func(col int) bool { return col != date })
+	if date < 0 || employee < 0 {
+		return types.Totals{}, false
+	}
+	return types.Totals{Employee: employee, Date: date}, true
+}
+
+// headerWords splits a header into its lowercase words, in any script.
+func headerWords(header string) []string {
+	return strings.FieldsFunc(strings.ToLower(header), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// headerHasWord reports whether word is one of the words of header,
+// ignoring case.
+func headerHasWord(header, word string) bool {
+	for _, w := range headerWords(header) {
+		if w == word {
+			return true
+		}
+	}
+	return false
+}
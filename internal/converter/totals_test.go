@@ -0,0 +1,185 @@
+package converter
+
+import (
+	"io"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/nconklindev/chronos/internal/types"
+	"github.com/xuri/excelize/v2"
+)
+
+// totalsRecords are two employees' hours over two weeks, with a footer
+var totalsRecords = [][]string{
+	{"Employee", "Date", "Hours", "Code"},
+	{"Bob", "2024-01-08", "8", "REG"},
+	{"Alice", "2024-01-01", "7.5", "REG"},
+	{"Alice", "1/3/2024", "8.25", "REG"},
+	{"Alice", "2024-01-08 09:00", "4", "REG"},
+	{"Carol", "soon", "6", "REG"},
+	{"Total", "", "27.75", ""},
+}
+
+func TestComputeTotals(t *testing.T) {
+	tests := []struct {
+		period   types.Period
+		expected [][]string
+	}{
+		{types.PeriodWeek, [][]string{
+			{"Employee", "Week Of", "Hours", "Hours (decimal)"},
+			{"Alice", "2024-01-01", "15:45", "15.75"},
+			{"Alice", "2024-01-08", "04:00", "4.00"},
+			{"Bob", "2024-01-08", "08:00", "8.00"},
+		}},
+		{types.PeriodMonth, [][]string{
+			{"Employee", "Month", "Hours", "Hours (decimal)"},
+			{"Alice", "2024-01", "19:45", "19.75"},
+			{"Bob", "2024-01", "08:00", "8.00"},
+		}},
+	}
+	for _, tt := range tests {
+		opts := types.ConversionOptions{FooterRows: 1, Totals: &types.Totals{Employee: 0, Date: 1, Period: tt.period}}
+		totals, untotalled := computeTotals(totalsRecords, []int{2}, opts)
+		if !reflect.DeepEqual(totals, tt.expected) {
+			t.Errorf("%q: expected %v, got %v", tt.period, tt.expected, totals)
+		}
+		if untotalled != 1 {
+			t.Errorf("%q: expected Carol's unreadable date to be left out, got %d", tt.period, untotalled)
+		}
+	}
+
+	// Hours in other units are totalled in hours, and filtered rows are left out
+	opts := types.ConversionOptions{
+		FooterRows: 1,
+		Columns:    map[int]types.ColumnSettings{2: {Unit: types.UnitMinutes}},
+		Filters:    []types.RowFilter{{Column: 0, Op: types.FilterEquals, Value: "Bob"}},
+		Totals:     &types.Totals{Employee: 0, Date: 1, Period: types.PeriodDay},
+	}
+	totals, _ := computeTotals(totalsRecords, []int{2}, opts)
+	expected := [][]string{
+		{"Employee", "Day", "Hours", "Hours (decimal)"},
+		{"Bob", "2024-01-08", "00:08", "0.13"},
+	}
+	if !reflect.DeepEqual(totals, expected) {
+		t.Errorf("Expected %v, got %v", expected, totals)
+	}
+}
+
+func TestTotalsOutput(t *testing.T) {
+	totals := types.ConversionOptions{Totals: &types.Totals{}}
+	if got := TotalsOutput("out/hours_converted.csv", totals); got != "out/hours_converted_totals.csv" {
+		t.Errorf("Expected the totals next to CSV output, got %q", got)
+	}
+	if got := TotalsOutput("out/hours_converted.xlsx", totals); got != "" {
+		t.Errorf("Expected XLSX output to keep its totals, got %q", got)
+	}
+	if got := TotalsOutput("out/hours_converted.csv", types.ConversionOptions{}); got != "" {
+		t.Errorf("Expected no totals file without totals, got %q", got)
+	}
+}
+
+func TestIsConvertedName(t *testing.T) {
+	for name, expected := range map[string]bool{
+		"hours.csv":                  false,
+		"hours_totals.csv":           false,
+		"hours_converted.csv":        true,
+		"hours_converted_totals.csv": true,
+		"hours_adp_converted.xlsx":   true,
+	} {
+		if got := IsConvertedName(name); got != expected {
+			t.Errorf("IsConvertedName(%q) = %v, expected %v", name, got, expected)
+		}
+	}
+}
+
+func TestDetectTotals(t *testing.T) {
+	data := &types.FileData{Headers: totalsRecords[0], Rows: totalsRecords[1:4]}
+	if got, ok := DetectTotals(data); !ok || got != (types.Totals{Employee: 0, Date: 1}) {
+		t.Errorf("Expected Employee and Date, got %v, %v", got, ok)
+	}
+
+	// A date column has to hold dates
+	data = &types.FileData{Headers: []string{"Name", "Day", "Hours"}, Rows: [][]string{{"Alice", "Monday", "8"}}}
+	if got, ok := DetectTotals(data); ok {
+		t.Errorf("Expected no totals without dates, got %v", got)
+	}
+}
+
+func TestConvertCSV_Totals(t *testing.T) {
+	tmpDir := t.TempDir()
+	inputFile := filepath.Join(tmpDir, "input.csv")
+	outputFile := filepath.Join(tmpDir, "output.csv")
+	writeTestCSV(t, inputFile, totalsRecords[:4])
+
+	opts := types.ConversionOptions{Totals: &types.Totals{Employee: 0, Date: 1}}
+	result, err := ConvertCSV(inputFile, LocalFile(outputFile), []int{2}, opts, nil)
+	if err != nil {
+		t.Fatalf("ConvertCSV failed: %v", err)
+	}
+	if result.TotalsFile != filepath.Join(tmpDir, "output_totals.csv") {
+		t.Errorf("Unexpected totals file %q", result.TotalsFile)
+	}
+
+	expected := [][]string{
+		{"Employee", "Week Of", "Hours", "Hours (decimal)"},
+		{"Alice", "2024-01-01", "15:45", "15.75"},
+		{"Bob", "2024-01-08", "08:00", "8.00"},
+	}
+	if records := readTestCSV(t, result.TotalsFile); !reflect.DeepEqual(records, expected) {
+		t.Errorf("Expected %v, got %v", expected, records)
+	}
+
+	// Totals can't go next to output that isn't on disk
+	result, err = ConvertCSV(inputFile, WriterSink{W: io.Discard, Label: "stdout"}, []int{2}, opts, nil)
+	if err != nil {
+		t.Fatalf("ConvertCSV failed: %v", err)
+	}
+	if result.TotalsFile != "" || len(result.Warnings) != 1 {
+		t.Errorf("Expected a warning instead of totals, got %q, %v", result.TotalsFile, result.Warnings)
+	}
+}
+
+func TestConvertXLSX_Totals(t *testing.T) {
+	tmpDir := t.TempDir()
+	inputFile := filepath.Join(tmpDir, "input.xlsx")
+
+	f := excelize.NewFile()
+	for i, record := range totalsRecords[:4] {
+		row := make([]any, len(record))
+		for j, value := range record {
+			row[j] = value
+		}
+		cell, _ := excelize.CoordinatesToCellName(1, i+1)
+		f.SetSheetRow("Sheet1", cell, &row)
+	}
+	if err := f.SaveAs(inputFile); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	for _, lowMemory := range []bool{false, true} {
+		outputFile := filepath.Join(tmpDir, "output.xlsx")
+		opts := types.ConversionOptions{LowMemory: lowMemory, Totals: &types.Totals{Employee: 0, Date: 1}}
+		if _, err := ConvertXLSX(inputFile, LocalFile(outputFile), []int{2}, opts, nil); err != nil {
+			t.Fatalf("ConvertXLSX failed: %v", err)
+		}
+
+		out, err := excelize.OpenFile(outputFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rows, _ := out.GetRows(TotalsSheet)
+		out.Close()
+
+		// Decimal hours are numbers, so Bob's 8.00 reads back as 8
+		expected := [][]string{
+			{"Employee", "Week Of", "Hours", "Hours (decimal)"},
+			{"Alice", "2024-01-01", "15:45", "15.75"},
+			{"Bob", "2024-01-08", "08:00", "8"},
+		}
+		if !reflect.DeepEqual(rows, expected) {
+			t.Errorf("lowMemory=%v: expected %v, got %v", lowMemory, expected, rows)
+		}
+	}
+}
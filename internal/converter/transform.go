@@ -0,0 +1,127 @@
+package converter
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/nconklindev/chronos/internal/types"
+)
+
+// Transformer converts the cells of a converted column. Transform is given
+// each non-empty cell as read, with the column's settings, and returns the
+// converted value; cells it returns an error for are left unchanged and
+// counted as unconverted.
+type Transformer interface {
+	// Name identifies the transformer in column settings, profiles and flags.
+	Name() string
+	Transform(cell string, s types.ColumnSettings) (string, error)
+}
+
+// DurationTransformer is the name of the transformer converting numbers
+// counting the column's unit to its format, which columns use by default.
+const DurationTransformer = "duration"
+
+var (
+	transformersMu sync.RWMutex
+	transformers   = make(map[string]Transformer)
+)
+
+func init() {
+	RegisterTransformer(durationTransformer{})
+	RegisterTransformer(scaleTransformer{name: "minutes_to_hours", divisor: 60, decimals: 2, trim: true})
+	RegisterTransformer(scaleTransformer{name: "cents_to_dollars", divisor: 100, decimals: 2})
+}
+
+// RegisterTransformer makes a transformer available to column settings
+// under its name, replacing any registered under the same name.
+func RegisterTransformer(t Transformer) {
+	transformersMu.Lock()
+	defer transformersMu.Unlock()
+	transformers[t.Name()] = t
+}
+
+// LookupTransformer returns the transformer registered under name. An
+// empty name is the duration transformer.
+func LookupTransformer(name string) (Transformer, bool) {
+	if name == "" {
+		name = DurationTransformer
+	}
+	transformersMu.RLock()
+	defer transformersMu.RUnlock()
+	t, ok := transformers[name]
+	return t, ok
+}
+
+// TransformerNames lists the registered transformers, the duration
+// transformer first and the rest by name.
+func TransformerNames() []string {
+	transformersMu.RLock()
+	defer transformersMu.RUnlock()
+	names := make([]string, 0, len(transformers))
+	for name := range transformers {
+		if name != DurationTransformer {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return append([]string{DurationTransformer}, names...)
+}
+
+// CheckTransformer reports whether a transformer is registered under name.
+func CheckTransformer(name string) error {
+	if _, ok := LookupTransformer(name); !ok {
+		return fmt.Errorf("unknown transformer %q (choose %s)", name, strings.Join(TransformerNames(), ", "))
+	}
+	return nil
+}
+
+// IsDuration reports whether a column is converted to a duration, the only
+// kind that's split into overtime, checked for rounding losses or flagged.
+func IsDuration(s types.ColumnSettings) bool {
+	return s.Transformer == "" || s.Transformer == DurationTransformer
+}
+
+// durationTransformer converts numbers counting the column's unit to its
+// format, after the column's expression, keeping only the regular part of
+// split columns.
+type durationTransformer struct{}
+
+func (durationTransformer) Name() string { return DurationTransformer }
+
+func (durationTransformer) Transform(cell string, s types.ColumnSettings) (string, error) {
+	decimal, err := CellValue(cell, s)
+	if err != nil {
+		return cell, err
+	}
+	regular, _ := SplitValue(decimal, s)
+	return ConvertValue(regular, s), nil
+}
+
+// scaleTransformer divides numbers by a constant, such as minutes into
+// hours, writing them with a number of decimals. With trim, trailing zeros
+// are dropped, so 90 minutes is 1.5 hours rather than 1.50.
+type scaleTransformer struct {
+	name     string
+	divisor  float64
+	decimals int
+	trim     bool
+}
+
+func (t scaleTransformer) Name() string { return t.name }
+
+func (t scaleTransformer) Transform(cell string, s types.ColumnSettings) (string, error) {
+	value, err := parseCell(cell, s)
+	if err != nil {
+		return cell, err
+	}
+	scale := math.Pow(10, float64(t.decimals))
+	value = math.Round(value/t.divisor*scale) / scale
+	if t.trim {
+		return strconv.FormatFloat(value, 'f', -1, 64), nil
+	}
+	return strconv.FormatFloat(value, 'f', t.decimals, 64), nil
+}
@@ -0,0 +1,91 @@
+package converter
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nconklindev/chronos/internal/types"
+)
+
+// initialsTransformer is registered by the tests to show that new
+// transformers need no changes to the pipeline.
+type initialsTransformer struct{}
+
+func (initialsTransformer) Name() string { return "initials" }
+
+func (initialsTransformer) Transform(cell string, _ types.ColumnSettings) (string, error) {
+	var initials strings.Builder
+	for _, word := range strings.Fields(cell) {
+		initials.WriteString(word[:1])
+	}
+	if initials.Len() < 2 {
+		return cell, fmt.Errorf("%q isn't a full name", cell)
+	}
+	return initials.String(), nil
+}
+
+func TestTransformers(t *testing.T) {
+	RegisterTransformer(initialsTransformer{})
+
+	tmpDir := t.TempDir()
+	inputFile := filepath.Join(tmpDir, "input.csv")
+	outputFile := filepath.Join(tmpDir, "output.csv")
+	writeTestCSV(t, inputFile, [][]string{
+		{"Name", "Hours", "Break", "Pay"},
+		{"Alice Smith", "9.5", "90", "1999"},
+		{"Bob", "7.25", "20", "5"},
+		{"Carol Jones", "", "", "n/a"},
+	})
+
+	opts := types.ConversionOptions{
+		FlagAbove: 8,
+		Columns: map[int]types.ColumnSettings{
+			0: {Transformer: "initials"},
+			1: {OvertimeAfter: 8},
+			2: {Transformer: "minutes_to_hours", OvertimeAfter: 8},
+			3: {Transformer: "cents_to_dollars", Format: types.FormatMinutes},
+		},
+	}
+	result, err := ConvertCSV(inputFile, LocalFile(outputFile), []int{0, 1, 2, 3}, opts, nil)
+	if err != nil {
+		t.Fatalf("ConvertCSV failed: %v", err)
+	}
+
+	// Only durations are split and flagged
+	expected := [][]string{
+		{"Name", "Regular (HH:MM)", "Overtime (HH:MM)", "Break", "Pay", "Over 8 hours"},
+		{"AS", "08:00", "01:30", "1.5", "19.99", "Hours"},
+		{"Bob", "07:15", "00:00", "0.33", "0.05", ""},
+		{"CJ", "", "", "", "n/a", ""},
+	}
+	if records := readTestCSV(t, outputFile); !reflect.DeepEqual(records, expected) {
+		t.Errorf("Expected %v, got %v", expected, records)
+	}
+	if result.Warnings[1] != "2 non-numeric cell(s) in converted columns were left unchanged" {
+		t.Errorf("Expected rejected cells to be counted, got %v", result.Warnings)
+	}
+	if len(result.Lossy) > 0 {
+		t.Errorf("Expected no rounding losses, got %+v", result.Lossy)
+	}
+}
+
+func TestLookupTransformer(t *testing.T) {
+	if tr, ok := LookupTransformer(""); !ok || tr.Name() != DurationTransformer {
+		t.Errorf("Expected the duration transformer by default, got %v", tr)
+	}
+	if err := CheckTransformer("cents_to_dollars"); err != nil {
+		t.Error(err)
+	}
+	if err := CheckTransformer("furlongs"); err == nil {
+		t.Error("Expected an unknown transformer to be rejected")
+	}
+	if names := TransformerNames(); names[0] != DurationTransformer {
+		t.Errorf("Expected the duration transformer first, got %v", names)
+	}
+	if _, ok := ConvertCell("7.5", types.ColumnSettings{Transformer: "furlongs"}); ok {
+		t.Error("Expected cells of an unknown transformer to be left unconverted")
+	}
+}
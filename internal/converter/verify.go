@@ -0,0 +1,114 @@
+package converter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nconklindev/chronos/internal/types"
+)
+
+// Verify checks a converted file against its source, confirming every
+// converted cell equals DecimalToTime of its original, or is blank for a
+// zero. Converted columns are found by header: a copy named with
+// DefaultHeaderSuffix when the originals were kept, otherwise a column
+// whose values changed. Rows are compared in order, so files with dropped
+// footers compare up to the end of the shorter file.
+func Verify(sourceFile, convertedFile string) (*types.VerifyResult, error) {
+	src, err := ReadFileData(sourceFile)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", sourceFile, err)
+	}
+	out, err := ReadFileData(convertedFile)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", convertedFile, err)
+	}
+
+	result := &types.VerifyResult{SourceFile: sourceFile, ConvertedFile: convertedFile}
+
+	outColumns := make(map[string]int, len(out.Headers))
+	for i, header := range out.Headers {
+		if _, ok := outColumns[header]; !ok {
+			outColumns[header] = i
+		}
+	}
+
+	rows := min(len(src.Rows), len(out.Rows))
+	if len(src.Rows) != len(out.Rows) {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("the source has %d data rows and the converted file %d; only the first %d were compared", len(src.Rows), len(out.Rows), rows))
+	}
+
+	// Columns formatted as times were read as Excel time fractions
+	settings := WithTimeColumns(nil, src.TimeColumns)
+	for col, header := range src.Headers {
+		if strings.TrimSpace(header) == "" {
+			continue
+		}
+
+		// A kept original's copy, or the column converted in place
+		target, isCopy := outColumns[header+DefaultHeaderSuffix]
+		if !isCopy {
+			same, ok := outColumns[header]
+			if !ok || !columnChanged(src.Rows[:rows], out.Rows[:rows], col, same) {
+				continue
+			}
+			target = same
+		}
+		result.Columns = append(result.Columns, header)
+
+		for i := 0; i < rows; i++ {
+			original := cellAt(src.Rows[i], col)
+			actual := cellAt(out.Rows[i], target)
+			expected, ok := ConvertCell(original, settings[col])
+			if !ok || expected == "" {
+				// Text and empty cells aren't converted
+				continue
+			}
+			result.CellsChecked++
+
+			// Cells in footer and filtered rows are left alone
+			unconverted := actual == original
+			if isCopy {
+				unconverted = actual == ""
+			}
+			switch {
+			case actual == expected:
+			case actual == "" && expected == zeroCell(settings[col]):
+				// Zero values may be left blank on purpose
+			case unconverted:
+				result.Unconverted++
+			default:
+				result.Mismatches = append(result.Mismatches, types.Mismatch{
+					Row:      src.HeaderRow + i + 2,
+					Column:   header,
+					Original: original,
+					Expected: expected,
+					Actual:   actual,
+				})
+			}
+		}
+	}
+
+	if len(result.Columns) == 0 {
+		return nil, fmt.Errorf("no converted columns found in %s", convertedFile)
+	}
+	return result, nil
+}
+
+// columnChanged reports whether any cell of column col in src differs from
+// column target in out.
+func columnChanged(src, out [][]string, col, target int) bool {
+	for i := range src {
+		if cellAt(src[i], col) != cellAt(out[i], target) {
+			return true
+		}
+	}
+	return false
+}
+
+// cellAt returns a row's cell, or empty past its end.
+func cellAt(row []string, col int) string {
+	if col < len(row) {
+		return row[col]
+	}
+	return ""
+}
@@ -0,0 +1,73 @@
+package converter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestVerify(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "hours.csv")
+	if err := os.WriteFile(source, []byte("Name,Hours,Note\nAlice,1.5,a\nBob,2.25,b\nTotal,3.75,\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		converted   string
+		mismatches  int
+		unconverted int
+	}{
+		{"In place", "Name,Hours,Note\nAlice,01:30,a\nBob,02:15,b\nTotal,3.75,\n", 0, 1},
+		{"Kept originals", "Name,Hours,Hours (HH:MM),Note\nAlice,1.5,01:30,a\nBob,2.25,02:15,b\nTotal,3.75,,\n", 0, 1},
+		{"Wrong cell", "Name,Hours,Note\nAlice,01:30,a\nBob,02:14,b\nTotal,3.75,\n", 1, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			converted := filepath.Join(t.TempDir(), "hours_converted.csv")
+			if err := os.WriteFile(converted, []byte(tt.converted), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			result, err := Verify(source, converted)
+			if err != nil {
+				t.Fatalf("Verify failed: %v", err)
+			}
+			if len(result.Columns) != 1 || result.Columns[0] != "Hours" {
+				t.Errorf("Expected Hours to be found converted, got %v", result.Columns)
+			}
+			if result.CellsChecked != 3 || len(result.Mismatches) != tt.mismatches || result.Unconverted != tt.unconverted {
+				t.Errorf("Unexpected result: %+v", result)
+			}
+			if tt.mismatches > 0 {
+				m := result.Mismatches[0]
+				if m.Row != 3 || m.Expected != "02:15" || m.Actual != "02:14" {
+					t.Errorf("Unexpected mismatch: %+v", m)
+				}
+			}
+		})
+	}
+
+	if _, err := Verify(source, source); err == nil {
+		t.Error("Expected an error when nothing was converted")
+	}
+
+	// Zeros left blank on purpose aren't mismatches
+	zeros := filepath.Join(dir, "zeros.csv")
+	blanked := filepath.Join(dir, "zeros_converted.csv")
+	if err := os.WriteFile(zeros, []byte("Name,Hours\nAlice,0\nBob,1.5\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(blanked, []byte("Name,Hours\nAlice,\nBob,01:30\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	result, err := Verify(zeros, blanked)
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if result.CellsChecked != 2 || len(result.Mismatches) != 0 || result.Unconverted != 0 {
+		t.Errorf("Expected blanked zeros to verify, got %+v", result)
+	}
+}
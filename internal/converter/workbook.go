@@ -0,0 +1,263 @@
+package converter
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// convertedSheetSuffix is added to a sheet's name to name its converted copy.
+const convertedSheetSuffix = " (converted)"
+
+// ConvertedSheetName returns the name of the sheet a workbook's converted
+// data goes in with ConversionOptions.NewSheet. Long names are shortened
+// to fit Excel's 31 character limit.
+func ConvertedSheetName(sheet string) string {
+	limit := excelize.MaxSheetNameLength - utf8.RuneCountInString(convertedSheetSuffix)
+	if runes := []rune(sheet); len(runes) > limit {
+		sheet = string(runes[:limit])
+	}
+	return sheet + convertedSheetSuffix
+}
+
+// addConvertedSheet copies a sheet into a new sheet named by
+// ConvertedSheetName, replacing one left by an earlier conversion, and
+// makes it the active sheet. It returns the new sheet's name.
+func addConvertedSheet(f *excelize.File, sheet string) (string, error) {
+	name := ConvertedSheetName(sheet)
+	if idx, _ := f.GetSheetIndex(name); idx >= 0 {
+		// Deleting a sheet leaves its tables behind, and their names taken
+		tables, err := f.GetTables(name)
+		if err != nil {
+			return "", err
+		}
+		for _, table := range tables {
+			if err := f.DeleteTable(table.Name); err != nil {
+				return "", err
+			}
+		}
+	}
+	if err := f.DeleteSheet(name); err != nil {
+		return "", err
+	}
+
+	from, err := f.GetSheetIndex(sheet)
+	if err != nil {
+		return "", err
+	}
+	to, err := f.NewSheet(name)
+	if err != nil {
+		return "", err
+	}
+	if err := f.CopySheet(from, to); err != nil {
+		return "", err
+	}
+	f.SetActiveSheet(to)
+	return name, nil
+}
+
+// DefaultTableStyle is the style of tables added without one.
+const DefaultTableStyle = "TableStyleMedium2"
+
+var (
+	tableNamePattern  = regexp.MustCompile(`^[A-Za-z_\\][A-Za-z0-9_.\\]*$`)
+	r1c1Pattern       = regexp.MustCompile(`(?i)^(r[0-9]*c?[0-9]*|c[0-9]*)$`)
+	tableStylePattern = regexp.MustCompile(`^TableStyle(Light([1-9]|1[0-9]|2[01])|Medium([1-9]|1[0-9]|2[0-8])|Dark([1-9]|1[01]))$`)
+)
+
+// CheckTableName reports whether Excel accepts name for a table: it starts
+// with a letter or underscore, has no spaces or punctuation besides
+// periods, and can't be read as a cell reference.
+func CheckTableName(name string) error {
+	if len(name) > 255 || !tableNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid table name %q: use letters, digits and underscores, starting with a letter", name)
+	}
+	if _, _, err := excelize.CellNameToCoordinates(name); err == nil || r1c1Pattern.MatchString(name) {
+		return fmt.Errorf("invalid table name %q: it looks like a cell reference", name)
+	}
+	return nil
+}
+
+// CheckTableStyle reports whether style is one of Excel's built-in table
+// styles, such as TableStyleLight9 or TableStyleMedium2.
+func CheckTableStyle(style string) error {
+	if !tableStylePattern.MatchString(style) {
+		return fmt.Errorf("unknown table style %q: use TableStyleLight1-21, TableStyleMedium1-28 or TableStyleDark1-11", style)
+	}
+	return nil
+}
+
+// addTable wraps the cells from the header row to lastRow, both 1-indexed,
+// in a table named name. A table left with that name on the sheet by an
+// earlier conversion is replaced.
+func addTable(f *excelize.File, sheet, name, style string, headerRow, lastRow int) error {
+	if style == "" {
+		style = DefaultTableStyle
+	}
+	if err := CheckTableName(name); err != nil {
+		return err
+	}
+	if err := CheckTableStyle(style); err != nil {
+		return err
+	}
+
+	// The table spans the header row's cells
+	rows, err := f.Rows(sheet)
+	if err != nil {
+		return err
+	}
+	width := 0
+	for i := 1; i <= headerRow && rows.Next(); i++ {
+		if i == headerRow {
+			header, err := rows.Columns()
+			if err != nil {
+				rows.Close()
+				return err
+			}
+			width = len(header)
+		}
+	}
+	if err := rows.Close(); err != nil {
+		return err
+	}
+	if width == 0 {
+		return fmt.Errorf("no header row to add table %q to", name)
+	}
+	first, _ := excelize.CoordinatesToCellName(1, headerRow)
+	last, _ := excelize.CoordinatesToCellName(width, max(lastRow, headerRow+1))
+	ref := first + ":" + last
+
+	tables, err := f.GetTables(sheet)
+	if err != nil {
+		return err
+	}
+	for _, table := range tables {
+		if table.Name == name {
+			if err := f.DeleteTable(name); err != nil {
+				return err
+			}
+			continue
+		}
+		overlaps, err := rangesOverlap(table.Range, ref)
+		if err != nil {
+			return err
+		}
+		if overlaps {
+			return fmt.Errorf("the data is already in table %q", table.Name)
+		}
+	}
+
+	err = f.AddTable(sheet, &excelize.Table{Range: ref, Name: name, StyleName: style})
+	if err == excelize.ErrExistsTableName {
+		return fmt.Errorf("another sheet already has a table named %q", name)
+	}
+	return err
+}
+
+// rangesOverlap reports whether two cell ranges such as A1:C5 share a cell.
+func rangesOverlap(a, b string) (bool, error) {
+	var coords [2][4]int
+	for i, ref := range []string{a, b} {
+		from, to, ok := strings.Cut(ref, ":")
+		if !ok {
+			to = from
+		}
+		x1, y1, err := excelize.CellNameToCoordinates(from)
+		if err != nil {
+			return false, err
+		}
+		x2, y2, err := excelize.CellNameToCoordinates(to)
+		if err != nil {
+			return false, err
+		}
+		coords[i] = [4]int{min(x1, x2), min(y1, y2), max(x1, x2), max(y1, y2)}
+	}
+	a1, b1 := coords[0], coords[1]
+	return a1[0] <= b1[2] && b1[0] <= a1[2] && a1[1] <= b1[3] && b1[1] <= a1[3], nil
+}
+
+// commentAuthor signs the comments holding original values.
+const commentAuthor = "Chronos"
+
+// originalNotes adds comments holding the values cells had before they
+// were converted in place. Comments the cells already had are kept, with
+// the original value added to the end.
+type originalNotes struct {
+	f        *excelize.File
+	sheet    string
+	existing map[string]excelize.Comment // By cell reference
+}
+
+func newOriginalNotes(f *excelize.File, sheet string) (*originalNotes, error) {
+	comments, err := f.GetComments(sheet)
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]excelize.Comment, len(comments))
+	for _, comment := range comments {
+		existing[comment.Cell] = comment
+	}
+	return &originalNotes{f: f, sheet: sheet, existing: existing}, nil
+}
+
+// add notes the original value of a cell.
+func (n *originalNotes) add(cell, original string) error {
+	note := "Original value: " + original
+	comment, ok := n.existing[cell]
+	if !ok {
+		return n.f.AddComment(n.sheet, excelize.Comment{Cell: cell, Author: commentAuthor, Text: note})
+	}
+
+	if err := n.f.DeleteComment(n.sheet, cell); err != nil {
+		return err
+	}
+	if comment.Text != "" {
+		comment.Paragraph = append([]excelize.RichTextRun{{Text: comment.Text}}, comment.Paragraph...)
+		comment.Text = ""
+	}
+	comment.Paragraph = append(comment.Paragraph, excelize.RichTextRun{Text: "\n" + note})
+	return n.f.AddComment(n.sheet, comment)
+}
+
+// writeWorkbook writes f straight to sink, reporting the bytes written
+// against an estimate of the workbook's size, so it's never held whole in
+// memory. A workbook that fails part way through is discarded, and one
+// written to disk is read back and checked against f, opened with low
+// memory when lowMemory is set.
+func writeWorkbook(f *excelize.File, sink OutputSink, estimate int64, lowMemory bool, progress ProgressReporter) error {
+	out, err := sink.Create()
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	// It's compressed as it's built, so that's what progress follows. The
+	// buffer excelize would compress it into is left empty.
+	sum := newChecksumWriter(out)
+	written := newProgressWriter(sum, estimate, PhaseWrite, progress)
+	f.SetZipWriter(func(io.Writer) excelize.ZipWriter {
+		return zip.NewWriter(written)
+	})
+	defer f.SetZipWriter(func(w io.Writer) excelize.ZipWriter {
+		return zip.NewWriter(w)
+	})
+	if _, err := f.WriteToBuffer(); err != nil {
+		discard(out)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	if path, ok := diskPath(sink); ok {
+		if err := checkXLSXOutput(path, sum, f, lowMemory); err != nil {
+			return err
+		}
+	}
+	written.finish()
+	return nil
+}
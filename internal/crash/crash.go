@@ -0,0 +1,99 @@
+package crash
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// IssuesURL is where crashes are reported.
+const IssuesURL = "https://github.com/nconklindev/chronos/issues"
+
+// restoreSequences leave the alternate screen, show the cursor and stop
+// mouse reporting, undoing what the interface set up.
+const restoreSequences = "\x1b[?1049l\x1b[?25h\x1b[?1002l\x1b[?1003l\x1b[?1006l"
+
+// Info identifies the build that crashed.
+type Info struct {
+	Version string
+	Commit  string
+	Date    string
+}
+
+var (
+	mu sync.Mutex
+	// last is the path of the last crash log written.
+	last string
+)
+
+// Write saves a crash log for a panic in the temp directory and returns its
+// path. The log holds the panic's value and stack, the build and system,
+// and state, a description of what chronos was doing.
+func Write(info Info, value any, stack []byte, state string) (string, error) {
+	f, err := os.CreateTemp("", fmt.Sprintf("chronos-crash-%s-*.log", time.Now().Format("20060102-150405")))
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	if _, err := f.WriteString(Format(info, value, stack, state)); err != nil {
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+
+	mu.Lock()
+	last = f.Name()
+	mu.Unlock()
+	return f.Name(), nil
+}
+
+// Format lays out a crash log.
+func Format(info Info, value any, stack []byte, state string) string {
+	return fmt.Sprintf("chronos %s\ncommit: %s\nbuilt: %s\n\nos: %s/%s\ngo: %s\nterm: %s\ntime: %s\n\npanic: %v\n\n%s\n\nstate:\n%s\n",
+		info.Version, info.Commit, info.Date,
+		runtime.GOOS, runtime.GOARCH, runtime.Version(), os.Getenv("TERM"), time.Now().Format(time.RFC3339),
+		value, stack, state)
+}
+
+// LastLog is the path of the last crash log written, or "" if there's none.
+func LastLog() string {
+	mu.Lock()
+	defer mu.Unlock()
+	return last
+}
+
+// RestoreTerminal undoes the interface's alternate screen, hidden cursor and
+// mouse reporting, when out is a terminal.
+func RestoreTerminal(out *os.File) {
+	if stat, err := out.Stat(); err == nil && stat.Mode()&os.ModeCharDevice != 0 {
+		out.WriteString(restoreSequences)
+	}
+}
+
+// Report tells the user where a crash log was saved, or why it couldn't be.
+func Report(path string, err error) {
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "chronos crashed, and the crash log couldn't be saved: %v\n", err)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "chronos crashed. A crash log was saved to %s\nPlease attach it to a report at %s\n", path, IssuesURL)
+}
+
+// Handle recovers a panic on the main goroutine. Deferred from main, it
+// restores the terminal, writes a crash log and reports where it was
+// saved before exiting with code.
+func Handle(info Info, code int) {
+	value := recover()
+	if value == nil {
+		return
+	}
+	RestoreTerminal(os.Stdout)
+	path, err := Write(info, value, debug.Stack(), "")
+	Report(path, err)
+	os.Exit(code)
+}
@@ -0,0 +1,35 @@
+package crash
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWrite(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("TMPDIR", dir)
+
+	info := Info{Version: "1.2.3", Commit: "abc123", Date: "2026-01-02"}
+	path, err := Write(info, "index out of range", []byte("goroutine 1 [running]:"), "screen: column selection\n")
+	if err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if filepath.Dir(path) != dir || !strings.HasPrefix(filepath.Base(path), "chronos-crash-") {
+		t.Errorf("Expected a chronos-crash log in %s, got %s", dir, path)
+	}
+	if LastLog() != path {
+		t.Errorf("Expected LastLog to be %s, got %s", path, LastLog())
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"chronos 1.2.3", "commit: abc123", "panic: index out of range", "goroutine 1 [running]:", "screen: column selection"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("Expected the log to contain %q, got:\n%s", want, data)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package debuglog
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"sync"
+
+	"github.com/nconklindev/chronos/internal/config"
+)
+
+// EnvVar turns debug logging on like --debug when set to 1 or true.
+const EnvVar = "CHRONOS_DEBUG"
+
+// MaxSize is how large the log grows before it's rotated.
+const MaxSize = 5 << 20
+
+// Backups is how many rotated logs are kept, as chronos.log.1 onward.
+const Backups = 2
+
+// EnabledByEnv reports whether EnvVar asks for debug logging.
+func EnabledByEnv() bool {
+	on, _ := strconv.ParseBool(os.Getenv(EnvVar))
+	return on
+}
+
+var (
+	mu sync.Mutex
+	// log is the open debug log, if Start has been called.
+	log *rotatingFile
+	// previous is the default logger Start replaced.
+	previous *slog.Logger
+)
+
+// Start opens the debug log in the config directory and sends every
+// slog.Debug record to it as JSON, tagged with the build's version.
+// Without it, debug records are dropped.
+func Start(version string) (string, error) {
+	dir, err := config.EnsureDir()
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, config.LogFile)
+	w, err := openRotating(path, MaxSize, Backups)
+	if err != nil {
+		return "", err
+	}
+
+	mu.Lock()
+	if log != nil {
+		log.Close()
+	} else {
+		previous = slog.Default()
+	}
+	log = w
+	mu.Unlock()
+
+	logger := slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	slog.SetDefault(logger.With("version", version))
+	slog.Debug("debug logging started", "os", runtime.GOOS, "arch", runtime.GOARCH, "pid", os.Getpid(), "args", os.Args[1:])
+	return path, nil
+}
+
+// Stop closes the debug log, if it's open, and drops debug records again.
+func Stop() error {
+	mu.Lock()
+	defer mu.Unlock()
+	if log == nil {
+		return nil
+	}
+	slog.SetDefault(previous)
+	err := log.Close()
+	log = nil
+	return err
+}
+
+// rotatingFile appends to a log file, moving it to path.1 (and path.1 to
+// path.2, and so on) once it would grow past max bytes.
+type rotatingFile struct {
+	mu      sync.Mutex
+	path    string
+	max     int64
+	backups int
+	file    *os.File
+	size    int64
+}
+
+// openRotating opens the log at path for appending.
+func openRotating(path string, max int64, backups int) (*rotatingFile, error) {
+	r := &rotatingFile{path: path, max: max, backups: backups}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *rotatingFile) open() error {
+	f, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	r.file, r.size = f, info.Size()
+	return nil
+}
+
+// Write appends p, rotating the log first when p would take it past max.
+// Each record is written whole, so one never straddles two files.
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.size > 0 && r.size+int64(len(p)) > r.max {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// Close closes the log file.
+func (r *rotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file.Close()
+}
+
+// rotate shifts the backups along, dropping the oldest, and starts a new log.
+func (r *rotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return err
+	}
+	os.Remove(fmt.Sprintf("%s.%d", r.path, r.backups))
+	for i := r.backups - 1; i >= 1; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", r.path, i), fmt.Sprintf("%s.%d", r.path, i+1))
+	}
+	if r.backups > 0 {
+		if err := os.Rename(r.path, r.path+".1"); err != nil {
+			return err
+		}
+	} else if err := os.Remove(r.path); err != nil {
+		return err
+	}
+	return r.open()
+}
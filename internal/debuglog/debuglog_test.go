@@ -0,0 +1,68 @@
+package debuglog
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStart(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	path, err := Start("1.2.3")
+	if err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	slog.Debug("state changed", "from", "loading", "to", "column selection")
+	if err := Stop(); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+	// Records after Stop are dropped
+	slog.Debug("after stop")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 records, got %d:\n%s", len(lines), data)
+	}
+	var record map[string]any
+	if err := json.Unmarshal([]byte(lines[1]), &record); err != nil {
+		t.Fatalf("Expected a JSON record, got %q: %v", lines[1], err)
+	}
+	if record["msg"] != "state changed" || record["to"] != "column selection" || record["version"] != "1.2.3" {
+		t.Errorf("Unexpected record: %v", record)
+	}
+}
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "chronos.log")
+	w, err := openRotating(path, 10, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	for _, record := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		if _, err := w.Write([]byte(record)); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+
+	// Each record would take the log past 10 bytes, so each starts a new
+	// file and the oldest falls off the end
+	for name, expected := range map[string]string{"": "fourth\n", ".1": "third\n", ".2": "second\n"} {
+		got, err := os.ReadFile(path + name)
+		if err != nil || string(got) != expected {
+			t.Errorf("chronos.log%s: got %q, %v; want %q", name, got, err, expected)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("Expected only 2 backups, found chronos.log.3")
+	}
+}
@@ -0,0 +1,90 @@
+package demo
+
+import (
+	"embed"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// DirPrefix starts the name of the folder created under the system temp
+// directory for demo files.
+const DirPrefix = "chronos-demo-"
+
+//go:embed samples/*.csv
+var samples embed.FS
+
+// Setup writes the embedded sample exports to a new demo directory and
+// returns its path, for the caller to remove when done. The data is
+// fictional and safe to share in screenshots.
+func Setup() (string, error) {
+	dir, err := os.MkdirTemp("", DirPrefix)
+	if err != nil {
+		return "", err
+	}
+	if err := writeSamples(dir); err != nil {
+		os.RemoveAll(dir)
+		return "", err
+	}
+	return dir, nil
+}
+
+// writeSamples writes the sample exports into dir.
+func writeSamples(dir string) error {
+	entries, err := samples.ReadDir("samples")
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		data, err := samples.ReadFile("samples/" + entry.Name())
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(dir, entry.Name()), data, 0o644); err != nil {
+			return err
+		}
+	}
+
+	return writeXLSXSample(filepath.Join(dir, "payroll_export.xlsx"))
+}
+
+// writeXLSXSample builds a workbook from the timesheet sample with a few
+// report title rows above the headers, like most payroll system exports.
+func writeXLSXSample(path string) error {
+	file, err := samples.Open("samples/timesheet_week42.csv")
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		return err
+	}
+
+	f := excelize.NewFile()
+	defer f.Close()
+
+	sheetName := f.GetSheetName(0)
+	f.SetCellValue(sheetName, "A1", "Acme Logistics - Payroll Export")
+	f.SetCellValue(sheetName, "A2", "Pay Period: 2025-10-13 to 2025-10-17")
+
+	const firstRow = 4
+	for i, record := range records {
+		for j, cell := range record {
+			cellName, _ := excelize.CoordinatesToCellName(j+1, firstRow+i)
+			// Store numbers as numbers so the workbook looks like a real export
+			if num, err := strconv.ParseFloat(cell, 64); err == nil && i > 0 {
+				f.SetCellValue(sheetName, cellName, num)
+			} else {
+				f.SetCellValue(sheetName, cellName, cell)
+			}
+		}
+	}
+
+	return f.SaveAs(path)
+}
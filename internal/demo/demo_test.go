@@ -0,0 +1,45 @@
+package demo
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nconklindev/chronos/internal/converter"
+)
+
+func TestSetup(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+
+	dir, err := Setup()
+	if err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Each run gets its own folder, so another's files are never removed
+	other, err := Setup()
+	if err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+	defer os.RemoveAll(other)
+	if other == dir || !strings.HasPrefix(filepath.Base(dir), DirPrefix) {
+		t.Errorf("Expected separate demo folders, got %s and %s", dir, other)
+	}
+
+	for _, name := range []string{"timesheet_week42.csv", "project_hours.csv", "payroll_export.xlsx"} {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err != nil {
+			t.Fatalf("expected %s to exist: %v", name, err)
+		}
+
+		data, err := converter.ReadFileData(path)
+		if err != nil {
+			t.Fatalf("ReadFileData(%s) failed: %v", name, err)
+		}
+		if len(converter.AutoDetectColumns(data)) == 0 {
+			t.Errorf("expected %s to have auto-detected columns", name)
+		}
+	}
+}
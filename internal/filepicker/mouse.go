@@ -0,0 +1,18 @@
+package filepicker
+
+// FileAt returns the index of the file shown on the given line of the
+// view, counting from 0, and whether a file is shown there.
+func (m Model) FileAt(line int) (int, bool) {
+	i := m.min + line
+	if line < 0 || i > m.max || i >= len(m.files) {
+		return 0, false
+	}
+	return i, true
+}
+
+// Select moves the cursor to the file at index i, as returned by FileAt.
+func (m *Model) Select(i int) {
+	if i >= 0 && i < len(m.files) {
+		m.selected = i
+	}
+}
@@ -0,0 +1,67 @@
+package filepicker
+
+import (
+	"os"
+	"sort"
+)
+
+// SortOrder is how the picker orders the files in a directory.
+type SortOrder string
+
+const (
+	// SortName lists files alphabetically.
+	SortName SortOrder = "name"
+	// SortModified lists the most recently modified files first.
+	SortModified SortOrder = "modified"
+	// SortSize lists the largest files first.
+	SortSize SortOrder = "size"
+)
+
+// SortOrders lists the sort orders in the order they're cycled through.
+var SortOrders = []SortOrder{SortName, SortModified, SortSize}
+
+// Next returns the sort order after o, wrapping around.
+func (o SortOrder) Next() SortOrder {
+	for i, order := range SortOrders {
+		if order == o {
+			return SortOrders[(i+1)%len(SortOrders)]
+		}
+	}
+	return SortOrders[1]
+}
+
+// sortEntries orders entries by the given order, with directories first
+// and ties broken by name. An unknown order sorts by name.
+func sortEntries(entries []os.DirEntry, order SortOrder) {
+	// Look up file info once rather than on every comparison
+	infos := make(map[string]os.FileInfo, len(entries))
+	if order == SortModified || order == SortSize {
+		for _, entry := range entries {
+			if info, err := entry.Info(); err == nil {
+				infos[entry.Name()] = info
+			}
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.IsDir() != b.IsDir() {
+			return a.IsDir()
+		}
+
+		ai, bi := infos[a.Name()], infos[b.Name()]
+		if ai != nil && bi != nil {
+			switch order {
+			case SortModified:
+				if !ai.ModTime().Equal(bi.ModTime()) {
+					return ai.ModTime().After(bi.ModTime())
+				}
+			case SortSize:
+				if ai.Size() != bi.Size() {
+					return ai.Size() > bi.Size()
+				}
+			}
+		}
+		return a.Name() < b.Name()
+	})
+}
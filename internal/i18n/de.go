@@ -0,0 +1,338 @@
+package i18n
+
+// german translates the interface into German.
+var german = map[string]string{
+	// Key help
+	"all keys":                     "alle Tasten",
+	"quit":                         "beenden",
+	"up":                           "hoch",
+	"down":                         "runter",
+	"select file":                  "Datei auswählen",
+	"confirm selection":            "Auswahl bestätigen",
+	"edit selection":               "Auswahl bearbeiten",
+	"remove last file":             "letzte Datei entfernen",
+	"search":                       "suchen",
+	"search subfolders":            "Unterordner durchsuchen",
+	"type a path":                  "Pfad eingeben",
+	"download from URL":            "von URL herunterladen",
+	"sort":                         "sortieren",
+	"hidden files":                 "versteckte Dateien",
+	"repeat last run":              "letzten Lauf wiederholen",
+	"history":                      "Verlauf",
+	"page up":                      "Seite hoch",
+	"page down":                    "Seite runter",
+	"first column":                 "erste Spalte",
+	"last column":                  "letzte Spalte",
+	"toggle":                       "umschalten",
+	"select detected":              "erkannte auswählen",
+	"select all":                   "alle auswählen",
+	"deselect all":                 "Auswahl aufheben",
+	"invert":                       "umkehren",
+	"filter columns":               "Spalten filtern",
+	"clear filter":                 "Filter löschen",
+	"keep original":                "Original behalten",
+	"converted column placement":   "Position der konvertierten Spalte",
+	"output only selected columns": "nur ausgewählte Spalten ausgeben",
+	"keep column in slim output":   "Spalte in schlanker Ausgabe behalten",
+	"column settings":              "Spalteneinstellungen",
+	"reorder columns":              "Spalten umordnen",
+	"move column up (reorder)":     "Spalte nach oben (umordnen)",
+	"move column down (reorder)":   "Spalte nach unten (umordnen)",
+	"more footer rows":             "mehr Fußzeilen",
+	"fewer footer rows":            "weniger Fußzeilen",
+	"drop footer":                  "Fußzeilen entfernen",
+	"require non-empty":            "nicht leer verlangen",
+	"require value":                "Wert verlangen",
+	"output encoding":              "Ausgabekodierung",
+	"line endings (CSV)":           "Zeilenenden (CSV)",
+	"final newline (CSV)":          "abschließender Zeilenumbruch (CSV)",
+	"quoting (CSV)":                "Anführungszeichen (CSV)",
+	"write to a new sheet (XLSX)":  "in ein neues Blatt schreiben (XLSX)",
+	"note original values in comments (XLSX)":    "Originalwerte in Kommentaren vermerken (XLSX)",
+	"wrap in an Excel table (XLSX)":              "in eine Excel-Tabelle einbetten (XLSX)",
+	"flag large values":                          "große Werte markieren",
+	"blanks and zeros":                           "Leerwerte und Nullen",
+	"compute durations from start and end times": "Dauern aus Start- und Endzeiten berechnen",
+	"total hours per employee and period":        "Gesamtstunden pro Mitarbeiter und Zeitraum",
+	"name converted column":                      "konvertierte Spalte benennen",
+	"explain detection":                          "Erkennung erklären",
+	"browse rows":                                "Zeilen durchsehen",
+	"save profile":                               "Profil speichern",
+	"confirm":                                    "bestätigen",
+	"re-run":                                     "erneut ausführen",
+	"back":                                       "zurück",
+	"show/hide details":                          "Details ein-/ausblenden",
+	"show/hide all details":                      "alle Details ein-/ausblenden",
+	"open file":                                  "Datei öffnen",
+	"show in folder":                             "im Ordner zeigen",
+	"copy path":                                  "Pfad kopieren",
+	"save run summary":                           "Zusammenfassung speichern",
+	"export report":                              "Bericht exportieren",
+	"save metadata sidecars":                     "Metadaten-Begleitdateien speichern",
+	"save audit log":                             "Prüfprotokoll speichern",
+	"zip outputs":                                "Ausgaben zippen",
+	"undo":                                       "rückgängig",
+	"convert more files":                         "weitere Dateien konvertieren",
+	"save bug report":                            "Fehlerbericht speichern",
+	"start over":                                 "neu beginnen",
+	"overwrite":                                  "überschreiben",
+	"overwrite the rest":                         "den Rest überschreiben",
+	"rename":                                     "umbenennen",
+	"rename the rest":                            "den Rest umbenennen",
+	"skip":                                       "überspringen",
+	"skip the rest":                              "den Rest überspringen",
+	"reload":                                     "neu laden",
+
+	// Help
+	"⏰ Keyboard Shortcuts": "⏰ Tastenkürzel",
+	"?/esc: close":         "?/esc: schließen",
+
+	// File picker
+	"⏰ Chronos - Decimal to Hour Converter": "⏰ Chronos - Umrechner für Dezimalstunden",
+	"by Nick Conklin":                       "von Nick Conklin",
+	"sorted by name":                        "nach Name sortiert",
+	"newest first":                          "neueste zuerst",
+	"largest first":                         "größte zuerst",
+	", showing hidden files":                ", versteckte Dateien werden angezeigt",
+	"Select up to 3 files to convert":       "Bis zu 3 Dateien zum Konvertieren auswählen",
+	"Selected Files:":                       "Ausgewählte Dateien:",
+	"(%d/3 selected) Select more or press 'Enter' to continue":                                                     "(%d/3 ausgewählt) Weitere auswählen oder 'Enter' drücken, um fortzufahren",
+	"Max files selected. Press 'Enter' to continue.":                                                               "Höchstzahl an Dateien ausgewählt. 'Enter' drücken, um fortzufahren.",
+	"enter: download • esc: cancel":                                                                                "enter: herunterladen • esc: abbrechen",
+	"enter: select matching files or open folder • esc: cancel":                                                    "enter: passende Dateien auswählen oder Ordner öffnen • esc: abbrechen",
+	"enter: resume • esc: discard • q: quit":                                                                       "enter: fortsetzen • esc: verwerfen • q: beenden",
+	"type to filter • ↑/↓: navigate • enter: select file or open folder • ctrl+r: search subfolders • esc: cancel": "tippen zum Filtern • ↑/↓: navigieren • enter: Datei auswählen oder Ordner öffnen • ctrl+r: Unterordner durchsuchen • esc: abbrechen",
+	"Resume the batch you were setting up?":                                                                        "Den begonnenen Stapel fortsetzen?",
+	"↑/↓: navigate • x/Delete: remove • Tab/Esc: back to files • Enter: confirm selection • q: quit":               "↑/↓: navigieren • x/Entf: entfernen • Tab/Esc: zurück zu den Dateien • Enter: Auswahl bestätigen • q: beenden",
+
+	// Column selection
+	"⏰ Select Columns to Convert": "⏰ Zu konvertierende Spalten auswählen",
+	" (from %s)":                  " (aus %s)",
+	"profile %s":                  "Profil %s",
+	"preset %s":                   "Vorlage %s",
+	"File (%d/%d): %s":            "Datei (%d/%d): %s",
+	"✓ Auto-detected %d decimal hour column(s)":                           "✓ %d Spalte(n) mit Dezimalstunden automatisch erkannt",
+	"Viewing %d-%d of %d columns":                                         "Spalten %d-%d von %d",
+	"Viewing %d-%d of %d columns matching %q (of %d) • esc: clear filter": "Spalten %d-%d von %d passend zu %q (von %d) • esc: Filter löschen",
+	"Column settings apply to this column only":                           "Spalteneinstellungen gelten nur für diese Spalte",
+	"Reordering %d columns, listed in output order":                       "%d Spalten werden umgeordnet, in Ausgabereihenfolge",
+	"Why columns were detected (first %d data rows sampled)":              "Warum Spalten erkannt wurden (die ersten %d Datenzeilen wurden geprüft)",
+	"Keep Original Columns: %s":                                           "Originalspalten behalten: %s",
+	"Output Columns: %s":                                                  "Ausgabespalten: %s",
+	"as in file":                                                          "wie in der Datei",
+	"custom":                                                              "benutzerdefiniert",
+	"Column Order: %s":                                                    "Spaltenreihenfolge: %s",
+	"passed through":                                                      "übernommen",
+	"dropped":                                                             "entfernt",
+	"Footer Rows Skipped: %d (%s)":                                        "Übersprungene Fußzeilen: %d (%s)",
+	"Row Filters: %d active":                                              "Zeilenfilter: %d aktiv",
+	"Flag: %s":                                                            "Markieren: %s",
+	"Blanks and Zeros: %s":                                                "Leerwerte und Nullen: %s",
+	"Durations: %s":                                                       "Dauern: %s",
+	"Totals: %s":                                                          "Summen: %s",
+	"same as input":                                                       "wie Eingabe",
+	"Encoding: %s → %s • Lines: %s • Quoting: %s":                         "Kodierung: %s → %s • Zeilen: %s • Anführungszeichen: %s",
+	" (%d short rows padded)":                                             " (%d kurze Zeilen aufgefüllt)",
+	"converted copy":                                                      "konvertierte Kopie",
+	"new %q sheet in this file":                                           "neues Blatt %q in dieser Datei",
+	"new %q sheet in a converted copy":                                    "neues Blatt %q in einer konvertierten Kopie",
+	", in table %q":                                                       ", in Tabelle %q",
+	", original values in comments":                                       ", Originalwerte in Kommentaren",
+	"Output: %s":                                                          "Ausgabe: %s",
+	"type to filter • enter: keep filter • esc: clear filter": "tippen zum Filtern • enter: Filter behalten • esc: Filter löschen",
+	"enter: apply (empty clears) • esc: cancel":               "enter: übernehmen (leer löscht) • esc: abbrechen",
+	"enter: apply (empty resets) • esc: cancel":               "enter: übernehmen (leer setzt zurück) • esc: abbrechen",
+	"enter: save • esc: cancel":                               "enter: speichern • esc: abbrechen",
+	"enter: convert anyway • esc: back to columns":            "enter: trotzdem konvertieren • esc: zurück zu den Spalten",
+	"No columns match %q":                                     "Keine Spalten passen zu %q",
+	" (kept)":                                                 " (behalten)",
+	" (detected)":                                             " (erkannt)",
+	"(empty)":                                                 "(leer)",
+	"sampled: %s":                                             "geprüft: %s",
+	"These files changed after their columns were chosen, such as by a new export:":        "Diese Dateien haben sich nach der Spaltenauswahl geändert, etwa durch einen neuen Export:",
+	"%s: reload and detect columns again • %s: convert anyway • esc: back to columns":      "%s: neu laden und Spalten erneut erkennen • %s: trotzdem konvertieren • esc: zurück zu den Spalten",
+	"These would stop the batch part way through, so it hasn't started:":                   "Dies würde den Stapel mittendrin abbrechen, daher wurde er nicht gestartet:",
+	"enter: check again • esc: back to columns":                                            "enter: erneut prüfen • esc: zurück zu den Spalten",
+	"%s: overwrite • %s: rename • %s: skip • %s: same for the rest • esc: back to columns": "%s: überschreiben • %s: umbenennen • %s: überspringen • %s: ebenso für den Rest • esc: zurück zu den Spalten",
+	"enter: apply preset and continue • esc: choose columns":                               "enter: Vorlage anwenden und fortfahren • esc: Spalten auswählen",
+	"%s: convert • esc: choose columns • %s: quit":                                         "%s: konvertieren • esc: Spalten auswählen • %s: beenden",
+	"Convert %s in %s?":                       "%s in %s konvertieren?",
+	"The converted copy will be saved as %s.": "Die konvertierte Kopie wird als %s gespeichert.",
+	"These columns weren't detected as hours and may be mangled by converting:":                                                                      "Diese Spalten wurden nicht als Stunden erkannt und könnten beim Konvertieren verfälscht werden:",
+	"↑/↓: scroll • x/esc: back to columns • q: quit":                                                                                                 "↑/↓: blättern • x/esc: zurück zu den Spalten • q: beenden",
+	"↑/↓: scroll rows • ←/→: scroll columns • V/esc: back to columns • q: quit":                                                                      "↑/↓: Zeilen blättern • ←/→: Spalten blättern • V/esc: zurück zu den Spalten • q: beenden",
+	"↑/↓: choose column • K/J: move it up/down • r/esc: done • q: quit":                                                                              "↑/↓: Spalte wählen • K/J: nach oben/unten verschieben • r/esc: fertig • q: beenden",
+	"↑/↓: choose setting • ←/→: change • a: apply to all selected • enter: rename, or type a pattern or expression • esc: back to columns • q: quit": "↑/↓: Einstellung wählen • ←/→: ändern • a: auf alle ausgewählten anwenden • enter: umbenennen oder Muster bzw. Ausdruck eingeben • esc: zurück zu den Spalten • q: beenden",
+
+	// Processing, results and errors
+	"Loading file...":               "Datei wird geladen...",
+	"⏰ Processing...":               "⏰ Verarbeitung...",
+	"Converting file %d of %d...":   "Datei %d von %d wird konvertiert...",
+	"Batch: %d%% done":              "Stapel: %d%% erledigt",
+	"✓ Conversion Complete!":        "✓ Konvertierung abgeschlossen!",
+	"Lines %d-%d of %d":             "Zeilen %d-%d von %d",
+	"%s of manual formatting saved": "%s manuelle Formatierung gespart",
+	"%s saved across %d runs":       "%s gespart in %d Läufen",
+	"Export report as c: CSV • j: JSON • m: Markdown • p: PDF • esc: cancel": "Bericht exportieren als c: CSV • j: JSON • m: Markdown • p: PDF • esc: abbrechen",
+	"✗ Error": "✗ Fehler",
+
+	// Plain mode
+	"Chronos %s converts decimal hours to HH:MM in CSV, XLSX and JSON files.":                       "Chronos %s rechnet Dezimalstunden in CSV-, XLSX- und JSON-Dateien in HH:MM um.",
+	"Type each answer and press Enter. An empty answer takes the default in brackets, and q quits.": "Jede Antwort eingeben und Enter drücken. Eine leere Antwort übernimmt den Wert in Klammern, q beendet.",
+	"File to convert, as a path or a link. Leave it empty to quit:":                                 "Zu konvertierende Datei, als Pfad oder Link. Leer lassen zum Beenden:",
+	"Error: %s":                       "Fehler: %s",
+	"Convert another file?":           "Eine weitere Datei konvertieren?",
+	"Type y for yes or n for no [y]:": "j für ja oder n für nein eingeben [j]:",
+	"Type y for yes or n for no [n]:": "j für ja oder n für nein eingeben [n]:",
+	"y":                               "j",
+	"yes":                             "ja",
+	"n":                               "n",
+	"no":                              "nein",
+	"Please answer y or n.":           "Bitte mit j oder n antworten.",
+	"Downloading %s...":               "%s wird heruntergeladen...",
+	"%s is an archive; convert it with \"chronos convert\" instead": "%s ist ein Archiv; stattdessen mit \"chronos convert\" konvertieren",
+	"Reading %s...": "%s wird gelesen...",
+	"%s has %d columns and %d rows, %d of them footer rows.": "%s hat %d Spalten und %d Zeilen, davon %d Fußzeilen.",
+	"Column %d: %s":                                "Spalte %d: %s",
+	", detected as decimal hours":                  ", als Dezimalstunden erkannt",
+	"No columns chosen, so nothing was converted.": "Keine Spalten gewählt, daher wurde nichts konvertiert.",
+	"Convert %s and save the result as %s?":        "%s konvertieren und das Ergebnis als %s speichern?",
+	"Skipped %s.":                                  "%s übersprungen.",
+	"%s already exists. Replace it?":               "%s existiert bereits. Ersetzen?",
+	"Converting...":                                "Wird konvertiert...",
+	"Converted %d values in %d rows.":              "%d Werte in %d Zeilen konvertiert.",
+	"Warning: %s":                                  "Warnung: %s",
+	"Saved as %s":                                  "Gespeichert als %s",
+	"Columns to convert, as numbers or names separated by commas [%s]:":                                       "Zu konvertierende Spalten, als Nummern oder Namen durch Kommas getrennt [%s]:",
+	"No columns were detected as decimal hours. Columns to convert, as numbers or names separated by commas:": "Keine Spalten wurden als Dezimalstunden erkannt. Zu konvertierende Spalten, als Nummern oder Namen durch Kommas getrennt:",
+	"there is no column %d":       "es gibt keine Spalte %d",
+	"there is no column named %q": "es gibt keine Spalte namens %q",
+
+	// Compact layout, history, column settings and results
+	"Terminal too small (%d×%d). Resize to at least %d×%d, or press q to quit.": "Terminal zu klein (%d×%d). Auf mindestens %d×%d vergrößern oder q zum Beenden drücken.",
+	"⏰ Chronos (%d/3)":                                           "⏰ Chronos (%d/3)",
+	"⏎: download • esc: cancel":                                  "⏎: herunterladen • esc: abbrechen",
+	"⏎: select • esc: cancel":                                    "⏎: auswählen • esc: abbrechen",
+	"x: remove • tab: back • ⏎: go":                              "x: entfernen • tab: zurück • ⏎: weiter",
+	"⏎: pick • ^r: subfolders • esc: cancel":                     "⏎: wählen • ^r: Unterordner • esc: abbrechen",
+	"⏎: resume • esc: discard":                                   "⏎: fortsetzen • esc: verwerfen",
+	"⏰ Setup (%d/%d)":                                            "⏰ Einrichtung (%d/%d)",
+	"⏰ Loading...":                                               "⏰ Wird geladen...",
+	"⏰ Recent Conversions":                                       "⏰ Letzte Umwandlungen",
+	"%d/%d %s • %d selected":                                     "%d/%d %s • %d ausgewählt",
+	"⏎: apply • esc: cancel":                                     "⏎: übernehmen • esc: abbrechen",
+	"⏎: save • esc: cancel":                                      "⏎: speichern • esc: abbrechen",
+	"⏎: keep • esc: clear":                                       "⏎: behalten • esc: leeren",
+	"%s: reload • %s: convert anyway • esc: back":                "%s: neu laden • %s: trotzdem umwandeln • esc: zurück",
+	"⏎: check again • esc: back":                                 "⏎: erneut prüfen • esc: zurück",
+	"%s: overwrite/rename/skip • %s: all":                        "%s: überschreiben/umbenennen/überspringen • %s: alle",
+	"⏎: apply preset • esc: choose columns":                      "⏎: Vorlage anwenden • esc: Spalten auswählen",
+	"%s: convert • esc: choose columns":                          "%s: umwandeln • esc: Spalten auswählen",
+	"⏎: convert anyway • esc: back":                              "⏎: trotzdem umwandeln • esc: zurück",
+	"↑/↓: scroll • x: back • q: quit":                            "↑/↓: blättern • x: zurück • q: beenden",
+	"↑/↓: rows • ←/→: columns • V: back":                         "↑/↓: Zeilen • ←/→: Spalten • V: zurück",
+	"K/J: move • r: done":                                        "K/J: verschieben • r: fertig",
+	"↑/↓: choose • ←/→: change • a: all • esc: back":             "↑/↓: wählen • ←/→: ändern • a: alle • esc: zurück",
+	" (batch %d%%)":                                              " (Stapel %d%%)",
+	"✓ %d file(s), %d rows":                                      "✓ %d Datei(en), %d Zeilen",
+	"Re-run a past conversion with the same columns and options": "Eine frühere Umwandlung mit denselben Spalten und Optionen wiederholen",
+	"Input:":          "Eingabe:",
+	"Output:":         "Ausgabe:",
+	"Columns:":        "Spalten:",
+	"Settings for %q": "Einstellungen für %q",
+	"Not selected, so these apply once it is": "Nicht ausgewählt, daher gelten diese erst, wenn sie es ist",
+	"Format:":     "Format:",
+	"Rounding:":   "Rundung:",
+	"Unit:":       "Einheit:",
+	"Decimal:":    "Dezimal:",
+	"Units:":      "Einheiten:",
+	"Adjust:":     "Anpassung:",
+	"Overtime:":   "Überstunden:",
+	"Transform:":  "Umwandlung:",
+	"Name:":       "Name:",
+	"Preview: %s": "Vorschau: %s",
+	"Browsing the first %d of %d rows • columns %d-%d of %d": "Die ersten %d von %d Zeilen • Spalten %d-%d von %d",
+	"Could not save bug report: %v":                          "Fehlerbericht konnte nicht gespeichert werden: %v",
+	"Bug report saved to %s":                                 "Fehlerbericht gespeichert unter %s",
+	"Could not save run summary: %v":                         "Zusammenfassung konnte nicht gespeichert werden: %v",
+	"Run summary saved to %s":                                "Zusammenfassung gespeichert unter %s",
+	"Could not save sidecars: %v":                            "Begleitdateien konnten nicht gespeichert werden: %v",
+	"Sidecars saved next to %d output(s)":                    "Begleitdateien neben %d Ausgabe(n) gespeichert",
+	"Could not save audit log: %v":                           "Prüfprotokoll konnte nicht gespeichert werden: %v",
+	"Audit log saved to %s":                                  "Prüfprotokoll gespeichert unter %s",
+	"Audit log already saved to %s":                          "Prüfprotokoll bereits gespeichert unter %s",
+	"Could not open %s: %v":                                  "%s konnte nicht geöffnet werden: %v",
+	"Opened %s":                                              "%s geöffnet",
+	"Could not copy path: %v":                                "Pfad konnte nicht kopiert werden: %v",
+	"Copied %s":                                              "%s kopiert",
+	"Could not zip outputs: %v":                              "Ausgaben konnten nicht gezippt werden: %v",
+	"No files came from an archive":                          "Keine Datei stammte aus einem Archiv",
+	"Converted files zipped to %s":                           "Umgewandelte Dateien gezippt nach %s",
+	"Could not export report: %v":                            "Bericht konnte nicht exportiert werden: %v",
+	"Report exported to %s":                                  "Bericht exportiert nach %s",
+	"Could not undo: %v":                                     "Rückgängigmachen fehlgeschlagen: %v",
+	"Undone: %d file(s) deleted, %d restored":                "Rückgängig gemacht: %d Datei(en) gelöscht, %d wiederhergestellt",
+
+	// Setup, prompts and statuses
+	"⏰ Welcome to Chronos": "⏰ Willkommen bei Chronos",
+	"Setup %d/%d • everything can be changed later in config.json": "Einrichtung %d/%d • alles lässt sich später in config.json ändern",
+	"Which folder should the file picker open in?":                 "In welchem Ordner soll die Dateiauswahl öffnen?",
+	"Which colors suit your terminal?":                             "Welche Farben passen zum Terminal?",
+	"Keep the decimal hours next to the converted columns?":        "Die Dezimalstunden neben den umgewandelten Spalten behalten?",
+	"How should converted hours be rounded?":                       "Wie sollen umgewandelte Stunden gerundet werden?",
+	"What should be added to the names of converted files?":        "Was soll an die Namen umgewandelter Dateien angehängt werden?",
+	"The keys you'll use most":                                     "Die am häufigsten gebrauchten Tasten",
+	"No, replace them":                                             "Nein, ersetzen",
+	"Yes, keep both":                                               "Ja, beide behalten",
+	"To the nearest minute":                                        "Auf die nächste Minute",
+	"Down to the minute":                                           "Auf die Minute abrunden",
+	"Up to the minute":                                             "Auf die Minute aufrunden",
+	"To the quarter hour (7-minute rule)":                          "Auf die Viertelstunde (7-Minuten-Regel)",
+	"To the tenth of an hour":                                      "Auf die Zehntelstunde",
+	"Folder: ":                                                     "Ordner: ",
+	"empty reopens the last folder used":                           "leer öffnet den zuletzt verwendeten Ordner",
+	"Suffix: ":                                                     "Suffix: ",
+	"timesheet.csv is converted to timesheet%s.csv":                "timesheet.csv wird zu timesheet%s.csv umgewandelt",
+	"%s is not a folder":                                           "%s ist kein Ordner",
+	"the suffix can't contain slashes":                             "das Suffix darf keine Schrägstriche enthalten",
+	"Could not save settings: %v":                                  "Einstellungen konnten nicht gespeichert werden: %v",
+	"enter: next • esc: back":                                      "enter: weiter • esc: zurück",
+	"enter: next • esc: skip setup":                                "enter: weiter • esc: Einrichtung überspringen",
+	"enter: start converting • esc: back":                          "enter: Umwandeln beginnen • esc: zurück",
+	"%s/%s: choose • %s":                                           "%s/%s: wählen • %s",
+	"Picking files":                                                "Dateien auswählen",
+	"Choosing columns":                                             "Spalten wählen",
+	"Results":                                                      "Ergebnisse",
+	"Press %s on any screen for all of its keys.":                  "%s auf jedem Bildschirm drücken, um alle seine Tasten zu sehen.",
+	"Saved %s, %d of %d file(s) configured:":                       "Gespeichert %s, %d von %d Datei(en) eingerichtet:",
+	"Invalid pattern: %v":                                          "Ungültiges Muster: %v",
+	"No CSV, XLSX or archive files match %s":                       "Keine CSV-, XLSX- oder Archivdateien passen zu %s",
+	"Added %d file(s)":                                             "%d Datei(en) hinzugefügt",
+	"Added %d of %d matching files; up to 3 can be selected":       "%d von %d passenden Dateien hinzugefügt; höchstens 3 können ausgewählt werden",
+	"Could not load history: %v":                                   "Verlauf konnte nicht geladen werden: %v",
+	"No conversions in the history yet":                            "Noch keine Umwandlungen im Verlauf",
+	"Reading %s of %s":                                             "%s von %s gelesen",
+	"Converting row %d of %d":                                      "Zeile %d von %d wird umgewandelt",
+	"Writing %s":                                                   "%s geschrieben",
+	"%s already exists.":                                           "%s existiert bereits.",
+	" (%d more after this one)":                                    " (%d weitere nach dieser)",
+	"Overwrite it, write to %s instead, or skip %s?":               "Überschreiben, stattdessen nach %s schreiben oder %s überspringen?",
+	"Every file was skipped":                                       "Alle Dateien wurden übersprungen",
+	"This looks like an export from %s.":                           "Das sieht nach einem Export aus %s aus.",
+	"The %s preset %s.":                                            "Die Vorlage %s %s.",
+	"Reloaded %s; check the columns and press enter":               "%s neu geladen; Spalten prüfen und Enter drücken",
+	"Columns of %s moved or are gone, so they were detected again; check them and press enter": "Spalten von %s wurden verschoben oder fehlen, daher wurden sie neu erkannt; prüfen und Enter drücken",
+	"%d rows":                    "%d Zeilen",
+	", %d warning(s)":            ", %d Warnung(en)",
+	"Totals:":                    "Summen:",
+	"Not a valid http(s) URL":    "Keine gültige http(s)-URL",
+	"Could not save profile: %v": "Profil konnte nicht gespeichert werden: %v",
+	"Saved profile %q":           "Profil %q gespeichert",
+	"Reloading...":               "Wird neu geladen...",
+	"Converted sheets are only for XLSX files":                             "Umgewandelte Blätter gibt es nur für XLSX-Dateien",
+	"Comments are only for XLSX files":                                     "Kommentare gibt es nur für XLSX-Dateien",
+	"Tables are only for XLSX files":                                       "Tabellen gibt es nur für XLSX-Dateien",
+	"No start and end time columns found":                                  "Keine Spalten für Start- und Endzeit gefunden",
+	"No employee and date columns found":                                   "Keine Spalten für Mitarbeiter und Datum gefunden",
+	"No decimal hour columns were detected; choose the columns to convert": "Es wurden keine Dezimalstunden-Spalten erkannt; umzuwandelnde Spalten auswählen",
+}
@@ -0,0 +1,338 @@
+package i18n
+
+// spanish translates the interface into Spanish.
+var spanish = map[string]string{
+	// Key help
+	"all keys":                     "todas las teclas",
+	"quit":                         "salir",
+	"up":                           "arriba",
+	"down":                         "abajo",
+	"select file":                  "seleccionar archivo",
+	"confirm selection":            "confirmar selección",
+	"edit selection":               "editar selección",
+	"remove last file":             "quitar el último archivo",
+	"search":                       "buscar",
+	"search subfolders":            "buscar en subcarpetas",
+	"type a path":                  "escribir una ruta",
+	"download from URL":            "descargar desde URL",
+	"sort":                         "ordenar",
+	"hidden files":                 "archivos ocultos",
+	"repeat last run":              "repetir la última ejecución",
+	"history":                      "historial",
+	"page up":                      "página anterior",
+	"page down":                    "página siguiente",
+	"first column":                 "primera columna",
+	"last column":                  "última columna",
+	"toggle":                       "marcar/desmarcar",
+	"select detected":              "seleccionar detectadas",
+	"select all":                   "seleccionar todas",
+	"deselect all":                 "deseleccionar todas",
+	"invert":                       "invertir",
+	"filter columns":               "filtrar columnas",
+	"clear filter":                 "borrar filtro",
+	"keep original":                "conservar original",
+	"converted column placement":   "posición de la columna convertida",
+	"output only selected columns": "exportar solo las columnas seleccionadas",
+	"keep column in slim output":   "conservar la columna en la salida reducida",
+	"column settings":              "ajustes de columna",
+	"reorder columns":              "reordenar columnas",
+	"move column up (reorder)":     "subir columna (reordenar)",
+	"move column down (reorder)":   "bajar columna (reordenar)",
+	"more footer rows":             "más filas de pie",
+	"fewer footer rows":            "menos filas de pie",
+	"drop footer":                  "eliminar pie",
+	"require non-empty":            "exigir no vacío",
+	"require value":                "exigir valor",
+	"output encoding":              "codificación de salida",
+	"line endings (CSV)":           "finales de línea (CSV)",
+	"final newline (CSV)":          "salto de línea final (CSV)",
+	"quoting (CSV)":                "comillas (CSV)",
+	"write to a new sheet (XLSX)":  "escribir en una hoja nueva (XLSX)",
+	"note original values in comments (XLSX)":    "anotar los valores originales en comentarios (XLSX)",
+	"wrap in an Excel table (XLSX)":              "envolver en una tabla de Excel (XLSX)",
+	"flag large values":                          "marcar valores grandes",
+	"blanks and zeros":                           "vacíos y ceros",
+	"compute durations from start and end times": "calcular duraciones a partir de horas de inicio y fin",
+	"total hours per employee and period":        "total de horas por empleado y periodo",
+	"name converted column":                      "nombrar la columna convertida",
+	"explain detection":                          "explicar la detección",
+	"browse rows":                                "explorar filas",
+	"save profile":                               "guardar perfil",
+	"confirm":                                    "confirmar",
+	"re-run":                                     "volver a ejecutar",
+	"back":                                       "atrás",
+	"show/hide details":                          "mostrar/ocultar detalles",
+	"show/hide all details":                      "mostrar/ocultar todos los detalles",
+	"open file":                                  "abrir archivo",
+	"show in folder":                             "mostrar en la carpeta",
+	"copy path":                                  "copiar ruta",
+	"save run summary":                           "guardar resumen de la ejecución",
+	"export report":                              "exportar informe",
+	"save metadata sidecars":                     "guardar archivos de metadatos",
+	"save audit log":                             "guardar registro de auditoría",
+	"zip outputs":                                "comprimir salidas en zip",
+	"undo":                                       "deshacer",
+	"convert more files":                         "convertir más archivos",
+	"save bug report":                            "guardar informe de error",
+	"start over":                                 "empezar de nuevo",
+	"overwrite":                                  "sobrescribir",
+	"overwrite the rest":                         "sobrescribir el resto",
+	"rename":                                     "renombrar",
+	"rename the rest":                            "renombrar el resto",
+	"skip":                                       "omitir",
+	"skip the rest":                              "omitir el resto",
+	"reload":                                     "recargar",
+
+	// Help
+	"⏰ Keyboard Shortcuts": "⏰ Atajos de teclado",
+	"?/esc: close":         "?/esc: cerrar",
+
+	// File picker
+	"⏰ Chronos - Decimal to Hour Converter": "⏰ Chronos - Conversor de horas decimales",
+	"by Nick Conklin":                       "por Nick Conklin",
+	"sorted by name":                        "ordenados por nombre",
+	"newest first":                          "más recientes primero",
+	"largest first":                         "más grandes primero",
+	", showing hidden files":                ", mostrando archivos ocultos",
+	"Select up to 3 files to convert":       "Selecciona hasta 3 archivos para convertir",
+	"Selected Files:":                       "Archivos seleccionados:",
+	"(%d/3 selected) Select more or press 'Enter' to continue":                                                     "(%d/3 seleccionados) Selecciona más o pulsa 'Enter' para continuar",
+	"Max files selected. Press 'Enter' to continue.":                                                               "Máximo de archivos seleccionados. Pulsa 'Enter' para continuar.",
+	"enter: download • esc: cancel":                                                                                "enter: descargar • esc: cancelar",
+	"enter: select matching files or open folder • esc: cancel":                                                    "enter: seleccionar los archivos coincidentes o abrir la carpeta • esc: cancelar",
+	"enter: resume • esc: discard • q: quit":                                                                       "enter: reanudar • esc: descartar • q: salir",
+	"type to filter • ↑/↓: navigate • enter: select file or open folder • ctrl+r: search subfolders • esc: cancel": "escribe para filtrar • ↑/↓: navegar • enter: seleccionar archivo o abrir carpeta • ctrl+r: buscar en subcarpetas • esc: cancelar",
+	"Resume the batch you were setting up?":                                                                        "¿Reanudar el lote que estabas preparando?",
+	"↑/↓: navigate • x/Delete: remove • Tab/Esc: back to files • Enter: confirm selection • q: quit":               "↑/↓: navegar • x/Supr: quitar • Tab/Esc: volver a los archivos • Enter: confirmar selección • q: salir",
+
+	// Column selection
+	"⏰ Select Columns to Convert": "⏰ Selecciona las columnas a convertir",
+	" (from %s)":                  " (de %s)",
+	"profile %s":                  "perfil %s",
+	"preset %s":                   "preajuste %s",
+	"File (%d/%d): %s":            "Archivo (%d/%d): %s",
+	"✓ Auto-detected %d decimal hour column(s)":                           "✓ %d columna(s) de horas decimales detectada(s)",
+	"Viewing %d-%d of %d columns":                                         "Mostrando %d-%d de %d columnas",
+	"Viewing %d-%d of %d columns matching %q (of %d) • esc: clear filter": "Mostrando %d-%d de %d columnas que coinciden con %q (de %d) • esc: borrar filtro",
+	"Column settings apply to this column only":                           "Los ajustes de columna se aplican solo a esta columna",
+	"Reordering %d columns, listed in output order":                       "Reordenando %d columnas, en el orden de salida",
+	"Why columns were detected (first %d data rows sampled)":              "Por qué se detectaron las columnas (se muestrearon las primeras %d filas de datos)",
+	"Keep Original Columns: %s":                                           "Conservar columnas originales: %s",
+	"Output Columns: %s":                                                  "Columnas de salida: %s",
+	"as in file":                                                          "como en el archivo",
+	"custom":                                                              "personalizado",
+	"Column Order: %s":                                                    "Orden de columnas: %s",
+	"passed through":                                                      "se conservan",
+	"dropped":                                                             "se eliminan",
+	"Footer Rows Skipped: %d (%s)":                                        "Filas de pie omitidas: %d (%s)",
+	"Row Filters: %d active":                                              "Filtros de filas: %d activos",
+	"Flag: %s":                                                            "Marcar: %s",
+	"Blanks and Zeros: %s":                                                "Vacíos y ceros: %s",
+	"Durations: %s":                                                       "Duraciones: %s",
+	"Totals: %s":                                                          "Totales: %s",
+	"same as input":                                                       "igual que la entrada",
+	"Encoding: %s → %s • Lines: %s • Quoting: %s":                         "Codificación: %s → %s • Líneas: %s • Comillas: %s",
+	" (%d short rows padded)":                                             " (%d filas cortas completadas)",
+	"converted copy":                                                      "copia convertida",
+	"new %q sheet in this file":                                           "nueva hoja %q en este archivo",
+	"new %q sheet in a converted copy":                                    "nueva hoja %q en una copia convertida",
+	", in table %q":                                                       ", en la tabla %q",
+	", original values in comments":                                       ", valores originales en comentarios",
+	"Output: %s":                                                          "Salida: %s",
+	"type to filter • enter: keep filter • esc: clear filter": "escribe para filtrar • enter: mantener filtro • esc: borrar filtro",
+	"enter: apply (empty clears) • esc: cancel":               "enter: aplicar (vacío lo borra) • esc: cancelar",
+	"enter: apply (empty resets) • esc: cancel":               "enter: aplicar (vacío lo restablece) • esc: cancelar",
+	"enter: save • esc: cancel":                               "enter: guardar • esc: cancelar",
+	"enter: convert anyway • esc: back to columns":            "enter: convertir de todos modos • esc: volver a las columnas",
+	"No columns match %q":                                     "Ninguna columna coincide con %q",
+	" (kept)":                                                 " (conservada)",
+	" (detected)":                                             " (detectada)",
+	"(empty)":                                                 "(vacío)",
+	"sampled: %s":                                             "muestreado: %s",
+	"These files changed after their columns were chosen, such as by a new export:":        "Estos archivos cambiaron después de elegir sus columnas, por ejemplo por una nueva exportación:",
+	"%s: reload and detect columns again • %s: convert anyway • esc: back to columns":      "%s: recargar y detectar las columnas de nuevo • %s: convertir de todos modos • esc: volver a las columnas",
+	"These would stop the batch part way through, so it hasn't started:":                   "Esto detendría el lote a medio camino, así que no se ha iniciado:",
+	"enter: check again • esc: back to columns":                                            "enter: comprobar de nuevo • esc: volver a las columnas",
+	"%s: overwrite • %s: rename • %s: skip • %s: same for the rest • esc: back to columns": "%s: sobrescribir • %s: renombrar • %s: omitir • %s: igual para el resto • esc: volver a las columnas",
+	"enter: apply preset and continue • esc: choose columns":                               "enter: aplicar el preajuste y continuar • esc: elegir columnas",
+	"%s: convert • esc: choose columns • %s: quit":                                         "%s: convertir • esc: elegir columnas • %s: salir",
+	"Convert %s in %s?":                       "¿Convertir %s en %s?",
+	"The converted copy will be saved as %s.": "La copia convertida se guardará como %s.",
+	"These columns weren't detected as hours and may be mangled by converting:":                                                                      "Estas columnas no se detectaron como horas y la conversión podría estropearlas:",
+	"↑/↓: scroll • x/esc: back to columns • q: quit":                                                                                                 "↑/↓: desplazar • x/esc: volver a las columnas • q: salir",
+	"↑/↓: scroll rows • ←/→: scroll columns • V/esc: back to columns • q: quit":                                                                      "↑/↓: desplazar filas • ←/→: desplazar columnas • V/esc: volver a las columnas • q: salir",
+	"↑/↓: choose column • K/J: move it up/down • r/esc: done • q: quit":                                                                              "↑/↓: elegir columna • K/J: subirla/bajarla • r/esc: listo • q: salir",
+	"↑/↓: choose setting • ←/→: change • a: apply to all selected • enter: rename, or type a pattern or expression • esc: back to columns • q: quit": "↑/↓: elegir ajuste • ←/→: cambiar • a: aplicar a todas las seleccionadas • enter: renombrar, o escribir un patrón o expresión • esc: volver a las columnas • q: salir",
+
+	// Processing, results and errors
+	"Loading file...":               "Cargando archivo...",
+	"⏰ Processing...":               "⏰ Procesando...",
+	"Converting file %d of %d...":   "Convirtiendo archivo %d de %d...",
+	"Batch: %d%% done":              "Lote: %d%% completado",
+	"✓ Conversion Complete!":        "✓ ¡Conversión completada!",
+	"Lines %d-%d of %d":             "Líneas %d-%d de %d",
+	"%s of manual formatting saved": "%s de formato manual ahorrados",
+	"%s saved across %d runs":       "%s ahorrados en %d ejecuciones",
+	"Export report as c: CSV • j: JSON • m: Markdown • p: PDF • esc: cancel": "Exportar informe como c: CSV • j: JSON • m: Markdown • p: PDF • esc: cancelar",
+	"✗ Error": "✗ Error",
+
+	// Plain mode
+	"Chronos %s converts decimal hours to HH:MM in CSV, XLSX and JSON files.":                       "Chronos %s convierte horas decimales a HH:MM en archivos CSV, XLSX y JSON.",
+	"Type each answer and press Enter. An empty answer takes the default in brackets, and q quits.": "Escribe cada respuesta y pulsa Enter. Una respuesta vacía toma el valor entre corchetes, y q sale.",
+	"File to convert, as a path or a link. Leave it empty to quit:":                                 "Archivo a convertir, como ruta o enlace. Déjalo vacío para salir:",
+	"Error: %s":                       "Error: %s",
+	"Convert another file?":           "¿Convertir otro archivo?",
+	"Type y for yes or n for no [y]:": "Escribe s para sí o n para no [s]:",
+	"Type y for yes or n for no [n]:": "Escribe s para sí o n para no [n]:",
+	"y":                               "s",
+	"yes":                             "sí",
+	"n":                               "n",
+	"no":                              "no",
+	"Please answer y or n.":           "Responde s o n.",
+	"Downloading %s...":               "Descargando %s...",
+	"%s is an archive; convert it with \"chronos convert\" instead": "%s es un archivo comprimido; conviértelo con \"chronos convert\"",
+	"Reading %s...": "Leyendo %s...",
+	"%s has %d columns and %d rows, %d of them footer rows.": "%s tiene %d columnas y %d filas, %d de ellas de pie.",
+	"Column %d: %s":                                "Columna %d: %s",
+	", detected as decimal hours":                  ", detectada como horas decimales",
+	"No columns chosen, so nothing was converted.": "No se eligió ninguna columna, así que no se convirtió nada.",
+	"Convert %s and save the result as %s?":        "¿Convertir %s y guardar el resultado como %s?",
+	"Skipped %s.":                                  "Se omitió %s.",
+	"%s already exists. Replace it?":               "%s ya existe. ¿Reemplazarlo?",
+	"Converting...":                                "Convirtiendo...",
+	"Converted %d values in %d rows.":              "Se convirtieron %d valores en %d filas.",
+	"Warning: %s":                                  "Advertencia: %s",
+	"Saved as %s":                                  "Guardado como %s",
+	"Columns to convert, as numbers or names separated by commas [%s]:":                                       "Columnas a convertir, como números o nombres separados por comas [%s]:",
+	"No columns were detected as decimal hours. Columns to convert, as numbers or names separated by commas:": "No se detectó ninguna columna de horas decimales. Columnas a convertir, como números o nombres separados por comas:",
+	"there is no column %d":       "no existe la columna %d",
+	"there is no column named %q": "no existe ninguna columna llamada %q",
+
+	// Compact layout, history, column settings and results
+	"Terminal too small (%d×%d). Resize to at least %d×%d, or press q to quit.": "Terminal demasiado pequeña (%d×%d). Amplíala a %d×%d como mínimo o pulsa q para salir.",
+	"⏰ Chronos (%d/3)":                                           "⏰ Chronos (%d/3)",
+	"⏎: download • esc: cancel":                                  "⏎: descargar • esc: cancelar",
+	"⏎: select • esc: cancel":                                    "⏎: seleccionar • esc: cancelar",
+	"x: remove • tab: back • ⏎: go":                              "x: quitar • tab: volver • ⏎: continuar",
+	"⏎: pick • ^r: subfolders • esc: cancel":                     "⏎: elegir • ^r: subcarpetas • esc: cancelar",
+	"⏎: resume • esc: discard":                                   "⏎: reanudar • esc: descartar",
+	"⏰ Setup (%d/%d)":                                            "⏰ Configuración (%d/%d)",
+	"⏰ Loading...":                                               "⏰ Cargando...",
+	"⏰ Recent Conversions":                                       "⏰ Conversiones recientes",
+	"%d/%d %s • %d selected":                                     "%d/%d %s • %d seleccionadas",
+	"⏎: apply • esc: cancel":                                     "⏎: aplicar • esc: cancelar",
+	"⏎: save • esc: cancel":                                      "⏎: guardar • esc: cancelar",
+	"⏎: keep • esc: clear":                                       "⏎: conservar • esc: borrar",
+	"%s: reload • %s: convert anyway • esc: back":                "%s: recargar • %s: convertir de todos modos • esc: volver",
+	"⏎: check again • esc: back":                                 "⏎: volver a comprobar • esc: volver",
+	"%s: overwrite/rename/skip • %s: all":                        "%s: sobrescribir/renombrar/omitir • %s: todos",
+	"⏎: apply preset • esc: choose columns":                      "⏎: aplicar el preajuste • esc: elegir columnas",
+	"%s: convert • esc: choose columns":                          "%s: convertir • esc: elegir columnas",
+	"⏎: convert anyway • esc: back":                              "⏎: convertir de todos modos • esc: volver",
+	"↑/↓: scroll • x: back • q: quit":                            "↑/↓: desplazar • x: volver • q: salir",
+	"↑/↓: rows • ←/→: columns • V: back":                         "↑/↓: filas • ←/→: columnas • V: volver",
+	"K/J: move • r: done":                                        "K/J: mover • r: listo",
+	"↑/↓: choose • ←/→: change • a: all • esc: back":             "↑/↓: elegir • ←/→: cambiar • a: todas • esc: volver",
+	" (batch %d%%)":                                              " (lote %d%%)",
+	"✓ %d file(s), %d rows":                                      "✓ %d archivo(s), %d filas",
+	"Re-run a past conversion with the same columns and options": "Repite una conversión anterior con las mismas columnas y opciones",
+	"Input:":          "Entrada:",
+	"Output:":         "Salida:",
+	"Columns:":        "Columnas:",
+	"Settings for %q": "Ajustes de %q",
+	"Not selected, so these apply once it is": "No está seleccionada, así que se aplicarán cuando lo esté",
+	"Format:":     "Formato:",
+	"Rounding:":   "Redondeo:",
+	"Unit:":       "Unidad:",
+	"Decimal:":    "Decimal:",
+	"Units:":      "Unidades:",
+	"Adjust:":     "Ajuste:",
+	"Overtime:":   "Horas extra:",
+	"Transform:":  "Transformación:",
+	"Name:":       "Nombre:",
+	"Preview: %s": "Vista previa: %s",
+	"Browsing the first %d of %d rows • columns %d-%d of %d": "Explorando las primeras %d de %d filas • columnas %d-%d de %d",
+	"Could not save bug report: %v":                          "No se pudo guardar el informe de error: %v",
+	"Bug report saved to %s":                                 "Informe de error guardado en %s",
+	"Could not save run summary: %v":                         "No se pudo guardar el resumen de la ejecución: %v",
+	"Run summary saved to %s":                                "Resumen de la ejecución guardado en %s",
+	"Could not save sidecars: %v":                            "No se pudieron guardar los archivos de metadatos: %v",
+	"Sidecars saved next to %d output(s)":                    "Archivos de metadatos guardados junto a %d resultado(s)",
+	"Could not save audit log: %v":                           "No se pudo guardar el registro de auditoría: %v",
+	"Audit log saved to %s":                                  "Registro de auditoría guardado en %s",
+	"Audit log already saved to %s":                          "Registro de auditoría ya guardado en %s",
+	"Could not open %s: %v":                                  "No se pudo abrir %s: %v",
+	"Opened %s":                                              "Se abrió %s",
+	"Could not copy path: %v":                                "No se pudo copiar la ruta: %v",
+	"Copied %s":                                              "Se copió %s",
+	"Could not zip outputs: %v":                              "No se pudieron comprimir los resultados: %v",
+	"No files came from an archive":                          "Ningún archivo venía de un archivo comprimido",
+	"Converted files zipped to %s":                           "Archivos convertidos comprimidos en %s",
+	"Could not export report: %v":                            "No se pudo exportar el informe: %v",
+	"Report exported to %s":                                  "Informe exportado a %s",
+	"Could not undo: %v":                                     "No se pudo deshacer: %v",
+	"Undone: %d file(s) deleted, %d restored":                "Deshecho: %d archivo(s) eliminados, %d restaurados",
+
+	// Setup, prompts and statuses
+	"⏰ Welcome to Chronos": "⏰ Bienvenido a Chronos",
+	"Setup %d/%d • everything can be changed later in config.json": "Configuración %d/%d • todo se puede cambiar más tarde en config.json",
+	"Which folder should the file picker open in?":                 "¿En qué carpeta debe abrirse el selector de archivos?",
+	"Which colors suit your terminal?":                             "¿Qué colores van con tu terminal?",
+	"Keep the decimal hours next to the converted columns?":        "¿Conservar las horas decimales junto a las columnas convertidas?",
+	"How should converted hours be rounded?":                       "¿Cómo se deben redondear las horas convertidas?",
+	"What should be added to the names of converted files?":        "¿Qué se debe añadir al nombre de los archivos convertidos?",
+	"The keys you'll use most":                                     "Las teclas que más usarás",
+	"No, replace them":                                             "No, reemplazarlas",
+	"Yes, keep both":                                               "Sí, conservar ambas",
+	"To the nearest minute":                                        "Al minuto más cercano",
+	"Down to the minute":                                           "Hacia abajo al minuto",
+	"Up to the minute":                                             "Hacia arriba al minuto",
+	"To the quarter hour (7-minute rule)":                          "Al cuarto de hora (regla de los 7 minutos)",
+	"To the tenth of an hour":                                      "A la décima de hora",
+	"Folder: ":                                                     "Carpeta: ",
+	"empty reopens the last folder used":                           "vacío reabre la última carpeta usada",
+	"Suffix: ":                                                     "Sufijo: ",
+	"timesheet.csv is converted to timesheet%s.csv":                "timesheet.csv se convierte en timesheet%s.csv",
+	"%s is not a folder":                                           "%s no es una carpeta",
+	"the suffix can't contain slashes":                             "el sufijo no puede contener barras",
+	"Could not save settings: %v":                                  "No se pudieron guardar los ajustes: %v",
+	"enter: next • esc: back":                                      "enter: siguiente • esc: volver",
+	"enter: next • esc: skip setup":                                "enter: siguiente • esc: omitir la configuración",
+	"enter: start converting • esc: back":                          "enter: empezar a convertir • esc: volver",
+	"%s/%s: choose • %s":                                           "%s/%s: elegir • %s",
+	"Picking files":                                                "Elegir archivos",
+	"Choosing columns":                                             "Elegir columnas",
+	"Results":                                                      "Resultados",
+	"Press %s on any screen for all of its keys.":                  "Pulsa %s en cualquier pantalla para ver todas sus teclas.",
+	"Saved %s, %d of %d file(s) configured:":                       "Guardado %s, %d de %d archivo(s) configurado(s):",
+	"Invalid pattern: %v":                                          "Patrón no válido: %v",
+	"No CSV, XLSX or archive files match %s":                       "Ningún archivo CSV, XLSX o comprimido coincide con %s",
+	"Added %d file(s)":                                             "Se añadieron %d archivo(s)",
+	"Added %d of %d matching files; up to 3 can be selected":       "Se añadieron %d de %d archivos coincidentes; se pueden seleccionar hasta 3",
+	"Could not load history: %v":                                   "No se pudo cargar el historial: %v",
+	"No conversions in the history yet":                            "Todavía no hay conversiones en el historial",
+	"Reading %s of %s":                                             "Leyendo %s de %s",
+	"Converting row %d of %d":                                      "Convirtiendo la fila %d de %d",
+	"Writing %s":                                                   "Escribiendo %s",
+	"%s already exists.":                                           "%s ya existe.",
+	" (%d more after this one)":                                    " (%d más después de este)",
+	"Overwrite it, write to %s instead, or skip %s?":               "¿Sobrescribirlo, escribir en %s en su lugar u omitir %s?",
+	"Every file was skipped":                                       "Se omitieron todos los archivos",
+	"This looks like an export from %s.":                           "Parece una exportación de %s.",
+	"The %s preset %s.":                                            "El preajuste %s %s.",
+	"Reloaded %s; check the columns and press enter":               "Se recargó %s; revisa las columnas y pulsa enter",
+	"Columns of %s moved or are gone, so they were detected again; check them and press enter": "Las columnas de %s se movieron o ya no están, así que se detectaron de nuevo; revísalas y pulsa enter",
+	"%d rows":                    "%d filas",
+	", %d warning(s)":            ", %d advertencia(s)",
+	"Totals:":                    "Totales:",
+	"Not a valid http(s) URL":    "No es una URL http(s) válida",
+	"Could not save profile: %v": "No se pudo guardar el perfil: %v",
+	"Saved profile %q":           "Perfil %q guardado",
+	"Reloading...":               "Recargando...",
+	"Converted sheets are only for XLSX files":                             "Las hojas convertidas son solo para archivos XLSX",
+	"Comments are only for XLSX files":                                     "Los comentarios son solo para archivos XLSX",
+	"Tables are only for XLSX files":                                       "Las tablas son solo para archivos XLSX",
+	"No start and end time columns found":                                  "No se encontraron columnas de hora de inicio y fin",
+	"No employee and date columns found":                                   "No se encontraron columnas de empleado y fecha",
+	"No decimal hour columns were detected; choose the columns to convert": "No se detectó ninguna columna de horas decimales; elige las columnas a convertir",
+}
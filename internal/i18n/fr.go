@@ -0,0 +1,338 @@
+package i18n
+
+// french translates the interface into French.
+var french = map[string]string{
+	// Key help
+	"all keys":                     "toutes les touches",
+	"quit":                         "quitter",
+	"up":                           "haut",
+	"down":                         "bas",
+	"select file":                  "sélectionner le fichier",
+	"confirm selection":            "confirmer la sélection",
+	"edit selection":               "modifier la sélection",
+	"remove last file":             "retirer le dernier fichier",
+	"search":                       "rechercher",
+	"search subfolders":            "rechercher dans les sous-dossiers",
+	"type a path":                  "saisir un chemin",
+	"download from URL":            "télécharger depuis une URL",
+	"sort":                         "trier",
+	"hidden files":                 "fichiers cachés",
+	"repeat last run":              "répéter la dernière exécution",
+	"history":                      "historique",
+	"page up":                      "page précédente",
+	"page down":                    "page suivante",
+	"first column":                 "première colonne",
+	"last column":                  "dernière colonne",
+	"toggle":                       "cocher/décocher",
+	"select detected":              "sélectionner les détectées",
+	"select all":                   "tout sélectionner",
+	"deselect all":                 "tout désélectionner",
+	"invert":                       "inverser",
+	"filter columns":               "filtrer les colonnes",
+	"clear filter":                 "effacer le filtre",
+	"keep original":                "conserver l'original",
+	"converted column placement":   "position de la colonne convertie",
+	"output only selected columns": "n'exporter que les colonnes sélectionnées",
+	"keep column in slim output":   "garder la colonne dans la sortie allégée",
+	"column settings":              "réglages de la colonne",
+	"reorder columns":              "réordonner les colonnes",
+	"move column up (reorder)":     "monter la colonne (réordonner)",
+	"move column down (reorder)":   "descendre la colonne (réordonner)",
+	"more footer rows":             "plus de lignes de pied",
+	"fewer footer rows":            "moins de lignes de pied",
+	"drop footer":                  "supprimer le pied",
+	"require non-empty":            "exiger une valeur non vide",
+	"require value":                "exiger une valeur",
+	"output encoding":              "encodage de sortie",
+	"line endings (CSV)":           "fins de ligne (CSV)",
+	"final newline (CSV)":          "saut de ligne final (CSV)",
+	"quoting (CSV)":                "guillemets (CSV)",
+	"write to a new sheet (XLSX)":  "écrire dans une nouvelle feuille (XLSX)",
+	"note original values in comments (XLSX)":    "noter les valeurs d'origine en commentaires (XLSX)",
+	"wrap in an Excel table (XLSX)":              "placer dans un tableau Excel (XLSX)",
+	"flag large values":                          "signaler les grandes valeurs",
+	"blanks and zeros":                           "vides et zéros",
+	"compute durations from start and end times": "calculer les durées à partir des heures de début et de fin",
+	"total hours per employee and period":        "total d'heures par employé et par période",
+	"name converted column":                      "nommer la colonne convertie",
+	"explain detection":                          "expliquer la détection",
+	"browse rows":                                "parcourir les lignes",
+	"save profile":                               "enregistrer le profil",
+	"confirm":                                    "confirmer",
+	"re-run":                                     "relancer",
+	"back":                                       "retour",
+	"show/hide details":                          "afficher/masquer les détails",
+	"show/hide all details":                      "afficher/masquer tous les détails",
+	"open file":                                  "ouvrir le fichier",
+	"show in folder":                             "afficher dans le dossier",
+	"copy path":                                  "copier le chemin",
+	"save run summary":                           "enregistrer le résumé",
+	"export report":                              "exporter le rapport",
+	"save metadata sidecars":                     "enregistrer les fichiers de métadonnées",
+	"save audit log":                             "enregistrer le journal d'audit",
+	"zip outputs":                                "compresser les sorties en zip",
+	"undo":                                       "annuler",
+	"convert more files":                         "convertir d'autres fichiers",
+	"save bug report":                            "enregistrer un rapport de bogue",
+	"start over":                                 "recommencer",
+	"overwrite":                                  "écraser",
+	"overwrite the rest":                         "écraser les suivants",
+	"rename":                                     "renommer",
+	"rename the rest":                            "renommer les suivants",
+	"skip":                                       "ignorer",
+	"skip the rest":                              "ignorer les suivants",
+	"reload":                                     "recharger",
+
+	// Help
+	"⏰ Keyboard Shortcuts": "⏰ Raccourcis clavier",
+	"?/esc: close":         "?/échap : fermer",
+
+	// File picker
+	"⏰ Chronos - Decimal to Hour Converter": "⏰ Chronos - Convertisseur d'heures décimales",
+	"by Nick Conklin":                       "par Nick Conklin",
+	"sorted by name":                        "triés par nom",
+	"newest first":                          "plus récents d'abord",
+	"largest first":                         "plus volumineux d'abord",
+	", showing hidden files":                ", fichiers cachés affichés",
+	"Select up to 3 files to convert":       "Sélectionnez jusqu'à 3 fichiers à convertir",
+	"Selected Files:":                       "Fichiers sélectionnés :",
+	"(%d/3 selected) Select more or press 'Enter' to continue":                                                     "(%d/3 sélectionnés) Sélectionnez-en d'autres ou appuyez sur 'Entrée' pour continuer",
+	"Max files selected. Press 'Enter' to continue.":                                                               "Nombre maximal de fichiers atteint. Appuyez sur 'Entrée' pour continuer.",
+	"enter: download • esc: cancel":                                                                                "entrée : télécharger • échap : annuler",
+	"enter: select matching files or open folder • esc: cancel":                                                    "entrée : sélectionner les fichiers correspondants ou ouvrir le dossier • échap : annuler",
+	"enter: resume • esc: discard • q: quit":                                                                       "entrée : reprendre • échap : abandonner • q : quitter",
+	"type to filter • ↑/↓: navigate • enter: select file or open folder • ctrl+r: search subfolders • esc: cancel": "tapez pour filtrer • ↑/↓ : naviguer • entrée : sélectionner le fichier ou ouvrir le dossier • ctrl+r : rechercher dans les sous-dossiers • échap : annuler",
+	"Resume the batch you were setting up?":                                                                        "Reprendre le lot que vous prépariez ?",
+	"↑/↓: navigate • x/Delete: remove • Tab/Esc: back to files • Enter: confirm selection • q: quit":               "↑/↓ : naviguer • x/Suppr : retirer • Tab/Échap : retour aux fichiers • Entrée : confirmer la sélection • q : quitter",
+
+	// Column selection
+	"⏰ Select Columns to Convert": "⏰ Sélectionnez les colonnes à convertir",
+	" (from %s)":                  " (de %s)",
+	"profile %s":                  "profil %s",
+	"preset %s":                   "préréglage %s",
+	"File (%d/%d): %s":            "Fichier (%d/%d) : %s",
+	"✓ Auto-detected %d decimal hour column(s)":                           "✓ %d colonne(s) d'heures décimales détectée(s)",
+	"Viewing %d-%d of %d columns":                                         "Colonnes %d-%d sur %d",
+	"Viewing %d-%d of %d columns matching %q (of %d) • esc: clear filter": "Colonnes %d-%d sur %d correspondant à %q (sur %d) • échap : effacer le filtre",
+	"Column settings apply to this column only":                           "Les réglages ne s'appliquent qu'à cette colonne",
+	"Reordering %d columns, listed in output order":                       "Réorganisation de %d colonnes, dans l'ordre de sortie",
+	"Why columns were detected (first %d data rows sampled)":              "Pourquoi les colonnes ont été détectées (%d premières lignes de données échantillonnées)",
+	"Keep Original Columns: %s":                                           "Conserver les colonnes d'origine : %s",
+	"Output Columns: %s":                                                  "Colonnes de sortie : %s",
+	"as in file":                                                          "comme dans le fichier",
+	"custom":                                                              "personnalisé",
+	"Column Order: %s":                                                    "Ordre des colonnes : %s",
+	"passed through":                                                      "conservées",
+	"dropped":                                                             "supprimées",
+	"Footer Rows Skipped: %d (%s)":                                        "Lignes de pied ignorées : %d (%s)",
+	"Row Filters: %d active":                                              "Filtres de lignes : %d actifs",
+	"Flag: %s":                                                            "Signaler : %s",
+	"Blanks and Zeros: %s":                                                "Vides et zéros : %s",
+	"Durations: %s":                                                       "Durées : %s",
+	"Totals: %s":                                                          "Totaux : %s",
+	"same as input":                                                       "identique à l'entrée",
+	"Encoding: %s → %s • Lines: %s • Quoting: %s":                         "Encodage : %s → %s • Lignes : %s • Guillemets : %s",
+	" (%d short rows padded)":                                             " (%d lignes courtes complétées)",
+	"converted copy":                                                      "copie convertie",
+	"new %q sheet in this file":                                           "nouvelle feuille %q dans ce fichier",
+	"new %q sheet in a converted copy":                                    "nouvelle feuille %q dans une copie convertie",
+	", in table %q":                                                       ", dans le tableau %q",
+	", original values in comments":                                       ", valeurs d'origine en commentaires",
+	"Output: %s":                                                          "Sortie : %s",
+	"type to filter • enter: keep filter • esc: clear filter": "tapez pour filtrer • entrée : garder le filtre • échap : effacer le filtre",
+	"enter: apply (empty clears) • esc: cancel":               "entrée : appliquer (vide pour effacer) • échap : annuler",
+	"enter: apply (empty resets) • esc: cancel":               "entrée : appliquer (vide pour réinitialiser) • échap : annuler",
+	"enter: save • esc: cancel":                               "entrée : enregistrer • échap : annuler",
+	"enter: convert anyway • esc: back to columns":            "entrée : convertir quand même • échap : retour aux colonnes",
+	"No columns match %q":                                     "Aucune colonne ne correspond à %q",
+	" (kept)":                                                 " (conservée)",
+	" (detected)":                                             " (détectée)",
+	"(empty)":                                                 "(vide)",
+	"sampled: %s":                                             "échantillon : %s",
+	"These files changed after their columns were chosen, such as by a new export:":        "Ces fichiers ont changé après le choix de leurs colonnes, par exemple à la suite d'un nouvel export :",
+	"%s: reload and detect columns again • %s: convert anyway • esc: back to columns":      "%s : recharger et détecter à nouveau les colonnes • %s : convertir quand même • échap : retour aux colonnes",
+	"These would stop the batch part way through, so it hasn't started:":                   "Ces problèmes interrompraient le lot en cours de route, il n'a donc pas été lancé :",
+	"enter: check again • esc: back to columns":                                            "entrée : vérifier à nouveau • échap : retour aux colonnes",
+	"%s: overwrite • %s: rename • %s: skip • %s: same for the rest • esc: back to columns": "%s : écraser • %s : renommer • %s : ignorer • %s : idem pour les suivants • échap : retour aux colonnes",
+	"enter: apply preset and continue • esc: choose columns":                               "entrée : appliquer le préréglage et continuer • échap : choisir les colonnes",
+	"%s: convert • esc: choose columns • %s: quit":                                         "%s : convertir • échap : choisir les colonnes • %s : quitter",
+	"Convert %s in %s?":                       "Convertir %s dans %s ?",
+	"The converted copy will be saved as %s.": "La copie convertie sera enregistrée sous %s.",
+	"These columns weren't detected as hours and may be mangled by converting:":                                                                      "Ces colonnes n'ont pas été détectées comme des heures et la conversion risque de les altérer :",
+	"↑/↓: scroll • x/esc: back to columns • q: quit":                                                                                                 "↑/↓ : défiler • x/échap : retour aux colonnes • q : quitter",
+	"↑/↓: scroll rows • ←/→: scroll columns • V/esc: back to columns • q: quit":                                                                      "↑/↓ : faire défiler les lignes • ←/→ : faire défiler les colonnes • V/échap : retour aux colonnes • q : quitter",
+	"↑/↓: choose column • K/J: move it up/down • r/esc: done • q: quit":                                                                              "↑/↓ : choisir la colonne • K/J : la monter/descendre • r/échap : terminé • q : quitter",
+	"↑/↓: choose setting • ←/→: change • a: apply to all selected • enter: rename, or type a pattern or expression • esc: back to columns • q: quit": "↑/↓ : choisir le réglage • ←/→ : modifier • a : appliquer à toutes les sélectionnées • entrée : renommer, ou saisir un motif ou une expression • échap : retour aux colonnes • q : quitter",
+
+	// Processing, results and errors
+	"Loading file...":               "Chargement du fichier...",
+	"⏰ Processing...":               "⏰ Traitement...",
+	"Converting file %d of %d...":   "Conversion du fichier %d sur %d...",
+	"Batch: %d%% done":              "Lot : %d %% terminé",
+	"✓ Conversion Complete!":        "✓ Conversion terminée !",
+	"Lines %d-%d of %d":             "Lignes %d-%d sur %d",
+	"%s of manual formatting saved": "%s de mise en forme manuelle économisés",
+	"%s saved across %d runs":       "%s économisés sur %d exécutions",
+	"Export report as c: CSV • j: JSON • m: Markdown • p: PDF • esc: cancel": "Exporter le rapport en c : CSV • j : JSON • m : Markdown • p : PDF • échap : annuler",
+	"✗ Error": "✗ Erreur",
+
+	// Plain mode
+	"Chronos %s converts decimal hours to HH:MM in CSV, XLSX and JSON files.":                       "Chronos %s convertit les heures décimales en HH:MM dans les fichiers CSV, XLSX et JSON.",
+	"Type each answer and press Enter. An empty answer takes the default in brackets, and q quits.": "Tapez chaque réponse puis appuyez sur Entrée. Une réponse vide prend la valeur entre crochets, et q quitte.",
+	"File to convert, as a path or a link. Leave it empty to quit:":                                 "Fichier à convertir, sous forme de chemin ou de lien. Laissez vide pour quitter :",
+	"Error: %s":                       "Erreur : %s",
+	"Convert another file?":           "Convertir un autre fichier ?",
+	"Type y for yes or n for no [y]:": "Tapez o pour oui ou n pour non [o] :",
+	"Type y for yes or n for no [n]:": "Tapez o pour oui ou n pour non [n] :",
+	"y":                               "o",
+	"yes":                             "oui",
+	"n":                               "n",
+	"no":                              "non",
+	"Please answer y or n.":           "Veuillez répondre o ou n.",
+	"Downloading %s...":               "Téléchargement de %s...",
+	"%s is an archive; convert it with \"chronos convert\" instead": "%s est une archive ; convertissez-la plutôt avec \"chronos convert\"",
+	"Reading %s...": "Lecture de %s...",
+	"%s has %d columns and %d rows, %d of them footer rows.": "%s compte %d colonnes et %d lignes, dont %d lignes de pied.",
+	"Column %d: %s":                                "Colonne %d : %s",
+	", detected as decimal hours":                  ", détectée comme heures décimales",
+	"No columns chosen, so nothing was converted.": "Aucune colonne choisie, rien n'a donc été converti.",
+	"Convert %s and save the result as %s?":        "Convertir %s et enregistrer le résultat sous %s ?",
+	"Skipped %s.":                                  "%s ignoré.",
+	"%s already exists. Replace it?":               "%s existe déjà. Le remplacer ?",
+	"Converting...":                                "Conversion...",
+	"Converted %d values in %d rows.":              "%d valeurs converties dans %d lignes.",
+	"Warning: %s":                                  "Avertissement : %s",
+	"Saved as %s":                                  "Enregistré sous %s",
+	"Columns to convert, as numbers or names separated by commas [%s]:":                                       "Colonnes à convertir, sous forme de numéros ou de noms séparés par des virgules [%s] :",
+	"No columns were detected as decimal hours. Columns to convert, as numbers or names separated by commas:": "Aucune colonne n'a été détectée comme heures décimales. Colonnes à convertir, sous forme de numéros ou de noms séparés par des virgules :",
+	"there is no column %d":       "il n'y a pas de colonne %d",
+	"there is no column named %q": "il n'y a pas de colonne nommée %q",
+
+	// Compact layout, history, column settings and results
+	"Terminal too small (%d×%d). Resize to at least %d×%d, or press q to quit.": "Terminal trop petit (%d×%d). Agrandissez-le à au moins %d×%d ou appuyez sur q pour quitter.",
+	"⏰ Chronos (%d/3)":                                           "⏰ Chronos (%d/3)",
+	"⏎: download • esc: cancel":                                  "⏎ : télécharger • échap : annuler",
+	"⏎: select • esc: cancel":                                    "⏎ : sélectionner • échap : annuler",
+	"x: remove • tab: back • ⏎: go":                              "x : retirer • tab : retour • ⏎ : continuer",
+	"⏎: pick • ^r: subfolders • esc: cancel":                     "⏎ : choisir • ^r : sous-dossiers • échap : annuler",
+	"⏎: resume • esc: discard":                                   "⏎ : reprendre • échap : abandonner",
+	"⏰ Setup (%d/%d)":                                            "⏰ Configuration (%d/%d)",
+	"⏰ Loading...":                                               "⏰ Chargement...",
+	"⏰ Recent Conversions":                                       "⏰ Conversions récentes",
+	"%d/%d %s • %d selected":                                     "%d/%d %s • %d sélectionnée(s)",
+	"⏎: apply • esc: cancel":                                     "⏎ : appliquer • échap : annuler",
+	"⏎: save • esc: cancel":                                      "⏎ : enregistrer • échap : annuler",
+	"⏎: keep • esc: clear":                                       "⏎ : garder • échap : effacer",
+	"%s: reload • %s: convert anyway • esc: back":                "%s : recharger • %s : convertir quand même • échap : retour",
+	"⏎: check again • esc: back":                                 "⏎ : vérifier à nouveau • échap : retour",
+	"%s: overwrite/rename/skip • %s: all":                        "%s : écraser/renommer/ignorer • %s : tous",
+	"⏎: apply preset • esc: choose columns":                      "⏎ : appliquer le préréglage • échap : choisir les colonnes",
+	"%s: convert • esc: choose columns":                          "%s : convertir • échap : choisir les colonnes",
+	"⏎: convert anyway • esc: back":                              "⏎ : convertir quand même • échap : retour",
+	"↑/↓: scroll • x: back • q: quit":                            "↑/↓ : défiler • x : retour • q : quitter",
+	"↑/↓: rows • ←/→: columns • V: back":                         "↑/↓ : lignes • ←/→ : colonnes • V : retour",
+	"K/J: move • r: done":                                        "K/J : déplacer • r : terminé",
+	"↑/↓: choose • ←/→: change • a: all • esc: back":             "↑/↓ : choisir • ←/→ : modifier • a : toutes • échap : retour",
+	" (batch %d%%)":                                              " (lot %d%%)",
+	"✓ %d file(s), %d rows":                                      "✓ %d fichier(s), %d lignes",
+	"Re-run a past conversion with the same columns and options": "Relancer une conversion passée avec les mêmes colonnes et options",
+	"Input:":          "Entrée :",
+	"Output:":         "Sortie :",
+	"Columns:":        "Colonnes :",
+	"Settings for %q": "Réglages de %q",
+	"Not selected, so these apply once it is": "Non sélectionnée : ces réglages s'appliqueront quand elle le sera",
+	"Format:":     "Format :",
+	"Rounding:":   "Arrondi :",
+	"Unit:":       "Unité :",
+	"Decimal:":    "Décimale :",
+	"Units:":      "Unités :",
+	"Adjust:":     "Ajustement :",
+	"Overtime:":   "Heures sup. :",
+	"Transform:":  "Transformation :",
+	"Name:":       "Nom :",
+	"Preview: %s": "Aperçu : %s",
+	"Browsing the first %d of %d rows • columns %d-%d of %d": "Affichage des %d premières lignes sur %d • colonnes %d-%d sur %d",
+	"Could not save bug report: %v":                          "Impossible d'enregistrer le rapport de bogue : %v",
+	"Bug report saved to %s":                                 "Rapport de bogue enregistré dans %s",
+	"Could not save run summary: %v":                         "Impossible d'enregistrer le résumé : %v",
+	"Run summary saved to %s":                                "Résumé enregistré dans %s",
+	"Could not save sidecars: %v":                            "Impossible d'enregistrer les fichiers de métadonnées : %v",
+	"Sidecars saved next to %d output(s)":                    "Fichiers de métadonnées enregistrés à côté de %d sortie(s)",
+	"Could not save audit log: %v":                           "Impossible d'enregistrer le journal d'audit : %v",
+	"Audit log saved to %s":                                  "Journal d'audit enregistré dans %s",
+	"Audit log already saved to %s":                          "Journal d'audit déjà enregistré dans %s",
+	"Could not open %s: %v":                                  "Impossible d'ouvrir %s : %v",
+	"Opened %s":                                              "%s ouvert",
+	"Could not copy path: %v":                                "Impossible de copier le chemin : %v",
+	"Copied %s":                                              "%s copié",
+	"Could not zip outputs: %v":                              "Impossible de compresser les sorties : %v",
+	"No files came from an archive":                          "Aucun fichier ne venait d'une archive",
+	"Converted files zipped to %s":                           "Fichiers convertis compressés dans %s",
+	"Could not export report: %v":                            "Impossible d'exporter le rapport : %v",
+	"Report exported to %s":                                  "Rapport exporté dans %s",
+	"Could not undo: %v":                                     "Impossible d'annuler : %v",
+	"Undone: %d file(s) deleted, %d restored":                "Annulé : %d fichier(s) supprimé(s), %d restauré(s)",
+
+	// Setup, prompts and statuses
+	"⏰ Welcome to Chronos": "⏰ Bienvenue dans Chronos",
+	"Setup %d/%d • everything can be changed later in config.json": "Configuration %d/%d • tout peut être modifié plus tard dans config.json",
+	"Which folder should the file picker open in?":                 "Dans quel dossier le sélecteur de fichiers doit-il s'ouvrir ?",
+	"Which colors suit your terminal?":                             "Quelles couleurs conviennent à votre terminal ?",
+	"Keep the decimal hours next to the converted columns?":        "Conserver les heures décimales à côté des colonnes converties ?",
+	"How should converted hours be rounded?":                       "Comment arrondir les heures converties ?",
+	"What should be added to the names of converted files?":        "Que faut-il ajouter au nom des fichiers convertis ?",
+	"The keys you'll use most":                                     "Les touches que vous utiliserez le plus",
+	"No, replace them":                                             "Non, les remplacer",
+	"Yes, keep both":                                               "Oui, garder les deux",
+	"To the nearest minute":                                        "À la minute la plus proche",
+	"Down to the minute":                                           "À la minute inférieure",
+	"Up to the minute":                                             "À la minute supérieure",
+	"To the quarter hour (7-minute rule)":                          "Au quart d'heure (règle des 7 minutes)",
+	"To the tenth of an hour":                                      "Au dixième d'heure",
+	"Folder: ":                                                     "Dossier : ",
+	"empty reopens the last folder used":                           "vide rouvre le dernier dossier utilisé",
+	"Suffix: ":                                                     "Suffixe : ",
+	"timesheet.csv is converted to timesheet%s.csv":                "timesheet.csv est converti en timesheet%s.csv",
+	"%s is not a folder":                                           "%s n'est pas un dossier",
+	"the suffix can't contain slashes":                             "le suffixe ne peut pas contenir de barres obliques",
+	"Could not save settings: %v":                                  "Impossible d'enregistrer les réglages : %v",
+	"enter: next • esc: back":                                      "entrée : suivant • échap : retour",
+	"enter: next • esc: skip setup":                                "entrée : suivant • échap : passer la configuration",
+	"enter: start converting • esc: back":                          "entrée : commencer la conversion • échap : retour",
+	"%s/%s: choose • %s":                                           "%s/%s : choisir • %s",
+	"Picking files":                                                "Choix des fichiers",
+	"Choosing columns":                                             "Choix des colonnes",
+	"Results":                                                      "Résultats",
+	"Press %s on any screen for all of its keys.":                  "Appuyez sur %s sur n'importe quel écran pour voir toutes ses touches.",
+	"Saved %s, %d of %d file(s) configured:":                       "Enregistré %s, %d fichier(s) sur %d configuré(s) :",
+	"Invalid pattern: %v":                                          "Motif non valide : %v",
+	"No CSV, XLSX or archive files match %s":                       "Aucun fichier CSV, XLSX ou archive ne correspond à %s",
+	"Added %d file(s)":                                             "%d fichier(s) ajouté(s)",
+	"Added %d of %d matching files; up to 3 can be selected":       "%d fichier(s) correspondant(s) sur %d ajouté(s) ; 3 au maximum peuvent être sélectionnés",
+	"Could not load history: %v":                                   "Impossible de charger l'historique : %v",
+	"No conversions in the history yet":                            "Aucune conversion dans l'historique pour l'instant",
+	"Reading %s of %s":                                             "Lecture de %s sur %s",
+	"Converting row %d of %d":                                      "Conversion de la ligne %d sur %d",
+	"Writing %s":                                                   "Écriture de %s",
+	"%s already exists.":                                           "%s existe déjà.",
+	" (%d more after this one)":                                    " (%d de plus après celui-ci)",
+	"Overwrite it, write to %s instead, or skip %s?":               "L'écraser, écrire dans %s à la place, ou ignorer %s ?",
+	"Every file was skipped":                                       "Tous les fichiers ont été ignorés",
+	"This looks like an export from %s.":                           "Cela ressemble à un export de %s.",
+	"The %s preset %s.":                                            "Le préréglage %s %s.",
+	"Reloaded %s; check the columns and press enter":               "%s rechargé ; vérifiez les colonnes et appuyez sur entrée",
+	"Columns of %s moved or are gone, so they were detected again; check them and press enter": "Les colonnes de %s ont été déplacées ou ont disparu, elles ont donc été détectées à nouveau ; vérifiez-les et appuyez sur entrée",
+	"%d rows":                    "%d lignes",
+	", %d warning(s)":            ", %d avertissement(s)",
+	"Totals:":                    "Totaux :",
+	"Not a valid http(s) URL":    "URL http(s) non valide",
+	"Could not save profile: %v": "Impossible d'enregistrer le profil : %v",
+	"Saved profile %q":           "Profil %q enregistré",
+	"Reloading...":               "Rechargement...",
+	"Converted sheets are only for XLSX files":                             "Les feuilles converties ne concernent que les fichiers XLSX",
+	"Comments are only for XLSX files":                                     "Les commentaires ne concernent que les fichiers XLSX",
+	"Tables are only for XLSX files":                                       "Les tableaux ne concernent que les fichiers XLSX",
+	"No start and end time columns found":                                  "Aucune colonne d'heure de début et de fin trouvée",
+	"No employee and date columns found":                                   "Aucune colonne d'employé et de date trouvée",
+	"No decimal hour columns were detected; choose the columns to convert": "Aucune colonne d'heures décimales n'a été détectée ; choisissez les colonnes à convertir",
+}
@@ -0,0 +1,111 @@
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/message/catalog"
+)
+
+// Supported lists the languages the interface is translated into, English
+// first as the fallback.
+var Supported = []language.Tag{language.English, language.Spanish, language.German, language.French}
+
+// translations maps each language but English to its messages, keyed by
+// the English format string they translate.
+var translations = map[language.Tag]map[string]string{
+	language.Spanish: spanish,
+	language.German:  german,
+	language.French:  french,
+}
+
+// messages is the catalog of every translation.
+var messages = buildCatalog()
+
+func buildCatalog() catalog.Catalog {
+	b := catalog.NewBuilder(catalog.Fallback(language.English))
+	for tag, msgs := range translations {
+		for key, msg := range msgs {
+			if err := b.SetString(tag, key, msg); err != nil {
+				panic(fmt.Sprintf("i18n: %s translation of %q: %v", tag, key, err))
+			}
+		}
+	}
+	return b
+}
+
+var matcher = language.NewMatcher(Supported)
+
+// Match returns the supported language closest to name, a BCP 47 tag such
+// as "de" or "es-MX" or a locale such as "fr_CA.UTF-8", or English when
+// none is close or name can't be read.
+func Match(name string) language.Tag {
+	name, _, _ = strings.Cut(name, ".")
+	name, _, _ = strings.Cut(name, "@")
+	name = strings.ReplaceAll(name, "_", "-")
+	if name == "" || name == "C" || name == "POSIX" {
+		return language.English
+	}
+	tag, err := language.Parse(name)
+	if err != nil {
+		return language.English
+	}
+	_, index, confidence := matcher.Match(tag)
+	if confidence == language.No {
+		return language.English
+	}
+	return Supported[index]
+}
+
+// Detect picks the interface's language: setting, the config file's
+// "language", when set, and otherwise the locale from LC_ALL, LC_MESSAGES
+// or LANG, the first that's set.
+func Detect(setting string) language.Tag {
+	if setting != "" {
+		return Match(setting)
+	}
+	for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if v := os.Getenv(env); v != "" {
+			return Match(v)
+		}
+	}
+	return language.English
+}
+
+// Translator formats messages in one language.
+type Translator struct {
+	tag     language.Tag
+	printer *message.Printer
+}
+
+// New returns a Translator for tag, one of Supported.
+func New(tag language.Tag) *Translator {
+	return &Translator{tag: tag, printer: message.NewPrinter(tag, message.Catalog(messages))}
+}
+
+// Language is the language t translates into.
+func (t *Translator) Language() language.Tag {
+	return t.tag
+}
+
+// Sprintf translates format and formats it like fmt.Sprintf. Messages
+// without a translation stay in English. English is formatted by fmt, so
+// its numbers aren't grouped as other languages' are.
+func (t *Translator) Sprintf(format string, args ...any) string {
+	if t.tag == language.English {
+		return fmt.Sprintf(format, args...)
+	}
+	return t.printer.Sprintf(format, args...)
+}
+
+// Text translates msg, a message without arguments, such as one chosen at
+// run time rather than written in the call.
+func (t *Translator) Text(msg string) string {
+	if translated, ok := translations[t.tag][msg]; ok {
+		return translated
+	}
+	return msg
+}
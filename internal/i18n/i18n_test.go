@@ -0,0 +1,93 @@
+package i18n
+
+import (
+	"regexp"
+	"slices"
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected language.Tag
+	}{
+		{"es", language.Spanish},
+		{"es-MX", language.Spanish},
+		{"de_DE.UTF-8", language.German},
+		{"fr_CA.UTF-8@euro", language.French},
+		{"en_US.UTF-8", language.English},
+		{"ja_JP.UTF-8", language.English},
+		{"C", language.English},
+		{"", language.English},
+		{"not a locale", language.English},
+	}
+	for _, tt := range tests {
+		if got := Match(tt.name); got != tt.expected {
+			t.Errorf("Match(%q) = %s; want %s", tt.name, got, tt.expected)
+		}
+	}
+}
+
+func TestDetect(t *testing.T) {
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "")
+	t.Setenv("LANG", "de_DE.UTF-8")
+
+	if got := Detect(""); got != language.German {
+		t.Errorf("Expected LANG to pick German, got %s", got)
+	}
+	if got := Detect("fr"); got != language.French {
+		t.Errorf("Expected the setting to win over LANG, got %s", got)
+	}
+	t.Setenv("LC_ALL", "es_ES.UTF-8")
+	if got := Detect(""); got != language.Spanish {
+		t.Errorf("Expected LC_ALL to win over LANG, got %s", got)
+	}
+}
+
+func TestTranslator(t *testing.T) {
+	es := New(language.Spanish)
+	if got := es.Sprintf("Converting file %d of %d...", 2, 3); got != "Convirtiendo archivo 2 de 3..." {
+		t.Errorf("Unexpected translation: %q", got)
+	}
+	if got := es.Text("quit"); got != "salir" {
+		t.Errorf("Unexpected translation: %q", got)
+	}
+	if got := es.Sprintf("Untranslated %s", "message"); got != "Untranslated message" {
+		t.Errorf("Expected an untranslated message in English, got %q", got)
+	}
+	if got := New(language.English).Sprintf("Lines %d-%d of %d", 1, 10, 1500); got != "Lines 1-10 of 1500" {
+		t.Errorf("Expected English formatted as fmt does, got %q", got)
+	}
+}
+
+// verbs matches the formatting verbs of a message.
+var verbs = regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+
+// Each translation has the same verbs, in the same order, as its message,
+// so arguments land where they should.
+func TestTranslationVerbs(t *testing.T) {
+	for tag, msgs := range translations {
+		for key, msg := range msgs {
+			if want, got := verbs.FindAllString(key, -1), verbs.FindAllString(msg, -1); !slices.Equal(want, got) {
+				t.Errorf("%s: %q has verbs %v; want %v", tag, msg, got, want)
+			}
+		}
+	}
+}
+
+// Every language translates the same messages.
+func TestTranslationsComplete(t *testing.T) {
+	for tag, msgs := range translations {
+		for key := range spanish {
+			if _, ok := msgs[key]; !ok {
+				t.Errorf("%s has no translation of %q", tag, key)
+			}
+		}
+		if len(msgs) != len(spanish) {
+			t.Errorf("%s has %d messages; Spanish has %d", tag, len(msgs), len(spanish))
+		}
+	}
+}
@@ -0,0 +1,171 @@
+// Package notify sends the reports of unattended batches, such as those
+// converted by watch, to a webhook or by email.
+package notify
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/nconklindev/chronos/internal/summary"
+)
+
+// Batch is what's sent about a batch: a line summing it up, its report and
+// the files that couldn't be converted.
+type Batch struct {
+	// Text sums up the batch in a line, which chat webhooks such as Slack's
+	// and Teams' show as the message.
+	Text   string         `json:"text"`
+	Report summary.Report `json:"report"`
+	Errors []string       `json:"errors,omitempty"`
+}
+
+// NewBatch describes a batch from its report and the errors of the files
+// that failed.
+func NewBatch(report summary.Report, errs []string) Batch {
+	t := report.Totals
+	text := fmt.Sprintf("chronos converted %d file(s), %d rows", t.Files, t.Rows)
+	if t.Warnings > 0 {
+		text += fmt.Sprintf(", %d warning(s)", t.Warnings)
+	}
+	if len(errs) > 0 {
+		text += fmt.Sprintf("; %d file(s) failed", len(errs))
+	}
+	return Batch{Text: text, Report: report, Errors: errs}
+}
+
+// Webhook posts the batch as JSON to url.
+func Webhook(client *http.Client, url string, b Batch) error {
+	data, err := json.Marshal(b)
+	if err != nil {
+		return err
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Post(url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook failed: %s", resp.Status)
+	}
+	return nil
+}
+
+// SMTP is the mail server batches are emailed through, and who to.
+type SMTP struct {
+	Addr string // host:port
+	From string
+	To   []string
+	// Username and Password sign in to the server when Username is set.
+	Username string
+	Password string
+	Timeout  time.Duration // Defaults to emailTimeout
+}
+
+// emailTimeout is how long the mail server has to accept a batch, so a
+// slow one doesn't hold up watching.
+const emailTimeout = 30 * time.Second
+
+// Email sends the batch through the SMTP server, its report as JSON
+// below the line summing it up. It does what smtp.SendMail does, with a
+// deadline on the whole exchange.
+func Email(s SMTP, b Batch) error {
+	for _, addr := range append([]string{s.From}, s.To...) {
+		if strings.ContainsAny(addr, "\r\n") {
+			return errors.New("smtp: an address contains CR or LF")
+		}
+	}
+	msg, err := message(s, b, time.Now())
+	if err != nil {
+		return err
+	}
+	host, _, err := net.SplitHostPort(s.Addr)
+	if err != nil {
+		return err
+	}
+
+	timeout := s.Timeout
+	if timeout <= 0 {
+		timeout = emailTimeout
+	}
+	conn, err := net.DialTimeout("tcp", s.Addr, timeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return err
+	}
+
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if s.Username != "" {
+		if ok, _ := c.Extension("AUTH"); !ok {
+			return errors.New("smtp: server doesn't support AUTH")
+		}
+		if err := c.Auth(smtp.PlainAuth("", s.Username, s.Password, host)); err != nil {
+			return err
+		}
+	}
+
+	if err := c.Mail(s.From); err != nil {
+		return err
+	}
+	for _, to := range s.To {
+		if err := c.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// message returns the email sent about a batch at t.
+func message(s SMTP, b Batch, t time.Time) ([]byte, error) {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", s.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(s.To, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", b.Text)
+	fmt.Fprintf(&msg, "Date: %s\r\n", t.Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(b.Text + "\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(string(data), "\n", "\r\n"))
+	msg.WriteString("\r\n")
+	return msg.Bytes(), nil
+}
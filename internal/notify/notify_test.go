@@ -0,0 +1,169 @@
+package notify
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nconklindev/chronos/internal/summary"
+	"github.com/nconklindev/chronos/internal/types"
+)
+
+func testBatch() Batch {
+	results := []*types.ConversionResult{
+		{InputFile: "hours.csv", OutputFile: "hours_converted.csv", ColumnsFound: []string{"Hours"}, RowsProcessed: 4, Warnings: []string{"1 cell wasn't a number"}},
+	}
+	return NewBatch(summary.NewReport(summary.NewRun("1.0.0", results, nil)), []string{"broken.csv: empty file"})
+}
+
+func TestNewBatch(t *testing.T) {
+	b := testBatch()
+	if want := "chronos converted 1 file(s), 4 rows, 1 warning(s); 1 file(s) failed"; b.Text != want {
+		t.Errorf("Expected %q, got %q", want, b.Text)
+	}
+}
+
+func TestWebhook(t *testing.T) {
+	var got Batch
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Expected JSON, got %q", r.Header.Get("Content-Type"))
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer server.Close()
+
+	b := testBatch()
+	if err := Webhook(server.Client(), server.URL, b); err != nil {
+		t.Fatalf("Webhook failed: %v", err)
+	}
+	if got.Text != b.Text || got.Report.Totals.Rows != 4 || len(got.Errors) != 1 {
+		t.Errorf("Expected the batch to be posted, got %+v", got)
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "no such hook", http.StatusNotFound)
+	}))
+	defer failing.Close()
+	if err := Webhook(failing.Client(), failing.URL, b); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("Expected the failed post to be reported, got %v", err)
+	}
+}
+
+func TestMessage(t *testing.T) {
+	s := SMTP{Addr: "mail.example.com:587", From: "chronos@example.com", To: []string{"payroll@example.com", "ops@example.com"}}
+	msg, err := message(s, testBatch(), time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	text := string(msg)
+	for _, part := range []string{
+		"To: payroll@example.com, ops@example.com\r\n",
+		"Subject: chronos converted 1 file(s), 4 rows, 1 warning(s); 1 file(s) failed\r\n",
+		"Date: Fri, 01 Mar 2024 09:30:00 +0000\r\n",
+		"\r\n\r\n{\r\n",
+		`"rows_processed": 4`,
+	} {
+		if !strings.Contains(text, part) {
+			t.Errorf("Expected the message to contain %q, got:\n%s", part, text)
+		}
+	}
+	if strings.Contains(strings.ReplaceAll(text, "\r\n", ""), "\n") {
+		t.Error("Expected every line to end with CRLF")
+	}
+}
+
+// fakeSMTP answers one SMTP conversation on a local port and sends what it
+// was given as DATA on the returned channel.
+func fakeSMTP(t *testing.T) (string, <-chan string) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	data := make(chan string, 1)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+		reply("220 localhost ESMTP")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			switch cmd := strings.ToUpper(strings.TrimSpace(line)); {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "MAIL"), strings.HasPrefix(cmd, "RCPT"):
+				reply("250 OK")
+			case cmd == "DATA":
+				reply("354 Go ahead")
+				var body strings.Builder
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil || line == ".\r\n" {
+						break
+					}
+					body.WriteString(line)
+				}
+				data <- body.String()
+				reply("250 OK")
+			case cmd == "QUIT":
+				reply("221 Bye")
+				return
+			default:
+				reply("502 Not implemented")
+			}
+		}
+	}()
+	return l.Addr().String(), data
+}
+
+func TestEmail(t *testing.T) {
+	addr, data := fakeSMTP(t)
+	s := SMTP{Addr: addr, From: "chronos@example.com", To: []string{"payroll@example.com"}}
+	if err := Email(s, testBatch()); err != nil {
+		t.Fatalf("Email failed: %v", err)
+	}
+	if body := <-data; !strings.Contains(body, "Subject: chronos converted 1 file(s)") {
+		t.Errorf("Unexpected message:\n%s", body)
+	}
+}
+
+func TestEmail_Timeout(t *testing.T) {
+	// A server that accepts the connection but never answers
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	go func() {
+		conn, err := l.Accept()
+		if err == nil {
+			defer conn.Close()
+			time.Sleep(5 * time.Second)
+		}
+	}()
+
+	start := time.Now()
+	s := SMTP{Addr: l.Addr().String(), From: "chronos@example.com", To: []string{"payroll@example.com"}, Timeout: 100 * time.Millisecond}
+	if err := Email(s, testBatch()); err == nil {
+		t.Error("Expected a server that doesn't answer to time out")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Expected to give up after the timeout, took %v", elapsed)
+	}
+}
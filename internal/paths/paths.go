@@ -0,0 +1,46 @@
+package paths
+
+import (
+	"runtime"
+	"strings"
+)
+
+// Normalize tidies a path typed or passed in by the user for the current
+// system. On Windows that means backslashes throughout, network shares
+// given with forward slashes (//server/share) as \\server\share, and the
+// \\?\ long-path prefix dropped, since Go adds it itself to long absolute
+// paths and other programs show paths without it. Other systems' paths are
+// returned as they are.
+func Normalize(path string) string {
+	if runtime.GOOS != "windows" {
+		return path
+	}
+	return normalizeWindows(path)
+}
+
+// normalizeWindows is Normalize for Windows paths, on any system.
+func normalizeWindows(path string) string {
+	path = strings.ReplaceAll(path, "/", `\`)
+
+	switch {
+	case hasPrefixFold(path, `\\?\UNC\`):
+		// \\?\UNC\server\share is \\server\share
+		return `\\` + path[len(`\\?\UNC\`):]
+	case strings.HasPrefix(path, `\\?\`) && isDrivePath(path[len(`\\?\`):]):
+		return path[len(`\\?\`):]
+	}
+	return path
+}
+
+// isDrivePath reports whether path starts with a drive letter and a colon.
+func isDrivePath(path string) bool {
+	if len(path) < 2 || path[1] != ':' {
+		return false
+	}
+	c := path[0] | 0x20 // Lowercase
+	return c >= 'a' && c <= 'z'
+}
+
+func hasPrefixFold(s, prefix string) bool {
+	return len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
+}
@@ -0,0 +1,28 @@
+package paths
+
+import "testing"
+
+func TestNormalizeWindows(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{`C:\exports\hours.csv`, `C:\exports\hours.csv`},
+		{`C:/exports/hours.csv`, `C:\exports\hours.csv`},
+		{`\\server\payroll\hours.csv`, `\\server\payroll\hours.csv`},
+		{`//server/payroll/hours.csv`, `\\server\payroll\hours.csv`},
+		{`\\?\C:\exports\hours.csv`, `C:\exports\hours.csv`},
+		{`\\?\UNC\server\payroll\hours.csv`, `\\server\payroll\hours.csv`},
+		{`\\?\unc\server\payroll`, `\\server\payroll`},
+		// Device and volume paths have no shorter form
+		{`\\.\COM1`, `\\.\COM1`},
+		{`\\?\Volume{b75e2c83-0000-0000-0000-602f00000000}\hours.csv`, `\\?\Volume{b75e2c83-0000-0000-0000-602f00000000}\hours.csv`},
+		{`exports\hours.csv`, `exports\hours.csv`},
+	}
+
+	for _, tt := range tests {
+		if got := normalizeWindows(tt.path); got != tt.want {
+			t.Errorf("normalizeWindows(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package profile
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/nconklindev/chronos/internal/types"
+)
+
+// PresetAuto picks the preset matching each file's headers.
+const PresetAuto = "auto"
+
+// Preset is a built-in profile for a vendor's export layout. A file is
+// recognized as one when its header has every column of the preset's,
+// wherever they are.
+type Preset struct {
+	Profile
+	Vendor string // Who makes the software, as shown to users
+	// Output names converted copies: {name} is the input's name without
+	// its extension. The name ends in _converted, so watched folders skip
+	// them.
+	Output string
+}
+
+// Presets are the built-in vendor presets. Footer rows are dropped from
+// all of them, since each vendor ends its exports with totals that would
+// be imported as another employee.
+var Presets = []Preset{
+	{
+		Profile: Profile{
+			Name: "adp",
+			Columns: []Column{
+				{Header: "Co Code", Type: TypeText},
+				{Header: "File #", Type: TypeNumber},
+				{Header: "Reg Hours", Type: TypeHours, Convert: true},
+				{Header: "O/T Hours", Type: TypeHours, Convert: true},
+			},
+			Options: types.ConversionOptions{DropFooter: true},
+		},
+		Vendor: "ADP Workforce Now",
+		Output: "{name}_adp_converted",
+	},
+	{
+		Profile: Profile{
+			Name: "kronos",
+			Columns: []Column{
+				{Header: "Person Number", Type: TypeNumber},
+				{Header: "Person Name", Type: TypeText},
+				{Header: "Pay Code", Type: TypeText},
+				{Header: "Hours", Type: TypeHours, Convert: true},
+			},
+			Options: types.ConversionOptions{DropFooter: true},
+		},
+		Vendor: "Kronos Workforce Central",
+		Output: "{name}_kronos_converted",
+	},
+	{
+		Profile: Profile{
+			Name: "ukg",
+			Columns: []Column{
+				{Header: "Employee ID", Type: TypeNumber},
+				{Header: "Employee Full Name", Type: TypeText},
+				{Header: "Apply Date", Type: TypeText},
+				{Header: "Paycode", Type: TypeText},
+				{Header: "Hours", Type: TypeHours, Convert: true},
+			},
+			Options: types.ConversionOptions{DropFooter: true},
+		},
+		Vendor: "UKG Pro Workforce Management",
+		Output: "{name}_ukg_converted",
+	},
+	{
+		Profile: Profile{
+			Name: "workday",
+			Columns: []Column{
+				{Header: "Worker", Type: TypeText},
+				{Header: "Time Type", Type: TypeText},
+				{Header: "Reported Date", Type: TypeText},
+				{Header: "Hours", Type: TypeHours, Convert: true},
+			},
+			Options: types.ConversionOptions{DropFooter: true},
+		},
+		Vendor: "Workday",
+		Output: "{name}_workday_converted",
+	},
+}
+
+// PresetNames returns the names of the built-in presets.
+func PresetNames() []string {
+	names := make([]string, len(Presets))
+	for i, p := range Presets {
+		names[i] = p.Name
+	}
+	return names
+}
+
+// FindPreset returns the built-in preset of this name.
+func FindPreset(name string) (*Preset, error) {
+	for i := range Presets {
+		if Presets[i].Name == strings.ToLower(name) {
+			return &Presets[i], nil
+		}
+	}
+	return nil, fmt.Errorf("unknown preset %q (choose %s or %s)", name, PresetAuto, strings.Join(PresetNames(), ", "))
+}
+
+// MatchPreset returns the preset a file's headers were exported with, or
+// nil when none match. When several do, the one naming the most columns
+// is the most specific.
+func MatchPreset(data *types.FileData) *Preset {
+	var best *Preset
+	for i := range Presets {
+		p := &Presets[i]
+		if p.Check(data) != nil {
+			continue
+		}
+		if best == nil || len(p.Columns) > len(best.Columns) {
+			best = p
+		}
+	}
+	return best
+}
+
+// Check reports the preset's columns missing from a file's header in a
+// *MismatchError. Unlike Validate, columns may be anywhere and of any
+// type, as vendors let exports be customized.
+func (p Preset) Check(data *types.FileData) error {
+	headers := make(map[string]bool)
+	for _, header := range data.Headers {
+		headers[normalize(header)] = true
+	}
+
+	var mismatches []Mismatch
+	for _, col := range p.Columns {
+		if !headers[normalize(col.Header)] {
+			mismatches = append(mismatches, Mismatch{col.Header, "missing"})
+		}
+	}
+	if len(mismatches) > 0 {
+		return &MismatchError{Profile: p.Name, Mismatches: mismatches}
+	}
+	return nil
+}
+
+// Apply returns the columns to convert and the options for a file that
+// passed Check, with the footer rows found in it.
+func (p Preset) Apply(data *types.FileData) (map[int]bool, types.ConversionOptions) {
+	selected, opts := p.Profile.Apply(data)
+	opts.FooterRows = data.FooterRows
+	return selected, opts
+}
+
+// OutputName returns the name of an input's converted copy, without an
+// extension.
+func (p Preset) OutputName(input string) string {
+	name := strings.TrimSuffix(filepath.Base(input), filepath.Ext(input))
+	return strings.ReplaceAll(p.Output, "{name}", name)
+}
+
+// Describe summarizes what applying the preset does, for confirming it.
+func (p Preset) Describe() string {
+	var columns []string
+	for _, col := range p.Columns {
+		if col.Convert {
+			columns = append(columns, col.Header)
+		}
+	}
+	footer := ""
+	if p.Options.DropFooter {
+		footer = ", drops footer rows"
+	}
+	return fmt.Sprintf("converts %s%s and names the output %s", strings.Join(columns, " and "), footer, p.Output)
+}
@@ -0,0 +1,240 @@
+package profile
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/nconklindev/chronos/internal/config"
+	"github.com/nconklindev/chronos/internal/converter"
+	"github.com/nconklindev/chronos/internal/types"
+)
+
+// Dir is the folder under the config directory that holds saved profiles.
+const Dir = "profiles"
+
+// Column types inferred from sample values.
+const (
+	TypeHours  = "hours"  // Every value is a decimal hour
+	TypeNumber = "number" // Every value is a number, but not all are decimal hours
+	TypeText   = "text"
+	TypeEmpty  = "empty" // No sampled values, so the type is unknown
+)
+
+// Profile describes the expected layout of a recurring export and how to convert it.
+type Profile struct {
+	Name    string                  `json:"name"`
+	Columns []Column                `json:"columns"`
+	Options types.ConversionOptions `json:"options"`
+}
+
+// Column is one expected column, in file order.
+type Column struct {
+	Header  string `json:"header"`
+	Type    string `json:"type"`
+	Convert bool   `json:"convert"`
+}
+
+// FromFile builds a profile from a loaded file and the columns chosen for it.
+func FromFile(name string, data *types.FileData, selected map[int]bool, opts types.ConversionOptions) Profile {
+	p := Profile{Name: name, Options: opts}
+	for i, header := range data.Headers {
+		p.Columns = append(p.Columns, Column{
+			Header:  header,
+			Type:    InferType(data, i),
+			Convert: selected[i],
+		})
+	}
+	return p
+}
+
+// InferType classifies a column from its first RowDetectionLimit data rows.
+func InferType(data *types.FileData, col int) string {
+	dataRows := len(data.Rows) - data.FooterRows
+	typ := TypeEmpty
+
+	for j := 0; j < dataRows && j < converter.RowDetectionLimit; j++ {
+		if col >= len(data.Rows[j]) {
+			continue
+		}
+		val := strings.TrimSpace(data.Rows[j][col])
+		if val == "" {
+			continue
+		}
+
+		switch {
+		case converter.IsDecimalHour(val):
+			if typ == TypeEmpty {
+				typ = TypeHours
+			}
+		case isNumber(val):
+			if typ != TypeText {
+				typ = TypeNumber
+			}
+		default:
+			return TypeText
+		}
+	}
+	return typ
+}
+
+func isNumber(s string) bool {
+	_, err := strconv.ParseFloat(s, 64)
+	return err == nil
+}
+
+// Mismatch is one way a file differs from its profile.
+type Mismatch struct {
+	Header string
+	Detail string
+}
+
+// MismatchError reports every difference between a file and a profile.
+type MismatchError struct {
+	Profile    string
+	Mismatches []Mismatch
+}
+
+func (e *MismatchError) Error() string {
+	var s strings.Builder
+	fmt.Fprintf(&s, "file does not match profile %q:", e.Profile)
+	for _, m := range e.Mismatches {
+		fmt.Fprintf(&s, "\n  • %s: %s", m.Header, m.Detail)
+	}
+	return s.String()
+}
+
+// Validate checks a file against the profile's expected columns. Missing
+// columns, columns that moved, and columns whose type changed are reported
+// together in a *MismatchError. Extra columns are allowed.
+func (p Profile) Validate(data *types.FileData) error {
+	positions := make(map[string]int)
+	for i, header := range data.Headers {
+		key := normalize(header)
+		if _, seen := positions[key]; !seen {
+			positions[key] = i
+		}
+	}
+
+	var mismatches []Mismatch
+	for i, col := range p.Columns {
+		if strings.TrimSpace(col.Header) == "" {
+			continue
+		}
+
+		pos, ok := positions[normalize(col.Header)]
+		if !ok {
+			mismatches = append(mismatches, Mismatch{col.Header, "missing"})
+			continue
+		}
+		if pos != i {
+			mismatches = append(mismatches, Mismatch{col.Header, fmt.Sprintf("moved from column %d to %d", i+1, pos+1)})
+		}
+
+		actual := InferType(data, pos)
+		if col.Type != "" && col.Type != TypeEmpty && actual != TypeEmpty && actual != col.Type {
+			mismatches = append(mismatches, Mismatch{col.Header, fmt.Sprintf("expected %s values, found %s", col.Type, actual)})
+		}
+	}
+
+	if len(mismatches) > 0 {
+		return &MismatchError{Profile: p.Name, Mismatches: mismatches}
+	}
+	return nil
+}
+
+// Apply returns the columns to convert and the options for a file that passed Validate.
+func (p Profile) Apply(data *types.FileData) (map[int]bool, types.ConversionOptions) {
+	selected := make(map[int]bool)
+	for _, col := range p.Columns {
+		if !col.Convert {
+			continue
+		}
+		for i, header := range data.Headers {
+			if normalize(header) == normalize(col.Header) {
+				selected[i] = true
+				break
+			}
+		}
+	}
+	return selected, p.Options
+}
+
+func normalize(header string) string {
+	return strings.ToLower(strings.TrimSpace(header))
+}
+
+// path returns where a named profile is stored
+func path(name string) (string, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return "", fmt.Errorf("invalid profile name %q", name)
+	}
+	return config.Path(filepath.Join(Dir, name+".json"))
+}
+
+// Load reads a saved profile by name.
+func Load(name string) (*Profile, error) {
+	file, err := path(name)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(file)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("no profile named %q", name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var p Profile
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("reading profile %q: %w", name, err)
+	}
+	p.Name = name
+	return &p, nil
+}
+
+// Save writes a profile under its name, replacing any existing one.
+func Save(p Profile) error {
+	file, err := path(p.Name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, data, 0o644)
+}
+
+// List returns the names of the saved profiles.
+func List() ([]string, error) {
+	dir, err := config.Path(Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if name, ok := strings.CutSuffix(entry.Name(), ".json"); ok && !entry.IsDir() {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
@@ -0,0 +1,180 @@
+package profile
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nconklindev/chronos/internal/types"
+)
+
+func weeklyExport() *types.FileData {
+	return &types.FileData{
+		Headers: []string{"Employee", "Regular", "Overtime"},
+		Rows: [][]string{
+			{"Alice", "8.0", "1.5"},
+			{"Bob", "7.5", ""},
+		},
+	}
+}
+
+func TestInferType(t *testing.T) {
+	data := &types.FileData{
+		Headers: []string{"Name", "Hours", "Badge", "Notes"},
+		Rows: [][]string{
+			{"Alice", "8.0", "12000", ""},
+			{"Bob", "7.5", "3", ""},
+		},
+	}
+
+	expected := []string{TypeText, TypeHours, TypeNumber, TypeEmpty}
+	for i, want := range expected {
+		if got := InferType(data, i); got != want {
+			t.Errorf("InferType(%s) = %s; want %s", data.Headers[i], got, want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	p := FromFile("weekly", weeklyExport(), map[int]bool{1: true, 2: true}, types.ConversionOptions{})
+
+	if err := p.Validate(weeklyExport()); err != nil {
+		t.Errorf("Expected the original file to match, got %v", err)
+	}
+
+	changed := &types.FileData{
+		Headers: []string{"Overtime", "Employee", "Regular", "Department"},
+		Rows: [][]string{
+			{"1.5", "Alice", "eight", "Ops"},
+		},
+	}
+
+	var mismatch *MismatchError
+	if !errors.As(p.Validate(changed), &mismatch) {
+		t.Fatal("Expected a MismatchError")
+	}
+
+	expected := []Mismatch{
+		{"Employee", "moved from column 1 to 2"},
+		{"Regular", "moved from column 2 to 3"},
+		{"Regular", "expected hours values, found text"},
+		{"Overtime", "moved from column 3 to 1"},
+	}
+	if len(mismatch.Mismatches) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, mismatch.Mismatches)
+	}
+	for i, want := range expected {
+		if mismatch.Mismatches[i] != want {
+			t.Errorf("Mismatch %d: expected %v, got %v", i, want, mismatch.Mismatches[i])
+		}
+	}
+
+	missing := &types.FileData{Headers: []string{"Employee", "Regular"}}
+	if err := p.Validate(missing); !errors.As(err, &mismatch) || mismatch.Mismatches[0].Detail != "missing" {
+		t.Errorf("Expected a missing column, got %v", err)
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	p := FromFile("weekly", weeklyExport(), map[int]bool{1: true}, types.ConversionOptions{KeepOriginal: true})
+	if err := Save(p); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded, err := Load("weekly")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	selected, opts := loaded.Apply(weeklyExport())
+	if !selected[1] || selected[2] || !opts.KeepOriginal {
+		t.Errorf("Unexpected applied profile: %v %+v", selected, opts)
+	}
+
+	if _, err := Load("../config"); err == nil {
+		t.Error("Expected an error for an invalid name")
+	}
+}
+
+func TestMatchPreset(t *testing.T) {
+	ukg := &types.FileData{
+		Headers: []string{"Employee ID", "Employee Full Name", "Location", "Apply Date", "Paycode", "Hours"},
+		Rows: [][]string{
+			{"1001", "Alice", "Ops", "2024-03-04", "REG", "8.5"},
+			{"1002", "Bob", "Ops", "2024-03-04", "OT", "1.25"},
+			{"", "Total", "", "", "", "9.75"},
+		},
+		FooterRows: 1,
+	}
+
+	p := MatchPreset(ukg)
+	if p == nil || p.Name != "ukg" {
+		t.Fatalf("Expected the ukg preset, got %v", p)
+	}
+	selected, opts := p.Apply(ukg)
+	if len(selected) != 1 || !selected[5] || !opts.DropFooter || opts.FooterRows != 1 {
+		t.Errorf("Unexpected applied preset: %v %+v", selected, opts)
+	}
+	if got := p.OutputName("/exports/week 10.csv"); got != "week 10_ukg_converted" {
+		t.Errorf("Unexpected output name %q", got)
+	}
+
+	// Kronos exports share the Hours column but not the rest
+	if p := MatchPreset(weeklyExport()); p != nil {
+		t.Errorf("Expected no preset for a plain export, got %s", p.Name)
+	}
+	kronos, _ := FindPreset("Kronos")
+	var mismatch *MismatchError
+	if !errors.As(kronos.Check(ukg), &mismatch) || len(mismatch.Mismatches) != 3 {
+		t.Errorf("Expected three missing Kronos columns, got %v", kronos.Check(ukg))
+	}
+	if _, err := FindPreset("paychex"); err == nil {
+		t.Error("Expected an error for an unknown preset")
+	}
+}
+
+func TestExportImport(t *testing.T) {
+	p := FromFile("weekly", weeklyExport(), map[int]bool{1: true}, types.ConversionOptions{KeepOriginal: true})
+	var buf bytes.Buffer
+	if err := Export(&buf, p); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `"format": "chronos-preset"`) {
+		t.Errorf("Expected the file marked as a shared profile, got:\n%s", buf.String())
+	}
+
+	imported, err := Import(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if !reflect.DeepEqual(*imported, p) {
+		t.Errorf("Expected %+v, got %+v", p, *imported)
+	}
+
+	// Shared links are downloaded
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(buf.Bytes())
+	}))
+	defer srv.Close()
+	if imported, err := ImportURL(srv.Client(), srv.URL+"/weekly"+SharedExt); err != nil || imported.Name != "weekly" {
+		t.Errorf("Expected the linked profile, got %v, %v", imported, err)
+	}
+
+	for name, text := range map[string]string{
+		"other JSON":      `{"name": "weekly", "columns": [{"header": "Regular", "convert": true}]}`,
+		"newer version":   `{"format": "chronos-preset", "version": 2, "name": "weekly", "columns": [{"header": "Regular", "convert": true}]}`,
+		"invalid name":    `{"format": "chronos-preset", "version": 1, "name": "../weekly", "columns": [{"header": "Regular", "convert": true}]}`,
+		"nothing to do":   `{"format": "chronos-preset", "version": 1, "name": "weekly", "columns": [{"header": "Regular"}]}`,
+		"not JSON at all": `Employee,Regular`,
+	} {
+		if _, err := Import(strings.NewReader(text)); err == nil {
+			t.Errorf("%s: expected the file to be refused", name)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package profile
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/nconklindev/chronos/internal/converter"
+)
+
+// SharedExt names the files profiles are shared in.
+const SharedExt = ".chronos-preset.json"
+
+// sharedFormat and sharedVersion mark a shared profile, so other JSON
+// isn't mistaken for one and newer files are refused rather than misread.
+const (
+	sharedFormat  = "chronos-preset"
+	sharedVersion = 1
+)
+
+// maxSharedSize is the largest shared profile read, well over any real one.
+const maxSharedSize = 1 << 20
+
+// shared is a profile as written to share it.
+type shared struct {
+	Format  string `json:"format"`
+	Version int    `json:"version"`
+	Profile
+}
+
+// IsShared reports whether a profile argument names a shared profile file
+// or link rather than a saved profile.
+func IsShared(s string) bool {
+	return converter.IsURL(s) || strings.HasSuffix(strings.ToLower(s), SharedExt)
+}
+
+// Export writes a profile to share with others.
+func Export(w io.Writer, p Profile) error {
+	data, err := json.MarshalIndent(shared{Format: sharedFormat, Version: sharedVersion, Profile: p}, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// ExportFile writes a profile to <dir>/<name>.chronos-preset.json and
+// returns the file's path.
+func ExportFile(p Profile, dir string) (string, error) {
+	file := filepath.Join(dir, p.Name+SharedExt)
+	f, err := os.Create(file)
+	if err != nil {
+		return "", err
+	}
+	if err := Export(f, p); err != nil {
+		f.Close()
+		return "", err
+	}
+	return file, f.Close()
+}
+
+// Import reads a shared profile. Files that aren't shared profiles, are
+// from a newer version or have no columns to convert are refused.
+func Import(r io.Reader) (*Profile, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxSharedSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxSharedSize {
+		return nil, fmt.Errorf("not a shared profile: over %d bytes", maxSharedSize)
+	}
+
+	var s shared
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("not a shared profile: %w", err)
+	}
+	switch {
+	case s.Format != sharedFormat:
+		return nil, fmt.Errorf("not a shared profile")
+	case s.Version > sharedVersion:
+		return nil, fmt.Errorf("profile %q is from a newer version of chronos", s.Name)
+	}
+	if _, err := path(s.Name); err != nil {
+		return nil, err
+	}
+
+	converts := false
+	for _, col := range s.Columns {
+		converts = converts || col.Convert
+	}
+	if !converts {
+		return nil, fmt.Errorf("profile %q has no columns to convert", s.Name)
+	}
+	return &s.Profile, nil
+}
+
+// ImportFile reads a shared profile from a file.
+func ImportFile(file string) (*Profile, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Import(f)
+}
+
+// importTimeout is how long downloading a shared profile may take, so a
+// server that stops answering doesn't hang the import.
+const importTimeout = 30 * time.Second
+
+// ImportURL downloads a shared profile, with a client that gives up after
+// importTimeout when client is nil.
+func ImportURL(client *http.Client, url string) (*Profile, error) {
+	if client == nil {
+		client = &http.Client{Timeout: importTimeout}
+	}
+	resp, err := client.Get(strings.TrimSpace(url))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("download failed: %s", resp.Status)
+	}
+	return Import(resp.Body)
+}
+
+// Open returns the profile a --profile argument names: a shared profile
+// file or link, read without being saved, or otherwise a saved profile.
+func Open(arg string) (*Profile, error) {
+	switch {
+	case converter.IsURL(arg):
+		return ImportURL(nil, arg)
+	case IsShared(arg):
+		return ImportFile(arg)
+	}
+	return Load(arg)
+}
@@ -0,0 +1,197 @@
+package server
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/nconklindev/chronos/internal/converter"
+	"github.com/nconklindev/chronos/internal/types"
+)
+
+// MaxUploadSize is the largest file the server accepts.
+const MaxUploadSize = 50 << 20
+
+//go:embed index.html
+var indexHTML []byte
+
+// Handler serves the upload page and the conversion API:
+//
+//	GET  /             upload form
+//	POST /api/detect   multipart "file"; returns the headers and auto-detected columns as JSON
+//	POST /api/convert  multipart "file", optional "columns", "keep_original", "placement", "new_sheet", "comment_originals" and "table"; returns the converted file
+//
+// "columns" is a comma-separated list of header names or 0-based indices.
+// When it's empty the auto-detected columns are converted.
+func Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", handleIndex)
+	mux.HandleFunc("POST /api/detect", handleDetect)
+	mux.HandleFunc("POST /api/convert", handleConvert)
+	return mux
+}
+
+// ListenAndServe starts the server on addr. The server has no
+// authentication, so addr should be a loopback address unless everyone who
+// can reach it may convert files.
+func ListenAndServe(addr string) error {
+	return http.ListenAndServe(addr, Handler())
+}
+
+func handleIndex(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(indexHTML)
+}
+
+type detectResponse struct {
+	Headers  []string `json:"headers"`
+	Detected []int    `json:"detected"`
+}
+
+func handleDetect(w http.ResponseWriter, r *http.Request) {
+	path, cleanup, err := saveUpload(w, r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer cleanup()
+
+	data, err := converter.ReadFileData(path)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(detectResponse{
+		Headers:  data.Headers,
+		Detected: converter.AutoDetectColumns(data),
+	})
+}
+
+func handleConvert(w http.ResponseWriter, r *http.Request) {
+	path, cleanup, err := saveUpload(w, r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer cleanup()
+
+	data, err := converter.ReadFileData(path)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+
+	columns, err := converter.ResolveColumns(r.FormValue("columns"), data.Headers)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(columns) == 0 {
+		columns = converter.AutoDetectColumns(data)
+	}
+	if len(columns) == 0 {
+		http.Error(w, "no decimal hour columns were detected; choose columns to convert", http.StatusUnprocessableEntity)
+		return
+	}
+
+	placement, err := types.ParsePlacement(r.FormValue("placement"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	table := strings.TrimSpace(r.FormValue("table"))
+	if table != "" {
+		if err := converter.CheckTableName(table); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	opts := types.ConversionOptions{
+		KeepOriginal:     r.FormValue("keep_original") != "",
+		Placement:        placement,
+		NewSheet:         r.FormValue("new_sheet") != "",
+		CommentOriginals: r.FormValue("comment_originals") != "",
+		Table:            table,
+		CSVOutput:        r.FormValue("csv_output") != "",
+		FooterRows:       data.FooterRows,
+	}
+
+	// Buffer the output so a failed conversion can still return an error status
+	var out bytes.Buffer
+	result, err := converter.Convert(path, converter.WriterSink{W: &out}, columns, opts, nil)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+
+	ext := converter.OutputExt(path, opts)
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)) + "_converted" + ext
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+	w.Header().Set("Content-Type", contentType(ext))
+	w.Header().Set("X-Chronos-Rows", strconv.Itoa(result.RowsProcessed))
+	w.Header().Set("X-Chronos-Columns", strings.Join(result.ColumnsFound, ","))
+	w.Write(out.Bytes())
+}
+
+// saveUpload copies the uploaded "file" field to a temp directory, keeping its
+// name so the converter can tell the file type. cleanup removes the copy.
+func saveUpload(w http.ResponseWriter, r *http.Request) (string, func(), error) {
+	r.Body = http.MaxBytesReader(w, r.Body, MaxUploadSize)
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		return "", nil, fmt.Errorf("upload a CSV, XLSX or JSON file in the \"file\" field: %w", err)
+	}
+	defer file.Close()
+
+	name := filepath.Base(header.Filename)
+	ext := strings.ToLower(filepath.Ext(name))
+	if ext != ".csv" && ext != ".xlsx" && !converter.IsJSON(name) {
+		return "", nil, fmt.Errorf("unsupported file type: %s", ext)
+	}
+
+	dir, err := os.MkdirTemp("", "chronos-upload-")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() { os.RemoveAll(dir) }
+
+	path := filepath.Join(dir, name)
+	out, err := os.Create(path)
+	if err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	_, err = io.Copy(out, file)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		cleanup()
+		return "", nil, err
+	}
+
+	return path, cleanup, nil
+}
+
+func contentType(ext string) string {
+	switch strings.ToLower(ext) {
+	case ".xlsx":
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case ".json":
+		return "application/json"
+	case ".ndjson":
+		return "application/x-ndjson"
+	}
+	return "text/csv"
+}
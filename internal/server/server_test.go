@@ -0,0 +1,115 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func upload(t *testing.T, url, name, content string, fields map[string]string) *http.Response {
+	t.Helper()
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	fw, err := mw.CreateFormFile("file", name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.WriteString(fw, content)
+	for k, v := range fields {
+		mw.WriteField(k, v)
+	}
+	mw.Close()
+
+	resp, err := http.Post(url, mw.FormDataContentType(), &body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+func TestDetect(t *testing.T) {
+	server := httptest.NewServer(Handler())
+	defer server.Close()
+
+	resp := upload(t, server.URL+"/api/detect", "hours.csv", "Name,Hours\nAlice,1.5\n", nil)
+	defer resp.Body.Close()
+
+	var got detectResponse
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Headers) != 2 || len(got.Detected) != 1 || got.Detected[0] != 1 {
+		t.Errorf("Unexpected detection: %+v", got)
+	}
+}
+
+func TestConvert(t *testing.T) {
+	server := httptest.NewServer(Handler())
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		fields   map[string]string
+		status   int
+		expected string
+	}{
+		{"Auto-detected columns", nil, http.StatusOK, "Name,Hours\nAlice,01:30\n"},
+		{"Columns by name", map[string]string{"columns": "hours", "keep_original": "on"}, http.StatusOK, "Name,Hours,Hours (HH:MM)\nAlice,1.5,01:30\n"},
+		{"Unknown column", map[string]string{"columns": "Overtime"}, http.StatusBadRequest, ""},
+		{"Unknown placement", map[string]string{"keep_original": "on", "placement": "first"}, http.StatusBadRequest, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := upload(t, server.URL+"/api/convert", "hours.csv", "Name,Hours\nAlice,1.5\n", tt.fields)
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.status {
+				t.Fatalf("Expected status %d, got %d", tt.status, resp.StatusCode)
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+
+			body, _ := io.ReadAll(resp.Body)
+			if string(body) != tt.expected {
+				t.Errorf("Unexpected output: %q", body)
+			}
+			if got := resp.Header.Get("Content-Disposition"); got != `attachment; filename="hours_converted.csv"` {
+				t.Errorf("Unexpected Content-Disposition: %s", got)
+			}
+		})
+	}
+}
+
+func TestConvert_UnsupportedType(t *testing.T) {
+	server := httptest.NewServer(Handler())
+	defer server.Close()
+
+	resp := upload(t, server.URL+"/api/convert", "notes.txt", "hello", nil)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", resp.StatusCode)
+	}
+}
+
+func TestConvert_JSON(t *testing.T) {
+	server := httptest.NewServer(Handler())
+	defer server.Close()
+
+	resp := upload(t, server.URL+"/api/convert", "hours.ndjson", "{\"name\": \"Alice\", \"hours\": 1.5}\n", map[string]string{"csv_output": "on"})
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || string(body) != "name,hours\nAlice,01:30\n" {
+		t.Fatalf("Unexpected response %d: %q", resp.StatusCode, body)
+	}
+	if got := resp.Header.Get("Content-Disposition"); got != `attachment; filename="hours_converted.csv"` {
+		t.Errorf("Unexpected Content-Disposition: %s", got)
+	}
+}
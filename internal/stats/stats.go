@@ -0,0 +1,109 @@
+package stats
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+
+	"github.com/nconklindev/chronos/internal/config"
+	"github.com/nconklindev/chronos/internal/types"
+)
+
+// StatsFile holds the running totals across every run.
+const StatsFile = "stats.json"
+
+// SecondsPerCell is roughly how long it takes to retype one decimal hour
+// value as HH:MM by hand, including finding the cell and checking it.
+const SecondsPerCell = 6
+
+// Stats are the lifetime totals shown to the user.
+type Stats struct {
+	Runs         int     `json:"runs"`
+	Files        int     `json:"files"`
+	Rows         int     `json:"rows"`
+	Cells        int     `json:"cells"`
+	MinutesSaved float64 `json:"minutes_saved"`
+}
+
+// CellsConverted counts the cells whose value a conversion changed across
+// results
+func CellsConverted(results []*types.ConversionResult) int {
+	cells := 0
+	for _, res := range results {
+		cells += res.CellsChanged
+	}
+	return cells
+}
+
+// EstimateMinutes estimates how long converting cells by hand would take
+func EstimateMinutes(cells int) float64 {
+	return float64(cells) * SecondsPerCell / 60
+}
+
+// FormatMinutes renders an estimate for display, e.g. "~45 minutes"
+func FormatMinutes(minutes float64) string {
+	switch {
+	case minutes < 1:
+		return "under a minute"
+	case minutes < 60:
+		return fmt.Sprintf("~%d minutes", int(math.Round(minutes)))
+	default:
+		return fmt.Sprintf("~%.1f hours", minutes/60)
+	}
+}
+
+// Load reads the saved totals. Missing stats are returned as zero.
+func Load() (Stats, error) {
+	var s Stats
+
+	path, err := config.Path(StatsFile)
+	if err != nil {
+		return s, err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return s, err
+	}
+
+	err = json.Unmarshal(data, &s)
+	return s, err
+}
+
+// Save writes the totals to the config directory.
+func Save(s Stats) error {
+	dir, err := config.EnsureDir()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, StatsFile), data, 0o644)
+}
+
+// Record adds a finished run to the saved totals and returns the new totals.
+func Record(results []*types.ConversionResult) (Stats, error) {
+	s, err := Load()
+	if err != nil {
+		return s, err
+	}
+
+	cells := CellsConverted(results)
+	s.Runs++
+	s.Files += len(results)
+	s.Cells += cells
+	s.MinutesSaved += EstimateMinutes(cells)
+	for _, res := range results {
+		s.Rows += res.RowsProcessed
+	}
+
+	return s, Save(s)
+}
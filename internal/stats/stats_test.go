@@ -0,0 +1,56 @@
+package stats
+
+import (
+	"testing"
+
+	"github.com/nconklindev/chronos/internal/types"
+)
+
+func TestFormatMinutes(t *testing.T) {
+	tests := []struct {
+		minutes  float64
+		expected string
+	}{
+		{0.5, "under a minute"},
+		{44.6, "~45 minutes"},
+		{90, "~1.5 hours"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatMinutes(tt.minutes); got != tt.expected {
+			t.Errorf("FormatMinutes(%v) = %s; want %s", tt.minutes, got, tt.expected)
+		}
+	}
+}
+
+func TestRecord(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	results := []*types.ConversionResult{
+		{ColumnsFound: []string{"Regular", "Overtime"}, RowsProcessed: 100, CellsChanged: 200},
+		{ColumnsFound: []string{"Hours"}, RowsProcessed: 50, CellsChanged: 50},
+	}
+
+	if _, err := Record(results); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	s, err := Record(results)
+	if err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+
+	if s.Runs != 2 || s.Files != 4 || s.Rows != 300 || s.Cells != 500 {
+		t.Errorf("Unexpected totals: %+v", s)
+	}
+	if s.MinutesSaved != EstimateMinutes(500) {
+		t.Errorf("Expected %v minutes saved, got %v", EstimateMinutes(500), s.MinutesSaved)
+	}
+
+	loaded, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded != s {
+		t.Errorf("Expected saved totals %+v, got %+v", s, loaded)
+	}
+}
@@ -0,0 +1,301 @@
+package summary
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/nconklindev/chronos/internal/stats"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// Pages are US Letter, in points.
+const (
+	pdfPageWidth  = 612
+	pdfPageHeight = 792
+	pdfMargin     = 54
+	pdfFontSize   = 10
+	pdfLine       = 14
+)
+
+// helveticaWidths are the widths of the printable ASCII characters in
+// Helvetica, in thousandths of the font size, from its font metrics.
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278, // space to /
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, // 0 to 9
+	278, 278, 584, 584, 584, 556, 1015, // : to @
+	667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, // A to M
+	722, 778, 667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, // N to Z
+	278, 278, 278, 469, 556, 333, // [ to `
+	556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, // a to m
+	556, 556, 556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, // n to z
+	334, 260, 334, 584, // { to ~
+}
+
+// textWidth estimates the width of text in Helvetica at size, in points.
+// Bold text runs about a tenth wider.
+func textWidth(text string, size float64, bold bool) float64 {
+	width := 0
+	for _, r := range text {
+		if r >= ' ' && r <= '~' {
+			width += helveticaWidths[r-' ']
+		} else {
+			width += 556
+		}
+	}
+	w := float64(width) * size / 1000
+	if bold {
+		w *= 1.1
+	}
+	return w
+}
+
+// fitText shortens text with an ellipsis to fit width.
+func fitText(text string, width, size float64, bold bool) string {
+	if textWidth(text, size, bold) <= width {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 && textWidth(string(runes)+"...", size, bold) > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "..."
+}
+
+// pdfColumn is a column of a table in a PDF report.
+type pdfColumn struct {
+	title string
+	width float64
+	right bool // Right-aligned, as numbers are
+}
+
+// pdfDoc lays out a text document a line at a time, starting new pages as
+// each fills up.
+type pdfDoc struct {
+	pages []*bytes.Buffer
+	y     float64 // The baseline of the next line on the last page
+}
+
+func (d *pdfDoc) newPage() {
+	d.pages = append(d.pages, new(bytes.Buffer))
+	d.y = pdfPageHeight - pdfMargin
+}
+
+// space moves down by height, starting a new page when there's no room for
+// it.
+func (d *pdfDoc) space(height float64) {
+	if len(d.pages) == 0 || d.y-height < pdfMargin {
+		d.newPage()
+		return
+	}
+	d.y -= height
+}
+
+// text writes text at x on the current line.
+func (d *pdfDoc) text(x float64, text string, size float64, bold bool) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	page := d.pages[len(d.pages)-1]
+	fmt.Fprintf(page, "BT /%s %s Tf %s %s Td (%s) Tj ET\n", font, pdfNumber(size), pdfNumber(x), pdfNumber(d.y), pdfString(text))
+}
+
+// rule draws a line across the page just below the current line.
+func (d *pdfDoc) rule() {
+	page := d.pages[len(d.pages)-1]
+	y := d.y - 4
+	fmt.Fprintf(page, "0.75 G 0.5 w %d %s m %d %s l S 0 G\n", pdfMargin, pdfNumber(y), pdfPageWidth-pdfMargin, pdfNumber(y))
+}
+
+func (d *pdfDoc) heading(text string, size float64) {
+	d.space(size + 10)
+	d.text(pdfMargin, text, size, true)
+	d.space(4)
+}
+
+// paragraph writes text wrapped to the page's width.
+func (d *pdfDoc) paragraph(text string, indent float64) {
+	width := pdfPageWidth - 2*pdfMargin - indent
+	line := ""
+	for _, word := range strings.Fields(text) {
+		next := strings.TrimSpace(line + " " + word)
+		if line != "" && textWidth(next, pdfFontSize, false) > width {
+			d.space(pdfLine)
+			d.text(pdfMargin+indent, fitText(line, width, pdfFontSize, false), pdfFontSize, false)
+			line = word
+			continue
+		}
+		line = next
+	}
+	if line != "" {
+		d.space(pdfLine)
+		d.text(pdfMargin+indent, fitText(line, width, pdfFontSize, false), pdfFontSize, false)
+	}
+}
+
+// row writes a row of a table, shortening cells to fit their columns.
+func (d *pdfDoc) row(columns []pdfColumn, cells []string, bold bool) {
+	d.space(pdfLine)
+	x := float64(pdfMargin)
+	for i, col := range columns {
+		if i < len(cells) {
+			cell := fitText(cells[i], col.width-8, pdfFontSize, bold)
+			cx := x
+			if col.right {
+				cx = x + col.width - 8 - textWidth(cell, pdfFontSize, bold)
+			}
+			d.text(cx, cell, pdfFontSize, bold)
+		}
+		x += col.width
+	}
+}
+
+// table writes a table with a header row ruled off from the rest.
+func (d *pdfDoc) table(columns []pdfColumn, rows [][]string) {
+	titles := make([]string, len(columns))
+	for i, col := range columns {
+		titles[i] = col.title
+	}
+	d.row(columns, titles, true)
+	d.rule()
+	d.space(4)
+	for _, row := range rows {
+		d.row(columns, row, false)
+	}
+	d.space(pdfLine / 2)
+}
+
+// pdfString escapes text for a PDF string in the fonts' WinAnsi encoding,
+// replacing characters it doesn't have.
+func pdfString(text string) string {
+	var s strings.Builder
+	enc := charmap.Windows1252
+	for _, r := range text {
+		b, ok := enc.EncodeRune(r)
+		if !ok {
+			b = '?'
+		}
+		switch {
+		case b == '(' || b == ')' || b == '\\':
+			s.WriteByte('\\')
+			s.WriteByte(b)
+		case b < ' ' || b > '~':
+			fmt.Fprintf(&s, "\\%03o", b)
+		default:
+			s.WriteByte(b)
+		}
+	}
+	return s.String()
+}
+
+// pdfNumber formats a coordinate or size compactly.
+func pdfNumber(n float64) string {
+	return strconv.FormatFloat(math.Round(n*100)/100, 'f', -1, 64)
+}
+
+// formatHours writes hours as H:MM, as converted values are.
+func formatHours(hours float64) string {
+	minutes := int(math.Round(math.Abs(hours) * 60))
+	sign := ""
+	if hours < 0 && minutes > 0 {
+		sign = "-"
+	}
+	return fmt.Sprintf("%s%d:%02d", sign, minutes/60, minutes%60)
+}
+
+// writeReportPDF writes the report as a printable PDF: the totals, the
+// hours of each column, the files and their warnings.
+func writeReportPDF(w io.Writer, report Report) error {
+	var d pdfDoc
+	d.heading("Conversion report", 18)
+	d.paragraph(fmt.Sprintf("Converted %s with chronos %s.", report.CreatedAt, report.Version), 0)
+
+	t := report.Totals
+	d.heading("Totals", 13)
+	d.table([]pdfColumn{
+		{"Files", 60, true}, {"Rows", 80, true}, {"Cells", 80, true},
+		{"Rounding losses", 100, true}, {"Warnings", 70, true}, {"Time saved", 110, false},
+	}, [][]string{{
+		strconv.Itoa(t.Files), strconv.Itoa(t.Rows), strconv.Itoa(t.Cells),
+		strconv.Itoa(t.RoundingLosses), strconv.Itoa(t.Warnings), stats.FormatMinutes(t.MinutesSaved),
+	}})
+
+	if len(report.Hours) > 0 {
+		d.heading("Hours by column", 13)
+		var rows [][]string
+		for _, h := range report.Hours {
+			rows = append(rows, []string{h.Column, strconv.Itoa(h.Cells), formatHours(h.Hours), strconv.FormatFloat(h.Hours, 'f', 2, 64)})
+		}
+		d.table([]pdfColumn{{"Column", 224, false}, {"Cells", 80, true}, {"Hours", 100, true}, {"Decimal hours", 100, true}}, rows)
+	}
+
+	d.heading("Files", 13)
+	var rows [][]string
+	for _, file := range report.Files {
+		rows = append(rows, []string{filepath.Base(file.Input), filepath.Base(file.Output), strings.Join(file.Columns, ", "), strconv.Itoa(file.RowsProcessed), strconv.Itoa(len(file.Warnings))})
+	}
+	d.table([]pdfColumn{{"Input", 130, false}, {"Output", 130, false}, {"Columns", 124, false}, {"Rows", 60, true}, {"Warnings", 60, true}}, rows)
+
+	if t.Warnings > 0 {
+		d.heading("Warnings", 13)
+		for _, file := range report.Files {
+			if len(file.Warnings) == 0 {
+				continue
+			}
+			d.space(4)
+			d.space(pdfLine)
+			d.text(pdfMargin, filepath.Base(file.Input), pdfFontSize, true)
+			for _, warning := range file.Warnings {
+				d.paragraph("- "+warning, 10)
+			}
+		}
+	}
+
+	return writePDF(w, d.pages)
+}
+
+// writePDF writes pages of content streams as a PDF document, numbering
+// each page at its foot. Text is set in Helvetica and Helvetica-Bold.
+func writePDF(w io.Writer, pages []*bytes.Buffer) error {
+	var out bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	// Objects 1 to 4 are the catalog, the page tree and the fonts; each
+	// page is followed by its content stream
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	for i, page := range pages {
+		footer := fmt.Sprintf("Page %d of %d", i+1, len(pages))
+		x := (pdfPageWidth - textWidth(footer, 8, false)) / 2
+		content := page.String() + fmt.Sprintf("BT /F1 8 Tf %s %d Td (%s) Tj ET\n", pdfNumber(x), pdfMargin/2, footer)
+
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>", pdfPageWidth, pdfPageHeight, 6+2*i))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", len(content), content))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	_, err := w.Write(out.Bytes())
+	return err
+}
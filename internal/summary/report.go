@@ -0,0 +1,210 @@
+package summary
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nconklindev/chronos/internal/stats"
+)
+
+// ReportFormat is a file format a batch report can be exported in.
+type ReportFormat string
+
+const (
+	ReportCSV      ReportFormat = "csv"
+	ReportJSON     ReportFormat = "json"
+	ReportMarkdown ReportFormat = "md"
+	ReportPDF      ReportFormat = "pdf"
+)
+
+// ReportFormats lists the formats reports can be exported in.
+var ReportFormats = []ReportFormat{ReportCSV, ReportJSON, ReportMarkdown, ReportPDF}
+
+// ParseReportFormat checks a report format name.
+func ParseReportFormat(name string) (ReportFormat, error) {
+	for _, f := range ReportFormats {
+		if string(f) == name {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unknown report format %q (choose csv, json, md or pdf)", name)
+}
+
+// Report describes a finished batch for attaching to a ticket: each file's
+// results and warnings, and totals for the whole batch.
+type Report struct {
+	Version   string       `json:"version"`
+	CreatedAt string       `json:"created_at"`
+	Totals    ReportTotals `json:"totals"`
+	// Hours totals each column converted as durations across the files.
+	Hours []ColumnHours `json:"hours,omitempty"`
+	Files []File        `json:"files"`
+}
+
+// ReportTotals sums up a batch.
+type ReportTotals struct {
+	Files          int     `json:"files"`
+	Rows           int     `json:"rows"`
+	Cells          int     `json:"cells"`
+	Warnings       int     `json:"warnings"`
+	RoundingLosses int     `json:"rounding_losses"`
+	MinutesSaved   float64 `json:"minutes_saved"`
+}
+
+// NewReport builds a report from a run summary.
+func NewReport(run Run) Report {
+	report := Report{Version: run.Version, CreatedAt: run.CreatedAt, Files: run.Files}
+	columns := make(map[string]int)
+	for _, file := range run.Files {
+		for _, h := range file.Hours {
+			i, ok := columns[h.Column]
+			if !ok {
+				i = len(report.Hours)
+				columns[h.Column] = i
+				report.Hours = append(report.Hours, ColumnHours{Column: h.Column})
+			}
+			report.Hours[i].Cells += h.Cells
+			report.Hours[i].Hours += h.Hours
+		}
+
+		report.Totals.Files++
+		report.Totals.Rows += file.RowsProcessed
+		report.Totals.Cells += file.CellsChanged
+		report.Totals.Warnings += len(file.Warnings)
+		report.Totals.RoundingLosses += len(file.RoundingLosses)
+	}
+	report.Totals.MinutesSaved = stats.EstimateMinutes(report.Totals.Cells)
+	return report
+}
+
+// ReportPath returns where a report exported at t is saved in dir.
+func ReportPath(dir string, format ReportFormat, t time.Time) string {
+	return filepath.Join(dir, fmt.Sprintf("chronos-report-%s.%s", t.Format("20060102-150405"), format))
+}
+
+// WriteReport saves a report in the given format.
+func WriteReport(path string, report Report, format ReportFormat) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case ReportCSV:
+		err = writeReportCSV(f, report)
+	case ReportJSON:
+		err = writeReportJSON(f, report)
+	case ReportMarkdown:
+		err = writeReportMarkdown(f, report)
+	case ReportPDF:
+		err = writeReportPDF(f, report)
+	default:
+		err = fmt.Errorf("unknown report format %q", format)
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// writeReportJSON writes the report as indented JSON, with each file's
+// options and checksums.
+func writeReportJSON(w io.Writer, report Report) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// writeReportCSV writes a row per file and a last row of totals.
+// Columns and warnings are joined into one cell each.
+func writeReportCSV(w io.Writer, report Report) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"Input", "Output", "Columns", "Rows", "Cells", "Rounding losses", "Warnings"})
+	for _, file := range report.Files {
+		cw.Write([]string{
+			file.Input,
+			file.Output,
+			strings.Join(file.Columns, "; "),
+			strconv.Itoa(file.RowsProcessed),
+			strconv.Itoa(file.CellsChanged),
+			strconv.Itoa(len(file.RoundingLosses)),
+			strings.Join(file.Warnings, "; "),
+		})
+	}
+	t := report.Totals
+	cw.Write([]string{
+		fmt.Sprintf("Total (%d files)", t.Files),
+		"",
+		"",
+		strconv.Itoa(t.Rows),
+		strconv.Itoa(t.Cells),
+		strconv.Itoa(t.RoundingLosses),
+		strconv.Itoa(t.Warnings),
+	})
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeReportMarkdown writes the report as tables of the totals and files,
+// followed by each file's warnings.
+func writeReportMarkdown(w io.Writer, report Report) error {
+	var s strings.Builder
+	s.WriteString("# Conversion report\n\n")
+	fmt.Fprintf(&s, "Converted %s with chronos %s.\n\n", report.CreatedAt, report.Version)
+
+	t := report.Totals
+	s.WriteString("| Files | Rows | Cells | Rounding losses | Warnings | Time saved |\n")
+	s.WriteString("| ---: | ---: | ---: | ---: | ---: | --- |\n")
+	fmt.Fprintf(&s, "| %d | %d | %d | %d | %d | %s |\n\n", t.Files, t.Rows, t.Cells, t.RoundingLosses, t.Warnings, stats.FormatMinutes(t.MinutesSaved))
+
+	if len(report.Hours) > 0 {
+		s.WriteString("## Hours by column\n\n")
+		s.WriteString("| Column | Cells | Hours | Decimal hours |\n")
+		s.WriteString("| --- | ---: | ---: | ---: |\n")
+		for _, h := range report.Hours {
+			fmt.Fprintf(&s, "| %s | %d | %s | %.2f |\n", markdownCell(h.Column), h.Cells, formatHours(h.Hours), h.Hours)
+		}
+		s.WriteString("\n")
+	}
+
+	s.WriteString("## Files\n\n")
+	s.WriteString("| Input | Output | Columns | Rows | Cells | Warnings |\n")
+	s.WriteString("| --- | --- | --- | ---: | ---: | ---: |\n")
+	for _, file := range report.Files {
+		fmt.Fprintf(&s, "| %s | %s | %s | %d | %d | %d |\n",
+			markdownCell(file.Input), markdownCell(file.Output), markdownCell(strings.Join(file.Columns, ", ")),
+			file.RowsProcessed, file.CellsChanged, len(file.Warnings))
+	}
+
+	if t.Warnings > 0 {
+		s.WriteString("\n## Warnings\n")
+		for _, file := range report.Files {
+			if len(file.Warnings) == 0 {
+				continue
+			}
+			fmt.Fprintf(&s, "\n### %s\n\n", filepath.Base(file.Input))
+			for _, warning := range file.Warnings {
+				fmt.Fprintf(&s, "- %s\n", warning)
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, s.String())
+	return err
+}
+
+// markdownCell escapes a value for a Markdown table cell.
+func markdownCell(value string) string {
+	value = strings.ReplaceAll(value, "|", `\|`)
+	return strings.ReplaceAll(value, "\n", " ")
+}
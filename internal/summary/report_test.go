@@ -0,0 +1,138 @@
+package summary
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nconklindev/chronos/internal/types"
+)
+
+func TestWriteReport(t *testing.T) {
+	dir := t.TempDir()
+	results := []*types.ConversionResult{
+		{InputFile: filepath.Join(dir, "a.csv"), OutputFile: filepath.Join(dir, "a_converted.csv"), ColumnsFound: []string{"Regular", "Overtime"}, RowsProcessed: 10, CellsChanged: 20, Hours: []types.ColumnHours{
+			{Column: "Regular", Cells: 2, Hours: 15.5},
+			{Column: "Overtime", Cells: 1, Hours: 1.25},
+		}},
+		{InputFile: filepath.Join(dir, "b|c.csv"), OutputFile: filepath.Join(dir, "b|c_converted.csv"), ColumnsFound: []string{"Hours"}, RowsProcessed: 5, CellsChanged: 5, Warnings: []string{"2 cells weren't numbers"}},
+	}
+	report := NewReport(NewRun("1.0.0", results, nil))
+
+	want := ReportTotals{Files: 2, Rows: 15, Cells: 25, Warnings: 1, MinutesSaved: 2.5}
+	if report.Totals != want {
+		t.Errorf("Expected totals %+v, got %+v", want, report.Totals)
+	}
+	wantHours := []ColumnHours{{Column: "Regular", Cells: 2, Hours: 15.5}, {Column: "Overtime", Cells: 1, Hours: 1.25}}
+	if !reflect.DeepEqual(report.Hours, wantHours) {
+		t.Errorf("Expected hours %+v, got %+v", wantHours, report.Hours)
+	}
+
+	created := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	if got := filepath.Base(ReportPath(dir, ReportMarkdown, created)); got != "chronos-report-20240301-093000.md" {
+		t.Errorf("Expected the report named for when it was exported, got %s", got)
+	}
+
+	for _, format := range ReportFormats {
+		path := ReportPath(dir, format, created)
+		if err := WriteReport(path, report, format); err != nil {
+			t.Fatalf("WriteReport(%s) failed: %v", format, err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		switch format {
+		case ReportCSV:
+			rows, err := csv.NewReader(strings.NewReader(string(data))).ReadAll()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(rows) != 4 || rows[1][2] != "Regular; Overtime" || rows[2][6] != "2 cells weren't numbers" || rows[3][0] != "Total (2 files)" || rows[3][4] != "25" {
+				t.Errorf("Unexpected CSV report %v", rows)
+			}
+		case ReportJSON:
+			var got Report
+			if err := json.Unmarshal(data, &got); err != nil {
+				t.Fatal(err)
+			}
+			if got.Totals != want || len(got.Hours) != 2 || len(got.Files) != 2 || got.Files[1].Warnings[0] != "2 cells weren't numbers" {
+				t.Errorf("Unexpected JSON report %+v", got)
+			}
+		case ReportMarkdown:
+			for _, part := range []string{"| 2 | 15 | 25 | 0 | 1 | ~3 minutes |", "| Regular | 2 | 15:30 | 15.50 |", `b\|c.csv`, "### b|c.csv\n\n- 2 cells weren't numbers"} {
+				if !strings.Contains(string(data), part) {
+					t.Errorf("Expected the Markdown report to contain %q, got:\n%s", part, data)
+				}
+			}
+		case ReportPDF:
+			checkPDF(t, data)
+			for _, part := range []string{"(Conversion report)", "(Hours by column)", "(15:30)", "(15.50)", "(b|c.csv)", "(- 2 cells weren't numbers)", "(Page 1 of 1)"} {
+				if !strings.Contains(string(data), part) {
+					t.Errorf("Expected the PDF report to contain %q", part)
+				}
+			}
+		}
+	}
+}
+
+// checkPDF checks a PDF's structure: its header and trailer, and that the
+// cross-reference table points at each object.
+func checkPDF(t *testing.T, data []byte) {
+	t.Helper()
+	text := string(data)
+	if !strings.HasPrefix(text, "%PDF-1.4\n") || !strings.HasSuffix(text, "%%EOF\n") {
+		t.Fatalf("Expected a PDF header and trailer, got:\n%s", text)
+	}
+	start := regexp.MustCompile(`startxref\n(\d+)\n`).FindStringSubmatch(text)
+	if start == nil {
+		t.Fatal("Expected startxref")
+	}
+	xref, _ := strconv.Atoi(start[1])
+	if !strings.HasPrefix(text[xref:], "xref\n") {
+		t.Fatalf("Expected startxref to point at the xref table, got %q", text[xref:min(len(text), xref+20)])
+	}
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllStringSubmatch(text[xref:], -1)
+	for i, entry := range entries {
+		offset, _ := strconv.Atoi(entry[1])
+		if want := strconv.Itoa(i+1) + " 0 obj\n"; !strings.HasPrefix(text[offset:], want) {
+			t.Errorf("Expected object %d at offset %d, got %q", i+1, offset, text[offset:min(len(text), offset+20)])
+		}
+	}
+	for _, stream := range regexp.MustCompile(`(?s)<< /Length (\d+) >>\nstream\n(.*?)endstream`).FindAllStringSubmatch(text, -1) {
+		if n, _ := strconv.Atoi(stream[1]); n != len(stream[2]) {
+			t.Errorf("Expected a stream of %d bytes, got %d", n, len(stream[2]))
+		}
+	}
+}
+
+func TestWriteReportPDF_Pages(t *testing.T) {
+	var warnings []string
+	for i := range 120 {
+		warnings = append(warnings, fmt.Sprintf("warning %d about (parentheses) and \\ café", i))
+	}
+	report := NewReport(NewRun("1.0.0", []*types.ConversionResult{{InputFile: "a.csv", OutputFile: "a_converted.csv", Warnings: warnings}}, nil))
+
+	var out bytes.Buffer
+	if err := writeReportPDF(&out, report); err != nil {
+		t.Fatal(err)
+	}
+	checkPDF(t, out.Bytes())
+	text := out.String()
+	if !strings.Contains(text, "/Count 3") || !strings.Contains(text, "(Page 3 of 3)") {
+		t.Errorf("Expected the warnings to run onto three pages")
+	}
+	if !strings.Contains(text, `about \(parentheses\) and \\ caf\351`) {
+		t.Errorf("Expected text escaped in WinAnsi encoding")
+	}
+}
@@ -0,0 +1,42 @@
+package summary
+
+import (
+	"encoding/json"
+	"os"
+)
+
+// SidecarSuffix is added to an output's path to name its sidecar.
+const SidecarSuffix = ".chronos.json"
+
+// Sidecar describes how a single output file was generated, written next
+// to it so its provenance travels with it.
+type Sidecar struct {
+	Version   string `json:"version"`
+	CreatedAt string `json:"created_at"`
+	File
+}
+
+// SidecarPath returns where the sidecar of an output is written.
+func SidecarPath(output string) string {
+	return output + SidecarSuffix
+}
+
+// WriteSidecars writes a sidecar next to every output of run. It returns
+// the paths written.
+func WriteSidecars(run Run) ([]string, error) {
+	var written []string
+	for _, file := range run.Files {
+		sidecar := Sidecar{Version: run.Version, CreatedAt: run.CreatedAt, File: file}
+		data, err := json.MarshalIndent(sidecar, "", "  ")
+		if err != nil {
+			return written, err
+		}
+
+		path := SidecarPath(file.Output)
+		if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+			return written, err
+		}
+		written = append(written, path)
+	}
+	return written, nil
+}
@@ -0,0 +1,47 @@
+package summary
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nconklindev/chronos/internal/types"
+)
+
+func TestWriteSidecars(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "a.csv")
+	output := filepath.Join(dir, "a_converted.csv")
+	if err := os.WriteFile(input, []byte("Hours\n1.5\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(output, []byte("Hours\n01:30\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	results := []*types.ConversionResult{{InputFile: input, OutputFile: output, ColumnsFound: []string{"Hours"}, RowsProcessed: 1}}
+	options := []types.ConversionOptions{{KeepOriginal: true}}
+	written, err := WriteSidecars(NewRun("1.0.0", results, options))
+	if err != nil {
+		t.Fatalf("WriteSidecars failed: %v", err)
+	}
+	if len(written) != 1 || written[0] != output+SidecarSuffix {
+		t.Fatalf("Expected a sidecar next to the output, got %v", written)
+	}
+
+	data, err := os.ReadFile(written[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sidecar Sidecar
+	if err := json.Unmarshal(data, &sidecar); err != nil {
+		t.Fatal(err)
+	}
+	if sidecar.Version != "1.0.0" || sidecar.CreatedAt == "" || sidecar.Input != input || !sidecar.Options.KeepOriginal {
+		t.Errorf("Unexpected sidecar: %+v", sidecar)
+	}
+	if len(sidecar.InputSHA256) != 64 || len(sidecar.Columns) != 1 {
+		t.Errorf("Expected the input checksum and columns, got %+v", sidecar)
+	}
+}
@@ -0,0 +1,149 @@
+package summary
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/nconklindev/chronos/internal/types"
+)
+
+// FileName is the name of the run summary written next to converted files.
+const FileName = "chronos-run.json"
+
+// Run describes one batch of conversions.
+type Run struct {
+	Version   string `json:"version"`
+	CreatedAt string `json:"created_at"`
+	Files     []File `json:"files"`
+}
+
+// File describes the conversion of a single input file.
+type File struct {
+	Input         string                  `json:"input"`
+	InputSHA256   string                  `json:"input_sha256,omitempty"`
+	Output        string                  `json:"output"`
+	OutputSHA256  string                  `json:"output_sha256,omitempty"`
+	Columns       []string                `json:"columns"`
+	RowsProcessed int                     `json:"rows_processed"`
+	CellsChanged  int                     `json:"cells_changed"`
+	Options       types.ConversionOptions `json:"options"`
+	Warnings      []string                `json:"warnings,omitempty"`
+	// RoundingLosses lists the cells that lost more than the loss threshold to rounding.
+	RoundingLosses []RoundingLoss `json:"rounding_losses,omitempty"`
+	// Hours totals each column converted as durations.
+	Hours []ColumnHours `json:"hours,omitempty"`
+}
+
+// ColumnHours totals the hours converted in a column.
+type ColumnHours = types.ColumnHours
+
+// RoundingLoss describes a cell whose converted value is further from the
+// original than the loss threshold allows.
+type RoundingLoss struct {
+	Sheet       string  `json:"sheet,omitempty"`
+	Row         int     `json:"row"`
+	Column      string  `json:"column"`
+	Original    string  `json:"original"`
+	Converted   string  `json:"converted"`
+	LostMinutes float64 `json:"lost_minutes"`
+}
+
+// NewRun builds a summary from conversion results and the options used for each.
+// options[i] must be the options that produced results[i].
+func NewRun(version string, results []*types.ConversionResult, options []types.ConversionOptions) Run {
+	run := Run{
+		Version:   version,
+		CreatedAt: time.Now().Format(time.RFC3339),
+	}
+
+	for i, res := range results {
+		file := File{
+			Input:         res.InputFile,
+			Output:        res.OutputFile,
+			Columns:       res.ColumnsFound,
+			RowsProcessed: res.RowsProcessed,
+			CellsChanged:  res.CellsChanged,
+			Warnings:      res.Warnings,
+			Hours:         res.Hours,
+		}
+		if i < len(options) {
+			file.Options = options[i]
+		}
+		for _, change := range res.Lossy {
+			file.RoundingLosses = append(file.RoundingLosses, RoundingLoss{
+				Sheet:       change.Sheet,
+				Row:         change.Row,
+				Column:      change.Column,
+				Original:    change.Original,
+				Converted:   change.Converted,
+				LostMinutes: change.LostMinutes,
+			})
+		}
+
+		// Checksums are best effort; outputs may not be local files
+		file.InputSHA256, _ = FileSHA256(res.InputFile)
+		file.OutputSHA256, _ = FileSHA256(res.OutputFile)
+
+		run.Files = append(run.Files, file)
+	}
+
+	return run
+}
+
+// WriteAll writes a chronos-run.json into every directory that received
+// output, listing only that directory's files. It returns the paths written.
+func WriteAll(run Run) ([]string, error) {
+	var dirs []string
+	byDir := make(map[string][]File)
+
+	for _, file := range run.Files {
+		dir := filepath.Dir(file.Output)
+		if _, ok := byDir[dir]; !ok {
+			dirs = append(dirs, dir)
+		}
+		byDir[dir] = append(byDir[dir], file)
+	}
+
+	var written []string
+	for _, dir := range dirs {
+		dirRun := run
+		dirRun.Files = byDir[dir]
+
+		path := filepath.Join(dir, FileName)
+		if err := Write(path, dirRun); err != nil {
+			return written, err
+		}
+		written = append(written, path)
+	}
+
+	return written, nil
+}
+
+// Write saves a run summary as indented JSON.
+func Write(path string, run Run) error {
+	data, err := json.MarshalIndent(run, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// FileSHA256 returns the hex SHA-256 checksum of a file.
+func FileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
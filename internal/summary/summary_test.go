@@ -0,0 +1,79 @@
+package summary
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nconklindev/chronos/internal/types"
+)
+
+func TestWriteAll(t *testing.T) {
+	dirA := t.TempDir()
+	dirB := t.TempDir()
+
+	outA := filepath.Join(dirA, "a_converted.csv")
+	outB := filepath.Join(dirB, "b_converted.csv")
+	for _, path := range []string{outA, outB} {
+		if err := os.WriteFile(path, []byte("Hours\n01:30\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	results := []*types.ConversionResult{
+		{InputFile: filepath.Join(dirA, "a.csv"), OutputFile: outA, ColumnsFound: []string{"Hours"}, RowsProcessed: 1},
+		{InputFile: filepath.Join(dirB, "b.csv"), OutputFile: outB, ColumnsFound: []string{"Hours"}, RowsProcessed: 1},
+	}
+	options := []types.ConversionOptions{{KeepOriginal: true}, {}}
+
+	written, err := WriteAll(NewRun("1.0.0", results, options))
+	if err != nil {
+		t.Fatalf("WriteAll failed: %v", err)
+	}
+	if len(written) != 2 {
+		t.Fatalf("Expected a summary per output directory, got %v", written)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dirA, FileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var run Run
+	if err := json.Unmarshal(data, &run); err != nil {
+		t.Fatal(err)
+	}
+	if len(run.Files) != 1 || run.Files[0].Output != outA {
+		t.Fatalf("Expected only the files in dirA, got %+v", run.Files)
+	}
+
+	file := run.Files[0]
+	if !file.Options.KeepOriginal {
+		t.Error("Expected options to be recorded")
+	}
+	// sha256 of "Hours\n01:30\n"
+	if len(file.OutputSHA256) != 64 {
+		t.Errorf("Expected an output checksum, got %q", file.OutputSHA256)
+	}
+	if file.InputSHA256 != "" {
+		t.Errorf("Expected no checksum for a missing input, got %q", file.InputSHA256)
+	}
+}
+
+func TestNewRun_RoundingLosses(t *testing.T) {
+	results := []*types.ConversionResult{{
+		InputFile:  "a.csv",
+		OutputFile: "a_converted.csv",
+		Lossy: []types.CellChange{
+			{Row: 3, Col: 1, Column: "Hours", Original: "1.98", Converted: "01:58", LostMinutes: 0.8},
+		},
+	}}
+
+	run := NewRun("1.0.0", results, nil)
+	losses := run.Files[0].RoundingLosses
+	expected := RoundingLoss{Row: 3, Column: "Hours", Original: "1.98", Converted: "01:58", LostMinutes: 0.8}
+	if len(losses) != 1 || losses[0] != expected {
+		t.Errorf("Expected %+v, got %+v", expected, losses)
+	}
+}
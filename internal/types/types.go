@@ -1,14 +1,498 @@
 package types
 
+import "fmt"
+
 type ConversionResult struct {
-	InputFile     string
-	OutputFile    string
-	ColumnsFound  []string
-	RowsProcessed int
+	InputFile     string   `json:"input_file"`
+	OutputFile    string   `json:"output_file"`
+	ColumnsFound  []string `json:"columns"`
+	RowsProcessed int      `json:"rows_processed"`
+	Warnings      []string `json:"warnings,omitempty"` // Problems that didn't stop the conversion
+	// Changes lists every cell that was converted, in row then column
+	// order, when the options record them
+	Changes []CellChange `json:"-"`
+	// CellsChanged is how many cells were converted, whether or not
+	// Changes lists them
+	CellsChanged int `json:"-"`
+	// Hours totals the converted durations of each column, in column order
+	Hours []ColumnHours `json:"-"`
+	// Lossy lists the changes whose rounding lost more than the loss threshold
+	Lossy []CellChange `json:"-"`
+	// Flagged lists the changes of more hours than the flag threshold
+	Flagged []CellChange `json:"-"`
+	// TotalsFile is where the totals of CSV output were written, if any.
+	TotalsFile string `json:"totals_file,omitempty"`
+}
+
+// ColumnHours totals the hours converted in a column.
+type ColumnHours struct {
+	Column string  `json:"column"`
+	Cells  int     `json:"cells"`
+	Hours  float64 `json:"hours"`
+}
+
+// CellChange records one converted cell.
+type CellChange struct {
+	Sheet     string // Sheet name in XLSX files, empty for CSV
+	Row       int    // 1-indexed row in the input, counting the header
+	Col       int    // 0-indexed column in the input
+	Column    string // Header of the column
+	Original  string
+	Converted string
+	// LostMinutes is how far the converted value falls short of the
+	// original, negative when it's over, as from rounding up.
+	LostMinutes float64
+	// Hours is the converted value in hours, with the overtime of a split
+	// value. It's only set, with HasHours, for durations.
+	Hours    float64
+	HasHours bool
 }
 
 type FileData struct {
-	Headers   []string
-	Rows      [][]string
-	HeaderRow int // Which row the headers were found on in XLSX files (0-index)
+	Headers    []string
+	Rows       [][]string
+	Sheet      string // The sheet read from XLSX files
+	HeaderRow  int    // Which row the headers were found on in XLSX files (0-index)
+	FooterRows int    // How many trailing rows look like totals or blank trailers
+	Encoding   string // Detected text encoding of CSV files
+	PaddedRows int    // How many CSV rows were shorter than the header and padded
+	// RowCount is how many rows are below the header when Rows holds only
+	// some of them, as low-memory reads do. Zero when Rows holds them all.
+	RowCount int
+	// TimeColumns are the XLSX columns formatted as times. Their Rows hold
+	// the Excel time fractions rather than the times shown.
+	TimeColumns []int
+}
+
+// ConversionOptions holds the per-file settings chosen by the user.
+type ConversionOptions struct {
+	KeepOriginal bool        `json:"keep_original"`
+	FooterRows   int         `json:"footer_rows"`       // Number of trailing rows to leave untouched
+	DropFooter   bool        `json:"drop_footer"`       // Exclude footer rows from the output instead of passing them through
+	Filters      []RowFilter `json:"filters,omitempty"` // Only rows matching every filter are converted
+	// OutputEncoding is the text encoding of CSV output. Empty keeps the input's encoding.
+	OutputEncoding string `json:"output_encoding,omitempty"`
+	// LineEnding is how lines of CSV output end. Empty keeps the input's.
+	LineEnding LineEnding `json:"line_ending,omitempty"`
+	// FinalNewline is whether CSV output ends with a line ending. Empty
+	// keeps the input's convention.
+	FinalNewline FinalNewline `json:"final_newline,omitempty"`
+	// Quoting is which fields of CSV output are quoted. Empty quotes only
+	// those that must be.
+	Quoting Quoting `json:"quoting,omitempty"`
+	// HeaderSuffix is added to a column's header to name its converted copy
+	// when KeepOriginal is set. Empty uses the default " (HH:MM)".
+	HeaderSuffix string `json:"header_suffix,omitempty"`
+	// Columns holds each converted column's own settings by column index.
+	// Columns without settings use the defaults.
+	Columns map[int]ColumnSettings `json:"columns,omitempty"`
+	// Placement is where the converted copies go when KeepOriginal is set.
+	// Empty means PlacementAdjacent.
+	Placement Placement `json:"placement,omitempty"`
+	// NewSheet writes the converted data of XLSX files to a new
+	// "<Sheet> (converted)" sheet in the workbook, leaving the original
+	// sheet as it was.
+	NewSheet bool `json:"new_sheet,omitempty"`
+	// CommentOriginals adds a comment holding the original value to each
+	// cell of an XLSX file converted in place, when originals aren't kept.
+	CommentOriginals bool `json:"comment_originals,omitempty"`
+	// Table wraps the converted data of XLSX files in an Excel table of
+	// this name, which Power Query and pivot tables can refer to. Footer
+	// rows are left out of it. Empty adds no table.
+	Table string `json:"table,omitempty"`
+	// TableStyle is the table's built-in style, such as TableStyleLight9.
+	// Empty uses TableStyleMedium2.
+	TableStyle string `json:"table_style,omitempty"`
+	// LowMemory streams XLSX files rather than loading the whole workbook,
+	// for sheets too large for memory. Only the converted sheet is written,
+	// with just the number formats of cells that aren't converted, so
+	// NewSheet and CommentOriginals aren't available.
+	LowMemory bool `json:"low_memory,omitempty"`
+	// Parquet writes the converted data as a Parquet file rather than in
+	// the input's format, for data warehouses and analytics pipelines.
+	// Only the header and the rows below it are written.
+	Parquet bool `json:"parquet,omitempty"`
+	// ParquetDurations types the converted columns of Parquet output as
+	// whole seconds rather than the converted text.
+	ParquetDurations bool `json:"parquet_durations,omitempty"`
+	// CSVOutput writes JSON and NDJSON files as CSV rather than in their
+	// own format.
+	CSVOutput bool `json:"csv_output,omitempty"`
+	// Markup writes the converted data as a Markdown or HTML table rather
+	// than in the input's format. Empty writes a data file.
+	Markup Markup `json:"markup,omitempty"`
+	// LossThreshold is how many minutes rounding may lose from a cell
+	// before it's reported. Zero uses the default of half a minute.
+	LossThreshold float64 `json:"loss_threshold,omitempty"`
+	// FlagAbove flags converted cells of more than this many hours, likely
+	// data entry errors: XLSX cells are filled and CSV files get a column
+	// naming the flagged columns of each row. Zero flags nothing.
+	FlagAbove float64 `json:"flag_above,omitempty"`
+	// Blanks is what converted columns hold for empty cells and zero values.
+	Blanks BlankPolicy `json:"blanks,omitempty"`
+	// OnlySelected writes just the converted columns and KeepColumns, the
+	// slimmed layout many payroll import templates expect.
+	OnlySelected bool `json:"only_selected,omitempty"`
+	// KeepColumns are unconverted columns, such as employee IDs, still
+	// written when OnlySelected is set.
+	KeepColumns []int `json:"keep_columns,omitempty"`
+	// Order lists columns in the order they're written, to match the layout
+	// a downstream system requires. Columns left out follow in file order.
+	Order []int `json:"order,omitempty"`
+	// Durations add a column of decimal hours computed from each pair of
+	// start and end timestamp columns, right after the header's last
+	// column. They're numbered from there and converted like any other.
+	Durations []DurationPair `json:"durations,omitempty"`
+	// Totals sums the converted hours of each employee over each period,
+	// in a Totals sheet of XLSX output or a CSV file next to CSV output.
+	// Nil adds no totals.
+	Totals *Totals `json:"totals,omitempty"`
+	// RecordChanges lists every converted cell in the result, for audit
+	// logs. Without it only their count and hours are kept, since large
+	// files change millions of cells.
+	RecordChanges bool `json:"-"`
+}
+
+// Totals picks the columns converted hours are grouped by.
+type Totals struct {
+	Employee int    `json:"employee"` // Column naming who worked, such as a name or ID
+	Date     int    `json:"date"`     // Column of the day worked
+	Period   Period `json:"period,omitempty"`
+}
+
+// DurationPair is a start and an end timestamp column whose difference is
+// written as a column of hours.
+type DurationPair struct {
+	Start  int    `json:"start"`
+	End    int    `json:"end"`
+	Header string `json:"header"` // The computed column's header
+}
+
+// VerifyResult reports how a converted file compares with its source.
+type VerifyResult struct {
+	SourceFile    string   `json:"source_file"`
+	ConvertedFile string   `json:"converted_file"`
+	Columns       []string `json:"columns"`       // Source columns found converted
+	CellsChecked  int      `json:"cells_checked"` // Numbers in those columns that were compared
+	// Unconverted counts numbers left as they were, as in footer and filtered rows.
+	Unconverted int        `json:"unconverted"`
+	Mismatches  []Mismatch `json:"mismatches,omitempty"`
+	Warnings    []string   `json:"warnings,omitempty"` // Layout differences, such as a different number of rows
+}
+
+// Mismatch is a converted cell that isn't what converting its original gives.
+type Mismatch struct {
+	Row      int    `json:"row"` // 1-indexed row in the source, counting the header
+	Column   string `json:"column"`
+	Original string `json:"original"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"`
+}
+
+// ColumnSettings are how one column is converted. The zero value converts
+// decimal hours to HH:MM, rounded to the nearest minute.
+type ColumnSettings struct {
+	Format   Format   `json:"format,omitempty"`
+	Rounding Rounding `json:"rounding,omitempty"`
+	Unit     Unit     `json:"unit,omitempty"` // What the input values count
+	// Decimal is how the input values are written, telling a decimal comma
+	// from a comma grouping thousands.
+	Decimal Decimal `json:"decimal,omitempty"`
+	// Lenient strips the units and symbols some exports write with hours
+	// before reading them, so 7.5 hrs, 7.5h and 750% are all 7.5. Values
+	// with anything else stay unconverted.
+	Lenient bool `json:"lenient,omitempty"`
+	// Transformer names the registered transformer converting the column's
+	// cells. Empty converts durations with the other settings; the rest,
+	// such as cents_to_dollars, ignore them.
+	Transformer string `json:"transformer,omitempty"`
+	// Expression adjusts each value before it's converted, for payroll
+	// rules such as "if v > 12 then v - 0.5 else v" to take a break off
+	// long shifts. v is the value as read, in the column's unit.
+	Expression string `json:"expression,omitempty"`
+	// Pattern is a custom format that replaces Format, such as H'h' mm'm'
+	// for 7h 30m: h is hours, m minutes and s seconds, doubled to pad them
+	// to two digits, and text goes in single quotes. Without hours,
+	// minutes count the whole duration, and likewise seconds.
+	Pattern string `json:"pattern,omitempty"`
+	// Header renames the converted column: its copy when originals are
+	// kept, otherwise the column itself. Empty keeps the default name.
+	Header string `json:"header,omitempty"`
+	// OvertimeAfter splits the converted column into regular and overtime
+	// columns at this many hours, such as 8 a day or 40 a week. Zero
+	// doesn't split it.
+	OvertimeAfter float64 `json:"overtime_after,omitempty"`
+}
+
+// Format is the layout of converted values.
+type Format string
+
+const (
+	FormatHHMM    Format = ""         // 07:30
+	FormatHMM     Format = "h:mm"     // 7:30
+	FormatHHMMSS  Format = "hh:mm:ss" // 07:30:00
+	FormatMinutes Format = "minutes"  // 450
+)
+
+// Formats lists the formats in the order the interface cycles them.
+var Formats = []Format{FormatHHMM, FormatHMM, FormatHHMMSS, FormatMinutes}
+
+// Rounding is how a value is rounded to the smallest unit its format
+// shows, the minute or, for FormatHHMMSS, the second, or snapped to a
+// payroll increment.
+type Rounding string
+
+const (
+	RoundNearest Rounding = ""
+	RoundDown    Rounding = "down"
+	RoundUp      Rounding = "up"
+	// RoundQuarter snaps to the quarter hour by the 7-minute rule: up to 7
+	// minutes past rounds down, 8 or more rounds up.
+	RoundQuarter Rounding = "quarter"
+	// RoundTenth snaps to the tenth of an hour: up to 2 minutes past rounds
+	// down, 3 or more rounds up.
+	RoundTenth Rounding = "tenth"
+)
+
+// Roundings lists the roundings in the order the interface cycles them.
+var Roundings = []Rounding{RoundNearest, RoundDown, RoundUp, RoundQuarter, RoundTenth}
+
+// Unit is what the numbers in a converted column count.
+type Unit string
+
+const (
+	UnitHours   Unit = ""
+	UnitMinutes Unit = "minutes"
+	UnitSeconds Unit = "seconds"
+	// UnitDays and UnitWeeks are whole calendar days and weeks of 24 and
+	// 168 hours, so 1.5 days is 36:00.
+	UnitDays  Unit = "days"
+	UnitWeeks Unit = "weeks"
+	// UnitExcelTime is a fraction of a day, which is how Excel stores
+	// cells formatted as times: 0.3125 is 7:30.
+	UnitExcelTime Unit = "excel_time"
+)
+
+// Units lists the units in the order the interface cycles them.
+var Units = []Unit{UnitHours, UnitMinutes, UnitSeconds, UnitDays, UnitWeeks, UnitExcelTime}
+
+// Decimal is how numbers are written: which of a point and a comma
+// separates the decimals, with the other grouping thousands.
+type Decimal string
+
+const (
+	// DecimalAuto reads both 1,234.5 and 1.234,5. The last separator is
+	// the decimal one, unless it appears more than once, so 1,5 is 1.5. A
+	// lone comma before three digits, as in 1,234, could be either, so it
+	// isn't read as a number.
+	DecimalAuto  Decimal = ""
+	DecimalPoint Decimal = "point" // 1,234.5, so 1,5 isn't a number
+	DecimalComma Decimal = "comma" // 1.234,5, so 1,234 is 1.234
+)
+
+// Decimals lists the ways of writing numbers in the order the interface
+// cycles them.
+var Decimals = []Decimal{DecimalAuto, DecimalPoint, DecimalComma}
+
+// Placement arranges the converted copies of columns kept alongside their originals.
+type Placement string
+
+const (
+	// PlacementAdjacent inserts each converted copy right after its original.
+	PlacementAdjacent Placement = "adjacent"
+	// PlacementEnd appends the converted copies after the last column.
+	PlacementEnd Placement = "end"
+	// PlacementGrouped moves each converted column and its copy to the end,
+	// so the converted columns form one block after the untouched ones.
+	PlacementGrouped Placement = "grouped"
+)
+
+// Placements lists the placements in the order the interface cycles them.
+var Placements = []Placement{PlacementAdjacent, PlacementEnd, PlacementGrouped}
+
+// ParsePlacement checks a placement name, accepting empty as adjacent.
+func ParsePlacement(name string) (Placement, error) {
+	if name == "" {
+		return PlacementAdjacent, nil
+	}
+	for _, p := range Placements {
+		if string(p) == name {
+			return p, nil
+		}
+	}
+	return "", fmt.Errorf("unknown placement %q (choose adjacent, end or grouped)", name)
+}
+
+// Markup is a table format for pasting converted data into wikis, emails
+// and pull requests.
+type Markup string
+
+const (
+	MarkupNone     Markup = ""
+	MarkupMarkdown Markup = "markdown"
+	MarkupHTML     Markup = "html"
+)
+
+// ParseMarkup checks a markup name, accepting md for markdown.
+func ParseMarkup(name string) (Markup, error) {
+	switch Markup(name) {
+	case MarkupNone, MarkupMarkdown, MarkupHTML:
+		return Markup(name), nil
+	case "md":
+		return MarkupMarkdown, nil
+	}
+	return "", fmt.Errorf("unknown markup %q (choose markdown or html)", name)
+}
+
+// BlankPolicy is what converted columns hold for empty cells and zero
+// values, since some import systems reject blank cells and others zeros.
+type BlankPolicy string
+
+const (
+	// BlankKeep leaves empty cells empty and writes zero values, as 00:00.
+	BlankKeep BlankPolicy = ""
+	// BlankAsZero writes empty cells as zero values too.
+	BlankAsZero BlankPolicy = "zero"
+	// ZeroAsBlank leaves zero values empty too.
+	ZeroAsBlank BlankPolicy = "blank"
+)
+
+// BlankPolicies lists the blank policies in the order the interface cycles them.
+var BlankPolicies = []BlankPolicy{BlankKeep, BlankAsZero, ZeroAsBlank}
+
+// Period is how long each total runs.
+type Period string
+
+const (
+	// PeriodWeek totals weeks starting on Monday.
+	PeriodWeek  Period = ""
+	PeriodDay   Period = "day"
+	PeriodMonth Period = "month"
+)
+
+// Periods lists the periods in the order the interface cycles them.
+var Periods = []Period{PeriodWeek, PeriodDay, PeriodMonth}
+
+// ParsePeriod checks a period name, accepting week and empty as PeriodWeek.
+func ParsePeriod(name string) (Period, error) {
+	switch Period(name) {
+	case "week", PeriodWeek:
+		return PeriodWeek, nil
+	case PeriodDay, PeriodMonth:
+		return Period(name), nil
+	}
+	return "", fmt.Errorf("unknown period %q (choose week, day or month)", name)
+}
+
+// ParseBlankPolicy checks a blank policy name, accepting keep and empty
+// as BlankKeep.
+func ParseBlankPolicy(name string) (BlankPolicy, error) {
+	switch BlankPolicy(name) {
+	case "keep", BlankKeep:
+		return BlankKeep, nil
+	case BlankAsZero, ZeroAsBlank:
+		return BlankPolicy(name), nil
+	}
+	return "", fmt.Errorf("unknown blank policy %q (choose keep, zero or blank)", name)
+}
+
+// LineEnding is how lines of CSV output end, since some importers require
+// Windows line endings.
+type LineEnding string
+
+const (
+	// LineEndingInput ends lines as the input's first line ends.
+	LineEndingInput LineEnding = ""
+	LineEndingLF    LineEnding = "lf"
+	LineEndingCRLF  LineEnding = "crlf"
+)
+
+// LineEndings lists the line endings in the order the interface cycles them.
+var LineEndings = []LineEnding{LineEndingInput, LineEndingLF, LineEndingCRLF}
+
+// ParseLineEnding checks a line ending name, accepting input and empty as
+// LineEndingInput.
+func ParseLineEnding(name string) (LineEnding, error) {
+	switch LineEnding(name) {
+	case "input", LineEndingInput:
+		return LineEndingInput, nil
+	case LineEndingLF, LineEndingCRLF:
+		return LineEnding(name), nil
+	}
+	return "", fmt.Errorf("unknown line ending %q (choose input, lf or crlf)", name)
+}
+
+// FinalNewline is whether CSV output ends with a line ending, since some
+// importers read one after the last row as an empty row.
+type FinalNewline string
+
+const (
+	// FinalNewlineInput ends the output as the input ends.
+	FinalNewlineInput  FinalNewline = ""
+	FinalNewlineAlways FinalNewline = "always"
+	FinalNewlineNever  FinalNewline = "never"
+)
+
+// FinalNewlines lists the final newline choices in the order the interface
+// cycles them.
+var FinalNewlines = []FinalNewline{FinalNewlineInput, FinalNewlineAlways, FinalNewlineNever}
+
+// ParseFinalNewline checks a final newline choice, accepting input and
+// empty as FinalNewlineInput.
+func ParseFinalNewline(name string) (FinalNewline, error) {
+	switch FinalNewline(name) {
+	case "input", FinalNewlineInput:
+		return FinalNewlineInput, nil
+	case FinalNewlineAlways, FinalNewlineNever:
+		return FinalNewline(name), nil
+	}
+	return "", fmt.Errorf("unknown final newline %q (choose input, always or never)", name)
+}
+
+// Quoting is which fields of CSV output are quoted, since strict parsers
+// may expect the quotes the input had.
+type Quoting string
+
+const (
+	// QuotingMinimal quotes only fields holding commas, quotes, line
+	// breaks or leading spaces.
+	QuotingMinimal Quoting = ""
+	// QuotingAll quotes every field.
+	QuotingAll Quoting = "all"
+	// QuotingPreserve quotes the fields quoted in the input, and any
+	// others that must be.
+	QuotingPreserve Quoting = "preserve"
+)
+
+// Quotings lists the quoting choices in the order the interface cycles them.
+var Quotings = []Quoting{QuotingMinimal, QuotingAll, QuotingPreserve}
+
+// ParseQuoting checks a quoting name, accepting minimal and empty as
+// QuotingMinimal.
+func ParseQuoting(name string) (Quoting, error) {
+	switch Quoting(name) {
+	case "minimal", QuotingMinimal:
+		return QuotingMinimal, nil
+	case QuotingAll, QuotingPreserve:
+		return Quoting(name), nil
+	}
+	return "", fmt.Errorf("unknown quoting %q (choose minimal, all or preserve)", name)
+}
+
+// FilterOp is the comparison a RowFilter applies to its column.
+type FilterOp int
+
+const (
+	// FilterNonEmpty matches rows where the column has a value.
+	FilterNonEmpty FilterOp = iota
+	// FilterEquals matches rows where the column equals Value, ignoring case and surrounding spaces.
+	FilterEquals
+)
+
+// RowFilter restricts conversion to rows whose Column passes Op.
+// Rows that don't match are passed through unconverted and aren't counted.
+type RowFilter struct {
+	Column int      `json:"column"`
+	Op     FilterOp `json:"op"`
+	Value  string   `json:"value,omitempty"`
 }
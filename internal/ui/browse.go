@@ -0,0 +1,115 @@
+package ui
+
+import (
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// browseRows is how many of a file's rows the data browser shows.
+const browseRows = 100
+
+// browseWidth caps the width of a column in the data browser, so one long
+// column doesn't crowd out the rest.
+const browseWidth = 24
+
+// browseHelp replaces the column keys while the data browser is open.
+const browseHelp = "↑/↓: scroll rows • ←/→: scroll columns • V/esc: back to columns • q: quit"
+
+// openBrowser shows the first rows of the current file in a table, from
+// its first column.
+func (m *Model) openBrowser() {
+	k := m.keys.Columns
+	m.browser = table.New(
+		table.WithFocused(true),
+		table.WithKeyMap(table.KeyMap{
+			LineUp:     k.Up,
+			LineDown:   k.Down,
+			PageUp:     k.PageUp,
+			PageDown:   k.PageDown,
+			GotoTop:    k.Home,
+			GotoBottom: k.End,
+		}),
+		table.WithStyles(table.Styles{
+			Header:   lipgloss.NewStyle().Foreground(color(theme.Accent)).Bold(true).Padding(0, 1),
+			Cell:     lipgloss.NewStyle().Foreground(color(theme.Text)).Padding(0, 1),
+			Selected: lipgloss.NewStyle().Foreground(color(theme.Primary)).Bold(true),
+		}),
+	)
+	m.browsing = true
+	m.browseColumn = 0
+	m.layoutBrowser()
+}
+
+// scrollBrowser moves the data browser's first column by delta, stopping
+// once the last column is in view.
+func (m *Model) scrollBrowser(delta int) {
+	headers := m.configs[m.currentFileIndex].fileData.Headers
+	if delta > 0 && m.browseColumn+len(m.browser.Columns()) >= len(headers) {
+		return
+	}
+	m.browseColumn = min(max(m.browseColumn+delta, 0), max(len(headers)-1, 0))
+	m.layoutBrowser()
+}
+
+// layoutBrowser fills the data browser with as many columns as fit the
+// screen from browseColumn on. Columns selected for conversion are
+// marked with a check.
+func (m *Model) layoutBrowser() {
+	config := m.configs[m.currentFileIndex]
+	data := config.fileData
+	rows := data.Rows[:min(len(data.Rows), browseRows)]
+
+	var columns []table.Column
+	used := 0
+	for i := m.browseColumn; i < len(data.Headers); i++ {
+		title := data.Headers[i]
+		if config.selectedCols[i] {
+			title = "✓ " + title
+		}
+		width := lipgloss.Width(title)
+		for _, row := range rows {
+			if i < len(row) {
+				width = max(width, lipgloss.Width(row[i]))
+			}
+		}
+		width = min(max(width, 1), browseWidth)
+
+		// Cells are padded a space each side
+		if used+width+2 > m.viewport.Width {
+			if len(columns) > 0 {
+				break
+			}
+			width = max(m.viewport.Width-2, 1)
+		}
+		columns = append(columns, table.Column{Title: title, Width: width})
+		used += width + 2
+	}
+
+	// Rows are cut to the columns shown, as the table renders every cell
+	// of a row
+	cells := make([]table.Row, len(rows))
+	for r, row := range rows {
+		cells[r] = make(table.Row, len(columns))
+		for c := range columns {
+			if i := m.browseColumn + c; i < len(row) {
+				cells[r][c] = row[i]
+			}
+		}
+	}
+
+	cursor := m.browser.Cursor()
+	m.browser.SetRows(nil)
+	m.browser.SetColumns(columns)
+	m.browser.SetRows(cells)
+	m.browser.SetWidth(m.viewport.Width)
+	m.browser.SetHeight(m.viewport.Height)
+	m.browser.SetCursor(max(cursor, 0))
+}
+
+// browseInfo describes which rows and columns the data browser shows.
+func (m Model) browseInfo() string {
+	data := m.configs[m.currentFileIndex].fileData
+	first := min(m.browseColumn+1, len(data.Headers))
+	last := m.browseColumn + len(m.browser.Columns())
+	return tr("Browsing the first %d of %d rows • columns %d-%d of %d", min(len(data.Rows), browseRows), len(data.Rows), first, last, len(data.Headers))
+}
@@ -0,0 +1,292 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/nconklindev/chronos/internal/converter"
+	"github.com/nconklindev/chronos/internal/types"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// columnDetailHelp replaces the column keys while a column's settings are open.
+const columnDetailHelp = "↑/↓: choose setting • ←/→: change • a: apply to all selected • enter: rename, or type a pattern or expression • esc: back to columns • q: quit"
+
+// settingPrompt describes a setting typed into the setting prompt rather
+// than cycled.
+type settingPrompt struct {
+	prompt, placeholder, help string
+	check                     func(string) error
+	get                       func(s types.ColumnSettings) string
+	set                       func(s *types.ColumnSettings, value string)
+}
+
+// settingPrompts are the typed settings by detail field.
+var settingPrompts = map[int]settingPrompt{
+	detailFormat: {
+		prompt:      "Format pattern: ",
+		placeholder: "H'h' mm'm'",
+		help:        "h: hours • m: minutes • s: seconds • doubled: two digits • 'text' • enter: apply (empty clears) • esc: cancel",
+		check:       converter.CheckPattern,
+		get:         func(s types.ColumnSettings) string { return s.Pattern },
+		set:         func(s *types.ColumnSettings, value string) { s.Pattern = value },
+	},
+	detailExpression: {
+		prompt:      "Adjust with: ",
+		placeholder: "if v > 12 then v - 0.5 else v",
+		help:        "v: the value • if … then … else … • and, or, not • min max round floor ceil abs • enter: apply (empty clears) • esc: cancel",
+		check:       converter.CheckExpression,
+		get:         func(s types.ColumnSettings) string { return s.Expression },
+		set:         func(s *types.ColumnSettings, value string) { s.Expression = value },
+	},
+}
+
+// The settings listed in the column detail view, in order.
+const (
+	detailFormat = iota
+	detailRounding
+	detailUnit
+	detailDecimal
+	detailLenient
+	detailExpression
+	detailOvertime
+	detailTransformer
+	detailName
+	detailFields
+)
+
+// previewSamples is how many of a column's values the detail view converts as a preview.
+const previewSamples = 3
+
+// startRename opens the prompt naming the converted column under the cursor.
+func (m Model) startRename() (Model, tea.Cmd) {
+	config := m.configs[m.currentFileIndex]
+	colIdx, ok := config.cursorColumn()
+	if !ok {
+		return m, nil
+	}
+	m.headerInput.SetValue(converter.ConvertedHeader(config.fileData.Headers[colIdx], colIdx, config.options))
+	m.headerInput.CursorEnd()
+	m.editingHeader = true
+	return m, m.headerInput.Focus()
+}
+
+// startSettingPrompt opens the prompt for the typed setting under the
+// cursor, such as the custom format pattern, of the column under the cursor.
+func (m Model) startSettingPrompt() (Model, tea.Cmd) {
+	config := m.configs[m.currentFileIndex]
+	colIdx, ok := config.cursorColumn()
+	if !ok {
+		return m, nil
+	}
+	p := settingPrompts[m.detailField]
+	m.settingBefore = config.options.Columns[colIdx]
+	m.settingErr = nil
+	m.settingInput.Prompt = p.prompt
+	m.settingInput.Placeholder = p.placeholder
+	m.settingInput.SetValue(p.get(m.settingBefore))
+	m.settingInput.CursorEnd()
+	m.editingSetting = true
+	return m, m.settingInput.Focus()
+}
+
+// updateSettingPrompt handles keys while the setting prompt is open. Valid
+// values are applied as they're typed so the preview follows them.
+func (m Model) updateSettingPrompt(msg tea.KeyMsg) (Model, tea.Cmd) {
+	p := settingPrompts[m.detailField]
+	config := &m.configs[m.currentFileIndex]
+	colIdx, ok := config.cursorColumn()
+	if !ok {
+		m.editingSetting = false
+		m.settingInput.Blur()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	switch msg.String() {
+	case "enter":
+		if m.settingErr != nil {
+			return m, nil
+		}
+		m.editingSetting = false
+		m.settingInput.Blur()
+	case "esc":
+		before := m.settingBefore
+		updateColumn(&config.options, colIdx, func(s *types.ColumnSettings) {
+			p.set(s, p.get(before))
+		})
+		m.editingSetting = false
+		m.settingInput.Blur()
+	default:
+		m.settingInput, cmd = m.settingInput.Update(msg)
+		value := strings.TrimSpace(m.settingInput.Value())
+		m.settingErr = nil
+		if value != "" {
+			m.settingErr = p.check(value)
+		}
+		if m.settingErr == nil {
+			updateColumn(&config.options, colIdx, func(s *types.ColumnSettings) {
+				p.set(s, value)
+			})
+		}
+	}
+	m.updateViewportContent()
+	return m, cmd
+}
+
+// updateColumnDetail handles keys while the settings of the column under
+// the cursor are open.
+func (m Model) updateColumnDetail(msg tea.KeyMsg) (Model, tea.Cmd) {
+	config := &m.configs[m.currentFileIndex]
+	colIdx, ok := config.cursorColumn()
+	k := m.keys.Columns
+
+	delta := 0
+	switch {
+	case key.Matches(msg, k.Quit):
+		return m, tea.Quit
+	case msg.String() == "esc", msg.String() == "tab", !ok:
+		m.columnDetail = false
+		m.updateViewportContent()
+		return m, nil
+	case key.Matches(msg, k.Up):
+		m.detailField = max(m.detailField-1, 0)
+	case key.Matches(msg, k.Down):
+		m.detailField = min(m.detailField+1, detailFields-1)
+	case msg.String() == "left", msg.String() == "h":
+		delta = -1
+	case msg.String() == "right", msg.String() == "l", msg.String() == " ":
+		delta = 1
+	case msg.String() == "enter" && (m.detailField == detailFormat || m.detailField == detailExpression):
+		return m.startSettingPrompt()
+	case msg.String() == "enter", key.Matches(msg, k.Rename):
+		m.detailField = detailName
+		return m.startRename()
+	case msg.String() == "a":
+		// Give every selected column this column's setting, such as one
+		// rounding for the whole file
+		current := config.options.Columns[colIdx]
+		for idx, on := range config.selectedCols {
+			if !on || idx == colIdx {
+				continue
+			}
+			updateColumn(&config.options, idx, func(s *types.ColumnSettings) {
+				switch m.detailField {
+				case detailFormat:
+					s.Format = current.Format
+					s.Pattern = current.Pattern
+				case detailRounding:
+					s.Rounding = current.Rounding
+				case detailUnit:
+					s.Unit = current.Unit
+				case detailDecimal:
+					s.Decimal = current.Decimal
+				case detailLenient:
+					s.Lenient = current.Lenient
+				case detailExpression:
+					s.Expression = current.Expression
+				case detailOvertime:
+					s.OvertimeAfter = current.OvertimeAfter
+				case detailTransformer:
+					s.Transformer = current.Transformer
+				}
+			})
+		}
+	}
+
+	if delta != 0 {
+		updateColumn(&config.options, colIdx, func(s *types.ColumnSettings) {
+			switch m.detailField {
+			case detailFormat:
+				// A pattern replaces the format, so cycling starts over from it
+				if s.Pattern != "" {
+					s.Pattern = ""
+				} else {
+					s.Format = cycle(types.Formats, s.Format, delta)
+				}
+			case detailRounding:
+				s.Rounding = cycle(types.Roundings, s.Rounding, delta)
+			case detailUnit:
+				s.Unit = cycle(types.Units, s.Unit, delta)
+			case detailDecimal:
+				s.Decimal = cycle(types.Decimals, s.Decimal, delta)
+			case detailLenient:
+				s.Lenient = !s.Lenient
+			case detailExpression:
+				// Expressions are typed, so changing one only clears it
+				s.Expression = ""
+			case detailOvertime:
+				s.OvertimeAfter = cycle(overtimeThresholds, s.OvertimeAfter, delta)
+			case detailTransformer:
+				s.Transformer = cycleTransformer(s.Transformer, delta)
+			}
+		})
+	}
+	m.updateViewportContent()
+	return m, nil
+}
+
+// columnDetail lists the settings of one column, with the one under the
+// cursor highlighted, and previews them on the column's values.
+func columnDetail(config fileConfig, colIdx, field int) string {
+	var s strings.Builder
+	header := config.fileData.Headers[colIdx]
+	settings := config.options.Columns[colIdx]
+
+	s.WriteString(TitleStyle.UnsetMarginTop().Render(tr("Settings for %q", header)))
+	s.WriteString("\n")
+	if !config.selectedCols[colIdx] {
+		s.WriteString(WarningStyle.Render(tr("Not selected, so these apply once it is")))
+		s.WriteString("\n")
+	}
+	s.WriteString("\n")
+
+	labels := alignLabels(tr("Format:"), tr("Rounding:"), tr("Unit:"), tr("Decimal:"), tr("Units:"),
+		tr("Adjust:"), tr("Overtime:"), tr("Transform:"), tr("Name:"))
+	lines := []string{
+		labels[0] + patternOrFormatLabel(settings),
+		labels[1] + roundingLabel(settings),
+		labels[2] + unitLabel(settings.Unit),
+		labels[3] + decimalLabel(settings.Decimal),
+		labels[4] + lenientLabel(settings.Lenient),
+		labels[5] + expressionLabel(settings.Expression),
+		labels[6] + overtimeLabel(settings.OvertimeAfter),
+		labels[7] + transformerLabel(settings.Transformer),
+		labels[8] + converter.ConvertedHeader(header, colIdx, config.options),
+	}
+	for i, line := range lines {
+		if i == field {
+			s.WriteString(SelectedStyle.Render("> " + line))
+		} else {
+			s.WriteString(UnselectedStyle.Render("  " + line))
+		}
+		s.WriteString("\n")
+	}
+
+	var previews []string
+	for _, row := range config.fileData.Rows {
+		if len(previews) == previewSamples {
+			break
+		}
+		if colIdx >= len(row) {
+			continue
+		}
+		value := strings.TrimSpace(row[colIdx])
+		converted, ok := converter.ConvertCell(value, settings)
+		if !ok || value == "" {
+			continue
+		}
+		preview := value + " → " + converted
+		if decimal, err := converter.CellValue(value, settings); err == nil && settings.OvertimeAfter > 0 && converter.IsDuration(settings) {
+			_, overtime := converter.SplitValue(decimal, settings)
+			preview += " + " + converter.ConvertValue(overtime, settings) + " OT"
+		}
+		previews = append(previews, preview)
+	}
+	if len(previews) > 0 {
+		s.WriteString("\n")
+		s.WriteString(SubtitleStyle.Render(tr("Preview: %s", strings.Join(previews, " • "))))
+	}
+	return s.String()
+}
@@ -0,0 +1,279 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nconklindev/chronos/internal/converter"
+	"github.com/nconklindev/chronos/internal/types"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// orderedIndices returns the selectable columns in the order they're written.
+func (c fileConfig) orderedIndices() []int {
+	if len(c.options.Order) == 0 {
+		return c.selectableIndices
+	}
+
+	selectable := make(map[int]bool, len(c.selectableIndices))
+	for _, idx := range c.selectableIndices {
+		selectable[idx] = true
+	}
+	var ordered []int
+	for _, idx := range converter.OutputOrder(len(c.fileData.Headers), c.options.Order) {
+		if selectable[idx] {
+			ordered = append(ordered, idx)
+		}
+	}
+	return ordered
+}
+
+// visibleIndices returns the selectable columns whose header matches the
+// column filter, in output order. Without a filter every selectable column
+// is visible.
+func (c fileConfig) visibleIndices() []int {
+	query := strings.ToLower(strings.TrimSpace(c.query))
+	if query == "" {
+		return c.orderedIndices()
+	}
+
+	var visible []int
+	for _, idx := range c.orderedIndices() {
+		if strings.Contains(strings.ToLower(c.fileData.Headers[idx]), query) {
+			visible = append(visible, idx)
+		}
+	}
+	return visible
+}
+
+// moveColumn swaps the column under the cursor with the one delta places
+// away in the output, and keeps the cursor on it.
+func (m *Model) moveColumn(delta int) {
+	config := &m.configs[m.currentFileIndex]
+	visible := config.visibleIndices()
+	target := config.cursor + delta
+	if config.cursor < 0 || config.cursor >= len(visible) || target < 0 || target >= len(visible) {
+		return
+	}
+
+	// Swap within the full order, which includes columns without headers
+	order := converter.OutputOrder(len(config.fileData.Headers), config.options.Order)
+	from, to := indexOf(order, visible[config.cursor]), indexOf(order, visible[target])
+	order[from], order[to] = order[to], order[from]
+	config.options.Order = order
+	if sort.IntsAreSorted(order) {
+		config.options.Order = nil
+	}
+
+	m.moveColumnCursor(delta)
+}
+
+// indexOf returns where v is in s, or -1.
+func indexOf(s []int, v int) int {
+	for i, x := range s {
+		if x == v {
+			return i
+		}
+	}
+	return -1
+}
+
+// setRounding rounds every column the same way, as the remembered
+// rounding does for newly loaded files.
+func (c *fileConfig) setRounding(r types.Rounding) {
+	if c.options.Columns == nil {
+		c.options.Columns = make(map[int]types.ColumnSettings)
+	}
+	for _, idx := range c.selectableIndices {
+		s := c.options.Columns[idx]
+		s.Rounding = r
+		c.options.Columns[idx] = s
+	}
+}
+
+// setDurations adds a computed column of hours for each pair of start and
+// end columns, selected in place of the timestamps, or with no pairs
+// removes them. Everything about the columns read is kept.
+func (c *fileConfig) setDurations(pairs []types.DurationPair) {
+	width := len(c.readData.Headers)
+	read := func(idx int) bool { return idx < width }
+
+	for idx := range c.selectedCols {
+		if !read(idx) {
+			delete(c.selectedCols, idx)
+		}
+	}
+	columns := make(map[int]types.ColumnSettings, len(c.options.Columns))
+	for idx, s := range c.options.Columns {
+		if read(idx) {
+			columns[idx] = s
+		}
+	}
+	c.options.Columns = columns
+	if len(columns) == 0 {
+		c.options.Columns = nil
+	}
+	c.options.Order = keepIndices(c.options.Order, read)
+	if sort.IntsAreSorted(c.options.Order) {
+		c.options.Order = nil
+	}
+	c.options.KeepColumns = keepIndices(c.options.KeepColumns, read)
+	var filters []types.RowFilter
+	for _, filter := range c.options.Filters {
+		if read(filter.Column) {
+			filters = append(filters, filter)
+		}
+	}
+	c.options.Filters = filters
+
+	c.options.Durations = pairs
+	c.fileData = converter.WithDurations(c.readData, pairs)
+	c.stats = converter.ComputeStats(c.fileData)
+	c.selectableIndices = selectableColumns(c.fileData.Headers)
+	c.detectedCols = converter.WithoutTimestamps(converter.AutoDetectColumns(c.fileData), pairs)
+	for i, pair := range pairs {
+		delete(c.selectedCols, pair.Start)
+		delete(c.selectedCols, pair.End)
+		c.selectedCols[width+i] = true
+	}
+}
+
+// keepIndices returns the indices keep is true for, or nil when there are none.
+func keepIndices(indices []int, keep func(idx int) bool) []int {
+	var kept []int
+	for _, idx := range indices {
+		if keep(idx) {
+			kept = append(kept, idx)
+		}
+	}
+	return kept
+}
+
+// suspectWarning heads the list of suspicious columns shown before converting.
+const suspectWarning = "These columns weren't detected as hours and may be mangled by converting:"
+
+// suspectColumns describes the selected columns that auto-detection didn't
+// pick and whose values look like IDs, money or dates, in output order.
+func (c fileConfig) suspectColumns() []string {
+	detected := make(map[int]bool, len(c.detectedCols))
+	for _, idx := range c.detectedCols {
+		detected[idx] = true
+	}
+
+	var suspects []string
+	for _, idx := range c.orderedIndices() {
+		if !c.selectedCols[idx] || detected[idx] {
+			continue
+		}
+		if reason := converter.SuspectColumn(c.fileData, idx); reason != "" {
+			suspects = append(suspects, fmt.Sprintf("%s: %s", c.fileData.Headers[idx], reason))
+		}
+	}
+	return suspects
+}
+
+// cursorColumn returns the column under the cursor, or false when the
+// filter hides every column.
+func (c fileConfig) cursorColumn() (int, bool) {
+	visible := c.visibleIndices()
+	if c.cursor < 0 || c.cursor >= len(visible) {
+		return 0, false
+	}
+	return visible[c.cursor], true
+}
+
+// selectVisible applies set to the selection state of every visible
+// column, so with a filter on only the matching columns change.
+func (c *fileConfig) selectVisible(set func(selected bool) bool) {
+	for _, idx := range c.visibleIndices() {
+		if set(c.selectedCols[idx]) {
+			c.selectedCols[idx] = true
+		} else {
+			delete(c.selectedCols, idx)
+		}
+	}
+}
+
+// wheelStep is how many columns one scroll wheel notch moves the cursor.
+const wheelStep = 3
+
+// moveColumnCursor moves the cursor by delta visible columns, clamped to
+// the list, and scrolls the viewport to keep it in view.
+func (m *Model) moveColumnCursor(delta int) {
+	config := &m.configs[m.currentFileIndex]
+	last := len(config.visibleIndices()) - 1
+	cursor := min(max(config.cursor+delta, 0), max(last, 0))
+	if cursor == config.cursor {
+		return
+	}
+	config.cursor = cursor
+
+	if cursor < m.viewport.YOffset {
+		m.viewport.SetYOffset(cursor)
+	} else if cursor >= m.viewport.YOffset+m.viewport.Height {
+		m.viewport.SetYOffset(cursor - m.viewport.Height + 1)
+	}
+	m.updateViewportContent()
+}
+
+// setColumnQuery filters the column list, keeping the cursor on the same
+// column when it's still visible. Selections are kept either way.
+func (m *Model) setColumnQuery(query string) {
+	config := &m.configs[m.currentFileIndex]
+	current, hadCursor := config.cursorColumn()
+
+	config.query = query
+	config.cursor = 0
+	if hadCursor {
+		for i, idx := range config.visibleIndices() {
+			if idx == current {
+				config.cursor = i
+				break
+			}
+		}
+	}
+
+	m.viewport.SetYOffset(max(config.cursor-m.viewport.Height+1, 0))
+	m.updateViewportContent()
+}
+
+// updateColumnQuery handles keys while the column filter prompt is open.
+// The list narrows as the query is typed.
+func (m Model) updateColumnQuery(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		m.editingColumnQuery = false
+		m.columnInput.Blur()
+		return m, nil
+	case "esc":
+		m.editingColumnQuery = false
+		m.columnInput.Blur()
+		m.setColumnQuery("")
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.columnInput, cmd = m.columnInput.Update(msg)
+	m.setColumnQuery(m.columnInput.Value())
+	return m, cmd
+}
+
+// statsWidth is the width of the column statistics beside the column list.
+const statsWidth = 36
+
+// minListWidth is the narrowest the column list gets to fit the
+// statistics beside it.
+const minListWidth = 40
+
+// statsListWidth returns how wide the column list is with the statistics
+// beside it, or 0 when they don't fit.
+func (m Model) statsListWidth() int {
+	// A bar and a space each side separate them
+	width := m.viewport.Width - statsWidth - 3
+	if width < minListWidth {
+		return 0
+	}
+	return width
+}
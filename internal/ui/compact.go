@@ -0,0 +1,234 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Below these sizes the regular views overlap and clip, so a compact
+// single-column layout without borders is used instead.
+const (
+	MinWidth  = 60
+	MinHeight = 20
+)
+
+// Below these sizes not even the compact layout fits, so the terminal is
+// asked to grow instead.
+const (
+	TinyWidth  = 30
+	TinyHeight = 8
+)
+
+// NarrowWidth is the width below which the regular views truncate long
+// lines and wrap boxes to the terminal rather than letting them overflow.
+const NarrowWidth = 80
+
+// compactChrome is the number of lines the compact views use around the
+// file picker or column list: a title line and a help line.
+const compactChrome = 2
+
+// compact reports whether the terminal is too small for the regular views.
+// It's checked on every render, so resizing switches layouts automatically.
+func (m Model) compact() bool {
+	// Before the first WindowSizeMsg the size is unknown
+	if m.width == 0 || m.height == 0 {
+		return false
+	}
+	return m.width < MinWidth || m.height < MinHeight
+}
+
+// tooSmall reports whether the terminal can't fit any layout.
+func (m Model) tooSmall() bool {
+	if m.width == 0 || m.height == 0 {
+		return false
+	}
+	return m.width < TinyWidth || m.height < TinyHeight
+}
+
+// narrow reports whether the regular views need to fit lines to the width.
+func (m Model) narrow() bool {
+	return m.width > 0 && m.width < NarrowWidth
+}
+
+// fit truncates s to the terminal width once the width is known.
+func (m Model) fit(s string) string {
+	if m.width == 0 {
+		return s
+	}
+	return truncate(s, m.width)
+}
+
+// box frames a view, wrapping its content to the terminal on narrow terminals.
+func (m Model) box(content string) string {
+	if m.narrow() {
+		// Width excludes the border
+		return BoxStyle.Width(m.width - 2).Render(content)
+	}
+	return BoxStyle.Render(content)
+}
+
+// viewTooSmall asks for a bigger terminal.
+func (m Model) viewTooSmall() string {
+	return lipgloss.NewStyle().Width(m.width).Render(tr(
+		"Terminal too small (%d×%d). Resize to at least %d×%d, or press q to quit.",
+		m.width, m.height, TinyWidth, TinyHeight))
+}
+
+// viewCompact renders the current state in the compact layout.
+func (m Model) viewCompact() string {
+	var title, body, help string
+	titleStyle := TitleStyle.UnsetMarginTop()
+
+	switch m.state {
+	case stateFilePicker:
+		title = tr("⏰ Chronos (%d/3)", len(m.selectedFiles))
+		body = m.filepicker.View()
+		help = m.compactHelp()
+		switch {
+		case m.editingURL:
+			body = m.urlInput.View()
+			help = tr("⏎: download • esc: cancel")
+		case m.editingPath:
+			body = m.pathInput.View()
+			help = tr("⏎: select • esc: cancel")
+		case m.selectionFocused:
+			body = strings.Join(m.selectedFileLines(), "\n")
+			help = tr("x: remove • tab: back • ⏎: go")
+		case m.searching:
+			body = m.viewSearch(m.height - compactChrome)
+			help = tr("⏎: pick • ^r: subfolders • esc: cancel")
+		case m.session != nil:
+			body = tr(sessionPrompt) + "\n" + strings.Join(m.sessionLines(), "\n")
+			help = tr("⏎: resume • esc: discard")
+		}
+	case stateSetup:
+		title = tr("⏰ Setup (%d/%d)", m.setup.step+1, setupSteps)
+		body = strings.TrimSuffix(m.setupBody(), "\n")
+		help = m.setupHelp()
+	case stateLoading:
+		title = tr("⏰ Loading...")
+	case stateHistory:
+		title = tr("⏰ Recent Conversions")
+		start, end := listWindow(m.historyCursor, len(m.history), m.height-compactChrome)
+		body = strings.Join(m.historyLines()[start:end], "\n")
+		help = m.compactHelp()
+	case stateColumnSelection:
+		config := m.configs[m.currentFileIndex]
+		selected := 0
+		for _, on := range config.selectedCols {
+			if on {
+				selected++
+			}
+		}
+		title = tr("%d/%d %s • %d selected", m.currentFileIndex+1, len(m.selectedFiles), filepath.Base(config.path), selected)
+		body = m.viewport.View()
+		help = m.compactHelp()
+		switch {
+		case m.editingFilter:
+			body = m.filterInput.View()
+			help = tr("⏎: apply • esc: cancel")
+		case m.editingHeader:
+			body = m.headerInput.View()
+			help = tr("⏎: apply • esc: cancel")
+		case m.editingSetting:
+			body = m.settingInput.View() + "\n" + m.viewport.View()
+			help = tr("⏎: apply • esc: cancel")
+		case m.editingProfile:
+			body = m.profileInput.View()
+			help = tr("⏎: save • esc: cancel")
+		case m.editingColumnQuery:
+			body = m.columnInput.View() + "\n" + m.viewport.View()
+			help = tr("⏎: keep • esc: clear")
+		case len(m.changed) > 0:
+			body = tr(changedHeading) + "\n" + strings.Join(m.changedNames(), "\n")
+			help = tr("%s: reload • %s: convert anyway • esc: back", helpKeys(m.keys.Prompts.Reload), helpKeys(m.keys.Columns.Confirm))
+		case len(m.problems) > 0:
+			body = tr(preflightHeading) + "\n" + strings.Join(m.problems, "\n")
+			help = tr("⏎: check again • esc: back")
+		case len(m.conflicts) > 0:
+			body = m.viewOverwrite()
+			p := m.keys.Prompts
+			help = tr("%s: overwrite/rename/skip • %s: all", helpKeys(p.Overwrite, p.Rename, p.Skip), helpKeys(p.OverwriteAll, p.RenameAll, p.SkipAll))
+		case m.recognized != nil:
+			body = presetPrompt(m.recognized)
+			help = tr("⏎: apply preset • esc: choose columns")
+		case m.quickConfirm:
+			body = m.quickPrompt(m.configs[m.currentFileIndex])
+			help = tr("%s: convert • esc: choose columns", helpKeys(m.keys.Columns.Confirm))
+		case len(m.suspects) > 0:
+			body = tr(suspectWarning) + "\n" + strings.Join(m.suspects, "\n")
+			help = tr("⏎: convert anyway • esc: back")
+		case m.explaining:
+			help = tr("↑/↓: scroll • x: back • q: quit")
+		case m.browsing:
+			body = m.browser.View()
+			help = tr("↑/↓: rows • ←/→: columns • V: back")
+		case m.reordering:
+			help = tr("K/J: move • r: done")
+		case m.columnDetail:
+			help = tr("↑/↓: choose • ←/→: change • a: all • esc: back")
+		}
+	case stateProcessing:
+		title = fmt.Sprintf("⏰ %d/%d %s", m.currentFileIndex+1, len(m.selectedFiles), filepath.Base(m.configs[m.currentFileIndex].path))
+		if len(m.selectedFiles) > 1 {
+			title += tr(" (batch %d%%)", int(m.batchProgress()*100))
+		}
+		body = m.progress.View()
+	case stateComplete:
+		rows := 0
+		var outputs []string
+		for _, res := range m.results {
+			rows += res.RowsProcessed
+			outputs = append(outputs, "→ "+filepath.Base(res.OutputFile))
+		}
+		title = tr("✓ %d file(s), %d rows", len(m.results), rows)
+		body = strings.Join(outputs, "\n")
+		if m.status != "" {
+			body += "\n" + m.status
+		}
+		help = m.compactHelp()
+		if m.exportingReport {
+			help = tr(reportHelp)
+		}
+	case stateError:
+		title = tr("✗ Error")
+		titleStyle = ErrorStyle
+		body = m.err.Error()
+		if m.status != "" {
+			body += "\n" + m.status
+		}
+		help = m.compactHelp()
+	}
+
+	var s strings.Builder
+	s.WriteString(titleStyle.Render(truncate(title, m.width)))
+	if body != "" {
+		s.WriteString("\n")
+		s.WriteString(lipgloss.NewStyle().Width(m.width).MaxHeight(m.height - compactChrome).Render(body))
+	}
+	if help != "" {
+		s.WriteString("\n")
+		s.WriteString(HelpStyle.UnsetMarginTop().Render(truncate(help, m.width)))
+	}
+	return s.String()
+}
+
+// compactHelp renders as many of the current screen's keys as fit on one line.
+func (m Model) compactHelp() string {
+	return m.shortHelpView(m.screenKeys().ShortHelp(), m.width)
+}
+
+// truncate shortens s to fit width cells, marking the cut with an ellipsis
+func truncate(s string, width int) string {
+	if width <= 1 || lipgloss.Width(s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && lipgloss.Width(string(runes))+1 > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…"
+}
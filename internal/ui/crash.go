@@ -0,0 +1,65 @@
+package ui
+
+import (
+	"fmt"
+	"runtime/debug"
+	"strings"
+
+	"github.com/nconklindev/chronos/internal/crash"
+)
+
+// crashInfo identifies the build in crash logs.
+func (m Model) crashInfo() crash.Info {
+	return crash.Info{Version: m.opts.Version, Commit: m.opts.Commit, Date: m.opts.Date}
+}
+
+// CrashState describes what the interface was doing, for a crash log. It
+// names the files and the columns chosen but leaves out their values.
+func (m Model) CrashState() string {
+	var s strings.Builder
+	fmt.Fprintf(&s, "screen: %s\n", m.state)
+	fmt.Fprintf(&s, "terminal: %dx%d\n", m.width, m.height)
+	fmt.Fprintf(&s, "files: %d selected, on file %d\n", len(m.selectedFiles), m.currentFileIndex+1)
+	for _, file := range m.selectedFiles {
+		fmt.Fprintf(&s, "  %s\n", file)
+	}
+	if m.currentFileIndex < len(m.configs) {
+		c := m.configs[m.currentFileIndex]
+		if c.fileData != nil {
+			fmt.Fprintf(&s, "columns: %d, %d rows\n", len(c.fileData.Headers), len(c.fileData.Rows))
+			var selected []string
+			for i, header := range c.fileData.Headers {
+				if c.selectedCols[i] {
+					selected = append(selected, header)
+				}
+			}
+			fmt.Fprintf(&s, "selected: %s\n", strings.Join(selected, ", "))
+		}
+		fmt.Fprintf(&s, "cursor: %d, filter: %q\n", c.cursor, c.query)
+		fmt.Fprintf(&s, "options: %+v\n", c.options)
+	}
+	if m.status != "" {
+		fmt.Fprintf(&s, "status: %s\n", m.status)
+	}
+	return s.String()
+}
+
+// recoverCrash is deferred in Update and View. It writes a crash log with
+// the model's state, then panics again for Bubble Tea to restore the
+// terminal and end the program.
+func (m Model) recoverCrash() {
+	if r := recover(); r != nil {
+		crash.Write(m.crashInfo(), r, debug.Stack(), m.CrashState())
+		panic(r)
+	}
+}
+
+// crashError turns a panic converting a file into the file's error, so one
+// file can't take the batch and the terminal down with it.
+func (m Model) crashError(r any) error {
+	path, err := crash.Write(m.crashInfo(), r, debug.Stack(), m.CrashState())
+	if err != nil {
+		return fmt.Errorf("chronos crashed converting this file: %v", r)
+	}
+	return fmt.Errorf("chronos crashed converting this file: %v; a crash log was saved to %s", r, path)
+}
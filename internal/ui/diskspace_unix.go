@@ -0,0 +1,26 @@
+//go:build !windows
+// +build !windows
+
+package ui
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// maxPathLength is the longest output path other programs can open, or 0
+// when paths are practically unlimited.
+const maxPathLength = 0
+
+// freeSpace returns the bytes free to the user on the volume holding dir.
+func freeSpace(dir string) (uint64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(dir, &stat); err != nil {
+		return 0, fmt.Errorf("free space of %s: %w", dir, err)
+	}
+	// The block size is signed on some systems
+	if stat.Bsize <= 0 {
+		return 0, fmt.Errorf("free space of %s: block size %d", dir, stat.Bsize)
+	}
+	return uint64(stat.Bavail) * uint64(stat.Bsize), nil
+}
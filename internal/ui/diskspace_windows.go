@@ -0,0 +1,35 @@
+//go:build windows
+// +build windows
+
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+// maxPathLength is the longest output path other programs can open, or 0
+// when paths are practically unlimited. Excel and Explorer still stop at
+// MAX_PATH, 260 characters with the terminating null.
+const maxPathLength = 259
+
+var getDiskFreeSpaceEx = syscall.NewLazyDLL("kernel32.dll").NewProc("GetDiskFreeSpaceExW")
+
+// freeSpace returns the bytes free to the user on the volume holding dir.
+func freeSpace(dir string) (uint64, error) {
+	// Network shares need the trailing backslash
+	if !strings.HasSuffix(dir, `\`) {
+		dir += `\`
+	}
+	pointer, err := syscall.UTF16PtrFromString(dir)
+	if err != nil {
+		return 0, fmt.Errorf("free space of %s: %w", dir, err)
+	}
+	var free uint64
+	if ok, _, err := getDiskFreeSpaceEx.Call(uintptr(unsafe.Pointer(pointer)), uintptr(unsafe.Pointer(&free)), 0, 0); ok == 0 {
+		return 0, fmt.Errorf("free space of %s: %w", dir, err)
+	}
+	return free, nil
+}
@@ -0,0 +1,115 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/nconklindev/chronos/internal/config"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// openHistory shows the recent conversions, or explains why it can't.
+func (m Model) openHistory() Model {
+	history, err := config.LoadHistory()
+	switch {
+	case err != nil:
+		m.status = tr("Could not load history: %v", err)
+	case len(history) == 0:
+		m.status = tr("No conversions in the history yet")
+	default:
+		m.status = ""
+		m.history = history
+		m.historyCursor = 0
+		m.state = stateHistory
+	}
+	return m
+}
+
+// updateHistory handles keys on the history screen.
+func (m Model) updateHistory(msg tea.KeyMsg) (Model, tea.Cmd) {
+	k := m.keys.History
+	switch {
+	case key.Matches(msg, k.Quit):
+		return m, tea.Quit
+	case key.Matches(msg, k.Back):
+		m.state = stateFilePicker
+	case key.Matches(msg, k.Up):
+		if m.historyCursor > 0 {
+			m.historyCursor--
+		}
+	case key.Matches(msg, k.Down):
+		if m.historyCursor < len(m.history)-1 {
+			m.historyCursor++
+		}
+	case key.Matches(msg, k.Rerun):
+		// Re-run with the same columns and options, without column selection
+		m.state = stateLoading
+		return m, loadRun(m.history[m.historyCursor].Files)
+	}
+	return m, nil
+}
+
+// historyLines renders each entry on one line, with the cursor marked.
+func (m Model) historyLines() []string {
+	var lines []string
+	for i, entry := range m.history {
+		var names []string
+		for _, file := range entry.Files {
+			names = append(names, filepath.Base(file.Path))
+		}
+
+		cursor := " "
+		if i == m.historyCursor {
+			cursor = ">"
+		}
+		line := fmt.Sprintf("%s %s  %s", cursor, entry.Time.Local().Format("Jan 02 15:04"), strings.Join(names, ", "))
+		if i == m.historyCursor {
+			line = SelectedStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// listWindow returns the range of a list of n lines to show in height
+// lines, keeping the cursor visible.
+func listWindow(cursor, n, height int) (start, end int) {
+	height = max(height, 1)
+	start = max(cursor-height+1, 0)
+	end = min(start+height, n)
+	return start, end
+}
+
+func (m Model) viewHistory() string {
+	var s strings.Builder
+
+	s.WriteString(TitleStyle.Render(tr("⏰ Recent Conversions")))
+	s.WriteString("\n")
+	s.WriteString(SubtitleStyle.Render(tr("Re-run a past conversion with the same columns and options")))
+	s.WriteString("\n\n")
+
+	// Leave room for the title, details, help and box border
+	start, end := listWindow(m.historyCursor, len(m.history), m.height-16)
+	s.WriteString(strings.Join(m.historyLines()[start:end], "\n"))
+	s.WriteString("\n\n")
+
+	// Details of the entry under the cursor
+	entry := m.history[m.historyCursor]
+	labels := alignLabels(tr("Input:"), tr("Output:"), tr("Columns:"))
+	for _, file := range entry.Files {
+		s.WriteString(labels[0] + file.Path + "\n")
+		if file.Output != "" {
+			s.WriteString(SuccessStyle.Render(labels[1] + file.Output))
+			s.WriteString("\n")
+		}
+		s.WriteString(labels[2] + strings.Join(file.Columns, ", ") + "\n")
+	}
+	s.WriteString("\n")
+
+	s.WriteString(m.shortHelp())
+
+	return m.box(s.String())
+}
@@ -0,0 +1,544 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// keyMap holds the key bindings of every screen. Each screen's bindings
+// implement help.KeyMap: the short help is the line under the screen, and
+// the full help is the overlay opened with '?'.
+//
+// Bindings can be remapped in the config file; see keyMap.apply. Ctrl+C
+// always quits and the prompts' Enter and Esc keys are fixed.
+type keyMap struct {
+	Picker  pickerKeys
+	Columns columnKeys
+	History historyKeys
+	Results resultKeys
+	Error   errorKeys
+	Prompts promptKeys
+}
+
+type pickerKeys struct {
+	Up, Down      key.Binding
+	Select        key.Binding
+	Confirm       key.Binding
+	EditSelection key.Binding
+	RemoveLast    key.Binding
+	Search        key.Binding
+	SearchAll     key.Binding
+	TypePath      key.Binding
+	Download      key.Binding
+	Sort          key.Binding
+	ToggleHidden  key.Binding
+	RepeatLastRun key.Binding
+	History       key.Binding
+	Help, Quit    key.Binding
+}
+
+func (k pickerKeys) ShortHelp() []key.Binding {
+	return []key.Binding{k.Select, k.Confirm, k.Search, k.Help, k.Quit}
+}
+
+func (k pickerKeys) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Up, k.Down, k.Select, k.Confirm, k.EditSelection, k.RemoveLast},
+		{k.Search, k.SearchAll, k.TypePath, k.Download, k.Sort, k.ToggleHidden},
+		{k.RepeatLastRun, k.History, k.Help, k.Quit},
+	}
+}
+
+type columnKeys struct {
+	Up, Down         key.Binding
+	PageUp, PageDown key.Binding
+	Home, End        key.Binding
+	Toggle           key.Binding
+	SelectDetected   key.Binding
+	SelectAll        key.Binding
+	DeselectAll      key.Binding
+	Invert           key.Binding
+	Filter           key.Binding
+	ClearFilter      key.Binding
+	KeepOriginal     key.Binding
+	Placement        key.Binding
+	OnlySelected     key.Binding
+	KeepColumn       key.Binding
+	Details          key.Binding
+	Reorder          key.Binding
+	MoveUp           key.Binding
+	MoveDown         key.Binding
+	MoreFooter       key.Binding
+	FewerFooter      key.Binding
+	DropFooter       key.Binding
+	RequireNonEmpty  key.Binding
+	RequireValue     key.Binding
+	Encoding         key.Binding
+	LineEndings      key.Binding
+	FinalNewline     key.Binding
+	Quoting          key.Binding
+	Destination      key.Binding
+	CommentOriginals key.Binding
+	Table            key.Binding
+	FlagAbove        key.Binding
+	Blanks           key.Binding
+	Durations        key.Binding
+	Totals           key.Binding
+	Rename           key.Binding
+	Explain          key.Binding
+	Browse           key.Binding
+	SaveProfile      key.Binding
+	Confirm          key.Binding
+	Help, Quit       key.Binding
+}
+
+func (k columnKeys) ShortHelp() []key.Binding {
+	return []key.Binding{k.Toggle, k.Confirm, k.Filter, k.Explain, k.Help, k.Quit}
+}
+
+func (k columnKeys) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Up, k.Down, k.PageUp, k.PageDown, k.Home, k.End},
+		{k.Toggle, k.SelectDetected, k.SelectAll, k.DeselectAll, k.Invert, k.Filter, k.ClearFilter},
+		{k.Reorder, k.MoveUp, k.MoveDown},
+		{k.Details, k.KeepOriginal, k.Placement, k.Rename, k.OnlySelected, k.KeepColumn, k.MoreFooter, k.FewerFooter, k.DropFooter, k.RequireNonEmpty, k.RequireValue, k.Encoding, k.LineEndings, k.FinalNewline, k.Quoting, k.Destination, k.CommentOriginals, k.Table, k.FlagAbove, k.Blanks, k.Durations, k.Totals},
+		{k.Explain, k.Browse, k.SaveProfile, k.Confirm, k.Help, k.Quit},
+	}
+}
+
+type historyKeys struct {
+	Up, Down   key.Binding
+	Rerun      key.Binding
+	Back       key.Binding
+	Help, Quit key.Binding
+}
+
+func (k historyKeys) ShortHelp() []key.Binding {
+	return []key.Binding{k.Up, k.Down, k.Rerun, k.Back, k.Help, k.Quit}
+}
+
+func (k historyKeys) FullHelp() [][]key.Binding {
+	return [][]key.Binding{{k.Up, k.Down, k.Rerun}, {k.Back, k.Help, k.Quit}}
+}
+
+type resultKeys struct {
+	Up, Down         key.Binding
+	PageUp, PageDown key.Binding
+	Toggle           key.Binding
+	ToggleAll        key.Binding
+	Open             key.Binding
+	Reveal           key.Binding
+	CopyPath         key.Binding
+	Summary          key.Binding
+	Report           key.Binding
+	Sidecars         key.Binding
+	Audit            key.Binding
+	Zip              key.Binding
+	Undo             key.Binding
+	Restart          key.Binding
+	Help, Quit       key.Binding
+}
+
+func (k resultKeys) ShortHelp() []key.Binding {
+	return []key.Binding{k.Summary, k.Undo, k.Restart, k.Help, k.Quit}
+}
+
+func (k resultKeys) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Up, k.Down, k.PageUp, k.PageDown, k.Toggle, k.ToggleAll},
+		{k.Open, k.Reveal, k.CopyPath},
+		{k.Summary, k.Report, k.Sidecars, k.Audit, k.Zip, k.Undo},
+		{k.Restart, k.Help, k.Quit},
+	}
+}
+
+type errorKeys struct {
+	BugReport  key.Binding
+	Restart    key.Binding
+	Help, Quit key.Binding
+}
+
+func (k errorKeys) ShortHelp() []key.Binding {
+	return []key.Binding{k.BugReport, k.Restart, k.Help, k.Quit}
+}
+
+func (k errorKeys) FullHelp() [][]key.Binding {
+	return [][]key.Binding{{k.BugReport, k.Restart, k.Help, k.Quit}}
+}
+
+// promptKeys are the keys of the prompts shown over the column screen
+// before a batch starts, besides its Confirm and Quit. Each prompt names
+// its keys in its own help rather than the help line.
+type promptKeys struct {
+	Overwrite, OverwriteAll key.Binding
+	Rename, RenameAll       key.Binding
+	Skip, SkipAll           key.Binding
+	Reload                  key.Binding
+}
+
+func binding(keys []string, helpKey, desc string) key.Binding {
+	return key.NewBinding(key.WithKeys(keys...), key.WithHelp(helpKey, translator.Text(desc)))
+}
+
+// defaultKeyMap returns the built-in key bindings.
+func defaultKeyMap() keyMap {
+	helpKey := binding([]string{"?"}, "?", "all keys")
+	quit := binding([]string{"q"}, "q", "quit")
+	up := binding([]string{"up", "k"}, "↑/k", "up")
+	down := binding([]string{"down", "j"}, "↓/j", "down")
+
+	return keyMap{
+		Picker: pickerKeys{
+			// The file picker also accepts its own Ctrl+P and Ctrl+N
+			Up:            binding([]string{"up", "k", "ctrl+p"}, "↑/k", "up"),
+			Down:          binding([]string{"down", "j", "ctrl+n"}, "↓/j", "down"),
+			Select:        binding([]string{" "}, "space", "select file"),
+			Confirm:       binding([]string{"enter"}, "enter", "confirm selection"),
+			EditSelection: binding([]string{"tab"}, "tab", "edit selection"),
+			RemoveLast:    binding([]string{"delete"}, "delete", "remove last file"),
+			Search:        binding([]string{"/"}, "/", "search"),
+			SearchAll:     binding([]string{"ctrl+r"}, "ctrl+r", "search subfolders"),
+			TypePath:      binding([]string{"p"}, "p", "type a path"),
+			Download:      binding([]string{"u"}, "u", "download from URL"),
+			Sort:          binding([]string{"s"}, "s", "sort"),
+			ToggleHidden:  binding([]string{"."}, ".", "hidden files"),
+			RepeatLastRun: binding([]string{"r"}, "r", "repeat last run"),
+			History:       binding([]string{"H"}, "H", "history"),
+			Help:          helpKey,
+			Quit:          quit,
+		},
+		Columns: columnKeys{
+			Up:               up,
+			Down:             down,
+			PageUp:           binding([]string{"pgup", "ctrl+u"}, "pgup", "page up"),
+			PageDown:         binding([]string{"pgdown", "ctrl+d"}, "pgdn", "page down"),
+			Home:             binding([]string{"home", "g"}, "home/g", "first column"),
+			End:              binding([]string{"end", "G"}, "end/G", "last column"),
+			Toggle:           binding([]string{" "}, "space", "toggle"),
+			SelectDetected:   binding([]string{"a"}, "a", "select detected"),
+			SelectAll:        binding([]string{"A"}, "A", "select all"),
+			DeselectAll:      binding([]string{"d"}, "d", "deselect all"),
+			Invert:           binding([]string{"i"}, "i", "invert"),
+			Filter:           binding([]string{"/"}, "/", "filter columns"),
+			ClearFilter:      binding([]string{"esc"}, "esc", "clear filter"),
+			KeepOriginal:     binding([]string{"o"}, "o", "keep original"),
+			Placement:        binding([]string{"l"}, "l", "converted column placement"),
+			OnlySelected:     binding([]string{"s"}, "s", "output only selected columns"),
+			KeepColumn:       binding([]string{"m"}, "m", "keep column in slim output"),
+			Details:          binding([]string{"right", "tab"}, "→/tab", "column settings"),
+			Reorder:          binding([]string{"r"}, "r", "reorder columns"),
+			MoveUp:           binding([]string{"K"}, "K", "move column up (reorder)"),
+			MoveDown:         binding([]string{"J"}, "J", "move column down (reorder)"),
+			MoreFooter:       binding([]string{"+", "="}, "+", "more footer rows"),
+			FewerFooter:      binding([]string{"-"}, "-", "fewer footer rows"),
+			DropFooter:       binding([]string{"f"}, "f", "drop footer"),
+			RequireNonEmpty:  binding([]string{"e"}, "e", "require non-empty"),
+			RequireValue:     binding([]string{"v"}, "v", "require value"),
+			Encoding:         binding([]string{"c"}, "c", "output encoding"),
+			LineEndings:      binding([]string{"L"}, "L", "line endings (CSV)"),
+			FinalNewline:     binding([]string{"N"}, "N", "final newline (CSV)"),
+			Quoting:          binding([]string{"Q"}, "Q", "quoting (CSV)"),
+			Destination:      binding([]string{"w"}, "w", "write to a new sheet (XLSX)"),
+			CommentOriginals: binding([]string{"C"}, "C", "note original values in comments (XLSX)"),
+			Table:            binding([]string{"t"}, "t", "wrap in an Excel table (XLSX)"),
+			FlagAbove:        binding([]string{"h"}, "h", "flag large values"),
+			Blanks:           binding([]string{"b"}, "b", "blanks and zeros"),
+			Durations:        binding([]string{"D"}, "D", "compute durations from start and end times"),
+			Totals:           binding([]string{"T"}, "T", "total hours per employee and period"),
+			Rename:           binding([]string{"n"}, "n", "name converted column"),
+			Explain:          binding([]string{"x"}, "x", "explain detection"),
+			Browse:           binding([]string{"V"}, "V", "browse rows"),
+			SaveProfile:      binding([]string{"p"}, "p", "save profile"),
+			Confirm:          binding([]string{"enter"}, "enter", "confirm"),
+			Help:             helpKey,
+			Quit:             quit,
+		},
+		History: historyKeys{
+			Up:    up,
+			Down:  down,
+			Rerun: binding([]string{"enter"}, "enter", "re-run"),
+			Back:  binding([]string{"esc", "H"}, "esc", "back"),
+			Help:  helpKey,
+			Quit:  quit,
+		},
+		Results: resultKeys{
+			Up:        up,
+			Down:      down,
+			PageUp:    binding([]string{"pgup", "ctrl+u"}, "pgup", "page up"),
+			PageDown:  binding([]string{"pgdown", "ctrl+d"}, "pgdn", "page down"),
+			Toggle:    binding([]string{" "}, "space", "show/hide details"),
+			ToggleAll: binding([]string{"tab"}, "tab", "show/hide all details"),
+			Open:      binding([]string{"o"}, "o", "open file"),
+			Reveal:    binding([]string{"O"}, "O", "show in folder"),
+			CopyPath:  binding([]string{"y"}, "y", "copy path"),
+			Summary:   binding([]string{"s"}, "s", "save run summary"),
+			Report:    binding([]string{"e"}, "e", "export report"),
+			Sidecars:  binding([]string{"m"}, "m", "save metadata sidecars"),
+			Audit:     binding([]string{"a"}, "a", "save audit log"),
+			Zip:       binding([]string{"z"}, "z", "zip outputs"),
+			Undo:      binding([]string{"u"}, "u", "undo"),
+			Restart:   binding([]string{"enter"}, "enter", "convert more files"),
+			Help:      helpKey,
+			Quit:      binding([]string{"q", "esc"}, "q", "quit"),
+		},
+		Error: errorKeys{
+			BugReport: binding([]string{"b"}, "b", "save bug report"),
+			Restart:   binding([]string{"enter"}, "enter", "start over"),
+			Help:      helpKey,
+			Quit:      binding([]string{"q", "esc"}, "q", "quit"),
+		},
+		Prompts: promptKeys{
+			Overwrite:    binding([]string{"o"}, "o", "overwrite"),
+			OverwriteAll: binding([]string{"O"}, "O", "overwrite the rest"),
+			Rename:       binding([]string{"r"}, "r", "rename"),
+			RenameAll:    binding([]string{"R"}, "R", "rename the rest"),
+			Skip:         binding([]string{"s"}, "s", "skip"),
+			SkipAll:      binding([]string{"S"}, "S", "skip the rest"),
+			Reload:       binding([]string{"r"}, "r", "reload"),
+		},
+	}
+}
+
+// actions names each screen's bindings for the config file.
+func (k *keyMap) actions() map[string]map[string]*key.Binding {
+	p, c, h, r, e, q := &k.Picker, &k.Columns, &k.History, &k.Results, &k.Error, &k.Prompts
+	return map[string]map[string]*key.Binding{
+		"picker": {
+			"up": &p.Up, "down": &p.Down, "select": &p.Select, "confirm": &p.Confirm,
+			"edit_selection": &p.EditSelection, "remove_last": &p.RemoveLast,
+			"search": &p.Search, "search_subfolders": &p.SearchAll, "type_path": &p.TypePath, "download": &p.Download,
+			"sort": &p.Sort, "toggle_hidden": &p.ToggleHidden,
+			"repeat_last_run": &p.RepeatLastRun, "history": &p.History,
+			"help": &p.Help, "quit": &p.Quit,
+		},
+		"columns": {
+			"up": &c.Up, "down": &c.Down, "page_up": &c.PageUp, "page_down": &c.PageDown,
+			"home": &c.Home, "end": &c.End, "toggle": &c.Toggle,
+			"select_detected": &c.SelectDetected, "select_all": &c.SelectAll,
+			"deselect_all": &c.DeselectAll, "invert": &c.Invert,
+			"filter": &c.Filter, "clear_filter": &c.ClearFilter,
+			"keep_original": &c.KeepOriginal, "placement": &c.Placement, "only_selected": &c.OnlySelected, "keep_column": &c.KeepColumn, "details": &c.Details, "reorder": &c.Reorder, "move_up": &c.MoveUp, "move_down": &c.MoveDown, "more_footer": &c.MoreFooter,
+			"fewer_footer": &c.FewerFooter, "drop_footer": &c.DropFooter,
+			"require_non_empty": &c.RequireNonEmpty, "require_value": &c.RequireValue,
+			"encoding": &c.Encoding, "line_endings": &c.LineEndings, "final_newline": &c.FinalNewline, "quoting": &c.Quoting, "destination": &c.Destination, "comment_originals": &c.CommentOriginals, "table": &c.Table, "flag_above": &c.FlagAbove, "blanks": &c.Blanks, "durations": &c.Durations, "totals": &c.Totals, "rename": &c.Rename, "explain": &c.Explain, "browse": &c.Browse, "save_profile": &c.SaveProfile,
+			"confirm": &c.Confirm, "help": &c.Help, "quit": &c.Quit,
+		},
+		"history": {
+			"up": &h.Up, "down": &h.Down, "confirm": &h.Rerun, "back": &h.Back,
+			"help": &h.Help, "quit": &h.Quit,
+		},
+		"results": {
+			"up": &r.Up, "down": &r.Down, "page_up": &r.PageUp, "page_down": &r.PageDown,
+			"toggle": &r.Toggle, "toggle_all": &r.ToggleAll,
+			"open": &r.Open, "reveal": &r.Reveal, "copy_path": &r.CopyPath, "summary": &r.Summary, "report": &r.Report, "sidecars": &r.Sidecars, "audit": &r.Audit, "zip": &r.Zip, "undo": &r.Undo,
+			"confirm": &r.Restart, "help": &r.Help, "quit": &r.Quit,
+		},
+		"error": {
+			"bug_report": &e.BugReport, "confirm": &e.Restart, "help": &e.Help, "quit": &e.Quit,
+		},
+		"prompts": {
+			"overwrite": &q.Overwrite, "overwrite_all": &q.OverwriteAll,
+			"rename": &q.Rename, "rename_all": &q.RenameAll,
+			"skip": &q.Skip, "skip_all": &q.SkipAll, "reload": &q.Reload,
+		},
+	}
+}
+
+// apply remaps bindings from the config file's "keys" setting. Names are
+// "screen.action", such as "columns.keep_original", or just the action to
+// remap it on every screen that has it, such as "quit". An empty key list
+// turns the action off. Unknown names are reported in err; the rest still
+// apply.
+func (k keyMap) apply(overrides map[string][]string) (keyMap, error) {
+	actions := k.actions()
+
+	names := make([]string, 0, len(overrides))
+	for name := range overrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var unknown []string
+	for _, name := range names {
+		var targets []*key.Binding
+		if screen, action, ok := strings.Cut(name, "."); ok {
+			if b := actions[screen][action]; b != nil {
+				targets = append(targets, b)
+			}
+		} else {
+			for _, screen := range actions {
+				if b := screen[name]; b != nil {
+					targets = append(targets, b)
+				}
+			}
+		}
+		if len(targets) == 0 {
+			unknown = append(unknown, name)
+			continue
+		}
+		for _, b := range targets {
+			rebind(b, overrides[name])
+		}
+	}
+
+	if len(unknown) > 0 {
+		return k, fmt.Errorf("unknown key bindings in config: %s", strings.Join(unknown, ", "))
+	}
+	return k, nil
+}
+
+// rebind replaces b's keys, keeping its description.
+func rebind(b *key.Binding, keys []string) {
+	if len(keys) == 0 {
+		b.SetEnabled(false)
+		return
+	}
+	labels := make([]string, len(keys))
+	for i, k := range keys {
+		labels[i] = k
+		if k == " " {
+			labels[i] = "space"
+		}
+	}
+	b.SetKeys(keys...)
+	b.SetHelp(strings.Join(labels, "/"), b.Help().Desc)
+}
+
+// helpKeys joins the keys of bindings as help shows them, such as "O/R/S",
+// for prompts that name their keys in a sentence of help.
+func helpKeys(bindings ...key.Binding) string {
+	var keys []string
+	for _, b := range bindings {
+		if b.Enabled() {
+			keys = append(keys, b.Help().Key)
+		}
+	}
+	return strings.Join(keys, "/")
+}
+
+// newHelp returns a help model styled to match the rest of the interface.
+func newHelp() help.Model {
+	h := help.New()
+	keyStyle := lipgloss.NewStyle().Foreground(color(theme.Accent))
+	descStyle := lipgloss.NewStyle().Foreground(color(theme.Muted))
+	h.Styles.ShortKey = keyStyle
+	h.Styles.FullKey = keyStyle
+	h.Styles.ShortDesc = descStyle
+	h.Styles.FullDesc = descStyle
+	h.Styles.ShortSeparator = descStyle
+	h.Styles.FullSeparator = descStyle
+	h.Styles.Ellipsis = descStyle
+	return h
+}
+
+// screenKeys returns the bindings of the current screen, or nil on screens
+// without keys of their own.
+func (m Model) screenKeys() help.KeyMap {
+	switch m.state {
+	case stateFilePicker:
+		return m.keys.Picker
+	case stateColumnSelection:
+		return m.keys.Columns
+	case stateHistory:
+		return m.keys.History
+	case stateComplete:
+		k := m.keys.Results
+		k.Zip.SetEnabled(len(m.archives) > 0)
+		return k
+	case stateError:
+		return m.keys.Error
+	}
+	return nil
+}
+
+// helpBinding returns the current screen's key for the help overlay.
+func (m Model) helpBinding() key.Binding {
+	switch m.state {
+	case stateColumnSelection:
+		return m.keys.Columns.Help
+	case stateHistory:
+		return m.keys.History.Help
+	case stateComplete:
+		return m.keys.Results.Help
+	case stateError:
+		return m.keys.Error.Help
+	}
+	return m.keys.Picker.Help
+}
+
+// typing reports whether a prompt has focus, so '?' is text rather than a key.
+func (m Model) typing() bool {
+	return m.editingURL || m.editingPath || m.searching ||
+		m.editingFilter || m.editingColumnQuery || m.editingProfile || m.editingHeader || m.editingSetting
+}
+
+// shortHelp renders the help line under the current screen.
+func (m Model) shortHelp() string {
+	keys := m.screenKeys()
+	if keys == nil {
+		return ""
+	}
+	return HelpStyle.Render(m.shortHelpView(keys.ShortHelp(), m.width-8)) // Room for a box's border and padding
+}
+
+// shortHelpView renders as many bindings as fit in width, marking any that
+// were left out with an ellipsis. When some don't fit, the help key moves
+// to the front so the rest stay reachable. help.Model's own Width still
+// overflows when the ellipsis doesn't fit, so the line is measured here.
+func (m Model) shortHelpView(bindings []key.Binding, width int) string {
+	view := m.help.ShortHelpView(bindings)
+	if width <= 0 || lipgloss.Width(view) <= width {
+		return view
+	}
+
+	helpKey := m.helpBinding()
+	reordered := []key.Binding{helpKey}
+	for _, b := range bindings {
+		if b.Help() != helpKey.Help() {
+			reordered = append(reordered, b)
+		}
+	}
+	bindings = reordered
+
+	tail := " " + m.help.Styles.Ellipsis.Render(m.help.Ellipsis)
+	for n := len(bindings) - 1; n > 0; n-- {
+		view = m.help.ShortHelpView(bindings[:n]) + tail
+		if lipgloss.Width(view) <= width {
+			return view
+		}
+	}
+	return truncate(m.help.ShortHelpView(bindings[:1]), width)
+}
+
+// viewHelp renders the key reference overlay for the current screen.
+func (m Model) viewHelp() string {
+	keys := m.screenKeys()
+	groups := keys.FullHelp()
+
+	// Columns side by side when they fit, otherwise one under another
+	h := m.help
+	body := h.FullHelpView(groups)
+	if m.width > 0 && lipgloss.Width(body)+8 > m.width {
+		var stacked []string
+		for _, group := range groups {
+			if len(stacked) > 0 {
+				stacked = append(stacked, "")
+			}
+			stacked = append(stacked, h.FullHelpView([][]key.Binding{group}))
+		}
+		body = lipgloss.JoinVertical(lipgloss.Left, stacked...)
+	}
+
+	if m.compact() {
+		return TitleStyle.UnsetMarginTop().Render("⏰ Keys") + "\n" + body
+	}
+
+	title := TitleStyle.UnsetMarginTop().Render(tr("⏰ Keyboard Shortcuts"))
+	footer := HelpStyle.Render(tr("?/esc: close"))
+	return m.box(lipgloss.JoinVertical(lipgloss.Left, title, "", body, footer))
+}
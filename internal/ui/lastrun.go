@@ -0,0 +1,133 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/nconklindev/chronos/internal/config"
+	"github.com/nconklindev/chronos/internal/converter"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type lastRunLoadedMsg struct {
+	files   []string
+	configs []fileConfig
+	// redetected names the files whose columns were detected again, since
+	// some of those the run was configured with are gone.
+	redetected []string
+	err        error
+}
+
+// repeatLastRun loads the files from the previous run, or the newest files
+// like them, and applies the columns and options that were used.
+func repeatLastRun() tea.Cmd {
+	return func() tea.Msg {
+		run, err := config.LoadLastRun()
+		if err != nil {
+			return lastRunLoadedMsg{err: err}
+		}
+		return loadRun(run.Files)()
+	}
+}
+
+// loadRun loads the files of a saved run, or the newest files like them,
+// and applies the columns and options that were used.
+func loadRun(files []config.RunFile) tea.Cmd {
+	return func() tea.Msg {
+		var msg lastRunLoadedMsg
+		for _, file := range files {
+			path, err := file.Resolve()
+			if err != nil {
+				return lastRunLoadedMsg{err: err}
+			}
+
+			cfg, redetected, err := restoreConfig(path, file)
+			if err != nil {
+				return lastRunLoadedMsg{err: err}
+			}
+			if len(cfg.selectedCols) == 0 {
+				return lastRunLoadedMsg{err: fmt.Errorf("none of the previous columns were found in %s", path)}
+			}
+			if redetected {
+				msg.redetected = append(msg.redetected, filepath.Base(path))
+			}
+
+			msg.files = append(msg.files, path)
+			msg.configs = append(msg.configs, cfg)
+		}
+
+		return msg
+	}
+}
+
+// restoreConfig reads the file at path and applies the columns and options
+// saved for it. Columns are matched by header, and the options naming
+// columns by index are moved with them. When some of the saved headers are
+// gone the file's columns are detected again instead, as when it's
+// reloaded, and redetected is set.
+func restoreConfig(path string, file config.RunFile) (cfg fileConfig, redetected bool, err error) {
+	data, err := converter.ReadFileData(path)
+	if err != nil {
+		return fileConfig{}, false, err
+	}
+
+	wanted := make(map[string]bool)
+	for _, col := range file.Columns {
+		wanted[col] = true
+	}
+	opts, ok := file.OptionsFor(data.Headers)
+	if !ok {
+		return redetect(path, data, wanted, file.Options), true, nil
+	}
+
+	cfg = newFileConfig(path, data)
+	cfg.setDurations(opts.Durations)
+	cfg.options = opts
+	cfg.selectedCols = make(map[int]bool)
+	for i, header := range cfg.fileData.Headers {
+		if wanted[header] {
+			cfg.selectedCols[i] = true
+		}
+	}
+	return cfg, false, nil
+}
+
+// redetectedStatus asks for the columns of files detected again to be checked.
+func redetectedStatus(names []string) string {
+	return tr("Columns of %s moved or are gone, so they were detected again; check them and press enter", strings.Join(names, ", "))
+}
+
+// saveLastRun records the finished run so it can be repeated, and adds it
+// to the history. Files that came from an archive or a download are left
+// out since their paths were temporary.
+func (m Model) saveLastRun() {
+	var run config.LastRun
+	for i, res := range m.results {
+		if i >= len(m.configs) {
+			break
+		}
+		if _, ok := m.archives[res.InputFile]; ok {
+			continue
+		}
+		if _, ok := m.downloads[res.InputFile]; ok {
+			continue
+		}
+		run.Files = append(run.Files, config.RunFile{
+			Path:    res.InputFile,
+			Output:  res.OutputFile,
+			Columns: res.ColumnsFound,
+			Headers: m.configs[i].readData.Headers,
+			Options: m.configs[i].options,
+		})
+	}
+
+	if len(run.Files) == 0 {
+		return
+	}
+	// Failing to remember the run shouldn't affect the conversion
+	_ = config.SaveLastRun(run)
+	_ = config.AddHistory(config.HistoryEntry{Time: time.Now(), Files: run.Files})
+}
@@ -2,15 +2,32 @@ package ui
 
 import (
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/nconklindev/chronos/internal/archive"
+	"github.com/nconklindev/chronos/internal/audit"
+	"github.com/nconklindev/chronos/internal/bugreport"
+	"github.com/nconklindev/chronos/internal/config"
 	"github.com/nconklindev/chronos/internal/converter"
+	"github.com/nconklindev/chronos/internal/filepicker"
+	"github.com/nconklindev/chronos/internal/i18n"
+	"github.com/nconklindev/chronos/internal/profile"
+	"github.com/nconklindev/chronos/internal/stats"
+	"github.com/nconklindev/chronos/internal/summary"
 	"github.com/nconklindev/chronos/internal/types"
+	"github.com/nconklindev/chronos/internal/update"
 
-	"github.com/charmbracelet/bubbles/filepicker"
+	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/progress"
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -31,16 +48,64 @@ const (
 	stateComplete
 	// stateError displays any errors that occurred during the process.
 	stateError
+	// stateHistory lists recent conversions so one can be re-run.
+	stateHistory
+	// stateSetup asks for the preferences on first launch.
+	stateSetup
 )
 
+// stateNames name each state in debug and crash logs.
+var stateNames = map[state]string{
+	stateFilePicker:      "file picker",
+	stateLoading:         "loading",
+	stateColumnSelection: "column selection",
+	stateProcessing:      "processing",
+	stateComplete:        "complete",
+	stateError:           "error",
+	stateHistory:         "history",
+	stateSetup:           "setup",
+}
+
+func (s state) String() string {
+	return stateNames[s]
+}
+
+// progressWidth is the progress bar's width when the terminal has room for it.
+const progressWidth = 40
+
+// explanationHelp replaces the column keys while the detection explanation is open.
+const explanationHelp = "↑/↓: scroll • x/esc: back to columns • q: quit"
+
+// reorderHelp replaces the column keys while columns are being reordered.
+const reorderHelp = "↑/↓: choose column • K/J: move it up/down • r/esc: done • q: quit"
+
 type fileConfig struct {
-	path              string
-	fileData          *types.FileData
+	path     string
+	fileData *types.FileData
+	// readData is the file as read, and fileData the same with the
+	// computed duration columns added.
+	readData *types.FileData
+	// stats summarize each column of fileData for the pane beside the
+	// column list.
+	stats             []converter.ColumnStats
 	detectedCols      []int
 	selectedCols      map[int]bool
 	selectableIndices []int
-	keepOriginal      bool
+	options           types.ConversionOptions
 	cursor            int
+	// query narrows the column list to headers containing it. cursor indexes the visible columns.
+	query string
+	// inPlace adds the converted sheet to the file itself rather than to a
+	// converted copy. It's only set along with options.NewSheet.
+	inPlace bool
+	// output is where the converted copy goes instead of overwriting the
+	// existing one, and skip leaves the file out of the batch instead.
+	output string
+	skip   bool
+	// loaded is the file's size and modification time when it was read.
+	loaded fileStamp
+	// preset is the vendor preset applied to the file, which names its output.
+	preset *profile.Preset
 }
 
 // Model holds the application state.
@@ -51,24 +116,174 @@ type Model struct {
 
 	// selectedFiles stores the paths of all files selected by the user.
 	selectedFiles []string
+	// selectionFocused moves the keyboard from the file picker to the
+	// selected files list, where selectionCursor picks a file to remove.
+	selectionFocused bool
+	selectionCursor  int
 	// currentFileIndex tracks which file is currently being configured or processed.
 	currentFileIndex int
 	// configs holds the column selection and settings for each selected file.
 	configs []fileConfig
 	// results stores the outcome of each file conversion.
 	results []*types.ConversionResult
-
-	err          error
+	// totals are the lifetime stats after the last finished run, if they could be saved.
+	totals *stats.Stats
+	// archives maps files extracted from a selected archive to the archive they came from.
+	archives map[string]string
+	// downloads maps files fetched from a URL to the URL they came from.
+	downloads map[string]string
+	// tempDirs holds the directories archives were extracted and downloads were saved into.
+	tempDirs []string
+	// resultsView scrolls the results on the complete screen, where
+	// resultCursor picks the file whose details expanded toggles.
+	resultsView  viewport.Model
+	resultCursor int
+	expanded     map[int]bool
+	// exportingReport asks which format to export the batch report in.
+	exportingReport bool
+	// auditLogs lists the audit logs the current batch was added to, so
+	// saving it again doesn't add its rows twice.
+	auditLogs []string
+	// batchStart is when the current batch started converting.
+	batchStart time.Time
+	// generated lists the files written by the current batch, so it can be undone.
+	generated []generatedFile
+
+	opts Options
+	// keys are the key bindings, and help renders them under each screen
+	// and in the overlay shown while showHelp is set.
+	keys     keyMap
+	help     help.Model
+	showHelp bool
+	// settings are the preferences restored at startup and saved by SaveSettings.
+	settings config.Settings
+	// settingsErr is why the saved settings couldn't be read, if they
+	// couldn't, which keeps SaveSettings from replacing them with defaults.
+	settingsErr error
+
+	// columnInput edits the column list filter of the current file.
+	columnInput        textinput.Model
+	editingColumnQuery bool
+
+	// filterInput prompts for the value a row filter should match.
+	filterInput   textinput.Model
+	editingFilter bool
+
+	// headerInput prompts for the header of a column's converted copy.
+	headerInput   textinput.Model
+	editingHeader bool
+
+	// settingInput prompts for a column's custom format pattern or
+	// expression, which is previewed as it's typed. settingBefore is
+	// restored if it's cancelled.
+	settingInput   textinput.Model
+	editingSetting bool
+	settingBefore  types.ColumnSettings
+	settingErr     error
+
+	// profileInput prompts for the name to save the current columns under.
+	profileInput   textinput.Model
+	editingProfile bool
+
+	// explaining shows why each column was or wasn't auto-detected instead of the column list.
+	explaining bool
+	// browsing shows the file's first rows in browser instead of the
+	// column list. browseColumn is the first column in view.
+	browsing     bool
+	browser      table.Model
+	browseColumn int
+	// columnDetail shows the settings of the column under the cursor
+	// instead of the column list. detailField is the setting being changed.
+	columnDetail bool
+	detailField  int
+	// reordering moves the column under the cursor with the move keys
+	// instead of selecting columns.
+	reordering bool
+	// suspects describes the hand-picked columns whose values look like
+	// IDs, money or dates, which are confirmed before they're converted.
+	suspects []string
+	// recognized is the vendor preset matching the file's headers, offered
+	// before its columns are chosen by hand.
+	recognized *profile.Preset
+	// quickConfirm asks once whether to convert a file opened on its own
+	// with the columns chosen for it, before showing the column list.
+	quickConfirm bool
+	// conflicts are the queue positions of the files whose converted copies
+	// already exist, each waiting to be overwritten, renamed or skipped
+	// before the batch starts.
+	conflicts []int
+	// problems are what the preflight checks found would stop the batch,
+	// such as unreadable inputs or output folders that can't be written.
+	problems []string
+	// changed are the queue positions of the files that changed on disk
+	// after they were read, waiting to be reloaded or converted anyway.
+	changed []int
+
+	// urlInput prompts for a link to download a file from.
+	urlInput   textinput.Model
+	editingURL bool
+
+	// pathInput prompts for a path or glob to select files from.
+	pathInput   textinput.Model
+	editingPath bool
+
+	// searchInput filters the file picker's directory while searching is set.
+	searchInput     textinput.Model
+	searching       bool
+	searchRecursive bool
+	// searchEntries are the candidates listed when the search opened, and
+	// searchMatches the ones matching the query, best first.
+	searchEntries []searchEntry
+	searchMatches []searchMatch
+	searchCursor  int
+
+	// session is a batch quit before it was converted, offered on the file
+	// picker until it's resumed or discarded.
+	session *config.Session
+
+	// setup walks through the preferences on first launch.
+	setup *setup
+
+	// history holds the recent conversions shown on the history screen, newest first.
+	history       []config.HistoryEntry
+	historyCursor int
+
+	err error
+	// status reports the outcome of the last action on the complete or error screen.
+	status       string
 	width        int
 	height       int
 	progress     progress.Model
-	progressChan chan float64
+	progressChan chan converter.Progress
+	// lastProgress is the latest report from the file being converted,
+	// which names its phase under the progress bar.
+	lastProgress converter.Progress
+	// fileStatuses are where each file of the batch has got to, by queue position.
+	fileStatuses []fileStatus
 	resultChan   chan conversionResultMsg
 }
 
+// Options configures the initial state of the model.
+type Options struct {
+	// StartDir is the directory the file picker opens in. Defaults to the user's home directory.
+	StartDir string
+	// URL is downloaded and converted at startup when set.
+	URL string
+	// File is converted at startup when set, as when chronos is used to
+	// open it, skipping the file picker.
+	File string
+	// Profile, when set, is enforced on every loaded file and supplies its columns and options.
+	Profile *profile.Profile
+	// Version, Commit, and Date identify the build in bug reports.
+	Version string
+	Commit  string
+	Date    string
+}
+
 type conversionResultMsg struct {
-	result *types.ConversionResult
-	err    error
+	result  *types.ConversionResult
+	outputs []generatedFile
+	err     error
 }
 
 type fileLoadedMsg struct {
@@ -76,48 +291,188 @@ type fileLoadedMsg struct {
 	err  error
 }
 
+type selectionExpandedMsg struct {
+	files    []string
+	archives map[string]string
+	tempDirs []string
+	err      error
+}
+
+type downloadCompleteMsg struct {
+	path    string
+	url     string
+	tempDir string
+	err     error
+}
+
 type conversionCompleteMsg struct {
-	result *types.ConversionResult
-	err    error
+	result  *types.ConversionResult
+	outputs []generatedFile
+	err     error
 }
 
-type progressMsg float64
+type progressMsg converter.Progress
 
 type waitForProgressMsg struct{}
 
-func InitialModel() Model {
+func InitialModel(opts Options) Model {
+	// Unreadable settings fall back to the defaults, and are reported and
+	// left as they are so a typo doesn't cost the rest of them
+	settings, settingsErr := config.LoadSettings()
+	var problems []string
+	if settingsErr != nil {
+		problems = append(problems, settingsErr.Error()+"; using the defaults, and not saving settings until it's fixed")
+	}
+
+	// Bad themes and bindings are reported, and the rest still apply
+	t, err := LoadTheme(settings.Theme, settings.Colors)
+	if err != nil {
+		problems = append(problems, err.Error())
+	}
+	SetTheme(t)
+	SetLanguage(i18n.Detect(settings.Language))
+
 	fp := filepicker.New()
-	fp.AllowedTypes = []string{".csv", ".xlsx"}
-	fp.CurrentDirectory, _ = os.UserHomeDir()
+	fp.AllowedTypes = append([]string{".csv", ".xlsx", ".json", ".ndjson"}, archive.Extensions...)
+	fp.ShowHidden = settings.ShowHidden
+	fp.SortBy = filepicker.SortOrder(settings.SortBy)
+	if fp.SortBy == "" {
+		fp.SortBy = filepicker.SortName
+	}
+	fp.CurrentDirectory = opts.StartDir
+	// The picker's own navigation follows the configured bindings
+	keys, err := defaultKeyMap().apply(settings.Keys)
+	if err != nil {
+		problems = append(problems, err.Error())
+	}
+	fp.KeyMap.Up = keys.Picker.Up
+	fp.KeyMap.Down = keys.Picker.Down
+	if fp.CurrentDirectory == "" && settings.StartDir != "" {
+		if info, err := os.Stat(settings.StartDir); err == nil && info.IsDir() {
+			fp.CurrentDirectory = settings.StartDir
+		}
+	}
+	if fp.CurrentDirectory == "" {
+		if info, err := os.Stat(settings.LastDir); err == nil && info.IsDir() {
+			fp.CurrentDirectory = settings.LastDir
+		}
+	}
+	if fp.CurrentDirectory == "" {
+		fp.CurrentDirectory, _ = os.UserHomeDir()
+	}
 
-	// Set filepicker colors to match theme
-	fp.Styles.Cursor = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF8C42"))
-	fp.Styles.Symlink = lipgloss.NewStyle().Foreground(lipgloss.Color("#FFB84D"))
-	fp.Styles.Directory = lipgloss.NewStyle().Foreground(lipgloss.Color("#FFB84D"))
-	fp.Styles.File = lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF"))
-	fp.Styles.Permission = lipgloss.NewStyle().Foreground(lipgloss.Color("#6B7280"))
-	fp.Styles.Selected = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF8C42")).Bold(true)
-	fp.Styles.FileSize = lipgloss.NewStyle().Foreground(lipgloss.Color("#6B7280"))
+	styleFilePicker(&fp)
+	prog := newProgress()
 
-	// Initialize progress bar
-	prog := progress.New(progress.WithGradient("#FF8C42", "#FF9F5A"))
+	filterInput := textinput.New()
+	filterInput.Prompt = "Only convert rows where this column equals: "
+	filterInput.PromptStyle = SelectedStyle
+
+	headerInput := textinput.New()
+	headerInput.Prompt = "Converted column header: "
+	headerInput.PromptStyle = SelectedStyle
+
+	settingInput := textinput.New()
+	settingInput.PromptStyle = SelectedStyle
+
+	profileInput := textinput.New()
+	profileInput.Prompt = "Save profile as: "
+	profileInput.PromptStyle = SelectedStyle
+
+	urlInput := textinput.New()
+	urlInput.Prompt = "Download from URL: "
+	urlInput.PromptStyle = SelectedStyle
+
+	columnInput := textinput.New()
+	columnInput.Prompt = "Filter columns: "
+	columnInput.PromptStyle = SelectedStyle
+
+	pathInput := textinput.New()
+	pathInput.Prompt = "Path or glob: "
+	pathInput.PromptStyle = SelectedStyle
+	pathInput.Placeholder = "~/Downloads/*.xlsx"
+
+	searchInput := textinput.New()
+	searchInput.Prompt = "/ "
+	searchInput.PromptStyle = SelectedStyle
+	searchInput.Placeholder = "fuzzy search"
+
+	state := stateFilePicker
+	selected := []string{}
+	if opts.URL != "" {
+		state = stateLoading
+	}
+	if opts.File != "" {
+		state = stateLoading
+		selected = []string{opts.File}
+	}
+	// The first launch sets up the preferences, unless a file, a URL or the
+	// demo files were opened straight away
+	var su *setup
+	if state == stateFilePicker && opts.StartDir == "" && !config.HasSettings() {
+		state = stateSetup
+		su = newSetup(settings)
+	}
+
+	// An unfinished batch is offered unless something else was asked for.
+	// Profiles are checked as files load, so theirs aren't resumed.
+	var session *config.Session
+	if opts.URL == "" && opts.File == "" && opts.Profile == nil {
+		session, _ = config.LoadSession()
+	}
 
 	return Model{
-		state:         stateFilePicker,
+		opts:          opts,
+		keys:          keys,
+		status:        strings.Join(problems, "; "),
+		help:          newHelp(),
+		settings:      settings,
+		settingsErr:   settingsErr,
+		filterInput:   filterInput,
+		urlInput:      urlInput,
+		searchInput:   searchInput,
+		pathInput:     pathInput,
+		columnInput:   columnInput,
+		profileInput:  profileInput,
+		headerInput:   headerInput,
+		settingInput:  settingInput,
+		state:         state,
+		session:       session,
+		setup:         su,
 		filepicker:    fp,
-		selectedFiles: []string{},
+		selectedFiles: selected,
 		configs:       []fileConfig{},
 		progress:      prog,
 		viewport:      viewport.New(0, 0),
+		resultsView:   viewport.New(0, 0),
 	}
 }
 
 func (m Model) Init() tea.Cmd {
-	return m.filepicker.Init()
+	cmds := []tea.Cmd{m.filepicker.Init()}
+	if m.opts.URL != "" {
+		cmds = append(cmds, downloadFile(m.opts.URL))
+	}
+	if m.opts.File != "" {
+		cmds = append(cmds, expandSelection(m.selectedFiles))
+	}
+	if m.settings.CheckUpdates && update.IsRelease(m.opts.Version) {
+		cmds = append(cmds, checkForUpdate(m.opts.Version))
+	}
+	return tea.Batch(cmds...)
 }
 
 // Update handles incoming events and updates the model state.
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	defer m.recoverCrash()
+	next, cmd := m.update(msg)
+	if n, ok := next.(Model); ok && n.state != m.state {
+		slog.Debug("state changed", "from", m.state.String(), "to", n.state.String(), "file", n.currentFileIndex+1, "files", len(n.selectedFiles))
+	}
+	return next, cmd
+}
+
+func (m Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
@@ -125,13 +480,13 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		// Set filepicker height based on available space
 		// Build the viewport chrome to measure actual height needed
-		title := TitleStyle.Render("⏰ Chronos - Decimal to Hour Converter")
-		authorSpan := SubtitleStyle.Render("by Nick Conklin • ")
+		title := TitleStyle.Render(tr("⏰ Chronos - Decimal to Hour Converter"))
+		authorSpan := SubtitleStyle.Render(tr("by Nick Conklin") + " • ")
 		githubSpan := LinkStyle.Render("https://github.com/nconklindev/chronos")
 		byLine := lipgloss.JoinHorizontal(lipgloss.Top, authorSpan, githubSpan)
 		header := lipgloss.JoinVertical(lipgloss.Left, title, byLine)
-		subtitle := SubtitleStyle.Render("Select up to 3 files to convert")
-		help := HelpStyle.Render("Space: select file • Enter: confirm selection • Backspace: remove last file • q: quit")
+		subtitle := SubtitleStyle.Render(tr("Select up to 3 files to convert"))
+		help := HelpStyle.Render(m.shortHelpView(m.keys.Picker.ShortHelp(), msg.Width))
 
 		// Measure actual chrome height for filepicker
 		chromeHeight := lipgloss.Height(header) + lipgloss.Height(subtitle) + lipgloss.Height(help) + 6 // Add spacing
@@ -143,18 +498,29 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		// Update viewport dimensions
 		// Build column selection chrome to measure actual height
-		vpTitle := TitleStyle.Render("⏰ Select Columns to Convert")
-		vpSubtitle := SubtitleStyle.Render("File (1/1): example.csv") // Representative text
-		vpHelp := HelpStyle.Render("↑/↓: navigate • space: toggle • o: keep original • a: select all detected • enter: confirm • q: quit")
-		vpScrollInfo := SubtitleStyle.Render("Viewing 1-10 of 10 columns") // Representative text
-		vpKeepOriginal := "Keep Original Columns: [ ]"
+		vpTitle := TitleStyle.Render(tr("⏰ Select Columns to Convert"))
+		vpSubtitle := SubtitleStyle.Render(tr("File (%d/%d): %s", 1, 1, "example.csv")) // Representative text
+		vpHelp := HelpStyle.Render(m.shortHelpView(m.keys.Columns.ShortHelp(), msg.Width))
+		vpScrollInfo := SubtitleStyle.Render(tr("Viewing %d-%d of %d columns", 1, 10, 10)) // Representative text
+		vpOptions := strings.Join([]string{
+			tr("Keep Original Columns: %s", "[ ]"),
+			tr("Output Columns: %s", "all"),
+			tr("Column Order: %s", tr("as in file")),
+			tr("Footer Rows Skipped: %d (%s)", 0, tr("passed through")),
+			tr("Row Filters: %d active", 0),
+			tr("Flag: %s", "off"),
+			tr("Blanks and Zeros: %s", "empty cells left blank, zeros written"),
+			tr("Durations: %s", "off"),
+			tr("Totals: %s", "off"),
+			tr("Encoding: %s → %s • Lines: %s • Quoting: %s", "UTF-8", "UTF-8", "as input", "minimal"),
+		}, "\n")
 
 		// Measure viewport chrome height
 		vpChromeHeight := lipgloss.Height(vpTitle) +
 			lipgloss.Height(vpSubtitle) +
 			lipgloss.Height(vpHelp) +
 			lipgloss.Height(vpScrollInfo) +
-			lipgloss.Height(vpKeepOriginal) +
+			lipgloss.Height(vpOptions) +
 			8 // Add spacing between elements
 
 		vpHeight := msg.Height - vpChromeHeight
@@ -169,128 +535,776 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			vpWidth = 10
 		}
 
+		// The compact layout gives the picker and column list everything but its title and help lines
+		if m.compact() {
+			height = max(msg.Height-compactChrome-1, 1)
+			vpHeight = max(msg.Height-compactChrome, 1)
+			vpWidth = msg.Width
+			m.filepicker.SetHeight(height)
+		}
+
 		m.viewport.Width = vpWidth
 		m.viewport.Height = vpHeight
+		m.progress.Width = min(progressWidth, max(msg.Width-horizontalPadding*2, 10))
 
 		// If we are in column selection, update content to ensure it fits
 		if m.state == stateColumnSelection {
 			m.updateViewportContent()
+			if m.browsing {
+				m.layoutBrowser()
+			}
+		}
+		if m.state == stateComplete {
+			m.updateResultsContent()
 		}
 
 		return m, nil
 
 	case tea.KeyMsg:
+		// Ctrl+C always quits, whatever the configured bindings are
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+
+		// The key reference overlay is closed before anything else happens
+		if m.showHelp {
+			if msg.String() == "esc" || msg.String() == "q" || key.Matches(msg, m.helpBinding()) {
+				m.showHelp = false
+			}
+			return m, nil
+		}
+		if !m.typing() && m.screenKeys() != nil && key.Matches(msg, m.helpBinding()) {
+			m.showHelp = true
+			return m, nil
+		}
+
 		switch m.state {
+		case stateSetup:
+			return m.updateSetup(msg)
+
 		case stateFilePicker:
-			switch msg.String() {
-			case "ctrl+c", "q":
-				return m, tea.Quit
-			case " ":
-				// Spacebar is used to select a file. We simulate an Enter keypress
-				// for the filepicker component to trigger its selection logic.
-				enterMsg := tea.KeyMsg{Type: tea.KeyEnter}
-
-				var cmd tea.Cmd
-				m.filepicker, cmd = m.filepicker.Update(enterMsg)
-
-				if didSelect, path := m.filepicker.DidSelectFile(enterMsg); didSelect {
-					// Check if file is already selected
-					alreadySelected := false
-					for _, p := range m.selectedFiles {
-						if p == path {
-							alreadySelected = true
-							break
-						}
+			// While the URL prompt is open it receives all keys
+			if m.editingURL {
+				switch msg.String() {
+				case "enter":
+					m.editingURL = false
+					m.urlInput.Blur()
+					link := strings.TrimSpace(m.urlInput.Value())
+					if !converter.IsURL(link) {
+						m.status = tr("Not a valid http(s) URL")
+						return m, nil
 					}
+					m.status = tr("Downloading %s...", link)
+					return m, downloadFile(link)
+				case "esc":
+					m.editingURL = false
+					m.urlInput.Blur()
+					return m, nil
+				default:
+					var cmd tea.Cmd
+					m.urlInput, cmd = m.urlInput.Update(msg)
+					return m, cmd
+				}
+			}
 
-					if !alreadySelected && len(m.selectedFiles) < 3 {
-						m.selectedFiles = append(m.selectedFiles, path)
-					}
+			// While the path prompt is open it receives all keys
+			if m.editingPath {
+				switch msg.String() {
+				case "enter":
+					m.editingPath = false
+					m.pathInput.Blur()
+					return m.applyTypedPath(m.pathInput.Value())
+				case "esc":
+					m.editingPath = false
+					m.pathInput.Blur()
+					return m, nil
+				default:
+					var cmd tea.Cmd
+					m.pathInput, cmd = m.pathInput.Update(msg)
+					return m, cmd
+				}
+			}
+
+			// While searching, the search prompt receives all keys
+			if m.searching {
+				return m.updateSearch(msg)
+			}
+
+			// The saved session waits for enter to resume it or esc to discard it
+			if m.session != nil {
+				switch {
+				case key.Matches(msg, m.keys.Picker.Quit):
+					return m, tea.Quit
+				case key.Matches(msg, m.keys.Picker.Confirm):
+					s := *m.session
+					m.session = nil
+					m.state = stateLoading
+					return m, resumeSession(s)
+				case msg.String() == "esc":
+					m.session = nil
+					_ = config.ClearSession()
+				}
+				return m, nil
+			}
+
+			if m.selectionFocused {
+				var handled bool
+				if m, handled = m.updateSelection(msg); handled {
 					return m, nil
 				}
-				return m, cmd
-			case "enter":
+			}
+
+			k := m.keys.Picker
+			switch {
+			case key.Matches(msg, k.Quit):
+				return m, tea.Quit
+			case key.Matches(msg, k.Download):
+				if len(m.selectedFiles) < 3 {
+					m.urlInput.SetValue("")
+					m.editingURL = true
+					return m, m.urlInput.Focus()
+				}
+			case key.Matches(msg, k.Search):
+				return m.startSearch()
+			case key.Matches(msg, k.TypePath):
+				m.pathInput.SetValue("")
+				m.editingPath = true
+				return m, m.pathInput.Focus()
+			case key.Matches(msg, k.Select):
+				return m.pickCurrentFile()
+			case key.Matches(msg, k.Confirm):
 				// Enter confirms the selection of all files and proceeds to the next step.
 				if len(m.selectedFiles) > 0 {
-					// Start loading the first file to prepare for column selection.
+					// Unpack any archives, then load the first file for column selection.
+					m.status = ""
 					m.currentFileIndex = 0
 					m.state = stateLoading
-					return m, m.loadFile(m.selectedFiles[0])
+					return m, expandSelection(m.selectedFiles)
 				}
-			case "delete":
+			case key.Matches(msg, k.RepeatLastRun):
+				// Repeat the previous run without going through column selection
+				m.state = stateLoading
+				return m, repeatLastRun()
+			case key.Matches(msg, k.History):
+				return m.openHistory(), nil
+			case key.Matches(msg, k.ToggleHidden):
+				m.filepicker.ShowHidden = !m.filepicker.ShowHidden
+				return m.openDirectory(m.filepicker.CurrentDirectory)
+			case key.Matches(msg, k.Sort):
+				m.filepicker.SortBy = m.filepicker.SortBy.Next()
+				return m.openDirectory(m.filepicker.CurrentDirectory)
+			case key.Matches(msg, k.RemoveLast):
+				m.removeSelected(len(m.selectedFiles) - 1)
+			case key.Matches(msg, k.EditSelection):
 				if len(m.selectedFiles) > 0 {
-					m.selectedFiles = m.selectedFiles[:len(m.selectedFiles)-1]
+					m.selectionFocused = true
+					m.selectionCursor = len(m.selectedFiles) - 1
 				}
 			}
 
 		case stateColumnSelection:
 			config := &m.configs[m.currentFileIndex]
-			switch msg.String() {
-			case "ctrl+c", "q":
-				return m, tea.Quit
-			case "up", "k":
-				if config.cursor > 0 {
-					config.cursor--
-					if config.cursor < m.viewport.YOffset {
-						m.viewport.SetYOffset(config.cursor)
+
+			// While the filter prompt is open it receives all keys
+			if m.editingFilter {
+				switch msg.String() {
+				case "enter":
+					if colIdx, ok := config.cursorColumn(); ok {
+						setEqualsFilter(&config.options, colIdx, strings.TrimSpace(m.filterInput.Value()))
 					}
+					m.editingFilter = false
+					m.filterInput.Blur()
 					m.updateViewportContent()
+				case "esc":
+					m.editingFilter = false
+					m.filterInput.Blur()
+				default:
+					var cmd tea.Cmd
+					m.filterInput, cmd = m.filterInput.Update(msg)
+					return m, cmd
 				}
-			case "down", "j":
-				if config.cursor < len(config.selectableIndices)-1 {
-					config.cursor++
-					if config.cursor >= m.viewport.YOffset+m.viewport.Height {
-						m.viewport.SetYOffset(config.cursor - m.viewport.Height + 1)
+				return m, nil
+			}
+
+			// While the header prompt is open it receives all keys
+			if m.editingHeader {
+				switch msg.String() {
+				case "enter":
+					if colIdx, ok := config.cursorColumn(); ok {
+						setConvertedHeader(&config.options, colIdx, config.fileData.Headers[colIdx], strings.TrimSpace(m.headerInput.Value()))
 					}
+					m.editingHeader = false
+					m.headerInput.Blur()
 					m.updateViewportContent()
+				case "esc":
+					m.editingHeader = false
+					m.headerInput.Blur()
+				default:
+					var cmd tea.Cmd
+					m.headerInput, cmd = m.headerInput.Update(msg)
+					return m, cmd
+				}
+				return m, nil
+			}
+
+			// While the setting prompt is open it receives all keys
+			if m.editingSetting {
+				return m.updateSettingPrompt(msg)
+			}
+
+			// While the column filter prompt is open it receives all keys
+			if m.editingColumnQuery {
+				return m.updateColumnQuery(msg)
+			}
+
+			// While the profile name prompt is open it receives all keys
+			if m.editingProfile {
+				switch msg.String() {
+				case "enter":
+					name := strings.TrimSpace(m.profileInput.Value())
+					p := profile.FromFile(name, config.fileData, config.selectedCols, config.options)
+					if err := profile.Save(p); err != nil {
+						m.status = tr("Could not save profile: %v", err)
+					} else {
+						m.status = tr("Saved profile %q", name)
+					}
+					m.editingProfile = false
+					m.profileInput.Blur()
+				case "esc":
+					m.editingProfile = false
+					m.profileInput.Blur()
+				default:
+					var cmd tea.Cmd
+					m.profileInput, cmd = m.profileInput.Update(msg)
+					return m, cmd
 				}
-			case " ":
+				return m, nil
+			}
+
+			k := m.keys.Columns
+
+			// Files that changed since they were read wait to be reloaded or converted anyway
+			if len(m.changed) > 0 {
+				switch {
+				case key.Matches(msg, k.Quit):
+					return m, tea.Quit
+				case key.Matches(msg, k.Confirm):
+					for _, i := range m.changed {
+						m.configs[i].loaded = stampFile(m.configs[i].path)
+					}
+					m.changed = nil
+					return m.startBatch()
+				case key.Matches(msg, m.keys.Prompts.Reload):
+					m.status = tr("Reloading...")
+					return m, m.reloadFiles()
+				case msg.String() == "esc":
+					m = m.backToColumns()
+				}
+				return m, nil
+			}
+
+			// Preflight problems wait to be fixed and checked again
+			if len(m.problems) > 0 {
+				switch {
+				case key.Matches(msg, k.Quit):
+					return m, tea.Quit
+				case key.Matches(msg, k.Confirm):
+					m.problems = nil
+					return m.startBatch()
+				case msg.String() == "esc":
+					m = m.backToColumns()
+				}
+				return m, nil
+			}
+
+			// Each existing output waits to be overwritten, renamed or skipped
+			if len(m.conflicts) > 0 {
+				if key.Matches(msg, k.Quit) {
+					return m, tea.Quit
+				}
+				return m.updateOverwrite(msg)
+			}
+
+			// A recognized vendor export is converted with its preset on enter
+			if m.recognized != nil {
+				switch {
+				case key.Matches(msg, k.Quit):
+					return m, tea.Quit
+				case key.Matches(msg, k.Confirm):
+					m.configs[m.currentFileIndex].applyPreset(m.recognized)
+					m.recognized = nil
+					return m.confirmColumns()
+				case msg.String() == "esc":
+					m.recognized = nil
+				}
+				return m, nil
+			}
+
+			// A file opened on its own is converted on enter
+			if m.quickConfirm {
+				switch {
+				case key.Matches(msg, k.Quit):
+					return m, tea.Quit
+				case key.Matches(msg, k.Confirm):
+					m.quickConfirm = false
+					return m.confirmColumns()
+				case msg.String() == "esc":
+					m.quickConfirm = false
+				}
+				return m, nil
+			}
+
+			// The suspicious column warning waits for enter to go ahead anyway
+			if len(m.suspects) > 0 {
+				switch {
+				case key.Matches(msg, k.Quit):
+					return m, tea.Quit
+				case key.Matches(msg, k.Confirm):
+					m.suspects = nil
+					return m.confirmColumns()
+				case msg.String() == "esc":
+					m.suspects = nil
+				}
+				return m, nil
+			}
+
+			// The detection explanation scrolls until it's closed
+			if m.explaining {
+				switch {
+				case key.Matches(msg, k.Quit):
+					return m, tea.Quit
+				case key.Matches(msg, k.Up):
+					m.viewport.ScrollUp(1)
+				case key.Matches(msg, k.Down):
+					m.viewport.ScrollDown(1)
+				case key.Matches(msg, k.Explain), msg.String() == "esc":
+					m.explaining = false
+					m.viewport.SetYOffset(0)
+					m.updateViewportContent()
+				}
+				return m, nil
+			}
+
+			// The data browser scrolls the file's rows until it's closed
+			if m.browsing {
+				switch {
+				case key.Matches(msg, k.Quit):
+					return m, tea.Quit
+				case msg.String() == "left":
+					m.scrollBrowser(-1)
+				case msg.String() == "right":
+					m.scrollBrowser(1)
+				case key.Matches(msg, k.Browse), msg.String() == "esc":
+					m.browsing = false
+				default:
+					m.browser, _ = m.browser.Update(msg)
+				}
+				return m, nil
+			}
+
+			// A column's settings take the keys until they're closed
+			if m.columnDetail {
+				return m.updateColumnDetail(msg)
+			}
+
+			// Reorder mode only moves columns until it's closed
+			if m.reordering {
+				switch {
+				case key.Matches(msg, k.Quit):
+					return m, tea.Quit
+				case key.Matches(msg, k.Up):
+					m.moveColumnCursor(-1)
+				case key.Matches(msg, k.Down):
+					m.moveColumnCursor(1)
+				case key.Matches(msg, k.MoveUp):
+					m.moveColumn(-1)
+				case key.Matches(msg, k.MoveDown):
+					m.moveColumn(1)
+				case key.Matches(msg, k.Reorder), msg.String() == "esc":
+					m.reordering = false
+					m.updateViewportContent()
+				}
+				return m, nil
+			}
+
+			switch {
+			case key.Matches(msg, k.Quit):
+				return m, tea.Quit
+			case key.Matches(msg, k.Reorder):
+				// Every column has to be visible to see where it ends up
+				if config.query != "" {
+					m.setColumnQuery("")
+				}
+				m.reordering = true
+				m.updateViewportContent()
+			case key.Matches(msg, k.SaveProfile):
+				// Prompt for a name to save these columns and options as a profile
+				m.profileInput.SetValue("")
+				if m.opts.Profile != nil {
+					m.profileInput.SetValue(m.opts.Profile.Name)
+				}
+				m.editingProfile = true
+				return m, m.profileInput.Focus()
+			case key.Matches(msg, k.Explain):
+				m.explaining = true
+				m.updateViewportContent()
+				m.viewport.SetYOffset(0)
+			case key.Matches(msg, k.Browse):
+				m.openBrowser()
+			case key.Matches(msg, k.Filter):
+				m.columnInput.SetValue(config.query)
+				m.columnInput.CursorEnd()
+				m.editingColumnQuery = true
+				return m, m.columnInput.Focus()
+			case key.Matches(msg, k.ClearFilter):
+				// Clear the column filter, keeping the selections made while it was on
+				if config.query != "" {
+					m.setColumnQuery("")
+				}
+			case key.Matches(msg, k.Up):
+				m.moveColumnCursor(-1)
+			case key.Matches(msg, k.Down):
+				m.moveColumnCursor(1)
+			case key.Matches(msg, k.PageUp):
+				m.moveColumnCursor(-m.viewport.Height)
+			case key.Matches(msg, k.PageDown):
+				m.moveColumnCursor(m.viewport.Height)
+			case key.Matches(msg, k.Home):
+				m.moveColumnCursor(-len(config.selectableIndices))
+			case key.Matches(msg, k.End):
+				m.moveColumnCursor(len(config.selectableIndices))
+			case key.Matches(msg, k.Toggle):
 				// Toggle selection for the column at the current cursor position
-				colIdx := config.selectableIndices[config.cursor]
-				config.selectedCols[colIdx] = !config.selectedCols[colIdx]
+				if colIdx, ok := config.cursorColumn(); ok {
+					if config.selectedCols[colIdx] {
+						delete(config.selectedCols, colIdx)
+					} else {
+						config.selectedCols[colIdx] = true
+					}
+					m.updateViewportContent()
+				}
+			case key.Matches(msg, k.KeepOriginal):
+				config.options.KeepOriginal = !config.options.KeepOriginal
+				m.updateViewportContent()
+			case key.Matches(msg, k.Placement):
+				// Empty means adjacent, so it cycles on from there
+				placement := config.options.Placement
+				if placement == "" {
+					placement = types.PlacementAdjacent
+				}
+				config.options.Placement = cycle(types.Placements, placement, 1)
+			case key.Matches(msg, k.OnlySelected):
+				config.options.OnlySelected = !config.options.OnlySelected
 				m.updateViewportContent()
-			case "o":
-				config.keepOriginal = !config.keepOriginal
+			case key.Matches(msg, k.KeepColumn):
+				// Keep the column under the cursor unconverted in slimmed output, like an employee ID
+				if colIdx, ok := config.cursorColumn(); ok {
+					toggleKeepColumn(&config.options, colIdx)
+					config.options.OnlySelected = true
+					m.updateViewportContent()
+				}
+			case key.Matches(msg, k.MoreFooter):
+				// Grow the footer, but always leave at least one data row
+				if config.options.FooterRows < len(config.fileData.Rows)-1 {
+					config.options.FooterRows++
+				}
+			case key.Matches(msg, k.FewerFooter):
+				if config.options.FooterRows > 0 {
+					config.options.FooterRows--
+				}
+			case key.Matches(msg, k.DropFooter):
+				config.options.DropFooter = !config.options.DropFooter
+			case key.Matches(msg, k.Encoding):
+				// Cycle the CSV output encoding, starting from "same as input"
+				config.options.OutputEncoding = nextOutputEncoding(config.options.OutputEncoding)
+			case key.Matches(msg, k.LineEndings):
+				// Cycle CSV line endings, for importers requiring Windows ones
+				config.options.LineEnding = cycle(types.LineEndings, config.options.LineEnding, 1)
+			case key.Matches(msg, k.FinalNewline):
+				// Cycle whether CSV output ends with a line ending
+				config.options.FinalNewline = cycle(types.FinalNewlines, config.options.FinalNewline, 1)
+			case key.Matches(msg, k.Quoting):
+				// Cycle which CSV fields are quoted, for strict parsers
+				config.options.Quoting = cycle(types.Quotings, config.options.Quoting, 1)
+			case key.Matches(msg, k.Destination):
+				// Cycle XLSX output: a converted copy, a converted sheet in
+				// a copy, then a converted sheet in the file itself
+				if !isXLSX(config.path) {
+					m.status = tr("Converted sheets are only for XLSX files")
+					break
+				}
+				switch {
+				case !config.options.NewSheet:
+					config.options.NewSheet = true
+				case !config.inPlace && m.canConvertInPlace(config.path):
+					config.inPlace = true
+				default:
+					config.options.NewSheet = false
+					config.inPlace = false
+				}
+			case key.Matches(msg, k.CommentOriginals):
+				// Note the values that cells converted in place had before
+				if !isXLSX(config.path) {
+					m.status = tr("Comments are only for XLSX files")
+					break
+				}
+				config.options.CommentOriginals = !config.options.CommentOriginals
+			case key.Matches(msg, k.Table):
+				// Wrap the converted data in an Excel table named after the file
+				if !isXLSX(config.path) {
+					m.status = tr("Tables are only for XLSX files")
+					break
+				}
+				if config.options.Table == "" {
+					config.options.Table = tableName(config.path)
+				} else {
+					config.options.Table = ""
+				}
+			case key.Matches(msg, k.FlagAbove):
+				// Cycle the threshold for flagging likely data entry errors
+				config.options.FlagAbove = cycle(flagThresholds, config.options.FlagAbove, 1)
+			case key.Matches(msg, k.Blanks):
+				// Cycle what's written for empty cells and zeros, for import systems rejecting either
+				config.options.Blanks = cycle(types.BlankPolicies, config.options.Blanks, 1)
+			case key.Matches(msg, k.Durations):
+				// Compute hours from pairs of start and end times, or stop
+				pairs := converter.DetectDurations(config.readData)
+				if len(config.options.Durations) > 0 {
+					pairs = nil
+				} else if len(pairs) == 0 {
+					m.status = tr("No start and end time columns found")
+					break
+				}
+				config.setDurations(pairs)
+				m.moveColumnCursor(0)
 				m.updateViewportContent()
-			case "a":
+			case key.Matches(msg, k.Totals):
+				// Cycle totals per employee by week, day and month, then off
+				switch {
+				case config.options.Totals == nil:
+					totals, ok := converter.DetectTotals(config.readData)
+					if !ok {
+						m.status = tr("No employee and date columns found")
+						break
+					}
+					config.options.Totals = &totals
+				case config.options.Totals.Period == types.Periods[len(types.Periods)-1]:
+					config.options.Totals = nil
+				default:
+					totals := *config.options.Totals
+					totals.Period = cycle(types.Periods, totals.Period, 1)
+					config.options.Totals = &totals
+				}
+			case key.Matches(msg, k.RequireNonEmpty):
+				// Only convert rows where the column under the cursor has a value
+				if colIdx, ok := config.cursorColumn(); ok {
+					toggleNonEmptyFilter(&config.options, colIdx)
+					m.updateViewportContent()
+				}
+			case key.Matches(msg, k.Rename):
+				// Prompt for the header of the converted column under the cursor
+				return m.startRename()
+			case key.Matches(msg, k.Details):
+				// Open the settings of the column under the cursor
+				if _, ok := config.cursorColumn(); ok {
+					m.columnDetail = true
+					m.detailField = detailFormat
+					m.updateViewportContent()
+					m.viewport.SetYOffset(0)
+				}
+			case key.Matches(msg, k.RequireValue):
+				// Prompt for a value the column under the cursor must equal
+				colIdx, ok := config.cursorColumn()
+				if !ok {
+					break
+				}
+				m.filterInput.SetValue("")
+				if i := findFilter(config.options.Filters, colIdx, types.FilterEquals); i >= 0 {
+					m.filterInput.SetValue(config.options.Filters[i].Value)
+				}
+				m.editingFilter = true
+				return m, m.filterInput.Focus()
+			case key.Matches(msg, k.SelectDetected):
 				// Select all detected columns
 				for _, idx := range config.detectedCols {
 					config.selectedCols[idx] = true
 				}
 				m.updateViewportContent()
-			case "enter":
+			case key.Matches(msg, k.SelectAll):
+				config.selectVisible(func(bool) bool { return true })
+				m.updateViewportContent()
+			case key.Matches(msg, k.DeselectAll):
+				config.selectVisible(func(bool) bool { return false })
+				m.updateViewportContent()
+			case key.Matches(msg, k.Invert):
+				config.selectVisible(func(selected bool) bool { return !selected })
+				m.updateViewportContent()
+			case key.Matches(msg, k.Confirm):
 				if len(config.selectedCols) > 0 {
-					// If there are more files to configure, load the next one.
-					if m.currentFileIndex < len(m.selectedFiles)-1 {
-						m.currentFileIndex++
-						m.state = stateLoading
-						return m, m.loadFile(m.selectedFiles[m.currentFileIndex])
-					} else {
-						// All files configured, start the batch conversion process.
-						m.state = stateProcessing
-						m.currentFileIndex = 0 // Reset index to start processing from the first file.
-						return m.convertNextFile()
+					// A profile's columns were already chosen when it was saved
+					if m.opts.Profile == nil {
+						m.suspects = config.suspectColumns()
+					}
+					if len(m.suspects) > 0 {
+						return m, nil
 					}
+					return m.confirmColumns()
 				}
 			}
 
+		case stateHistory:
+			return m.updateHistory(msg)
+
 		case stateComplete, stateError:
-			switch msg.String() {
-			case "ctrl+c", "q", "esc":
+			if m.exportingReport {
+				return m.updateExport(msg), nil
+			}
+			if m.state == stateComplete {
+				var handled bool
+				if m, handled = m.updateResults(msg); handled {
+					return m, nil
+				}
+			}
+
+			k, e := m.keys.Results, m.keys.Error
+			switch {
+			case key.Matches(msg, k.Quit, e.Quit):
 				return m, tea.Quit
-			case "enter":
+			case key.Matches(msg, e.BugReport):
+				if m.state == stateError {
+					path, err := m.writeBugReport()
+					if err != nil {
+						m.status = tr("Could not save bug report: %v", err)
+					} else {
+						m.status = tr("Bug report saved to %s", path)
+					}
+				}
+			case key.Matches(msg, k.Summary):
+				if m.state == stateComplete {
+					paths, err := m.writeRunSummary()
+					if err != nil {
+						m.status = tr("Could not save run summary: %v", err)
+					} else {
+						m.status = tr("Run summary saved to %s", strings.Join(paths, ", "))
+					}
+				}
+			case key.Matches(msg, k.Report):
+				if m.state == stateComplete {
+					m.exportingReport = true
+				}
+			case key.Matches(msg, k.Sidecars):
+				if m.state == stateComplete {
+					paths, err := summary.WriteSidecars(m.finishedRun())
+					if err != nil {
+						m.status = tr("Could not save sidecars: %v", err)
+					} else {
+						m.status = tr("Sidecars saved next to %d output(s)", len(paths))
+					}
+				}
+			case key.Matches(msg, k.Audit):
+				if m.state == stateComplete {
+					m.saveAuditLogs()
+				}
+			case key.Matches(msg, k.Open, k.Reveal):
+				if m.state == stateComplete && len(m.results) > 0 {
+					path := m.results[m.resultCursor].OutputFile
+					if err := openPath(path, key.Matches(msg, k.Reveal)); err != nil {
+						m.status = tr("Could not open %s: %v", filepath.Base(path), err)
+					} else {
+						m.status = tr("Opened %s", filepath.Base(path))
+					}
+				}
+			case key.Matches(msg, k.CopyPath):
+				if m.state == stateComplete && len(m.results) > 0 {
+					path := m.results[m.resultCursor].OutputFile
+					if err := clipboard.WriteAll(path); err != nil {
+						m.status = tr("Could not copy path: %v", err)
+					} else {
+						m.status = tr("Copied %s", path)
+					}
+				}
+			case key.Matches(msg, k.Undo):
+				if m.state == stateComplete {
+					return m.undoBatch()
+				}
+			case key.Matches(msg, k.Zip):
+				if m.state == stateComplete {
+					paths, generated, err := m.zipArchiveOutputs()
+					m.generated = append(m.generated, generated...)
+					switch {
+					case err != nil:
+						m.status = tr("Could not zip outputs: %v", err)
+					case len(paths) == 0:
+						m.status = tr("No files came from an archive")
+					default:
+						m.status = tr("Converted files zipped to %s", strings.Join(paths, ", "))
+					}
+				}
+			case key.Matches(msg, k.Restart, e.Restart):
 				// Reset to initial state
+				m.Cleanup()
+				m.tempDirs = nil
+				m.generated = nil
+				m.archives = nil
+				m.downloads = nil
+				m.totals = nil
 				m.state = stateFilePicker
 				m.selectedFiles = []string{}
+				m.selectionFocused = false
 				m.configs = []fileConfig{}
 				m.results = []*types.ConversionResult{}
+				m.auditLogs = nil
+				m.fileStatuses = nil
 				m.currentFileIndex = 0
 				m.err = nil
+				m.status = ""
 				return m, nil
 			}
 		}
 
+	// downloadCompleteMsg is received when a file has been fetched from a URL.
+	case downloadCompleteMsg:
+		if msg.tempDir != "" {
+			m.tempDirs = append(m.tempDirs, msg.tempDir)
+		}
+		if msg.err != nil {
+			m.err = msg.err
+			m.state = stateError
+			return m, nil
+		}
+		if m.downloads == nil {
+			m.downloads = make(map[string]string)
+		}
+		m.downloads[msg.path] = msg.url
+		m.selectedFiles = append(m.selectedFiles, msg.path)
+		m.status = ""
+
+		// A URL passed on the command line is converted straight away
+		if m.state == stateLoading {
+			return m, expandSelection(m.selectedFiles)
+		}
+		return m, nil
+
+	// selectionExpandedMsg is received once selected archives have been unpacked.
+	case selectionExpandedMsg:
+		m.tempDirs = append(m.tempDirs, msg.tempDirs...)
+		if msg.err != nil {
+			m.err = msg.err
+			m.state = stateError
+			return m, nil
+		}
+		m.selectedFiles = msg.files
+		m.archives = msg.archives
+		return m, m.loadFile(m.selectedFiles[0])
+
 	// fileLoadedMsg is received when a file has been read from disk.
+	case updateAvailableMsg:
+		// Shown in the file picker unless something more pressing is
+		if m.status == "" {
+			m.status = updateNotice(msg.tag)
+		}
+		return m, nil
+
 	case fileLoadedMsg:
 		if msg.err != nil {
 			m.err = msg.err
@@ -298,103 +1312,566 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
-		// Auto-detect columns that look like decimal hours.
-		detected := converter.AutoDetectColumns(msg.data)
-		selected := make(map[int]bool)
-		for _, idx := range detected {
-			selected[idx] = true
+		config := m.loadedConfig(m.selectedFiles[m.currentFileIndex], msg.data)
+
+		// A profile is enforced strictly: mismatched files are reported before anything is converted
+		if m.opts.Profile != nil {
+			config.setDurations(m.opts.Profile.Options.Durations)
+			if err := m.opts.Profile.Validate(config.fileData); err != nil {
+				m.err = fmt.Errorf("%s: %w", filepath.Base(config.path), err)
+				m.state = stateError
+				return m, nil
+			}
+			config.selectedCols, config.options = m.opts.Profile.Apply(config.fileData)
+		} else {
+			m.recognized = profile.MatchPreset(config.fileData)
+		}
+
+		// A file opened on its own needs only a keypress to convert, unless
+		// there's nothing to convert or a preset is offered instead
+		if config.path == m.opts.File && len(m.selectedFiles) == 1 && m.recognized == nil {
+			m.quickConfirm = len(config.selectedCols) > 0
+			if !m.quickConfirm {
+				m.status = tr("No decimal hour columns were detected; choose the columns to convert")
+			}
+		}
+
+		// Ensure configs slice is large enough
+		if len(m.configs) <= m.currentFileIndex {
+			m.configs = append(m.configs, config)
+		} else {
+			m.configs[m.currentFileIndex] = config
+		}
+
+		m.state = stateColumnSelection
+
+		// Reset viewport scroll and update content
+		m.viewport.SetYOffset(0)
+		m.updateViewportContent()
+
+		return m, nil
+
+	// lastRunLoadedMsg is received when the previous run's files are ready to convert again.
+	case lastRunLoadedMsg:
+		if msg.err != nil {
+			m.err = msg.err
+			m.state = stateError
+			return m, nil
+		}
+		m.selectedFiles = msg.files
+		m.configs = msg.configs
+		// Existing outputs are asked about over the last file's columns
+		m.currentFileIndex = len(msg.configs) - 1
+		m.state = stateColumnSelection
+		m.viewport.SetYOffset(0)
+		m.updateViewportContent()
+		// Columns detected again are checked before anything is converted
+		if len(msg.redetected) > 0 {
+			m.status = redetectedStatus(msg.redetected)
+			return m, nil
+		}
+		if m.conflicts = m.existingOutputs(); len(m.conflicts) > 0 {
+			return m, nil
+		}
+		return m.startBatch()
+
+	// sessionLoadedMsg is received when a saved session's files are ready to configure again.
+	case sessionLoadedMsg:
+		// A session that can't be loaded isn't offered again
+		_ = config.ClearSession()
+		if msg.err != nil {
+			m.err = msg.err
+			m.state = stateError
+			return m, nil
+		}
+		m.selectedFiles = msg.files
+		m.configs = msg.configs
+		m.currentFileIndex = len(msg.configs) - 1
+		m.state = stateColumnSelection
+		m.viewport.SetYOffset(0)
+		m.updateViewportContent()
+		if len(msg.redetected) > 0 {
+			m.status = redetectedStatus(msg.redetected)
+		}
+		return m, nil
+
+	// filesReloadedMsg is received when files that changed since they were read have been read again.
+	case filesReloadedMsg:
+		if msg.err != nil {
+			m.err = msg.err
+			m.state = stateError
+			return m, nil
+		}
+		for i, cfg := range msg.configs {
+			m.configs[i] = cfg
+		}
+		m = m.backToColumns()
+		m.status = reloadedStatus(msg.configs)
+		m.updateViewportContent()
+		return m, nil
+
+	// conversionCompleteMsg is received when a single file conversion finishes.
+	case conversionCompleteMsg:
+		m.generated = append(m.generated, msg.outputs...)
+		if msg.err != nil {
+			m.fileStatuses[m.currentFileIndex] = fileFailed
+			m.err = msg.err
+			m.state = stateError
+			return m, m.notifyBatch("Chronos conversion failed", msg.err.Error())
+		}
+		m.results = append(m.results, msg.result)
+		m.fileStatuses[m.currentFileIndex] = fileDone
+
+		// If there are more files in the queue, start converting the next one.
+		if m.currentFileIndex < len(m.selectedFiles)-1 {
+			m.currentFileIndex++
+			return m.convertNextFile()
+		}
+
+		// All files processed.
+		m = m.showResults()
+		m.saveLastRun()
+		m.rememberOptions()
+		if totals, err := stats.Record(m.results); err == nil {
+			m.totals = &totals
+		}
+		rows := 0
+		for _, res := range m.results {
+			rows += res.RowsProcessed
+		}
+		return m, m.notifyBatch("Chronos conversion complete", fmt.Sprintf("%d file(s) converted, %d rows", len(m.results), rows))
+
+	case tea.MouseMsg:
+		return m.updateMouse(msg)
+
+	case progress.FrameMsg:
+		progressModel, cmd := m.progress.Update(msg)
+		m.progress = progressModel.(progress.Model)
+		return m, cmd
+
+	case progressMsg:
+		if m.state == stateProcessing {
+			m.lastProgress = converter.Progress(msg)
+			cmd := m.progress.SetPercent(m.lastProgress.Overall())
+			return m, tea.Batch(cmd, waitForProgress(m.progressChan, m.resultChan))
+		}
+		return m, nil
+
+	case waitForProgressMsg:
+		return m, waitForProgress(m.progressChan, m.resultChan)
+	}
+
+	// Handle filepicker updates
+	if m.state == stateFilePicker {
+		var cmd tea.Cmd
+		m.filepicker, cmd = m.filepicker.Update(msg)
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+// pickCurrentFile selects the file under the picker's cursor, or opens
+// it if it's a folder.
+func (m Model) pickCurrentFile() (Model, tea.Cmd) {
+	// We simulate an Enter keypress for the filepicker component to trigger its selection logic.
+	enterMsg := tea.KeyMsg{Type: tea.KeyEnter}
+
+	var cmd tea.Cmd
+	m.filepicker, cmd = m.filepicker.Update(enterMsg)
+
+	if didSelect, path := m.filepicker.DidSelectFile(enterMsg); didSelect {
+		m.selectFile(path)
+		return m, nil
+	}
+	return m, cmd
+}
+
+// selectFile adds path to the selection unless it's already selected or
+// the selection is full.
+func (m *Model) selectFile(path string) {
+	for _, p := range m.selectedFiles {
+		if p == path {
+			return
+		}
+	}
+	if len(m.selectedFiles) < 3 {
+		m.selectedFiles = append(m.selectedFiles, path)
+	}
+}
+
+// openDirectory moves the file picker to dir, starting at the top of its listing.
+func (m Model) openDirectory(dir string) (Model, tea.Cmd) {
+	m.filepicker.CurrentDirectory = dir
+	// The picker's position is private; its go-to-top key resets it
+	m.filepicker, _ = m.filepicker.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'g'}})
+	return m, m.filepicker.Init()
+}
+
+// newFileConfig creates the default configuration for a loaded file, with
+// the auto-detected columns selected.
+func newFileConfig(path string, data *types.FileData) fileConfig {
+	// Auto-detect columns that look like decimal hours.
+	detected := converter.AutoDetectColumns(data)
+	selected := make(map[int]bool)
+	for _, idx := range detected {
+		selected[idx] = true
+	}
+
+	config := fileConfig{
+		path:              path,
+		fileData:          data,
+		readData:          data,
+		stats:             converter.ComputeStats(data),
+		detectedCols:      detected,
+		selectedCols:      selected,
+		selectableIndices: selectableColumns(data.Headers),
+		loaded:            stampFile(path),
+		options: types.ConversionOptions{
+			FooterRows: data.FooterRows,
+			Columns:    converter.WithTimeColumns(nil, data.TimeColumns),
+		},
+		cursor: 0,
+	}
+
+	// Files without hours may have the start and end times to compute them from
+	if len(detected) == 0 {
+		config.setDurations(converter.DetectDurations(data))
+	}
+	return config
+}
+
+// loadedConfig creates the configuration for a file just read, starting
+// from the remembered options.
+func (m Model) loadedConfig(path string, data *types.FileData) fileConfig {
+	config := newFileConfig(path, data)
+	config.options.KeepOriginal = m.settings.KeepOriginal
+	config.options.DropFooter = m.settings.DropFooter
+	config.options.OutputEncoding = m.settings.OutputEncoding
+	config.options.LineEnding = m.settings.LineEnding
+	config.options.FinalNewline = m.settings.FinalNewline
+	config.options.Quoting = m.settings.Quoting
+	config.options.HeaderSuffix = m.settings.HeaderSuffix
+	config.options.Placement = m.settings.Placement
+	config.options.OnlySelected = m.settings.OnlySelected
+	config.options.LossThreshold = m.settings.LossThreshold
+	config.options.Blanks = m.settings.Blanks
+	if m.settings.Rounding != types.RoundNearest {
+		config.setRounding(m.settings.Rounding)
+	}
+	return config
+}
+
+// selectableColumns returns the columns with headers, which can be chosen.
+func selectableColumns(headers []string) []int {
+	var selectable []int
+	for i, header := range headers {
+		if strings.TrimSpace(header) != "" {
+			selectable = append(selectable, i)
+		}
+	}
+	return selectable
+}
+
+// loadFile reads the file content asynchronously.
+func (m Model) loadFile(path string) tea.Cmd {
+	return func() tea.Msg {
+		data, err := converter.ReadFileData(path)
+		return fileLoadedMsg{data: data, err: err}
+	}
+}
+
+// expandSelection replaces each selected archive with the files it contains,
+// extracted into a temporary directory.
+func expandSelection(paths []string) tea.Cmd {
+	return func() tea.Msg {
+		msg := selectionExpandedMsg{archives: make(map[string]string)}
+
+		for _, path := range paths {
+			if !archive.IsArchive(path) {
+				msg.files = append(msg.files, path)
+				continue
+			}
+
+			dir, err := os.MkdirTemp("", "chronos-archive-")
+			if err != nil {
+				msg.err = err
+				return msg
+			}
+			msg.tempDirs = append(msg.tempDirs, dir)
+
+			extracted, err := archive.Extract(path, dir)
+			if err != nil {
+				msg.err = err
+				return msg
+			}
+			for _, file := range extracted {
+				msg.files = append(msg.files, file)
+				msg.archives[file] = path
+			}
+		}
+
+		return msg
+	}
+}
+
+// downloadFile fetches a URL into a temporary directory.
+func downloadFile(link string) tea.Cmd {
+	return func() tea.Msg {
+		dir, err := os.MkdirTemp("", "chronos-download-")
+		if err != nil {
+			return downloadCompleteMsg{err: err}
+		}
+
+		path, err := converter.HTTPDownload{URL: link, Dir: dir}.Fetch()
+		return downloadCompleteMsg{path: path, url: link, tempDir: dir, err: err}
+	}
+}
+
+// outputDir returns the directory converted copies of a file are written to.
+// Files extracted from an archive go next to the archive, and downloaded
+// files go to the directory open in the file picker.
+func (m Model) outputDir(path string) string {
+	if archivePath, ok := m.archives[path]; ok {
+		path = archivePath
+	}
+	if _, ok := m.downloads[path]; ok {
+		return m.filepicker.CurrentDirectory
+	}
+	return filepath.Dir(path)
+}
+
+// canConvertInPlace reports whether a file is one the user picked, rather
+// than a temporary copy from an archive or download, so it can be changed in place.
+func (m Model) canConvertInPlace(path string) bool {
+	_, archived := m.archives[path]
+	_, downloaded := m.downloads[path]
+	return !archived && !downloaded
+}
+
+// isXLSX reports whether a file is a workbook.
+func isXLSX(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".xlsx")
+}
+
+// tableName names the Excel table of a file's converted data after the
+// file, replacing characters Excel doesn't allow in table names.
+func tableName(path string) string {
+	name := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' {
+			return r
+		}
+		return '_'
+	}, strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)))
+	if converter.CheckTableName(name) != nil {
+		name = "Table_" + name
+	}
+	return name
+}
+
+// outputPath returns where the converted copy of a file is written with
+// the given options.
+func (m Model) outputPath(path string, opts types.ConversionOptions) string {
+	base := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	suffix := m.settings.OutputSuffix
+	if suffix == "" {
+		suffix = "_converted"
+	}
+	return filepath.Join(m.outputDir(path), base+suffix+strings.ToLower(converter.OutputExt(path, opts)))
+}
+
+// zipArchiveOutputs bundles the converted files from each selected archive
+// into <archive>_converted.zip next to it. The zips are also returned as
+// generated files so they can be undone.
+func (m Model) zipArchiveOutputs() ([]string, []generatedFile, error) {
+	var order []string
+	outputs := make(map[string][]string)
+	for _, res := range m.results {
+		archivePath, ok := m.archives[res.InputFile]
+		if !ok {
+			continue
+		}
+		if _, seen := outputs[archivePath]; !seen {
+			order = append(order, archivePath)
 		}
+		outputs[archivePath] = append(outputs[archivePath], res.OutputFile)
+	}
 
-		// Filter out empty headers
-		var selectable []int
-		for i, header := range msg.data.Headers {
-			if strings.TrimSpace(header) != "" {
-				selectable = append(selectable, i)
+	var written []string
+	var generated []generatedFile
+	for _, archivePath := range order {
+		name := filepath.Base(archivePath)
+		for _, ext := range archive.Extensions {
+			if strings.HasSuffix(strings.ToLower(name), ext) {
+				name = name[:len(name)-len(ext)]
+				break
 			}
 		}
 
-		// Create a configuration for this file.
-		config := fileConfig{
-			path:              m.selectedFiles[m.currentFileIndex],
-			fileData:          msg.data,
-			detectedCols:      detected,
-			selectedCols:      selected,
-			selectableIndices: selectable,
-			keepOriginal:      false,
-			cursor:            0,
+		zipPath := filepath.Join(m.outputDir(archivePath), name+"_converted.zip")
+		output, err := backupOutput(zipPath)
+		if err != nil {
+			return written, generated, err
 		}
-
-		// Ensure configs slice is large enough
-		if len(m.configs) <= m.currentFileIndex {
-			m.configs = append(m.configs, config)
-		} else {
-			m.configs[m.currentFileIndex] = config
+		generated = append(generated, output)
+		if err := archive.Zip(zipPath, outputs[archivePath]); err != nil {
+			return written, generated, err
 		}
+		written = append(written, zipPath)
+	}
 
-		m.state = stateColumnSelection
+	return written, generated, nil
+}
 
-		// Reset viewport scroll and update content
-		m.viewport.SetYOffset(0)
-		m.updateViewportContent()
+// Cleanup removes the temporary directories archives were extracted into
+// and the backups kept for undo.
+func (m Model) Cleanup() {
+	for _, dir := range m.tempDirs {
+		os.RemoveAll(dir)
+	}
+	m.removeBackups()
+}
 
-		return m, nil
+// rememberOptions makes the options of the last converted file the
+// defaults for files loaded later, in this session and the next.
+func (m *Model) rememberOptions() {
+	if len(m.configs) == 0 {
+		return
+	}
+	opts := m.configs[len(m.configs)-1].options
+	m.settings.KeepOriginal = opts.KeepOriginal
+	m.settings.DropFooter = opts.DropFooter
+	m.settings.OutputEncoding = opts.OutputEncoding
+	m.settings.LineEnding = opts.LineEnding
+	m.settings.FinalNewline = opts.FinalNewline
+	m.settings.Quoting = opts.Quoting
+	m.settings.Placement = opts.Placement
+	m.settings.OnlySelected = opts.OnlySelected
+	m.settings.Blanks = opts.Blanks
+}
 
-	// conversionCompleteMsg is received when a single file conversion finishes.
-	case conversionCompleteMsg:
-		if msg.err != nil {
-			m.err = msg.err
-			m.state = stateError
-			return m, nil
+// SaveSettings remembers the file picker's directory, listing options and
+// the last used conversion options for the next session. The directory
+// isn't remembered when the picker was opened somewhere specific, such as
+// the demo files. Settings that couldn't be read aren't replaced.
+func (m Model) SaveSettings() error {
+	// Quitting the setup leaves it to be done next time
+	if m.state == stateSetup || m.settingsErr != nil {
+		return nil
+	}
+	settings := m.settings
+	if m.opts.StartDir == "" {
+		settings.LastDir = m.filepicker.CurrentDirectory
+	}
+	settings.ShowHidden = m.filepicker.ShowHidden
+	settings.SortBy = string(m.filepicker.SortBy)
+	return config.SaveSettings(settings)
+}
+
+// writeBugReport saves a bug report bundle for the current error into the
+// directory the user was browsing.
+func (m Model) writeBugReport() (string, error) {
+	info := bugreport.Info{
+		Version: m.opts.Version,
+		Commit:  m.opts.Commit,
+		Date:    m.opts.Date,
+	}
+	if m.err != nil {
+		info.Error = m.err.Error()
+	}
+	if m.currentFileIndex < len(m.selectedFiles) {
+		info.InputFile = m.selectedFiles[m.currentFileIndex]
+	}
+	// The columns chosen as hours are kept in the sample, and the rest masked
+	if m.currentFileIndex < len(m.configs) && m.configs[m.currentFileIndex].path == info.InputFile {
+		info.Columns = []int{}
+		for idx, selected := range m.configs[m.currentFileIndex].selectedCols {
+			if selected {
+				info.Columns = append(info.Columns, idx)
+			}
 		}
-		m.results = append(m.results, msg.result)
+	}
 
-		// If there are more files in the queue, start converting the next one.
-		if m.currentFileIndex < len(m.selectedFiles)-1 {
-			m.currentFileIndex++
-			return m.convertNextFile()
+	return bugreport.Write(m.filepicker.CurrentDirectory, info)
+}
+
+// finishedRun describes the finished conversions with the options each
+// was converted with.
+func (m Model) finishedRun() summary.Run {
+	options := make([]types.ConversionOptions, len(m.results))
+	for i := range m.results {
+		if i < len(m.configs) {
+			options[i] = m.configs[i].options
 		}
+	}
 
-		// All files processed.
-		m.state = stateComplete
-		return m, nil
+	return summary.NewRun(m.opts.Version, m.results, options)
+}
 
-	case progress.FrameMsg:
-		progressModel, cmd := m.progress.Update(msg)
-		m.progress = progressModel.(progress.Model)
-		return m, cmd
+// writeRunSummary saves a chronos-run.json describing the finished
+// conversions next to their outputs.
+func (m Model) writeRunSummary() ([]string, error) {
+	return summary.WriteAll(m.finishedRun())
+}
 
-	case progressMsg:
-		if m.state == stateProcessing {
-			cmd := m.progress.SetPercent(float64(msg))
-			return m, tea.Batch(cmd, waitForProgress(m.progressChan, m.resultChan))
+// saveAuditLogs adds the finished conversions to the audit log next to
+// their outputs. Conversions already logged, including by an earlier try
+// that failed partway, aren't added again.
+func (m *Model) saveAuditLogs() {
+	logged := make(map[string]bool, len(m.auditLogs))
+	for _, path := range m.auditLogs {
+		logged[path] = true
+	}
+	var pending []*types.ConversionResult
+	for _, res := range m.results {
+		if !logged[filepath.Join(filepath.Dir(res.OutputFile), audit.FileName)] {
+			pending = append(pending, res)
 		}
-		return m, nil
-
-	case waitForProgressMsg:
-		return m, waitForProgress(m.progressChan, m.resultChan)
 	}
-
-	// Handle filepicker updates
-	if m.state == stateFilePicker {
-		var cmd tea.Cmd
-		m.filepicker, cmd = m.filepicker.Update(msg)
-		return m, cmd
+	if len(pending) == 0 {
+		m.status = tr("Audit log already saved to %s", strings.Join(m.auditLogs, ", "))
+		return
 	}
 
-	return m, nil
+	paths, err := audit.AppendAll(pending, time.Now())
+	m.auditLogs = append(m.auditLogs, paths...)
+	if err != nil {
+		m.status = tr("Could not save audit log: %v", err)
+		return
+	}
+	m.status = tr("Audit log saved to %s", strings.Join(m.auditLogs, ", "))
 }
 
-// loadFile reads the file content asynchronously.
-func (m Model) loadFile(path string) tea.Cmd {
-	return func() tea.Msg {
-		data, err := converter.ReadFileData(path)
-		return fileLoadedMsg{data: data, err: err}
+// confirmColumns moves on from the column screen: to the next file to
+// configure, or to converting once every file is configured.
+func (m Model) confirmColumns() (Model, tea.Cmd) {
+	m.status = ""
+	// If there are more files to configure, load the next one.
+	if m.currentFileIndex < len(m.selectedFiles)-1 {
+		m.currentFileIndex++
+		m.state = stateLoading
+		return m, m.loadFile(m.selectedFiles[m.currentFileIndex])
+	}
+
+	// All files configured. Outputs that already exist are asked about
+	// before the batch starts.
+	if m.conflicts = m.existingOutputs(); len(m.conflicts) > 0 {
+		return m, nil
 	}
+	return m.startBatch()
 }
 
 // convertNextFile starts the conversion process for the current file in the queue.
 func (m Model) convertNextFile() (Model, tea.Cmd) {
-	m.progressChan = make(chan float64, 100)
+	if m.currentFileIndex == 0 {
+		m.batchStart = time.Now()
+	}
+	m.progressChan = make(chan converter.Progress, 100)
+	m.lastProgress = converter.Progress{}
+	if m.currentFileIndex == 0 {
+		m.fileStatuses = make([]fileStatus, len(m.selectedFiles))
+	}
+	m.fileStatuses[m.currentFileIndex] = fileRunning
 	m.resultChan = make(chan conversionResultMsg, 1)
 
 	config := m.configs[m.currentFileIndex]
@@ -407,34 +1884,54 @@ func (m Model) convertNextFile() (Model, tea.Cmd) {
 					selectedIndices = append(selectedIndices, idx)
 				}
 			}
+			sort.Ints(selectedIndices)
 
-			ext := strings.ToLower(filepath.Ext(config.path))
-			base := strings.TrimSuffix(config.path, ext)
-			outputFile := base + "_converted" + ext
+			outputFile := m.outputFor(config)
 
 			// Capture channels for the goroutine
 			progressChan := m.progressChan
 			resultChan := m.resultChan
 			selectedFile := config.path
-			keepOriginal := config.keepOriginal
+			options := config.options
+			// The audit log can be saved once the batch is done
+			options.RecordChanges = true
 
 			go func() {
 				var result *types.ConversionResult
+				var outputs []generatedFile
 				var err error
+				defer func() {
+					// Bubble Tea can't recover this goroutine, so a panic fails the file instead
+					if r := recover(); r != nil {
+						err = m.crashError(r)
+					}
 
-				switch ext {
-				case ".csv":
-					result, err = converter.ConvertCSV(selectedFile, outputFile, selectedIndices, keepOriginal, progressChan)
-				case ".xlsx":
-					result, err = converter.ConvertXLSX(selectedFile, outputFile, selectedIndices, keepOriginal, progressChan)
-				}
+					// Send result
+					resultChan <- conversionResultMsg{result: result, outputs: outputs, err: err}
 
-				// Send result
-				resultChan <- conversionResultMsg{result: result, err: err}
+					// Close channels
+					close(progressChan)
+					close(resultChan)
+				}()
 
-				// Close channels
-				close(progressChan)
-				close(resultChan)
+				// Keep a copy of any file about to be overwritten so the batch can be undone
+				for _, path := range []string{outputFile, converter.TotalsOutput(outputFile, options)} {
+					if path == "" {
+						continue
+					}
+					var output generatedFile
+					if output, err = backupOutput(path); err != nil {
+						break
+					}
+					outputs = append(outputs, output)
+				}
+				if err == nil {
+					var sink converter.OutputSink = converter.LocalFile(outputFile)
+					if config.inPlace {
+						sink = converter.ReplaceFile(outputFile)
+					}
+					result, err = converter.Convert(selectedFile, sink, selectedIndices, options, converter.ProgressChan(progressChan))
+				}
 			}()
 
 			return waitForProgressMsg{}
@@ -446,7 +1943,7 @@ func (m Model) convertNextFile() (Model, tea.Cmd) {
 	return m, cmd
 }
 
-func waitForProgress(progressChan chan float64, resultChan chan conversionResultMsg) tea.Cmd {
+func waitForProgress(progressChan chan converter.Progress, resultChan chan conversionResultMsg) tea.Cmd {
 	return func() tea.Msg {
 		if progressChan == nil {
 			return nil
@@ -467,6 +1964,17 @@ func waitForProgress(progressChan chan float64, resultChan chan conversionResult
 }
 
 func (m Model) View() string {
+	defer m.recoverCrash()
+	if m.tooSmall() {
+		return m.viewTooSmall()
+	}
+	if m.showHelp {
+		return m.viewHelp()
+	}
+	if m.compact() {
+		return m.viewCompact()
+	}
+
 	switch m.state {
 	case stateFilePicker:
 		return m.viewFilePicker()
@@ -480,65 +1988,153 @@ func (m Model) View() string {
 		return m.viewComplete()
 	case stateError:
 		return m.viewError()
+	case stateHistory:
+		return m.viewHistory()
+	case stateSetup:
+		return m.viewSetup()
 	}
 	return ""
 }
 
-func (m Model) viewFilePicker() string {
+// filePickerHeader renders everything above the file list: the title, the
+// selected files and the status line.
+func (m Model) filePickerHeader() string {
 	var s strings.Builder
 
-	title := TitleStyle.Render("⏰ Chronos - Decimal to Hour Converter")
+	title := TitleStyle.Render(tr("⏰ Chronos - Decimal to Hour Converter"))
 
-	authorSpan := SubtitleStyle.Render("by Nick Conklin • ")
+	authorSpan := SubtitleStyle.Render(tr("by Nick Conklin") + " • ")
 	githubSpan := LinkStyle.Render("https://github.com/nconklindev/chronos")
 	byLine := lipgloss.JoinHorizontal(lipgloss.Top, authorSpan, githubSpan)
+	if m.narrow() {
+		// Stack the link under the author rather than let it wrap mid-URL
+		byLine = lipgloss.JoinVertical(lipgloss.Left, SubtitleStyle.UnsetMarginBottom().Render(tr("by Nick Conklin")), githubSpan)
+	}
 
 	s.WriteString(lipgloss.JoinVertical(lipgloss.Left, title, byLine))
 	s.WriteString("\n")
-	s.WriteString(SubtitleStyle.Render("Select up to 3 files to convert"))
+	listing := map[filepicker.SortOrder]string{
+		filepicker.SortName:     tr("sorted by name"),
+		filepicker.SortModified: tr("newest first"),
+		filepicker.SortSize:     tr("largest first"),
+	}[m.filepicker.SortBy]
+	if m.filepicker.ShowHidden {
+		listing += tr(", showing hidden files")
+	}
+	s.WriteString(SubtitleStyle.Render(m.fit(tr("Select up to 3 files to convert") + " • " + listing)))
 	s.WriteString("\n\n")
 
 	// Show selected files
 	if len(m.selectedFiles) > 0 {
-		s.WriteString("Selected Files:\n")
-		for i, file := range m.selectedFiles {
-			s.WriteString(fmt.Sprintf("%d. %s\n", i+1, filepath.Base(file)))
-		}
-		s.WriteString("\n")
+		s.WriteString(tr("Selected Files:") + "\n")
+		s.WriteString(strings.Join(m.selectedFileLines(), "\n"))
+		s.WriteString("\n\n")
 		if len(m.selectedFiles) < 3 {
-			s.WriteString(SubtitleStyle.Render(fmt.Sprintf("(%d/3 selected) Select more or press 'Enter' to continue", len(m.selectedFiles))))
+			s.WriteString(SubtitleStyle.Render(m.fit(tr("(%d/3 selected) Select more or press 'Enter' to continue", len(m.selectedFiles)))))
 		} else {
-			s.WriteString(SuccessStyle.Render("Max files selected. Press 'Enter' to continue."))
+			s.WriteString(SuccessStyle.Render(tr("Max files selected. Press 'Enter' to continue.")))
 		}
 		s.WriteString("\n\n")
 	}
 
+	if m.status != "" {
+		s.WriteString(SubtitleStyle.Render(m.fit(m.status)))
+		s.WriteString("\n\n")
+	}
+
+	return s.String()
+}
+
+func (m Model) viewFilePicker() string {
+	var s strings.Builder
+	s.WriteString(m.filePickerHeader())
+
+	if m.editingURL {
+		s.WriteString(m.urlInput.View())
+		s.WriteString("\n")
+		s.WriteString(HelpStyle.Render(tr("enter: download • esc: cancel")))
+		return s.String()
+	}
+
+	if m.editingPath {
+		s.WriteString(m.pathInput.View())
+		s.WriteString("\n")
+		s.WriteString(HelpStyle.Render(tr("enter: select matching files or open folder • esc: cancel")))
+		return s.String()
+	}
+
+	if m.searching {
+		s.WriteString(m.viewSearch(m.filepicker.Height))
+		s.WriteString("\n\n")
+		s.WriteString(HelpStyle.Render(tr(searchHelp)))
+		return s.String()
+	}
+
+	if m.session != nil {
+		s.WriteString(WarningStyle.Render(tr(sessionPrompt)))
+		s.WriteString("\n")
+		s.WriteString(strings.Join(m.sessionLines(), "\n"))
+		s.WriteString("\n\n")
+		s.WriteString(HelpStyle.Render(tr("enter: resume • esc: discard • q: quit")))
+		return s.String()
+	}
+
 	s.WriteString(m.filepicker.View())
 	s.WriteString("\n\n")
-	s.WriteString(HelpStyle.Render("Space: select file • Enter: confirm selection • Delete: remove last file • q: quit"))
+	if m.selectionFocused {
+		s.WriteString(HelpStyle.Render(tr(selectionHelp)))
+		return s.String()
+	}
+	s.WriteString(m.shortHelp())
 
 	return s.String()
 }
 
-func (m Model) viewColumnSelection() string {
+// columnSelectionHeader renders everything above the column list.
+func (m Model) columnSelectionHeader() string {
 	var s strings.Builder
 	config := m.configs[m.currentFileIndex]
 
-	s.WriteString(TitleStyle.Render("⏰ Select Columns to Convert"))
+	s.WriteString(TitleStyle.Render(tr("⏰ Select Columns to Convert")))
 	s.WriteString("\n")
-	s.WriteString(SubtitleStyle.Render(fmt.Sprintf("File (%d/%d): %s", m.currentFileIndex+1, len(m.selectedFiles), filepath.Base(config.path))))
+	name := filepath.Base(config.path)
+	if archivePath, ok := m.archives[config.path]; ok {
+		name += tr(" (from %s)", filepath.Base(archivePath))
+	}
+	if m.opts.Profile != nil {
+		name += " • " + tr("profile %s", m.opts.Profile.Name)
+	}
+	if config.preset != nil {
+		name += " • " + tr("preset %s", config.preset.Name)
+	}
+	if m.status != "" {
+		name += " • " + m.status
+	}
+	s.WriteString(SubtitleStyle.Render(m.fit(tr("File (%d/%d): %s", m.currentFileIndex+1, len(m.selectedFiles), name))))
 	s.WriteString("\n\n")
 
 	if len(config.detectedCols) > 0 {
-		s.WriteString(SuccessStyle.Render(fmt.Sprintf("✓ Auto-detected %d decimal hour column(s)", len(config.detectedCols))))
+		s.WriteString(SuccessStyle.Render(tr("✓ Auto-detected %d decimal hour column(s)", len(config.detectedCols))))
 		s.WriteString("\n\n")
 	}
 
-	s.WriteString(m.viewport.View())
+	return s.String()
+}
+
+func (m Model) viewColumnSelection() string {
+	var s strings.Builder
+	config := m.configs[m.currentFileIndex]
+
+	s.WriteString(m.columnSelectionHeader())
+	if m.browsing {
+		s.WriteString(m.browser.View())
+	} else {
+		s.WriteString(m.viewport.View())
+	}
 	s.WriteString("\n\n")
 
 	// Show scroll position indicator
-	totalCols := len(config.selectableIndices)
+	totalCols := len(config.visibleIndices())
 	visibleStart := m.viewport.YOffset + 1
 	visibleEnd := m.viewport.YOffset + m.viewport.Height
 	if visibleEnd > totalCols {
@@ -547,17 +2143,194 @@ func (m Model) viewColumnSelection() string {
 	if visibleStart > totalCols {
 		visibleStart = totalCols
 	}
-	scrollInfo := SubtitleStyle.Render(fmt.Sprintf("Viewing %d-%d of %d columns", visibleStart, visibleEnd, totalCols))
+	scrollInfo := SubtitleStyle.Render(tr("Viewing %d-%d of %d columns", visibleStart, visibleEnd, totalCols))
+	if config.query != "" {
+		scrollInfo = SubtitleStyle.Render(tr("Viewing %d-%d of %d columns matching %q (of %d) • esc: clear filter", visibleStart, visibleEnd, totalCols, config.query, len(config.selectableIndices)))
+	}
+	if m.columnDetail {
+		scrollInfo = SubtitleStyle.Render(tr("Column settings apply to this column only"))
+	}
+	if m.reordering {
+		scrollInfo = SubtitleStyle.Render(tr("Reordering %d columns, listed in output order", totalCols))
+	}
+	if m.explaining {
+		scrollInfo = SubtitleStyle.Render(tr("Why columns were detected (first %d data rows sampled)", converter.RowDetectionLimit))
+	}
+	if m.browsing {
+		scrollInfo = SubtitleStyle.Render(m.browseInfo())
+	}
 	s.WriteString(scrollInfo)
 	s.WriteString("\n\n")
 
 	keepOriginalStatus := "[ ]"
-	if config.keepOriginal {
-		keepOriginalStatus = "[x]"
+	if config.options.KeepOriginal {
+		keepOriginalStatus = "[x] " + placementLabel(config.options.Placement)
+	}
+	s.WriteString(tr("Keep Original Columns: %s", keepOriginalStatus) + "\n")
+	s.WriteString(tr("Output Columns: %s", outputColumnsLabel(config.options)) + "\n")
+	columnOrder := tr("as in file")
+	if len(config.options.Order) > 0 {
+		columnOrder = tr("custom")
+	}
+	s.WriteString(tr("Column Order: %s", columnOrder) + "\n")
+
+	footerStatus := tr("passed through")
+	if config.options.DropFooter {
+		footerStatus = tr("dropped")
+	}
+	s.WriteString(tr("Footer Rows Skipped: %d (%s)", config.options.FooterRows, footerStatus) + "\n")
+	s.WriteString(tr("Row Filters: %d active", len(config.options.Filters)) + "\n")
+	s.WriteString(tr("Flag: %s", flagLabel(config.options.FlagAbove)) + "\n")
+	s.WriteString(tr("Blanks and Zeros: %s", blanksLabel(config.options.Blanks)) + "\n")
+	s.WriteString(tr("Durations: %s", durationsLabel(config.readData.Headers, config.options.Durations)) + "\n")
+	s.WriteString(tr("Totals: %s", totalsLabel(config.readData.Headers, config.options.Totals)) + "\n")
+	if config.fileData.Encoding != "" {
+		outputEncoding := config.options.OutputEncoding
+		if outputEncoding == "" {
+			outputEncoding = tr("same as input")
+		}
+		s.WriteString(tr("Encoding: %s → %s • Lines: %s • Quoting: %s", config.fileData.Encoding, outputEncoding, lineEndingsLabel(config.options), quotingLabel(config.options.Quoting)))
+		if config.fileData.PaddedRows > 0 {
+			s.WriteString(WarningStyle.Render(tr(" (%d short rows padded)", config.fileData.PaddedRows)))
+		}
+		s.WriteString("\n")
+	} else if isXLSX(config.path) {
+		destination := tr("converted copy")
+		switch {
+		case config.inPlace:
+			destination = tr("new %q sheet in this file", converter.ConvertedSheetName(config.fileData.Sheet))
+		case config.options.NewSheet:
+			destination = tr("new %q sheet in a converted copy", converter.ConvertedSheetName(config.fileData.Sheet))
+		}
+		if config.options.Table != "" {
+			destination += tr(", in table %q", config.options.Table)
+		}
+		if config.options.CommentOriginals && !config.options.KeepOriginal {
+			destination += tr(", original values in comments")
+		}
+		s.WriteString(tr("Output: %s", destination) + "\n")
 	}
-	s.WriteString(fmt.Sprintf("Keep Original Columns: %s\n", keepOriginalStatus))
 	s.WriteString("\n")
-	s.WriteString(HelpStyle.Render("↑/↓: navigate • space: toggle • o: keep original • a: select all detected • enter: confirm • q: quit"))
+
+	if m.editingColumnQuery {
+		s.WriteString(m.columnInput.View())
+		s.WriteString("\n")
+		s.WriteString(HelpStyle.Render(tr("type to filter • enter: keep filter • esc: clear filter")))
+		return s.String()
+	}
+
+	if m.editingFilter {
+		s.WriteString(m.filterInput.View())
+		s.WriteString("\n")
+		s.WriteString(HelpStyle.Render(tr("enter: apply (empty clears) • esc: cancel")))
+		return s.String()
+	}
+
+	if m.editingHeader {
+		s.WriteString(m.headerInput.View())
+		s.WriteString("\n")
+		s.WriteString(HelpStyle.Render(tr("enter: apply (empty resets) • esc: cancel")))
+		return s.String()
+	}
+
+	if m.editingSetting {
+		s.WriteString(m.settingInput.View())
+		s.WriteString("\n")
+		if m.settingErr != nil {
+			s.WriteString(WarningStyle.Render(m.settingErr.Error()))
+			s.WriteString("\n")
+		}
+		s.WriteString(HelpStyle.Render(settingPrompts[m.detailField].help))
+		return s.String()
+	}
+
+	if m.editingProfile {
+		s.WriteString(m.profileInput.View())
+		s.WriteString("\n")
+		s.WriteString(HelpStyle.Render(tr("enter: save • esc: cancel")))
+		return s.String()
+	}
+
+	if len(m.changed) > 0 {
+		s.WriteString(WarningStyle.Render(tr(changedHeading)))
+		s.WriteString("\n")
+		for _, name := range m.changedNames() {
+			s.WriteString(WarningStyle.Render("  • " + name))
+			s.WriteString("\n")
+		}
+		s.WriteString(HelpStyle.Render(tr(changedHelp, helpKeys(m.keys.Prompts.Reload), helpKeys(m.keys.Columns.Confirm))))
+		return s.String()
+	}
+
+	if len(m.problems) > 0 {
+		s.WriteString(ErrorStyle.Render(tr(preflightHeading)))
+		s.WriteString("\n")
+		for _, problem := range m.problems {
+			s.WriteString(ErrorStyle.Render("  • " + problem))
+			s.WriteString("\n")
+		}
+		s.WriteString(HelpStyle.Render(tr(preflightHelp)))
+		return s.String()
+	}
+
+	if len(m.conflicts) > 0 {
+		p := m.keys.Prompts
+		s.WriteString(WarningStyle.Render(m.viewOverwrite()))
+		s.WriteString("\n")
+		s.WriteString(HelpStyle.Render(tr(overwriteHelp, helpKeys(p.Overwrite), helpKeys(p.Rename), helpKeys(p.Skip), helpKeys(p.OverwriteAll, p.RenameAll, p.SkipAll))))
+		return s.String()
+	}
+
+	if m.recognized != nil {
+		for _, line := range strings.Split(presetPrompt(m.recognized), "\n") {
+			s.WriteString(SuccessStyle.Render(m.fit(line)))
+			s.WriteString("\n")
+		}
+		s.WriteString(HelpStyle.Render(tr(presetHelp)))
+		return s.String()
+	}
+
+	if m.quickConfirm {
+		for _, line := range strings.Split(m.quickPrompt(config), "\n") {
+			s.WriteString(SuccessStyle.Render(m.fit(line)))
+			s.WriteString("\n")
+		}
+		s.WriteString(HelpStyle.Render(tr(quickHelp, helpKeys(m.keys.Columns.Confirm), helpKeys(m.keys.Columns.Quit))))
+		return s.String()
+	}
+
+	if len(m.suspects) > 0 {
+		s.WriteString(WarningStyle.Render(tr(suspectWarning)))
+		s.WriteString("\n")
+		for _, suspect := range m.suspects {
+			s.WriteString(WarningStyle.Render("  • " + suspect))
+			s.WriteString("\n")
+		}
+		s.WriteString(HelpStyle.Render(tr("enter: convert anyway • esc: back to columns")))
+		return s.String()
+	}
+
+	if m.explaining {
+		s.WriteString(HelpStyle.Render(tr(explanationHelp)))
+		return s.String()
+	}
+
+	if m.browsing {
+		s.WriteString(HelpStyle.Render(tr(browseHelp)))
+		return s.String()
+	}
+
+	if m.reordering {
+		s.WriteString(HelpStyle.Render(tr(reorderHelp)))
+		return s.String()
+	}
+
+	if m.columnDetail {
+		s.WriteString(HelpStyle.Render(tr(columnDetailHelp)))
+		return s.String()
+	}
+
+	s.WriteString(m.shortHelp())
 
 	return s.String()
 }
@@ -569,11 +2342,30 @@ func (m *Model) updateViewportContent() {
 	config := m.configs[m.currentFileIndex]
 	var s strings.Builder
 
-	for i, colIdx := range config.selectableIndices {
+	if m.explaining {
+		m.viewport.SetContent(detectionExplanation(config))
+		return
+	}
+	if m.columnDetail {
+		if colIdx, ok := config.cursorColumn(); ok {
+			m.viewport.SetContent(columnDetail(config, colIdx, m.detailField))
+			return
+		}
+	}
+
+	visible := config.visibleIndices()
+	if len(visible) == 0 {
+		s.WriteString(UnselectedStyle.Render("  " + tr("No columns match %q", config.query)))
+	}
+	listWidth := m.statsListWidth()
+	for i, colIdx := range visible {
 		header := config.fileData.Headers[colIdx]
 		cursor := " "
 		if config.cursor == i {
 			cursor = ">"
+			if m.reordering {
+				cursor = "↕"
+			}
 		}
 
 		checked := " "
@@ -581,7 +2373,14 @@ func (m *Model) updateViewportContent() {
 			checked = "✓"
 		}
 
-		line := fmt.Sprintf("%s [%s] %s", cursor, checked, header)
+		line := fmt.Sprintf("%s [%s] %s", cursor, checked, header) + columnSettingsLabel(config.options.Columns[colIdx]) + columnFilterLabel(config.options.Filters, colIdx)
+		if config.options.OnlySelected && !config.selectedCols[colIdx] && keepsColumn(config.options, colIdx) {
+			line += tr(" (kept)")
+		}
+		// Name the new column when the original is kept next to it or it's renamed
+		if name := converter.ConvertedHeader(header, colIdx, config.options); config.selectedCols[colIdx] && name != header {
+			line += " → " + name
+		}
 
 		isDetected := false
 		for _, idx := range config.detectedCols {
@@ -591,12 +2390,23 @@ func (m *Model) updateViewportContent() {
 			}
 		}
 
+		if isDetected && config.cursor != i && !config.selectedCols[colIdx] {
+			line += tr(" (detected)")
+		}
+		if listWidth > 0 {
+			line = truncate(line, listWidth)
+			line += strings.Repeat(" ", listWidth-lipgloss.Width(line))
+		}
+
 		if config.cursor == i {
 			line = SelectedStyle.Render(line)
 		} else if config.selectedCols[colIdx] {
 			line = CheckedStyle.Render(line)
 		} else if isDetected {
-			line = UnselectedStyle.Render(line + " (detected)")
+			line = UnselectedStyle.Render(line)
+		}
+		if listWidth > 0 {
+			line += SubtitleStyle.UnsetMarginBottom().Render(" │ " + truncate(config.stats[colIdx].String(), statsWidth))
 		}
 
 		s.WriteString(line)
@@ -606,71 +2416,133 @@ func (m *Model) updateViewportContent() {
 	m.viewport.SetContent(s.String())
 }
 
+// detectionExplanation lists each column with the reason it was or wasn't
+// auto-detected and the sample values that were checked.
+func detectionExplanation(config fileConfig) string {
+	var s strings.Builder
+
+	for _, trace := range converter.TraceDetection(config.fileData) {
+		if strings.TrimSpace(trace.Header) == "" {
+			continue
+		}
+
+		if trace.Detected {
+			s.WriteString(CheckedStyle.Render("✓ " + trace.Header))
+		} else {
+			s.WriteString(UnselectedStyle.Render("✗ " + trace.Header))
+		}
+		s.WriteString(" - " + trace.Reason + "\n")
+
+		var samples []string
+		for _, sample := range trace.Samples {
+			switch {
+			case sample.Value == "":
+				samples = append(samples, tr("(empty)"))
+			case sample.OK:
+				samples = append(samples, sample.Value+" ✓")
+			default:
+				samples = append(samples, fmt.Sprintf("%q ✗", sample.Value))
+			}
+		}
+		if len(samples) > 0 {
+			s.WriteString(HelpStyle.UnsetMarginTop().Render("    " + tr("sampled: %s", strings.Join(samples, ", "))))
+			s.WriteString("\n")
+		}
+	}
+
+	return s.String()
+}
+
 func (m Model) viewLoading() string {
-	return BoxStyle.Render(TitleStyle.Render("Loading file..."))
+	return m.box(TitleStyle.Render(tr("Loading file...")))
 }
 
 func (m Model) viewProcessing() string {
 	var s strings.Builder
 
-	s.WriteString(TitleStyle.Render("⏰ Processing..."))
+	s.WriteString(TitleStyle.Render(tr("⏰ Processing...")))
 	s.WriteString("\n\n")
-	s.WriteString(fmt.Sprintf("Converting file %d of %d...", m.currentFileIndex+1, len(m.selectedFiles)))
+	s.WriteString(tr("Converting file %d of %d...", m.currentFileIndex+1, len(m.selectedFiles)))
 	s.WriteString("\n")
-	s.WriteString(filepath.Base(m.configs[m.currentFileIndex].path))
-	s.WriteString("\n\n")
+	if queue := m.viewQueue(); queue != "" {
+		s.WriteString("\n")
+		s.WriteString(queue)
+		s.WriteString("\n\n")
+		s.WriteString(tr("Batch: %d%% done", int(m.batchProgress()*100)))
+		s.WriteString("\n\n")
+	} else {
+		s.WriteString(filepath.Base(m.configs[m.currentFileIndex].path))
+		s.WriteString("\n\n")
+	}
 	s.WriteString(m.progress.View())
+	if phase := phaseLabel(m.lastProgress); phase != "" {
+		s.WriteString("\n")
+		s.WriteString(SubtitleStyle.Render(phase))
+	}
 
-	return BoxStyle.Render(s.String())
+	return m.box(s.String())
 }
 
 func (m Model) viewComplete() string {
 	var s strings.Builder
 
-	s.WriteString(TitleStyle.Render("✓ Conversion Complete!"))
+	s.WriteString(TitleStyle.Render(tr("✓ Conversion Complete!")))
 	s.WriteString("\n\n")
 
-	// Truncate paths if they're too long
-	maxPathLen := m.width - 20 // Leave room for padding and borders
-	if maxPathLen < 30 {
-		maxPathLen = 30
+	s.WriteString(m.resultsView.View())
+	s.WriteString("\n")
+	if m.resultsView.TotalLineCount() > m.resultsView.Height {
+		first := m.resultsView.YOffset + 1
+		last := min(m.resultsView.YOffset+m.resultsView.Height, m.resultsView.TotalLineCount())
+		s.WriteString(SubtitleStyle.UnsetMarginBottom().Render(tr("Lines %d-%d of %d", first, last, m.resultsView.TotalLineCount())))
+		s.WriteString("\n")
 	}
+	s.WriteString("\n")
 
-	for _, res := range m.results {
-		inputPath := res.InputFile
-		if len(inputPath) > maxPathLen {
-			inputPath = "..." + inputPath[len(inputPath)-maxPathLen+3:]
-		}
-
-		outputPath := res.OutputFile
-		if len(outputPath) > maxPathLen {
-			outputPath = "..." + outputPath[len(outputPath)-maxPathLen+3:]
-		}
-
-		s.WriteString(fmt.Sprintf("Input:    %s\n", inputPath))
-		s.WriteString(SuccessStyle.Render(fmt.Sprintf("Output:   %s", outputPath)))
-		s.WriteString("\n")
-		s.WriteString(fmt.Sprintf("Columns:  %s", strings.Join(res.ColumnsFound, ", ")))
+	saved := stats.EstimateMinutes(stats.CellsConverted(m.results))
+	s.WriteString(SuccessStyle.Render(tr("%s of manual formatting saved", stats.FormatMinutes(saved))))
+	s.WriteString("\n")
+	if m.totals != nil {
+		s.WriteString(SubtitleStyle.Render(tr("%s saved across %d runs", stats.FormatMinutes(m.totals.MinutesSaved), m.totals.Runs)))
 		s.WriteString("\n")
-		s.WriteString(fmt.Sprintf("Rows:     %d", res.RowsProcessed))
+	}
+
+	if m.status != "" {
+		s.WriteString(SubtitleStyle.Render(m.status))
 		s.WriteString("\n")
-		s.WriteString("---")
-		s.WriteString("\n\n")
 	}
 
-	s.WriteString(HelpStyle.Render("Press Enter to convert more files or q to quit"))
+	if m.exportingReport {
+		s.WriteString(HelpStyle.Render(tr(reportHelp)))
+	} else {
+		s.WriteString(m.shortHelp())
+	}
 
-	return BoxStyle.Render(s.String())
+	return m.box(s.String())
 }
 
 func (m Model) viewError() string {
 	var s strings.Builder
 
-	s.WriteString(ErrorStyle.Render("✗ Error"))
+	s.WriteString(ErrorStyle.Render(tr("✗ Error")))
 	s.WriteString("\n\n")
 	s.WriteString(m.err.Error())
 	s.WriteString("\n\n")
-	s.WriteString(HelpStyle.Render("Press any key to exit"))
 
-	return BoxStyle.Render(s.String())
+	// Show where a failed batch stopped
+	if m.batchFailed() {
+		if queue := m.viewQueue(); queue != "" {
+			s.WriteString(queue)
+			s.WriteString("\n\n")
+		}
+	}
+
+	if m.status != "" {
+		s.WriteString(SubtitleStyle.Render(m.status))
+		s.WriteString("\n")
+	}
+
+	s.WriteString(m.shortHelp())
+
+	return m.box(s.String())
 }
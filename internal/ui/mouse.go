@@ -0,0 +1,110 @@
+package ui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// updateMouse handles clicks and the scroll wheel. Clicking a file selects
+// it (or opens a folder), clicking a column toggles it, and the wheel
+// scrolls whichever list is on screen.
+func (m Model) updateMouse(msg tea.MouseMsg) (Model, tea.Cmd) {
+	if msg.Action != tea.MouseActionPress || m.showHelp || m.tooSmall() {
+		return m, nil
+	}
+
+	switch m.state {
+	case stateFilePicker:
+		// Prompts and the search results aren't clickable
+		if m.editingURL || m.editingPath || m.searching || m.session != nil {
+			return m, nil
+		}
+		switch msg.Button {
+		case tea.MouseButtonWheelUp, tea.MouseButtonWheelDown:
+			key := tea.KeyMsg{Type: tea.KeyDown}
+			if msg.Button == tea.MouseButtonWheelUp {
+				key = tea.KeyMsg{Type: tea.KeyUp}
+			}
+			for range wheelStep {
+				m.filepicker, _ = m.filepicker.Update(key)
+			}
+		case tea.MouseButtonLeft:
+			if i, ok := m.filepicker.FileAt(msg.Y - m.listTop()); ok {
+				m.selectionFocused = false
+				m.filepicker.Select(i)
+				return m.pickCurrentFile()
+			}
+		}
+
+	case stateComplete:
+		switch msg.Button {
+		case tea.MouseButtonWheelUp:
+			m.resultsView.ScrollUp(wheelStep)
+		case tea.MouseButtonWheelDown:
+			m.resultsView.ScrollDown(wheelStep)
+		}
+
+	case stateColumnSelection:
+		if m.editingFilter || m.editingProfile || m.editingColumnQuery || m.editingHeader || m.editingSetting || m.columnDetail || len(m.suspects) > 0 || m.recognized != nil || m.quickConfirm || len(m.conflicts) > 0 || len(m.problems) > 0 || len(m.changed) > 0 {
+			return m, nil
+		}
+		switch msg.Button {
+		case tea.MouseButtonWheelUp:
+			if m.browsing {
+				m.browser.MoveUp(wheelStep)
+			} else if m.explaining {
+				m.viewport.ScrollUp(wheelStep)
+			} else {
+				m.moveColumnCursor(-wheelStep)
+			}
+		case tea.MouseButtonWheelDown:
+			if m.browsing {
+				m.browser.MoveDown(wheelStep)
+			} else if m.explaining {
+				m.viewport.ScrollDown(wheelStep)
+			} else {
+				m.moveColumnCursor(wheelStep)
+			}
+		case tea.MouseButtonLeft:
+			line := msg.Y - m.listTop()
+			if m.explaining || m.browsing || line < 0 || line >= m.viewport.Height {
+				return m, nil
+			}
+			config := &m.configs[m.currentFileIndex]
+			i := m.viewport.YOffset + line
+			visible := config.visibleIndices()
+			if i >= len(visible) {
+				return m, nil
+			}
+			config.cursor = i
+			if m.reordering {
+				m.updateViewportContent()
+				return m, nil
+			}
+			if config.selectedCols[visible[i]] {
+				delete(config.selectedCols, visible[i])
+			} else {
+				config.selectedCols[visible[i]] = true
+			}
+			m.updateViewportContent()
+		}
+	}
+
+	return m, nil
+}
+
+// listTop returns the screen line the file or column list starts on.
+func (m Model) listTop() int {
+	// The compact views put the list right under their title line
+	if m.compact() {
+		return 1
+	}
+	switch m.state {
+	case stateFilePicker:
+		return strings.Count(m.filePickerHeader(), "\n")
+	case stateColumnSelection:
+		return strings.Count(m.columnSelectionHeader(), "\n")
+	}
+	return 0
+}
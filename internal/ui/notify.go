@@ -0,0 +1,64 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// notifyBatch alerts the user that the batch started at m.batchStart has
+// finished, as configured in the "notify" settings. Quick batches are left
+// alone, since the user is still watching.
+func (m Model) notifyBatch(title, body string) tea.Cmd {
+	n := m.settings.Notify
+	if !n.Desktop && !n.Bell {
+		return nil
+	}
+	if time.Since(m.batchStart) < time.Duration(n.MinSeconds)*time.Second {
+		return nil
+	}
+
+	return func() tea.Msg {
+		if n.Bell {
+			// The renderer owns stdout; the bell doesn't move the cursor either way
+			fmt.Fprint(os.Stderr, "\a")
+		}
+		if n.Desktop {
+			// Notifications are a convenience; there's nowhere useful to report failures
+			_ = notificationCommand(title, body).Run()
+		}
+		return nil
+	}
+}
+
+// notificationCommand returns the command that shows a desktop notification.
+func notificationCommand(title, body string) *exec.Cmd {
+	switch runtime.GOOS {
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s", appleScriptString(body), appleScriptString(title))
+		return exec.Command("osascript", "-e", script)
+	case "windows":
+		// A balloon tip from the notification area, which needs no extra modules
+		quote := func(s string) string { return "'" + strings.ReplaceAll(s, "'", "''") + "'" }
+		script := `Add-Type -AssemblyName System.Windows.Forms;` +
+			`$n = New-Object System.Windows.Forms.NotifyIcon;` +
+			`$n.Icon = [System.Drawing.SystemIcons]::Information;` +
+			`$n.Visible = $true;` +
+			`$n.ShowBalloonTip(5000, ` + quote(title) + `, ` + quote(body) + `, 'Info');` +
+			`Start-Sleep -Seconds 5; $n.Dispose()`
+		return exec.Command("powershell", "-NoProfile", "-Command", script)
+	default:
+		return exec.Command("notify-send", "--app-name=chronos", title, body)
+	}
+}
+
+// appleScriptString quotes s as an AppleScript string literal.
+func appleScriptString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
@@ -0,0 +1,41 @@
+package ui
+
+import (
+	"os/exec"
+	"path/filepath"
+	"runtime"
+)
+
+// openCommand returns the command that opens path in its default
+// application, or reveals it in the file manager when reveal is set.
+func openCommand(path string, reveal bool) *exec.Cmd {
+	switch runtime.GOOS {
+	case "darwin":
+		if reveal {
+			return exec.Command("open", "-R", path)
+		}
+		return exec.Command("open", path)
+	case "windows":
+		if reveal {
+			return exec.Command("explorer", "/select,", path)
+		}
+		return exec.Command("rundll32", "url.dll,FileProtocolHandler", path)
+	default:
+		// Linux file managers have no common way to select a file, so open its folder
+		if reveal {
+			path = filepath.Dir(path)
+		}
+		return exec.Command("xdg-open", path)
+	}
+}
+
+// openPath opens or reveals path without waiting for the application to exit.
+func openPath(path string, reveal bool) error {
+	cmd := openCommand(path, reveal)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	// Reap the process once it exits so it doesn't linger
+	go cmd.Wait()
+	return nil
+}
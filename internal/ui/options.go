@@ -0,0 +1,429 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/nconklindev/chronos/internal/converter"
+	"github.com/nconklindev/chronos/internal/types"
+
+	"github.com/dustin/go-humanize"
+)
+
+// updateColumn changes the settings of a column, dropping them once
+// they're back to the defaults. The map is copied rather than changed, since
+// options are shared with profiles and the remembered settings.
+func updateColumn(opts *types.ConversionOptions, column int, update func(s *types.ColumnSettings)) {
+	columns := make(map[int]types.ColumnSettings, len(opts.Columns)+1)
+	for idx, s := range opts.Columns {
+		columns[idx] = s
+	}
+
+	s := columns[column]
+	update(&s)
+	if s == (types.ColumnSettings{}) {
+		delete(columns, column)
+	} else {
+		columns[column] = s
+	}
+
+	opts.Columns = columns
+	if len(columns) == 0 {
+		opts.Columns = nil
+	}
+}
+
+// setConvertedHeader names the converted column, going back to the
+// default name when name is empty or matches it.
+func setConvertedHeader(opts *types.ConversionOptions, column int, header, name string) {
+	updateColumn(opts, column, func(s *types.ColumnSettings) { s.Header = "" })
+	if name == "" || name == converter.ConvertedHeader(header, column, *opts) {
+		return
+	}
+	updateColumn(opts, column, func(s *types.ColumnSettings) { s.Header = name })
+}
+
+// cycle returns the value after current in values, wrapping around. A
+// value that isn't listed is followed by the first.
+func cycle[T comparable](values []T, current T, delta int) T {
+	for i, v := range values {
+		if v == current {
+			return values[(i+delta+len(values))%len(values)]
+		}
+	}
+	return values[0]
+}
+
+// formatLabel names a format for the column settings.
+func formatLabel(f types.Format) string {
+	switch f {
+	case types.FormatHHMM:
+		return "HH:MM"
+	case types.FormatMinutes:
+		return "total minutes"
+	default:
+		return strings.ToUpper(string(f))
+	}
+}
+
+// patternOrFormatLabel names a column's format, or its custom pattern
+// when it has one.
+func patternOrFormatLabel(s types.ColumnSettings) string {
+	if s.Pattern != "" {
+		return "pattern " + s.Pattern
+	}
+	return formatLabel(s.Format)
+}
+
+// roundingLabel names a column's rounding for the column settings.
+func roundingLabel(s types.ColumnSettings) string {
+	unit := "minute"
+	if converter.ShowsSeconds(s) {
+		unit = "second"
+	}
+	switch s.Rounding {
+	case types.RoundDown:
+		return "down to the " + unit
+	case types.RoundUp:
+		return "up to the " + unit
+	case types.RoundQuarter:
+		return "quarter hour (7-minute rule)"
+	case types.RoundTenth:
+		return "tenth of an hour"
+	default:
+		return "nearest " + unit
+	}
+}
+
+// unitLabel names a unit for the column settings.
+func unitLabel(u types.Unit) string {
+	switch u {
+	case types.UnitHours:
+		return "hours"
+	case types.UnitDays:
+		return "days (24 hours)"
+	case types.UnitWeeks:
+		return "weeks (168 hours)"
+	case types.UnitExcelTime:
+		return "Excel time (fraction of a day)"
+	}
+	return string(u)
+}
+
+// decimalLabel names how a column's numbers are written for the column
+// settings.
+func decimalLabel(d types.Decimal) string {
+	switch d {
+	case types.DecimalPoint:
+		return "point (1,234.5)"
+	case types.DecimalComma:
+		return "comma (1.234,5)"
+	}
+	return "auto (1,234.5 or 1.234,5; 1,234 is left as is)"
+}
+
+// lenientLabel describes how strictly a column's values are read for the
+// column settings.
+func lenientLabel(lenient bool) string {
+	if lenient {
+		return "stripped (7.5 hrs, 7.5h and 750% read as 7.5)"
+	}
+	return "numbers only"
+}
+
+// expressionLabel describes a column's expression for the column settings.
+func expressionLabel(expr string) string {
+	if expr == "" {
+		return "not adjusted (enter to type an expression)"
+	}
+	return expr
+}
+
+// cycleTransformer returns the registered transformer delta steps from
+// current, with the duration transformer stored as the empty default.
+func cycleTransformer(current string, delta int) string {
+	if current == "" {
+		current = converter.DurationTransformer
+	}
+	next := cycle(converter.TransformerNames(), current, delta)
+	if next == converter.DurationTransformer {
+		return ""
+	}
+	return next
+}
+
+// transformerLabel names a transformer for the column settings.
+func transformerLabel(name string) string {
+	if name == "" || name == converter.DurationTransformer {
+		return "duration (format, rounding and unit apply)"
+	}
+	return strings.ReplaceAll(name, "_", " ")
+}
+
+// overtimeThresholds are the overtime thresholds the column settings cycle
+// through, in hours: off, common daily limits and the 40-hour week.
+var overtimeThresholds = []float64{0, 8, 10, 12, 40}
+
+// overtimeLabel names an overtime threshold for the column settings.
+func overtimeLabel(hours float64) string {
+	if hours <= 0 {
+		return "not split"
+	}
+	return "split after " + strconv.FormatFloat(hours, 'f', -1, 64) + "h"
+}
+
+// flagThresholds are the flag thresholds the column screen cycles through,
+// in hours: off, then longer than most shifts, a double shift and a day.
+var flagThresholds = []float64{0, 12, 16, 24}
+
+// flagLabel names a flag threshold for the column screen.
+func flagLabel(hours float64) string {
+	if hours <= 0 {
+		return "off"
+	}
+	return "values over " + strconv.FormatFloat(hours, 'f', -1, 64) + "h"
+}
+
+// columnSettingsLabel summarizes a column's settings that differ from the
+// defaults for the column list.
+func columnSettingsLabel(s types.ColumnSettings) string {
+	var parts []string
+	if s.Unit != types.UnitHours {
+		parts = append(parts, "from "+unitLabel(s.Unit))
+	}
+	if s.Decimal != types.DecimalAuto {
+		parts = append(parts, "decimal "+string(s.Decimal))
+	}
+	if s.Lenient {
+		parts = append(parts, "units stripped")
+	}
+	if s.Pattern != "" || s.Format != types.FormatHHMM {
+		parts = append(parts, patternOrFormatLabel(s))
+	}
+	if s.Rounding != types.RoundNearest {
+		parts = append(parts, "round "+string(s.Rounding))
+	}
+	if s.Expression != "" {
+		parts = append(parts, "adjusted: "+s.Expression)
+	}
+	if s.OvertimeAfter > 0 {
+		parts = append(parts, overtimeLabel(s.OvertimeAfter))
+	}
+	if !converter.IsDuration(s) {
+		parts = append(parts, transformerLabel(s.Transformer))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " [" + strings.Join(parts, ", ") + "]"
+}
+
+// phaseLabel describes a conversion's latest progress report under the
+// progress bar, or is empty before the first one.
+func phaseLabel(p converter.Progress) string {
+	switch p.Phase {
+	case converter.PhaseRead:
+		return tr("Reading %s of %s", byteCount(p.Done), byteCount(p.Total))
+	case converter.PhaseConvert:
+		return tr("Converting row %d of %d", p.Done, p.Total)
+	case converter.PhaseWrite:
+		// The total is only an estimate
+		return tr("Writing %s", byteCount(p.Done))
+	}
+	return ""
+}
+
+// byteCount renders a count of bytes from a progress report, such as
+// "1.2 MB", counting a negative one as none.
+func byteCount(n int) string {
+	return humanize.Bytes(uint64(max(n, 0)))
+}
+
+// findFilter returns the index of the filter on column with the given op, or -1.
+func findFilter(filters []types.RowFilter, column int, op types.FilterOp) int {
+	for i, filter := range filters {
+		if filter.Column == column && filter.Op == op {
+			return i
+		}
+	}
+	return -1
+}
+
+// toggleNonEmptyFilter adds or removes a "not empty" filter on a column.
+func toggleNonEmptyFilter(opts *types.ConversionOptions, column int) {
+	if i := findFilter(opts.Filters, column, types.FilterNonEmpty); i >= 0 {
+		opts.Filters = append(opts.Filters[:i], opts.Filters[i+1:]...)
+		return
+	}
+	opts.Filters = append(opts.Filters, types.RowFilter{Column: column, Op: types.FilterNonEmpty})
+}
+
+// setEqualsFilter sets the "equals" filter on a column, removing it when value is empty.
+func setEqualsFilter(opts *types.ConversionOptions, column int, value string) {
+	i := findFilter(opts.Filters, column, types.FilterEquals)
+
+	switch {
+	case value == "" && i >= 0:
+		opts.Filters = append(opts.Filters[:i], opts.Filters[i+1:]...)
+	case value == "":
+		return
+	case i >= 0:
+		opts.Filters[i].Value = value
+	default:
+		opts.Filters = append(opts.Filters, types.RowFilter{Column: column, Op: types.FilterEquals, Value: value})
+	}
+}
+
+// toggleKeepColumn adds or removes a column from those kept unconverted in slimmed output.
+func toggleKeepColumn(opts *types.ConversionOptions, column int) {
+	for i, kept := range opts.KeepColumns {
+		if kept == column {
+			opts.KeepColumns = append(opts.KeepColumns[:i], opts.KeepColumns[i+1:]...)
+			return
+		}
+	}
+	opts.KeepColumns = append(opts.KeepColumns, column)
+	sort.Ints(opts.KeepColumns)
+}
+
+// keepsColumn reports whether slimmed output keeps a column it doesn't convert.
+func keepsColumn(opts types.ConversionOptions, column int) bool {
+	for _, kept := range opts.KeepColumns {
+		if kept == column {
+			return true
+		}
+	}
+	return false
+}
+
+// outputColumnsLabel describes which columns are written for the options block.
+func outputColumnsLabel(opts types.ConversionOptions) string {
+	if !opts.OnlySelected {
+		return "all"
+	}
+	if len(opts.KeepColumns) == 0 {
+		return "selected only"
+	}
+	return fmt.Sprintf("selected + %d kept", len(opts.KeepColumns))
+}
+
+// blanksLabel describes what's written for empty cells and zeros for the
+// options block.
+func blanksLabel(p types.BlankPolicy) string {
+	switch p {
+	case types.BlankAsZero:
+		return "empty cells written as 00:00"
+	case types.ZeroAsBlank:
+		return "zeros left blank"
+	default:
+		return "empty cells left blank, zeros written"
+	}
+}
+
+// lineEndingsLabel describes how CSV output lines end for the options block.
+func lineEndingsLabel(opts types.ConversionOptions) string {
+	label := "as input"
+	switch opts.LineEnding {
+	case types.LineEndingLF:
+		label = "LF"
+	case types.LineEndingCRLF:
+		label = "CRLF"
+	}
+	switch opts.FinalNewline {
+	case types.FinalNewlineAlways:
+		label += ", final newline"
+	case types.FinalNewlineNever:
+		label += ", no final newline"
+	}
+	return label
+}
+
+// quotingLabel describes which CSV output fields are quoted for the options
+// block.
+func quotingLabel(q types.Quoting) string {
+	if q == types.QuotingMinimal {
+		return "minimal"
+	}
+	return string(q)
+}
+
+// durationsLabel describes the computed duration columns for the options block.
+func durationsLabel(headers []string, pairs []types.DurationPair) string {
+	if len(pairs) == 0 {
+		return "off"
+	}
+	var parts []string
+	for _, pair := range pairs {
+		parts = append(parts, fmt.Sprintf("%s → %s as %q", headers[pair.Start], headers[pair.End], pair.Header))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// totalsLabel describes the totals added to the output for the options block.
+func totalsLabel(headers []string, t *types.Totals) string {
+	if t == nil {
+		return "off"
+	}
+	period := string(t.Period)
+	if t.Period == types.PeriodWeek {
+		period = "week"
+	}
+	header := func(idx int) string {
+		if idx < len(headers) {
+			return headers[idx]
+		}
+		return fmt.Sprintf("column %d", idx+1)
+	}
+	return fmt.Sprintf("per %s by %s (%s)", period, header(t.Employee), header(t.Date))
+}
+
+// placementLabel describes where converted copies go for the options block.
+func placementLabel(p types.Placement) string {
+	switch p {
+	case types.PlacementEnd:
+		return "(copies at end of row)"
+	case types.PlacementGrouped:
+		return "(grouped at end of row)"
+	default:
+		return "(copies next to originals)"
+	}
+}
+
+// nextOutputEncoding cycles through "same as input" followed by each supported output encoding.
+func nextOutputEncoding(current string) string {
+	if current == "" {
+		return converter.OutputEncodings[0]
+	}
+	for i, enc := range converter.OutputEncodings {
+		if enc == current && i+1 < len(converter.OutputEncodings) {
+			return converter.OutputEncodings[i+1]
+		}
+	}
+	return ""
+}
+
+// columnFilterLabel describes the filters on a column for the column list.
+func columnFilterLabel(filters []types.RowFilter, column int) string {
+	label := ""
+	for _, filter := range filters {
+		if filter.Column != column {
+			continue
+		}
+		if label != "" {
+			label += ", "
+		}
+		switch filter.Op {
+		case types.FilterNonEmpty:
+			label += "not empty"
+		case types.FilterEquals:
+			label += fmt.Sprintf("= %q", filter.Value)
+		}
+	}
+
+	if label == "" {
+		return ""
+	}
+	return " [filter: " + label + "]"
+}
@@ -0,0 +1,164 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nconklindev/chronos/internal/config"
+	"github.com/nconklindev/chronos/internal/converter"
+	"github.com/nconklindev/chronos/internal/types"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// overwriteHelp lists what can be done with an output that already exists,
+// given the keys of promptKeys that do it.
+const overwriteHelp = "%s: overwrite • %s: rename • %s: skip • %s: same for the rest • esc: back to columns"
+
+// outputFor returns where a file's converted copy is written: the file
+// itself when it's converted in place, the name chosen instead of
+// overwriting an existing copy, or the default next to it.
+func (m Model) outputFor(cfg fileConfig) string {
+	switch {
+	case cfg.inPlace:
+		return cfg.path
+	case cfg.output != "":
+		return cfg.output
+	case cfg.preset != nil:
+		return m.presetOutput(cfg)
+	}
+	return m.outputPath(cfg.path, cfg.options)
+}
+
+// existingOutputs returns the queue positions of the files whose converted
+// copies, or the totals written next to them, already exist. Files
+// converted in place are meant to change.
+func (m Model) existingOutputs() []int {
+	var existing []int
+	for i, cfg := range m.configs {
+		if cfg.inPlace {
+			continue
+		}
+		if outputExists(m.outputFor(cfg), cfg.options) {
+			existing = append(existing, i)
+		}
+	}
+	return existing
+}
+
+// outputExists reports whether converting with opts to output would
+// overwrite a file, the output itself or its totals.
+func outputExists(output string, opts types.ConversionOptions) bool {
+	for _, path := range []string{output, converter.TotalsOutput(output, opts)} {
+		if _, err := os.Stat(path); path != "" && err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// freePath returns the first of "name (2).ext", "name (3).ext" and so on
+// that doesn't exist, nor its totals, for an output that shouldn't be
+// overwritten.
+func freePath(path string, opts types.ConversionOptions) string {
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(path, ext)
+	for n := 2; ; n++ {
+		candidate := fmt.Sprintf("%s (%d)%s", base, n, ext)
+		if !outputExists(candidate, opts) {
+			return candidate
+		}
+	}
+}
+
+// updateOverwrite handles the keys of the prompt shown for each output that
+// already exists. The choice applies to the first file waiting, or with
+// its "all" key, a capital letter by default, to every file still waiting.
+func (m Model) updateOverwrite(msg tea.KeyMsg) (Model, tea.Cmd) {
+	if msg.String() == "esc" {
+		return m.backToColumns(), nil
+	}
+
+	p := m.keys.Prompts
+	count := 1
+	if key.Matches(msg, p.OverwriteAll, p.RenameAll, p.SkipAll) {
+		count = len(m.conflicts)
+	} else if !key.Matches(msg, p.Overwrite, p.Rename, p.Skip) {
+		return m, nil
+	}
+
+	for _, i := range m.conflicts[:count] {
+		switch {
+		case key.Matches(msg, p.Rename, p.RenameAll):
+			m.configs[i].output = freePath(m.outputFor(m.configs[i]), m.configs[i].options)
+		case key.Matches(msg, p.Skip, p.SkipAll):
+			m.configs[i].skip = true
+		}
+	}
+	m.conflicts = m.conflicts[count:]
+	if len(m.conflicts) > 0 {
+		return m, nil
+	}
+	return m.startBatch()
+}
+
+// backToColumns leaves the prompts shown before a batch starts. Nothing
+// chosen in them sticks, since the columns may change.
+func (m Model) backToColumns() Model {
+	for i := range m.configs {
+		m.configs[i].output = ""
+		m.configs[i].skip = false
+	}
+	m.conflicts = nil
+	m.problems = nil
+	m.changed = nil
+	return m
+}
+
+// startBatch converts every configured file that wasn't skipped, once
+// they pass the preflight checks and are checked for changes since they
+// were read.
+func (m Model) startBatch() (Model, tea.Cmd) {
+	var files []string
+	var configs []fileConfig
+	for i, cfg := range m.configs {
+		if !cfg.skip {
+			files = append(files, m.selectedFiles[i])
+			configs = append(configs, cfg)
+		}
+	}
+	if len(configs) == 0 {
+		for i := range m.configs {
+			m.configs[i].skip = false
+		}
+		m.status = tr("Every file was skipped")
+		return m, nil
+	}
+	if m.problems = m.preflight(configs); len(m.problems) > 0 {
+		return m, nil
+	}
+	if m.changed = m.changedFiles(); len(m.changed) > 0 {
+		return m, nil
+	}
+
+	// There's nothing left to resume
+	_ = config.ClearSession()
+	m.selectedFiles = files
+	m.configs = configs
+	m.state = stateProcessing
+	m.currentFileIndex = 0 // Reset index to start processing from the first file.
+	return m.convertNextFile()
+}
+
+// viewOverwrite asks what to do with the first output that already exists.
+func (m Model) viewOverwrite() string {
+	cfg := m.configs[m.conflicts[0]]
+	prompt := tr("%s already exists.", filepath.Base(m.outputFor(cfg)))
+	if len(m.conflicts) > 1 {
+		prompt += tr(" (%d more after this one)", len(m.conflicts)-1)
+	}
+	return prompt + "\n" + tr("Overwrite it, write to %s instead, or skip %s?", filepath.Base(freePath(m.outputFor(cfg), cfg.options)), filepath.Base(cfg.path))
+}
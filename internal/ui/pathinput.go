@@ -0,0 +1,71 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nconklindev/chronos/internal/paths"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// expandPath resolves a typed path against dir, expanding a leading ~ to
+// the home directory. Network shares and long paths are normalized, so a
+// pasted //server/share or \\?\C:\ path works as typed.
+func expandPath(path, dir string) string {
+	path = paths.Normalize(path)
+	if path == "~" || strings.HasPrefix(path, "~/") || strings.HasPrefix(path, `~\`) {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, path[1:])
+		}
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	return path
+}
+
+// applyTypedPath selects the files matching a typed path or glob, or opens
+// it in the file picker if it's a folder.
+func (m Model) applyTypedPath(typed string) (Model, tea.Cmd) {
+	typed = strings.TrimSpace(typed)
+	if typed == "" {
+		return m, nil
+	}
+	path := expandPath(typed, m.filepicker.CurrentDirectory)
+
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		m.status = ""
+		return m.openDirectory(path)
+	}
+
+	matches, err := filepath.Glob(path)
+	if err != nil {
+		m.status = tr("Invalid pattern: %v", err)
+		return m, nil
+	}
+
+	var files []string
+	for _, match := range matches {
+		if info, err := os.Stat(match); err == nil && !info.IsDir() && allowedFile(m.filepicker, filepath.Base(match)) {
+			files = append(files, match)
+		}
+	}
+	if len(files) == 0 {
+		m.status = tr("No CSV, XLSX or archive files match %s", typed)
+		return m, nil
+	}
+
+	before := len(m.selectedFiles)
+	for _, file := range files {
+		m.selectFile(file)
+	}
+	added := len(m.selectedFiles) - before
+
+	m.status = tr("Added %d file(s)", added)
+	if added < len(files) {
+		m.status = tr("Added %d of %d matching files; up to 3 can be selected", added, len(files))
+	}
+	return m, nil
+}
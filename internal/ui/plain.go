@@ -0,0 +1,265 @@
+package ui
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/nconklindev/chronos/internal/archive"
+	"github.com/nconklindev/chronos/internal/config"
+	"github.com/nconklindev/chronos/internal/converter"
+	"github.com/nconklindev/chronos/internal/i18n"
+)
+
+// plainSession asks its questions a line at a time, for RunPlain.
+type plainSession struct {
+	in  *bufio.Scanner
+	out io.Writer
+	// m supplies the remembered options and output names the full-screen
+	// interface uses, so both convert a file the same way.
+	m Model
+}
+
+// RunPlain converts files by asking one question at a time, for screen
+// readers and for output that isn't a terminal. Nothing is drawn: each
+// line is plain text without color, borders or cursor movement, and
+// every question says what answers it takes. Answers are read a line at
+// a time from in until an empty file name, q or the end of the input.
+func RunPlain(in io.Reader, out io.Writer, opts Options) error {
+	// Unreadable settings fall back to the defaults; the command reports them
+	settings, _ := config.LoadSettings()
+	SetLanguage(i18n.Detect(settings.Language))
+
+	s := &plainSession{
+		in:  bufio.NewScanner(in),
+		out: out,
+		m:   Model{opts: opts, settings: settings, downloads: make(map[string]string)},
+	}
+	// Downloads are converted into the working directory, as with chronos convert
+	s.m.filepicker.CurrentDirectory, _ = os.Getwd()
+	defer func() { s.m.Cleanup() }()
+
+	s.say(tr("Chronos %s converts decimal hours to HH:MM in CSV, XLSX and JSON files.", opts.Version))
+	s.say(tr("Type each answer and press Enter. An empty answer takes the default in brackets, and q quits."))
+
+	next := opts.URL
+	if opts.File != "" {
+		next = opts.File
+	}
+	for {
+		path := next
+		next = ""
+		if path == "" {
+			answer, ok := s.ask(tr("File to convert, as a path or a link. Leave it empty to quit:"))
+			if !ok || answer == "" {
+				return nil
+			}
+			path = answer
+		}
+
+		if err := s.convert(path); err != nil {
+			s.say(tr("Error: %s", err))
+		}
+
+		again, ok := s.confirm(tr("Convert another file?"), false)
+		if !ok || !again {
+			return nil
+		}
+	}
+}
+
+// say writes a line.
+func (s *plainSession) say(line string) {
+	fmt.Fprintln(s.out, line)
+}
+
+// ask writes a question on its own line and reads the answer. It returns
+// false when the input ends or the answer is q.
+func (s *plainSession) ask(question string) (string, bool) {
+	s.say(question)
+	if !s.in.Scan() {
+		return "", false
+	}
+	answer := strings.TrimSpace(s.in.Text())
+	if strings.EqualFold(answer, "q") {
+		return "", false
+	}
+	return answer, true
+}
+
+// confirm asks a yes or no question, taking def for an empty answer, until
+// it gets one.
+func (s *plainSession) confirm(question string, def bool) (yes, ok bool) {
+	choices := tr("Type y for yes or n for no [y]:")
+	if !def {
+		choices = tr("Type y for yes or n for no [n]:")
+	}
+	for {
+		answer, ok := s.ask(question + " " + choices)
+		if !ok {
+			return false, false
+		}
+		switch strings.ToLower(answer) {
+		case "":
+			return def, true
+		case "y", "yes", strings.ToLower(tr("y")), strings.ToLower(tr("yes")):
+			return true, true
+		case "n", "no", strings.ToLower(tr("n")), strings.ToLower(tr("no")):
+			return false, true
+		}
+		s.say(tr("Please answer y or n."))
+	}
+}
+
+// convert reads a file, asks which columns to convert and where, and
+// converts it.
+func (s *plainSession) convert(path string) error {
+	// Files dragged onto a terminal arrive quoted
+	path = strings.Trim(path, `"'`)
+	if converter.IsURL(path) {
+		s.say(tr("Downloading %s...", path))
+		dir, err := os.MkdirTemp("", "chronos-download-")
+		if err != nil {
+			return err
+		}
+		s.m.tempDirs = append(s.m.tempDirs, dir)
+		downloaded, err := converter.HTTPDownload{URL: path, Dir: dir}.Fetch()
+		if err != nil {
+			return err
+		}
+		s.m.downloads[downloaded] = path
+		path = downloaded
+	}
+	if archive.IsArchive(path) {
+		return errors.New(tr("%s is an archive; convert it with \"chronos convert\" instead", filepath.Base(path)))
+	}
+
+	s.say(tr("Reading %s...", filepath.Base(path)))
+	data, err := converter.ReadFileData(path)
+	if err != nil {
+		return err
+	}
+	cfg := s.m.loadedConfig(path, data)
+	if p := s.m.opts.Profile; p != nil {
+		cfg.setDurations(p.Options.Durations)
+		if err := p.Validate(cfg.fileData); err != nil {
+			return err
+		}
+		cfg.selectedCols, cfg.options = p.Apply(cfg.fileData)
+	}
+
+	headers := cfg.fileData.Headers
+	s.say(tr("%s has %d columns and %d rows, %d of them footer rows.", filepath.Base(path), len(headers), len(cfg.fileData.Rows), cfg.options.FooterRows))
+	var detected []string
+	for _, idx := range cfg.selectableIndices {
+		line := tr("Column %d: %s", idx+1, headers[idx])
+		if cfg.selectedCols[idx] {
+			line += tr(", detected as decimal hours")
+			detected = append(detected, strconv.Itoa(idx+1))
+		}
+		s.say(line)
+	}
+
+	columns, ok := s.askColumns(cfg, strings.Join(detected, ", "))
+	if !ok {
+		return nil
+	}
+	if len(columns) == 0 {
+		s.say(tr("No columns chosen, so nothing was converted."))
+		return nil
+	}
+	cfg.selectedCols = make(map[int]bool)
+	names := make([]string, len(columns))
+	for i, idx := range columns {
+		cfg.selectedCols[idx] = true
+		names[i] = headers[idx]
+	}
+
+	output := s.m.outputFor(cfg)
+	yes, ok := s.confirm(tr("Convert %s and save the result as %s?", strings.Join(names, ", "), output), true)
+	if !ok || !yes {
+		s.say(tr("Skipped %s.", filepath.Base(path)))
+		return nil
+	}
+	if _, err := os.Stat(output); err == nil {
+		yes, ok := s.confirm(tr("%s already exists. Replace it?", output), false)
+		if !ok || !yes {
+			s.say(tr("Skipped %s.", filepath.Base(path)))
+			return nil
+		}
+	}
+
+	s.say(tr("Converting..."))
+	result, err := converter.Convert(path, converter.LocalFile(output), columns, cfg.options, nil)
+	if err != nil {
+		return err
+	}
+	s.say(tr("Converted %d values in %d rows.", result.CellsChanged, result.RowsProcessed))
+	for _, warning := range result.Warnings {
+		s.say(tr("Warning: %s", warning))
+	}
+	s.say(tr("Saved as %s", result.OutputFile))
+	return nil
+}
+
+// askColumns asks which columns to convert, by number or header, until the
+// answer names only columns of the file. An empty answer takes def, the
+// detected columns.
+func (s *plainSession) askColumns(cfg fileConfig, def string) ([]int, bool) {
+	question := tr("Columns to convert, as numbers or names separated by commas [%s]:", def)
+	if def == "" {
+		question = tr("No columns were detected as decimal hours. Columns to convert, as numbers or names separated by commas:")
+	}
+	for {
+		answer, ok := s.ask(question)
+		if !ok {
+			return nil, false
+		}
+		if answer == "" {
+			answer = def
+		}
+		columns, err := plainColumns(answer, cfg.fileData.Headers)
+		if err == nil {
+			return columns, true
+		}
+		s.say(tr("Error: %s", err))
+	}
+}
+
+// plainColumns reads a list of columns numbered from 1, as they're listed,
+// or named by header, into sorted column indices.
+func plainColumns(answer string, headers []string) ([]int, error) {
+	seen := make(map[int]bool)
+	var columns []int
+	for _, field := range strings.Split(answer, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		var idx int
+		if n, err := strconv.Atoi(field); err == nil {
+			if n < 1 || n > len(headers) {
+				return nil, errors.New(tr("there is no column %d", n))
+			}
+			idx = n - 1
+		} else {
+			found, err := converter.ResolveColumns(field, headers)
+			if err != nil {
+				return nil, errors.New(tr("there is no column named %q", field))
+			}
+			idx = found[0]
+		}
+		if !seen[idx] {
+			seen[idx] = true
+			columns = append(columns, idx)
+		}
+	}
+	sort.Ints(columns)
+	return columns, nil
+}
@@ -0,0 +1,134 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"unicode/utf8"
+
+	"github.com/dustin/go-humanize"
+)
+
+// preflightHeading heads the problems found before a batch starts.
+const preflightHeading = "These would stop the batch part way through, so it hasn't started:"
+
+// preflightHelp lists what can be done about the problems found.
+const preflightHelp = "enter: check again • esc: back to columns"
+
+// preflight checks that every file of a batch can be read and its output
+// written, and returns every problem found, so they can be fixed together
+// rather than one file at a time as the batch fails on them.
+func (m Model) preflight(configs []fileConfig) []string {
+	var problems []string
+	var dirs []string
+	needed := make(map[string]uint64)
+	for _, cfg := range configs {
+		name := filepath.Base(cfg.path)
+		if err := checkReadable(cfg.path); err != nil {
+			problems = append(problems, fmt.Sprintf("%s can't be read: %v", name, err))
+		}
+
+		output := m.outputFor(cfg)
+		dir := filepath.Dir(output)
+		if _, checked := needed[dir]; !checked {
+			dirs = append(dirs, dir)
+			if err := checkWritableDir(dir); err != nil {
+				problems = append(problems, fmt.Sprintf("%s can't be written to: %v", dir, err))
+			}
+		}
+		needed[dir] += outputEstimate(cfg.path)
+		if err := checkWritableFile(output); err != nil {
+			problems = append(problems, fmt.Sprintf("%s can't be replaced: %v", filepath.Base(output), err))
+		}
+		if err := checkPathLength(output); err != nil {
+			problems = append(problems, fmt.Sprintf("%s can't be opened by every program: %v", filepath.Base(output), err))
+		}
+	}
+
+	for _, dir := range dirs {
+		if err := checkFreeSpace(dir, needed[dir]); err != nil {
+			problems = append(problems, fmt.Sprintf("%s doesn't have room for the converted files: %v", dir, err))
+		}
+	}
+	return problems
+}
+
+// outputEstimate guesses how many bytes converting a file writes. Outputs
+// come out about the size of their inputs, and a file converted in place
+// is written next to itself before it's replaced.
+func outputEstimate(path string) uint64 {
+	info, err := os.Stat(path)
+	if err != nil || info.Size() < 0 {
+		return 0
+	}
+	return uint64(info.Size())
+}
+
+// checkFreeSpace checks the volume holding dir has room for needed bytes.
+// Space that can't be read isn't counted as a problem, since the write
+// checks would already have found an unusable folder.
+func checkFreeSpace(dir string, needed uint64) error {
+	free, err := freeSpace(dir)
+	if err != nil || free >= needed {
+		return nil
+	}
+	return fmt.Errorf("%s free, about %s needed; free up space or choose another folder", humanize.Bytes(free), humanize.Bytes(needed))
+}
+
+// checkPathLength checks an output's path is short enough for the programs
+// that will open it, which on Windows is MAX_PATH.
+func checkPathLength(path string) error {
+	if maxPathLength == 0 {
+		return nil
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	if n := utf8.RuneCountInString(path); n > maxPathLength {
+		return fmt.Errorf("its path is %d characters, over the %d Windows allows; move the file to a shorter folder or give it a shorter name", n, maxPathLength)
+	}
+	return nil
+}
+
+// checkReadable opens path for reading.
+func checkReadable(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return pathErr(err)
+	}
+	return f.Close()
+}
+
+// checkWritableDir creates and removes a file in dir.
+func checkWritableDir(dir string) error {
+	f, err := os.CreateTemp(dir, ".chronos-preflight-*")
+	if err != nil {
+		return pathErr(err)
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
+
+// checkWritableFile opens path for writing without changing it, if it
+// exists. A missing file is written to its directory, checked separately.
+func checkWritableFile(path string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return pathErr(err)
+	}
+	return f.Close()
+}
+
+// pathErr drops the operation and path from a file error, which the
+// problem already names.
+func pathErr(err error) error {
+	var pe *os.PathError
+	if errors.As(err, &pe) {
+		return pe.Err
+	}
+	return err
+}
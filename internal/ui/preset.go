@@ -0,0 +1,33 @@
+package ui
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/nconklindev/chronos/internal/converter"
+	"github.com/nconklindev/chronos/internal/profile"
+)
+
+// presetHelp lists what can be done with a recognized vendor export.
+const presetHelp = "enter: apply preset and continue • esc: choose columns"
+
+// presetPrompt describes the vendor preset recognized from a file's
+// headers, a sentence a line.
+func presetPrompt(p *profile.Preset) string {
+	return tr("This looks like an export from %s.", p.Vendor) + "\n" + tr("The %s preset %s.", p.Name, p.Describe())
+}
+
+// applyPreset selects a preset's columns and drops footer rows as it does,
+// keeping the other remembered options.
+func (c *fileConfig) applyPreset(p *profile.Preset) {
+	selected, opts := p.Apply(c.fileData)
+	c.selectedCols = selected
+	c.options.FooterRows = opts.FooterRows
+	c.options.DropFooter = opts.DropFooter
+	c.preset = p
+}
+
+// presetOutput is where a file converted with a preset is written.
+func (m Model) presetOutput(cfg fileConfig) string {
+	return filepath.Join(m.outputDir(cfg.path), cfg.preset.OutputName(cfg.path)+strings.ToLower(converter.OutputExt(cfg.path, cfg.options)))
+}
@@ -0,0 +1,91 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// fileStatus is where a file of the batch being converted has got to.
+type fileStatus int
+
+const (
+	filePending fileStatus = iota
+	fileRunning
+	fileDone
+	fileFailed
+)
+
+// queueLines is the most files the queue lists at once. Longer queues
+// show the files around the one being converted.
+const queueLines = 8
+
+// render shows a file of the queue with its status icon.
+func (s fileStatus) render(name string) string {
+	switch s {
+	case fileRunning:
+		return SelectedStyle.Render("▶ " + name)
+	case fileDone:
+		return CheckedStyle.Render("✓ " + name)
+	case fileFailed:
+		return ErrorStyle.Render("✗ " + name)
+	default:
+		return UnselectedStyle.Render("· " + name)
+	}
+}
+
+// batchProgress is how much of the batch is converted, from 0 to 1, with
+// each file counting the same and the one being converted counting as far
+// as it's got.
+func (m Model) batchProgress() float64 {
+	if len(m.fileStatuses) == 0 {
+		return 0
+	}
+	done := 0.0
+	for _, status := range m.fileStatuses {
+		switch status {
+		case fileDone, fileFailed:
+			done++
+		case fileRunning:
+			done += m.lastProgress.Overall()
+		}
+	}
+	return done / float64(len(m.fileStatuses))
+}
+
+// batchFailed reports whether the error shown is a file of the batch
+// failing to convert, rather than something before converting started.
+func (m Model) batchFailed() bool {
+	for _, status := range m.fileStatuses {
+		if status == fileFailed {
+			return true
+		}
+	}
+	return false
+}
+
+// viewQueue lists the files of the batch with their status, or is empty
+// for a single file.
+func (m Model) viewQueue() string {
+	if len(m.fileStatuses) < 2 {
+		return ""
+	}
+
+	start := 0
+	if len(m.fileStatuses) > queueLines {
+		start = min(max(m.currentFileIndex-queueLines/2, 0), len(m.fileStatuses)-queueLines)
+	}
+	end := min(start+queueLines, len(m.fileStatuses))
+
+	var lines []string
+	if start > 0 {
+		lines = append(lines, SubtitleStyle.UnsetMarginBottom().Render(fmt.Sprintf("  … %d more", start)))
+	}
+	for i := start; i < end; i++ {
+		lines = append(lines, m.fileStatuses[i].render(filepath.Base(m.configs[i].path)))
+	}
+	if end < len(m.fileStatuses) {
+		lines = append(lines, SubtitleStyle.UnsetMarginBottom().Render(fmt.Sprintf("  … %d more", len(m.fileStatuses)-end)))
+	}
+	return strings.Join(lines, "\n")
+}
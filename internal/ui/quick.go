@@ -0,0 +1,23 @@
+package ui
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// quickHelp lists what can be done with a file opened on its own, given
+// the keys that convert it and quit.
+const quickHelp = "%s: convert • esc: choose columns • %s: quit"
+
+// quickPrompt describes converting a file opened on its own, as with "Open
+// with", using the columns chosen for it, a sentence a line.
+func (m Model) quickPrompt(cfg fileConfig) string {
+	var names []string
+	for _, idx := range cfg.orderedIndices() {
+		if cfg.selectedCols[idx] {
+			names = append(names, cfg.fileData.Headers[idx])
+		}
+	}
+	return tr("Convert %s in %s?", strings.Join(names, ", "), filepath.Base(cfg.path)) + "\n" +
+		tr("The converted copy will be saved as %s.", m.outputFor(cfg))
+}
@@ -0,0 +1,150 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nconklindev/chronos/internal/converter"
+	"github.com/nconklindev/chronos/internal/types"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// changedHeading heads the files that changed after they were loaded.
+const changedHeading = "These files changed after their columns were chosen, such as by a new export:"
+
+// changedHelp lists what can be done about files that changed, given the
+// keys that reload them and convert them anyway.
+const changedHelp = "%s: reload and detect columns again • %s: convert anyway • esc: back to columns"
+
+// fileStamp is the size and modification time of a file when it was read.
+type fileStamp struct {
+	size    int64
+	modTime time.Time
+}
+
+// stampFile returns the stamp of the file at path, or the zero stamp if it
+// can't be read, which is never compared.
+func stampFile(path string) fileStamp {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{}
+	}
+	return fileStamp{size: info.Size(), modTime: info.ModTime()}
+}
+
+// changedFiles returns the queue positions of the files about to be
+// converted whose size or modification time changed since they were read.
+// Files that are gone are left to the preflight checks.
+func (m Model) changedFiles() []int {
+	var changed []int
+	for i, cfg := range m.configs {
+		if cfg.skip || cfg.loaded == (fileStamp{}) {
+			continue
+		}
+		if now := stampFile(cfg.path); now != (fileStamp{}) && now != cfg.loaded {
+			changed = append(changed, i)
+		}
+	}
+	return changed
+}
+
+type filesReloadedMsg struct {
+	configs map[int]fileConfig
+	err     error
+}
+
+// reloadFiles reads the changed files again.
+func (m Model) reloadFiles() tea.Cmd {
+	configs := make(map[int]fileConfig, len(m.changed))
+	for _, i := range m.changed {
+		configs[i] = m.configs[i]
+	}
+	return func() tea.Msg {
+		for i, cfg := range configs {
+			reloaded, err := reloadConfig(cfg)
+			if err != nil {
+				return filesReloadedMsg{err: err}
+			}
+			configs[i] = reloaded
+		}
+		return filesReloadedMsg{configs: configs}
+	}
+}
+
+// reloadConfig reads a file's configuration again from disk. A file with
+// the same headers keeps its columns and options. Otherwise its columns are
+// detected again, chosen columns stay chosen where their header is still
+// found, and the options naming columns by position are dropped, since
+// they may now point at other columns.
+func reloadConfig(cfg fileConfig) (fileConfig, error) {
+	data, err := converter.ReadFileData(cfg.path)
+	if err != nil {
+		return cfg, err
+	}
+
+	if strings.Join(data.Headers, "\x00") == strings.Join(cfg.readData.Headers, "\x00") {
+		selected := cfg.selectedCols
+		cfg.readData = data
+		cfg.setDurations(cfg.options.Durations)
+		cfg.selectedCols = selected
+		cfg.loaded = stampFile(cfg.path)
+		return cfg, nil
+	}
+
+	wanted := make(map[string]bool)
+	for idx, on := range cfg.selectedCols {
+		if on {
+			wanted[cfg.fileData.Headers[idx]] = true
+		}
+	}
+	fresh := redetect(cfg.path, data, wanted, cfg.options)
+	fresh.inPlace = cfg.inPlace
+	return fresh, nil
+}
+
+// redetect configures a file whose columns no longer match those opts were
+// chosen for. Its columns are detected again, the wanted headers stay
+// chosen where they're found, and the options naming columns by position
+// are dropped, since they may now point at other columns.
+func redetect(path string, data *types.FileData, wanted map[string]bool, opts types.ConversionOptions) fileConfig {
+	fresh := newFileConfig(path, data)
+	kept := make(map[int]bool)
+	for i, header := range fresh.fileData.Headers {
+		if wanted[header] {
+			kept[i] = true
+		}
+	}
+	if len(kept) > 0 {
+		fresh.selectedCols = kept
+	}
+
+	opts.FooterRows = fresh.options.FooterRows
+	opts.Columns = fresh.options.Columns
+	opts.Durations = fresh.options.Durations
+	opts.Filters, opts.Order, opts.KeepColumns, opts.Totals = nil, nil, nil, nil
+	fresh.options = opts
+	return fresh
+}
+
+// changedNames lists the files that changed for the prompt.
+func (m Model) changedNames() []string {
+	var names []string
+	for _, i := range m.changed {
+		names = append(names, filepath.Base(m.configs[i].path))
+	}
+	return names
+}
+
+// reloadedStatus reports the files read again.
+func reloadedStatus(configs map[int]fileConfig) string {
+	var names []string
+	for _, cfg := range configs {
+		names = append(names, filepath.Base(cfg.path))
+	}
+	sort.Strings(names)
+	return tr("Reloaded %s; check the columns and press enter", strings.Join(names, ", "))
+}
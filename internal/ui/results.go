@@ -0,0 +1,199 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/nconklindev/chronos/internal/summary"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// expandLimit is the most results shown expanded when the complete screen
+// opens; larger batches start collapsed to one line per file.
+const expandLimit = 3
+
+// resultsChrome is how many lines the complete screen uses around the
+// results: the title, time saved, status, help and box.
+const resultsChrome = 16
+
+// reportHelp lists the formats the batch report can be exported in.
+const reportHelp = "Export report as c: CSV • j: JSON • m: Markdown • p: PDF • esc: cancel"
+
+// reportFormats are the batch report's formats by the keys that pick them.
+var reportFormats = map[string]summary.ReportFormat{
+	"c": summary.ReportCSV,
+	"j": summary.ReportJSON,
+	"m": summary.ReportMarkdown,
+	"p": summary.ReportPDF,
+}
+
+// showResults switches to the complete screen with the cursor on the first file.
+func (m Model) showResults() Model {
+	m.state = stateComplete
+	m.resultCursor = 0
+	m.expanded = make(map[int]bool)
+	if len(m.results) <= expandLimit {
+		for i := range m.results {
+			m.expanded[i] = true
+		}
+	}
+	m.resultsView.SetYOffset(0)
+	m.updateResultsContent()
+	return m
+}
+
+// updateResults handles the keys that move through the results. handled
+// is false for the complete screen's other keys.
+func (m Model) updateResults(msg tea.KeyMsg) (Model, bool) {
+	k := m.keys.Results
+	switch {
+	case key.Matches(msg, k.Up):
+		m.resultCursor = max(m.resultCursor-1, 0)
+	case key.Matches(msg, k.Down):
+		m.resultCursor = min(m.resultCursor+1, len(m.results)-1)
+	case key.Matches(msg, k.Toggle):
+		m.expanded[m.resultCursor] = !m.expanded[m.resultCursor]
+	case key.Matches(msg, k.ToggleAll):
+		// Expand everything unless it's all expanded already
+		expand := false
+		for i := range m.results {
+			if !m.expanded[i] {
+				expand = true
+			}
+		}
+		for i := range m.results {
+			m.expanded[i] = expand
+		}
+	case key.Matches(msg, k.PageUp):
+		m.resultsView.PageUp()
+		return m, true
+	case key.Matches(msg, k.PageDown):
+		m.resultsView.PageDown()
+		return m, true
+	default:
+		return m, false
+	}
+	m.updateResultsContent()
+	return m, true
+}
+
+// updateResultsContent renders the results into their viewport, scrolled
+// so the file under the cursor is visible.
+func (m *Model) updateResultsContent() {
+	var s strings.Builder
+	// The lines the file under the cursor starts and ends on
+	cursorStart, cursorEnd := 0, 0
+
+	// Keep paths inside the box: its border and padding, and the labels
+	maxPathLen := max(m.width-20, 30)
+	shorten := func(path string) string {
+		if len(path) > maxPathLen {
+			return "..." + path[len(path)-maxPathLen+3:]
+		}
+		return path
+	}
+
+	for i, res := range m.results {
+		if i == m.resultCursor {
+			cursorStart = strings.Count(s.String(), "\n")
+		}
+
+		arrow := "▸"
+		if m.expanded[i] {
+			arrow = "▾"
+		}
+		counts := tr("%d rows", res.RowsProcessed)
+		if len(res.Warnings) > 0 {
+			counts += tr(", %d warning(s)", len(res.Warnings))
+		}
+		line := fmt.Sprintf("%s %s → %s (%s)", arrow, filepath.Base(res.InputFile), filepath.Base(res.OutputFile), counts)
+		if i == m.resultCursor {
+			s.WriteString(SelectedStyle.Render("> " + line))
+		} else {
+			s.WriteString("  " + line)
+		}
+		s.WriteString("\n")
+
+		if !m.expanded[i] {
+			if i == m.resultCursor {
+				cursorEnd = cursorStart
+			}
+			continue
+		}
+		labels := alignLabels(tr("Input:"), tr("Output:"), tr("Totals:"), tr("Columns:"))
+		s.WriteString("    " + labels[0] + shorten(res.InputFile) + "\n")
+		s.WriteString("    " + SuccessStyle.Render(labels[1]+shorten(res.OutputFile)))
+		s.WriteString("\n")
+		if res.TotalsFile != "" {
+			s.WriteString("    " + SuccessStyle.Render(labels[2]+shorten(res.TotalsFile)))
+			s.WriteString("\n")
+		}
+		s.WriteString("    " + labels[3] + strings.Join(res.ColumnsFound, ", ") + "\n")
+		for _, warning := range res.Warnings {
+			s.WriteString("    " + WarningStyle.Render("⚠ "+warning))
+			s.WriteString("\n")
+		}
+		if i == m.resultCursor {
+			cursorEnd = strings.Count(s.String(), "\n") - 1
+		}
+	}
+
+	// Cut long lines rather than let the viewport wrap them
+	m.resultsView.Width = max(m.width-6, 10)
+	lines := strings.Split(strings.TrimSuffix(s.String(), "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = lipgloss.NewStyle().MaxWidth(m.resultsView.Width).Render(line)
+	}
+	content := strings.Join(lines, "\n")
+	m.resultsView.Height = strings.Count(content, "\n") + 1
+	if m.height > 0 {
+		m.resultsView.Height = min(m.resultsView.Height, max(m.height-resultsChrome, 3))
+	}
+	m.resultsView.SetContent(content)
+
+	// Scroll just enough to show the file under the cursor, or at least
+	// its first line when its details don't fit
+	if cursorEnd >= m.resultsView.YOffset+m.resultsView.Height {
+		m.resultsView.SetYOffset(cursorEnd - m.resultsView.Height + 1)
+	}
+	if cursorStart < m.resultsView.YOffset {
+		m.resultsView.SetYOffset(cursorStart)
+	}
+}
+
+// updateExport exports the batch report in the format picked, or cancels.
+func (m Model) updateExport(msg tea.KeyMsg) Model {
+	if msg.String() == "esc" {
+		m.exportingReport = false
+		return m
+	}
+	format, ok := reportFormats[msg.String()]
+	if !ok {
+		return m
+	}
+
+	m.exportingReport = false
+	path, err := m.exportReport(format)
+	if err != nil {
+		m.status = tr("Could not export report: %v", err)
+	} else {
+		m.status = tr("Report exported to %s", path)
+	}
+	return m
+}
+
+// exportReport saves a report of the finished batch next to its first
+// output, where it can be attached to a ticket.
+func (m Model) exportReport(format summary.ReportFormat) (string, error) {
+	dir := m.filepicker.CurrentDirectory
+	if len(m.results) > 0 {
+		dir = filepath.Dir(m.results[0].OutputFile)
+	}
+	path := summary.ReportPath(dir, format, time.Now())
+	return path, summary.WriteReport(path, summary.NewReport(m.finishedRun()), format)
+}
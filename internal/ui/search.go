@@ -0,0 +1,288 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/nconklindev/chronos/internal/filepicker"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// searchLimit caps how many entries a recursive search collects, so a
+// search started in a huge tree stays responsive.
+const searchLimit = 10000
+
+// searchHelp is the key reference shown under the search results.
+const searchHelp = "type to filter • ↑/↓: navigate • enter: select file or open folder • ctrl+r: search subfolders • esc: cancel"
+
+// errSearchLimit stops the directory walk once searchLimit entries are found.
+var errSearchLimit = errors.New("search limit reached")
+
+// searchEntry is a file or folder the search can match.
+type searchEntry struct {
+	path  string
+	rel   string // Path relative to the directory being searched, as shown and matched
+	isDir bool
+}
+
+// searchMatch is an entry that matches the query, with the matched rune
+// positions in rel for highlighting.
+type searchMatch struct {
+	searchEntry
+	positions []int
+	score     int
+}
+
+// startSearch opens the search prompt over the file picker's directory.
+func (m Model) startSearch() (Model, tea.Cmd) {
+	m.searching = true
+	m.searchInput.SetValue("")
+	m.searchEntries = listSearchEntries(m.filepicker, m.searchRecursive)
+	m.filterSearch()
+	return m, m.searchInput.Focus()
+}
+
+// updateSearch handles keys while the search prompt is open.
+func (m Model) updateSearch(msg tea.KeyMsg) (Model, tea.Cmd) {
+	if key.Matches(msg, m.keys.Picker.SearchAll) {
+		m.searchRecursive = !m.searchRecursive
+		m.searchEntries = listSearchEntries(m.filepicker, m.searchRecursive)
+		m.filterSearch()
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "esc":
+		m.searching = false
+		m.searchInput.Blur()
+		return m, nil
+	case "up", "ctrl+p":
+		if m.searchCursor > 0 {
+			m.searchCursor--
+		}
+		return m, nil
+	case "down", "ctrl+n":
+		if m.searchCursor < len(m.searchMatches)-1 {
+			m.searchCursor++
+		}
+		return m, nil
+	case "enter":
+		if len(m.searchMatches) == 0 {
+			return m, nil
+		}
+		match := m.searchMatches[m.searchCursor]
+		m.searching = false
+		m.searchInput.Blur()
+		if match.isDir {
+			return m.openDirectory(match.path)
+		}
+		m.selectFile(match.path)
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.searchInput, cmd = m.searchInput.Update(msg)
+	m.filterSearch()
+	return m, cmd
+}
+
+// filterSearch matches the entries against the query, best first.
+func (m *Model) filterSearch() {
+	query := m.searchInput.Value()
+	m.searchMatches = m.searchMatches[:0]
+	for _, entry := range m.searchEntries {
+		positions, score, ok := fuzzyMatch(query, entry.rel)
+		if ok {
+			m.searchMatches = append(m.searchMatches, searchMatch{searchEntry: entry, positions: positions, score: score})
+		}
+	}
+
+	if query != "" {
+		sort.SliceStable(m.searchMatches, func(i, j int) bool {
+			a, b := m.searchMatches[i], m.searchMatches[j]
+			if a.score != b.score {
+				return a.score > b.score
+			}
+			return len(a.rel) < len(b.rel)
+		})
+	}
+	m.searchCursor = 0
+}
+
+// listSearchEntries collects the folders and selectable files under the
+// picker's directory, skipping hidden ones unless the picker shows them. Only the directory itself is
+// listed unless recursive is set.
+func listSearchEntries(fp filepicker.Model, recursive bool) []searchEntry {
+	root := fp.CurrentDirectory
+	var entries []searchEntry
+
+	add := func(path string, d fs.DirEntry) {
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return
+		}
+		if d.IsDir() || allowedFile(fp, d.Name()) {
+			entries = append(entries, searchEntry{path: path, rel: rel, isDir: d.IsDir()})
+		}
+	}
+
+	if !recursive {
+		dirEntries, err := os.ReadDir(root)
+		if err != nil {
+			return nil
+		}
+		for _, d := range dirEntries {
+			if !hidden(fp, d.Name()) {
+				add(filepath.Join(root, d.Name()), d)
+			}
+		}
+		return entries
+	}
+
+	// Unreadable folders are skipped rather than ending the search
+	_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || path == root {
+			return nil
+		}
+		if hidden(fp, d.Name()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		add(path, d)
+		if len(entries) >= searchLimit {
+			return errSearchLimit
+		}
+		return nil
+	})
+	return entries
+}
+
+// hidden reports whether the picker hides name
+func hidden(fp filepicker.Model, name string) bool {
+	if fp.ShowHidden {
+		return false
+	}
+	isHidden, _ := filepicker.IsHidden(name)
+	return isHidden
+}
+
+// allowedFile reports whether the picker would let name be selected
+func allowedFile(fp filepicker.Model, name string) bool {
+	if len(fp.AllowedTypes) == 0 {
+		return true
+	}
+	for _, ext := range fp.AllowedTypes {
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// fuzzyMatch reports whether every rune of pattern appears in s in order,
+// ignoring case. It returns the positions of the matched runes and a score
+// in which consecutive matches and matches at the start of a word count
+// for more, so "tsw42" ranks timesheet_week42.csv above unrelated names.
+func fuzzyMatch(pattern, s string) ([]int, int, bool) {
+	if pattern == "" {
+		return nil, 0, true
+	}
+
+	want := []rune(strings.ToLower(pattern))
+	runes := []rune(s)
+	var positions []int
+	score := 0
+	p := 0
+
+	for i, r := range runes {
+		if p == len(want) {
+			break
+		}
+		if unicode.ToLower(r) != want[p] {
+			continue
+		}
+
+		score++
+		if len(positions) > 0 && positions[len(positions)-1] == i-1 {
+			score += 2
+		}
+		if i == 0 || strings.ContainsRune("/\\_-. ", runes[i-1]) {
+			score += 3
+		}
+		positions = append(positions, i)
+		p++
+	}
+
+	if p < len(want) {
+		return nil, 0, false
+	}
+	return positions, score, true
+}
+
+// searchLines renders the matches with their matched runes highlighted and
+// the cursor marked.
+func (m Model) searchLines() []string {
+	var lines []string
+	for i, match := range m.searchMatches {
+		matched := make(map[int]bool, len(match.positions))
+		for _, pos := range match.positions {
+			matched[pos] = true
+		}
+
+		var name strings.Builder
+		for j, r := range []rune(match.rel) {
+			if matched[j] {
+				name.WriteString(MatchStyle.Render(string(r)))
+			} else {
+				name.WriteRune(r)
+			}
+		}
+		if match.isDir {
+			name.WriteString(string(filepath.Separator))
+		}
+
+		cursor := "  "
+		if i == m.searchCursor {
+			cursor = SelectedStyle.Render("> ")
+		}
+		lines = append(lines, cursor+name.String())
+	}
+	return lines
+}
+
+// searchStatus summarizes how many entries matched.
+func (m Model) searchStatus() string {
+	scope := "this folder"
+	if m.searchRecursive {
+		scope = "this folder and subfolders"
+	}
+	status := fmt.Sprintf("%d of %d in %s", len(m.searchMatches), len(m.searchEntries), scope)
+	if len(m.searchEntries) >= searchLimit {
+		status += " (stopped at the first " + fmt.Sprint(searchLimit) + ")"
+	}
+	return status
+}
+
+// viewSearch renders the search prompt and results in height lines.
+func (m Model) viewSearch(height int) string {
+	var s strings.Builder
+	s.WriteString(m.searchInput.View())
+	s.WriteString("\n")
+	s.WriteString(SubtitleStyle.Render(m.searchStatus()))
+	s.WriteString("\n")
+
+	// The prompt and the status line with its margin take three lines
+	start, end := listWindow(m.searchCursor, len(m.searchMatches), height-3)
+	s.WriteString(strings.Join(m.searchLines()[start:end], "\n"))
+	return s.String()
+}
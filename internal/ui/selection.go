@@ -0,0 +1,126 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// selectionHelp is the key reference shown while the selected files list has focus.
+const selectionHelp = "↑/↓: navigate • x/Delete: remove • Tab/Esc: back to files • Enter: confirm selection • q: quit"
+
+// updateSelection handles keys while the selected files list has focus.
+// handled is false for keys the file picker screen handles the same way
+// either way, such as Enter and q.
+func (m Model) updateSelection(msg tea.KeyMsg) (Model, bool) {
+	k := m.keys.Picker
+	switch {
+	case key.Matches(msg, k.EditSelection), msg.String() == "esc":
+		m.selectionFocused = false
+	case key.Matches(msg, k.Up):
+		if m.selectionCursor > 0 {
+			m.selectionCursor--
+		}
+	case key.Matches(msg, k.Down):
+		if m.selectionCursor < len(m.selectedFiles)-1 {
+			m.selectionCursor++
+		}
+	case key.Matches(msg, k.RemoveLast), msg.String() == "x", msg.String() == "backspace":
+		m.removeSelected(m.selectionCursor)
+	case key.Matches(msg, k.Confirm, k.Quit):
+		return m, false
+	}
+	return m, true
+}
+
+// removeSelected drops the selected file at i, keeping the cursor in range
+// and returning focus to the picker once the list is empty.
+func (m *Model) removeSelected(i int) {
+	if i < 0 || i >= len(m.selectedFiles) {
+		return
+	}
+	m.selectedFiles = append(m.selectedFiles[:i:i], m.selectedFiles[i+1:]...)
+	m.selectionCursor = min(m.selectionCursor, len(m.selectedFiles)-1)
+	if len(m.selectedFiles) == 0 {
+		m.selectionCursor = 0
+		m.selectionFocused = false
+	}
+}
+
+// fileKind names the type of a selectable file from its extension.
+func fileKind(path string) string {
+	name := strings.ToLower(path)
+	switch {
+	case strings.HasSuffix(name, ".csv.gz"):
+		return "gzipped CSV"
+	case strings.HasSuffix(name, ".zip"):
+		return "ZIP archive"
+	case strings.HasSuffix(name, ".xlsx"):
+		return "XLSX"
+	case strings.HasSuffix(name, ".csv"):
+		return "CSV"
+	case strings.HasSuffix(name, ".ndjson"):
+		return "NDJSON"
+	case strings.HasSuffix(name, ".json"):
+		return "JSON"
+	}
+	return strings.TrimPrefix(filepath.Ext(name), ".")
+}
+
+// formatSize formats a byte count for display, such as "12.3 KB".
+func formatSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for q := n / unit; q >= unit; q /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGT"[exp])
+}
+
+// selectedFileLines lists the selected files with their size and type,
+// marking the cursor while the list has focus.
+func (m Model) selectedFileLines() []string {
+	var lines []string
+	for i, file := range m.selectedFiles {
+		details := fileKind(file)
+		if info, err := os.Stat(file); err == nil {
+			details = formatSize(info.Size()) + " • " + details
+		}
+
+		name := filepath.Base(file)
+		details = "(" + details + ")"
+		detailsStyle := SubtitleStyle.UnsetMarginBottom()
+
+		// On narrow terminals long names get the details on their own line
+		var below string
+		if m.width > 0 {
+			room := m.width - len(fmt.Sprintf("  %d. ", i+1))
+			if lipgloss.Width(name)+1+lipgloss.Width(details) > room {
+				name = truncate(name, room)
+				below = "\n     " + detailsStyle.Render(truncate(details, room))
+				details = ""
+			}
+		}
+
+		line := fmt.Sprintf("%d. %s", i+1, name)
+		if m.selectionFocused && i == m.selectionCursor {
+			line = SelectedStyle.Render("> " + line)
+		} else {
+			line = "  " + line
+		}
+		if details != "" {
+			line += " " + detailsStyle.Render(details)
+		}
+		lines = append(lines, line+below)
+	}
+	return lines
+}
@@ -0,0 +1,104 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/nconklindev/chronos/internal/config"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// sessionPrompt asks whether to pick up a batch left unfinished last time.
+const sessionPrompt = "Resume the batch you were setting up?"
+
+type sessionLoadedMsg struct {
+	files   []string
+	configs []fileConfig
+	// redetected names the files whose columns were detected again.
+	redetected []string
+	err        error
+}
+
+// SaveSession remembers a batch of files that's quit while its columns are
+// still being chosen, so it can be resumed on the next launch. Single
+// files are quick to set up again and aren't saved, and neither are files
+// that came from an archive or a download, since their paths were
+// temporary. Any other state leaves the saved session alone.
+func (m Model) SaveSession() error {
+	if m.state != stateColumnSelection && m.state != stateLoading {
+		return nil
+	}
+	// While the next file loads, the ones before it are configured
+	configured := min(len(m.configs), m.currentFileIndex+1)
+	if configured == 0 || len(m.selectedFiles) < 2 {
+		return nil
+	}
+
+	s := config.Session{Time: time.Now()}
+	for i, path := range m.selectedFiles {
+		if _, ok := m.archives[path]; ok {
+			continue
+		}
+		if _, ok := m.downloads[path]; ok {
+			continue
+		}
+		item := config.SessionItem{RunFile: config.RunFile{Path: path}}
+		if i < configured {
+			cfg := m.configs[i]
+			item.Configured = true
+			item.InPlace = cfg.inPlace
+			item.Options = cfg.options
+			item.Headers = cfg.readData.Headers
+			for _, idx := range cfg.orderedIndices() {
+				if cfg.selectedCols[idx] {
+					item.Columns = append(item.Columns, cfg.fileData.Headers[idx])
+				}
+			}
+		}
+		s.Files = append(s.Files, item)
+	}
+	if s.Configured() == 0 {
+		return nil
+	}
+	return config.SaveSession(s)
+}
+
+// resumeSession loads the files of a saved session, applying the columns
+// and options of those that were configured.
+func resumeSession(s config.Session) tea.Cmd {
+	return func() tea.Msg {
+		var msg sessionLoadedMsg
+		for i, item := range s.Files {
+			msg.files = append(msg.files, item.Path)
+			if i >= s.Configured() {
+				continue
+			}
+
+			cfg, redetected, err := restoreConfig(item.Path, item.RunFile)
+			if err != nil {
+				return sessionLoadedMsg{err: err}
+			}
+			if redetected {
+				msg.redetected = append(msg.redetected, filepath.Base(item.Path))
+			}
+			cfg.inPlace = item.InPlace
+			msg.configs = append(msg.configs, cfg)
+		}
+		return msg
+	}
+}
+
+// sessionLines lists the files of the saved session for the resume prompt.
+func (m Model) sessionLines() []string {
+	lines := []string{tr("Saved %s, %d of %d file(s) configured:", m.session.Time.Format("Jan 2 15:04"), m.session.Configured(), len(m.session.Files))}
+	for i, item := range m.session.Files {
+		mark := "  "
+		if item.Configured {
+			mark = "✓ "
+		}
+		lines = append(lines, fmt.Sprintf("%s%d. %s", mark, i+1, filepath.Base(item.Path)))
+	}
+	return lines
+}
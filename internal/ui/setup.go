@@ -0,0 +1,295 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/nconklindev/chronos/internal/config"
+	"github.com/nconklindev/chronos/internal/paths"
+	"github.com/nconklindev/chronos/internal/types"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// The steps of the setup shown on first launch, in order.
+const (
+	setupFolder = iota
+	setupTheme
+	setupKeepOriginal
+	setupRounding
+	setupOutput
+	setupKeys
+	setupSteps
+)
+
+// setupTitles head each step of the setup.
+var setupTitles = [setupSteps]string{
+	setupFolder:       "Which folder should the file picker open in?",
+	setupTheme:        "Which colors suit your terminal?",
+	setupKeepOriginal: "Keep the decimal hours next to the converted columns?",
+	setupRounding:     "How should converted hours be rounded?",
+	setupOutput:       "What should be added to the names of converted files?",
+	setupKeys:         "The keys you'll use most",
+}
+
+// setupChoices are the options of the steps picked from a list.
+var setupChoices = map[int][]string{
+	setupTheme:        ThemeNames(),
+	setupKeepOriginal: {"No, replace them", "Yes, keep both"},
+	setupRounding:     {"To the nearest minute", "Down to the minute", "Up to the minute", "To the quarter hour (7-minute rule)", "To the tenth of an hour"},
+}
+
+// setup is the state of the first-run setup.
+type setup struct {
+	step   int
+	cursor map[int]int // The choice under the cursor of each list step
+	input  textinput.Model
+	err    string
+	// auto is the theme "auto" picked at startup. The terminal can't be
+	// asked its background again while the interface is running.
+	auto Theme
+}
+
+// newSetup starts the setup, with the choices of each step on the current
+// settings.
+func newSetup(s config.Settings) *setup {
+	input := textinput.New()
+	input.PromptStyle = SelectedStyle
+
+	su := &setup{cursor: make(map[int]int), input: input}
+	su.auto, _ = LoadTheme("auto", s.Colors)
+	for i, name := range setupChoices[setupTheme] {
+		if name == s.Theme || (s.Theme == "" && name == "auto") {
+			su.cursor[setupTheme] = i
+		}
+	}
+	if s.KeepOriginal {
+		su.cursor[setupKeepOriginal] = 1
+	}
+	for i, r := range types.Roundings {
+		if r == s.Rounding {
+			su.cursor[setupRounding] = i
+		}
+	}
+	su.enter(s)
+	return su
+}
+
+// enter readies the text input of the current step, if it has one.
+func (su *setup) enter(s config.Settings) {
+	su.err = ""
+	switch su.step {
+	case setupFolder:
+		su.input.Prompt = tr("Folder: ")
+		su.input.Placeholder = tr("empty reopens the last folder used")
+		su.input.SetValue(s.StartDir)
+		su.input.Focus()
+	case setupOutput:
+		su.input.Prompt = tr("Suffix: ")
+		su.input.Placeholder = "_converted"
+		su.input.SetValue(s.OutputSuffix)
+		su.input.Focus()
+	default:
+		su.input.Blur()
+	}
+}
+
+// updateSetup handles the keys of the setup. Enter keeps the step's answer
+// and moves on, and esc goes back a step or, on the first, skips the rest.
+func (m Model) updateSetup(msg tea.KeyMsg) (Model, tea.Cmd) {
+	su := m.setup
+	switch msg.String() {
+	case "esc":
+		if su.step == setupFolder {
+			return m.finishSetup()
+		}
+		su.step--
+		su.enter(m.settings)
+		return m, nil
+	case "enter":
+		if err := m.keepSetupAnswer(); err != nil {
+			su.err = err.Error()
+			return m, nil
+		}
+		su.step++
+		if su.step == setupSteps {
+			return m.finishSetup()
+		}
+		su.enter(m.settings)
+		return m, nil
+	}
+
+	if choices, ok := setupChoices[su.step]; ok {
+		switch {
+		case key.Matches(msg, m.keys.Picker.Up):
+			su.cursor[su.step] = max(su.cursor[su.step]-1, 0)
+		case key.Matches(msg, m.keys.Picker.Down):
+			su.cursor[su.step] = min(su.cursor[su.step]+1, len(choices)-1)
+		}
+		if su.step == setupTheme {
+			m.previewTheme(choices[su.cursor[su.step]])
+		}
+		return m, nil
+	}
+	if su.input.Focused() {
+		var cmd tea.Cmd
+		su.input, cmd = su.input.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+// keepSetupAnswer records the current step's answer in the settings.
+func (m *Model) keepSetupAnswer() error {
+	su := m.setup
+	value := strings.TrimSpace(su.input.Value())
+	switch su.step {
+	case setupFolder:
+		if value == "" {
+			m.settings.StartDir = ""
+			return nil
+		}
+		dir := paths.Normalize(value)
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			return errors.New(tr("%s is not a folder", value))
+		}
+		m.settings.StartDir = dir
+	case setupTheme:
+		m.settings.Theme = setupChoices[setupTheme][su.cursor[setupTheme]]
+	case setupKeepOriginal:
+		m.settings.KeepOriginal = su.cursor[setupKeepOriginal] == 1
+	case setupRounding:
+		m.settings.Rounding = types.Roundings[su.cursor[setupRounding]]
+	case setupOutput:
+		if strings.ContainsAny(value, `/\`) {
+			return errors.New(tr("the suffix can't contain slashes"))
+		}
+		m.settings.OutputSuffix = value
+	}
+	return nil
+}
+
+// previewTheme restyles the interface in a theme while it's being chosen.
+func (m *Model) previewTheme(name string) {
+	t := m.setup.auto
+	if name != "auto" {
+		t, _ = LoadTheme(name, m.settings.Colors)
+	}
+	SetTheme(t)
+	m.restyle()
+	m.setup.input.PromptStyle = SelectedStyle
+}
+
+// finishSetup saves the settings chosen and opens the file picker in the
+// folder picked.
+func (m Model) finishSetup() (Model, tea.Cmd) {
+	m.setup = nil
+	m.state = stateFilePicker
+	if err := config.SaveSettings(m.settings); err != nil {
+		m.status = tr("Could not save settings: %v", err)
+	}
+	if m.settings.StartDir != "" && m.settings.StartDir != m.filepicker.CurrentDirectory {
+		return m.openDirectory(m.settings.StartDir)
+	}
+	return m, nil
+}
+
+// viewSetup renders the current step of the setup.
+func (m Model) viewSetup() string {
+	var s strings.Builder
+	s.WriteString(TitleStyle.Render(tr("⏰ Welcome to Chronos")))
+	s.WriteString("\n")
+	s.WriteString(SubtitleStyle.Render(tr("Setup %d/%d • everything can be changed later in config.json", m.setup.step+1, setupSteps)))
+	s.WriteString("\n")
+	s.WriteString(m.setupBody())
+	s.WriteString(HelpStyle.Render(m.setupHelp()))
+	return m.box(s.String())
+}
+
+// setupBody renders the current step's question and answer.
+func (m Model) setupBody() string {
+	su := m.setup
+	var s strings.Builder
+	s.WriteString(SelectedStyle.Render(translator.Text(setupTitles[su.step])))
+	s.WriteString("\n\n")
+
+	switch su.step {
+	case setupFolder, setupOutput:
+		s.WriteString(su.input.View())
+		s.WriteString("\n")
+		if su.step == setupOutput {
+			suffix := strings.TrimSpace(su.input.Value())
+			if suffix == "" {
+				suffix = "_converted"
+			}
+			s.WriteString(SubtitleStyle.UnsetMarginBottom().Render(tr("timesheet.csv is converted to timesheet%s.csv", suffix)))
+			s.WriteString("\n")
+		}
+	case setupKeys:
+		s.WriteString(m.setupKeysView())
+	default:
+		for i, choice := range setupChoices[su.step] {
+			// Theme names are what the config file takes
+			if su.step != setupTheme {
+				choice = translator.Text(choice)
+			}
+			if i == su.cursor[su.step] {
+				s.WriteString(SelectedStyle.Render("> " + choice))
+			} else {
+				s.WriteString(UnselectedStyle.Render("  " + choice))
+			}
+			s.WriteString("\n")
+		}
+	}
+
+	if su.err != "" {
+		s.WriteString("\n")
+		s.WriteString(ErrorStyle.Render(su.err))
+		s.WriteString("\n")
+	}
+	return s.String()
+}
+
+// setupHelp lists the keys of the current step.
+func (m Model) setupHelp() string {
+	su := m.setup
+	help := tr("enter: next • esc: back")
+	switch su.step {
+	case setupFolder:
+		help = tr("enter: next • esc: skip setup")
+	case setupKeys:
+		help = tr("enter: start converting • esc: back")
+	}
+	if _, ok := setupChoices[su.step]; ok {
+		help = tr("%s/%s: choose • %s", m.keys.Picker.Up.Help().Key, m.keys.Picker.Down.Help().Key, help)
+	}
+	return help
+}
+
+// setupKeysView explains the keys of each screen, as configured.
+func (m Model) setupKeysView() string {
+	p, c, r := m.keys.Picker, m.keys.Columns, m.keys.Results
+	screens := []struct {
+		name     string
+		bindings []key.Binding
+	}{
+		{tr("Picking files"), []key.Binding{p.Select, p.Confirm, p.Search, p.TypePath, p.History}},
+		{tr("Choosing columns"), []key.Binding{c.Toggle, c.KeepOriginal, c.Details, c.Explain, c.Confirm}},
+		{tr("Results"), []key.Binding{r.Open, r.Report, r.Undo}},
+	}
+
+	var s strings.Builder
+	for _, screen := range screens {
+		s.WriteString(CheckedStyle.Render(screen.name))
+		s.WriteString("\n")
+		for _, b := range screen.bindings {
+			s.WriteString(fmt.Sprintf("  %-8s %s\n", b.Help().Key, b.Help().Desc))
+		}
+	}
+	s.WriteString("\n" + tr("Press %s on any screen for all of its keys.", m.keys.Picker.Help.Help().Key) + "\n")
+	return s.String()
+}
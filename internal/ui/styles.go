@@ -1,46 +1,242 @@
 package ui
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/nconklindev/chronos/internal/filepicker"
+
+	"github.com/charmbracelet/bubbles/progress"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Theme is the palette the interface is drawn with. Colors are hex codes
+// or ANSI color numbers; an empty color leaves the terminal's default.
+type Theme struct {
+	Primary string // Titles, the cursor and borders
+	Accent  string // Links, checked columns, folders and success messages
+	Muted   string // Subtitles and help
+	Text    string // File names and unselected columns
+	Error   string
+	Warning string
+	// ProgressEnd is where the progress bar's gradient from Primary ends.
+	ProgressEnd string
+}
+
+// Themes are the built-in themes, selected with the "theme" setting.
+var Themes = map[string]Theme{
+	"dark": {
+		Primary:     "#FF8C42",
+		Accent:      "#FFB84D",
+		Muted:       "#6B7280",
+		Text:        "#FFFFFF",
+		Error:       "#FF4757",
+		Warning:     "#FACC15",
+		ProgressEnd: "#FF9F5A",
+	},
+	"light": {
+		Primary:     "#C2410C",
+		Accent:      "#B45309",
+		Muted:       "#4B5563",
+		Text:        "#111827",
+		Error:       "#B91C1C",
+		Warning:     "#A16207",
+		ProgressEnd: "#EA580C",
+	},
+	// high-contrast sticks to the basic ANSI colors, which terminals keep readable
+	"high-contrast": {
+		Primary:     "14",
+		Accent:      "11",
+		Muted:       "7",
+		Text:        "15",
+		Error:       "9",
+		Warning:     "11",
+		ProgressEnd: "14",
+	},
+	// no-color keeps only bold and underline, as with NO_COLOR
+	"no-color": {},
+}
+
+// ThemeNames lists the values the "theme" setting accepts.
+func ThemeNames() []string {
+	names := []string{"auto"}
+	for name := range Themes {
+		names = append(names, name)
+	}
+	sort.Strings(names[1:])
+	return names
+}
+
+// LoadTheme picks the theme named in the settings and applies custom
+// colors on top. NO_COLOR (https://no-color.org) overrides both. "auto" or
+// an empty name picks dark or light from the terminal's background. An
+// unknown theme or color name is reported in err alongside the dark theme
+// or the remaining colors.
+func LoadTheme(name string, colors map[string]string) (Theme, error) {
+	if os.Getenv("NO_COLOR") != "" {
+		return Themes["no-color"], nil
+	}
+
+	var problems []string
+	if name == "" || name == "auto" {
+		name = "dark"
+		if !lipgloss.HasDarkBackground() {
+			name = "light"
+		}
+	}
+	t, ok := Themes[name]
+	if !ok {
+		problems = append(problems, fmt.Sprintf("unknown theme %q (choose from %s)", name, strings.Join(ThemeNames(), ", ")))
+		t = Themes["dark"]
+	}
+
+	fields := map[string]*string{
+		"primary":      &t.Primary,
+		"accent":       &t.Accent,
+		"muted":        &t.Muted,
+		"text":         &t.Text,
+		"error":        &t.Error,
+		"warning":      &t.Warning,
+		"progress_end": &t.ProgressEnd,
+	}
+	var unknown []string
+	for field, color := range colors {
+		if p, ok := fields[field]; ok {
+			*p = color
+		} else {
+			unknown = append(unknown, field)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		problems = append(problems, "unknown theme colors: "+strings.Join(unknown, ", "))
+	}
+
+	if len(problems) > 0 {
+		return t, fmt.Errorf("%s", strings.Join(problems, "; "))
+	}
+	return t, nil
+}
+
+// color converts a theme color, leaving empty colors unset.
+func color(c string) lipgloss.TerminalColor {
+	if c == "" {
+		return lipgloss.NoColor{}
+	}
+	return lipgloss.Color(c)
+}
+
+// theme is the palette the styles below were built from.
+var theme Theme
 
 var (
+	TitleStyle      lipgloss.Style
+	LinkStyle       lipgloss.Style
+	SubtitleStyle   lipgloss.Style
+	SelectedStyle   lipgloss.Style
+	UnselectedStyle lipgloss.Style
+	CheckedStyle    lipgloss.Style
+	ErrorStyle      lipgloss.Style
+	SuccessStyle    lipgloss.Style
+	// MatchStyle highlights the characters a search matched.
+	MatchStyle   lipgloss.Style
+	WarningStyle lipgloss.Style
+	HelpStyle    lipgloss.Style
+	BoxStyle     lipgloss.Style
+)
+
+func init() {
+	SetTheme(Themes["dark"])
+}
+
+// SetTheme rebuilds the styles from t. Components created afterwards, such
+// as the file picker and progress bar, pick it up too.
+func SetTheme(t Theme) {
+	theme = t
+
 	TitleStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("#FF8C42")).
-			MarginTop(1)
+		Bold(true).
+		Foreground(color(t.Primary)).
+		MarginTop(1)
 
 	LinkStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FFB84D")).
-			Underline(true)
+		Foreground(color(t.Accent)).
+		Underline(true)
 
 	SubtitleStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#6B7280")).
-			MarginBottom(1)
+		Foreground(color(t.Muted)).
+		MarginBottom(1)
 
 	SelectedStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FF8C42")).
-			Bold(true)
+		Foreground(color(t.Primary)).
+		Bold(true)
 
 	UnselectedStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FFFFFF"))
+		Foreground(color(t.Text))
 
 	CheckedStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FFB84D")).
-			Bold(true)
+		Foreground(color(t.Accent)).
+		Bold(true)
 
 	ErrorStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FF4757")).
-			Bold(true)
+		Foreground(color(t.Error)).
+		Bold(true)
 
 	SuccessStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FFB84D")).
-			Bold(true)
+		Foreground(color(t.Accent)).
+		Bold(true)
+
+	MatchStyle = lipgloss.NewStyle().
+		Foreground(color(t.Primary)).
+		Bold(true).
+		Underline(true)
+
+	WarningStyle = lipgloss.NewStyle().
+		Foreground(color(t.Warning))
 
 	HelpStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#6B7280")).
-			MarginTop(1)
+		Foreground(color(t.Muted)).
+		MarginTop(1)
 
 	BoxStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("#FF8C42")).
-			Padding(1, 2)
-)
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(color(t.Primary)).
+		Padding(1, 2)
+}
+
+// styleFilePicker colors the file picker to match the theme.
+func styleFilePicker(fp *filepicker.Model) {
+	fp.Styles.Cursor = lipgloss.NewStyle().Foreground(color(theme.Primary))
+	fp.Styles.Symlink = lipgloss.NewStyle().Foreground(color(theme.Accent))
+	fp.Styles.Directory = lipgloss.NewStyle().Foreground(color(theme.Accent))
+	fp.Styles.File = lipgloss.NewStyle().Foreground(color(theme.Text))
+	fp.Styles.Permission = lipgloss.NewStyle().Foreground(color(theme.Muted))
+	fp.Styles.Selected = lipgloss.NewStyle().Foreground(color(theme.Primary)).Bold(true)
+	fp.Styles.FileSize = lipgloss.NewStyle().Foreground(color(theme.Muted))
+}
+
+// newProgress builds a progress bar in the theme's colors and the
+// terminal's color profile, so NO_COLOR applies to it too.
+func newProgress() progress.Model {
+	// Gradients need hex colors; ANSI and empty colors fill solid
+	fill := progress.WithSolidFill(theme.Primary)
+	if strings.HasPrefix(theme.Primary, "#") && strings.HasPrefix(theme.ProgressEnd, "#") {
+		fill = progress.WithGradient(theme.Primary, theme.ProgressEnd)
+	}
+	return progress.New(fill, progress.WithColorProfile(lipgloss.ColorProfile()))
+}
+
+// restyle applies a theme changed while running to the components that
+// copied its styles when they were created.
+func (m *Model) restyle() {
+	styleFilePicker(&m.filepicker)
+	width := m.progress.Width
+	m.progress = newProgress()
+	m.progress.Width = width
+	for _, input := range []*textinput.Model{&m.filterInput, &m.headerInput, &m.settingInput, &m.profileInput, &m.urlInput, &m.columnInput, &m.pathInput, &m.searchInput} {
+		input.PromptStyle = SelectedStyle
+	}
+}
@@ -0,0 +1,40 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/nconklindev/chronos/internal/i18n"
+
+	"golang.org/x/text/language"
+)
+
+// translator renders the interface in the language chosen in the config
+// file or by the locale.
+var translator = i18n.New(language.English)
+
+// SetLanguage translates everything rendered afterwards into tag. Key
+// help is translated when the key map is built, so set it before then.
+func SetLanguage(tag language.Tag) {
+	translator = i18n.New(tag)
+}
+
+// tr translates a message and formats it like fmt.Sprintf. Messages
+// without a translation stay in English.
+func tr(format string, args ...any) string {
+	return translator.Sprintf(format, args...)
+}
+
+// alignLabels pads labels to the width of the widest, and a space, so the
+// values after them line up however long their translations are.
+func alignLabels(labels ...string) []string {
+	width := 0
+	for _, label := range labels {
+		width = max(width, lipgloss.Width(label))
+	}
+	padded := make([]string, len(labels))
+	for i, label := range labels {
+		padded[i] = label + strings.Repeat(" ", width-lipgloss.Width(label)+1)
+	}
+	return padded
+}
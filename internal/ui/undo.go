@@ -0,0 +1,111 @@
+package ui
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/nconklindev/chronos/internal/types"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// generatedFile is a file written during the current batch.
+type generatedFile struct {
+	path string
+	// backup is a copy of the file that was at path before it was
+	// overwritten, or empty if the file is new.
+	backup string
+}
+
+// backupOutput copies path into a new temporary directory if it exists, so
+// undo can put it back after it's overwritten.
+func backupOutput(path string) (generatedFile, error) {
+	out := generatedFile{path: path}
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return out, nil
+	} else if err != nil {
+		return out, err
+	}
+
+	dir, err := os.MkdirTemp("", "chronos-backup-")
+	if err != nil {
+		return out, err
+	}
+	out.backup = filepath.Join(dir, filepath.Base(path))
+	if err := copyFile(path, out.backup); err != nil {
+		os.RemoveAll(dir)
+		return generatedFile{path: path}, err
+	}
+	return out, nil
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// undo deletes the files written in this batch, newest first, and puts back
+// any files they overwrote.
+func (m Model) undo() (removed, restored int, err error) {
+	for i := len(m.generated) - 1; i >= 0; i-- {
+		g := m.generated[i]
+		if g.backup == "" {
+			if err := os.Remove(g.path); err != nil && !os.IsNotExist(err) {
+				return removed, restored, err
+			}
+			removed++
+			continue
+		}
+		if err := copyFile(g.backup, g.path); err != nil {
+			return removed, restored, err
+		}
+		restored++
+	}
+	return removed, restored, nil
+}
+
+// undoBatch reverts the finished batch and returns to column selection for
+// the first file, keeping its settings so a different choice can be made.
+func (m Model) undoBatch() (Model, tea.Cmd) {
+	removed, restored, err := m.undo()
+	if err != nil {
+		// Undo can be retried; files already reverted are handled again safely
+		m.status = tr("Could not undo: %v", err)
+		return m, nil
+	}
+
+	m.removeBackups()
+	m.generated = nil
+	m.results = []*types.ConversionResult{}
+	m.auditLogs = nil
+	m.currentFileIndex = 0
+	m.state = stateColumnSelection
+	m.status = tr("Undone: %d file(s) deleted, %d restored", removed, restored)
+	m.viewport.SetYOffset(0)
+	m.updateViewportContent()
+	return m, nil
+}
+
+// removeBackups deletes the copies kept for undo.
+func (m Model) removeBackups() {
+	for _, g := range m.generated {
+		if g.backup != "" {
+			os.RemoveAll(filepath.Dir(g.backup))
+		}
+	}
+}
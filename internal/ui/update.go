@@ -0,0 +1,31 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/nconklindev/chronos/internal/update"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// updateAvailableMsg names a newer release found when the interface opened.
+type updateAvailableMsg struct {
+	tag string
+}
+
+// checkForUpdate looks for a newer release than version in the background.
+// Failures aren't reported, as the check is only a courtesy.
+func checkForUpdate(version string) tea.Cmd {
+	return func() tea.Msg {
+		tag, err := update.Available(nil, update.LatestURL, version)
+		if err != nil || tag == "" {
+			return nil
+		}
+		return updateAvailableMsg{tag: tag}
+	}
+}
+
+// updateNotice tells the user how to install a newer release.
+func updateNotice(tag string) string {
+	return fmt.Sprintf("chronos %s is available; run \"chronos update\" to install it", tag)
+}
@@ -0,0 +1,350 @@
+package update
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nconklindev/chronos/internal/config"
+)
+
+// LatestURL describes the latest release of chronos on GitHub.
+const LatestURL = "https://api.github.com/repos/nconklindev/chronos/releases/latest"
+
+// ChecksumsFile lists the SHA-256 checksum of each archive of a release.
+const ChecksumsFile = "checksums.txt"
+
+// CheckFile remembers the last startup check, so GitHub is asked at most
+// once every CheckInterval.
+const CheckFile = "update-check.json"
+
+// CheckInterval is how long the latest release found by a startup check
+// is trusted.
+const CheckInterval = 24 * time.Hour
+
+// maxDownloadSize is the largest archive downloaded, well over any release.
+const maxDownloadSize = 100 << 20
+
+// requestTimeout is how long a request to GitHub may take, long enough to
+// download a release's archive on a slow connection, so a server that
+// stops answering doesn't hang the update or the startup check.
+const requestTimeout = 2 * time.Minute
+
+// Release is a published release of chronos.
+type Release struct {
+	Tag    string  `json:"tag_name"`
+	URL    string  `json:"html_url"`
+	Assets []Asset `json:"assets"`
+}
+
+// Asset is a file attached to a release.
+type Asset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+// Latest asks url, the GitHub API, for the latest release, with a client
+// that gives up after requestTimeout when client is nil.
+func Latest(client *http.Client, url string) (*Release, error) {
+	body, err := get(client, url)
+	if err != nil {
+		return nil, err
+	}
+	var r Release
+	if err := json.Unmarshal(body, &r); err != nil {
+		return nil, fmt.Errorf("reading the latest release: %w", err)
+	}
+	if r.Tag == "" {
+		return nil, fmt.Errorf("the latest release has no version")
+	}
+	return &r, nil
+}
+
+// Newer reports whether tag is a later version than current. Versions are
+// compared as major.minor.patch, with or without a leading v, and a
+// prerelease comes before its release. Development builds, whose version
+// isn't a number, are never older.
+func Newer(current, tag string) bool {
+	have, haveRelease, ok := parseVersion(current)
+	if !ok {
+		return false
+	}
+	want, wantRelease, ok := parseVersion(tag)
+	if !ok {
+		return false
+	}
+	for i := range have {
+		if have[i] != want[i] {
+			return want[i] > have[i]
+		}
+	}
+	return wantRelease && !haveRelease
+}
+
+// IsRelease reports whether a version is a release's, rather than a
+// development build's, so it can be compared.
+func IsRelease(version string) bool {
+	_, _, ok := parseVersion(version)
+	return ok
+}
+
+// parseVersion splits a version such as v1.2.3 or 1.2.3-rc1 into its
+// numbers, and whether it's a release rather than a prerelease.
+func parseVersion(v string) (numbers [3]int, release bool, ok bool) {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	v, pre, _ := strings.Cut(v, "-")
+	parts := strings.Split(v, ".")
+	if len(parts) != 3 {
+		return numbers, false, false
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return numbers, false, false
+		}
+		numbers[i] = n
+	}
+	return numbers, pre == "", true
+}
+
+// ArchiveName is the name of the release archive for an operating system
+// and architecture, as named by the release build.
+func ArchiveName(goos, goarch string) string {
+	arch := goarch
+	switch goarch {
+	case "amd64":
+		arch = "x86_64"
+	case "386":
+		arch = "i386"
+	}
+	ext := ".tar.gz"
+	if goos == "windows" {
+		ext = ".zip"
+	}
+	return fmt.Sprintf("chronos_%s_%s%s", strings.ToUpper(goos[:1])+goos[1:], arch, ext)
+}
+
+// binaryName is the name of the chronos binary in an archive.
+func binaryName(goos string) string {
+	if goos == "windows" {
+		return "chronos.exe"
+	}
+	return "chronos"
+}
+
+// asset returns the release's file of this name.
+func (r Release) asset(name string) (Asset, error) {
+	for _, a := range r.Assets {
+		if a.Name == name {
+			return a, nil
+		}
+	}
+	return Asset{}, fmt.Errorf("release %s has no %s", r.Tag, name)
+}
+
+// Download fetches the release's archive for an operating system and
+// architecture, checks it against the release's checksums and returns
+// the binary inside.
+func Download(client *http.Client, r *Release, goos, goarch string) ([]byte, error) {
+	name := ArchiveName(goos, goarch)
+	archive, err := r.asset(name)
+	if err != nil {
+		return nil, err
+	}
+	sums, err := r.asset(ChecksumsFile)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := get(client, sums.URL)
+	if err != nil {
+		return nil, err
+	}
+	want, err := checksum(list, name)
+	if err != nil {
+		return nil, err
+	}
+	data, err := get(client, archive.URL)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+	if got := hex.EncodeToString(sum[:]); got != want {
+		return nil, fmt.Errorf("%s doesn't match its checksum: got %s, expected %s", name, got, want)
+	}
+
+	if goos == "windows" {
+		return fromZip(data, binaryName(goos))
+	}
+	return fromTarGz(data, binaryName(goos))
+}
+
+// checksum finds a file's checksum in a checksums.txt, which lists one
+// "<sha256>  <name>" a line.
+func checksum(list []byte, name string) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(list))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[1] == name {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	return "", fmt.Errorf("%s has no checksum for %s", ChecksumsFile, name)
+}
+
+// fromTarGz returns the contents of the file of this name in a .tar.gz.
+func fromTarGz(data []byte, name string) ([]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	tr := tar.NewReader(gz)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("the archive has no %s", name)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if h.Typeflag == tar.TypeReg && path.Base(h.Name) == name {
+			return io.ReadAll(io.LimitReader(tr, maxDownloadSize))
+		}
+	}
+}
+
+// fromZip returns the contents of the file of this name in a .zip.
+func fromZip(data []byte, name string) ([]byte, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range zr.File {
+		if path.Base(f.Name) != name || f.FileInfo().IsDir() {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		return io.ReadAll(io.LimitReader(rc, maxDownloadSize))
+	}
+	return nil, fmt.Errorf("the archive has no %s", name)
+}
+
+// Replace swaps the binary at exe for a new one. The old binary is moved
+// aside first, as Windows can rename a running program but not overwrite
+// it, and removed where that's allowed.
+func Replace(exe string, binary []byte) error {
+	info, err := os.Stat(exe)
+	if err != nil {
+		return err
+	}
+	old := exe + ".old"
+	// A Windows update leaves the binary it replaced until the next one
+	os.Remove(old)
+
+	tmp, err := os.CreateTemp(filepath.Dir(exe), filepath.Base(exe)+".new-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(binary); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), info.Mode().Perm()); err != nil {
+		return err
+	}
+
+	if err := os.Rename(exe, old); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), exe); err != nil {
+		// Put the old binary back rather than leave none
+		os.Rename(old, exe)
+		return err
+	}
+	os.Remove(old)
+	return nil
+}
+
+// lastCheck is the startup check remembered in CheckFile.
+type lastCheck struct {
+	Checked time.Time `json:"checked"`
+	Tag     string    `json:"tag"`
+}
+
+// Available returns the latest release's version when it's newer than
+// current, or "" when it isn't. GitHub is asked at most once every
+// CheckInterval, and the version found is remembered in between.
+func Available(client *http.Client, url, current string) (string, error) {
+	file, err := config.Path(CheckFile)
+	if err != nil {
+		return "", err
+	}
+
+	var last lastCheck
+	if data, err := os.ReadFile(file); err == nil {
+		json.Unmarshal(data, &last)
+	}
+	if time.Since(last.Checked) > CheckInterval || last.Tag == "" {
+		r, err := Latest(client, url)
+		if err != nil {
+			return "", err
+		}
+		last = lastCheck{Checked: time.Now(), Tag: r.Tag}
+		if dir, err := config.EnsureDir(); err == nil {
+			data, _ := json.Marshal(last)
+			os.WriteFile(filepath.Join(dir, CheckFile), data, 0o644)
+		}
+	}
+
+	if Newer(current, last.Tag) {
+		return last.Tag, nil
+	}
+	return "", nil
+}
+
+// get downloads url, with a client that gives up after requestTimeout
+// when client is nil.
+func get(client *http.Client, url string) ([]byte, error) {
+	if client == nil {
+		client = &http.Client{Timeout: requestTimeout}
+	}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("download failed: %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxDownloadSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxDownloadSize {
+		return nil, fmt.Errorf("download failed: over %d bytes", maxDownloadSize)
+	}
+	return data, nil
+}
@@ -0,0 +1,173 @@
+package update
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewer(t *testing.T) {
+	tests := []struct {
+		current, tag string
+		expected     bool
+	}{
+		{"1.2.3", "v1.3.0", true},
+		{"v1.2.3", "v1.2.4", true},
+		{"1.2.3", "v2.0.0", true},
+		{"1.2.3", "v1.2.3", false},
+		{"1.10.0", "v1.9.9", false},
+		{"1.3.0-rc1", "v1.3.0", true},
+		{"1.3.0", "v1.3.1-rc1", true},
+		{"1.3.0", "v1.3.0-rc1", false},
+		{"dev", "v1.3.0", false},
+		{"1.2.3", "nightly", false},
+	}
+	for _, tt := range tests {
+		if got := Newer(tt.current, tt.tag); got != tt.expected {
+			t.Errorf("Newer(%q, %q) = %v; want %v", tt.current, tt.tag, got, tt.expected)
+		}
+	}
+}
+
+func TestArchiveName(t *testing.T) {
+	tests := []struct {
+		goos, goarch, expected string
+	}{
+		{"linux", "amd64", "chronos_Linux_x86_64.tar.gz"},
+		{"darwin", "arm64", "chronos_Darwin_arm64.tar.gz"},
+		{"windows", "amd64", "chronos_Windows_x86_64.zip"},
+	}
+	for _, tt := range tests {
+		if got := ArchiveName(tt.goos, tt.goarch); got != tt.expected {
+			t.Errorf("ArchiveName(%q, %q) = %q; want %q", tt.goos, tt.goarch, got, tt.expected)
+		}
+	}
+}
+
+// tarGz builds a release archive holding one file.
+func tarGz(t *testing.T, name string, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o755, Size: int64(len(data)), Typeflag: tar.TypeReg}); err != nil {
+		t.Fatal(err)
+	}
+	tw.Write(data)
+	tw.Close()
+	gz.Close()
+	return buf.Bytes()
+}
+
+// releaseServer serves a release of chronos v9.9.9 for Linux, with the
+// checksum listed for its archive.
+func releaseServer(t *testing.T, sum string, archive []byte) *httptest.Server {
+	t.Helper()
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/latest":
+			json.NewEncoder(w).Encode(Release{Tag: "v9.9.9", Assets: []Asset{
+				{Name: "chronos_Linux_x86_64.tar.gz", URL: server.URL + "/archive"},
+				{Name: ChecksumsFile, URL: server.URL + "/checksums"},
+			}})
+		case "/archive":
+			w.Write(archive)
+		case "/checksums":
+			fmt.Fprintf(w, "%s  chronos_Linux_x86_64.tar.gz\n%s  chronos_Windows_x86_64.zip\n", sum, sum)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestDownload(t *testing.T) {
+	archive := tarGz(t, "chronos", []byte("new binary"))
+	sum := sha256.Sum256(archive)
+	server := releaseServer(t, hex.EncodeToString(sum[:]), archive)
+
+	r, err := Latest(server.Client(), server.URL+"/latest")
+	if err != nil {
+		t.Fatalf("Latest failed: %v", err)
+	}
+	if r.Tag != "v9.9.9" {
+		t.Errorf("Expected v9.9.9, got %s", r.Tag)
+	}
+
+	binary, err := Download(server.Client(), r, "linux", "amd64")
+	if err != nil {
+		t.Fatalf("Download failed: %v", err)
+	}
+	if string(binary) != "new binary" {
+		t.Errorf("Unexpected binary: %q", binary)
+	}
+
+	if _, err := Download(server.Client(), r, "darwin", "amd64"); err == nil {
+		t.Error("Expected an error for a missing archive")
+	}
+}
+
+func TestDownload_BadChecksum(t *testing.T) {
+	archive := tarGz(t, "chronos", []byte("tampered binary"))
+	server := releaseServer(t, hex.EncodeToString(make([]byte, sha256.Size)), archive)
+
+	r, err := Latest(server.Client(), server.URL+"/latest")
+	if err != nil {
+		t.Fatalf("Latest failed: %v", err)
+	}
+	if _, err := Download(server.Client(), r, "linux", "amd64"); err == nil {
+		t.Error("Expected a checksum mismatch to be an error")
+	}
+}
+
+func TestReplace(t *testing.T) {
+	exe := filepath.Join(t.TempDir(), "chronos")
+	if err := os.WriteFile(exe, []byte("old binary"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Replace(exe, []byte("new binary")); err != nil {
+		t.Fatalf("Replace failed: %v", err)
+	}
+	got, err := os.ReadFile(exe)
+	if err != nil || string(got) != "new binary" {
+		t.Errorf("Expected the new binary, got %q, %v", got, err)
+	}
+	if info, err := os.Stat(exe); err != nil || info.Mode().Perm() != 0o755 {
+		t.Errorf("Expected the old binary's mode, got %v, %v", info.Mode(), err)
+	}
+	entries, _ := os.ReadDir(filepath.Dir(exe))
+	if len(entries) != 1 {
+		t.Errorf("Expected only the binary left, got %d files", len(entries))
+	}
+}
+
+func TestAvailable(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	server := releaseServer(t, "", nil)
+
+	tag, err := Available(server.Client(), server.URL+"/latest", "1.0.0")
+	if err != nil || tag != "v9.9.9" {
+		t.Fatalf("Expected v9.9.9 to be available, got %q, %v", tag, err)
+	}
+
+	// The release found is remembered rather than asked for again
+	server.Close()
+	if tag, err := Available(nil, server.URL+"/latest", "1.0.0"); err != nil || tag != "v9.9.9" {
+		t.Errorf("Expected the remembered v9.9.9, got %q, %v", tag, err)
+	}
+	if tag, err := Available(nil, server.URL+"/latest", "9.9.9"); err != nil || tag != "" {
+		t.Errorf("Expected no update for the latest version, got %q, %v", tag, err)
+	}
+}
@@ -1,12 +1,11 @@
 package main
 
 import (
-	"fmt"
 	"os"
 
-	"github.com/nconklindev/chronos/internal/ui"
-
-	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nconklindev/chronos/internal/cli"
+	"github.com/nconklindev/chronos/internal/crash"
+	"github.com/nconklindev/chronos/internal/debuglog"
 )
 
 var (
@@ -16,15 +15,11 @@ var (
 )
 
 func main() {
-	// Handle --version flag
-	if len(os.Args) > 1 && (os.Args[1] == "--version" || os.Args[1] == "-v") {
-		fmt.Printf("chronos %s\ncommit: %s\nbuilt: %s\n", version, commit, date)
-		os.Exit(0)
-	}
+	// Restore the terminal and save a crash log for panics the interface doesn't catch
+	defer crash.Handle(crash.Info{Version: version, Commit: commit, Date: date}, cli.ExitFailure)
 
-	p := tea.NewProgram(ui.InitialModel(), tea.WithAltScreen(), tea.WithMouseCellMotion())
-	if _, err := p.Run(); err != nil {
-		fmt.Printf("Error: %v\n", err)
-		os.Exit(1)
-	}
+	root := cli.NewRootCommand(cli.BuildInfo{Version: version, Commit: commit, Date: date})
+	code := cli.ExitCode(root.Execute())
+	debuglog.Stop()
+	os.Exit(code)
 }